@@ -0,0 +1,368 @@
+// Package banner provides a full-width alert banner for Bubble Tea
+// applications. Banners show a single line message with a severity level,
+// optional action hint and can dismiss themselves after a timeout.
+package banner
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
+	"github.com/mikeflynn/bubbles/textutil"
+)
+
+// Internal ID management. Used to ensure that dismiss messages are received
+// only by the banner that scheduled them.
+var lastID int64
+
+func nextID() int {
+	return int(atomic.AddInt64(&lastID, 1))
+}
+
+// Severity is the level of a banner message.
+type Severity int
+
+// Available severities.
+const (
+	Info Severity = iota
+	Success
+	Warning
+	Error
+)
+
+// String returns a human-readable name for the severity.
+func (s Severity) String() string {
+	return [...]string{
+		"info",
+		"success",
+		"warning",
+		"error",
+	}[s]
+}
+
+// DismissedMsg is sent when the banner is dismissed, either by the user or
+// because its timeout elapsed. Layouts that make room for the banner can use
+// it to reclaim the line.
+type DismissedMsg struct {
+	ID int
+}
+
+// timeoutMsg is sent when a banner's auto-dismiss timeout elapses.
+type timeoutMsg struct {
+	id  int
+	tag int
+}
+
+// KeyMap is the key bindings of a banner, which can only be dismissed. It
+// satisfies the help.KeyMap interface.
+type KeyMap struct {
+	Dismiss key.Binding
+}
+
+// ShortHelp implements the KeyMap interface.
+func (km KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{km.Dismiss}
+}
+
+// FullHelp implements the KeyMap interface.
+func (km KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{km.Dismiss}}
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Dismiss: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "dismiss"),
+		),
+	}
+}
+
+// Styles are the styles of a banner for each severity and of its hints.
+// DefaultStyles returns the defaults.
+type Styles struct {
+	Info    lipgloss.Style
+	Success lipgloss.Style
+	Warning lipgloss.Style
+	Error   lipgloss.Style
+
+	// Hint is applied to the action and dismiss hints on the right side of
+	// the banner, on top of the severity style.
+	Hint lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this
+// component.
+func DefaultStyles() Styles {
+	base := lipgloss.NewStyle().Padding(0, 1).Foreground(lipgloss.Color("#FFFDF5"))
+
+	return Styles{
+		Info:    base.Background(lipgloss.AdaptiveColor{Light: "#3C7DD9", Dark: "#2F5FA8"}),
+		Success: base.Background(lipgloss.AdaptiveColor{Light: "#00A66D", Dark: "#04875A"}),
+		Warning: base.Background(lipgloss.AdaptiveColor{Light: "#E8A317", Dark: "#B37A00"}),
+		Error:   base.Background(lipgloss.AdaptiveColor{Light: "#E0455B", Dark: "#B3263C"}),
+		Hint:    lipgloss.NewStyle().Faint(true),
+	}
+}
+
+// Icons holds the icon displayed before the message for each severity.
+type Icons struct {
+	Info    string
+	Success string
+	Warning string
+	Error   string
+}
+
+// DefaultIcons returns the default set of icons.
+func DefaultIcons() Icons {
+	return Icons{
+		Info:    "ℹ",
+		Success: "✓",
+		Warning: "⚠",
+		Error:   "✗",
+	}
+}
+
+// Model is the state of an alert banner.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+	Icons  Icons
+
+	// Width is the width of the banner. The message is truncated to fit.
+	Width int
+
+	// Timeout is how long a banner stays visible before dismissing itself.
+	// If 0 or less the banner stays until it's dismissed.
+	Timeout time.Duration
+
+	// ShowDismissHint determines whether the dismiss keybinding is shown on
+	// the right side of the banner.
+	ShowDismissHint bool
+
+	// Translator, if set, localizes the dismiss hint. A Dismiss binding with
+	// a translator of its own uses that instead. See
+	// key.Binding.SetTranslator.
+	Translator key.Translator
+
+	severity Severity
+	message  string
+	action   string
+	visible  bool
+
+	id  int
+	tag int
+}
+
+// New returns a model with default settings.
+func New() Model {
+	return Model{
+		KeyMap:          DefaultKeyMap(),
+		Styles:          DefaultStyles(),
+		Icons:           DefaultIcons(),
+		ShowDismissHint: true,
+		id:              nextID(),
+	}
+}
+
+// ID returns the model's identifier.
+func (m Model) ID() int {
+	return m.id
+}
+
+// Show displays a message with the given severity, replacing any current
+// message. The returned command dismisses the banner once Timeout elapses.
+func (m *Model) Show(severity Severity, message string) tea.Cmd {
+	m.severity = severity
+	m.message = message
+	m.action = ""
+	m.visible = true
+	m.tag++
+
+	if m.Timeout <= 0 {
+		return nil
+	}
+	id, tag := m.id, m.tag
+	return tea.Tick(m.Timeout, func(time.Time) tea.Msg {
+		return timeoutMsg{id: id, tag: tag}
+	})
+}
+
+// Info displays an informational message.
+func (m *Model) Info(message string) tea.Cmd {
+	return m.Show(Info, message)
+}
+
+// Success displays a success message.
+func (m *Model) Success(message string) tea.Cmd {
+	return m.Show(Success, message)
+}
+
+// Warning displays a warning message.
+func (m *Model) Warning(message string) tea.Cmd {
+	return m.Show(Warning, message)
+}
+
+// Error displays an error message.
+func (m *Model) Error(message string) tea.Cmd {
+	return m.Show(Error, message)
+}
+
+// SetAction sets a hint describing an action the user can take, such as
+// "r to retry". It's shown on the right side of the banner and cleared when a
+// new message is shown.
+func (m *Model) SetAction(hint string) {
+	m.action = hint
+}
+
+// Dismiss hides the banner.
+func (m *Model) Dismiss() {
+	m.visible = false
+	m.tag++
+}
+
+// Visible returns whether the banner is showing a message.
+func (m Model) Visible() bool {
+	return m.visible
+}
+
+// Severity returns the severity of the current message.
+func (m Model) Severity() Severity {
+	return m.severity
+}
+
+// Message returns the current message.
+func (m Model) Message() string {
+	return m.message
+}
+
+// Height returns the number of lines the banner occupies, which is one while
+// it's visible and zero otherwise.
+func (m Model) Height() int {
+	if !m.visible {
+		return 0
+	}
+	return 1
+}
+
+// Init exists to satisfy the tea.Model interface.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case timeoutMsg:
+		if msg.id != m.id || msg.tag != m.tag {
+			return m, nil
+		}
+		return m, m.dismiss()
+	case tea.KeyMsg:
+		if key.Matches(msg, m.KeyMap.Dismiss) {
+			return m, m.dismiss()
+		}
+	}
+
+	return m, nil
+}
+
+func (m *Model) dismiss() tea.Cmd {
+	m.Dismiss()
+	id := m.id
+	return func() tea.Msg {
+		return DismissedMsg{ID: id}
+	}
+}
+
+// View renders the banner. It renders an empty string while the banner is
+// hidden.
+func (m Model) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	style := m.style()
+	width := max(0, m.Width-style.GetHorizontalFrameSize())
+
+	left := m.message
+	if icon := m.icon(); icon != "" {
+		left = icon + " " + left
+	}
+
+	var hints []string
+	if m.action != "" {
+		hints = append(hints, m.action)
+	}
+	if m.ShowDismissHint && m.KeyMap.Dismiss.Enabled() {
+		h := m.KeyMap.Dismiss.TranslatedHelp(m.Translator)
+		hints = append(hints, h.Key+" "+h.Desc)
+	}
+	right := strings.Join(hints, " • ")
+
+	hint := m.Styles.Hint.Inherit(style).Render
+
+	// Without a width, the hints simply follow the message. Otherwise they're
+	// right-aligned, and dropped if there's no room for them.
+	var content string
+	gap := width - ansi.StringWidth(left) - ansi.StringWidth(right)
+	switch {
+	case m.Width <= 0:
+		content = left
+		if right != "" {
+			content += "  " + hint(right)
+		}
+	case right != "" && gap >= 1:
+		content = left + strings.Repeat(" ", gap) + hint(right)
+	default:
+		content = textutil.Truncate(left, width, textutil.Ellipsis)
+	}
+
+	if m.Width > 0 {
+		style = style.Width(m.Width)
+	}
+	return style.MaxHeight(1).Render(content)
+}
+
+// RenderString renders the banner deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	m.Width = width
+	return render.View(m.View, width, height)
+}
+
+func (m Model) style() lipgloss.Style {
+	switch m.severity {
+	case Success:
+		return m.Styles.Success
+	case Warning:
+		return m.Styles.Warning
+	case Error:
+		return m.Styles.Error
+	default:
+		return m.Styles.Info
+	}
+}
+
+func (m Model) icon() string {
+	switch m.severity {
+	case Success:
+		return m.Icons.Success
+	case Warning:
+		return m.Icons.Warning
+	case Error:
+		return m.Icons.Error
+	default:
+		return m.Icons.Info
+	}
+}
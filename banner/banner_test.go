@@ -0,0 +1,96 @@
+package banner
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/mikeflynn/bubbles/key"
+)
+
+func TestView(t *testing.T) {
+	m := New()
+	m.Width = 60
+
+	if m.View() != "" || m.Height() != 0 {
+		t.Fatal("expected hidden banner to render nothing")
+	}
+
+	m.Warning("Disk almost full")
+	m.SetAction("c to clean up")
+
+	v := ansi.Strip(m.View())
+	if m.Height() != 1 || strings.Contains(v, "\n") {
+		t.Fatalf("expected a single line, got %q", v)
+	}
+	if ansi.StringWidth(v) != 60 {
+		t.Errorf("expected banner to fill the width, got %d", ansi.StringWidth(v))
+	}
+	if !strings.HasPrefix(v, " ⚠ Disk almost full") {
+		t.Errorf("expected icon and message, got %q", v)
+	}
+	if !strings.HasSuffix(v, "c to clean up • esc dismiss ") {
+		t.Errorf("expected hints on the right, got %q", v)
+	}
+
+	m.Translator = key.Translations{"dismiss": "schließen"}
+	if v := ansi.Strip(m.View()); !strings.HasSuffix(v, "esc schließen ") {
+		t.Errorf("expected the dismiss hint to be translated, got %q", v)
+	}
+
+	// Hints are dropped when there's no room for them.
+	m.Width = 20
+	v = ansi.Strip(m.View())
+	if strings.Contains(v, "dismiss") || ansi.StringWidth(v) != 20 {
+		t.Errorf("expected truncated message without hints, got %q", v)
+	}
+}
+
+func TestDismiss(t *testing.T) {
+	m := New()
+	m.Error("Something broke")
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if m.Visible() {
+		t.Fatal("expected banner to be dismissed")
+	}
+	if msg, ok := cmd().(DismissedMsg); !ok || msg.ID != m.ID() {
+		t.Fatalf("expected DismissedMsg, got %#v", cmd())
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	m := New()
+	m.Timeout = time.Minute
+	if cmd := m.Info("first"); cmd == nil {
+		t.Fatal("expected an auto-dismiss command")
+	}
+	stale := timeoutMsg{id: m.id, tag: m.tag}
+
+	// Showing a new message invalidates the previous timeout.
+	m.Success("second")
+	m, _ = m.Update(stale)
+	if !m.Visible() {
+		t.Fatal("expected stale timeout to be ignored")
+	}
+
+	m, cmd := m.Update(timeoutMsg{id: m.id, tag: m.tag})
+	if m.Visible() || cmd == nil {
+		t.Fatal("expected banner to dismiss itself")
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	t.Run("hidden", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(New(), 40, 1)))
+	})
+	t.Run("warning", func(t *testing.T) {
+		m := New()
+		m.Warning("Disk almost full")
+		m.SetAction("c to clean up")
+		golden.RequireEqual(t, []byte(RenderString(m, 60, 1)))
+	})
+}
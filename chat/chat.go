@@ -0,0 +1,496 @@
+// Package chat provides a scrollback of chat messages for Bubble Tea
+// applications. Messages are laid out with their sender and time, word
+// wrapped to the width of the component and can be streamed in piece by
+// piece, such as the output of a language model. The log sticks to the bottom
+// as messages arrive unless the user has scrolled up, and a message can be
+// selected and copied.
+package chat
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mikeflynn/bubbles/clipboard"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
+	"github.com/mikeflynn/bubbles/textutil"
+	"github.com/mikeflynn/bubbles/viewport"
+)
+
+var lastID int64
+
+func nextID() int {
+	return int(atomic.AddInt64(&lastID, 1))
+}
+
+// Message is a single message in the log.
+type Message struct {
+	Sender  string
+	Time    time.Time
+	Content string
+
+	// Streaming is true while content is still being appended to the
+	// message. A cursor is drawn after its content until it's done.
+	Streaming bool
+}
+
+// ChunkMsg appends a chunk of content to a streaming message. Use
+// Model.Chunk to create one for a given message.
+type ChunkMsg struct {
+	ID    int
+	Index int
+	Chunk string
+
+	// Done marks the end of the message.
+	Done bool
+}
+
+// CopiedMsg is sent after a message has been copied to the clipboard.
+type CopiedMsg struct {
+	Message Message
+	Err     error
+}
+
+// KeyMap is the key bindings for scrolling the chat log and selecting
+// messages. It satisfies the help.KeyMap interface.
+type KeyMap struct {
+	LineUp     key.Binding
+	LineDown   key.Binding
+	PageUp     key.Binding
+	PageDown   key.Binding
+	GotoTop    key.Binding
+	GotoBottom key.Binding
+
+	SelectPrevious key.Binding
+	SelectNext     key.Binding
+	ClearSelection key.Binding
+	Copy           key.Binding
+}
+
+// ShortHelp implements the KeyMap interface.
+func (km KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{km.LineUp, km.LineDown, km.SelectPrevious, km.SelectNext, km.Copy}
+}
+
+// FullHelp implements the KeyMap interface.
+func (km KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{km.LineUp, km.LineDown, km.PageUp, km.PageDown, km.GotoTop, km.GotoBottom},
+		{km.SelectPrevious, km.SelectNext, km.ClearSelection, km.Copy},
+	}
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		LineUp: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		LineDown: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup", "b"),
+			key.WithHelp("b/pgup", "page up"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown", "f"),
+			key.WithHelp("f/pgdn", "page down"),
+		),
+		GotoTop: key.NewBinding(
+			key.WithKeys("home", "g"),
+			key.WithHelp("g/home", "go to start"),
+		),
+		GotoBottom: key.NewBinding(
+			key.WithKeys("end", "G"),
+			key.WithHelp("G/end", "go to end"),
+		),
+		SelectPrevious: key.NewBinding(
+			key.WithKeys("shift+up", "K"),
+			key.WithHelp("K", "previous message"),
+		),
+		SelectNext: key.NewBinding(
+			key.WithKeys("shift+down", "J"),
+			key.WithHelp("J", "next message"),
+		),
+		ClearSelection: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "clear selection"),
+			key.WithDisabled(),
+		),
+		Copy: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "copy message"),
+			key.WithDisabled(),
+		),
+	}
+}
+
+// Styles are the styles of messages, their senders and times, and of the
+// selected message. DefaultStyles returns the defaults.
+type Styles struct {
+	Sender    lipgloss.Style
+	OwnSender lipgloss.Style
+	Timestamp lipgloss.Style
+	Content   lipgloss.Style
+	Cursor    lipgloss.Style
+	Message   lipgloss.Style
+	Selected  lipgloss.Style
+	Empty     lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this
+// component.
+func DefaultStyles() Styles {
+	return Styles{
+		Sender:    lipgloss.NewStyle().Bold(true),
+		OwnSender: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.AdaptiveColor{Light: "#AD58B4", Dark: "#F793FF"}),
+		Timestamp: lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}),
+		Content:   lipgloss.NewStyle(),
+		Cursor:    lipgloss.NewStyle().Foreground(lipgloss.Color("212")),
+		Message:   lipgloss.NewStyle().PaddingLeft(2).MarginBottom(1), //nolint:mnd
+		Selected: lipgloss.NewStyle().
+			Border(lipgloss.ThickBorder(), false, false, false, true).
+			BorderForeground(lipgloss.Color("212")).
+			PaddingLeft(1).
+			MarginBottom(1),
+		Empty: lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#909090", Dark: "#626262"}),
+	}
+}
+
+// Model is the state of a chat log.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+
+	// Self is the sender name of the local user. Their messages are styled
+	// with OwnSender.
+	Self string
+
+	// TimeFormat is the layout used for message times. If empty, times are
+	// not shown.
+	TimeFormat string
+
+	// StreamingCursor is drawn after the content of streaming messages.
+	StreamingCursor string
+
+	// EmptyText is shown when there are no messages.
+	EmptyText string
+
+	// Clipboard is where messages are copied to. If nil, the system
+	// clipboard is used. See clipboard.NewOSC52 for copying over SSH.
+	Clipboard clipboard.Clipboard
+
+	id       int
+	messages []Message
+	blocks   []string // rendered messages, empty when stale
+	selected int      // -1 if no message is selected
+	sticky   bool
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+// New returns a model with default settings.
+func New() Model {
+	vp := viewport.New(0, 0)
+	vp.KeyMap = viewport.KeyMap{}
+
+	m := Model{
+		KeyMap:          DefaultKeyMap(),
+		Styles:          DefaultStyles(),
+		TimeFormat:      "15:04",
+		StreamingCursor: "▍",
+		EmptyText:       "No messages yet.",
+		id:              nextID(),
+		selected:        -1,
+		sticky:          true,
+		viewport:        vp,
+	}
+	m.updateKeybindings()
+	return m
+}
+
+// ID returns the component's unique ID, which is carried by ChunkMsg.
+func (m Model) ID() int {
+	return m.id
+}
+
+// SetSize sets the dimensions of the component.
+func (m *Model) SetSize(width, height int) {
+	if width != m.width {
+		m.blocks = make([]string, len(m.messages))
+	}
+	m.width = width
+	m.height = height
+	m.viewport.Width = width
+	m.viewport.Height = height
+	m.refresh()
+}
+
+// Width returns the width of the component.
+func (m Model) Width() int {
+	return m.width
+}
+
+// Height returns the height of the component.
+func (m Model) Height() int {
+	return m.height
+}
+
+// Messages returns the messages in the log, oldest first.
+func (m Model) Messages() []Message {
+	return append([]Message(nil), m.messages...)
+}
+
+// SetMessages replaces the messages in the log and clears the selection.
+func (m *Model) SetMessages(msgs []Message) {
+	m.messages = append([]Message(nil), msgs...)
+	m.blocks = make([]string, len(m.messages))
+	m.selected = -1
+	m.refresh()
+	m.updateKeybindings()
+}
+
+// Append adds a message to the end of the log and returns its index. To
+// stream a message, append it with Streaming set and add to its content with
+// AppendChunk or ChunkMsg.
+func (m *Model) Append(msg Message) int {
+	m.messages = append(m.messages, msg)
+	m.blocks = append(m.blocks, "")
+	m.refresh()
+	return len(m.messages) - 1
+}
+
+// AppendChunk adds content to the message at the given index.
+func (m *Model) AppendChunk(index int, chunk string) {
+	if index < 0 || index >= len(m.messages) {
+		return
+	}
+	m.messages[index].Content += chunk
+	m.blocks[index] = ""
+	m.refresh()
+}
+
+// SetStreaming sets whether the message at the given index is still being
+// streamed.
+func (m *Model) SetStreaming(index int, v bool) {
+	if index < 0 || index >= len(m.messages) {
+		return
+	}
+	m.messages[index].Streaming = v
+	m.blocks[index] = ""
+	m.refresh()
+}
+
+// Chunk returns a command which appends a chunk of content to the message at
+// the given index, for use from code that streams content, such as a
+// goroutine reading a response.
+func (m Model) Chunk(index int, chunk string, done bool) tea.Cmd {
+	id := m.id
+	return func() tea.Msg {
+		return ChunkMsg{ID: id, Index: index, Chunk: chunk, Done: done}
+	}
+}
+
+// Selected returns the index of the selected message, or -1 if no message is
+// selected.
+func (m Model) Selected() int {
+	return m.selected
+}
+
+// Select selects the message at the given index and scrolls it into view.
+// Pass -1 to clear the selection.
+func (m *Model) Select(index int) {
+	if index < -1 || index >= len(m.messages) {
+		return
+	}
+	if m.selected >= 0 {
+		m.blocks[m.selected] = ""
+	}
+	m.selected = index
+	if index >= 0 {
+		m.blocks[index] = ""
+	}
+	m.refresh()
+	m.scrollToSelected()
+	m.updateKeybindings()
+}
+
+// CopySelected returns a command which copies the content of the selected
+// message to the Clipboard.
+func (m Model) CopySelected() tea.Cmd {
+	if m.selected < 0 {
+		return nil
+	}
+	msg := m.messages[m.selected]
+	cb := clipboard.Or(m.Clipboard)
+	return func() tea.Msg {
+		return CopiedMsg{Message: msg, Err: cb.WriteAll(msg.Content)}
+	}
+}
+
+// AtBottom returns whether the log is scrolled to the bottom. While it is,
+// new content keeps it there.
+func (m Model) AtBottom() bool {
+	return m.viewport.AtBottom()
+}
+
+// GotoBottom scrolls to the bottom of the log, so that it follows new
+// content again.
+func (m *Model) GotoBottom() {
+	m.viewport.GotoBottom()
+	m.sticky = true
+}
+
+// Init exists to satisfy the tea.Model interface.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case ChunkMsg:
+		if msg.ID != m.id {
+			break
+		}
+		m.AppendChunk(msg.Index, msg.Chunk)
+		if msg.Done {
+			m.SetStreaming(msg.Index, false)
+		}
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.LineUp):
+			m.viewport.ScrollUp(1)
+		case key.Matches(msg, m.KeyMap.LineDown):
+			m.viewport.ScrollDown(1)
+		case key.Matches(msg, m.KeyMap.PageUp):
+			m.viewport.PageUp()
+		case key.Matches(msg, m.KeyMap.PageDown):
+			m.viewport.PageDown()
+		case key.Matches(msg, m.KeyMap.GotoTop):
+			m.viewport.GotoTop()
+		case key.Matches(msg, m.KeyMap.GotoBottom):
+			m.viewport.GotoBottom()
+		case key.Matches(msg, m.KeyMap.SelectPrevious):
+			if m.selected < 0 {
+				m.Select(len(m.messages) - 1)
+			} else {
+				m.Select(max(0, m.selected-1))
+			}
+		case key.Matches(msg, m.KeyMap.SelectNext):
+			if m.selected >= 0 {
+				m.Select(min(len(m.messages)-1, m.selected+1))
+			}
+		case key.Matches(msg, m.KeyMap.ClearSelection):
+			m.Select(-1)
+		case key.Matches(msg, m.KeyMap.Copy):
+			cmd = m.CopySelected()
+		}
+		m.sticky = m.viewport.AtBottom()
+	case tea.MouseMsg:
+		m.viewport, cmd = m.viewport.Update(msg)
+		m.sticky = m.viewport.AtBottom()
+	}
+
+	return m, cmd
+}
+
+// View renders the component.
+func (m Model) View() string {
+	if len(m.messages) == 0 {
+		return lipgloss.NewStyle().
+			Width(m.width).MaxWidth(m.width).
+			Height(m.height).MaxHeight(m.height).
+			Render(m.Styles.Empty.Render(m.EmptyText))
+	}
+	return m.viewport.View()
+}
+
+// RenderString renders the log deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	m.SetSize(width, height)
+	return render.View(m.View, width, height)
+}
+
+// refresh renders stale messages and updates the content of the viewport,
+// following the bottom of the log if it's sticky.
+func (m *Model) refresh() {
+	for i := range m.messages {
+		if m.blocks[i] == "" {
+			m.blocks[i] = m.renderMessage(i)
+		}
+	}
+	m.viewport.SetContent(strings.TrimSuffix(strings.Join(m.blocks, ""), "\n"))
+	if m.sticky {
+		m.viewport.GotoBottom()
+	}
+}
+
+// renderMessage renders the message at the given index: a header with the
+// sender and time, followed by the word-wrapped content. Each rendered
+// message ends with a newline.
+func (m Model) renderMessage(i int) string {
+	msg := m.messages[i]
+	style := m.Styles.Message
+	if i == m.selected {
+		style = m.Styles.Selected
+	}
+	width := max(1, m.width-style.GetHorizontalFrameSize())
+
+	sender := m.Styles.Sender
+	if m.Self != "" && msg.Sender == m.Self {
+		sender = m.Styles.OwnSender
+	}
+	var stamp string
+	if m.TimeFormat != "" && !msg.Time.IsZero() {
+		stamp = msg.Time.Format(m.TimeFormat)
+	}
+	name := textutil.Truncate(msg.Sender, max(0, width-textutil.Width(stamp)-1), textutil.Ellipsis)
+	gap := max(1, width-textutil.Width(name)-textutil.Width(stamp))
+	header := sender.Render(name) + strings.Repeat(" ", gap) + m.Styles.Timestamp.Render(stamp)
+
+	content := msg.Content
+	if msg.Streaming {
+		content += m.Styles.Cursor.Render(m.StreamingCursor)
+	}
+	if content != "" {
+		header += "\n" + m.Styles.Content.Render(ansi.Wrap(content, width, ""))
+	}
+
+	return style.Render(header) + "\n"
+}
+
+// scrollToSelected scrolls the selected message into view.
+func (m *Model) scrollToSelected() {
+	if m.selected < 0 {
+		return
+	}
+	var top int
+	for _, b := range m.blocks[:m.selected] {
+		top += strings.Count(b, "\n")
+	}
+	bottom := top + strings.Count(m.blocks[m.selected], "\n")
+	switch {
+	case top < m.viewport.YOffset:
+		m.viewport.SetYOffset(top)
+	case bottom > m.viewport.YOffset+m.viewport.Height:
+		m.viewport.SetYOffset(max(top, bottom-m.viewport.Height))
+	}
+	m.sticky = m.viewport.AtBottom()
+}
+
+func (m *Model) updateKeybindings() {
+	m.KeyMap.ClearSelection.SetEnabled(m.selected >= 0)
+	m.KeyMap.Copy.SetEnabled(m.selected >= 0)
+}
@@ -0,0 +1,105 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/mikeflynn/bubbles/clipboard"
+)
+
+var start = time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+
+func newChat() Model {
+	m := New()
+	m.Self = "me"
+	m.SetMessages([]Message{
+		{Sender: "me", Time: start, Content: "Can you summarize the release notes?"},
+		{Sender: "assistant", Time: start.Add(time.Minute), Content: "Sure. The release adds a chat view and fixes scrolling in the viewport."},
+	})
+	return m
+}
+
+func TestRenderString(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(newChat(), 30, 10)))
+	})
+	t.Run("selected", func(t *testing.T) {
+		m := newChat()
+		m.Select(0)
+		golden.RequireEqual(t, []byte(RenderString(m, 30, 10)))
+	})
+	t.Run("empty", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(New(), 30, 3)))
+	})
+}
+
+func TestStreaming(t *testing.T) {
+	m := newChat()
+	m.SetSize(30, 4)
+	if !m.AtBottom() {
+		t.Fatal("expected the log to start at the bottom")
+	}
+
+	i := m.Append(Message{Sender: "assistant", Time: start, Streaming: true})
+	for _, chunk := range []string{"Here ", "are ", "the ", "highlights."} {
+		m, _ = m.Update(m.Chunk(i, chunk, false)())
+		if !m.AtBottom() {
+			t.Fatal("expected the log to follow streamed content")
+		}
+	}
+	m, _ = m.Update(m.Chunk(i, "", true)())
+	if got := m.Messages()[i]; got.Content != "Here are the highlights." || got.Streaming {
+		t.Fatalf("unexpected message: %+v", got)
+	}
+
+	// Chunks for other logs are ignored.
+	m, _ = m.Update(ChunkMsg{ID: m.ID() + 1, Index: i, Chunk: "!"})
+	if got := m.Messages()[i].Content; got != "Here are the highlights." {
+		t.Fatalf("expected chunk for another log to be ignored, got %q", got)
+	}
+
+	// Once the user scrolls up, new content doesn't move the view.
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	offset := m.viewport.YOffset
+	m.AppendChunk(i, " More to come.")
+	if m.AtBottom() || m.viewport.YOffset != offset {
+		t.Fatal("expected the view to stay put after scrolling up")
+	}
+	m.GotoBottom()
+	m.AppendChunk(i, " And more.")
+	if !m.AtBottom() {
+		t.Fatal("expected the log to follow content again")
+	}
+}
+
+func TestSelection(t *testing.T) {
+	m := newChat()
+	m.SetSize(30, 4)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("K")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("K")})
+	if m.Selected() != 0 {
+		t.Fatalf("expected the first message to be selected, got %d", m.Selected())
+	}
+	if m.viewport.YOffset != 0 {
+		t.Fatalf("expected the selected message to be scrolled into view, got offset %d", m.viewport.YOffset)
+	}
+	var out strings.Builder
+	m.Clipboard = clipboard.NewOSC52(&out)
+	cmd := m.CopySelected()
+	if cmd == nil {
+		t.Fatal("expected a copy command")
+	}
+	if msg := cmd().(CopiedMsg); msg.Err != nil || out.String() != ansi.SetSystemClipboard(msg.Message.Content) {
+		t.Fatalf("expected the message to be copied, got %q", out.String())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.Selected() != -1 || m.CopySelected() != nil {
+		t.Fatal("expected the selection to be cleared")
+	}
+}
@@ -0,0 +1,92 @@
+// Package clipboard provides the clipboards bubbles copy text to and paste
+// text from: the system clipboard, the terminal's clipboard, reached with
+// OSC 52 escape sequences, and one which discards what's copied.
+package clipboard
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	system "github.com/atotto/clipboard"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Clipboard is where text is copied to and pasted from.
+type Clipboard interface {
+	ReadAll() (string, error)
+	WriteAll(text string) error
+}
+
+// System is the system clipboard of the machine the program runs on. Bubbles
+// use it when they aren't given a clipboard.
+var System Clipboard = systemClipboard{}
+
+type systemClipboard struct{}
+
+func (systemClipboard) ReadAll() (string, error) {
+	return system.ReadAll() //nolint:wrapcheck
+}
+
+func (systemClipboard) WriteAll(text string) error {
+	return system.WriteAll(text) //nolint:wrapcheck
+}
+
+// Discard is a clipboard which leaves the clipboard alone, for programs
+// handling the copied text themselves. Pasting from it pastes nothing.
+var Discard Clipboard = discard{}
+
+type discard struct{}
+
+func (discard) ReadAll() (string, error) { return "", nil }
+
+func (discard) WriteAll(string) error { return nil }
+
+// ErrEmpty is returned when pasting from an OSC52 clipboard which nothing's
+// been copied to yet.
+var ErrEmpty = errors.New("clipboard: nothing has been copied")
+
+// OSC52 copies text to the clipboard of the terminal with an OSC 52 escape
+// sequence, which works over SSH where there's no system clipboard to reach.
+// Terminals rarely allow reading their clipboard, so pasting pastes the text
+// last copied through it.
+type OSC52 struct {
+	mu     sync.Mutex
+	output io.Writer
+	text   string
+	copied bool
+}
+
+// NewOSC52 returns a clipboard writing OSC 52 sequences to w, which should be
+// the output of the program, as given to tea.WithOutput, so that they reach
+// the terminal showing it.
+func NewOSC52(w io.Writer) *OSC52 {
+	return &OSC52{output: w}
+}
+
+// ReadAll returns the text last copied.
+func (c *OSC52) ReadAll() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.copied {
+		return "", ErrEmpty
+	}
+	return c.text, nil
+}
+
+// WriteAll sets the terminal's clipboard to text.
+func (c *OSC52) WriteAll(text string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.text, c.copied = text, true
+	_, err := io.WriteString(c.output, ansi.SetSystemClipboard(text))
+	return err //nolint:wrapcheck
+}
+
+// Or returns c, or System if c is nil.
+func Or(c Clipboard) Clipboard {
+	if c == nil {
+		return System
+	}
+	return c
+}
@@ -0,0 +1,35 @@
+package clipboard
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestOSC52(t *testing.T) {
+	var out bytes.Buffer
+	c := NewOSC52(&out)
+	if _, err := c.ReadAll(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expected ErrEmpty before copying, got %v", err)
+	}
+	if err := c.WriteAll("foo\nbar"); err != nil {
+		t.Fatal(err)
+	}
+	if want := ansi.SetSystemClipboard("foo\nbar"); out.String() != want {
+		t.Errorf("expected %q to be written, got %q", want, out.String())
+	}
+	if text, err := c.ReadAll(); err != nil || text != "foo\nbar" {
+		t.Errorf("expected the copied text back, got %q, %v", text, err)
+	}
+}
+
+func TestOr(t *testing.T) {
+	if Or(nil) != System {
+		t.Error("expected the system clipboard for nil")
+	}
+	if Or(Discard) != Discard {
+		t.Error("expected the given clipboard")
+	}
+}
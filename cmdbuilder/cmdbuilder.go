@@ -0,0 +1,469 @@
+// Package cmdbuilder provides a component for filling in the flags and
+// arguments of a command template in Bubble Tea applications. It shows a live
+// preview of the assembled command and emits it once every slot is valid.
+package cmdbuilder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/help"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
+	"github.com/mikeflynn/bubbles/textinput"
+)
+
+// ErrRequired is the error reported for required slots which have been left
+// empty.
+var ErrRequired = errors.New("required")
+
+// Slot describes an editable placeholder in a command template.
+type Slot struct {
+	// Name identifies the slot. It's referenced in the template as {name}.
+	Name string
+
+	// Description is shown alongside the slot's input.
+	Description string
+
+	// Default is the slot's initial value.
+	Default string
+
+	// Required slots must have a value before the command can be confirmed.
+	Required bool
+
+	// Validate, if set, checks the slot's value. Errors are shown next to the
+	// input and prevent the command from being confirmed.
+	Validate textinput.ValidateFunc
+}
+
+// CommandMsg is sent when the user confirms the command and every slot is
+// valid.
+type CommandMsg struct {
+	// Command is the assembled command string.
+	Command string
+
+	// Values holds the raw, unquoted value of each slot.
+	Values map[string]string
+}
+
+// part is either literal template text or a reference to a slot.
+type part struct {
+	text string
+	slot int // index into Model.slots, or -1 for literal text
+}
+
+// KeyMap is the key bindings for moving between the slots of the command
+// and confirming it. It satisfies the help.KeyMap interface.
+type KeyMap struct {
+	NextSlot key.Binding
+	PrevSlot key.Binding
+	Confirm  key.Binding
+}
+
+// ShortHelp implements the KeyMap interface.
+func (km KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{km.NextSlot, km.PrevSlot, km.Confirm}
+}
+
+// FullHelp implements the KeyMap interface.
+func (km KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{km.NextSlot, km.PrevSlot, km.Confirm}}
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		NextSlot: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "next"),
+		),
+		PrevSlot: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "prev"),
+		),
+		Confirm: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "confirm"),
+		),
+	}
+}
+
+// Styles are the styles of the slots and of the command preview, as returned
+// by DefaultStyles by default.
+type Styles struct {
+	// Preview styles.
+	Preview     lipgloss.Style
+	Literal     lipgloss.Style
+	Value       lipgloss.Style
+	ActiveValue lipgloss.Style
+	EmptySlot   lipgloss.Style
+
+	// Slot list styles.
+	Label       lipgloss.Style
+	ActiveLabel lipgloss.Style
+	Description lipgloss.Style
+	Error       lipgloss.Style
+	HelpSection lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this
+// component.
+func DefaultStyles() Styles {
+	subdued := lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}
+	active := lipgloss.AdaptiveColor{Light: "#F793FF", Dark: "#AD58B4"}
+
+	return Styles{
+		Preview:     lipgloss.NewStyle().PaddingBottom(1),
+		Literal:     lipgloss.NewStyle(),
+		Value:       lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#00A66D", Dark: "#04B575"}),
+		ActiveValue: lipgloss.NewStyle().Foreground(active).Underline(true),
+		EmptySlot:   lipgloss.NewStyle().Foreground(subdued),
+		Label:       lipgloss.NewStyle(),
+		ActiveLabel: lipgloss.NewStyle().Foreground(active).Bold(true),
+		Description: lipgloss.NewStyle().Foreground(subdued),
+		Error:       lipgloss.NewStyle().Foreground(lipgloss.Color("203")),
+		HelpSection: lipgloss.NewStyle().PaddingTop(1),
+	}
+}
+
+// Model is the state of a command builder.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+	Help   help.Model
+
+	// ShowHelp determines whether the help view is rendered beneath the
+	// slots.
+	ShowHelp bool
+
+	// Quote is used to quote slot values when assembling the command. By
+	// default values are quoted for POSIX shells when needed.
+	Quote func(string) string
+
+	parts  []part
+	slots  []Slot
+	inputs []textinput.Model
+	errs   []error
+	focus  int
+}
+
+// New returns a model for the given command template. Slots are referenced in
+// the template as {name}; any slot that's referenced but not passed in slots
+// is added as an optional slot with no validation.
+func New(template string, slots ...Slot) Model {
+	m := Model{
+		KeyMap:   DefaultKeyMap(),
+		Styles:   DefaultStyles(),
+		Help:     help.New(),
+		ShowHelp: true,
+		Quote:    ShellQuote,
+	}
+	m.parts, m.slots = parse(template, slots)
+
+	m.inputs = make([]textinput.Model, len(m.slots))
+	m.errs = make([]error, len(m.slots))
+	for i, s := range m.slots {
+		ti := textinput.New()
+		ti.Prompt = ""
+		ti.Placeholder = s.Name
+		ti.Validate = s.Validate
+		ti.SetValue(s.Default)
+		m.inputs[i] = ti
+	}
+	if len(m.inputs) > 0 {
+		m.inputs[0].Focus()
+	}
+	return m
+}
+
+// parse splits a template into literal text and slot references, returning
+// the slots in the order they're first referenced.
+func parse(template string, slots []Slot) ([]part, []Slot) {
+	var (
+		parts    []part
+		ordered  []Slot
+		index    = map[string]int{}
+		declared = map[string]Slot{}
+		literal  strings.Builder
+	)
+	for _, s := range slots {
+		declared[s.Name] = s
+	}
+
+	for len(template) > 0 {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			literal.WriteString(template)
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		name := ""
+		if end > 0 {
+			name = template[start+1 : start+end]
+		}
+		if !validName(name) {
+			literal.WriteString(template[:start+1])
+			template = template[start+1:]
+			continue
+		}
+
+		literal.WriteString(template[:start])
+		if literal.Len() > 0 {
+			parts = append(parts, part{text: literal.String(), slot: -1})
+			literal.Reset()
+		}
+
+		i, ok := index[name]
+		if !ok {
+			s, ok := declared[name]
+			if !ok {
+				s = Slot{Name: name}
+			}
+			i = len(ordered)
+			index[name] = i
+			ordered = append(ordered, s)
+		}
+		parts = append(parts, part{slot: i})
+		template = template[start+end+1:]
+	}
+	if literal.Len() > 0 {
+		parts = append(parts, part{text: literal.String(), slot: -1})
+	}
+
+	return parts, ordered
+}
+
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r != '_' && r != '-' &&
+			(r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Slots returns the slots referenced by the template, in order.
+func (m Model) Slots() []Slot {
+	return m.slots
+}
+
+// Focused returns the index of the slot being edited.
+func (m Model) Focused() int {
+	return m.focus
+}
+
+// SetFocus moves focus to the slot at the given index.
+func (m *Model) SetFocus(i int) tea.Cmd {
+	if len(m.inputs) == 0 {
+		return nil
+	}
+	m.inputs[m.focus].Blur()
+	m.focus = clamp(i, 0, len(m.inputs)-1)
+	return m.inputs[m.focus].Focus()
+}
+
+// Value returns the value of the named slot.
+func (m Model) Value(name string) string {
+	for i, s := range m.slots {
+		if s.Name == name {
+			return m.inputs[i].Value()
+		}
+	}
+	return ""
+}
+
+// SetValue sets the value of the named slot.
+func (m *Model) SetValue(name, value string) {
+	for i, s := range m.slots {
+		if s.Name == name {
+			m.inputs[i].SetValue(value)
+			m.errs[i] = nil
+		}
+	}
+}
+
+// Values returns the value of every slot, keyed by name.
+func (m Model) Values() map[string]string {
+	values := make(map[string]string, len(m.slots))
+	for i, s := range m.slots {
+		values[s.Name] = m.inputs[i].Value()
+	}
+	return values
+}
+
+// Command returns the command assembled from the template and the current
+// slot values.
+func (m Model) Command() string {
+	var b strings.Builder
+	for _, p := range m.parts {
+		if p.slot < 0 {
+			b.WriteString(p.text)
+			continue
+		}
+		v := m.inputs[p.slot].Value()
+		if m.Quote != nil {
+			v = m.Quote(v)
+		}
+		b.WriteString(v)
+	}
+	return b.String()
+}
+
+// Validate checks every slot, returning the first error found. Errors are
+// also recorded so they can be shown next to each slot.
+func (m *Model) Validate() error {
+	var first error
+	for i := range m.slots {
+		m.errs[i] = m.validateSlot(i)
+		if m.errs[i] != nil && first == nil {
+			first = fmt.Errorf("%s: %w", m.slots[i].Name, m.errs[i])
+		}
+	}
+	return first
+}
+
+func (m Model) validateSlot(i int) error {
+	v := m.inputs[i].Value()
+	if v == "" {
+		if m.slots[i].Required {
+			return ErrRequired
+		}
+		return nil
+	}
+	if m.slots[i].Validate != nil {
+		return m.slots[i].Validate(v)
+	}
+	return nil
+}
+
+// Init exists to satisfy the tea.Model interface.
+func (m Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if len(m.inputs) == 0 {
+		return m, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.KeyMap.NextSlot):
+			return m, m.SetFocus((m.focus + 1) % len(m.inputs))
+		case key.Matches(msg, m.KeyMap.PrevSlot):
+			return m, m.SetFocus((m.focus - 1 + len(m.inputs)) % len(m.inputs))
+		case key.Matches(msg, m.KeyMap.Confirm):
+			if m.Validate() != nil {
+				for i, err := range m.errs {
+					if err != nil {
+						return m, m.SetFocus(i)
+					}
+				}
+			}
+			cmdMsg := CommandMsg{Command: m.Command(), Values: m.Values()}
+			return m, func() tea.Msg {
+				return cmdMsg
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	prev := m.inputs[m.focus].Value()
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	if m.inputs[m.focus].Value() != prev {
+		m.errs[m.focus] = m.validateSlot(m.focus)
+	}
+	return m, cmd
+}
+
+// View renders the component.
+func (m Model) View() string {
+	sections := []string{m.Styles.Preview.Render(m.previewView())}
+
+	labelWidth := 0
+	for _, s := range m.slots {
+		labelWidth = max(labelWidth, lipgloss.Width(s.Name))
+	}
+
+	for i, s := range m.slots {
+		label := m.Styles.Label
+		if i == m.focus {
+			label = m.Styles.ActiveLabel
+		}
+		row := label.Width(labelWidth).Render(s.Name) + "  " + m.inputs[i].View()
+		if m.errs[i] != nil {
+			row += "  " + m.Styles.Error.Render(m.errs[i].Error())
+		} else if s.Description != "" {
+			row += "  " + m.Styles.Description.Render(s.Description)
+		}
+		sections = append(sections, row)
+	}
+
+	if m.ShowHelp {
+		sections = append(sections, m.Styles.HelpSection.Render(m.Help.View(m.KeyMap)))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// RenderString renders the builder deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	return render.View(m.View, width, height)
+}
+
+// previewView renders the live preview of the assembled command.
+func (m Model) previewView() string {
+	var b strings.Builder
+	for _, p := range m.parts {
+		if p.slot < 0 {
+			b.WriteString(m.Styles.Literal.Render(p.text))
+			continue
+		}
+
+		v := m.inputs[p.slot].Value()
+		if v == "" {
+			b.WriteString(m.Styles.EmptySlot.Render("{" + m.slots[p.slot].Name + "}"))
+			continue
+		}
+		if m.Quote != nil {
+			v = m.Quote(v)
+		}
+		style := m.Styles.Value
+		if p.slot == m.focus {
+			style = m.Styles.ActiveValue
+		}
+		b.WriteString(style.Render(v))
+	}
+	return b.String()
+}
+
+// ShellQuote quotes s for POSIX shells if it contains anything other than
+// characters which are safe to leave unquoted.
+func ShellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	for _, r := range s {
+		if !shellSafe(r) {
+			return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+		}
+	}
+	return s
+}
+
+func shellSafe(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+		strings.ContainsRune("@%+=:,./-_", r)
+}
+
+func clamp(v, low, high int) int {
+	return min(max(v, low), high)
+}
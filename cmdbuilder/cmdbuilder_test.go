@@ -0,0 +1,101 @@
+package cmdbuilder
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+func newBuilder() Model {
+	return New("kubectl scale deployment/{name} --replicas={replicas} -n {namespace}",
+		Slot{Name: "name", Required: true},
+		Slot{Name: "replicas", Default: "1", Validate: func(s string) error {
+			if _, err := strconv.Atoi(s); err != nil {
+				return errors.New("must be a number")
+			}
+			return nil
+		}},
+	)
+}
+
+func TestParse(t *testing.T) {
+	m := New("echo {a} {not a slot} {a} {b}")
+
+	slots := m.Slots()
+	if len(slots) != 2 || slots[0].Name != "a" || slots[1].Name != "b" {
+		t.Fatalf("unexpected slots: %+v", slots)
+	}
+
+	m.SetValue("a", "x")
+	m.SetValue("b", "hello world")
+	if got, want := m.Command(), "echo x {not a slot} x 'hello world'"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	for in, want := range map[string]string{
+		"":            "''",
+		"plain":       "plain",
+		"a/b.c:d=e":   "a/b.c:d=e",
+		"two words":   "'two words'",
+		"it's":        `'it'\''s'`,
+		"$(rm -rf /)": "'$(rm -rf /)'",
+	} {
+		if got := ShellQuote(in); got != want {
+			t.Errorf("ShellQuote(%q): expected %q, got %q", in, want, got)
+		}
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	m := newBuilder()
+
+	// The required name is missing, so confirming focuses it instead.
+	m.SetFocus(2)
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.Focused() != 0 {
+		t.Fatalf("expected focus to move to the invalid slot, got %d", m.Focused())
+	}
+	if cmd != nil {
+		if _, ok := cmd().(CommandMsg); ok {
+			t.Fatal("expected no command while slots are invalid")
+		}
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("web")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if m.Validate() == nil {
+		t.Fatal("expected replicas to be invalid")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("prod")})
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msg, ok := cmd().(CommandMsg)
+	if !ok {
+		t.Fatalf("expected CommandMsg, got %T", cmd())
+	}
+	if want := "kubectl scale deployment/web --replicas=1 -n prod"; msg.Command != want {
+		t.Errorf("expected %q, got %q", want, msg.Command)
+	}
+	if msg.Values["namespace"] != "prod" {
+		t.Errorf("expected raw values, got %v", msg.Values)
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(newBuilder(), 70, 10)))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		m := newBuilder()
+		m.SetValue("replicas", "x")
+		golden.RequireEqual(t, []byte(RenderString(m, 70, 10)))
+	})
+}
@@ -0,0 +1,597 @@
+// Package colorpicker provides a color picker for Bubble Tea applications. It
+// renders a palette of swatches, hue and lightness sliders and an input for
+// hex or RGB values, all navigable by keyboard, and can snap colors to the
+// 256-color ANSI palette for terminals without true color.
+package colorpicker
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/mikeflynn/bubbles/help"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
+	"github.com/mikeflynn/bubbles/textinput"
+	"github.com/muesli/termenv"
+)
+
+const (
+	// swatchWidth is the width of a swatch in the palette.
+	swatchWidth = 3
+
+	// labelWidth is the width of the slider labels.
+	labelWidth = 11
+)
+
+// ErrInvalidColor is returned for text which isn't a hex or RGB color.
+var ErrInvalidColor = errors.New("colorpicker: invalid color")
+
+// SelectedMsg is sent when the user chooses a color. Color is its hex value,
+// or its ANSI index if colors are snapped to the 256-color palette.
+type SelectedMsg struct {
+	Color lipgloss.Color
+	Hex   string
+}
+
+// DefaultPalette holds the 16 basic terminal colors, followed by a row of
+// softer accents.
+var DefaultPalette = []string{
+	"#000000", "#800000", "#008000", "#808000", "#000080", "#800080", "#008080", "#C0C0C0",
+	"#808080", "#FF0000", "#00FF00", "#FFFF00", "#0000FF", "#FF00FF", "#00FFFF", "#FFFFFF",
+	"#AD58B4", "#F793FF", "#FF4672", "#ED567A", "#E8A317", "#04B575", "#5A56E0", "#A49FA5",
+}
+
+// Field is a part of the picker which can have focus.
+type Field int
+
+// Fields, in the order focus moves through them.
+const (
+	PaletteField Field = iota
+	HueField
+	LightnessField
+	InputField
+)
+
+// fieldCount is the number of fields.
+const fieldCount = 4
+
+// KeyMap is the key bindings for moving between the picker's fields,
+// adjusting them and choosing the color. It satisfies the help.KeyMap
+// interface.
+type KeyMap struct {
+	Up        key.Binding
+	Down      key.Binding
+	Left      key.Binding
+	Right     key.Binding
+	NextField key.Binding
+	PrevField key.Binding
+	Select    key.Binding
+}
+
+// ShortHelp implements the KeyMap interface.
+func (km KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{km.Left, km.Right, km.NextField, km.Select}
+}
+
+// FullHelp implements the KeyMap interface.
+func (km KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{km.Up, km.Down, km.Left, km.Right},
+		{km.NextField, km.PrevField, km.Select},
+	}
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Left: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "left/less"),
+		),
+		Right: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "right/more"),
+		),
+		NextField: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "next field"),
+		),
+		PrevField: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "prev field"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "select"),
+		),
+	}
+}
+
+// Styles are the styles of the picker's field labels, slider knobs and
+// values, as returned by DefaultStyles by default.
+type Styles struct {
+	Label        lipgloss.Style
+	FocusedLabel lipgloss.Style
+	Knob         lipgloss.Style
+	Value        lipgloss.Style
+	Error        lipgloss.Style
+	HelpSection  lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this
+// component.
+func DefaultStyles() Styles {
+	subdued := lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}
+
+	return Styles{
+		Label:        lipgloss.NewStyle().Foreground(subdued),
+		FocusedLabel: lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#AD58B4", Dark: "#F793FF"}).Bold(true),
+		Knob:         lipgloss.NewStyle().Bold(true),
+		Value:        lipgloss.NewStyle(),
+		Error:        lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#FF4672", Dark: "#ED567A"}),
+		HelpSection:  lipgloss.NewStyle().PaddingTop(1),
+	}
+}
+
+// Model is the state of a color picker.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+	Help   help.Model
+
+	// ShowHelp determines whether the help view is rendered beneath the
+	// picker.
+	ShowHelp bool
+
+	// Palette holds the hex colors of the swatches, laid out in rows of
+	// Columns.
+	Palette []string
+	Columns int
+
+	// HueStep and LightnessStep are how far the sliders move at a time, in
+	// degrees and percentage points.
+	HueStep       float64
+	LightnessStep float64
+
+	// SliderWidth is the width of the slider tracks.
+	SliderWidth int
+
+	// ANSI256 snaps the color to the nearest of the 256 ANSI colors, for
+	// terminals without true color. The sliders still move smoothly, but the
+	// color shown and chosen is the snapped one.
+	ANSI256 bool
+
+	hue, saturation, lightness float64
+
+	focus  Field
+	cursor int
+	input  textinput.Model
+	err    error
+}
+
+// New returns a color picker with the first color of the palette chosen.
+func New() Model {
+	input := textinput.New()
+	input.Prompt = "Color: "
+	input.Placeholder = "#RRGGBB or R, G, B"
+	input.CharLimit = 24 //nolint:mnd
+
+	m := Model{
+		KeyMap:        DefaultKeyMap(),
+		Styles:        DefaultStyles(),
+		Help:          help.New(),
+		ShowHelp:      true,
+		Palette:       DefaultPalette,
+		Columns:       8,  //nolint:mnd
+		HueStep:       5,  //nolint:mnd
+		LightnessStep: 2,  //nolint:mnd
+		SliderWidth:   24, //nolint:mnd
+		input:         input,
+	}
+	if len(m.Palette) > 0 {
+		_ = m.SetHex(m.Palette[0])
+	}
+	return m
+}
+
+// SetHex sets the color from a hex value such as "#AD58B4" or "#F9F". It
+// returns ErrInvalidColor if the value can't be parsed.
+func (m *Model) SetHex(s string) error {
+	c, err := ParseColor(s)
+	if err != nil {
+		return err
+	}
+	m.setColor(c)
+	return nil
+}
+
+// SetRGB sets the color from its red, green and blue components.
+func (m *Model) SetRGB(r, g, b uint8) {
+	m.setColor(colorful.Color{R: float64(r) / 255, G: float64(g) / 255, B: float64(b) / 255}) //nolint:mnd
+}
+
+// Hex returns the hex value of the color, snapped to the 256-color palette
+// if ANSI256 is set.
+func (m Model) Hex() string {
+	return m.output().Hex()
+}
+
+// RGB returns the red, green and blue components of the color, snapped to
+// the 256-color palette if ANSI256 is set.
+func (m Model) RGB() (r, g, b uint8) {
+	return m.output().RGB255()
+}
+
+// Color returns the color for use in styles: its hex value, or its ANSI
+// index if ANSI256 is set.
+func (m Model) Color() lipgloss.Color {
+	if m.ANSI256 {
+		return lipgloss.Color(strconv.Itoa(int(m.ansi256())))
+	}
+	return lipgloss.Color(m.Hex())
+}
+
+// Hue returns the hue of the color, in degrees.
+func (m Model) Hue() float64 {
+	return m.hue
+}
+
+// Lightness returns the lightness of the color, from 0 to 1.
+func (m Model) Lightness() float64 {
+	return m.lightness
+}
+
+// Focused returns the field which has focus.
+func (m Model) Focused() Field {
+	return m.focus
+}
+
+// Focus gives focus to a field. Note that this returns a command, as the
+// input's cursor blinks.
+func (m *Model) Focus(f Field) tea.Cmd {
+	m.focus = Field(clamp(int(f), 0, fieldCount-1))
+	if m.focus != InputField {
+		m.input.Blur()
+		m.syncInput()
+		return nil
+	}
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+// Err returns the error from parsing the text in the input, if any.
+func (m Model) Err() error {
+	return m.err
+}
+
+// ParseColor parses a hex color, such as "#AD58B4", "AD58B4" or "#F9F", or
+// an RGB color, such as "173, 88, 180" or "rgb(173 88 180)".
+func ParseColor(s string) (colorful.Color, error) {
+	s = strings.TrimSpace(s)
+	lower := strings.ToLower(s)
+	if strings.HasPrefix(lower, "rgb(") && strings.HasSuffix(lower, ")") {
+		return parseRGB(s[4 : len(s)-1])
+	}
+	if strings.ContainsAny(s, ", ") {
+		return parseRGB(s)
+	}
+
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 { //nolint:mnd
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 { //nolint:mnd
+		return colorful.Color{}, fmt.Errorf("%w: %q", ErrInvalidColor, s)
+	}
+	c, err := colorful.Hex("#" + hex)
+	if err != nil {
+		return colorful.Color{}, fmt.Errorf("%w: %q", ErrInvalidColor, s)
+	}
+	return c, nil
+}
+
+func parseRGB(s string) (colorful.Color, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+	if len(fields) != 3 { //nolint:mnd
+		return colorful.Color{}, fmt.Errorf("%w: %q", ErrInvalidColor, s)
+	}
+	var rgb [3]float64
+	for i, f := range fields {
+		n, err := strconv.ParseUint(f, 10, 8)
+		if err != nil {
+			return colorful.Color{}, fmt.Errorf("%w: %q", ErrInvalidColor, s)
+		}
+		rgb[i] = float64(n) / 255 //nolint:mnd
+	}
+	return colorful.Color{R: rgb[0], G: rgb[1], B: rgb[2]}, nil
+}
+
+// setColor sets the color, keeping the hue of grays and the saturation of
+// black and white so that the sliders don't jump. The palette's cursor moves
+// to the color if it's in the palette.
+func (m *Model) setColor(c colorful.Color) {
+	for i, hex := range m.Palette {
+		if p, err := ParseColor(hex); err == nil && p.Hex() == c.Hex() {
+			m.cursor = i
+			break
+		}
+	}
+
+	h, s, l := c.Hsl()
+	if s > 0 {
+		m.hue = h
+	}
+	if l > 0 && l < 1 {
+		m.saturation = s
+	}
+	m.lightness = l
+	m.syncInput()
+}
+
+// color returns the color as chosen, without snapping.
+func (m Model) color() colorful.Color {
+	return colorful.Hsl(m.hue, m.saturation, m.lightness).Clamped()
+}
+
+// output returns the color, snapped if ANSI256 is set.
+func (m Model) output() colorful.Color {
+	if m.ANSI256 {
+		return termenv.ConvertToRGB(m.ansi256())
+	}
+	return m.color()
+}
+
+func (m Model) ansi256() termenv.ANSI256Color {
+	if c, ok := termenv.ANSI256.Convert(termenv.RGBColor(m.color().Hex())).(termenv.ANSI256Color); ok {
+		return c
+	}
+	return 0
+}
+
+// syncInput shows the color in the input, unless it's being typed into.
+func (m *Model) syncInput() {
+	if m.focus == InputField {
+		return
+	}
+	m.input.SetValue(m.Hex())
+	m.err = nil
+}
+
+// Init exists to satisfy the tea.Model interface.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		if m.focus == InputField {
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.KeyMap.Select):
+		if m.err != nil {
+			return m, nil
+		}
+		sel := SelectedMsg{Color: m.Color(), Hex: m.Hex()}
+		return m, func() tea.Msg {
+			return sel
+		}
+	case key.Matches(keyMsg, m.KeyMap.NextField):
+		return m, m.Focus((m.focus + 1) % fieldCount)
+	case key.Matches(keyMsg, m.KeyMap.PrevField):
+		return m, m.Focus((m.focus + fieldCount - 1) % fieldCount)
+	}
+
+	switch m.focus {
+	case PaletteField:
+		m.updatePalette(keyMsg)
+	case HueField, LightnessField:
+		return m, m.updateSlider(keyMsg)
+	case InputField:
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(keyMsg)
+		m.applyInput()
+		return m, cmd
+	}
+	return m, nil
+}
+
+// updatePalette moves the cursor around the palette, choosing the color
+// under it.
+func (m *Model) updatePalette(msg tea.KeyMsg) {
+	cols := max(1, m.Columns)
+	next := m.cursor
+	switch {
+	case key.Matches(msg, m.KeyMap.Up):
+		next -= cols
+	case key.Matches(msg, m.KeyMap.Down):
+		next += cols
+	case key.Matches(msg, m.KeyMap.Left):
+		next--
+	case key.Matches(msg, m.KeyMap.Right):
+		next++
+	default:
+		return
+	}
+	if next < 0 || next >= len(m.Palette) {
+		return
+	}
+	m.cursor = next
+	_ = m.SetHex(m.Palette[next])
+}
+
+// updateSlider moves the focused slider, or moves focus between the sliders
+// with the up and down keys.
+func (m *Model) updateSlider(msg tea.KeyMsg) tea.Cmd {
+	step := 0.0
+	switch {
+	case key.Matches(msg, m.KeyMap.Up):
+		return m.Focus(m.focus - 1)
+	case key.Matches(msg, m.KeyMap.Down):
+		return m.Focus(m.focus + 1)
+	case key.Matches(msg, m.KeyMap.Left):
+		step = -1
+	case key.Matches(msg, m.KeyMap.Right):
+		step = 1
+	default:
+		return nil
+	}
+
+	if m.focus == HueField {
+		m.hue = math.Mod(m.hue+step*m.HueStep+360, 360) //nolint:mnd
+	} else {
+		m.lightness = clampFloat(m.lightness+step*m.LightnessStep/100, 0, 1) //nolint:mnd
+	}
+	m.syncInput()
+	return nil
+}
+
+// applyInput chooses the color typed into the input, if it's valid.
+func (m *Model) applyInput() {
+	c, err := ParseColor(m.input.Value())
+	m.err = err
+	if err != nil {
+		return
+	}
+	m.setColor(c)
+}
+
+// View renders the component.
+func (m Model) View() string {
+	var b strings.Builder
+
+	if len(m.Palette) > 0 {
+		b.WriteString(m.paletteView())
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(m.sliderView(HueField, "Hue", m.hue/360, fmt.Sprintf("%3.0f°", m.hue), func(p float64) colorful.Color { //nolint:mnd
+		return colorful.Hsl(p*360, m.saturation, m.lightness) //nolint:mnd
+	}))
+	b.WriteString("\n")
+	b.WriteString(m.sliderView(LightnessField, "Lightness", m.lightness, fmt.Sprintf("%3.0f%%", m.lightness*100), func(p float64) colorful.Color { //nolint:mnd
+		return colorful.Hsl(m.hue, m.saturation, p)
+	}))
+	b.WriteString("\n\n")
+
+	b.WriteString(m.swatch(m.output(), "      "))
+	r, g, bl := m.RGB()
+	value := fmt.Sprintf(" %s  rgb(%d, %d, %d)", m.Hex(), r, g, bl)
+	if m.ANSI256 {
+		value += fmt.Sprintf("  ansi %d", m.ansi256())
+	}
+	b.WriteString(m.Styles.Value.Render(value))
+	b.WriteString("\n")
+
+	input := m.input
+	input.PromptStyle = m.Styles.Label
+	if m.focus == InputField {
+		input.PromptStyle = m.Styles.FocusedLabel
+	}
+	b.WriteString(input.View())
+	if m.err != nil {
+		b.WriteString("\n")
+		b.WriteString(m.Styles.Error.Render(m.err.Error()))
+	}
+
+	if m.ShowHelp {
+		b.WriteString("\n")
+		b.WriteString(m.Styles.HelpSection.Render(m.Help.View(m.KeyMap)))
+	}
+
+	return b.String()
+}
+
+// RenderString renders the color picker deterministically at the given
+// size, for golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	return render.View(m.View, width, height)
+}
+
+func (m Model) paletteView() string {
+	cols := max(1, m.Columns)
+	rows := make([]string, 0, (len(m.Palette)+cols-1)/cols)
+	var row strings.Builder
+	for i, hex := range m.Palette {
+		c, err := ParseColor(hex)
+		if err != nil {
+			row.WriteString(strings.Repeat(" ", swatchWidth))
+		} else {
+			if m.ANSI256 {
+				c = termenv.ConvertToRGB(termenv.ANSI256.Convert(termenv.RGBColor(c.Hex())))
+			}
+			content := strings.Repeat(" ", swatchWidth)
+			if i == m.cursor && m.focus == PaletteField {
+				content = "[ ]"
+			}
+			row.WriteString(m.swatch(c, content))
+		}
+		if (i+1)%cols == 0 || i == len(m.Palette)-1 {
+			rows = append(rows, row.String())
+			row.Reset()
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// sliderView renders a slider with its label, a track colored by the color
+// at each position, a knob at pos, from 0 to 1, and its value.
+func (m Model) sliderView(f Field, label string, pos float64, value string, at func(p float64) colorful.Color) string {
+	labelStyle := m.Styles.Label
+	if m.focus == f {
+		labelStyle = m.Styles.FocusedLabel
+	}
+
+	width := max(2, m.SliderWidth) //nolint:mnd
+	knob := int(math.Round(clampFloat(pos, 0, 1) * float64(width-1)))
+	var track strings.Builder
+	for i := range width {
+		if i == knob {
+			track.WriteString(m.Styles.Knob.Render("●"))
+			continue
+		}
+		c := at(float64(i) / float64(width-1)).Clamped()
+		track.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(c.Hex())).Render("━"))
+	}
+
+	return labelStyle.Width(labelWidth).Render(label) + track.String() + " " + m.Styles.Value.Render(value)
+}
+
+// swatch renders content on a background of the given color, in black or
+// white, whichever stands out.
+func (m Model) swatch(c colorful.Color, content string) string {
+	fg := lipgloss.Color("#FFFFFF")
+	if _, _, l := c.Hsl(); l > 0.6 { //nolint:mnd
+		fg = lipgloss.Color("#000000")
+	}
+	return lipgloss.NewStyle().Background(lipgloss.Color(c.Hex())).Foreground(fg).Render(content)
+}
+
+func clamp(v, low, high int) int {
+	return min(max(v, low), high)
+}
+
+func clampFloat(v, low, high float64) float64 {
+	return math.Min(math.Max(v, low), high)
+}
@@ -0,0 +1,124 @@
+package colorpicker
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+func keyPress(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestParseColor(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want string
+	}{
+		{"#AD58B4", "#ad58b4"},
+		{"ad58b4", "#ad58b4"},
+		{"#F9F", "#ff99ff"},
+		{"173, 88, 180", "#ad58b4"},
+		{"rgb(173 88 180)", "#ad58b4"},
+	} {
+		c, err := ParseColor(tc.in)
+		if err != nil || c.Hex() != tc.want {
+			t.Fatalf("%q: expected %s, got %s (%v)", tc.in, tc.want, c.Hex(), err)
+		}
+	}
+	for _, in := range []string{"", "#12345", "red", "1, 2", "256, 0, 0"} {
+		if _, err := ParseColor(in); !errors.Is(err, ErrInvalidColor) {
+			t.Fatalf("%q: expected ErrInvalidColor, got %v", in, err)
+		}
+	}
+}
+
+func TestNavigation(t *testing.T) {
+	m := New()
+	if m.Hex() != "#000000" {
+		t.Fatalf("expected the first palette color, got %s", m.Hex())
+	}
+
+	m, _ = m.Update(keyPress("j"))
+	m, _ = m.Update(keyPress("l"))
+	if m.Hex() != "#ff0000" {
+		t.Fatalf("expected red from the palette, got %s", m.Hex())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if m.Focused() != HueField {
+		t.Fatalf("expected the hue slider to have focus, got %d", m.Focused())
+	}
+	for range 24 {
+		m, _ = m.Update(keyPress("l"))
+	}
+	if m.Hex() != "#00ff00" || m.Hue() != 120 {
+		t.Fatalf("expected the hue to move to green, got %s at %.0f°", m.Hex(), m.Hue())
+	}
+
+	m, _ = m.Update(keyPress("j"))
+	for range 25 {
+		m, _ = m.Update(keyPress("h"))
+	}
+	if m.Focused() != LightnessField || m.Hex() != "#000000" {
+		t.Fatalf("expected the lightness to move to black, got %s", m.Hex())
+	}
+	for range 25 {
+		m, _ = m.Update(keyPress("l"))
+	}
+	if m.Hex() != "#00ff00" {
+		t.Fatalf("expected the hue to be kept through black, got %s", m.Hex())
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if msg, ok := cmd().(SelectedMsg); !ok || msg.Hex != "#00ff00" || msg.Color != lipgloss.Color("#00ff00") {
+		t.Fatalf("expected SelectedMsg for green, got %#v", cmd())
+	}
+}
+
+func TestInput(t *testing.T) {
+	m := New()
+	m.Focus(InputField)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	for _, r := range "12, 34, 5" {
+		m, _ = m.Update(keyPress(string(r)))
+	}
+	if r, g, b := m.RGB(); r != 12 || g != 34 || b != 5 || m.Err() != nil {
+		t.Fatalf("expected rgb(12, 34, 5), got rgb(%d, %d, %d) (%v)", r, g, b, m.Err())
+	}
+
+	m, _ = m.Update(keyPress("x"))
+	if !errors.Is(m.Err(), ErrInvalidColor) {
+		t.Fatalf("expected an error, got %v", m.Err())
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil {
+		t.Fatal("expected an invalid color not to be selected")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if m.Err() != nil || m.input.Value() != m.Hex() {
+		t.Fatalf("expected leaving the input to show the color, got %q", m.input.Value())
+	}
+}
+
+func TestANSI256(t *testing.T) {
+	m := New()
+	if err := m.SetHex("#AD58B4"); err != nil {
+		t.Fatal(err)
+	}
+	m.ANSI256 = true
+	if m.Color() != "133" || m.Hex() != "#af5faf" {
+		t.Fatalf("expected the color to snap to ANSI 133, got %s %s", m.Color(), m.Hex())
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	m := New()
+	m.ShowHelp = false
+	m.SliderWidth = 12
+	m.SetRGB(0xAD, 0x58, 0xB4)
+	golden.RequireEqual(t, []byte(RenderString(m, 40, 12)))
+}
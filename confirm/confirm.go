@@ -0,0 +1,382 @@
+// Package confirm provides a one-line prompt asking the user to confirm an
+// action, such as "Delete 3 files? Yes No", for Bubble Tea applications. It
+// fits in a status line or as the body of a modal. Buttons are chosen with
+// the arrow keys or their shortcut keys, and the answer is sent in a
+// ResultMsg.
+package confirm
+
+import (
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
+)
+
+var lastID int64
+
+func nextID() int {
+	return int(atomic.AddInt64(&lastID, 1))
+}
+
+// ResultMsg is sent when the user answers the prompt, by choosing a button or
+// canceling.
+type ResultMsg struct {
+	ID int
+
+	// Index is the index of the chosen button in Buttons, or -1 if the
+	// prompt was canceled, and Label is the button's label.
+	Index int
+	Label string
+}
+
+// Confirmed reports whether the first button was chosen, which is Yes unless
+// the buttons were changed.
+func (r ResultMsg) Confirmed() bool {
+	return r.Index == 0
+}
+
+// Canceled reports whether the prompt was canceled without choosing a
+// button.
+func (r ResultMsg) Canceled() bool {
+	return r.Index < 0
+}
+
+// Button is a button of the prompt.
+type Button struct {
+	Label string
+
+	// Key is the shortcut key choosing the button, such as "y". Letters
+	// match in either case, and the first one in the label is underlined.
+	Key string
+
+	// Destructive buttons, such as those deleting something, are drawn with
+	// the Destructive styles.
+	Destructive bool
+}
+
+// KeyMap is the key bindings for moving between the buttons and answering
+// the prompt. It satisfies the help.KeyMap interface. The buttons' shortcut
+// keys are matched besides these.
+type KeyMap struct {
+	Prev   key.Binding
+	Next   key.Binding
+	Choose key.Binding
+	Cancel key.Binding
+}
+
+// ShortHelp implements the KeyMap interface.
+func (km KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{km.Prev, km.Next, km.Choose, km.Cancel}
+}
+
+// FullHelp implements the KeyMap interface.
+func (km KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{km.Prev, km.Next}, {km.Choose, km.Cancel}}
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Prev: key.NewBinding(
+			key.WithKeys("left", "h", "shift+tab"),
+			key.WithHelp("←", "previous"),
+		),
+		Next: key.NewBinding(
+			key.WithKeys("right", "l", "tab"),
+			key.WithHelp("→", "next"),
+		),
+		Choose: key.NewBinding(
+			key.WithKeys("enter", " "),
+			key.WithHelp("enter", "choose"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+	}
+}
+
+// Styles are the styles of the prompt and its buttons. DefaultStyles
+// returns the defaults.
+type Styles struct {
+	Prompt lipgloss.Style
+
+	// Button is used for buttons and Selected for the selected one, or
+	// Destructive and DestructiveSelected if they're destructive. Their
+	// padding is kept when the shortcut key is underlined.
+	Button              lipgloss.Style
+	Selected            lipgloss.Style
+	Destructive         lipgloss.Style
+	DestructiveSelected lipgloss.Style
+
+	// Shortcut is applied over the button's style to the shortcut key in
+	// its label.
+	Shortcut lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this
+// component.
+func DefaultStyles() Styles {
+	danger := lipgloss.AdaptiveColor{Light: "#FF4672", Dark: "#ED567A"}
+	button := lipgloss.NewStyle().Padding(0, 1)
+
+	return Styles{
+		Prompt: lipgloss.NewStyle(),
+		Button: button.Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}),
+		Selected: button.Bold(true).
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(lipgloss.Color("#AD58B4")),
+		Destructive: button.Foreground(danger),
+		DestructiveSelected: button.Bold(true).
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(danger),
+		Shortcut: lipgloss.NewStyle().Underline(true),
+	}
+}
+
+// Model is the state of a confirmation prompt.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+
+	// Prompt is the question asked, shown before the buttons.
+	Prompt string
+
+	// Buttons are the buttons to choose from, in order.
+	Buttons []Button
+
+	// Default is the index of the button selected when the prompt is shown,
+	// and again when it's reset.
+	Default int
+
+	// X and Y are the position of the component on screen. They're used to
+	// work out which button was clicked.
+	X, Y int
+
+	id       int
+	selected int
+	answered bool
+}
+
+// Option is used to set options in New.
+type Option func(*Model)
+
+// New returns a prompt asking the given question, with Yes and No buttons
+// chosen with the y and n keys, and Yes selected.
+func New(prompt string, opts ...Option) Model {
+	m := Model{
+		KeyMap: DefaultKeyMap(),
+		Styles: DefaultStyles(),
+		Prompt: prompt,
+		Buttons: []Button{
+			{Label: "Yes", Key: "y"},
+			{Label: "No", Key: "n"},
+		},
+		id: nextID(),
+	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+	m.Reset()
+	return m
+}
+
+// WithButtons sets the buttons, in place of Yes and No.
+func WithButtons(buttons ...Button) Option {
+	return func(m *Model) {
+		m.Buttons = buttons
+	}
+}
+
+// WithDefault sets the index of the button selected at first.
+func WithDefault(i int) Option {
+	return func(m *Model) {
+		m.Default = i
+	}
+}
+
+// WithDestructive marks the first button as destructive and selects the last
+// one at first, so that pressing enter straight away doesn't delete
+// anything. It's meant to come after WithButtons, if it's used.
+func WithDestructive() Option {
+	return func(m *Model) {
+		if len(m.Buttons) == 0 {
+			return
+		}
+		m.Buttons[0].Destructive = true
+		m.Default = len(m.Buttons) - 1
+	}
+}
+
+// ID returns the unique ID of the model.
+func (m Model) ID() int {
+	return m.id
+}
+
+// Selected returns the index of the selected button.
+func (m Model) Selected() int {
+	return m.selected
+}
+
+// SetSelected selects a button.
+func (m *Model) SetSelected(i int) {
+	m.selected = clamp(i, 0, len(m.Buttons)-1)
+}
+
+// Answered reports whether the prompt was answered. Once it is, it ignores
+// input until it's reset.
+func (m Model) Answered() bool {
+	return m.answered
+}
+
+// Reset readies the prompt to be asked again, selecting the default button.
+func (m *Model) Reset() {
+	m.answered = false
+	m.SetSelected(m.Default)
+}
+
+// Init exists to satisfy the tea.Model interface.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if m.answered || len(m.Buttons) == 0 {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.Prev):
+			m.selected = (m.selected + len(m.Buttons) - 1) % len(m.Buttons)
+		case key.Matches(msg, m.KeyMap.Next):
+			m.selected = (m.selected + 1) % len(m.Buttons)
+		case key.Matches(msg, m.KeyMap.Choose):
+			return m, m.answer(m.selected)
+		case key.Matches(msg, m.KeyMap.Cancel):
+			return m, m.answer(-1)
+		default:
+			for i, b := range m.Buttons {
+				if shortcut(b).Enabled() && key.Matches(msg, shortcut(b)) {
+					m.selected = i
+					return m, m.answer(i)
+				}
+			}
+		}
+
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft || msg.Y != m.Y {
+			break
+		}
+		if i, ok := m.buttonAt(msg.X - m.X); ok {
+			m.selected = i
+			return m, m.answer(i)
+		}
+	}
+
+	return m, nil
+}
+
+// answer answers the prompt with the button at index i, or cancels it if i
+// is -1, returning a command sending the ResultMsg.
+func (m *Model) answer(i int) tea.Cmd {
+	m.answered = true
+	msg := ResultMsg{ID: m.id, Index: i}
+	if i >= 0 {
+		msg.Label = m.Buttons[i].Label
+	}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// shortcut returns the binding for a button's shortcut key, which matches
+// letters in either case.
+func shortcut(b Button) key.Binding {
+	if b.Key == "" {
+		return key.NewBinding()
+	}
+	keys := []string{b.Key}
+	if utf8.RuneCountInString(b.Key) == 1 {
+		if lower, upper := strings.ToLower(b.Key), strings.ToUpper(b.Key); lower != upper {
+			keys = []string{lower, upper}
+		}
+	}
+	return key.NewBinding(key.WithKeys(keys...))
+}
+
+// buttonAt returns the index of the button at a column relative to the
+// prompt.
+func (m Model) buttonAt(x int) (int, bool) {
+	x -= lipgloss.Width(m.promptView())
+	for i := range m.Buttons {
+		x-- // the space before the button
+		w := lipgloss.Width(m.buttonView(i))
+		if x >= 0 && x < w {
+			return i, true
+		}
+		x -= w
+	}
+	return 0, false
+}
+
+// View renders the component.
+func (m Model) View() string {
+	var b strings.Builder
+	b.WriteString(m.promptView())
+	for i := range m.Buttons {
+		b.WriteString(" " + m.buttonView(i))
+	}
+	return b.String()
+}
+
+// RenderString renders the prompt deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	return render.View(m.View, width, height)
+}
+
+func (m Model) promptView() string {
+	return m.Styles.Prompt.Render(m.Prompt)
+}
+
+// buttonView renders a button, with its shortcut key underlined in its
+// label.
+func (m Model) buttonView(i int) string {
+	b := m.Buttons[i]
+	style := m.Styles.Button
+	switch {
+	case b.Destructive && i == m.selected:
+		style = m.Styles.DestructiveSelected
+	case b.Destructive:
+		style = m.Styles.Destructive
+	case i == m.selected:
+		style = m.Styles.Selected
+	}
+
+	label := strings.Repeat(" ", style.GetPaddingLeft()) + b.Label + strings.Repeat(" ", style.GetPaddingRight())
+	style = style.UnsetPadding()
+	j := -1
+	if utf8.RuneCountInString(b.Key) == 1 {
+		j = strings.IndexFunc(label, func(r rune) bool {
+			return strings.EqualFold(string(r), b.Key)
+		})
+	}
+	if j < 0 {
+		return style.Render(label)
+	}
+	_, n := utf8.DecodeRuneInString(label[j:])
+	return style.Render(label[:j]) +
+		m.Styles.Shortcut.Inherit(style).Render(label[j:j+n]) +
+		style.Render(label[j+n:])
+}
+
+func clamp(v, low, high int) int {
+	return max(low, min(v, high))
+}
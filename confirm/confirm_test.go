@@ -0,0 +1,71 @@
+package confirm
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+func result(t *testing.T, cmd tea.Cmd) ResultMsg {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("expected a result")
+	}
+	msg, ok := cmd().(ResultMsg)
+	if !ok {
+		t.Fatalf("expected a ResultMsg, got %#v", cmd())
+	}
+	return msg
+}
+
+func TestKeys(t *testing.T) {
+	m := New("Delete 3 files?", WithDestructive())
+	if m.Selected() != 1 {
+		t.Fatalf("expected No to be selected at first, got %d", m.Selected())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if m.Selected() != 0 {
+		t.Fatalf("expected tab to wrap around to Yes, got %d", m.Selected())
+	}
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if msg := result(t, cmd); !msg.Confirmed() || msg.Label != "Yes" || msg.ID != m.ID() {
+		t.Fatalf("expected Yes, got %+v", msg)
+	}
+	if _, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil {
+		t.Fatal("expected an answered prompt to ignore input")
+	}
+
+	m.Reset()
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	if msg := result(t, cmd); msg.Index != 1 || msg.Confirmed() {
+		t.Fatalf("expected the shortcut to choose No, got %+v", msg)
+	}
+
+	m.Reset()
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if msg := result(t, cmd); !msg.Canceled() {
+		t.Fatalf("expected the prompt to be canceled, got %+v", msg)
+	}
+}
+
+func TestMouse(t *testing.T) {
+	m := New("Save?", WithButtons(
+		Button{Label: "Save", Key: "s"},
+		Button{Label: "Discard", Key: "d", Destructive: true},
+		Button{Label: "Cancel", Key: "c"},
+	))
+	m.X, m.Y = 2, 4
+
+	// "Save?" then " [ Save ]" and " [ Discard ]", the buttons being padded.
+	_, cmd := m.Update(tea.MouseMsg{X: m.X + 15, Y: m.Y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	if msg := result(t, cmd); msg.Label != "Discard" {
+		t.Fatalf("expected Discard to be clicked, got %+v", msg)
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	m := New("Delete 3 files?", WithDestructive())
+	golden.RequireEqual(t, []byte(RenderString(m, 30, 1)))
+}
@@ -0,0 +1,455 @@
+// Package datepicker provides a calendar date picker for Bubble Tea
+// applications. It renders a month grid with keyboard navigation, optional
+// minimum and maximum dates, a configurable first day of the week, localized
+// month and weekday names and an optional time-of-day spinner.
+package datepicker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/help"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
+)
+
+// cellWidth is the width of a day in the grid, excluding the gap between
+// days.
+const cellWidth = 2
+
+// SelectedMsg is sent when the user chooses a date. If the time spinner is
+// shown, the date includes the chosen time of day.
+type SelectedMsg struct {
+	Date time.Time
+}
+
+// Locale holds the names used to render the calendar.
+type Locale struct {
+	// Months are the full month names, starting with January.
+	Months [12]string
+
+	// Weekdays are abbreviated weekday names, starting with Sunday. They
+	// should be no wider than two cells.
+	Weekdays [7]string
+}
+
+// English is the default locale.
+var English = Locale{
+	Months: [12]string{
+		"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December",
+	},
+	Weekdays: [7]string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"},
+}
+
+// KeyMap is the key bindings for moving through the calendar and setting the
+// time. It satisfies the help.KeyMap interface.
+type KeyMap struct {
+	PrevDay     key.Binding
+	NextDay     key.Binding
+	PrevWeek    key.Binding
+	NextWeek    key.Binding
+	PrevMonth   key.Binding
+	NextMonth   key.Binding
+	PrevYear    key.Binding
+	NextYear    key.Binding
+	Today       key.Binding
+	ToggleFocus key.Binding
+	Select      key.Binding
+}
+
+// ShortHelp implements the KeyMap interface.
+func (km KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{km.PrevMonth, km.NextMonth, km.Today, km.ToggleFocus, km.Select}
+}
+
+// FullHelp implements the KeyMap interface.
+func (km KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{km.PrevDay, km.NextDay, km.PrevWeek, km.NextWeek},
+		{km.PrevMonth, km.NextMonth, km.PrevYear, km.NextYear},
+		{km.Today, km.ToggleFocus, km.Select},
+	}
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		PrevDay: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "previous day"),
+		),
+		NextDay: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "next day"),
+		),
+		PrevWeek: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "previous week"),
+		),
+		NextWeek: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "next week"),
+		),
+		PrevMonth: key.NewBinding(
+			key.WithKeys("pgup", "["),
+			key.WithHelp("[", "previous month"),
+		),
+		NextMonth: key.NewBinding(
+			key.WithKeys("pgdown", "]"),
+			key.WithHelp("]", "next month"),
+		),
+		PrevYear: key.NewBinding(
+			key.WithKeys("{"),
+			key.WithHelp("{", "previous year"),
+		),
+		NextYear: key.NewBinding(
+			key.WithKeys("}"),
+			key.WithHelp("}", "next year"),
+		),
+		Today: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "today"),
+		),
+		ToggleFocus: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "date/time"),
+			key.WithDisabled(),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "select"),
+		),
+	}
+}
+
+// Styles are the styles of the month grid, its days and the time spinner,
+// as returned by DefaultStyles by default.
+type Styles struct {
+	Header      lipgloss.Style
+	Weekday     lipgloss.Style
+	Day         lipgloss.Style
+	Today       lipgloss.Style
+	Cursor      lipgloss.Style
+	Disabled    lipgloss.Style
+	Time        lipgloss.Style
+	TimeFocused lipgloss.Style
+	HelpSection lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this
+// component.
+func DefaultStyles() Styles {
+	accent := lipgloss.AdaptiveColor{Light: "#F793FF", Dark: "#AD58B4"}
+	subdued := lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}
+
+	return Styles{
+		Header:      lipgloss.NewStyle().Bold(true),
+		Weekday:     lipgloss.NewStyle().Foreground(subdued),
+		Day:         lipgloss.NewStyle(),
+		Today:       lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#00A66D", Dark: "#04B575"}).Bold(true),
+		Cursor:      lipgloss.NewStyle().Background(accent).Foreground(lipgloss.Color("#FFFFFF")),
+		Disabled:    lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#DDDADA", Dark: "#3C3C3C"}),
+		Time:        lipgloss.NewStyle(),
+		TimeFocused: lipgloss.NewStyle().Background(accent).Foreground(lipgloss.Color("#FFFFFF")),
+		HelpSection: lipgloss.NewStyle().PaddingTop(1),
+	}
+}
+
+// timeField is the part of the time spinner which has focus.
+type timeField int
+
+const (
+	noTime timeField = iota
+	hourField
+	minuteField
+)
+
+// Model is the state of a date picker.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+	Help   help.Model
+	Locale Locale
+
+	// ShowHelp determines whether the help view is rendered beneath the
+	// calendar.
+	ShowHelp bool
+
+	// WeekStart is the first day of the week.
+	WeekStart time.Weekday
+
+	// Min and Max are the earliest and latest dates which can be chosen. Zero
+	// values mean there's no limit.
+	Min, Max time.Time
+
+	// MinuteStep is how far the minute spinner moves at a time.
+	MinuteStep int
+
+	date     time.Time
+	today    time.Time
+	showTime bool
+	hour     int
+	minute   int
+	focus    timeField
+}
+
+// New returns a date picker with the cursor on today's date.
+func New() Model {
+	now := time.Now()
+	m := Model{
+		KeyMap:     DefaultKeyMap(),
+		Styles:     DefaultStyles(),
+		Help:       help.New(),
+		Locale:     English,
+		ShowHelp:   true,
+		WeekStart:  time.Sunday,
+		MinuteStep: 5, //nolint:mnd
+		today:      truncateDay(now),
+	}
+	m.SetDate(now)
+	return m
+}
+
+// SetDate moves the cursor to the given date, and sets the time spinner to its
+// time of day. The date is clamped to Min and Max.
+func (m *Model) SetDate(t time.Time) {
+	m.date = m.clampDate(truncateDay(t))
+	m.hour = t.Hour()
+	m.minute = t.Minute()
+}
+
+// Date returns the date under the cursor. If the time spinner is shown the
+// date includes the chosen time of day, otherwise it's midnight.
+func (m Model) Date() time.Time {
+	if !m.showTime {
+		return m.date
+	}
+	return m.date.Add(time.Duration(m.hour)*time.Hour + time.Duration(m.minute)*time.Minute)
+}
+
+// SetShowTime sets whether the time-of-day spinner is shown.
+func (m *Model) SetShowTime(v bool) {
+	m.showTime = v
+	if !v {
+		m.focus = noTime
+	}
+	m.updateKeybindings()
+}
+
+// ShowTime returns whether the time-of-day spinner is shown.
+func (m Model) ShowTime() bool {
+	return m.showTime
+}
+
+// Selectable returns whether the given date is within Min and Max.
+func (m Model) Selectable(t time.Time) bool {
+	d := truncateDay(t)
+	if !m.Min.IsZero() && d.Before(truncateDay(m.Min)) {
+		return false
+	}
+	if !m.Max.IsZero() && d.After(truncateDay(m.Max)) {
+		return false
+	}
+	return true
+}
+
+func (m Model) clampDate(d time.Time) time.Time {
+	if !m.Min.IsZero() && d.Before(truncateDay(m.Min)) {
+		return truncateDay(m.Min.In(d.Location()))
+	}
+	if !m.Max.IsZero() && d.After(truncateDay(m.Max)) {
+		return truncateDay(m.Max.In(d.Location()))
+	}
+	return d
+}
+
+// addMonths moves the date by the given number of months, keeping the day of
+// the month where possible.
+func (m Model) addMonths(n int) time.Time {
+	y, mo, d := m.date.Date()
+	first := time.Date(y, mo+time.Month(n), 1, 0, 0, 0, 0, m.date.Location())
+	return first.AddDate(0, 0, min(d, daysIn(first))-1)
+}
+
+func (m *Model) updateKeybindings() {
+	m.KeyMap.ToggleFocus.SetEnabled(m.showTime)
+}
+
+// Init exists to satisfy the tea.Model interface.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.KeyMap.Select):
+		if !m.Selectable(m.date) {
+			return m, nil
+		}
+		date := m.Date()
+		return m, func() tea.Msg {
+			return SelectedMsg{Date: date}
+		}
+	case key.Matches(keyMsg, m.KeyMap.ToggleFocus):
+		m.focus = (m.focus + 1) % (minuteField + 1)
+		return m, nil
+	case m.focus != noTime:
+		m.updateTime(keyMsg)
+		return m, nil
+	}
+
+	next := m.date
+	switch {
+	case key.Matches(keyMsg, m.KeyMap.PrevDay):
+		next = m.date.AddDate(0, 0, -1)
+	case key.Matches(keyMsg, m.KeyMap.NextDay):
+		next = m.date.AddDate(0, 0, 1)
+	case key.Matches(keyMsg, m.KeyMap.PrevWeek):
+		next = m.date.AddDate(0, 0, -7) //nolint:mnd
+	case key.Matches(keyMsg, m.KeyMap.NextWeek):
+		next = m.date.AddDate(0, 0, 7) //nolint:mnd
+	case key.Matches(keyMsg, m.KeyMap.PrevMonth):
+		next = m.addMonths(-1)
+	case key.Matches(keyMsg, m.KeyMap.NextMonth):
+		next = m.addMonths(1)
+	case key.Matches(keyMsg, m.KeyMap.PrevYear):
+		next = m.addMonths(-12) //nolint:mnd
+	case key.Matches(keyMsg, m.KeyMap.NextYear):
+		next = m.addMonths(12) //nolint:mnd
+	case key.Matches(keyMsg, m.KeyMap.Today):
+		next = m.today
+	}
+	m.date = m.clampDate(next)
+
+	return m, nil
+}
+
+// updateTime handles keys while the time spinner has focus. The day keys move
+// between the hour and minute and the week keys change the focused value.
+func (m *Model) updateTime(msg tea.KeyMsg) {
+	step := 0
+	switch {
+	case key.Matches(msg, m.KeyMap.PrevDay):
+		m.focus = hourField
+	case key.Matches(msg, m.KeyMap.NextDay):
+		m.focus = minuteField
+	case key.Matches(msg, m.KeyMap.PrevWeek):
+		step = 1
+	case key.Matches(msg, m.KeyMap.NextWeek):
+		step = -1
+	}
+	if step == 0 {
+		return
+	}
+
+	if m.focus == hourField {
+		m.hour = (m.hour + step + 24) % 24 //nolint:mnd
+		return
+	}
+	s := max(1, m.MinuteStep)
+	// Snap to the step before moving, so 09:07 goes to 09:10 or 09:05.
+	if step > 0 {
+		m.minute = (m.minute/s*s + s) % 60 //nolint:mnd
+	} else {
+		m.minute = ((m.minute+s-1)/s*s - s + 60) % 60 //nolint:mnd
+	}
+}
+
+// View renders the component.
+func (m Model) View() string {
+	width := 7*(cellWidth+1) - 1 //nolint:mnd
+	y, mo, _ := m.date.Date()
+
+	var b strings.Builder
+	header := fmt.Sprintf("%s %d", m.Locale.Months[mo-1], y)
+	b.WriteString(m.Styles.Header.Render(lipgloss.PlaceHorizontal(width, lipgloss.Center, header)))
+	b.WriteString("\n")
+
+	names := make([]string, 7) //nolint:mnd
+	for i := range names {
+		wd := (int(m.WeekStart) + i) % 7 //nolint:mnd
+		names[i] = m.Styles.Weekday.Render(fmt.Sprintf("%*s", cellWidth, m.Locale.Weekdays[wd]))
+	}
+	b.WriteString(strings.Join(names, " "))
+
+	first := time.Date(y, mo, 1, 0, 0, 0, 0, m.date.Location())
+	lead := (int(first.Weekday()) - int(m.WeekStart) + 7) % 7 //nolint:mnd
+	cells := make([]string, 0, 42)                            //nolint:mnd
+	for range lead {
+		cells = append(cells, strings.Repeat(" ", cellWidth))
+	}
+	for d := first; d.Month() == mo; d = d.AddDate(0, 0, 1) {
+		cells = append(cells, m.dayView(d))
+	}
+	for i := 0; i < len(cells); i += 7 {
+		b.WriteString("\n")
+		b.WriteString(strings.Join(cells[i:min(i+7, len(cells))], " "))
+	}
+
+	if m.showTime {
+		b.WriteString("\n\n")
+		b.WriteString(m.timeView(width))
+	}
+
+	if m.ShowHelp {
+		m.Help.Width = width
+		b.WriteString("\n")
+		b.WriteString(m.Styles.HelpSection.Render(m.Help.View(m.KeyMap)))
+	}
+
+	return b.String()
+}
+
+// RenderString renders the date picker deterministically at the given size,
+// for golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	return render.View(m.View, width, height)
+}
+
+func (m Model) dayView(d time.Time) string {
+	s := fmt.Sprintf("%*d", cellWidth, d.Day())
+	switch {
+	case d.Equal(m.date):
+		return m.Styles.Cursor.Render(s)
+	case !m.Selectable(d):
+		return m.Styles.Disabled.Render(s)
+	case d.Equal(m.today):
+		return m.Styles.Today.Render(s)
+	default:
+		return m.Styles.Day.Render(s)
+	}
+}
+
+func (m Model) timeView(width int) string {
+	hour, minute := m.Styles.Time, m.Styles.Time
+	switch m.focus {
+	case hourField:
+		hour = m.Styles.TimeFocused
+	case minuteField:
+		minute = m.Styles.TimeFocused
+	case noTime:
+	}
+	t := hour.Render(fmt.Sprintf("%02d", m.hour)) + ":" + minute.Render(fmt.Sprintf("%02d", m.minute))
+	return lipgloss.PlaceHorizontal(width, lipgloss.Center, t)
+}
+
+// truncateDay returns midnight at the start of t's day, in t's location.
+func truncateDay(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, t.Location())
+}
+
+// daysIn returns the number of days in t's month.
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+}
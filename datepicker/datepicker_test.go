@@ -0,0 +1,123 @@
+package datepicker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+func keyPress(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestNavigation(t *testing.T) {
+	m := New()
+	m.SetDate(date(2024, time.January, 31))
+
+	for _, tc := range []struct {
+		msg  tea.KeyMsg
+		want time.Time
+	}{
+		{keyPress("]"), date(2024, time.February, 29)},
+		{keyPress("l"), date(2024, time.March, 1)},
+		{keyPress("k"), date(2024, time.February, 23)},
+		{keyPress("}"), date(2025, time.February, 23)},
+		{keyPress("["), date(2025, time.January, 23)},
+	} {
+		m, _ = m.Update(tc.msg)
+		if !m.Date().Equal(tc.want) {
+			t.Fatalf("after %q: expected %s, got %s", tc.msg, tc.want, m.Date())
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	m := New()
+	m.Min = date(2024, time.March, 10)
+	m.Max = date(2024, time.March, 20)
+	m.SetDate(date(2024, time.March, 1))
+
+	if want := m.Min; !m.Date().Equal(want) {
+		t.Fatalf("expected date to be clamped to %s, got %s", want, m.Date())
+	}
+
+	m, _ = m.Update(keyPress("]"))
+	if want := m.Max; !m.Date().Equal(want) {
+		t.Fatalf("expected date to be clamped to %s, got %s", want, m.Date())
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if msg, ok := cmd().(SelectedMsg); !ok || !msg.Date.Equal(m.Max) {
+		t.Fatalf("expected SelectedMsg for %s, got %#v", m.Max, cmd())
+	}
+}
+
+func TestTime(t *testing.T) {
+	m := New()
+	m.SetShowTime(true)
+	m.SetDate(time.Date(2024, time.March, 1, 9, 7, 0, 0, time.UTC))
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m, _ = m.Update(keyPress("k"))
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m, _ = m.Update(keyPress("k"))
+
+	if want := time.Date(2024, time.March, 1, 10, 10, 0, 0, time.UTC); !m.Date().Equal(want) {
+		t.Fatalf("expected %s, got %s", want, m.Date())
+	}
+
+	m, _ = m.Update(keyPress("j"))
+	m, _ = m.Update(keyPress("j"))
+	if got := m.Date().Minute(); got != 0 {
+		t.Fatalf("expected minute to step down to 0, got %d", got)
+	}
+}
+
+func TestView(t *testing.T) {
+	m := New()
+	m.ShowHelp = false
+	m.WeekStart = time.Monday
+	m.SetDate(date(2024, time.September, 12))
+
+	want := strings.Join([]string{
+		"   September 2024",
+		"Mo Tu We Th Fr Sa Su",
+		"                   1",
+		" 2  3  4  5  6  7  8",
+		" 9 10 11 12 13 14 15",
+		"16 17 18 19 20 21 22",
+		"23 24 25 26 27 28 29",
+		"30",
+	}, "\n")
+	got := ansi.Strip(m.View())
+	lines := strings.Split(got, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	if got := strings.Join(lines, "\n"); got != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	t.Run("month", func(t *testing.T) {
+		m := New()
+		m.SetDate(date(2024, time.February, 14))
+		golden.RequireEqual(t, []byte(RenderString(m, 20, 10)))
+	})
+	t.Run("time", func(t *testing.T) {
+		m := New()
+		m.ShowHelp = false
+		m.SetShowTime(true)
+		m.SetDate(time.Date(2024, time.February, 14, 9, 30, 0, 0, time.UTC))
+		golden.RequireEqual(t, []byte(RenderString(m, 20, 10)))
+	})
+}
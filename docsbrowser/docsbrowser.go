@@ -0,0 +1,670 @@
+// Package docsbrowser provides a frame for browsing in-app documentation in
+// Bubble Tea applications. It pairs an outline of sections with a content
+// pane showing them as Markdown, and supports searching across sections along
+// with back and forward history navigation.
+package docsbrowser
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mikeflynn/bubbles/help"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/markdownview"
+	"github.com/mikeflynn/bubbles/render"
+	"github.com/mikeflynn/bubbles/textinput"
+	"github.com/mikeflynn/bubbles/textutil"
+)
+
+// Section is a single entry in the outline.
+type Section struct {
+	// Title is shown in the outline.
+	Title string
+
+	// Level is the nesting depth of the section, starting at zero. Nested
+	// sections are indented in the outline.
+	Level int
+
+	// Content is the body of the section, in Markdown. It's rendered by the
+	// model's Renderer instead, if set.
+	Content string
+}
+
+// RenderFunc renders the content of a section at the given width. It can be
+// used to plug in another Markdown renderer, syntax highlighting and so on.
+type RenderFunc func(content string, width int) string
+
+// NavigateMsg opens the section at the given index, recording it in the
+// history.
+type NavigateMsg struct {
+	Index int
+}
+
+// BackMsg navigates to the previous section in the history.
+type BackMsg struct{}
+
+// ForwardMsg navigates to the next section in the history.
+type ForwardMsg struct{}
+
+// SectionChangedMsg is sent whenever a different section is opened.
+type SectionChangedMsg struct {
+	Index int
+	Title string
+}
+
+type focus int
+
+const (
+	focusOutline focus = iota
+	focusContent
+)
+
+// KeyMap is the key bindings for moving through the outline, searching and
+// going back and forward. It satisfies the help.KeyMap interface.
+type KeyMap struct {
+	// Outline navigation.
+	CursorUp   key.Binding
+	CursorDown key.Binding
+	Open       key.Binding
+
+	// Switches focus between the outline and the content pane.
+	SwitchFocus key.Binding
+
+	// History.
+	Back    key.Binding
+	Forward key.Binding
+
+	// Search.
+	Search       key.Binding
+	AcceptSearch key.Binding
+	CancelSearch key.Binding
+	NextMatch    key.Binding
+	PrevMatch    key.Binding
+}
+
+// ShortHelp implements the KeyMap interface.
+func (km KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		km.CursorUp, km.CursorDown, km.Open, km.SwitchFocus,
+		km.Back, km.Forward, km.Search, km.AcceptSearch, km.CancelSearch,
+	}
+}
+
+// FullHelp implements the KeyMap interface.
+func (km KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{km.CursorUp, km.CursorDown, km.Open, km.SwitchFocus},
+		{km.Back, km.Forward},
+		{km.Search, km.AcceptSearch, km.CancelSearch, km.NextMatch, km.PrevMatch},
+	}
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		CursorUp: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		CursorDown: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Open: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "open"),
+		),
+		SwitchFocus: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "switch pane"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("alt+left", "backspace"),
+			key.WithHelp("⌫", "back"),
+		),
+		Forward: key.NewBinding(
+			key.WithKeys("alt+right"),
+			key.WithHelp("alt+→", "forward"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "search"),
+		),
+		AcceptSearch: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "go to match"),
+			key.WithDisabled(),
+		),
+		CancelSearch: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+			key.WithDisabled(),
+		),
+		NextMatch: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next match"),
+			key.WithDisabled(),
+		),
+		PrevMatch: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "prev match"),
+			key.WithDisabled(),
+		),
+	}
+}
+
+// Styles are the styles of the outline, the content pane and the status
+// line. DefaultStyles returns the defaults.
+type Styles struct {
+	Pane         lipgloss.Style
+	ActivePane   lipgloss.Style
+	Item         lipgloss.Style
+	SelectedItem lipgloss.Style
+	CurrentItem  lipgloss.Style
+	MatchItem    lipgloss.Style
+	Status       lipgloss.Style
+	HelpSection  lipgloss.Style
+
+	// Content styles the Markdown of the open section.
+	Content markdownview.Styles
+}
+
+// DefaultStyles returns a set of default style definitions for this
+// component.
+func DefaultStyles() Styles {
+	border := lipgloss.AdaptiveColor{Light: "#DDDADA", Dark: "#3C3C3C"}
+	active := lipgloss.AdaptiveColor{Light: "#F793FF", Dark: "#AD58B4"}
+
+	return Styles{
+		Pane:         lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(border),
+		ActivePane:   lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(active),
+		Item:         lipgloss.NewStyle(),
+		SelectedItem: lipgloss.NewStyle().Foreground(active).Bold(true),
+		CurrentItem:  lipgloss.NewStyle().Underline(true),
+		MatchItem:    lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#00A66D", Dark: "#04B575"}),
+		Status:       lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}),
+		HelpSection:  lipgloss.NewStyle().PaddingTop(1),
+		Content:      markdownview.DefaultStyles(),
+	}
+}
+
+// Model is the state of a documentation browser.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+	Help   help.Model
+
+	// ShowHelp determines whether the help view is rendered beneath the
+	// panes.
+	ShowHelp bool
+
+	// OutlineWidth is the width of the outline pane, including its frame.
+	OutlineWidth int
+
+	// Renderer renders section content for the content pane. When nil the
+	// content is rendered as Markdown with the Content styles.
+	Renderer RenderFunc
+
+	// Indent is the string used to indent nested sections in the outline.
+	Indent string
+
+	sections []Section
+	cursor   int
+	current  int
+	focus    focus
+
+	back    []int
+	forward []int
+
+	searching bool
+	search    textinput.Model
+	matches   []int
+	match     int
+
+	width  int
+	height int
+
+	content markdownview.Model
+}
+
+// New returns a model for the given sections.
+func New(sections []Section) Model {
+	search := textinput.New()
+	search.Prompt = "/"
+
+	m := Model{
+		KeyMap:       DefaultKeyMap(),
+		Styles:       DefaultStyles(),
+		Help:         help.New(),
+		ShowHelp:     true,
+		OutlineWidth: 30, //nolint:mnd
+		Indent:       "  ",
+		search:       search,
+		content:      markdownview.New(0, 0),
+	}
+	m.SetSections(sections)
+	return m
+}
+
+// SetSections replaces the sections, resetting the history and any search.
+func (m *Model) SetSections(sections []Section) {
+	m.sections = sections
+	m.cursor = 0
+	m.current = 0
+	m.back = nil
+	m.forward = nil
+	m.resetSearch()
+	m.updateContent()
+	m.content.GotoTop()
+}
+
+// Sections returns the sections.
+func (m Model) Sections() []Section {
+	return m.sections
+}
+
+// Current returns the index of the open section.
+func (m Model) Current() int {
+	return m.current
+}
+
+// Cursor returns the index of the section under the outline cursor.
+func (m Model) Cursor() int {
+	return m.cursor
+}
+
+// Open opens the section at the given index and records the previously open
+// section in the history. It returns whether a different section was opened.
+func (m *Model) Open(i int) bool {
+	if i < 0 || i >= len(m.sections) || i == m.current {
+		return false
+	}
+	m.back = append(m.back, m.current)
+	m.forward = nil
+	m.show(i)
+	return true
+}
+
+// Back opens the previous section in the history. It returns false if there's
+// nothing to go back to.
+func (m *Model) Back() bool {
+	if len(m.back) == 0 {
+		return false
+	}
+	i := m.back[len(m.back)-1]
+	m.back = m.back[:len(m.back)-1]
+	m.forward = append(m.forward, m.current)
+	m.show(i)
+	return true
+}
+
+// Forward opens the next section in the history. It returns false if there's
+// nothing to go forward to.
+func (m *Model) Forward() bool {
+	if len(m.forward) == 0 {
+		return false
+	}
+	i := m.forward[len(m.forward)-1]
+	m.forward = m.forward[:len(m.forward)-1]
+	m.back = append(m.back, m.current)
+	m.show(i)
+	return true
+}
+
+// CanGoBack returns whether there's a previous section in the history.
+func (m Model) CanGoBack() bool {
+	return len(m.back) > 0
+}
+
+// CanGoForward returns whether there's a next section in the history.
+func (m Model) CanGoForward() bool {
+	return len(m.forward) > 0
+}
+
+func (m *Model) show(i int) {
+	m.current = i
+	m.cursor = i
+	m.updateContent()
+	m.content.GotoTop()
+	m.scrollToMatch()
+}
+
+// Searching returns whether the search input is active.
+func (m Model) Searching() bool {
+	return m.searching
+}
+
+// Query returns the current search query.
+func (m Model) Query() string {
+	return m.search.Value()
+}
+
+// SetQuery sets the search query and updates the matching sections.
+func (m *Model) SetQuery(q string) {
+	m.search.SetValue(q)
+	m.updateMatches()
+}
+
+// Matches returns the indices of the sections matching the search query.
+func (m Model) Matches() []int {
+	return m.matches
+}
+
+func (m *Model) updateMatches() {
+	m.matches = nil
+	m.match = 0
+
+	q := strings.ToLower(m.search.Value())
+	if q == "" {
+		return
+	}
+	for i, s := range m.sections {
+		if strings.Contains(strings.ToLower(s.Title), q) ||
+			strings.Contains(strings.ToLower(s.Content), q) {
+			m.matches = append(m.matches, i)
+		}
+	}
+	if len(m.matches) > 0 {
+		m.cursor = m.matches[0]
+	}
+}
+
+func (m *Model) resetSearch() {
+	m.searching = false
+	m.search.Reset()
+	m.search.Blur()
+	m.matches = nil
+	m.match = 0
+	m.updateKeybindings()
+}
+
+// scrollToMatch scrolls the content pane to the first line containing the
+// search query, if any.
+func (m *Model) scrollToMatch() {
+	q := strings.ToLower(m.search.Value())
+	if q == "" || len(m.matches) == 0 {
+		return
+	}
+	for i, l := range strings.Split(m.content.Rendered(), "\n") {
+		if strings.Contains(strings.ToLower(ansi.Strip(l)), q) {
+			m.content.SetYOffset(i)
+			return
+		}
+	}
+}
+
+// SetSize sets the dimensions of the component.
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.Help.Width = width
+	m.updateContent()
+}
+
+// Width returns the width of the component.
+func (m Model) Width() int {
+	return m.width
+}
+
+// Height returns the height of the component.
+func (m Model) Height() int {
+	return m.height
+}
+
+func (m *Model) updateKeybindings() {
+	hasMatches := !m.searching && len(m.matches) > 0
+
+	m.KeyMap.AcceptSearch.SetEnabled(m.searching)
+	m.KeyMap.CancelSearch.SetEnabled(m.searching || hasMatches)
+	m.KeyMap.NextMatch.SetEnabled(hasMatches)
+	m.KeyMap.PrevMatch.SetEnabled(hasMatches)
+
+	m.KeyMap.CursorUp.SetEnabled(!m.searching && m.focus == focusOutline)
+	m.KeyMap.CursorDown.SetEnabled(!m.searching && m.focus == focusOutline)
+	m.KeyMap.Open.SetEnabled(!m.searching && m.focus == focusOutline)
+	m.KeyMap.SwitchFocus.SetEnabled(!m.searching)
+	m.KeyMap.Back.SetEnabled(!m.searching)
+	m.KeyMap.Forward.SetEnabled(!m.searching)
+	m.KeyMap.Search.SetEnabled(!m.searching)
+}
+
+// paneHeight returns the inner height of the panes.
+func (m Model) paneHeight() int {
+	h := m.height - m.Styles.Pane.GetVerticalFrameSize() - 1 // status line
+	if m.ShowHelp {
+		h -= lipgloss.Height(m.helpView())
+	}
+	return max(0, h)
+}
+
+// updateContent syncs the content pane with the open section and the
+// component's dimensions, keeping its scroll position.
+func (m *Model) updateContent() {
+	frameW := m.Styles.Pane.GetHorizontalFrameSize()
+	m.content.Styles = m.Styles.Content
+	m.content.Renderer = markdownview.RenderFunc(m.Renderer)
+	m.content.SetSize(max(0, m.width-m.OutlineWidth-frameW), m.paneHeight())
+	m.search.Width = max(0, m.width-lipgloss.Width(m.search.Prompt)-1)
+
+	if len(m.sections) == 0 {
+		m.content.SetContent("")
+		return
+	}
+
+	m.content.SetContent(m.sections[m.current].Content)
+}
+
+// Init exists to satisfy the tea.Model interface.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case NavigateMsg:
+		return m, m.changed(m.Open(msg.Index))
+	case BackMsg:
+		return m, m.changed(m.Back())
+	case ForwardMsg:
+		return m, m.changed(m.Forward())
+	}
+
+	if m.searching {
+		return m.handleSearching(msg)
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		if m.focus == focusContent {
+			var cmd tea.Cmd
+			m.content, cmd = m.content.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.KeyMap.SwitchFocus):
+		if m.focus == focusOutline {
+			m.focus = focusContent
+		} else {
+			m.focus = focusOutline
+		}
+		m.updateKeybindings()
+		return m, nil
+	case key.Matches(keyMsg, m.KeyMap.Back):
+		return m, m.changed(m.Back())
+	case key.Matches(keyMsg, m.KeyMap.Forward):
+		return m, m.changed(m.Forward())
+	case key.Matches(keyMsg, m.KeyMap.Search):
+		m.searching = true
+		m.updateKeybindings()
+		return m, m.search.Focus()
+	case key.Matches(keyMsg, m.KeyMap.CancelSearch):
+		m.resetSearch()
+		return m, nil
+	case key.Matches(keyMsg, m.KeyMap.NextMatch):
+		return m, m.cycleMatch(1)
+	case key.Matches(keyMsg, m.KeyMap.PrevMatch):
+		return m, m.cycleMatch(-1)
+	case key.Matches(keyMsg, m.KeyMap.CursorUp):
+		m.cursor = clamp(m.cursor-1, 0, max(0, len(m.sections)-1))
+		return m, nil
+	case key.Matches(keyMsg, m.KeyMap.CursorDown):
+		m.cursor = clamp(m.cursor+1, 0, max(0, len(m.sections)-1))
+		return m, nil
+	case key.Matches(keyMsg, m.KeyMap.Open):
+		return m, m.changed(m.Open(m.cursor))
+	}
+
+	if m.focus == focusContent {
+		var cmd tea.Cmd
+		m.content, cmd = m.content.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m Model) handleSearching(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.KeyMap.CancelSearch):
+			m.resetSearch()
+			m.cursor = m.current
+			return m, nil
+		case key.Matches(msg, m.KeyMap.AcceptSearch):
+			m.searching = false
+			m.search.Blur()
+			if len(m.matches) == 0 {
+				m.resetSearch()
+				return m, nil
+			}
+			m.updateKeybindings()
+			opened := m.Open(m.matches[m.match])
+			if !opened {
+				m.scrollToMatch()
+			}
+			return m, m.changed(opened)
+		}
+	}
+
+	var cmd tea.Cmd
+	prev := m.search.Value()
+	m.search, cmd = m.search.Update(msg)
+	if m.search.Value() != prev {
+		m.updateMatches()
+	}
+	return m, cmd
+}
+
+// cycleMatch opens the next or previous search match, wrapping around.
+func (m *Model) cycleMatch(delta int) tea.Cmd {
+	if len(m.matches) == 0 {
+		return nil
+	}
+	m.match = (m.match + delta + len(m.matches)) % len(m.matches)
+	return m.changed(m.Open(m.matches[m.match]))
+}
+
+// changed returns a command announcing the open section, or nil if nothing
+// changed.
+func (m Model) changed(ok bool) tea.Cmd {
+	if !ok {
+		return nil
+	}
+	msg := SectionChangedMsg{Index: m.current, Title: m.sections[m.current].Title}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// View renders the component.
+func (m Model) View() string {
+	outlineStyle, contentStyle := m.Styles.Pane, m.Styles.Pane
+	if m.focus == focusOutline {
+		outlineStyle = m.Styles.ActivePane
+	} else {
+		contentStyle = m.Styles.ActivePane
+	}
+
+	outlineW := max(0, m.OutlineWidth-outlineStyle.GetHorizontalFrameSize())
+	panes := lipgloss.JoinHorizontal(lipgloss.Top,
+		outlineStyle.Width(outlineW).Height(m.paneHeight()).Render(m.outlineView(outlineW)),
+		contentStyle.Render(m.content.View()),
+	)
+
+	sections := []string{panes, m.statusView()}
+	if m.ShowHelp {
+		sections = append(sections, m.helpView())
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// RenderString renders the browser deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	m.SetSize(width, height)
+	return render.View(m.View, width, height)
+}
+
+func (m Model) outlineView(width int) string {
+	height := m.paneHeight()
+	if height == 0 || len(m.sections) == 0 {
+		return ""
+	}
+
+	matched := make(map[int]bool, len(m.matches))
+	for _, i := range m.matches {
+		matched[i] = true
+	}
+
+	// Keep the cursor in view.
+	start := max(0, m.cursor-height+1)
+	end := min(len(m.sections), start+height)
+
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		s := m.sections[i]
+		title := textutil.Truncate(strings.Repeat(m.Indent, s.Level)+s.Title, width, textutil.Ellipsis)
+
+		style := m.Styles.Item
+		switch {
+		case i == m.cursor:
+			style = m.Styles.SelectedItem
+		case matched[i]:
+			style = m.Styles.MatchItem
+		}
+		if i == m.current {
+			style = style.Inherit(m.Styles.CurrentItem)
+		}
+		lines = append(lines, style.Render(title))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) statusView() string {
+	if m.searching {
+		return m.search.View()
+	}
+	if len(m.sections) == 0 {
+		return m.Styles.Status.Render("No sections")
+	}
+
+	status := fmt.Sprintf("%s (%d/%d)", m.sections[m.current].Title, m.current+1, len(m.sections))
+	if q := m.search.Value(); q != "" {
+		status += fmt.Sprintf(" • %d matches for %q", len(m.matches), q)
+	}
+	return m.Styles.Status.Render(textutil.Truncate(status, m.width, textutil.Ellipsis))
+}
+
+func (m Model) helpView() string {
+	return m.Styles.HelpSection.Render(m.Help.View(m.KeyMap))
+}
+
+func clamp(v, low, high int) int {
+	return min(max(v, low), high)
+}
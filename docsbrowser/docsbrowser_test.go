@@ -0,0 +1,135 @@
+package docsbrowser
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+var testSections = []Section{
+	{Title: "Introduction", Content: "Welcome to the docs."},
+	{Title: "Installation", Content: "Run go get."},
+	{Title: "Configuration", Level: 1, Content: "Set the theme in the config file."},
+	{Title: "Themes", Level: 1, Content: "Pick a theme."},
+}
+
+func keyPress(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestHistory(t *testing.T) {
+	m := New(testSections)
+	m.SetSize(80, 20)
+
+	m, cmd := m.Update(NavigateMsg{Index: 2})
+	if m.Current() != 2 {
+		t.Fatalf("expected section 2 to be open, got %d", m.Current())
+	}
+	if cmd == nil {
+		t.Fatal("expected a command after navigating")
+	}
+	if msg, ok := cmd().(SectionChangedMsg); !ok || msg.Title != "Configuration" {
+		t.Fatalf("expected SectionChangedMsg for Configuration, got %#v", cmd())
+	}
+
+	m, _ = m.Update(NavigateMsg{Index: 3})
+	m, _ = m.Update(BackMsg{})
+	if m.Current() != 2 {
+		t.Fatalf("expected back to open section 2, got %d", m.Current())
+	}
+	m, _ = m.Update(BackMsg{})
+	if m.Current() != 0 {
+		t.Fatalf("expected back to open section 0, got %d", m.Current())
+	}
+	if m.CanGoBack() {
+		t.Fatal("expected history to be exhausted")
+	}
+	if _, cmd := m.Update(BackMsg{}); cmd != nil {
+		t.Fatal("expected no command when there's nothing to go back to")
+	}
+
+	m, _ = m.Update(ForwardMsg{})
+	if m.Current() != 2 {
+		t.Fatalf("expected forward to open section 2, got %d", m.Current())
+	}
+
+	// Opening a new section discards the forward history.
+	m, _ = m.Update(NavigateMsg{Index: 1})
+	if m.CanGoForward() {
+		t.Fatal("expected forward history to be cleared")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	m := New(testSections)
+	m.SetSize(80, 20)
+
+	m, _ = m.Update(keyPress("/"))
+	if !m.Searching() {
+		t.Fatal("expected to be searching")
+	}
+	for _, r := range "theme" {
+		m, _ = m.Update(keyPress(string(r)))
+	}
+
+	if got := m.Matches(); len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Fatalf("expected sections 2 and 3 to match, got %v", got)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.Searching() {
+		t.Fatal("expected search to be accepted")
+	}
+	if m.Current() != 2 {
+		t.Fatalf("expected first match to be opened, got %d", m.Current())
+	}
+
+	m, _ = m.Update(keyPress("n"))
+	if m.Current() != 3 {
+		t.Fatalf("expected next match to be opened, got %d", m.Current())
+	}
+	m, _ = m.Update(keyPress("n"))
+	if m.Current() != 2 {
+		t.Fatalf("expected matches to wrap around, got %d", m.Current())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if m.Query() != "" || len(m.Matches()) != 0 {
+		t.Fatal("expected search to be cleared")
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	t.Run("outline", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(New(testSections), 60, 10)))
+	})
+	t.Run("section", func(t *testing.T) {
+		m := New(testSections)
+		m.SetSize(60, 10)
+		m, _ = m.Update(NavigateMsg{Index: 2})
+		golden.RequireEqual(t, []byte(RenderString(m, 60, 10)))
+	})
+}
+
+func TestResizeKeepsScroll(t *testing.T) {
+	sections := []Section{
+		{Title: "Long", Content: strings.Repeat("Paragraph.\n\n", 50)},
+		{Title: "Short", Content: "Short."},
+	}
+	m := New(sections)
+	m.SetSize(80, 20)
+	m.content.SetYOffset(10)
+
+	m.SetSize(90, 24)
+	if m.content.YOffset() != 10 {
+		t.Fatalf("expected resizing to keep the scroll position, got %d", m.content.YOffset())
+	}
+
+	m.Open(1)
+	m.Back()
+	if m.content.YOffset() != 0 {
+		t.Fatalf("expected opening a section to scroll to its top, got %d", m.content.YOffset())
+	}
+}
@@ -0,0 +1,284 @@
+package filepicker
+
+import (
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/mikeflynn/bubbles/textutil"
+)
+
+// Column is a column of metadata shown after each entry's name. See
+// Model.Columns.
+type Column int
+
+// Metadata columns.
+const (
+	// ColumnPermissions shows the entry's mode, such as -rw-r--r--.
+	ColumnPermissions Column = iota
+
+	// ColumnSize shows the entry's size in human-readable units.
+	ColumnSize
+
+	// ColumnModTime shows when the entry was last modified, formatted with
+	// Model.TimeFormat.
+	ColumnModTime
+
+	// ColumnSymlinkTarget shows where a symlink points. It's blank for
+	// other entries.
+	ColumnSymlinkTarget
+)
+
+// SortOrder is the order entries are listed in. Directories are always
+// listed before files. See Model.SortBy.
+type SortOrder int
+
+// Sort orders.
+const (
+	// SortByName lists entries by name. It's the default.
+	SortByName SortOrder = iota
+
+	// SortBySize lists the largest entries first.
+	SortBySize
+
+	// SortByModTime lists the most recently modified entries first.
+	SortByModTime
+)
+
+// String returns the name of the sort order.
+func (o SortOrder) String() string {
+	switch o {
+	case SortBySize:
+		return "size"
+	case SortByModTime:
+		return "modified"
+	default:
+		return "name"
+	}
+}
+
+// next returns the sort order the Sort key switches to.
+func (o SortOrder) next() SortOrder {
+	return (o + 1) % (SortByModTime + 1)
+}
+
+const (
+	// DefaultTimeFormat is the layout modification times are shown in when
+	// Model.TimeFormat is empty.
+	DefaultTimeFormat = "Jan _2 15:04"
+
+	// minNameWidth is the narrowest names are squeezed to before columns are
+	// dropped.
+	minNameWidth = 12
+
+	symlinkTargetWidth = 24
+)
+
+// WithColumns sets the metadata columns shown after each entry's name, in
+// order. See Model.Columns.
+func WithColumns(cols ...Column) Option {
+	return func(m *Model) {
+		m.Columns = cols
+	}
+}
+
+// WithSort sets the order entries are listed in. See Model.SortBy.
+func WithSort(o SortOrder) Option {
+	return func(m *Model) {
+		m.SortBy = o
+	}
+}
+
+// SetSort sets the order entries are listed in and sorts the current ones,
+// keeping the selected entry selected.
+func (m *Model) SetSort(o SortOrder) {
+	m.SortBy = o
+	if len(m.files) == 0 {
+		return
+	}
+	name := m.files[m.selected].Name()
+	m.files = append([]fs.DirEntry(nil), m.files...)
+	m.sortEntries(m.files)
+	m.selectEntry(name)
+}
+
+// sortEntries sorts entries in the picker's SortBy order, directories first
+// and by name where they're otherwise equal.
+func (m Model) sortEntries(entries []fs.DirEntry) {
+	infos := make(map[string]fs.FileInfo, len(entries))
+	if m.SortBy != SortByName {
+		for _, e := range entries {
+			if info, err := e.Info(); err == nil {
+				infos[e.Name()] = info
+			}
+		}
+	}
+	less := func(a, b fs.DirEntry) (less, ok bool) {
+		ia, ib := infos[a.Name()], infos[b.Name()]
+		if ia == nil || ib == nil {
+			return false, false
+		}
+		switch m.SortBy {
+		case SortBySize:
+			return ia.Size() > ib.Size(), ia.Size() != ib.Size()
+		case SortByModTime:
+			return ia.ModTime().After(ib.ModTime()), !ia.ModTime().Equal(ib.ModTime())
+		}
+		return false, false
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.IsDir() != b.IsDir() {
+			return a.IsDir()
+		}
+		if less, ok := less(a, b); ok {
+			return less
+		}
+		return a.Name() < b.Name()
+	})
+}
+
+// WithWidth sets the width of the picker, which the columns are aligned to.
+func WithWidth(w int) Option {
+	return func(m *Model) {
+		m.SetWidth(w)
+	}
+}
+
+// SetWidth sets the width of the picker, which the columns are aligned to.
+func (m *Model) SetWidth(w int) {
+	m.Width = max(0, w)
+}
+
+// fittedColumns returns the columns which fit in the picker's width, leaving
+// names at least minNameWidth. Columns are dropped from the end.
+func (m Model) fittedColumns() []Column {
+	cols := m.Columns
+	if m.Width <= 0 {
+		return cols
+	}
+	for len(cols) > 0 && m.nameWidth(cols) < minNameWidth {
+		cols = cols[:len(cols)-1]
+	}
+	return cols
+}
+
+// nameWidth returns the width left for names beside the given columns.
+func (m Model) nameWidth(cols []Column) int {
+	w := m.Width - textutil.Width(m.Cursor) - 1
+	for _, c := range cols {
+		w -= m.columnWidth(c) + 1
+	}
+	return w
+}
+
+// columnStyle returns the style of a column, whose width, if set, is the
+// column's width.
+func (m Model) columnStyle(c Column) lipgloss.Style {
+	switch c {
+	case ColumnPermissions:
+		return m.Styles.Permission
+	case ColumnSize:
+		return m.Styles.FileSize
+	case ColumnModTime:
+		return m.Styles.ModTime
+	default:
+		return m.Styles.SymlinkTarget
+	}
+}
+
+func (m Model) columnWidth(c Column) int {
+	if w := m.columnStyle(c).GetWidth(); w > 0 {
+		return w
+	}
+	switch c {
+	case ColumnPermissions:
+		return len(fs.FileMode(0).String())
+	case ColumnSize:
+		return fileSizeWidth
+	case ColumnModTime:
+		// A date with two-digit fields is as wide as the layout gets.
+		return textutil.Width(time.Date(2000, 12, 31, 23, 59, 59, 0, time.Local).Format(m.timeFormat())) //nolint:mnd
+	default:
+		return symlinkTargetWidth
+	}
+}
+
+func (m Model) timeFormat() string {
+	if m.TimeFormat == "" {
+		return DefaultTimeFormat
+	}
+	return m.TimeFormat
+}
+
+// columnCell returns a column's value for an entry, fitted to the column's
+// width and aligned as its style is. info is nil if the entry has none, in
+// which case the cell is blank.
+func (m Model) columnCell(c Column, info fs.FileInfo, target string) string {
+	w := m.columnWidth(c)
+	var v string
+	switch c {
+	case ColumnPermissions:
+		if info != nil {
+			v = info.Mode().String()
+		}
+	case ColumnSize:
+		if info != nil {
+			v = strings.Replace(humanize.Bytes(uint64(info.Size())), " ", "", 1) //nolint:gosec
+		}
+	case ColumnModTime:
+		if info != nil {
+			v = info.ModTime().Format(m.timeFormat())
+		}
+	case ColumnSymlinkTarget:
+		if target != "" {
+			// The end of a path says the most about it.
+			v = "→ " + textutil.TruncateLeft(target, w-2, textutil.Ellipsis) //nolint:mnd
+		}
+	}
+	if m.columnStyle(c).GetAlignHorizontal() == lipgloss.Right {
+		return textutil.PadLeft(textutil.Truncate(v, w, ""), w)
+	}
+	return textutil.Fit(v, w, textutil.Ellipsis)
+}
+
+// columnsRowView renders an entry with its name followed by the metadata
+// columns, which are aligned to the right edge when the picker has a width.
+func (m Model) columnsRowView(i int, f fs.DirEntry, target string, disabled bool) string {
+	var info fs.FileInfo
+	if fi, err := f.Info(); err == nil {
+		info = fi
+	}
+	cols := m.fittedColumns()
+
+	name := f.Name()
+	var pad string
+	if m.Width > 0 {
+		w := m.nameWidth(cols)
+		name = textutil.Truncate(name, w, textutil.Ellipsis)
+		pad = strings.Repeat(" ", max(0, w-textutil.Width(name)))
+	}
+
+	if i == m.selected {
+		cursorStyle, style := m.Styles.Cursor, m.Styles.Selected
+		if disabled {
+			cursorStyle, style = m.Styles.DisabledSelected, m.Styles.DisabledSelected
+		}
+		row := " " + name + pad
+		for _, c := range cols {
+			row += " " + m.columnCell(c, info, target)
+		}
+		return cursorStyle.Render(m.Cursor) + style.Render(row)
+	}
+
+	var b strings.Builder
+	b.WriteString(m.Styles.Cursor.Render(strings.Repeat(" ", textutil.Width(m.Cursor))))
+	b.WriteString(" " + m.nameStyle(f, disabled).Render(name) + pad)
+	for _, c := range cols {
+		b.WriteString(" " + m.columnStyle(c).Render(m.columnCell(c, info, target)))
+	}
+	return b.String()
+}
@@ -0,0 +1,132 @@
+package filepicker
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// writeFileAt creates a file with the given content in dir, modified at t.
+func writeFileAt(t *testing.T, dir, name, content string, mod time.Time) {
+	t.Helper()
+	path := writeFile(t, dir, name, content)
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestColumns(t *testing.T) {
+	dir := t.TempDir()
+	mod := time.Date(2024, 3, 5, 9, 7, 0, 0, time.Local)
+	writeFileAt(t, dir, "a.txt", "hello", mod)
+	writeFileAt(t, dir, "b.txt", strings.Repeat("x", 2048), mod)
+
+	m := newPicker(t, dir, WithColumns(ColumnSize, ColumnModTime), WithWidth(40), WithHeight(5))
+	lines := strings.Split(strings.TrimRight(ansi.Strip(m.View()), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected two entries, got %q", lines)
+	}
+	for _, line := range lines {
+		if w := ansi.StringWidth(line); w != 40 {
+			t.Errorf("expected %q to fill the width, got %d", line, w)
+		}
+		if !strings.HasSuffix(line, "Mar  5 09:07") {
+			t.Errorf("expected %q to end with the modification time", line)
+		}
+	}
+	if !strings.Contains(lines[0], "5B") || !strings.Contains(lines[1], "2.0kB") {
+		t.Errorf("expected human-readable sizes, got %q", lines)
+	}
+
+	m.TimeFormat = time.DateOnly
+	if v := ansi.Strip(m.View()); !strings.Contains(v, "2024-03-05") {
+		t.Errorf("expected the time format to be used, got %q", v)
+	}
+}
+
+func TestColumnsDroppedWhenNarrow(t *testing.T) {
+	m := New()
+	m.Columns = []Column{ColumnPermissions, ColumnSize, ColumnModTime}
+
+	for _, tc := range []struct {
+		width int
+		want  []Column
+	}{
+		{0, m.Columns},
+		{80, m.Columns},
+		{40, []Column{ColumnPermissions, ColumnSize}},
+		{25, []Column{ColumnPermissions}},
+		{10, []Column{}},
+	} {
+		m.SetWidth(tc.width)
+		if got := m.fittedColumns(); !slices.Equal(got, tc.want) {
+			t.Errorf("width %d: expected columns %v, got %v", tc.width, tc.want, got)
+		}
+	}
+}
+
+func TestSymlinkTargetColumn(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "target.txt", "")
+	if err := os.Symlink(filepath.Join(dir, "target.txt"), filepath.Join(dir, "link")); err != nil {
+		t.Skipf("can't create symlinks: %v", err)
+	}
+
+	m := newPicker(t, dir, WithColumns(ColumnSymlinkTarget), WithHeight(5))
+	lines := strings.Split(ansi.Strip(m.View()), "\n")
+	if !strings.Contains(lines[0], "link") || !strings.Contains(lines[0], "→ ") || !strings.HasSuffix(strings.TrimSpace(lines[0]), "target.txt") {
+		t.Errorf("expected the link to show its target, got %q", lines[0])
+	}
+	if strings.Contains(lines[1], "→") {
+		t.Errorf("expected no target for a regular file, got %q", lines[1])
+	}
+}
+
+func TestSort(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeFileAt(t, dir, "a", "12", now.Add(-time.Hour))
+	writeFileAt(t, dir, "b", "1", now)
+	writeFileAt(t, dir, "c", "123", now.Add(-2*time.Hour))
+	if err := os.Mkdir(filepath.Join(dir, "z"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newPicker(t, dir)
+	if got := names(m); !slices.Equal(got, []string{"z", "a", "b", "c"}) {
+		t.Fatalf("expected directories first and then names, got %v", got)
+	}
+
+	// Switching order keeps the selected entry selected.
+	m.selected = 2
+	m, _ = m.Update(keyPress("s"))
+	if m.SortBy != SortBySize {
+		t.Fatalf("expected to sort by size, got %v", m.SortBy)
+	}
+	if got := names(m); !slices.Equal(got, []string{"z", "c", "a", "b"}) {
+		t.Fatalf("expected the largest files first, got %v", got)
+	}
+	if m.files[m.selected].Name() != "b" {
+		t.Fatalf("expected b to stay selected, got %s", m.files[m.selected].Name())
+	}
+
+	m, _ = m.Update(keyPress("s"))
+	if got := names(m); !slices.Equal(got, []string{"z", "b", "a", "c"}) {
+		t.Fatalf("expected the most recently modified files first, got %v", got)
+	}
+
+	// The order is kept when the directory is read again.
+	if got := names(read(t, m)); !slices.Equal(got, []string{"z", "b", "a", "c"}) {
+		t.Fatalf("expected the order to be kept when rereading, got %v", got)
+	}
+
+	m, _ = m.Update(keyPress("s"))
+	if m.SortBy != SortByName {
+		t.Fatalf("expected to cycle back to sorting by name, got %v", m.SortBy)
+	}
+}
@@ -3,11 +3,7 @@
 package filepicker
 
 import (
-	"fmt"
-	"os"
-	"path/filepath"
-	"sort"
-	"strconv"
+	"io/fs"
 	"strings"
 	"sync/atomic"
 
@@ -15,6 +11,9 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dustin/go-humanize"
 	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
+	"github.com/mikeflynn/bubbles/textinput"
+	"github.com/mikeflynn/bubbles/textutil"
 )
 
 var lastID int64
@@ -29,7 +28,6 @@ func New() Model {
 		id:               nextID(),
 		CurrentDirectory: ".",
 		Cursor:           ">",
-		AllowedTypes:     []string{},
 		selected:         0,
 		ShowPermissions:  true,
 		ShowSize:         true,
@@ -45,6 +43,8 @@ func New() Model {
 		maxStack:         newStack(),
 		KeyMap:           DefaultKeyMap(),
 		Styles:           DefaultStyles(),
+		watch:            &dirWatch{},
+		Bookmarks:        DefaultBookmarks(),
 	}
 }
 
@@ -54,7 +54,7 @@ type errorMsg struct {
 
 type readDirMsg struct {
 	id      int
-	entries []os.DirEntry
+	entries []fs.DirEntry
 }
 
 const (
@@ -74,6 +74,27 @@ type KeyMap struct {
 	Back     key.Binding
 	Open     key.Binding
 	Select   key.Binding
+
+	// Deleting, which is disabled by default. See Model.SetDeleteEnabled.
+	Delete  key.Binding
+	Confirm key.Binding
+	Cancel  key.Binding
+
+	// Creating directories and renaming files, which are disabled by
+	// default. See Model.AllowMutations.
+	NewDirectory key.Binding
+	Rename       key.Binding
+	AcceptInput  key.Binding
+	CancelInput  key.Binding
+
+	// Showing the bookmarks and recent directories, and pinning the current
+	// directory to the bookmarks. See Model.Bookmarks.
+	Locations key.Binding
+	Pin       key.Binding
+
+	// Switching between sorting by name, size and modification time. See
+	// Model.SortBy.
+	Sort key.Binding
 }
 
 // DefaultKeyMap defines the default keybindings.
@@ -88,6 +109,19 @@ func DefaultKeyMap() KeyMap {
 		Back:     key.NewBinding(key.WithKeys("h", "backspace", "left", "esc"), key.WithHelp("h", "back")),
 		Open:     key.NewBinding(key.WithKeys("l", "right", "enter"), key.WithHelp("l", "open")),
 		Select:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")),
+		Delete:   key.NewBinding(key.WithKeys("d", "delete"), key.WithHelp("d", "delete"), key.WithDisabled()),
+		Confirm:  key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "confirm")),
+		Cancel:   key.NewBinding(key.WithKeys("n", "esc"), key.WithHelp("n", "cancel")),
+
+		NewDirectory: key.NewBinding(key.WithKeys("N"), key.WithHelp("N", "new directory"), key.WithDisabled()),
+		Rename:       key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "rename"), key.WithDisabled()),
+		AcceptInput:  key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "accept")),
+		CancelInput:  key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+
+		Locations: key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "bookmarks")),
+		Pin:       key.NewBinding(key.WithKeys("B"), key.WithHelp("B", "pin directory")),
+
+		Sort: key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "sort")),
 	}
 }
 
@@ -103,7 +137,18 @@ type Styles struct {
 	Selected         lipgloss.Style
 	DisabledSelected lipgloss.Style
 	FileSize         lipgloss.Style
+	ModTime          lipgloss.Style
+	SymlinkTarget    lipgloss.Style
 	EmptyDirectory   lipgloss.Style
+	DeletePrompt     lipgloss.Style
+	Prompt           lipgloss.Style
+	Error            lipgloss.Style
+	LocationHeader   lipgloss.Style
+	LocationPath     lipgloss.Style
+
+	// Rejection is used for the reason the entry the user tried to select
+	// can't be. See Model.Rules.
+	Rejection lipgloss.Style
 }
 
 // DefaultStyles defines the default styling for the file picker.
@@ -125,7 +170,15 @@ func DefaultStylesWithRenderer(r *lipgloss.Renderer) Styles {
 		Permission:       r.NewStyle().Foreground(lipgloss.Color("244")),
 		Selected:         r.NewStyle().Foreground(lipgloss.Color("212")).Bold(true),
 		FileSize:         r.NewStyle().Foreground(lipgloss.Color("240")).Width(fileSizeWidth).Align(lipgloss.Right),
+		ModTime:          r.NewStyle().Foreground(lipgloss.Color("240")),
+		SymlinkTarget:    r.NewStyle().Foreground(lipgloss.Color("36")),
 		EmptyDirectory:   r.NewStyle().Foreground(lipgloss.Color("240")).PaddingLeft(paddingLeft).SetString("Bummer. No Files Found."),
+		DeletePrompt:     r.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#FF4672", Dark: "#ED567A"}).PaddingLeft(paddingLeft),
+		Prompt:           r.NewStyle().Foreground(lipgloss.Color("212")).PaddingLeft(paddingLeft),
+		Error:            r.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#FF4672", Dark: "#ED567A"}).PaddingLeft(paddingLeft),
+		LocationHeader:   r.NewStyle().Foreground(lipgloss.Color("244")).Bold(true),
+		LocationPath:     r.NewStyle().Foreground(lipgloss.Color("240")),
+		Rejection:        r.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#FF4672", Dark: "#ED567A"}).PaddingLeft(paddingLeft),
 	}
 }
 
@@ -139,18 +192,59 @@ type Model struct {
 	// CurrentDirectory is the directory that the user is currently in.
 	CurrentDirectory string
 
+	// FS, if set, is browsed instead of the OS filesystem, such as a zip
+	// archive or an embed.FS. CurrentDirectory and the selected Path are
+	// then slash-separated paths within it, starting at ".". Permissions and
+	// sizes are left blank for entries whose info the FS can't provide.
+	FS fs.FS
+
 	// AllowedTypes specifies which file types the user may select.
 	// If empty the user may select any file.
+	//
+	// Deprecated: use Rules with Extensions instead.
 	AllowedTypes []string
 
+	// Rules decide which of the entries allowed by DirAllowed and
+	// FileAllowed can be selected, in order, such as FilesOnly, MaxSize and
+	// Extensions. Files they reject are shown disabled. Trying to select an
+	// entry they reject shows why it can't be until the next keypress,
+	// though directories they reject can still be opened.
+	Rules []Rule
+
+	// CanSelect, if set, reports whether an entry can be selected, once
+	// Rules have let it be.
+	CanSelect func(path string, info fs.FileInfo) bool
+
 	KeyMap          KeyMap
-	files           []os.DirEntry
+	files           []fs.DirEntry
 	ShowPermissions bool
 	ShowSize        bool
 	ShowHidden      bool
 	DirAllowed      bool
 	FileAllowed     bool
 
+	// Columns, if set, are metadata columns shown after each entry's name,
+	// in order, in place of the permissions and size shown before it with
+	// ShowPermissions and ShowSize and of the symlink target after it. When
+	// the picker has a Width they're aligned to its right edge, and dropped
+	// from the last one when names would otherwise be squeezed too narrow.
+	// Their widths are those of their styles, if set.
+	Columns []Column
+
+	// SortBy is the order entries are listed in, directories first. The
+	// Sort key switches between the orders. Use SetSort to change it once
+	// entries are shown.
+	SortBy SortOrder
+
+	// TimeFormat is the layout of modification times, as for time.Format.
+	// DefaultTimeFormat is used if it's empty.
+	TimeFormat string
+
+	// Width of the picker, which Columns are aligned to. If zero, they
+	// follow the names without being aligned. It follows the window's width
+	// when AutoHeight is set.
+	Width int
+
 	FileSelected  string
 	selected      int
 	selectedStack stack
@@ -168,6 +262,64 @@ type Model struct {
 
 	Cursor string
 	Styles Styles
+
+	// Trasher moves deleted files to the trash. If nil, DefaultTrasher is
+	// used.
+	Trasher Trasher
+
+	// AllowMutations lets the user create directories, rename files and
+	// delete them, enabling the NewDirectory, Rename and Delete keybindings.
+	// A CreatedDirMsg, RenamedMsg or DeletedMsg is sent after each change,
+	// so that other state can be refreshed. Files can't be changed when
+	// browsing an FS.
+	AllowMutations bool
+
+	// The name being typed for a new directory or a file being renamed, and
+	// the path of the file being renamed.
+	prompt     promptKind
+	promptPath string
+	input      textinput.Model
+
+	// The error of the last failed change, shown until the next keypress.
+	mutationErr error
+
+	// Why the entry the user tried to select can't be, shown until the next
+	// keypress.
+	rejection error
+
+	// The name of the entry to select once the directory's been reread.
+	selectName string
+
+	// A deletion awaiting confirmation. confirmErr is why the file couldn't
+	// be moved to the trash when offering to delete it permanently.
+	confirm     confirmKind
+	confirmPath string
+	confirmErr  error
+
+	// Watch rereads the current directory when files are created, removed
+	// or renamed in it by other programs, keeping the selected entry
+	// selected, and sends a DirChangedMsg. It takes effect in Init, and the
+	// watch is stopped with Close. An FS can't be watched.
+	Watch bool
+	watch *dirWatch
+
+	// Bookmarks are the directories listed first in the locations list,
+	// shown with the Locations key, followed by the directories the user
+	// pinned with the Pin key and those visited recently. They're
+	// DefaultBookmarks by default. Entries which don't exist when the list
+	// is opened are left out.
+	Bookmarks []Bookmark
+
+	// MaxRecent is the number of recent directories remembered.
+	// DefaultMaxRecent is used if it's zero.
+	MaxRecent int
+
+	pinned          []string
+	recent          []string
+	showLocations   bool
+	locationCursor  int
+	locations       []location
+	markedLocations int
 }
 
 type stack struct {
@@ -205,26 +357,20 @@ func (m *Model) popView() (int, int, int) {
 
 func (m Model) readDir(path string, showHidden bool) tea.Cmd {
 	return func() tea.Msg {
-		dirEntries, err := os.ReadDir(path)
+		dirEntries, err := m.readDirEntries(path)
 		if err != nil {
 			return errorMsg{err}
 		}
 
-		sort.Slice(dirEntries, func(i, j int) bool {
-			if dirEntries[i].IsDir() == dirEntries[j].IsDir() {
-				return dirEntries[i].Name() < dirEntries[j].Name()
-			}
-			return dirEntries[i].IsDir()
-		})
+		m.sortEntries(dirEntries)
 
 		if showHidden {
 			return readDirMsg{id: m.id, entries: dirEntries}
 		}
 
-		var sanitizedDirEntries []os.DirEntry
+		var sanitizedDirEntries []fs.DirEntry
 		for _, dirEntry := range dirEntries {
-			isHidden, _ := IsHidden(dirEntry.Name())
-			if isHidden {
+			if m.isHidden(dirEntry.Name()) {
 				continue
 			}
 			sanitizedDirEntries = append(sanitizedDirEntries, dirEntry)
@@ -235,7 +381,7 @@ func (m Model) readDir(path string, showHidden bool) tea.Cmd {
 
 // Init initializes the file picker model.
 func (m Model) Init() tea.Cmd {
-	return m.readDir(m.CurrentDirectory, m.ShowHidden)
+	return tea.Batch(m.readDir(m.CurrentDirectory, m.ShowHidden), m.startWatch())
 }
 
 // SetHeight sets the height of the filepicker.
@@ -248,16 +394,39 @@ func (m *Model) SetHeight(height int) {
 
 // Update handles user interactions within the file picker model.
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.rejection = nil
+	}
+	if cmd, ok := m.updateMutations(msg); ok {
+		return m, cmd
+	}
+	if cmd, ok := m.updateDelete(msg); ok {
+		return m, cmd
+	}
+	if cmd, ok := m.updateLocations(msg); ok {
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case readDirMsg:
 		if msg.id != m.id {
 			break
 		}
 		m.files = msg.entries
+		m.watchDir()
+		m.visit()
+		m.selected = min(m.selected, max(0, len(m.files)-1))
 		m.max = max(m.max, m.Height-1)
+		if m.selectName != "" {
+			m.selectEntry(m.selectName)
+			m.selectName = ""
+		}
+	case DirChangedMsg:
+		return m, m.updateWatch(msg)
 	case tea.WindowSizeMsg:
 		if m.AutoHeight {
 			m.Height = msg.Height - marginBottom
+			m.SetWidth(msg.Width)
 		}
 		m.max = m.Height - 1
 	case tea.KeyMsg:
@@ -313,7 +482,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				m.max = m.min + m.Height
 			}
 		case key.Matches(msg, m.KeyMap.Back):
-			m.CurrentDirectory = filepath.Dir(m.CurrentDirectory)
+			m.CurrentDirectory = m.parent(m.CurrentDirectory)
 			if m.selectedStack.Length() > 0 {
 				m.selected, m.min, m.max = m.popView()
 			} else {
@@ -322,34 +491,24 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				m.max = m.Height - 1
 			}
 			return m, m.readDir(m.CurrentDirectory, m.ShowHidden)
+		case key.Matches(msg, m.KeyMap.Sort):
+			m.SetSort(m.SortBy.next())
 		case key.Matches(msg, m.KeyMap.Open):
 			if len(m.files) == 0 {
 				break
 			}
 
 			f := m.files[m.selected]
-			info, err := f.Info()
+			isDir, _, err := m.resolve(f)
 			if err != nil {
 				break
 			}
-			isSymlink := info.Mode()&os.ModeSymlink != 0
-			isDir := f.IsDir()
-
-			if isSymlink {
-				symlinkPath, _ := filepath.EvalSymlinks(filepath.Join(m.CurrentDirectory, f.Name()))
-				info, err := os.Stat(symlinkPath)
-				if err != nil {
-					break
-				}
-				if info.IsDir() {
-					isDir = true
-				}
-			}
 
 			if (!isDir && m.FileAllowed) || (isDir && m.DirAllowed) {
 				if key.Matches(msg, m.KeyMap.Select) {
 					// Select the current path as the selection
-					m.Path = filepath.Join(m.CurrentDirectory, f.Name())
+					m.Path = m.join(m.CurrentDirectory, f.Name())
+					m.rejection = m.checkPath(m.Path)
 				}
 			}
 
@@ -357,7 +516,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				break
 			}
 
-			m.CurrentDirectory = filepath.Join(m.CurrentDirectory, f.Name())
+			m.CurrentDirectory = m.join(m.CurrentDirectory, f.Name())
 			m.pushView(m.selected, m.min, m.max)
 			m.selected = 0
 			m.min = 0
@@ -370,6 +529,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 // View returns the view of the file picker.
 func (m Model) View() string {
+	if m.showLocations {
+		return m.locationsView()
+	}
 	if len(m.files) == 0 {
 		return m.Styles.EmptyDirectory.Height(m.Height).MaxHeight(m.Height).String()
 	}
@@ -380,28 +542,41 @@ func (m Model) View() string {
 			continue
 		}
 
-		var symlinkPath string
-		info, _ := f.Info()
-		isSymlink := info.Mode()&os.ModeSymlink != 0
-		size := strings.Replace(humanize.Bytes(uint64(info.Size())), " ", "", 1) //nolint:gosec
 		name := f.Name()
-
+		isSymlink := f.Type()&fs.ModeSymlink != 0
+		var symlinkPath string
 		if isSymlink {
-			symlinkPath, _ = filepath.EvalSymlinks(filepath.Join(m.CurrentDirectory, name))
+			_, symlinkPath, _ = m.resolve(f)
 		}
 
-		disabled := !m.canSelect(name) && !f.IsDir()
+		// Not every fs.FS can describe its entries, so the permission and
+		// size columns are left blank when the info is missing.
+		var mode, size string
+		if info, err := f.Info(); err == nil {
+			mode = info.Mode().String()
+			size = strings.Replace(humanize.Bytes(uint64(info.Size())), " ", "", 1) //nolint:gosec
+		} else {
+			mode = strings.Repeat(" ", len(fs.FileMode(0).String()))
+		}
+
+		disabled := !f.IsDir() && m.checkEntry(f) != nil
+
+		if m.Columns != nil {
+			s.WriteString(m.columnsRowView(i, f, symlinkPath, disabled))
+			s.WriteRune('\n')
+			continue
+		}
 
 		if m.selected == i { //nolint:nestif
 			selected := ""
 			if m.ShowPermissions {
-				selected += " " + info.Mode().String()
+				selected += " " + mode
 			}
 			if m.ShowSize {
-				selected += fmt.Sprintf("%"+strconv.Itoa(m.Styles.FileSize.GetWidth())+"s", size)
+				selected += textutil.PadLeft(size, m.Styles.FileSize.GetWidth())
 			}
 			selected += " " + name
-			if isSymlink {
+			if symlinkPath != "" {
 				selected += " → " + symlinkPath
 			}
 			if disabled {
@@ -413,22 +588,13 @@ func (m Model) View() string {
 			continue
 		}
 
-		style := m.Styles.File
-		if f.IsDir() {
-			style = m.Styles.Directory
-		} else if isSymlink {
-			style = m.Styles.Symlink
-		} else if disabled {
-			style = m.Styles.DisabledFile
-		}
-
-		fileName := style.Render(name)
+		fileName := m.nameStyle(f, disabled).Render(name)
 		s.WriteString(m.Styles.Cursor.Render(" "))
-		if isSymlink {
+		if symlinkPath != "" {
 			fileName += " → " + symlinkPath
 		}
 		if m.ShowPermissions {
-			s.WriteString(" " + m.Styles.Permission.Render(info.Mode().String()))
+			s.WriteString(" " + m.Styles.Permission.Render(mode))
 		}
 		if m.ShowSize {
 			s.WriteString(m.Styles.FileSize.Render(size))
@@ -437,6 +603,19 @@ func (m Model) View() string {
 		s.WriteRune('\n')
 	}
 
+	if m.confirm != confirmNone {
+		s.WriteString(m.confirmView())
+		s.WriteRune('\n')
+	}
+	if v := m.promptView(); v != "" {
+		s.WriteString(v)
+		s.WriteRune('\n')
+	}
+	if v := m.rejectionView(); v != "" {
+		s.WriteString(v)
+		s.WriteRune('\n')
+	}
+
 	for i := lipgloss.Height(s.String()); i <= m.Height; i++ {
 		s.WriteRune('\n')
 	}
@@ -444,10 +623,30 @@ func (m Model) View() string {
 	return s.String()
 }
 
+// RenderString renders the file picker deterministically at the given size,
+// for golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	return render.View(m.View, width, height)
+}
+
+// nameStyle returns the style of an entry's name.
+func (m Model) nameStyle(f fs.DirEntry, disabled bool) lipgloss.Style {
+	switch {
+	case f.IsDir():
+		return m.Styles.Directory
+	case f.Type()&fs.ModeSymlink != 0:
+		return m.Styles.Symlink
+	case disabled:
+		return m.Styles.DisabledFile
+	default:
+		return m.Styles.File
+	}
+}
+
 // DidSelectFile returns whether a user has selected a file (on this msg).
 func (m Model) DidSelectFile(msg tea.Msg) (bool, string) {
 	didSelect, path := m.didSelectFile(msg)
-	if didSelect && m.canSelect(path) {
+	if didSelect && m.checkPath(path) == nil {
 		return true, path
 	}
 	return false, ""
@@ -458,7 +657,7 @@ func (m Model) DidSelectFile(msg tea.Msg) (bool, string) {
 // they tried to select a disabled file.
 func (m Model) DidSelectDisabledFile(msg tea.Msg) (bool, string) {
 	didSelect, path := m.didSelectFile(msg)
-	if didSelect && !m.canSelect(path) {
+	if didSelect && m.checkPath(path) != nil {
 		return true, path
 	}
 	return false, ""
@@ -477,24 +676,10 @@ func (m Model) didSelectFile(msg tea.Msg) (bool, string) {
 
 		// The key press was a selection, let's confirm whether the current file could
 		// be selected or used for navigating deeper into the stack.
-		f := m.files[m.selected]
-		info, err := f.Info()
+		isDir, _, err := m.resolve(m.files[m.selected])
 		if err != nil {
 			return false, ""
 		}
-		isSymlink := info.Mode()&os.ModeSymlink != 0
-		isDir := f.IsDir()
-
-		if isSymlink {
-			symlinkPath, _ := filepath.EvalSymlinks(filepath.Join(m.CurrentDirectory, f.Name()))
-			info, err := os.Stat(symlinkPath)
-			if err != nil {
-				break
-			}
-			if info.IsDir() {
-				isDir = true
-			}
-		}
 
 		if (!isDir && m.FileAllowed) || (isDir && m.DirAllowed) && m.Path != "" {
 			return true, m.Path
@@ -507,16 +692,3 @@ func (m Model) didSelectFile(msg tea.Msg) (bool, string) {
 	}
 	return false, ""
 }
-
-func (m Model) canSelect(file string) bool {
-	if len(m.AllowedTypes) <= 0 {
-		return true
-	}
-
-	for _, ext := range m.AllowedTypes {
-		if strings.HasSuffix(file, ext) {
-			return true
-		}
-	}
-	return false
-}
@@ -0,0 +1,400 @@
+package filepicker
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+// newPicker returns a picker showing dir, with its entries read.
+func newPicker(t *testing.T, dir string, opts ...Option) Model {
+	t.Helper()
+	m := New()
+	m.CurrentDirectory = dir
+	for _, opt := range opts {
+		opt(&m)
+	}
+	return read(t, m)
+}
+
+// read rereads the current directory of the picker.
+func read(t *testing.T, m Model) Model {
+	t.Helper()
+	msg := m.readDir(m.CurrentDirectory, m.ShowHidden)()
+	if err, ok := msg.(errorMsg); ok {
+		t.Fatalf("reading %s: %v", m.CurrentDirectory, err.err)
+	}
+	m, _ = m.Update(msg)
+	return m
+}
+
+// writeFile creates a file with the given content in dir.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// names returns the names of the entries the picker shows.
+func names(m Model) []string {
+	s := make([]string, len(m.files))
+	for i, f := range m.files {
+		s[i] = f.Name()
+	}
+	return s
+}
+
+func keyPress(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// update passes msg to the picker and then the messages sent by the commands
+// it returns, in turn, until one returns none.
+func update(m Model, msg tea.Msg) Model {
+	for msg != nil {
+		var cmd tea.Cmd
+		m, cmd = m.Update(msg)
+		if cmd == nil {
+			break
+		}
+		msg = cmd()
+	}
+	return m
+}
+
+func TestRenderString(t *testing.T) {
+	mod := time.Date(2024, 3, 5, 9, 7, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"docs":        {Mode: fs.ModeDir | 0o755, ModTime: mod},
+		"README.md":   {Data: []byte("# Hello"), Mode: 0o644, ModTime: mod},
+		"main.go":     {Data: make([]byte, 2048), Mode: 0o644, ModTime: mod},
+		".gitignore":  {Data: []byte("bin/"), Mode: 0o644, ModTime: mod},
+		"docs/api.md": {Data: []byte("API"), Mode: 0o644, ModTime: mod},
+	}
+
+	m := newPicker(t, ".", WithFS(fsys), WithHeight(4))
+	t.Run("default", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(m, 30, 5)))
+	})
+
+	m = newPicker(t, ".", WithFS(fsys), WithHeight(4), WithWidth(40),
+		WithColumns(ColumnPermissions, ColumnSize))
+	t.Run("columns", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(m, 40, 5)))
+	})
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	m := newPicker(t, dir, WithWatch(true))
+	t.Cleanup(func() { _ = m.Close() })
+
+	// waitForChange starts watching, creates a file and waits for the
+	// change to be reported.
+	waitForChange := func(name string) {
+		t.Helper()
+		cmd := m.startWatch()
+		if cmd == nil {
+			t.Fatal("expected a command watching the directory")
+		}
+		msgs := make(chan tea.Msg, 1)
+		go func() { msgs <- cmd() }()
+		writeFile(t, dir, name, "")
+		select {
+		case msg := <-msgs:
+			changed, ok := msg.(DirChangedMsg)
+			if !ok || changed.Dir != filepath.Clean(dir) {
+				t.Fatalf("expected the directory to change, got %#v", msg)
+			}
+			m, cmd = m.Update(changed)
+			if cmd == nil {
+				t.Fatal("expected the directory to be reread")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the directory to change")
+		}
+	}
+
+	waitForChange("a")
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if m.watch.dir != "" {
+		t.Fatalf("expected Close to forget the watched directory, got %q", m.watch.dir)
+	}
+
+	// Watching again after closing follows the same directory.
+	waitForChange("b")
+}
+
+func TestRename(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a", "")
+	writeFile(t, dir, "b", "")
+	m := newPicker(t, dir)
+
+	if m = update(m, keyPress("r")); m.Prompting() {
+		t.Fatal("expected renaming to be disabled by default")
+	}
+	m.AllowMutations = true
+	m = update(m, keyPress("r"))
+	if !m.Prompting() || m.input.Value() != "a" {
+		t.Fatalf("expected a prompt filled in with the name, got %q", m.input.Value())
+	}
+
+	// Renaming onto an existing file fails rather than replacing it.
+	m.input.SetValue("b")
+	m = update(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.Prompting() || m.mutationErr == nil {
+		t.Fatal("expected renaming onto an existing file to fail")
+	}
+	if got, want := m.promptView(), "b already exists"; !strings.Contains(got, want) {
+		t.Errorf("expected %q to be shown, got %q", want, got)
+	}
+	if got := names(m); !slices.Equal(got, []string{"a", "b"}) {
+		t.Fatalf("expected the files to be untouched, got %v", got)
+	}
+
+	m = update(m, keyPress("r"))
+	m.input.SetValue("../c")
+	if m = update(m, tea.KeyMsg{Type: tea.KeyEnter}); m.mutationErr == nil {
+		t.Fatal("expected a name with a separator to be rejected")
+	}
+
+	m = update(m, keyPress("r"))
+	m.input.SetValue("c")
+	m = update(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mutationErr != nil {
+		t.Fatal(m.mutationErr)
+	}
+	if got := names(m); !slices.Equal(got, []string{"b", "c"}) {
+		t.Fatalf("expected a to be renamed to c, got %v", got)
+	}
+	if m.files[m.selected].Name() != "c" {
+		t.Errorf("expected the renamed file to be selected, got %s", m.files[m.selected].Name())
+	}
+}
+
+func TestDelete(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a", "")
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, sub, "b", "")
+
+	var trashed []string
+	trashErr := ErrNoTrash
+	m := newPicker(t, dir)
+	m.SetDeleteEnabled(true)
+	m.Trasher = TrasherFunc(func(path string) error {
+		if trashErr != nil {
+			return trashErr
+		}
+		trashed = append(trashed, path)
+		return os.Remove(path)
+	})
+
+	// Declining leaves the file alone.
+	m = update(m, keyPress("d"))
+	if !m.ConfirmingDelete() || !strings.Contains(m.confirmView(), "Move sub to the trash?") {
+		t.Fatalf("expected to be asked to confirm, got %q", m.confirmView())
+	}
+	if m = update(m, keyPress("n")); m.ConfirmingDelete() {
+		t.Fatal("expected the deletion to be canceled")
+	}
+
+	// A directory which can't be trashed is offered to be deleted
+	// permanently, which removes its contents too.
+	m = update(m, keyPress("d"))
+	m = update(m, keyPress("y"))
+	if !m.ConfirmingDelete() || !strings.Contains(m.confirmView(), "Delete sub permanently?") {
+		t.Fatalf("expected to be offered to delete permanently, got %q", m.confirmView())
+	}
+	if _, err := os.Stat(sub); err != nil {
+		t.Fatal("expected the directory to be kept until confirmed")
+	}
+	m = update(m, keyPress("y"))
+	if m.ConfirmingDelete() {
+		t.Fatal("expected the deletion to be done")
+	}
+	if _, err := os.Stat(sub); !os.IsNotExist(err) {
+		t.Fatalf("expected the directory to be removed, got %v", err)
+	}
+	if got := names(m); !slices.Equal(got, []string{"a"}) {
+		t.Fatalf("expected the directory to be reread, got %v", got)
+	}
+
+	trashErr = nil
+	m = update(m, keyPress("d"))
+	m = update(m, keyPress("y"))
+	if want := []string{filepath.Join(dir, "a")}; !slices.Equal(trashed, want) {
+		t.Fatalf("expected %v to be trashed, got %v", want, trashed)
+	}
+	if m.ConfirmingDelete() || len(m.files) != 0 {
+		t.Fatalf("expected the file to be gone, got %v", names(m))
+	}
+}
+
+func TestRules(t *testing.T) {
+	dir := t.TempDir()
+	small := writeFile(t, dir, "small.go", "x")
+	large := writeFile(t, dir, "large.txt", strings.Repeat("x", 2048))
+	info := func(path string) fs.FileInfo {
+		t.Helper()
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fi
+	}
+
+	tests := []struct {
+		name string
+		rule Rule
+		path string
+		want string
+	}{
+		{"extension", Extensions(".go", ".mod"), small, ""},
+		{"other extension", Extensions(".go", ".mod"), large, "filepicker: only .go, .mod files can be selected"},
+		{"small enough", MaxSize(1024), small, ""},
+		{"too large", MaxSize(1024), large, "filepicker: large.txt is larger than 1.0 kB"},
+		{"directory of any size", MaxSize(0), dir, ""},
+		{"files only", FilesOnly(), dir, "filepicker: only files can be selected"},
+		{"dirs only", DirsOnly(), small, "filepicker: only directories can be selected"},
+		{"all", All(FilesOnly(), MaxSize(1024), Extensions(".go")), small, ""},
+		{"all rejects with the first error", All(Extensions(".go"), MaxSize(1024)), large, "filepicker: only .go files can be selected"},
+		{"none of all", All(), large, ""},
+		{"any", Any(Extensions(".go"), MaxSize(4096)), large, ""},
+		{"any rejects with the first error", Any(MaxSize(1024), Extensions(".go")), large, "filepicker: large.txt is larger than 1.0 kB"},
+		{"none of any", Any(), large, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			if err := tt.rule(tt.path, info(tt.path)); err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSelectRejected(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "a.go", "")
+	writeFile(t, dir, "b.txt", "")
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	// selectEntry selects the entry with the given name and returns the
+	// picker and whether it was selected, or why it wasn't.
+	selectEntry := func(m Model, name string) (Model, bool, string) {
+		t.Helper()
+		m.selectEntry(name)
+		m, _ = m.Update(enter)
+		ok, _ := m.DidSelectFile(enter)
+		disabled, _ := m.DidSelectDisabledFile(enter)
+		if ok == disabled {
+			t.Fatalf("%s: expected the entry to be either selected or disabled", name)
+		}
+		return m, ok, ansi.Strip(m.rejectionView())
+	}
+
+	// AllowedTypes keeps working as it did.
+	m := newPicker(t, dir)
+	m.AllowedTypes = []string{".go"}
+	if _, ok, rejection := selectEntry(m, "a.go"); !ok || rejection != "" {
+		t.Errorf("expected a.go to be selected, got %q", rejection)
+	}
+	m, ok, rejection := selectEntry(m, "b.txt")
+	if ok || !strings.Contains(rejection, "only .go files can be selected") {
+		t.Errorf("expected b.txt to be rejected, got %q", rejection)
+	}
+	if m, _ = m.Update(keyPress("j")); m.rejectionView() != "" {
+		t.Error("expected the rejection to be cleared by the next key")
+	}
+
+	// Rejected directories say why too, but still open.
+	m = newPicker(t, dir, WithRules(FilesOnly()), WithDirAllowed(true))
+	m.selectEntry("sub")
+	m, cmd := m.Update(enter)
+	if !strings.Contains(m.rejectionView(), "only files can be selected") {
+		t.Errorf("expected the directory to be rejected, got %q", m.rejectionView())
+	}
+	if disabled, _ := m.DidSelectDisabledFile(enter); !disabled {
+		t.Error("expected the directory to be reported as disabled")
+	}
+	if cmd == nil || m.CurrentDirectory != filepath.Join(dir, "sub") {
+		t.Errorf("expected the directory to be opened, got %s", m.CurrentDirectory)
+	}
+
+	m = newPicker(t, dir, WithCanSelect(func(path string, _ fs.FileInfo) bool {
+		return filepath.Base(path) != "a.go"
+	}))
+	if _, ok, rejection := selectEntry(m, "a.go"); ok || !strings.Contains(rejection, "a.go can't be selected") {
+		t.Errorf("expected a.go to be rejected by CanSelect, got %q", rejection)
+	}
+}
+
+func TestLocations(t *testing.T) {
+	root := t.TempDir()
+	work := filepath.Join(root, "work")
+	gone := filepath.Join(root, "gone")
+	for _, dir := range []string{work, gone} {
+		if err := os.Mkdir(dir, 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := newPicker(t, root, WithBookmarks(
+		Bookmark{Name: "work", Path: work},
+		Bookmark{Name: "missing", Path: filepath.Join(root, "missing")},
+	))
+	m.Pin(gone)
+
+	m, _ = m.Update(keyPress("b"))
+	if !m.ShowingLocations() {
+		t.Fatal("expected the locations list to be shown")
+	}
+	v := ansi.Strip(m.View())
+	if !strings.Contains(v, "work") || !strings.Contains(v, "gone") || strings.Contains(v, "missing") {
+		t.Fatalf("expected the existing bookmarks and pinned directories, got %q", v)
+	}
+
+	// The list is checked when it's opened, not on every key.
+	if err := os.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+	m, _ = m.Update(keyPress("j"))
+	if v := ansi.Strip(m.View()); !strings.Contains(v, "gone") {
+		t.Fatalf("expected the list to be kept while it's open, got %q", v)
+	}
+
+	m, _ = m.Update(keyPress("b"))
+	m, _ = m.Update(keyPress("b"))
+	if v := ansi.Strip(m.View()); strings.Contains(v, "gone") {
+		t.Fatalf("expected the removed directory to be left out when reopened, got %q", v)
+	}
+
+	m = update(m, keyPress("l"))
+	if m.ShowingLocations() || m.CurrentDirectory != work {
+		t.Fatalf("expected to open %s, got %s", work, m.CurrentDirectory)
+	}
+}
@@ -0,0 +1,69 @@
+package filepicker
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// The helpers below dispatch between the OS filesystem and Model.FS. Paths
+// into an fs.FS are always slash-separated and unrooted, so they use the path
+// package rather than path/filepath.
+
+func (m Model) join(elem ...string) string {
+	if m.FS != nil {
+		return path.Join(elem...)
+	}
+	return filepath.Join(elem...)
+}
+
+func (m Model) parent(dir string) string {
+	if m.FS != nil {
+		return path.Dir(dir)
+	}
+	return filepath.Dir(dir)
+}
+
+func (m Model) stat(name string) (fs.FileInfo, error) {
+	if m.FS != nil {
+		return fs.Stat(m.FS, name) //nolint:wrapcheck
+	}
+	return os.Stat(name) //nolint:wrapcheck
+}
+
+func (m Model) readDirEntries(dir string) ([]fs.DirEntry, error) {
+	if m.FS != nil {
+		return fs.ReadDir(m.FS, dir) //nolint:wrapcheck
+	}
+	return os.ReadDir(dir) //nolint:wrapcheck
+}
+
+func (m Model) isHidden(name string) bool {
+	if m.FS != nil {
+		return strings.HasPrefix(name, ".")
+	}
+	hidden, _ := IsHidden(name)
+	return hidden
+}
+
+// resolve reports whether an entry in the current directory is a directory,
+// following symlinks, and where it links to. An error is returned for broken
+// links. An fs.FS can't read links, so the target is empty there, though the
+// directory check still follows them when the FS supports it.
+func (m Model) resolve(f fs.DirEntry) (isDir bool, target string, err error) {
+	if f.Type()&fs.ModeSymlink == 0 {
+		return f.IsDir(), "", nil
+	}
+	name := m.join(m.CurrentDirectory, f.Name())
+	if m.FS == nil {
+		target, _ = filepath.EvalSymlinks(name)
+		name = target
+	}
+	info, err := m.stat(name)
+	if err != nil {
+		return false, target, err
+	}
+	return info.IsDir(), target, nil
+}
@@ -0,0 +1,325 @@
+package filepicker
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mikeflynn/bubbles/key"
+)
+
+// DefaultMaxRecent is the number of recent directories remembered when
+// MaxRecent is zero.
+const DefaultMaxRecent = 10
+
+// Bookmark is a directory listed under a name in the locations list.
+type Bookmark struct {
+	Name string
+	Path string
+}
+
+// State is the part of a file picker's state worth keeping between runs:
+// the directories the user pinned and those visited recently, most recent
+// first. It can be encoded with encoding/json and restored with SetState.
+type State struct {
+	Pinned []string `json:"pinned,omitempty"`
+	Recent []string `json:"recent,omitempty"`
+}
+
+// location is an entry of the locations list.
+type location struct {
+	name string
+	path string
+}
+
+// DefaultBookmarks returns bookmarks for the user's home directory and for
+// the root of the project the working directory is in, if any, found by
+// looking for a version control directory such as .git.
+func DefaultBookmarks() []Bookmark {
+	var bookmarks []Bookmark
+	if home, err := os.UserHomeDir(); err == nil {
+		bookmarks = append(bookmarks, Bookmark{Name: "Home", Path: home})
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if root, ok := ProjectRoot(wd); ok {
+			bookmarks = append(bookmarks, Bookmark{Name: filepath.Base(root), Path: root})
+		}
+	}
+	return bookmarks
+}
+
+// ProjectRoot returns the nearest directory containing dir, or dir itself,
+// which holds a .git, .hg or .svn directory. It returns false if there's
+// none.
+func ProjectRoot(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		for _, vcs := range []string{".git", ".hg", ".svn"} {
+			if _, err := os.Stat(filepath.Join(dir, vcs)); err == nil {
+				return dir, true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// WithBookmarks sets the bookmarks listed in the locations list. See
+// Model.Bookmarks.
+func WithBookmarks(bookmarks ...Bookmark) Option {
+	return func(m *Model) {
+		m.Bookmarks = bookmarks
+	}
+}
+
+// WithState restores the pinned and recent directories. See Model.SetState.
+func WithState(s State) Option {
+	return func(m *Model) {
+		m.SetState(s)
+	}
+}
+
+// State returns the pinned and recent directories, for the host to persist
+// between runs.
+func (m Model) State() State {
+	return State{
+		Pinned: slices.Clone(m.pinned),
+		Recent: slices.Clone(m.recent),
+	}
+}
+
+// SetState restores the pinned and recent directories, such as those saved
+// from State in a previous run.
+func (m *Model) SetState(s State) {
+	m.pinned = slices.Clone(s.Pinned)
+	m.recent = slices.Clone(s.Recent)
+	if n := m.maxRecent(); len(m.recent) > n {
+		m.recent = m.recent[:n]
+	}
+	m.refreshLocations()
+}
+
+// Pinned returns the directories the user pinned, in the order they were
+// pinned.
+func (m Model) Pinned() []string {
+	return slices.Clone(m.pinned)
+}
+
+// Recent returns the directories visited recently, most recent first.
+func (m Model) Recent() []string {
+	return slices.Clone(m.recent)
+}
+
+// Pin adds a directory to the pinned directories, if it isn't already.
+func (m *Model) Pin(dir string) {
+	dir = m.absDir(dir)
+	if !slices.Contains(m.pinned, dir) {
+		m.pinned = append(slices.Clone(m.pinned), dir)
+		m.refreshLocations()
+	}
+}
+
+// Unpin removes a directory from the pinned directories.
+func (m *Model) Unpin(dir string) {
+	dir = m.absDir(dir)
+	m.pinned = slices.DeleteFunc(slices.Clone(m.pinned), func(d string) bool {
+		return d == dir
+	})
+	m.refreshLocations()
+}
+
+// ShowingLocations returns whether the locations list is shown in place of
+// the current directory.
+func (m Model) ShowingLocations() bool {
+	return m.showLocations
+}
+
+// absDir returns the absolute form of a directory on the OS filesystem, or
+// the cleaned path of a directory in an FS.
+func (m Model) absDir(dir string) string {
+	if m.FS != nil {
+		return path.Clean(dir)
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+	return filepath.Clean(dir)
+}
+
+// visit records the current directory as the most recent one.
+func (m *Model) visit() {
+	dir := m.absDir(m.CurrentDirectory)
+	recent := make([]string, 0, len(m.recent)+1)
+	recent = append(recent, dir)
+	for _, d := range m.recent {
+		if d != dir {
+			recent = append(recent, d)
+		}
+	}
+	m.recent = recent[:min(len(recent), m.maxRecent())]
+}
+
+func (m Model) maxRecent() int {
+	if m.MaxRecent > 0 {
+		return m.MaxRecent
+	}
+	return DefaultMaxRecent
+}
+
+// refreshLocations rebuilds the locations list while it's shown, so the
+// directories are only checked for when it's opened or its entries change.
+func (m *Model) refreshLocations() {
+	if !m.showLocations {
+		m.locations, m.markedLocations = nil, 0
+		return
+	}
+	m.locations, m.markedLocations = m.locationList()
+	m.locationCursor = max(min(m.locationCursor, len(m.locations)-1), 0)
+}
+
+// locationList returns the entries of the locations list: the bookmarks,
+// pinned directories and recent directories which exist, with the number of
+// bookmarks and pinned directories among them. Directories are listed once,
+// under the first of those they're in.
+func (m Model) locationList() ([]location, int) {
+	var list []location
+	seen := make(map[string]bool)
+	add := func(name, dir string) {
+		dir = m.absDir(dir)
+		if seen[dir] {
+			return
+		}
+		if info, err := m.stat(dir); err != nil || !info.IsDir() {
+			return
+		}
+		seen[dir] = true
+		list = append(list, location{name: name, path: dir})
+	}
+	for _, b := range m.Bookmarks {
+		add(b.Name, b.Path)
+	}
+	for _, d := range m.pinned {
+		add(m.locationName(d), d)
+	}
+	marked := len(list)
+	for _, d := range m.recent {
+		add(m.locationName(d), d)
+	}
+	return list, marked
+}
+
+func (m Model) locationName(dir string) string {
+	if m.FS != nil {
+		return path.Base(dir)
+	}
+	return filepath.Base(dir)
+}
+
+// openLocation moves to a directory from the locations list.
+func (m *Model) openLocation(dir string) tea.Cmd {
+	m.showLocations = false
+	m.refreshLocations()
+	m.CurrentDirectory = dir
+	m.selectedStack, m.minStack, m.maxStack = newStack(), newStack(), newStack()
+	m.selected = 0
+	m.min = 0
+	m.max = m.Height - 1
+	return m.readDir(m.CurrentDirectory, m.ShowHidden)
+}
+
+// updateLocations handles the keys for pinning directories and for the
+// locations list. It returns false if the message should be handled
+// normally.
+func (m *Model) updateLocations(msg tea.Msg) (tea.Cmd, bool) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil, false
+	}
+
+	if !m.showLocations {
+		switch {
+		case key.Matches(keyMsg, m.KeyMap.Locations):
+			m.showLocations = true
+			m.locationCursor = 0
+			m.refreshLocations()
+			return nil, true
+		case key.Matches(keyMsg, m.KeyMap.Pin):
+			dir := m.absDir(m.CurrentDirectory)
+			if slices.Contains(m.pinned, dir) {
+				m.Unpin(dir)
+			} else {
+				m.Pin(dir)
+			}
+			return nil, true
+		}
+		return nil, false
+	}
+
+	list := m.locations
+	switch {
+	case key.Matches(keyMsg, m.KeyMap.Down):
+		m.locationCursor = max(min(m.locationCursor+1, len(list)-1), 0)
+	case key.Matches(keyMsg, m.KeyMap.Up):
+		m.locationCursor = max(m.locationCursor-1, 0)
+	case key.Matches(keyMsg, m.KeyMap.GoToTop):
+		m.locationCursor = 0
+	case key.Matches(keyMsg, m.KeyMap.GoToLast):
+		m.locationCursor = max(len(list)-1, 0)
+	case key.Matches(keyMsg, m.KeyMap.Open, m.KeyMap.Select):
+		if m.locationCursor < len(list) {
+			return m.openLocation(list[m.locationCursor].path), true
+		}
+	case key.Matches(keyMsg, m.KeyMap.Locations, m.KeyMap.Back, m.KeyMap.Cancel):
+		m.showLocations = false
+		m.refreshLocations()
+	}
+	return nil, true
+}
+
+// locationsView renders the locations list, bookmarks and pinned
+// directories first and then recent ones, scrolled to keep the selected
+// entry in view.
+func (m Model) locationsView() string {
+	list, marked := m.locations, m.markedLocations
+	if len(list) == 0 {
+		return m.Styles.EmptyDirectory.Height(m.Height).MaxHeight(m.Height).
+			Render("No bookmarks or recent directories.")
+	}
+
+	var rows []string
+	cursorRow := 0
+	for i, loc := range list {
+		switch {
+		case i == 0 && marked > 0:
+			rows = append(rows, m.Styles.LocationHeader.Render("Bookmarks"))
+		case i == marked:
+			rows = append(rows, m.Styles.LocationHeader.Render("Recent"))
+		}
+
+		path := m.Styles.LocationPath.Render(loc.path)
+		if i == m.locationCursor {
+			cursorRow = len(rows)
+			rows = append(rows, m.Styles.Cursor.Render(m.Cursor)+m.Styles.Selected.Render(" "+loc.name)+" "+path)
+			continue
+		}
+		rows = append(rows, m.Styles.Cursor.Render(" ")+" "+m.Styles.Directory.Render(loc.name)+" "+path)
+	}
+
+	if m.Height > 0 && len(rows) > m.Height {
+		first := max(0, min(cursorRow-m.Height+1, len(rows)-m.Height))
+		rows = rows[first : first+m.Height]
+	}
+	for len(rows) <= m.Height {
+		rows = append(rows, "")
+	}
+	return strings.Join(rows, "\n") + "\n"
+}
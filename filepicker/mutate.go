@@ -0,0 +1,228 @@
+package filepicker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/textinput"
+)
+
+// CreatedDirMsg is sent after the user creates a directory. Err is set if it
+// couldn't be created.
+type CreatedDirMsg struct {
+	id int
+
+	// Path is the path of the new directory.
+	Path string
+
+	Err error
+}
+
+// RenamedMsg is sent after the user renames a file. Err is set if it couldn't
+// be renamed.
+type RenamedMsg struct {
+	id int
+
+	// From and To are the old and new paths of the file.
+	From, To string
+
+	Err error
+}
+
+// promptKind is the kind of name being typed in the prompt.
+type promptKind int
+
+const (
+	promptNone promptKind = iota
+	promptNewDir
+	promptRename
+)
+
+// Prompting returns whether the file picker is waiting for the user to type
+// the name of a new directory or of a file being renamed.
+func (m Model) Prompting() bool {
+	return m.prompt != promptNone
+}
+
+// mutationKey reports whether msg matches b, one of the bindings for changing
+// files. AllowMutations enables the binding even when it's disabled in the
+// KeyMap. Files can't be changed when browsing an FS.
+func (m Model) mutationKey(msg tea.KeyMsg, b key.Binding) bool {
+	if m.FS != nil {
+		return false
+	}
+	if m.AllowMutations {
+		b.SetEnabled(true)
+	}
+	return key.Matches(msg, b)
+}
+
+// createDir returns a command which creates a directory.
+func (m Model) createDir(path string) tea.Cmd {
+	id := m.id
+	return func() tea.Msg {
+		msg := CreatedDirMsg{id: id, Path: path}
+		if err := os.Mkdir(path, 0o755); err != nil { //nolint:mnd,gosec
+			msg.Err = fmt.Errorf("filepicker: %w", err)
+		}
+		return msg
+	}
+}
+
+// rename returns a command which renames a file. Unlike os.Rename it won't
+// replace an existing file.
+func (m Model) rename(from, to string) tea.Cmd {
+	id := m.id
+	return func() tea.Msg {
+		msg := RenamedMsg{id: id, From: from, To: to}
+		if _, err := os.Lstat(to); err == nil {
+			msg.Err = fmt.Errorf("filepicker: %s already exists", filepath.Base(to))
+		} else if err := os.Rename(from, to); err != nil {
+			msg.Err = fmt.Errorf("filepicker: %w", err)
+		}
+		return msg
+	}
+}
+
+// startPrompt shows the prompt for a name, filled in with value.
+func (m *Model) startPrompt(kind promptKind, value string) tea.Cmd {
+	m.prompt = kind
+	m.mutationErr = nil
+	m.input = textinput.New()
+	m.input.Prompt = ""
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+// acceptPrompt returns a command which creates or renames a file with the
+// name typed in the prompt.
+func (m *Model) acceptPrompt() tea.Cmd {
+	kind := m.prompt
+	m.prompt = promptNone
+
+	name := strings.TrimSpace(m.input.Value())
+	switch {
+	case name == "" || (kind == promptRename && name == filepath.Base(m.promptPath)):
+		return nil
+	case name == "." || name == ".." || strings.ContainsAny(name, `/`+string(filepath.Separator)):
+		m.mutationErr = fmt.Errorf("filepicker: invalid name %q", name)
+		return nil
+	}
+
+	path := m.join(m.CurrentDirectory, name)
+	if kind == promptRename {
+		return m.rename(m.promptPath, path)
+	}
+	return m.createDir(path)
+}
+
+// updateMutations handles the keys and messages for creating and renaming
+// files. It returns false if the message should be handled normally.
+func (m *Model) updateMutations(msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case CreatedDirMsg:
+		if msg.id != m.id {
+			return nil, true
+		}
+		return m.afterMutation(msg.Path, msg.Err), true
+	case RenamedMsg:
+		if msg.id != m.id {
+			return nil, true
+		}
+		return m.afterMutation(msg.To, msg.Err), true
+	case tea.KeyMsg:
+		if m.prompt != promptNone {
+			switch {
+			case key.Matches(msg, m.KeyMap.AcceptInput):
+				return m.acceptPrompt(), true
+			case key.Matches(msg, m.KeyMap.CancelInput):
+				m.prompt = promptNone
+				return nil, true
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return cmd, true
+		}
+
+		m.mutationErr = nil
+		switch {
+		case m.mutationKey(msg, m.KeyMap.NewDirectory):
+			return m.startPrompt(promptNewDir, ""), true
+		case m.mutationKey(msg, m.KeyMap.Rename) && len(m.files) > 0:
+			name := m.files[m.selected].Name()
+			m.promptPath = m.join(m.CurrentDirectory, name)
+			return m.startPrompt(promptRename, name), true
+		}
+	}
+	return nil, false
+}
+
+// afterMutation shows the error of a failed operation, or rereads the
+// directory and selects the file at path.
+func (m *Model) afterMutation(path string, err error) tea.Cmd {
+	if err != nil {
+		m.mutationErr = err
+		return nil
+	}
+	m.selectName = filepath.Base(path)
+	return m.readDir(m.CurrentDirectory, m.ShowHidden)
+}
+
+// selectEntry selects the entry with the given name, scrolling it into view.
+func (m *Model) selectEntry(name string) {
+	i := indexOf(m.files, name)
+	if i < 0 {
+		return
+	}
+	m.selected = i
+	if m.Height <= 0 {
+		return
+	}
+	if m.selected > m.max {
+		m.max = m.selected
+		m.min = m.max - m.Height + 1
+	}
+	if m.selected < m.min {
+		m.min = m.selected
+		m.max = m.min + m.Height - 1
+	}
+}
+
+func indexOf(files []fs.DirEntry, name string) int {
+	for i, f := range files {
+		if f.Name() == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// promptView renders the prompt for a name, or the error of the last
+// operation.
+func (m Model) promptView() string {
+	switch {
+	case m.prompt == promptNewDir:
+		return m.Styles.Prompt.Render("New directory: ") + m.input.View()
+	case m.prompt == promptRename:
+		return m.Styles.Prompt.Render(fmt.Sprintf("Rename %s to: ", filepath.Base(m.promptPath))) + m.input.View()
+	case m.mutationErr != nil:
+		return m.Styles.Error.Render(errorText(m.mutationErr))
+	}
+	return ""
+}
+
+// errorText returns the message of err without the package prefix.
+func errorText(err error) string {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return fmt.Sprintf("%s: %v", filepath.Base(pathErr.Path), pathErr.Err)
+	}
+	return strings.TrimPrefix(err.Error(), "filepicker: ")
+}
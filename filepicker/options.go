@@ -0,0 +1,121 @@
+package filepicker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// Option is used to set options in NewWithOptions. For example:
+//
+//	fp, err := NewWithOptions(
+//		WithCurrentDirectory("/tmp"),
+//		WithRules(Extensions(".go", ".mod")),
+//	)
+type Option func(*Model)
+
+// WithCurrentDirectory sets the directory the picker starts in.
+func WithCurrentDirectory(dir string) Option {
+	return func(m *Model) {
+		m.CurrentDirectory = dir
+	}
+}
+
+// WithFS sets the filesystem to browse instead of the OS filesystem.
+func WithFS(fsys fs.FS) Option {
+	return func(m *Model) {
+		m.FS = fsys
+	}
+}
+
+// WithAllowedTypes sets the file types the user may select. If none are
+// given the user may select any file.
+//
+// Deprecated: use WithRules with Extensions instead.
+func WithAllowedTypes(types ...string) Option {
+	return func(m *Model) {
+		m.AllowedTypes = types
+	}
+}
+
+// WithHeight sets a fixed height for the picker, disabling AutoHeight.
+func WithHeight(h int) Option {
+	return func(m *Model) {
+		m.AutoHeight = false
+		m.SetHeight(h)
+	}
+}
+
+// WithShowHidden sets whether hidden files are shown.
+func WithShowHidden(v bool) Option {
+	return func(m *Model) {
+		m.ShowHidden = v
+	}
+}
+
+// WithDirAllowed sets whether directories can be selected.
+func WithDirAllowed(v bool) Option {
+	return func(m *Model) {
+		m.DirAllowed = v
+	}
+}
+
+// WithFileAllowed sets whether files can be selected.
+func WithFileAllowed(v bool) Option {
+	return func(m *Model) {
+		m.FileAllowed = v
+	}
+}
+
+// WithKeyMap sets the keymap of the picker.
+func WithKeyMap(km KeyMap) Option {
+	return func(m *Model) {
+		m.KeyMap = km
+	}
+}
+
+// WithStyles sets the styles of the picker.
+func WithStyles(s Styles) Option {
+	return func(m *Model) {
+		m.Styles = s
+	}
+}
+
+// NewWithOptions returns a new file picker configured by the given options.
+// Unlike New it checks the configuration up front, returning an error if it
+// can't be honored, such as a starting directory that doesn't exist.
+func NewWithOptions(opts ...Option) (Model, error) {
+	m := New()
+	for _, opt := range opts {
+		opt(&m)
+	}
+
+	if err := m.validate(); err != nil {
+		return Model{}, err
+	}
+	return m, nil
+}
+
+// validate returns an error if the picker's configuration can't be honored.
+func (m Model) validate() error {
+	if !m.DirAllowed && !m.FileAllowed {
+		return errors.New("filepicker: neither directories nor files can be selected")
+	}
+	if len(m.AllowedTypes) > 0 && !m.FileAllowed {
+		return errors.New("filepicker: allowed types are set but files can't be selected")
+	}
+	if m.Height < 0 {
+		return fmt.Errorf("filepicker: invalid height %d", m.Height)
+	}
+	if m.Watch && m.FS != nil {
+		return errors.New("filepicker: an FS can't be watched")
+	}
+	info, err := m.stat(m.CurrentDirectory)
+	if err != nil {
+		return fmt.Errorf("filepicker: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("filepicker: %s is not a directory", m.CurrentDirectory)
+	}
+	return nil
+}
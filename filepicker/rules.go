@@ -0,0 +1,166 @@
+package filepicker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+// Rule decides whether an entry can be selected, given its path and info,
+// with symlinks followed. It returns nil if the entry can be selected, or an
+// error saying why not, which is shown when the user tries to select it.
+// Rules are composed with All and Any.
+type Rule func(path string, info fs.FileInfo) error
+
+// FilesOnly returns a rule letting only files be selected.
+func FilesOnly() Rule {
+	return func(_ string, info fs.FileInfo) error {
+		if info.IsDir() {
+			return errors.New("filepicker: only files can be selected")
+		}
+		return nil
+	}
+}
+
+// DirsOnly returns a rule letting only directories be selected.
+func DirsOnly() Rule {
+	return func(_ string, info fs.FileInfo) error {
+		if !info.IsDir() {
+			return errors.New("filepicker: only directories can be selected")
+		}
+		return nil
+	}
+}
+
+// Extensions returns a rule letting only files whose name ends with one of
+// the given extensions be selected, such as ".go".
+func Extensions(exts ...string) Rule {
+	return func(path string, _ fs.FileInfo) error {
+		for _, ext := range exts {
+			if strings.HasSuffix(path, ext) {
+				return nil
+			}
+		}
+		return fmt.Errorf("filepicker: only %s files can be selected", strings.Join(exts, ", "))
+	}
+}
+
+// MaxSize returns a rule letting only files of up to the given number of
+// bytes be selected. Directories aren't limited.
+func MaxSize(n int64) Rule {
+	return func(path string, info fs.FileInfo) error {
+		if !info.IsDir() && info.Size() > n {
+			return fmt.Errorf("filepicker: %s is larger than %s", filepath.Base(path), humanize.Bytes(uint64(n))) //nolint:gosec
+		}
+		return nil
+	}
+}
+
+// All returns a rule letting an entry be selected if every one of the given
+// rules does. The error of the first rule rejecting it is returned.
+func All(rules ...Rule) Rule {
+	return func(path string, info fs.FileInfo) error {
+		for _, r := range rules {
+			if err := r(path, info); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Any returns a rule letting an entry be selected if at least one of the
+// given rules does. If none does, the error of the first rule is returned.
+func Any(rules ...Rule) Rule {
+	return func(path string, info fs.FileInfo) error {
+		var first error
+		for _, r := range rules {
+			err := r(path, info)
+			if err == nil {
+				return nil
+			}
+			if first == nil {
+				first = err
+			}
+		}
+		return first
+	}
+}
+
+// WithRules sets the rules deciding which entries can be selected. See
+// Model.Rules.
+func WithRules(rules ...Rule) Option {
+	return func(m *Model) {
+		m.Rules = rules
+	}
+}
+
+// WithCanSelect sets a function reporting whether an entry can be selected.
+// See Model.CanSelect.
+func WithCanSelect(f func(path string, info fs.FileInfo) bool) Option {
+	return func(m *Model) {
+		m.CanSelect = f
+	}
+}
+
+// hasRules returns whether any rule limits which entries can be selected.
+func (m Model) hasRules() bool {
+	return len(m.Rules) > 0 || len(m.AllowedTypes) > 0 || m.CanSelect != nil
+}
+
+// check returns why an entry can't be selected, or nil if it can.
+func (m Model) check(path string, info fs.FileInfo) error {
+	if err := All(m.Rules...)(path, info); err != nil {
+		return err
+	}
+	if len(m.AllowedTypes) > 0 {
+		if err := Extensions(m.AllowedTypes...)(path, info); err != nil {
+			return err
+		}
+	}
+	if m.CanSelect != nil && !m.CanSelect(path, info) {
+		return fmt.Errorf("filepicker: %s can't be selected", filepath.Base(path))
+	}
+	return nil
+}
+
+// checkPath returns why the entry at path can't be selected, or nil if it
+// can.
+func (m Model) checkPath(path string) error {
+	if !m.hasRules() {
+		return nil
+	}
+	info, err := m.stat(path)
+	if err != nil {
+		return err
+	}
+	return m.check(path, info)
+}
+
+// checkEntry returns why an entry of the current directory can't be
+// selected, or nil if it can. The entry's own info is used unless it's a
+// symlink, saving a stat for each entry shown.
+func (m Model) checkEntry(f fs.DirEntry) error {
+	if !m.hasRules() {
+		return nil
+	}
+	path := m.join(m.CurrentDirectory, f.Name())
+	if f.Type()&fs.ModeSymlink == 0 {
+		if info, err := f.Info(); err == nil {
+			return m.check(path, info)
+		}
+	}
+	return m.checkPath(path)
+}
+
+// rejectionView renders why the entry the user tried to select can't be.
+func (m Model) rejectionView() string {
+	if m.rejection == nil {
+		return ""
+	}
+	return m.Styles.Rejection.Render(errorText(m.rejection))
+}
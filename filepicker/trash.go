@@ -0,0 +1,148 @@
+package filepicker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mikeflynn/bubbles/key"
+)
+
+// ErrNoTrash is returned by a Trasher when there's no trash to move a file
+// to, such as when the file is on a different volume than the trash.
+var ErrNoTrash = errors.New("filepicker: no trash available")
+
+// Trasher moves files to a trash or recycle bin, from which the user can
+// restore them.
+type Trasher interface {
+	Trash(path string) error
+}
+
+// TrasherFunc adapts a function to the Trasher interface.
+type TrasherFunc func(path string) error
+
+// Trash calls f(path).
+func (f TrasherFunc) Trash(path string) error {
+	return f(path)
+}
+
+// DefaultTrasher returns a Trasher for the trash of the current platform:
+// the XDG trash in the user's data directory on Linux and other Unix-like
+// systems, ~/.Trash on macOS and the Recycle Bin on Windows.
+func DefaultTrasher() Trasher {
+	return osTrasher{}
+}
+
+// DeletedMsg is sent after the user confirms the deletion of a file. Err is
+// set if it couldn't be deleted, in which case the file picker offers to
+// delete it permanently if it couldn't be moved to the trash.
+type DeletedMsg struct {
+	id int
+
+	// Path is the path of the deleted file.
+	Path string
+
+	// Permanent is true if the file was unlinked rather than moved to the
+	// trash.
+	Permanent bool
+
+	Err error
+}
+
+// SetDeleteEnabled sets whether the user can delete files, without allowing
+// the other changes AllowMutations does. Files are moved to the trash after
+// confirmation, using Trasher, or DefaultTrasher if it's nil. Deleting isn't
+// supported when browsing an FS.
+func (m *Model) SetDeleteEnabled(v bool) {
+	m.KeyMap.Delete.SetEnabled(v)
+}
+
+// ConfirmingDelete returns whether the file picker is waiting for the user to
+// confirm a deletion.
+func (m Model) ConfirmingDelete() bool {
+	return m.confirm != confirmNone
+}
+
+// deleteFile returns a command which deletes a file, moving it to the trash
+// unless permanent is set.
+func (m Model) deleteFile(path string, permanent bool) tea.Cmd {
+	trasher := m.Trasher
+	if trasher == nil {
+		trasher = DefaultTrasher()
+	}
+	id := m.id
+	return func() tea.Msg {
+		msg := DeletedMsg{id: id, Path: path, Permanent: permanent}
+		if permanent {
+			if err := os.RemoveAll(path); err != nil {
+				msg.Err = fmt.Errorf("filepicker: %w", err)
+			}
+		} else {
+			msg.Err = trasher.Trash(path)
+		}
+		return msg
+	}
+}
+
+// confirmKind is the kind of deletion awaiting confirmation.
+type confirmKind int
+
+const (
+	confirmNone confirmKind = iota
+	confirmTrash
+	confirmPermanent
+)
+
+// updateDelete handles deletion keys and messages. It returns false if the
+// message should be handled normally.
+func (m *Model) updateDelete(msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case DeletedMsg:
+		if msg.id != m.id {
+			return nil, true
+		}
+		if msg.Err != nil && !msg.Permanent {
+			// Offer to unlink what couldn't be trashed.
+			m.confirm = confirmPermanent
+			m.confirmPath = msg.Path
+			m.confirmErr = msg.Err
+			return nil, true
+		}
+		return m.readDir(m.CurrentDirectory, m.ShowHidden), true
+	case tea.KeyMsg:
+		if m.confirm == confirmNone {
+			if !m.mutationKey(msg, m.KeyMap.Delete) || len(m.files) == 0 {
+				return nil, false
+			}
+			m.confirm = confirmTrash
+			m.confirmPath = m.join(m.CurrentDirectory, m.files[m.selected].Name())
+			m.confirmErr = nil
+			return nil, true
+		}
+
+		kind := m.confirm
+		switch {
+		case key.Matches(msg, m.KeyMap.Confirm):
+			m.confirm = confirmNone
+			return m.deleteFile(m.confirmPath, kind == confirmPermanent), true
+		case key.Matches(msg, m.KeyMap.Cancel):
+			m.confirm = confirmNone
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+// confirmView renders the prompt for a deletion awaiting confirmation.
+func (m Model) confirmView() string {
+	name := filepath.Base(m.confirmPath)
+	switch m.confirm {
+	case confirmTrash:
+		return m.Styles.DeletePrompt.Render(fmt.Sprintf("Move %s to the trash? (y/n)", name))
+	case confirmPermanent:
+		return m.Styles.DeletePrompt.Render(fmt.Sprintf("%v. Delete %s permanently? (y/n)", m.confirmErr, name))
+	}
+	return ""
+}
@@ -0,0 +1,47 @@
+//go:build darwin
+// +build darwin
+
+package filepicker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// osTrasher moves files to the user's trash in ~/.Trash.
+type osTrasher struct{}
+
+func (osTrasher) Trash(name string) error {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return fmt.Errorf("filepicker: %w", err)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ErrNoTrash
+	}
+	trash := filepath.Join(home, ".Trash")
+
+	// Like the Finder, number clashing names before the extension.
+	ext := filepath.Ext(abs)
+	stem := strings.TrimSuffix(filepath.Base(abs), ext)
+	target := filepath.Join(trash, stem+ext)
+	for i := 2; ; i++ {
+		if _, err := os.Lstat(target); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		target = filepath.Join(trash, fmt.Sprintf("%s %d%s", stem, i, ext))
+	}
+
+	if err := os.Rename(abs, target); err != nil {
+		if errors.Is(err, syscall.EXDEV) {
+			return ErrNoTrash
+		}
+		return fmt.Errorf("filepicker: %w", err)
+	}
+	return nil
+}
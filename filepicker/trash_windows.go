@@ -0,0 +1,50 @@
+//go:build windows
+// +build windows
+
+package filepicker
+
+import (
+	"fmt"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+var procSHFileOperationW = syscall.NewLazyDLL("shell32.dll").NewProc("SHFileOperationW")
+
+// Values for SHFileOperationW.
+const (
+	foDelete          = 0x3
+	fofSilent         = 0x4
+	fofNoConfirmation = 0x10
+	fofAllowUndo      = 0x40
+	fofNoErrorUI      = 0x400
+)
+
+// osTrasher moves files to the Recycle Bin.
+type osTrasher struct{}
+
+func (osTrasher) Trash(name string) error {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return fmt.Errorf("filepicker: %w", err)
+	}
+	// pFrom is a list of paths terminated by an empty one.
+	from, err := syscall.UTF16FromString(abs)
+	if err != nil {
+		return fmt.Errorf("filepicker: %w", err)
+	}
+	from = append(from, 0)
+	op := shFileOpStruct{
+		wFunc:  foDelete,
+		pFrom:  &from[0],
+		fFlags: fofAllowUndo | fofNoConfirmation | fofSilent | fofNoErrorUI,
+	}
+	if r, _, _ := procSHFileOperationW.Call(uintptr(unsafe.Pointer(&op))); r != 0 {
+		return fmt.Errorf("filepicker: moving %s to the Recycle Bin failed with code %#x", name, r)
+	}
+	if op.aborted() {
+		return ErrNoTrash
+	}
+	return nil
+}
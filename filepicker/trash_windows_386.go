@@ -0,0 +1,25 @@
+//go:build windows && 386
+// +build windows,386
+
+package filepicker
+
+import "encoding/binary"
+
+// shFileOpStruct mirrors SHFILEOPSTRUCTW, which is packed to 1 byte on
+// 32-bit Windows. The fields after fFlags are unaligned, so they're held as
+// bytes.
+type shFileOpStruct struct {
+	hwnd                  uintptr
+	wFunc                 uint32
+	pFrom                 *uint16
+	pTo                   *uint16
+	fFlags                uint16
+	fAnyOperationsAborted [4]byte
+	hNameMappings         [4]byte
+	lpszProgressTitle     [4]byte
+}
+
+// aborted returns whether the user or the system aborted the operation.
+func (op *shFileOpStruct) aborted() bool {
+	return binary.LittleEndian.Uint32(op.fAnyOperationsAborted[:]) != 0
+}
@@ -0,0 +1,22 @@
+//go:build windows && !386
+// +build windows,!386
+
+package filepicker
+
+// shFileOpStruct mirrors SHFILEOPSTRUCTW, which has natural alignment on
+// 64-bit Windows.
+type shFileOpStruct struct {
+	hwnd                  uintptr
+	wFunc                 uint32
+	pFrom                 *uint16
+	pTo                   *uint16
+	fFlags                uint16
+	fAnyOperationsAborted int32
+	hNameMappings         uintptr
+	lpszProgressTitle     *uint16
+}
+
+// aborted returns whether the user or the system aborted the operation.
+func (op *shFileOpStruct) aborted() bool {
+	return op.fAnyOperationsAborted != 0
+}
@@ -0,0 +1,25 @@
+//go:build windows
+// +build windows
+
+package filepicker
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestSHFileOpStructLayout(t *testing.T) {
+	var op shFileOpStruct
+	// Offsets of fAnyOperationsAborted and lpszProgressTitle in
+	// SHFILEOPSTRUCTW, which is packed on 32-bit Windows.
+	aborted, title := uintptr(18), uintptr(26)
+	if unsafe.Sizeof(uintptr(0)) == 8 {
+		aborted, title = 36, 48
+	}
+	if got := unsafe.Offsetof(op.fAnyOperationsAborted); got != aborted {
+		t.Errorf("expected fAnyOperationsAborted at %d, got %d", aborted, got)
+	}
+	if got := unsafe.Offsetof(op.lpszProgressTitle); got != title {
+		t.Errorf("expected lpszProgressTitle at %d, got %d", title, got)
+	}
+}
@@ -0,0 +1,80 @@
+//go:build !windows && !darwin
+// +build !windows,!darwin
+
+package filepicker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// renameFile moves files to the trash. It's replaced in tests.
+var renameFile = os.Rename
+
+// osTrasher implements the FreeDesktop.org trash specification, using the
+// home trash in $XDG_DATA_HOME/Trash.
+type osTrasher struct{}
+
+func (osTrasher) Trash(name string) error {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return fmt.Errorf("filepicker: %w", err)
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ErrNoTrash
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	files := filepath.Join(dataHome, "Trash", "files")
+	info := filepath.Join(dataHome, "Trash", "info")
+	for _, dir := range []string{files, info} {
+		if err := os.MkdirAll(dir, 0o700); err != nil { //nolint:mnd
+			return fmt.Errorf("filepicker: %w", err)
+		}
+	}
+
+	// The info file is created first, exclusively, to claim a name in the
+	// trash.
+	base := filepath.Base(abs)
+	for i := 1; ; i++ {
+		trashName := base
+		if i > 1 {
+			trashName = fmt.Sprintf("%s.%d", base, i)
+		}
+		infoPath := filepath.Join(info, trashName+".trashinfo")
+		f, err := os.OpenFile(infoPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600) //nolint:mnd
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("filepicker: %w", err)
+		}
+
+		_, err = fmt.Fprintf(f, "[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+			(&url.URL{Path: abs}).EscapedPath(), time.Now().Format("2006-01-02T15:04:05"))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err == nil {
+			err = renameFile(abs, filepath.Join(files, trashName))
+		}
+		if err != nil {
+			_ = os.Remove(infoPath)
+			if errors.Is(err, syscall.EXDEV) {
+				return ErrNoTrash
+			}
+			return fmt.Errorf("filepicker: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,67 @@
+//go:build !windows && !darwin
+// +build !windows,!darwin
+
+package filepicker
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestXDGTrash(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	dir := t.TempDir()
+	trash := filepath.Join(dataHome, "Trash")
+
+	path := writeFile(t, dir, "a b.txt", "first")
+	if err := DefaultTrasher().Trash(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected the file to be moved, got %v", err)
+	}
+	if b, err := os.ReadFile(filepath.Join(trash, "files", "a b.txt")); err != nil || string(b) != "first" {
+		t.Fatalf("expected the file in the trash, got %q, %v", b, err)
+	}
+	info, err := os.ReadFile(filepath.Join(trash, "info", "a b.txt.trashinfo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(info), "\n")
+	if lines[0] != "[Trash Info]" || lines[1] != "Path="+strings.ReplaceAll(path, " ", "%20") || !strings.HasPrefix(lines[2], "DeletionDate=") {
+		t.Errorf("unexpected trash info:\n%s", info)
+	}
+
+	// A file of the same name is numbered.
+	path = writeFile(t, dir, "a b.txt", "second")
+	if err := DefaultTrasher().Trash(path); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(filepath.Join(trash, "files", "a b.txt.2")); err != nil || string(b) != "second" {
+		t.Fatalf("expected the second file to be numbered, got %q, %v", b, err)
+	}
+	if _, err := os.Stat(filepath.Join(trash, "info", "a b.txt.2.trashinfo")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A file on another device than the trash can't be trashed.
+	renameFile = func(from, to string) error {
+		return &os.LinkError{Op: "rename", Old: from, New: to, Err: syscall.EXDEV}
+	}
+	t.Cleanup(func() { renameFile = os.Rename })
+	path = writeFile(t, dir, "c", "")
+	if err := DefaultTrasher().Trash(path); !errors.Is(err, ErrNoTrash) {
+		t.Fatalf("expected ErrNoTrash, got %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatal("expected the file to be kept")
+	}
+	if _, err := os.Stat(filepath.Join(trash, "info", "c.trashinfo")); !os.IsNotExist(err) {
+		t.Fatalf("expected the trash info to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package filepicker
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long the directory has to stay unchanged before it's
+// reread, so that a burst of changes, such as a copy of many files, rereads
+// it once.
+const watchDebounce = 100 * time.Millisecond
+
+// DirChangedMsg is sent when files are created, removed or renamed in the
+// current directory while it's being watched, before it's reread. See
+// Model.Watch.
+type DirChangedMsg struct {
+	id int
+
+	// Dir is the directory which changed.
+	Dir string
+}
+
+// WithWatch sets whether the current directory is watched for changes. See
+// Model.Watch.
+func WithWatch(v bool) Option {
+	return func(m *Model) {
+		m.Watch = v
+	}
+}
+
+// dirWatch watches the directory shown by a picker. It's shared between
+// copies of the model, so that the watch follows the picker as it moves
+// between directories.
+type dirWatch struct {
+	mu      sync.Mutex
+	watcher *fsnotify.Watcher
+	dir     string
+}
+
+// Close stops watching the current directory. The picker no longer follows
+// changes to it after it's closed, until Init starts watching it again.
+func (m Model) Close() error {
+	if m.watch == nil {
+		return nil
+	}
+	m.watch.mu.Lock()
+	defer m.watch.mu.Unlock()
+	if m.watch.watcher == nil {
+		return nil
+	}
+	err := m.watch.watcher.Close()
+	m.watch.watcher, m.watch.dir = nil, ""
+	return err //nolint:wrapcheck
+}
+
+// startWatch starts watching the current directory, returning a command
+// waiting for it to change. It returns nil if the picker isn't set to watch
+// it or the directory can't be watched.
+func (m Model) startWatch() tea.Cmd {
+	if !m.Watch || m.FS != nil || m.watch == nil {
+		return nil
+	}
+	m.watch.mu.Lock()
+	if m.watch.watcher == nil {
+		w, err := fsnotify.NewWatcher()
+		if err != nil {
+			m.watch.mu.Unlock()
+			return nil
+		}
+		m.watch.watcher = w
+	}
+	m.watch.mu.Unlock()
+	m.watchDir()
+	return m.waitForChange()
+}
+
+// watchDir moves the watch to the current directory.
+func (m Model) watchDir() {
+	if m.watch == nil {
+		return
+	}
+	m.watch.mu.Lock()
+	defer m.watch.mu.Unlock()
+	dir := filepath.Clean(m.CurrentDirectory)
+	if m.watch.watcher == nil || m.watch.dir == dir {
+		return
+	}
+	if m.watch.dir != "" {
+		_ = m.watch.watcher.Remove(m.watch.dir)
+	}
+	m.watch.dir = ""
+	if m.watch.watcher.Add(dir) == nil {
+		m.watch.dir = dir
+	}
+}
+
+// waitForChange returns a command waiting for files to be created, removed
+// or renamed in the watched directory, and for it to settle, returning a
+// DirChangedMsg.
+func (m Model) waitForChange() tea.Cmd {
+	id, watch := m.id, m.watch
+	if watch == nil {
+		return nil
+	}
+	watch.mu.Lock()
+	w := watch.watcher
+	watch.mu.Unlock()
+	if w == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		var settled <-chan time.Time
+		for {
+			select {
+			case event, ok := <-w.Events:
+				if !ok {
+					return nil
+				}
+				if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Remove) && !event.Has(fsnotify.Rename) {
+					continue
+				}
+				watch.mu.Lock()
+				dir := watch.dir
+				watch.mu.Unlock()
+				if filepath.Dir(event.Name) == dir {
+					settled = time.After(watchDebounce)
+				}
+			case _, ok := <-w.Errors:
+				if !ok {
+					return nil
+				}
+			case <-settled:
+				watch.mu.Lock()
+				dir := watch.dir
+				watch.mu.Unlock()
+				return DirChangedMsg{id: id, Dir: dir}
+			}
+		}
+	}
+}
+
+// updateWatch rereads the directory after it's changed, keeping the same
+// entry selected, and waits for the next change.
+func (m *Model) updateWatch(msg DirChangedMsg) tea.Cmd {
+	if msg.id != m.id {
+		return nil
+	}
+	next := m.waitForChange()
+	if msg.Dir != filepath.Clean(m.CurrentDirectory) {
+		return next
+	}
+	if m.selected >= 0 && m.selected < len(m.files) {
+		m.selectName = m.files[m.selected].Name()
+	}
+	return tea.Batch(m.readDir(m.CurrentDirectory, m.ShowHidden), next)
+}
@@ -2,11 +2,13 @@
 package help
 
 import (
+	"slices"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
 )
 
 // KeyMap is a map of keybindings used to generate help. Since it's an
@@ -27,6 +29,16 @@ type KeyMap interface {
 	FullHelp() [][]key.Binding
 }
 
+// GroupedKeyMap is a KeyMap whose bindings are divided into titled groups,
+// such as key.Groups. The full help shows each group as a column under its
+// title, in place of the columns of FullHelp.
+type GroupedKeyMap interface {
+	KeyMap
+
+	// HelpGroups returns the groups to show in the full help, in order.
+	HelpGroups() []key.Group
+}
+
 // Styles is a set of available style definitions for the Help bubble.
 type Styles struct {
 	Ellipsis lipgloss.Style
@@ -40,6 +52,12 @@ type Styles struct {
 	FullKey       lipgloss.Style
 	FullDesc      lipgloss.Style
 	FullSeparator lipgloss.Style
+	FullLongDesc  lipgloss.Style
+	FullTitle     lipgloss.Style
+
+	// Styling for the debug view
+	DebugSource   lipgloss.Style
+	DebugConflict lipgloss.Style
 }
 
 // Model contains the state of the help view.
@@ -54,6 +72,17 @@ type Model struct {
 	// due to width. Periods of ellipsis by default.
 	Ellipsis string
 
+	// MoreKey follows the ellipsis when short help items have been dropped,
+	// to hint at the key which shows the full help, such as "?". It's empty
+	// by default, in which case only the ellipsis is shown, as not every
+	// program binds a key to ShowAll.
+	MoreKey string
+
+	// Translator, if set, localizes the help text of the bindings, and the
+	// titles of groups, as they're rendered. Bindings with a translator of
+	// their own use that instead. See key.Binding.SetTranslator.
+	Translator key.Translator
+
 	Styles Styles
 }
 
@@ -86,6 +115,13 @@ func New() Model {
 			FullKey:        keyStyle,
 			FullDesc:       descStyle,
 			FullSeparator:  sepStyle,
+			FullLongDesc:   descStyle,
+			FullTitle:      keyStyle.Bold(true),
+			DebugSource:    lipgloss.NewStyle().Bold(true),
+			DebugConflict: lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{
+				Light: "#FF4672",
+				Dark:  "#ED567A",
+			}),
 		},
 	}
 }
@@ -103,62 +139,138 @@ func (m Model) Update(_ tea.Msg) (Model, tea.Cmd) {
 // View renders the help view's current state.
 func (m Model) View(k KeyMap) string {
 	if m.ShowAll {
+		if g, ok := k.(GroupedKeyMap); ok {
+			return m.GroupedHelpView(g.HelpGroups())
+		}
 		return m.FullHelpView(k.FullHelp())
 	}
 	return m.ShortHelpView(k.ShortHelp())
 }
 
+// RenderString renders the help view for the given keymap deterministically
+// at the given size, for golden-file tests. See the render package for
+// details.
+func RenderString(m Model, k KeyMap, width, height int) string {
+	m.Width = width
+	return render.View(func() string { return m.View(k) }, width, height)
+}
+
 // ShortHelpView renders a single line help view from a slice of keybindings.
-// If the line is longer than the maximum width it will be gracefully
-// truncated, showing only as many help items as possible.
+// If the line is longer than the maximum width, bindings are dropped, lowest
+// priority first, until it fits with the ellipsis and MoreKey appended. See
+// key.Help.Priority.
 func (m Model) ShortHelpView(bindings []key.Binding) string {
 	if len(bindings) == 0 {
 		return ""
 	}
 
-	var b strings.Builder
-	var totalWidth int
-	separator := m.Styles.ShortSeparator.Inline(true).Render(m.ShortSeparator)
-
-	for i, kb := range bindings {
+	var items []string
+	var priorities []int
+	for _, kb := range bindings {
 		if !kb.Enabled() {
 			continue
 		}
+		h := kb.TranslatedHelp(m.Translator)
+		items = append(items, m.Styles.ShortKey.Inline(true).Render(h.Key)+" "+
+			m.Styles.ShortDesc.Inline(true).Render(h.Desc))
+		priorities = append(priorities, h.Priority)
+	}
 
-		// Sep
-		var sep string
-		if totalWidth > 0 && i < len(bindings) {
-			sep = separator
+	separator := m.Styles.ShortSeparator.Inline(true).Render(m.ShortSeparator)
+	kept := make([]bool, len(items))
+	for i := range kept {
+		kept[i] = true
+	}
+	line := func() string {
+		var b strings.Builder
+		for i, item := range items {
+			if !kept[i] {
+				continue
+			}
+			if b.Len() > 0 {
+				b.WriteString(separator)
+			}
+			b.WriteString(item)
 		}
+		return b.String()
+	}
 
-		// Item
-		str := sep +
-			m.Styles.ShortKey.Inline(true).Render(kb.Help().Key) + " " +
-			m.Styles.ShortDesc.Inline(true).Render(kb.Help().Desc)
-		w := lipgloss.Width(str)
+	full := line()
+	if m.Width <= 0 || lipgloss.Width(full) <= m.Width {
+		return full
+	}
 
-		// Tail
-		if tail, ok := m.shouldAddItem(totalWidth, w); !ok {
-			if tail != "" {
-				b.WriteString(tail)
-			}
+	more := m.Ellipsis
+	if m.MoreKey != "" {
+		more += " " + m.MoreKey
+	}
+	tail := m.Styles.Ellipsis.Inline(true).Render(more)
+
+	// Drop the lowest priority bindings first and, among those, the last
+	// ones.
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		if priorities[a] != priorities[b] {
+			return priorities[a] - priorities[b]
+		}
+		return b - a
+	})
+	for _, i := range order {
+		kept[i] = false
+		if s := line(); s == "" {
 			break
+		} else if lipgloss.Width(s)+1+lipgloss.Width(tail) <= m.Width {
+			return s + " " + tail
 		}
-
-		totalWidth += w
-		b.WriteString(str)
 	}
-
-	return b.String()
+	if lipgloss.Width(tail) <= m.Width {
+		return tail
+	}
+	return ""
 }
 
 // FullHelpView renders help columns from a slice of key binding slices. Each
-// top level slice entry renders into a column.
+// top level slice entry renders into a column. Extended descriptions of
+// bindings, if any, are listed beneath the columns.
 func (m Model) FullHelpView(groups [][]key.Binding) string {
 	if len(groups) == 0 {
 		return ""
 	}
+	columns := m.fullHelpColumns(groups, nil)
+	if long := m.longHelpView(groups); long != "" {
+		return columns + "\n\n" + long
+	}
+	return columns
+}
+
+// GroupedHelpView renders help columns from groups of key bindings, each
+// under the group's title. Groups without enabled bindings are left out.
+// Extended descriptions of bindings, if any, are listed beneath the columns.
+func (m Model) GroupedHelpView(groups []key.Group) string {
+	if len(groups) == 0 {
+		return ""
+	}
+	bindings := make([][]key.Binding, len(groups))
+	titles := make([]string, len(groups))
+	for i, g := range groups {
+		bindings[i], titles[i] = g.Bindings(), g.Title()
+		if m.Translator != nil {
+			titles[i] = m.Translator.Translate(titles[i])
+		}
+	}
+	columns := m.fullHelpColumns(bindings, titles)
+	if long := m.longHelpView(bindings); long != "" {
+		return columns + "\n\n" + long
+	}
+	return columns
+}
 
+// fullHelpColumns renders the groups of bindings as columns, under the
+// titles, if any.
+func (m Model) fullHelpColumns(groups [][]key.Binding, titles []string) string {
 	// Linter note: at this time we don't think it's worth the additional
 	// code complexity involved in preallocating this slice.
 	//nolint:prealloc
@@ -190,17 +302,21 @@ func (m Model) FullHelpView(groups [][]key.Binding) string {
 			if !kb.Enabled() {
 				continue
 			}
-			keys = append(keys, kb.Help().Key)
-			descriptions = append(descriptions, kb.Help().Desc)
+			h := kb.TranslatedHelp(m.Translator)
+			keys = append(keys, h.Key)
+			descriptions = append(descriptions, h.Desc)
 		}
 
 		// Column
-		col := lipgloss.JoinHorizontal(lipgloss.Top,
-			sep,
+		body := lipgloss.JoinHorizontal(lipgloss.Top,
 			m.Styles.FullKey.Render(strings.Join(keys, "\n")),
 			" ",
 			m.Styles.FullDesc.Render(strings.Join(descriptions, "\n")),
 		)
+		if i < len(titles) {
+			body = lipgloss.JoinVertical(lipgloss.Left, m.Styles.FullTitle.Render(titles[i]), body)
+		}
+		col := lipgloss.JoinHorizontal(lipgloss.Top, sep, body)
 		w := lipgloss.Width(col)
 
 		// Tail
@@ -218,6 +334,38 @@ func (m Model) FullHelpView(groups [][]key.Binding) string {
 	return lipgloss.JoinHorizontal(lipgloss.Top, out...)
 }
 
+// longHelpView lists the extended descriptions of the enabled bindings, each
+// wrapped to the width of the help view and indented past the widest key.
+func (m Model) longHelpView(groups [][]key.Binding) string {
+	var helps []key.Help
+	var keyWidth int
+	for _, group := range groups {
+		for _, kb := range group {
+			if !kb.Enabled() {
+				continue
+			}
+			if h := kb.TranslatedHelp(m.Translator); h.Long != "" {
+				helps = append(helps, h)
+				keyWidth = max(keyWidth, lipgloss.Width(h.Key))
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(helps))
+	for _, h := range helps {
+		k := m.Styles.FullKey.Inline(true).Render(h.Key)
+		desc := m.Styles.FullLongDesc
+		if m.Width > 0 {
+			desc = desc.Width(max(1, m.Width-keyWidth-1))
+		}
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top,
+			k, strings.Repeat(" ", keyWidth-lipgloss.Width(h.Key)+1),
+			desc.Render(h.Long),
+		))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m Model) shouldAddItem(totalWidth, width int) (tail string, ok bool) {
 	// If there's room for an ellipsis, print that.
 	if m.Width > 0 && totalWidth+width > m.Width {
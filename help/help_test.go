@@ -2,11 +2,15 @@ package help
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/charmbracelet/x/exp/golden"
 
 	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
 )
 
 func TestFullHelp(t *testing.T) {
@@ -36,3 +40,234 @@ func TestFullHelp(t *testing.T) {
 		})
 	}
 }
+
+type renderKeyMap [][]key.Binding
+
+func (k renderKeyMap) ShortHelp() []key.Binding  { return k[0] }
+func (k renderKeyMap) FullHelp() [][]key.Binding { return k }
+
+func TestRenderString(t *testing.T) {
+	k := key.WithKeys("x")
+	km := renderKeyMap{
+		{
+			key.NewBinding(k, key.WithHelp("↑/k", "up")),
+			key.NewBinding(k, key.WithHelp("↓/j", "down")),
+		},
+		{
+			key.NewBinding(k, key.WithHelp("/", "filter")),
+			key.NewBinding(k, key.WithHelp("q", "quit")),
+		},
+	}
+
+	t.Run("short", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(New(), km, 40, 1)))
+	})
+	t.Run("short truncated", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(New(), km, 12, 1)))
+	})
+	t.Run("full", func(t *testing.T) {
+		m := New()
+		m.ShowAll = true
+		golden.RequireEqual(t, []byte(RenderString(m, km, 40, 2)))
+	})
+}
+
+type otherKeyMap []key.Binding
+
+func (k otherKeyMap) ShortHelp() []key.Binding  { return k }
+func (k otherKeyMap) FullHelp() [][]key.Binding { return [][]key.Binding{k} }
+
+func TestValidate(t *testing.T) {
+	nav := renderKeyMap{
+		{
+			key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+			key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+		},
+		{
+			key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+		},
+	}
+	app := otherKeyMap{
+		key.NewBinding(key.WithKeys("k"), key.WithHelp("k", "kill")),
+		key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "query"), key.WithDisabled()),
+		key.NewBinding(key.WithKeys("ctrl+c", "q"), key.WithHelp("ctrl+c", "quit")),
+	}
+
+	conflicts := Validate(nav, app)
+	if len(conflicts) != 2 {
+		t.Fatalf("expected 2 conflicts, got %v", conflicts)
+	}
+	if got, want := conflicts[0].String(), `"k" is bound to "up" (help.renderKeyMap) and "kill" (help.otherKeyMap)`; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if got, want := conflicts[1].String(), `"q" is bound to "quit" (help.renderKeyMap) and "quit" (help.otherKeyMap)`; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if c := Validate(nav); len(c) != 0 {
+		t.Errorf("expected no conflicts within a single keymap, got %v", c)
+	}
+
+	t.Run("debug view", func(t *testing.T) {
+		m := New()
+		golden.RequireEqual(t, []byte(render.View(func() string { return m.DebugView(nav, app) }, 80, 9)))
+	})
+}
+
+func TestLongHelp(t *testing.T) {
+	k := key.WithKeys("x")
+	km := renderKeyMap{
+		{
+			key.NewBinding(k, key.WithHelp("s", "cycle sort"),
+				key.WithLongHelp("Sort by CPU, memory, PID and command in turn.")),
+			key.NewBinding(k, key.WithHelp("q", "quit")),
+		},
+		{
+			key.NewBinding(k, key.WithHelp("ctrl+r", "refresh"),
+				key.WithLongHelp("Reload the list now instead of waiting for the next update.")),
+		},
+	}
+
+	m := New()
+	if got := m.View(km); got != m.ShortHelpView(km.ShortHelp()) || len(got) > 40 {
+		t.Fatalf("expected short help to leave out long descriptions, got %q", got)
+	}
+
+	m.ShowAll = true
+	golden.RequireEqual(t, []byte(RenderString(m, km, 40, 7)))
+}
+
+func TestModeMap(t *testing.T) {
+	modes := key.NewModeMap("normal")
+	modes.Add("normal", "insert", key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "insert")))
+	modes.Add("insert", "normal", key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "normal mode")))
+	modes.AddGlobal("quit", key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")))
+
+	m := New()
+	if got, want := ansi.Strip(m.View(modes)), "i insert • ctrl+c quit"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	modes.SetMode("insert")
+	if got, want := ansi.Strip(m.View(modes)), "esc normal mode • ctrl+c quit"; got != want {
+		t.Fatalf("expected help to follow the mode: want %q, got %q", want, got)
+	}
+}
+
+func TestGroupedHelp(t *testing.T) {
+	editing := key.NewGroup("Editing")
+	editing.Add("cut", key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "cut")))
+	editing.Add("paste", key.NewBinding(key.WithKeys("ctrl+v"), key.WithHelp("ctrl+v", "paste")))
+	general := key.NewGroup("General")
+	general.Add("quit", key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")))
+	groups := key.Groups{editing, general}
+
+	m := New()
+	m.ShowAll = true
+	golden.RequireEqual(t, []byte(RenderString(m, groups, 40, 3)))
+
+	groups.Group("Editing").SetEnabled(false)
+	if got := ansi.Strip(m.View(groups)); strings.Contains(got, "Editing") {
+		t.Fatalf("expected a disabled group to be left out, got %q", got)
+	}
+}
+
+func TestShortHelpPriority(t *testing.T) {
+	k := key.WithKeys("x")
+	bindings := []key.Binding{
+		key.NewBinding(k, key.WithHelp("↑", "up")),
+		key.NewBinding(k, key.WithHelp("↓", "down")),
+		key.NewBinding(k, key.WithHelp("/", "filter"), key.WithPriority(-1)),
+		key.NewBinding(k, key.WithHelp("q", "quit"), key.WithPriority(1)),
+	}
+
+	m := New()
+	if got, want := ansi.Strip(m.ShortHelpView(bindings)), "↑ up • ↓ down • / filter • q quit"; got != want {
+		t.Fatalf("expected every binding without a width, got %q", got)
+	}
+
+	m.Width = 26
+	if got, want := ansi.Strip(m.ShortHelpView(bindings)), "↑ up • ↓ down • q quit …"; got != want {
+		t.Fatalf("expected only the ellipsis by default: want %q, got %q", want, got)
+	}
+
+	m.MoreKey = "?"
+	if got, want := ansi.Strip(m.ShortHelpView(bindings)), "↑ up • ↓ down • q quit … ?"; got != want {
+		t.Fatalf("expected the lowest priority binding to be dropped: want %q, got %q", want, got)
+	}
+	m.Width = 20
+	if got, want := ansi.Strip(m.ShortHelpView(bindings)), "↑ up • q quit … ?"; got != want {
+		t.Fatalf("expected the last of the default priority bindings to be dropped: want %q, got %q", want, got)
+	}
+	m.Width = 3
+	if got, want := ansi.Strip(m.ShortHelpView(bindings)), "… ?"; got != want {
+		t.Fatalf("expected only the indicator: want %q, got %q", want, got)
+	}
+}
+
+func TestFromStruct(t *testing.T) {
+	var keys struct {
+		Up      key.Binding `key:"up,k" helpkey:"↑/k" help:"up" group:"Navigation"`
+		Down    key.Binding `key:"down,j" helpkey:"↓/j" help:"down" group:"Navigation"`
+		Save    key.Binding `key:"ctrl+s" help:"save" long:"Writes the file to disk." group:"File"`
+		Reload  key.Binding `key:"ctrl+r" help:"reload" short:"false" disabled:"true" group:"File"`
+		Ignored key.Binding
+	}
+	km, err := FromStruct(&keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")}, keys.Up) {
+		t.Fatal("expected the binding to match its keys")
+	}
+	if keys.Save.Help().Key != "ctrl+s" || keys.Save.Help().Long != "Writes the file to disk." {
+		t.Fatalf("unexpected help %+v", keys.Save.Help())
+	}
+	if keys.Ignored.Keys() != nil {
+		t.Fatal("expected fields without a key tag to be left alone")
+	}
+
+	m := New()
+	if got, want := ansi.Strip(m.View(km)), "↑/k up • ↓/j down • ctrl+s save"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	keys.Reload.SetEnabled(true)
+	m.ShowAll = true
+	golden.RequireEqual(t, []byte(RenderString(m, km, 40, 5)))
+
+	if _, err := FromStruct(keys); err == nil {
+		t.Fatal("expected an error for a struct that isn't a pointer")
+	}
+	var bad struct {
+		Quit string `key:"q"`
+	}
+	if _, err := FromStruct(&bad); err == nil {
+		t.Fatal("expected an error for a tagged field that isn't a binding")
+	}
+}
+
+func TestTranslator(t *testing.T) {
+	k := key.WithKeys("x")
+	editing := key.NewGroup("Editing")
+	editing.Add("cut", key.NewBinding(k, key.WithHelp("ctrl+x", "cut")))
+	editing.Add("up", key.NewBinding(k, key.WithHelp("↑/pgup", "page up"), key.WithLongHelp("Scrolls up.")))
+	groups := key.Groups{editing}
+
+	m := New()
+	m.Translator = key.Translations{
+		"Editing":     "Bearbeiten",
+		"cut":         "ausschneiden",
+		"page up":     "Seite hoch",
+		"pgup":        "Bild↑",
+		"Scrolls up.": "Blättert hoch.",
+	}
+	if got, want := ansi.Strip(m.View(groups)), "ctrl+x ausschneiden • ↑/Bild↑ Seite hoch"; got != want {
+		t.Fatalf("expected the short help to be translated: want %q, got %q", want, got)
+	}
+
+	m.ShowAll = true
+	got := ansi.Strip(m.View(groups))
+	for _, want := range []string{"Bearbeiten", "Seite hoch", "Blättert hoch."} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected the full help to contain %q, got %q", want, got)
+		}
+	}
+}
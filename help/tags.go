@@ -0,0 +1,200 @@
+package help
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mikeflynn/bubbles/key"
+)
+
+var bindingType = reflect.TypeOf(key.Binding{})
+
+// FromStruct sets up the key.Binding fields of the struct v points to from
+// their struct tags, and returns a KeyMap listing them for help, so that a
+// keymap is declared once for both matching and help:
+//
+//	type keyMap struct {
+//	    Up   key.Binding `key:"up,k" helpkey:"↑/k" help:"move up" group:"Navigation"`
+//	    Save key.Binding `key:"ctrl+s" help:"save" group:"File"`
+//	    Quit key.Binding `key:"q,ctrl+c" help:"quit" group:"File"`
+//	}
+//
+//	var keys keyMap
+//	km, err := help.FromStruct(&keys)
+//
+//	// Later on.
+//	key.Matches(msg, keys.Save)
+//	helpModel.View(km)
+//
+// These tags are recognized:
+//
+//   - key: the keys, separated by commas. Fields without it are left alone.
+//   - help: the description.
+//   - helpkey: the keys as shown in help. They're the keys separated by
+//     slashes by default.
+//   - long: the extended description. See key.WithLongHelp.
+//   - priority: the priority in short help. See key.Help.Priority.
+//   - short: "false" leaves the binding out of short help.
+//   - disabled: "true" disables the binding.
+//   - group: the title of the binding's group. If any binding has one, the
+//     KeyMap is a GroupedKeyMap, and full help shows each group as a column
+//     under its title, in the order they first appear. Otherwise it shows
+//     all the bindings in a single column.
+//
+// The KeyMap reads the fields each time help is rendered, so changes made to
+// them later, such as enabling or disabling bindings, show up in help.
+func FromStruct(v any) (KeyMap, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("help: FromStruct needs a pointer to a struct, got %T", v)
+	}
+	rv = rv.Elem()
+
+	var km structKeyMap
+	grouped := false
+	for i := range rv.NumField() {
+		f := rv.Type().Field(i)
+		if _, ok := f.Tag.Lookup("key"); !ok {
+			continue
+		}
+		if f.Type != bindingType {
+			return nil, fmt.Errorf("help: field %s has a key tag but isn't a key.Binding", f.Name)
+		}
+		if !f.IsExported() {
+			return nil, fmt.Errorf("help: field %s has a key tag but isn't exported", f.Name)
+		}
+
+		b, short, err := bindingFromTag(f)
+		if err != nil {
+			return nil, err
+		}
+		field := rv.Field(i).Addr().Interface().(*key.Binding)
+		*field = b
+
+		group, ok := f.Tag.Lookup("group")
+		grouped = grouped || ok
+		km.bindings = append(km.bindings, field)
+		km.short = append(km.short, short)
+		km.groups = append(km.groups, group)
+	}
+
+	if grouped {
+		return groupedStructKeyMap{km}, nil
+	}
+	return km, nil
+}
+
+// bindingFromTag returns the binding described by the tags of a field, and
+// whether it's shown in short help.
+func bindingFromTag(f reflect.StructField) (key.Binding, bool, error) {
+	keys := strings.Split(f.Tag.Get("key"), ",")
+	for i, k := range keys {
+		keys[i] = strings.TrimSpace(k)
+	}
+	helpKey, ok := f.Tag.Lookup("helpkey")
+	if !ok {
+		helpKey = strings.Join(keys, "/")
+	}
+	opts := []key.BindingOpt{
+		key.WithKeys(keys...),
+		key.WithHelp(helpKey, f.Tag.Get("help")),
+		key.WithLongHelp(f.Tag.Get("long")),
+	}
+
+	if s, ok := f.Tag.Lookup("priority"); ok {
+		p, err := strconv.Atoi(s)
+		if err != nil {
+			return key.Binding{}, false, fmt.Errorf("help: field %s has invalid priority %q", f.Name, s)
+		}
+		opts = append(opts, key.WithPriority(p))
+	}
+	if s, ok := f.Tag.Lookup("disabled"); ok {
+		disabled, err := strconv.ParseBool(s)
+		if err != nil {
+			return key.Binding{}, false, fmt.Errorf("help: field %s has invalid disabled tag %q", f.Name, s)
+		}
+		if disabled {
+			opts = append(opts, key.WithDisabled())
+		}
+	}
+	short := true
+	if s, ok := f.Tag.Lookup("short"); ok {
+		var err error
+		if short, err = strconv.ParseBool(s); err != nil {
+			return key.Binding{}, false, fmt.Errorf("help: field %s has invalid short tag %q", f.Name, s)
+		}
+	}
+	return key.NewBinding(opts...), short, nil
+}
+
+// structKeyMap is the KeyMap returned by FromStruct. It holds pointers to the
+// struct's fields, along with whether each is shown in short help and the
+// title of its group.
+type structKeyMap struct {
+	bindings []*key.Binding
+	short    []bool
+	groups   []string
+}
+
+// ShortHelp returns the bindings shown in short help, satisfying the KeyMap
+// interface.
+func (km structKeyMap) ShortHelp() []key.Binding {
+	var bindings []key.Binding
+	for i, b := range km.bindings {
+		if km.short[i] {
+			bindings = append(bindings, *b)
+		}
+	}
+	return bindings
+}
+
+// FullHelp returns all the bindings in a single column, satisfying the
+// KeyMap interface.
+func (km structKeyMap) FullHelp() [][]key.Binding {
+	bindings := make([]key.Binding, len(km.bindings))
+	for i, b := range km.bindings {
+		bindings[i] = *b
+	}
+	return [][]key.Binding{bindings}
+}
+
+// groupedStructKeyMap is the KeyMap returned by FromStruct when bindings
+// have a group tag.
+type groupedStructKeyMap struct {
+	structKeyMap
+}
+
+// FullHelp returns the bindings of each group in a column of its own,
+// satisfying the KeyMap interface.
+func (km groupedStructKeyMap) FullHelp() [][]key.Binding {
+	groups := km.HelpGroups()
+	columns := make([][]key.Binding, len(groups))
+	for i, g := range groups {
+		columns[i] = g.Bindings()
+	}
+	return columns
+}
+
+// HelpGroups returns the groups of bindings in the order they first appear,
+// satisfying the GroupedKeyMap interface.
+func (km groupedStructKeyMap) HelpGroups() []key.Group {
+	var titles []string
+	for _, t := range km.groups {
+		if !slices.Contains(titles, t) {
+			titles = append(titles, t)
+		}
+	}
+	groups := make([]key.Group, len(titles))
+	for i, t := range titles {
+		groups[i] = key.NewGroup(t)
+		for j, b := range km.bindings {
+			if km.groups[j] == t {
+				groups[i].Add(strconv.Itoa(j), *b)
+			}
+		}
+	}
+	return groups
+}
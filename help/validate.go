@@ -0,0 +1,157 @@
+package help
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/key"
+)
+
+// Source is a key binding along with the KeyMap it was found in.
+type Source struct {
+	Binding key.Binding
+
+	// KeyMap is the index of the KeyMap among those given to Validate or
+	// DebugView, and Name is its type name, such as "list.KeyMap".
+	KeyMap int
+	Name   string
+}
+
+// Conflict is a key bound to more than one enabled binding.
+type Conflict struct {
+	Key      string
+	Bindings []Source
+}
+
+// String returns a description of the conflict such as
+// `"q" is bound to "quit" (list.KeyMap) and "query" (main.keyMap)`. The
+// descriptions are translated by the bindings' own translators, if any.
+func (c Conflict) String() string {
+	descs := make([]string, len(c.Bindings))
+	for i, s := range c.Bindings {
+		descs[i] = fmt.Sprintf("%q (%s)", s.Binding.TranslatedHelp(nil).Desc, s.Name)
+	}
+	last := len(descs) - 1
+	return fmt.Sprintf("%q is bound to %s and %s", c.Key, strings.Join(descs[:last], ", "), descs[last])
+}
+
+// Validate reports the keys bound to more than one enabled binding across
+// the given keymaps, which is useful to audit keymaps composed from several
+// bubbles. Bindings are gathered from both ShortHelp and FullHelp, so a
+// binding listed in both is only counted once. Conflicts are sorted by key.
+func Validate(keymaps ...KeyMap) []Conflict {
+	byKey := make(map[string][]Source)
+	for _, s := range sources(keymaps) {
+		if !s.Binding.Enabled() {
+			continue
+		}
+		for _, k := range s.Binding.Keys() {
+			byKey[k] = append(byKey[k], s)
+		}
+	}
+
+	var conflicts []Conflict
+	for k, bindings := range byKey {
+		if len(bindings) > 1 {
+			conflicts = append(conflicts, Conflict{Key: k, Bindings: bindings})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Key < conflicts[j].Key
+	})
+	return conflicts
+}
+
+// DebugView renders every binding of the given keymaps under the name of the
+// KeyMap it comes from, along with the keys it's bound to. Keys that
+// conflict, as reported by Validate, are highlighted and annotated with the
+// other keymaps they're bound in. Unlike View it includes disabled bindings,
+// which are marked as such.
+func (m Model) DebugView(keymaps ...KeyMap) string {
+	conflicts := make(map[string]Conflict)
+	for _, c := range Validate(keymaps...) {
+		conflicts[c.Key] = c
+	}
+
+	srcs := sources(keymaps)
+	var helpWidth, descWidth int
+	for _, s := range srcs {
+		h := s.Binding.TranslatedHelp(m.Translator)
+		helpWidth = max(helpWidth, lipgloss.Width(h.Key))
+		descWidth = max(descWidth, lipgloss.Width(h.Desc))
+	}
+
+	var b strings.Builder
+	for i, s := range srcs {
+		if i == 0 || s.KeyMap != srcs[i-1].KeyMap {
+			if i > 0 {
+				b.WriteRune('\n')
+			}
+			b.WriteString(m.Styles.DebugSource.Render(s.Name) + "\n")
+		}
+
+		h := s.Binding.TranslatedHelp(m.Translator)
+		b.WriteString("  " + m.Styles.FullKey.Width(helpWidth).Render(h.Key) +
+			"  " + m.Styles.FullDesc.Width(descWidth).Render(h.Desc) + "  ")
+
+		keys := make([]string, len(s.Binding.Keys()))
+		var others []string
+		for j, k := range s.Binding.Keys() {
+			c, ok := conflicts[k]
+			if !ok || !s.Binding.Enabled() {
+				keys[j] = m.Styles.FullKey.Render(k)
+				continue
+			}
+			keys[j] = m.Styles.DebugConflict.Render(k)
+			for _, o := range c.Bindings {
+				if o.KeyMap != s.KeyMap || !slices.Equal(o.Binding.Keys(), s.Binding.Keys()) || o.Binding.TranslatedHelp(m.Translator) != h {
+					others = append(others, fmt.Sprintf("%s: %q in %s", k, o.Binding.TranslatedHelp(m.Translator).Desc, o.Name))
+				}
+			}
+		}
+		b.WriteString(strings.Join(keys, m.Styles.FullSeparator.Render(", ")))
+		if !s.Binding.Enabled() {
+			b.WriteString(m.Styles.FullDesc.Render(" (disabled)"))
+		}
+		if len(others) > 0 {
+			b.WriteString(m.Styles.DebugConflict.Render(" ! also " + strings.Join(others, "; ")))
+		}
+		b.WriteRune('\n')
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// sources returns the bindings of the given keymaps in the order they appear
+// in their help, skipping those with no keys and those already listed for
+// the same KeyMap.
+func sources(keymaps []KeyMap) []Source {
+	var out []Source
+	for i, km := range keymaps {
+		name := fmt.Sprintf("%T", km)
+		var seen []key.Binding
+		add := func(kb key.Binding) {
+			if len(kb.Keys()) == 0 {
+				return
+			}
+			for _, s := range seen {
+				if slices.Equal(s.Keys(), kb.Keys()) && s.TranslatedHelp(nil) == kb.TranslatedHelp(nil) {
+					return
+				}
+			}
+			seen = append(seen, kb)
+			out = append(out, Source{Binding: kb, KeyMap: i, Name: name})
+		}
+		for _, kb := range km.ShortHelp() {
+			add(kb)
+		}
+		for _, group := range km.FullHelp() {
+			for _, kb := range group {
+				add(kb)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,566 @@
+// Package history provides a command history browser for Bubble Tea
+// applications. It loads shell-style history files, ranks entries by how often
+// and how recently they were used, supports fuzzy searching and pinning, and
+// returns the chosen entry.
+package history
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mikeflynn/bubbles/cursor"
+	"github.com/mikeflynn/bubbles/help"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
+	"github.com/mikeflynn/bubbles/textinput"
+	"github.com/mikeflynn/bubbles/textutil"
+	"github.com/sahilm/fuzzy"
+)
+
+// Entry is a unique command in the history.
+type Entry struct {
+	Command string
+
+	// Count is the number of times the command appears in the history.
+	Count int
+
+	// Last is the position of the command's most recent use, where higher
+	// numbers are more recent.
+	Last int
+
+	// Pinned entries are always listed first.
+	Pinned bool
+}
+
+// SelectedMsg is sent when the user chooses an entry.
+type SelectedMsg struct {
+	Command string
+}
+
+// CancelledMsg is sent when the user leaves the browser without choosing an
+// entry.
+type CancelledMsg struct{}
+
+// loadedMsg is sent when a history file has been read.
+type loadedMsg struct {
+	lines []string
+	err   error
+}
+
+// LoadFile reads a history file and returns its commands, oldest first. Both
+// plain, one command per line files such as bash's and zsh's extended format
+// are supported.
+func LoadFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	var lines []string
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1024*1024) //nolint:mnd
+	for s.Scan() {
+		l := s.Text()
+
+		// zsh extended history: ": <timestamp>:<duration>;<command>".
+		if strings.HasPrefix(l, ": ") {
+			if i := strings.IndexByte(l, ';'); i >= 0 {
+				l = l[i+1:]
+			}
+		}
+		// bash timestamps: "#<timestamp>" on its own line.
+		if strings.HasPrefix(l, "#") && isDigits(l[1:]) {
+			continue
+		}
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		lines = append(lines, l)
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	return lines, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// KeyMap is the key bindings for moving through the history, pinning entries
+// and choosing one. It satisfies the help.KeyMap interface.
+type KeyMap struct {
+	CursorUp   key.Binding
+	CursorDown key.Binding
+	Select     key.Binding
+	TogglePin  key.Binding
+	Cancel     key.Binding
+}
+
+// ShortHelp implements the KeyMap interface.
+func (km KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{km.CursorUp, km.CursorDown, km.Select, km.TogglePin, km.Cancel}
+}
+
+// FullHelp implements the KeyMap interface.
+func (km KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{km.CursorUp, km.CursorDown},
+		{km.Select, km.TogglePin, km.Cancel},
+	}
+}
+
+// DefaultKeyMap returns a default set of keybindings. Since typing goes to the
+// search input, none of them use printable characters.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		CursorUp: key.NewBinding(
+			key.WithKeys("up", "ctrl+r", "ctrl+k"),
+			key.WithHelp("↑/ctrl+r", "older"),
+		),
+		CursorDown: key.NewBinding(
+			key.WithKeys("down", "ctrl+s", "ctrl+j"),
+			key.WithHelp("↓/ctrl+s", "newer"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "select"),
+		),
+		TogglePin: key.NewBinding(
+			key.WithKeys("ctrl+p"),
+			key.WithHelp("ctrl+p", "pin"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc", "ctrl+c", "ctrl+g"),
+			key.WithHelp("esc", "cancel"),
+		),
+	}
+}
+
+// Styles are the styles of the history browser's search line and entries,
+// as returned by DefaultStyles by default.
+type Styles struct {
+	Prompt      lipgloss.Style
+	Entry       lipgloss.Style
+	Selected    lipgloss.Style
+	Match       lipgloss.Style
+	Pin         lipgloss.Style
+	Count       lipgloss.Style
+	Status      lipgloss.Style
+	HelpSection lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this
+// component.
+func DefaultStyles() Styles {
+	subdued := lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}
+
+	return Styles{
+		Prompt:      lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#F793FF", Dark: "#AD58B4"}),
+		Entry:       lipgloss.NewStyle().PaddingLeft(2),                                              //nolint:mnd
+		Selected:    lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Bold(true).PaddingLeft(2), //nolint:mnd
+		Match:       lipgloss.NewStyle().Underline(true),
+		Pin:         lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#E8A317", Dark: "#ECFD65"}),
+		Count:       lipgloss.NewStyle().Foreground(subdued),
+		Status:      lipgloss.NewStyle().Foreground(subdued),
+		HelpSection: lipgloss.NewStyle().PaddingTop(1),
+	}
+}
+
+// match is a visible entry along with the indices of the runes which matched
+// the search query.
+type match struct {
+	entry   int
+	matches []int
+}
+
+// Model is the state of a command history browser.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+	Help   help.Model
+	Input  textinput.Model
+
+	// ShowHelp determines whether the help view is rendered beneath the
+	// entries.
+	ShowHelp bool
+
+	// ShowCounts determines whether the number of times each command has
+	// been used is shown.
+	ShowCounts bool
+
+	// PinIndicator is shown before pinned entries.
+	PinIndicator string
+
+	// Err is set if loading a history file fails.
+	Err error
+
+	entries []Entry
+	visible []match
+	cursor  int
+	offset  int
+
+	width  int
+	height int
+}
+
+// New returns a model for the given commands, oldest first.
+func New(commands []string) Model {
+	input := textinput.New()
+	input.Prompt = "history> "
+	input.Focus()
+
+	m := Model{
+		KeyMap:       DefaultKeyMap(),
+		Styles:       DefaultStyles(),
+		Help:         help.New(),
+		Input:        input,
+		ShowHelp:     true,
+		ShowCounts:   true,
+		PinIndicator: "★ ",
+	}
+	m.Input.PromptStyle = m.Styles.Prompt
+	m.SetCommands(commands)
+	return m
+}
+
+// Load returns a command which reads the history file at the given path and
+// loads it into the model.
+func (m Model) Load(path string) tea.Cmd {
+	return func() tea.Msg {
+		lines, err := LoadFile(path)
+		return loadedMsg{lines: lines, err: err}
+	}
+}
+
+// SetCommands replaces the history with the given commands, oldest first.
+// Duplicate commands are merged into a single entry. Pins are kept for
+// commands which are still present.
+func (m *Model) SetCommands(commands []string) {
+	pinned := map[string]bool{}
+	for _, e := range m.entries {
+		if e.Pinned {
+			pinned[e.Command] = true
+		}
+	}
+
+	index := map[string]int{}
+	m.entries = nil
+	for i, c := range commands {
+		if j, ok := index[c]; ok {
+			m.entries[j].Count++
+			m.entries[j].Last = i
+			continue
+		}
+		index[c] = len(m.entries)
+		m.entries = append(m.entries, Entry{Command: c, Count: 1, Last: i, Pinned: pinned[c]})
+	}
+
+	m.refresh()
+}
+
+// Entries returns the entries in ranked order, ignoring any search.
+func (m Model) Entries() []Entry {
+	entries := append([]Entry(nil), m.entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return m.less(entries[i], entries[j])
+	})
+	return entries
+}
+
+// Pinned returns the pinned commands, so they can be persisted.
+func (m Model) Pinned() []string {
+	var pinned []string
+	for _, e := range m.entries {
+		if e.Pinned {
+			pinned = append(pinned, e.Command)
+		}
+	}
+	sort.Strings(pinned)
+	return pinned
+}
+
+// SetPinned pins the given commands and unpins all others. Commands which
+// aren't in the history are added to it.
+func (m *Model) SetPinned(commands []string) {
+	pinned := map[string]bool{}
+	for _, c := range commands {
+		pinned[c] = true
+	}
+	for i := range m.entries {
+		m.entries[i].Pinned = pinned[m.entries[i].Command]
+		delete(pinned, m.entries[i].Command)
+	}
+	for _, c := range commands {
+		if pinned[c] {
+			m.entries = append(m.entries, Entry{Command: c, Last: -1, Pinned: true})
+			delete(pinned, c)
+		}
+	}
+	m.refresh()
+}
+
+// Selected returns the entry under the cursor.
+func (m Model) Selected() (Entry, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.visible) {
+		return Entry{}, false
+	}
+	return m.entries[m.visible[m.cursor].entry], true
+}
+
+// SetSize sets the dimensions of the component.
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.Help.Width = width
+	m.Input.Width = max(0, width-lipgloss.Width(m.Input.Prompt)-1)
+	m.updateOffset()
+}
+
+// frecency scores an entry by how often and how recently it was used.
+func (m Model) frecency(e Entry) float64 {
+	recency := 0.0
+	if n := len(m.entries); n > 1 && e.Last >= 0 {
+		recency = float64(e.Last) / float64(n-1)
+	}
+	return math.Log1p(float64(e.Count)) + 2*recency //nolint:mnd
+}
+
+// less orders entries with pinned entries first, then by frecency.
+func (m Model) less(a, b Entry) bool {
+	if a.Pinned != b.Pinned {
+		return a.Pinned
+	}
+	return m.frecency(a) > m.frecency(b)
+}
+
+// refresh rebuilds the visible entries from the search query.
+func (m *Model) refresh() {
+	m.visible = nil
+
+	q := m.Input.Value()
+	if q == "" {
+		for i := range m.entries {
+			m.visible = append(m.visible, match{entry: i})
+		}
+		sort.SliceStable(m.visible, func(i, j int) bool {
+			return m.less(m.entries[m.visible[i].entry], m.entries[m.visible[j].entry])
+		})
+	} else {
+		targets := make([]string, len(m.entries))
+		for i, e := range m.entries {
+			targets[i] = e.Command
+		}
+		ranks := fuzzy.Find(q, targets)
+		for _, r := range ranks {
+			m.visible = append(m.visible, match{entry: r.Index, matches: r.MatchedIndexes})
+		}
+
+		// Pinned entries float to the top; otherwise matches are ordered by
+		// match quality, with frecency breaking ties.
+		scores := make(map[int]int, len(ranks))
+		for _, r := range ranks {
+			scores[r.Index] = r.Score
+		}
+		sort.SliceStable(m.visible, func(i, j int) bool {
+			a, b := m.entries[m.visible[i].entry], m.entries[m.visible[j].entry]
+			if a.Pinned != b.Pinned {
+				return a.Pinned
+			}
+			if sa, sb := scores[m.visible[i].entry], scores[m.visible[j].entry]; sa != sb {
+				return sa > sb
+			}
+			return m.frecency(a) > m.frecency(b)
+		})
+	}
+
+	m.cursor = clamp(m.cursor, 0, max(0, len(m.visible)-1))
+	m.updateOffset()
+}
+
+// listHeight returns the number of lines available for entries.
+func (m Model) listHeight() int {
+	h := m.height - 2 // input and status line
+	if m.ShowHelp {
+		h -= lipgloss.Height(m.helpView())
+	}
+	return max(0, h)
+}
+
+func (m *Model) updateOffset() {
+	h := m.listHeight()
+	if h == 0 {
+		m.offset = 0
+		return
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+h {
+		m.offset = m.cursor - h + 1
+	}
+}
+
+// Init exists to satisfy the tea.Model interface.
+func (m Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.Err = msg.err
+		if msg.err == nil {
+			m.SetCommands(msg.lines)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.CursorUp):
+			m.cursor = clamp(m.cursor+1, 0, max(0, len(m.visible)-1))
+			m.updateOffset()
+			return m, nil
+		case key.Matches(msg, m.KeyMap.CursorDown):
+			m.cursor = clamp(m.cursor-1, 0, max(0, len(m.visible)-1))
+			m.updateOffset()
+			return m, nil
+		case key.Matches(msg, m.KeyMap.TogglePin):
+			if len(m.visible) > 0 {
+				e := &m.entries[m.visible[m.cursor].entry]
+				e.Pinned = !e.Pinned
+				cmd := e.Command
+				m.refresh()
+				m.selectCommand(cmd)
+			}
+			return m, nil
+		case key.Matches(msg, m.KeyMap.Select):
+			e, ok := m.Selected()
+			if !ok {
+				return m, nil
+			}
+			return m, func() tea.Msg {
+				return SelectedMsg{Command: e.Command}
+			}
+		case key.Matches(msg, m.KeyMap.Cancel):
+			return m, func() tea.Msg {
+				return CancelledMsg{}
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	prev := m.Input.Value()
+	m.Input, cmd = m.Input.Update(msg)
+	if m.Input.Value() != prev {
+		m.cursor = 0
+		m.refresh()
+	}
+	return m, cmd
+}
+
+func (m *Model) selectCommand(command string) {
+	for i, v := range m.visible {
+		if m.entries[v.entry].Command == command {
+			m.cursor = i
+			m.updateOffset()
+			return
+		}
+	}
+}
+
+// View renders the component. Like shell history search, the best match is
+// shown at the bottom, closest to the input, and older entries stack above it.
+func (m Model) View() string {
+	height := m.listHeight()
+	end := min(len(m.visible), m.offset+height)
+
+	lines := make([]string, 0, height+2) //nolint:mnd
+	for i := end - 1; i >= m.offset; i-- {
+		lines = append(lines, m.entryView(i))
+	}
+	for len(lines) < height {
+		lines = append([]string{""}, lines...)
+	}
+
+	status := fmt.Sprintf("%d/%d", len(m.visible), len(m.entries))
+	if m.Err != nil {
+		status = m.Err.Error()
+	}
+	lines = append(lines, m.Styles.Status.Render(m.truncate(status)), m.Input.View())
+	if m.ShowHelp {
+		lines = append(lines, m.helpView())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RenderString renders the history browser deterministically at the given
+// size, for golden-file tests. The cursor is drawn without blinking. See the
+// render package for details.
+func RenderString(m Model, width, height int) string {
+	m.SetSize(width, height)
+	m.Input.Cursor.SetMode(cursor.CursorStatic)
+	return render.View(m.View, width, height)
+}
+
+func (m Model) entryView(i int) string {
+	v := m.visible[i]
+	e := m.entries[v.entry]
+
+	style := m.Styles.Entry
+	if i == m.cursor {
+		style = m.Styles.Selected
+	}
+
+	var prefix string
+	if e.Pinned {
+		prefix = m.Styles.Pin.Render(m.PinIndicator)
+	}
+	var suffix string
+	if m.ShowCounts && e.Count > 1 {
+		suffix = " " + m.Styles.Count.Render(fmt.Sprintf("×%d", e.Count))
+	}
+
+	avail := m.width - style.GetHorizontalFrameSize() - ansi.StringWidth(prefix) - ansi.StringWidth(suffix)
+	command := e.Command
+	if m.width > 0 {
+		command = textutil.Truncate(command, avail, textutil.Ellipsis)
+	}
+	if len(v.matches) > 0 {
+		unmatched := style.Inline(true).UnsetPadding()
+		command = lipgloss.StyleRunes(command, v.matches, unmatched.Inherit(m.Styles.Match), unmatched)
+	}
+	return style.Render(prefix + command + suffix)
+}
+
+func (m Model) truncate(s string) string {
+	if m.width <= 0 {
+		return s
+	}
+	return textutil.Truncate(s, m.width, textutil.Ellipsis)
+}
+
+func (m Model) helpView() string {
+	return m.Styles.HelpSection.Render(m.Help.View(m.KeyMap))
+}
+
+func clamp(v, low, high int) int {
+	return min(max(v, low), high)
+}
@@ -0,0 +1,111 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+func commands(entries []Entry) []string {
+	var c []string
+	for _, e := range entries {
+		c = append(c, e.Command)
+	}
+	return c
+}
+
+func TestLoadFile(t *testing.T) {
+	for file, want := range map[string][]string{
+		"testdata/zsh_history":  {"git status", "make", "git status"},
+		"testdata/bash_history": {"ls -la", "cd /tmp", "ls -la", "go test ./..."},
+	} {
+		got, err := LoadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %q, got %q", file, want, got)
+		}
+	}
+
+	if _, err := LoadFile("testdata/missing"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestRanking(t *testing.T) {
+	m := New([]string{"make", "ls", "git push", "ls", "ls", "vim"})
+
+	got := commands(m.Entries())
+	want := []string{"ls", "vim", "git push", "make"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected entries ranked by frecency %q, got %q", want, got)
+	}
+
+	m.SetPinned([]string{"make"})
+	if got := commands(m.Entries()); got[0] != "make" {
+		t.Fatalf("expected pinned entry first, got %q", got)
+	}
+
+	// Pins survive reloading the history.
+	m.SetCommands([]string{"make", "ls"})
+	if got := m.Pinned(); !reflect.DeepEqual(got, []string{"make"}) {
+		t.Fatalf("expected pin to be kept, got %q", got)
+	}
+}
+
+func TestSearchAndSelect(t *testing.T) {
+	m := New([]string{"git status", "go test ./...", "git push", "ls"})
+
+	for _, r := range "gp" {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if e, _ := m.Selected(); e.Command != "git push" {
+		t.Fatalf("expected best match to be selected, got %q", e.Command)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if msg, ok := cmd().(SelectedMsg); !ok || msg.Command != "git push" {
+		t.Fatalf("expected SelectedMsg for git push, got %#v", cmd())
+	}
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if _, ok := cmd().(CancelledMsg); !ok {
+		t.Fatalf("expected CancelledMsg, got %#v", cmd())
+	}
+}
+
+func TestTogglePin(t *testing.T) {
+	m := New([]string{"a", "b", "c"})
+
+	// Move to the oldest entry and pin it.
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+
+	if got := m.Pinned(); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Fatalf("expected a to be pinned, got %q", got)
+	}
+	if e, _ := m.Selected(); e.Command != "a" {
+		t.Fatalf("expected cursor to follow the pinned entry, got %q", e.Command)
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	cmds := []string{"git status", "go test ./...", "git push", "ls", "git status"}
+
+	t.Run("all", func(t *testing.T) {
+		m := New(cmds)
+		m.ShowHelp = false
+		golden.RequireEqual(t, []byte(RenderString(m, 30, 6)))
+	})
+	t.Run("search", func(t *testing.T) {
+		m := New(cmds)
+		m.ShowHelp = false
+		m.SetPinned([]string{"ls"})
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("gi")})
+		golden.RequireEqual(t, []byte(RenderString(m, 30, 6)))
+	})
+}
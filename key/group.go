@@ -0,0 +1,181 @@
+package key
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Group is a titled set of related keybindings, such as all of an editor's
+// editing keys, which are enabled and disabled together. Bindings are added
+// under a name, like those of a ModeMap:
+//
+//	editing := key.NewGroup("Editing")
+//	editing.Add("cut", key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "cut")))
+//	editing.Add("paste", key.NewBinding(key.WithKeys("ctrl+v"), key.WithHelp("ctrl+v", "paste")))
+//
+//	// Entering a read-only mode.
+//	editing.SetEnabled(false)
+//
+// Group satisfies the help.KeyMap interface, and Groups, a list of groups,
+// shows each group as a titled column in the help bubble's full help.
+type Group struct {
+	title    string
+	bindings []NamedBinding
+}
+
+// NewGroup returns a group with the given title and bindings.
+func NewGroup(title string, bindings ...NamedBinding) Group {
+	g := Group{title: title}
+	for _, b := range bindings {
+		g.Add(b.Name, b.Binding)
+	}
+	return g
+}
+
+// Title returns the group's title.
+func (g Group) Title() string {
+	return g.title
+}
+
+// Add adds a binding to the group under the given name, replacing any binding
+// already added with that name.
+func (g *Group) Add(name string, b Binding) {
+	if i := g.index(name); i >= 0 {
+		g.bindings[i].Binding = b
+		return
+	}
+	g.bindings = append(g.bindings, NamedBinding{Name: name, Binding: b})
+}
+
+// Binding returns the binding with the given name. It returns a pointer, so
+// that the binding can be changed in place, or nil if there's no such
+// binding.
+func (g *Group) Binding(name string) *Binding {
+	if i := g.index(name); i >= 0 {
+		return &g.bindings[i].Binding
+	}
+	return nil
+}
+
+func (g Group) index(name string) int {
+	for i, b := range g.bindings {
+		if b.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetEnabled enables or disables all of the group's bindings at once.
+func (g *Group) SetEnabled(v bool) {
+	for i := range g.bindings {
+		g.bindings[i].Binding.SetEnabled(v)
+	}
+}
+
+// Enabled reports whether any of the group's bindings is enabled.
+func (g Group) Enabled() bool {
+	for _, b := range g.bindings {
+		if b.Binding.Enabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns the name of the enabled binding which matches the given key,
+// or an empty string if there's none.
+func (g Group) Match(k fmt.Stringer) string {
+	for _, b := range g.bindings {
+		if Matches(k, b.Binding) {
+			return b.Name
+		}
+	}
+	return ""
+}
+
+// Matches reports whether the given key matches one of the named bindings.
+func (g Group) Matches(k fmt.Stringer, names ...string) bool {
+	name := g.Match(k)
+	return name != "" && slices.Contains(names, name)
+}
+
+// Named returns the group's bindings along with their names, in the order
+// they were added.
+func (g Group) Named() []NamedBinding {
+	return slices.Clone(g.bindings)
+}
+
+// Bindings returns the group's bindings, in the order they were added.
+func (g Group) Bindings() []Binding {
+	bindings := make([]Binding, len(g.bindings))
+	for i, b := range g.bindings {
+		bindings[i] = b.Binding
+	}
+	return bindings
+}
+
+// ShortHelp returns the group's bindings, satisfying the help.KeyMap
+// interface.
+func (g Group) ShortHelp() []Binding {
+	return g.Bindings()
+}
+
+// FullHelp returns the group's bindings in a single column, satisfying the
+// help.KeyMap interface.
+func (g Group) FullHelp() [][]Binding {
+	return [][]Binding{g.Bindings()}
+}
+
+// Groups is a keymap divided into groups. It satisfies the help.KeyMap
+// interface, and the help bubble shows each group as a column under its
+// title in the full help.
+type Groups []Group
+
+// Group returns the group with the given title, so that it can be changed in
+// place, or nil if there's no such group.
+func (gs Groups) Group(title string) *Group {
+	for i := range gs {
+		if gs[i].title == title {
+			return &gs[i]
+		}
+	}
+	return nil
+}
+
+// Match returns the title of the group and the name of the enabled binding
+// which matches the given key, or empty strings if there's none. Groups are
+// checked in order.
+func (gs Groups) Match(k fmt.Stringer) (group, name string) {
+	for _, g := range gs {
+		if name := g.Match(k); name != "" {
+			return g.title, name
+		}
+	}
+	return "", ""
+}
+
+// ShortHelp returns the bindings of all groups, satisfying the help.KeyMap
+// interface.
+func (gs Groups) ShortHelp() []Binding {
+	var bindings []Binding
+	for _, g := range gs {
+		bindings = append(bindings, g.Bindings()...)
+	}
+	return bindings
+}
+
+// FullHelp returns the bindings of each group in a column of its own,
+// satisfying the help.KeyMap interface.
+func (gs Groups) FullHelp() [][]Binding {
+	columns := make([][]Binding, len(gs))
+	for i, g := range gs {
+		columns[i] = g.Bindings()
+	}
+	return columns
+}
+
+// HelpGroups returns the groups, satisfying the help.GroupedKeyMap interface.
+func (gs Groups) HelpGroups() []Group {
+	return gs
+}
@@ -44,6 +44,12 @@ type Binding struct {
 	keys     []string
 	help     Help
 	disabled bool
+
+	// normalized holds the keys as Normalize returns them, for matching.
+	normalized []string
+
+	// translator localizes the help text. See SetTranslator.
+	translator Translator
 }
 
 // BindingOpt is an initialization option for a keybinding. It's used as an
@@ -62,14 +68,30 @@ func NewBinding(opts ...BindingOpt) Binding {
 // WithKeys initializes a keybinding with the given keystrokes.
 func WithKeys(keys ...string) BindingOpt {
 	return func(b *Binding) {
-		b.keys = keys
+		b.SetKeys(keys...)
 	}
 }
 
 // WithHelp initializes a keybinding with the given help text.
 func WithHelp(key, desc string) BindingOpt {
 	return func(b *Binding) {
-		b.help = Help{Key: key, Desc: desc}
+		b.help.Key, b.help.Desc = key, desc
+	}
+}
+
+// WithLongHelp initializes a keybinding with an extended description, which
+// help views show in full help only, so that short help stays terse.
+func WithLongHelp(long string) BindingOpt {
+	return func(b *Binding) {
+		b.help.Long = long
+	}
+}
+
+// WithPriority initializes a keybinding with a help priority. See
+// Help.Priority.
+func WithPriority(p int) BindingOpt {
+	return func(b *Binding) {
+		b.help.Priority = p
 	}
 }
 
@@ -83,6 +105,10 @@ func WithDisabled() BindingOpt {
 // SetKeys sets the keys for the keybinding.
 func (b *Binding) SetKeys(keys ...string) {
 	b.keys = keys
+	b.normalized = make([]string, len(keys))
+	for i, k := range keys {
+		b.normalized[i] = Normalize(k)
+	}
 }
 
 // Keys returns the keys for the keybinding.
@@ -90,9 +116,20 @@ func (b Binding) Keys() []string {
 	return b.keys
 }
 
-// SetHelp sets the help text for the keybinding.
+// SetHelp sets the help text for the keybinding. The extended description
+// and priority, if any, are kept.
 func (b *Binding) SetHelp(key, desc string) {
-	b.help = Help{Key: key, Desc: desc}
+	b.help.Key, b.help.Desc = key, desc
+}
+
+// SetLongHelp sets the extended description for the keybinding.
+func (b *Binding) SetLongHelp(long string) {
+	b.help.Long = long
+}
+
+// SetPriority sets the help priority for the keybinding. See Help.Priority.
+func (b *Binding) SetPriority(p int) {
+	b.help.Priority = p
 }
 
 // Help returns the Help information for the keybinding.
@@ -117,6 +154,7 @@ func (b *Binding) SetEnabled(v bool) {
 // or disable key bindings based on application state.
 func (b *Binding) Unbind() {
 	b.keys = nil
+	b.normalized = nil
 	b.help = Help{}
 }
 
@@ -124,13 +162,24 @@ func (b *Binding) Unbind() {
 type Help struct {
 	Key  string
 	Desc string
+
+	// Long is an optional extended description, explaining what the binding
+	// does in more detail than Desc.
+	Long string
+
+	// Priority decides which bindings short help views drop first when they
+	// don't all fit: those with the lowest priority, and among those the
+	// last ones. Bindings have a priority of 0 by default.
+	Priority int
 }
 
-// Matches checks if the given key matches the given bindings.
+// Matches checks if the given key matches the given bindings. Keys are
+// compared by their normalized forms, so that a binding matches the variants
+// different terminals send for its keys. See Normalize.
 func Matches[Key fmt.Stringer](k Key, b ...Binding) bool {
-	keys := k.String()
+	keys := Normalize(k.String())
 	for _, binding := range b {
-		for _, v := range binding.keys {
+		for _, v := range binding.normalized {
 			if keys == v && binding.Enabled() {
 				return true
 			}
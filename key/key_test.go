@@ -1,7 +1,11 @@
 package key
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 func TestBinding_Enabled(t *testing.T) {
@@ -24,3 +28,174 @@ func TestBinding_Enabled(t *testing.T) {
 		t.Errorf("expected key not to be Enabled")
 	}
 }
+
+func TestLongHelp(t *testing.T) {
+	b := NewBinding(
+		WithLongHelp("Moves the cursor up one line."),
+		WithHelp("↑/k", "move up"),
+	)
+	if h := b.Help(); h.Desc != "move up" || h.Long != "Moves the cursor up one line." {
+		t.Fatalf("expected WithHelp to keep the long description, got %+v", h)
+	}
+
+	b.SetHelp("k", "up")
+	if b.Help().Long == "" {
+		t.Fatal("expected SetHelp to keep the long description")
+	}
+}
+
+func TestTranslatedHelp(t *testing.T) {
+	de := Translations{"page up": "Seite hoch", "pgup": "Bild↑", "Scrolls up a page.": "Blättert eine Seite hoch."}
+	b := NewBinding(WithHelp("b/pgup", "page up"), WithLongHelp("Scrolls up a page."))
+
+	if h := b.TranslatedHelp(nil); h != b.Help() {
+		t.Fatalf("expected help without a translator to be unchanged, got %+v", h)
+	}
+	h := b.TranslatedHelp(de)
+	if h.Key != "b/Bild↑" || h.Desc != "Seite hoch" || h.Long != "Blättert eine Seite hoch." {
+		t.Fatalf("expected the help to be translated, got %+v", h)
+	}
+
+	b.SetTranslator(TranslatorFunc(strings.ToUpper))
+	if h := b.TranslatedHelp(de); h.Key != "B/PGUP" || h.Desc != "PAGE UP" {
+		t.Fatalf("expected the binding's translator to take precedence, got %+v", h)
+	}
+	if b.Help().Desc != "page up" {
+		t.Fatalf("expected the help to be kept untranslated, got %q", b.Help().Desc)
+	}
+}
+
+type testKey string
+
+func (k testKey) String() string { return string(k) }
+
+func TestModeMap(t *testing.T) {
+	modes := NewModeMap("normal")
+	modes.Add("normal", "insert", NewBinding(WithKeys("i"), WithHelp("i", "insert")))
+	modes.Add("normal", "search", NewBinding(WithKeys("/"), WithHelp("/", "search")))
+	modes.Add("insert", "normal", NewBinding(WithKeys("esc"), WithHelp("esc", "normal mode")))
+	modes.Add("search", "cancel", NewBinding(WithKeys("esc"), WithHelp("esc", "cancel")))
+	modes.AddGlobal("quit", NewBinding(WithKeys("ctrl+c"), WithHelp("ctrl+c", "quit")))
+	modes.AddGlobal("help", NewBinding(WithKeys("?"), WithHelp("?", "help")))
+	modes.Add("search", "help", NewBinding(WithKeys("ctrl+h"), WithHelp("ctrl+h", "search help")))
+
+	if got := modes.Match(testKey("i")); got != "insert" {
+		t.Fatalf("expected i to insert, got %q", got)
+	}
+	if modes.Matches(testKey("esc"), "normal", "cancel") {
+		t.Fatal("expected bindings of other modes not to match")
+	}
+	if !modes.Matches(testKey("ctrl+c"), "quit") {
+		t.Fatal("expected global bindings to match")
+	}
+
+	modes.SetMode("search")
+	if got := modes.Match(testKey("esc")); got != "cancel" {
+		t.Fatalf("expected esc to cancel the search, got %q", got)
+	}
+	if modes.Match(testKey("?")) != "" || modes.Match(testKey("ctrl+h")) != "help" {
+		t.Fatal("expected the mode's help binding to replace the global one")
+	}
+	var desc []string
+	for _, b := range modes.ShortHelp() {
+		desc = append(desc, b.Help().Desc)
+	}
+	if got := fmt.Sprint(desc); got != "[cancel search help quit]" {
+		t.Fatalf("expected help for the search mode, got %s", got)
+	}
+	if got := len(modes.FullHelp()); got != 2 {
+		t.Fatalf("expected the mode and global bindings in separate columns, got %d", got)
+	}
+
+	modes.Binding("search", "cancel").SetEnabled(false)
+	if modes.Match(testKey("esc")) != "" {
+		t.Fatal("expected a disabled binding not to match")
+	}
+}
+
+func TestGroup(t *testing.T) {
+	editing := NewGroup("Editing",
+		NamedBinding{Name: "cut", Binding: NewBinding(WithKeys("ctrl+x"), WithHelp("ctrl+x", "cut"))},
+		NamedBinding{Name: "paste", Binding: NewBinding(WithKeys("ctrl+v"), WithHelp("ctrl+v", "paste"))},
+	)
+	general := NewGroup("General")
+	general.Add("quit", NewBinding(WithKeys("ctrl+c"), WithHelp("ctrl+c", "quit")))
+	groups := Groups{editing, general}
+
+	if g, name := groups.Match(testKey("ctrl+v")); g != "Editing" || name != "paste" {
+		t.Fatalf("expected ctrl+v to paste, got %q in %q", name, g)
+	}
+
+	groups.Group("Editing").SetEnabled(false)
+	if g, name := groups.Match(testKey("ctrl+x")); g != "" || name != "" {
+		t.Fatalf("expected a disabled group not to match, got %q in %q", name, g)
+	}
+	if groups[0].Enabled() || !groups[1].Enabled() {
+		t.Fatal("expected only the editing group to be disabled")
+	}
+	if _, name := groups.Match(testKey("ctrl+c")); name != "quit" {
+		t.Fatal("expected other groups to still match")
+	}
+
+	groups[0].SetEnabled(true)
+	groups[0].Binding("cut").SetEnabled(false)
+	if groups[0].Match(testKey("ctrl+x")) != "" || !groups[0].Matches(testKey("ctrl+v"), "paste") {
+		t.Fatal("expected bindings of a group to be disabled individually")
+	}
+	if got := len(groups.FullHelp()); got != 2 {
+		t.Fatalf("expected a column per group, got %d", got)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+	}{
+		{"alt+enter", "alt+enter"},
+		{"meta+return", "alt+enter"},
+		{"ctrl+option+a", "alt+ctrl+a"},
+		{"shift+a", "A"},
+		{"space", " "},
+		{"ctrl++", "ctrl++"},
+		{"?0x8d?", "alt+enter"},
+		{"?0xe1?", "alt+a"},
+		{"?CSI[49 51 59 51 117]?", "alt+enter"},          // kitty: 13;3u
+		{"?CSI[50 55 59 51 59 49 51 126]?", "alt+enter"}, // xterm: 27;3;13~
+		{"?CSI[57 55 59 53 117]?", "ctrl+a"},             // 97;5u
+		{"?CSI[53 55 52 49 52 117]?", "enter"},           // keypad enter
+		{"?CSI[53 55 52 48 48 59 51 117]?", "alt+1"},     // alt+keypad 1
+		{"?CSI[49 59 53 65]?", "?CSI[49 59 53 65]?"},     // unrelated
+		{"[a+b]", "[a+b]"},                               // pastes never match
+	} {
+		if got := Normalize(tc.in); got != tc.want {
+			t.Errorf("%q: expected %q, got %q", tc.in, tc.want, got)
+		}
+	}
+
+	b := NewBinding(WithKeys("alt+enter"))
+	for _, k := range []string{"alt+enter", "?0x8d?", "?CSI[49 51 59 51 117]?"} {
+		if !Matches(testKey(k), b) {
+			t.Errorf("expected %q to match alt+enter", k)
+		}
+	}
+	if Matches(testKey("enter"), b) {
+		t.Error("expected enter not to match alt+enter")
+	}
+	if got := b.Keys(); len(got) != 1 || got[0] != "alt+enter" {
+		t.Errorf("expected the keys to be kept as given, got %v", got)
+	}
+}
+
+func TestNormalizeMsg(t *testing.T) {
+	msg := NormalizeMsg(testKey("?CSI[49 51 59 51 117]?"))
+	if k, ok := msg.(tea.KeyMsg); !ok || k.Type != tea.KeyEnter || !k.Alt {
+		t.Fatalf("expected alt+enter, got %#v", msg)
+	}
+	msg = NormalizeMsg(testKey("?0xe1?"))
+	if k, ok := msg.(tea.KeyMsg); !ok || k.String() != "alt+a" {
+		t.Fatalf("expected alt+a, got %#v", msg)
+	}
+	if msg := NormalizeMsg(testKey("?CSI[49 59 53 65]?")); msg != testKey("?CSI[49 59 53 65]?") {
+		t.Fatalf("expected an unrecognized sequence to be kept, got %#v", msg)
+	}
+}
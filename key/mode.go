@@ -0,0 +1,169 @@
+package key
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ModeMap is a set of keybindings scoped to application modes, such as the
+// "normal", "insert" and "search" modes of a modal editor. Bindings are added
+// under a name, and only those of the active mode, along with global ones,
+// are matched and shown in help:
+//
+//	modes := key.NewModeMap("normal")
+//	modes.Add("normal", "insert", key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "insert")))
+//	modes.Add("insert", "normal", key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "normal mode")))
+//	modes.AddGlobal("quit", key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")))
+//
+//	switch modes.Match(msg) {
+//	case "insert":
+//	    modes.SetMode("insert")
+//	case "normal":
+//	    modes.SetMode("normal")
+//	}
+//
+// ModeMap satisfies the help.KeyMap interface, so passing it to a help view
+// renders the active mode's bindings, which change as the mode does.
+type ModeMap struct {
+	mode     string
+	bindings []modeBinding
+}
+
+// modeBinding is a named binding in a ModeMap. Global bindings have an empty
+// mode.
+type modeBinding struct {
+	mode    string
+	name    string
+	binding Binding
+}
+
+// NewModeMap returns an empty ModeMap with the given mode active.
+func NewModeMap(mode string) ModeMap {
+	return ModeMap{mode: mode}
+}
+
+// Mode returns the active mode.
+func (m ModeMap) Mode() string {
+	return m.mode
+}
+
+// SetMode activates a mode. Modes don't need to be declared, and a mode
+// without bindings of its own leaves only the global bindings active.
+func (m *ModeMap) SetMode(mode string) {
+	m.mode = mode
+}
+
+// Add adds a binding to a mode under the given name, replacing any binding
+// already added to the mode with that name. A binding in the active mode
+// takes precedence over a global binding with the same name. An empty mode
+// adds a global binding, like AddGlobal.
+func (m *ModeMap) Add(mode, name string, b Binding) {
+	if i := m.index(mode, name); i >= 0 {
+		m.bindings[i].binding = b
+		return
+	}
+	m.bindings = append(m.bindings, modeBinding{mode: mode, name: name, binding: b})
+}
+
+// AddGlobal adds a binding which is active in every mode, such as quit.
+func (m *ModeMap) AddGlobal(name string, b Binding) {
+	m.Add("", name, b)
+}
+
+// Binding returns the binding with the given name in a mode, or the global
+// binding with that name if mode is empty. It returns a pointer, so that the
+// binding can be changed in place, such as to disable it, or nil if there's
+// no such binding.
+func (m *ModeMap) Binding(mode, name string) *Binding {
+	if i := m.index(mode, name); i >= 0 {
+		return &m.bindings[i].binding
+	}
+	return nil
+}
+
+func (m ModeMap) index(mode, name string) int {
+	for i, b := range m.bindings {
+		if b.mode == mode && b.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Match returns the name of the active binding which matches the given key,
+// or an empty string if there's none. Bindings in the active mode are checked
+// before global ones.
+func (m ModeMap) Match(k fmt.Stringer) string {
+	for _, b := range m.Active() {
+		if Matches(k, b.Binding) {
+			return b.Name
+		}
+	}
+	return ""
+}
+
+// Matches reports whether the given key matches one of the named bindings,
+// considering only those that are active in the current mode.
+func (m ModeMap) Matches(k fmt.Stringer, names ...string) bool {
+	name := m.Match(k)
+	return name != "" && slices.Contains(names, name)
+}
+
+// Bindings returns the bindings which are active in the current mode: those
+// of the mode, followed by the global bindings they don't override.
+func (m ModeMap) Bindings() []Binding {
+	active := m.Active()
+	bindings := make([]Binding, len(active))
+	for i, b := range active {
+		bindings[i] = b.Binding
+	}
+	return bindings
+}
+
+// NamedBinding is a binding and the name it was added to a ModeMap with.
+type NamedBinding struct {
+	Name    string
+	Binding Binding
+}
+
+// Active returns the bindings which are active in the current mode along
+// with their names, in the same order as Bindings.
+func (m ModeMap) Active() []NamedBinding {
+	var mode, global []NamedBinding
+	for _, b := range m.bindings {
+		switch {
+		case b.mode == m.mode && m.mode != "":
+			mode = append(mode, NamedBinding{Name: b.name, Binding: b.binding})
+		case b.mode == "" && (m.mode == "" || m.index(m.mode, b.name) < 0):
+			global = append(global, NamedBinding{Name: b.name, Binding: b.binding})
+		}
+	}
+	return append(mode, global...)
+}
+
+// ShortHelp returns the active bindings, satisfying the help.KeyMap
+// interface.
+func (m ModeMap) ShortHelp() []Binding {
+	return m.Bindings()
+}
+
+// FullHelp returns the active bindings, with the mode's bindings in one
+// column and the global ones in another, satisfying the help.KeyMap
+// interface.
+func (m ModeMap) FullHelp() [][]Binding {
+	var mode, global []Binding
+	for _, b := range m.Active() {
+		if m.mode != "" && m.index(m.mode, b.Name) >= 0 {
+			mode = append(mode, b.Binding)
+			continue
+		}
+		global = append(global, b.Binding)
+	}
+	var groups [][]Binding
+	for _, g := range [][]Binding{mode, global} {
+		if len(g) > 0 {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
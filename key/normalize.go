@@ -0,0 +1,277 @@
+package key
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Modifier bits of CSI u and modifyOtherKeys sequences, after subtracting
+// one from the reported value.
+const (
+	csiShift = 1 << iota
+	csiAlt
+	csiCtrl
+)
+
+// modifierNames maps the names of modifiers to those Bubble Tea reports.
+var modifierNames = map[string]string{
+	"alt":     "alt",
+	"meta":    "alt",
+	"opt":     "alt",
+	"option":  "alt",
+	"ctrl":    "ctrl",
+	"control": "ctrl",
+	"shift":   "shift",
+}
+
+// keyNames maps other names of keys to those Bubble Tea reports.
+var keyNames = map[string]string{
+	"return":    "enter",
+	"escape":    "esc",
+	"space":     " ",
+	"del":       "delete",
+	"ins":       "insert",
+	"page_up":   "pgup",
+	"page_down": "pgdown",
+}
+
+// csiKeys maps the key codes of CSI u sequences to key names. Codes of
+// printable characters are the characters themselves.
+var csiKeys = map[int]string{
+	9:   "tab",
+	13:  "enter",
+	27:  "esc",
+	32:  " ",
+	127: "backspace",
+
+	// Keypad keys, as kitty reports them with its keyboard protocol.
+	57399: "0", 57400: "1", 57401: "2", 57402: "3", 57403: "4",
+	57404: "5", 57405: "6", 57406: "7", 57407: "8", 57408: "9",
+	57409: ".", 57410: "/", 57411: "*", 57412: "-", 57413: "+",
+	57414: "enter", 57415: "=",
+	57417: "left", 57418: "right", 57419: "up", 57420: "down",
+	57421: "pgup", 57422: "pgdown", 57423: "home", 57424: "end",
+	57425: "insert", 57426: "delete",
+}
+
+// Normalize returns the canonical form of a key, as Bubble Tea names it, so
+// that keys reported differently by different terminals compare equal.
+// Bindings match keys by their normalized forms, so a binding only needs the
+// canonical name of a key.
+//
+// Normalize handles:
+//
+//   - modifier aliases and order, so "ctrl+meta+enter" and
+//     "option+control+enter" are both "alt+ctrl+enter";
+//   - key aliases, such as "return" for "enter" and "escape" for "esc";
+//   - 8-bit meta, where terminals set the high bit of a byte instead of
+//     sending an escape prefix, so that 0xE1 is "alt+a";
+//   - CSI u sequences of the kitty keyboard protocol and others, including
+//     keypad keys, and xterm's modifyOtherKeys sequences, which Bubble Tea
+//     reports as unknown.
+//
+// Keys it doesn't recognize are returned unchanged.
+func Normalize(k string) string {
+	switch {
+	case strings.HasPrefix(k, "?0x") && strings.HasSuffix(k, "?"):
+		if n, err := strconv.ParseUint(k[3:len(k)-1], 16, 8); err == nil && n >= 0x80 {
+			return "alt+" + byteName(byte(n&0x7f))
+		}
+		return k
+	case strings.HasPrefix(k, "?CSI[") && strings.HasSuffix(k, "]?"):
+		if name, ok := csiName(k[5 : len(k)-2]); ok {
+			return name
+		}
+		return k
+	case len(k) <= 1 || !strings.ContainsAny(k[1:], "+_"):
+		if name, ok := keyNames[strings.ToLower(k)]; ok {
+			return name
+		}
+		return k
+	}
+
+	parts := strings.Split(k, "+")
+	name := parts[len(parts)-1]
+	if name == "" {
+		// The key itself is a plus, as in "ctrl++".
+		name = "+"
+		parts = parts[:len(parts)-1]
+	}
+	var mods int
+	for _, p := range parts[:len(parts)-1] {
+		switch modifierNames[strings.ToLower(p)] {
+		case "alt":
+			mods |= csiAlt
+		case "ctrl":
+			mods |= csiCtrl
+		case "shift":
+			mods |= csiShift
+		default:
+			return k
+		}
+	}
+	if alias, ok := keyNames[strings.ToLower(name)]; ok {
+		name = alias
+	}
+	return withModifiers(name, mods)
+}
+
+// NormalizeMsg turns messages Bubble Tea reports for keys it doesn't
+// recognize, such as 8-bit meta bytes and CSI u sequences, into key messages,
+// so that components handling tea.KeyMsg see them. Other messages are
+// returned unchanged. It can be used as a program's filter:
+//
+//	p := tea.NewProgram(model, tea.WithFilter(func(_ tea.Model, msg tea.Msg) tea.Msg {
+//	    return key.NormalizeMsg(msg)
+//	}))
+func NormalizeMsg(msg tea.Msg) tea.Msg {
+	if _, ok := msg.(tea.KeyMsg); ok {
+		return msg
+	}
+	s, ok := msg.(interface{ String() string })
+	if !ok {
+		return msg
+	}
+	raw := s.String()
+	if !strings.HasPrefix(raw, "?") {
+		return msg
+	}
+	name := Normalize(raw)
+	if name == raw {
+		return msg
+	}
+	if k, ok := parseKey(name); ok {
+		return tea.KeyMsg(k)
+	}
+	return msg
+}
+
+// byteName returns the name of the key sending the given 7-bit byte.
+func byteName(b byte) string {
+	switch {
+	case b == 0:
+		return "ctrl+@"
+	case b < ' ' || b == 0x7f:
+		return tea.KeyType(b).String()
+	default:
+		return string(rune(b))
+	}
+}
+
+// csiName returns the name of the key of a CSI u or modifyOtherKeys
+// sequence, given its bytes after the CSI as Bubble Tea formats them, such
+// as "49 51 59 51 117" for "13;3u".
+func csiName(s string) (string, bool) {
+	var seq strings.Builder
+	for _, f := range strings.Fields(s) {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 || n > 0x7f {
+			return "", false
+		}
+		seq.WriteByte(byte(n))
+	}
+	body := seq.String()
+	if body == "" {
+		return "", false
+	}
+
+	final := body[len(body)-1]
+	params := strings.Split(body[:len(body)-1], ";")
+	var code, mods string
+	switch {
+	case final == 'u' && len(params) <= 2:
+		code = params[0]
+		if len(params) == 2 {
+			mods = params[1]
+		}
+	case final == '~' && len(params) == 3 && params[0] == "27":
+		mods, code = params[1], params[2]
+	default:
+		return "", false
+	}
+
+	// Codes and modifiers may carry alternates and event types after a
+	// colon, which don't matter here.
+	code, _, _ = strings.Cut(code, ":")
+	mods, _, _ = strings.Cut(mods, ":")
+	c, err := strconv.Atoi(code)
+	if err != nil {
+		return "", false
+	}
+	m := 1
+	if mods != "" {
+		if m, err = strconv.Atoi(mods); err != nil || m < 1 {
+			return "", false
+		}
+	}
+
+	name, ok := csiKeys[c]
+	if !ok {
+		if c < ' ' || !unicode.IsPrint(rune(c)) {
+			return "", false
+		}
+		name = string(rune(c))
+	}
+	return withModifiers(name, m-1), true
+}
+
+// withModifiers returns the name of a key with the given CSI modifier bits,
+// in the form Bubble Tea reports it. Shifted letters are reported as capitals,
+// and control with a letter as its control character.
+func withModifiers(name string, mods int) string {
+	runes := []rune(name)
+	if len(runes) == 1 && unicode.IsLetter(runes[0]) {
+		if mods&csiShift != 0 && mods&csiCtrl == 0 {
+			name = string(unicode.ToUpper(runes[0]))
+			mods &^= csiShift
+		} else if mods&csiCtrl != 0 {
+			name = string(unicode.ToLower(runes[0]))
+		}
+	}
+
+	var b strings.Builder
+	if mods&csiAlt != 0 {
+		b.WriteString("alt+")
+	}
+	if mods&csiCtrl != 0 {
+		b.WriteString("ctrl+")
+	}
+	if mods&csiShift != 0 {
+		b.WriteString("shift+")
+	}
+	b.WriteString(name)
+	return b.String()
+}
+
+// namedKeys maps the names of Bubble Tea's key types to the types.
+var namedKeys = func() map[string]tea.KeyType {
+	names := make(map[string]tea.KeyType)
+	for t := tea.KeyType(-128); t <= 0x7f; t++ { //nolint:mnd
+		if s := t.String(); s != "" && t != tea.KeyRunes {
+			if _, ok := names[s]; !ok {
+				names[s] = t
+			}
+		}
+	}
+	return names
+}()
+
+// parseKey returns the key with the given name, as Bubble Tea names it.
+func parseKey(name string) (tea.Key, bool) {
+	var k tea.Key
+	if rest, ok := strings.CutPrefix(name, "alt+"); ok && rest != "" {
+		k.Alt, name = true, rest
+	}
+	if t, ok := namedKeys[name]; ok {
+		k.Type = t
+		return k, true
+	}
+	if runes := []rune(name); len(runes) == 1 {
+		k.Type, k.Runes = tea.KeyRunes, runes
+		return k, true
+	}
+	return k, false
+}
@@ -0,0 +1,86 @@
+package key
+
+import "strings"
+
+// Translator localizes help text, such as the descriptions of bindings and
+// the names of their keys, when help is rendered. Translate returns the text
+// unchanged if it has no translation.
+type Translator interface {
+	Translate(text string) string
+}
+
+// TranslatorFunc adapts a function to the Translator interface.
+type TranslatorFunc func(text string) string
+
+// Translate implements the Translator interface.
+func (f TranslatorFunc) Translate(text string) string {
+	return f(text)
+}
+
+// Translations is a Translator looking texts up in a map, such as "page up"
+// or "pgup". Texts which aren't in it are left unchanged.
+type Translations map[string]string
+
+// Translate implements the Translator interface.
+func (t Translations) Translate(text string) string {
+	if s, ok := t[text]; ok {
+		return s
+	}
+	return text
+}
+
+// WithTranslator initializes a keybinding with a translator for its help
+// text. See SetTranslator.
+func WithTranslator(t Translator) BindingOpt {
+	return func(b *Binding) {
+		b.translator = t
+	}
+}
+
+// SetTranslator sets the translator for the keybinding's help text, which
+// takes precedence over the one help views are given. Passing nil removes
+// it.
+func (b *Binding) SetTranslator(t Translator) {
+	b.translator = t
+}
+
+// TranslatedHelp returns the help information for the keybinding, translated
+// by its own translator if it has one, and by t otherwise. If neither is set
+// the help is returned as is.
+//
+// A key that has no translation as a whole, like "b/pgup", is translated
+// name by name.
+func (b Binding) TranslatedHelp(t Translator) Help {
+	if b.translator != nil {
+		t = b.translator
+	}
+	h := b.help
+	if t == nil {
+		return h
+	}
+	h.Key = translateKey(t, h.Key)
+	h.Desc = translate(t, h.Desc)
+	h.Long = translate(t, h.Long)
+	return h
+}
+
+// translate translates text, leaving it empty if it is.
+func translate(t Translator, text string) string {
+	if text == "" {
+		return ""
+	}
+	return t.Translate(text)
+}
+
+// translateKey translates the help text for a binding's keys as a whole or,
+// if it has no translation, each of the keys it lists separated by slashes.
+func translateKey(t Translator, k string) string {
+	if s := translate(t, k); s != k || !strings.Contains(k, "/") {
+		return s
+	}
+	names := strings.Split(k, "/")
+	for i, name := range names {
+		names[i] = translate(t, name)
+	}
+	return strings.Join(names, "/")
+}
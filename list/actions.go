@@ -0,0 +1,185 @@
+package list
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mikeflynn/bubbles/key"
+)
+
+// Action is an entry of an item's action menu, such as "open", "rename" or
+// "delete".
+type Action struct {
+	// Name identifies the action in ActionChosenMsg. It's shown in the menu
+	// unless Title is set.
+	Name  string
+	Title string
+
+	// Key, if set, chooses the action straight away while the menu is open,
+	// such as "d" for delete.
+	Key string
+}
+
+// ActionItem can be implemented by items with actions of their own, which
+// are shown in their menu in place of those set with SetActions. An item returning
+// no actions has no menu.
+type ActionItem interface {
+	Actions() []Action
+}
+
+// ActionChosenMsg is sent when the user chooses an action from an item's
+// action menu.
+type ActionChosenMsg struct {
+	Action Action
+
+	// Item is the item the menu was opened for, and Index its index in the
+	// unfiltered list of items, as returned by GlobalIndex.
+	Item  Item
+	Index int
+}
+
+// title returns the text of the action in the menu.
+func (a Action) title() string {
+	if a.Title != "" {
+		return a.Title
+	}
+	return a.Name
+}
+
+// SetActions sets the actions offered in the action menu of items which don't
+// implement ActionItem. Passing nil leaves only those items with a menu.
+func (m *Model) SetActions(actions []Action) {
+	m.actions = actions
+	m.updateKeybindings()
+}
+
+// ItemActions returns the actions offered for the given item: its own if it
+// implements ActionItem, or the list's otherwise.
+func (m Model) ItemActions(item Item) []Action {
+	if i, ok := item.(ActionItem); ok {
+		return i.Actions()
+	}
+	return m.actions
+}
+
+// ShowingActions returns whether the action menu of the selected item is
+// open.
+func (m Model) ShowingActions() bool {
+	return m.showActions
+}
+
+// OpenActions opens the action menu of the selected item, if it has actions.
+func (m *Model) OpenActions() {
+	item := m.SelectedItem()
+	if item == nil || len(m.ItemActions(item)) == 0 {
+		return
+	}
+	m.showActions = true
+	m.actionCursor = 0
+	m.updateKeybindings()
+}
+
+// CloseActions closes the action menu without choosing an action.
+func (m *Model) CloseActions() {
+	m.showActions = false
+	m.updateKeybindings()
+}
+
+// hasActions returns whether any item can have an action menu.
+func (m Model) hasActions() bool {
+	if len(m.actions) > 0 {
+		return true
+	}
+	for _, item := range m.items {
+		if _, ok := item.(ActionItem); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// chooseAction closes the menu and returns a command sending an
+// ActionChosenMsg for the action at the given index.
+func (m *Model) chooseAction(i int) tea.Cmd {
+	item := m.SelectedItem()
+	actions := m.ItemActions(item)
+	m.CloseActions()
+	if i < 0 || i >= len(actions) {
+		return nil
+	}
+	msg := ActionChosenMsg{Action: actions[i], Item: item, Index: m.GlobalIndex()}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// handleActions handles keys while the action menu is open.
+func (m *Model) handleActions(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	actions := m.ItemActions(m.SelectedItem())
+	if len(actions) == 0 {
+		m.CloseActions()
+		return nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.KeyMap.CloseActions):
+		m.CloseActions()
+	case key.Matches(keyMsg, m.KeyMap.ChooseAction):
+		return m.chooseAction(m.actionCursor)
+	case key.Matches(keyMsg, m.KeyMap.CursorUp):
+		m.actionCursor = max(0, m.actionCursor-1)
+	case key.Matches(keyMsg, m.KeyMap.CursorDown):
+		m.actionCursor = min(len(actions)-1, m.actionCursor+1)
+	default:
+		for i, a := range actions {
+			if a.Key != "" && key.Normalize(keyMsg.String()) == key.Normalize(a.Key) {
+				return m.chooseAction(i)
+			}
+		}
+	}
+	return nil
+}
+
+// actionMenuView renders the action menu of the selected item.
+func (m Model) actionMenuView() []string {
+	actions := m.ItemActions(m.SelectedItem())
+	lines := make([]string, len(actions))
+	for i, a := range actions {
+		title := a.title()
+		if a.Key != "" {
+			title += " " + m.Styles.ActionKey.Render(a.Key)
+		}
+		if i == m.actionCursor {
+			lines[i] = m.Styles.SelectedAction.Render("› " + title)
+			continue
+		}
+		lines[i] = m.Styles.Action.Render("  " + title)
+	}
+	return strings.Split(m.Styles.ActionMenu.Render(strings.Join(lines, "\n")), "\n")
+}
+
+// overlayActionMenu draws the action menu, if it's open, over the lines of
+// the page, below the selected item, which spans the lines first to last, or
+// above it if there's no room below.
+func (m Model) overlayActionMenu(page string, first, last int) string {
+	if !m.showActions || first < 0 {
+		return page
+	}
+	lines := strings.Split(page, "\n")
+	menu := m.actionMenuView()
+	start := last + 1
+	if start+len(menu) > len(lines) && first-len(menu) >= 0 {
+		start = first - len(menu)
+	}
+	for i, line := range menu {
+		if start+i >= len(lines) {
+			break
+		}
+		lines[start+i] = line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,122 @@
+package list
+
+import (
+	"io"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mikeflynn/bubbles/key"
+)
+
+// CheckableItem describes an item designed to work with CheckboxDelegate.
+type CheckableItem interface {
+	DefaultItem
+
+	// Checked returns whether the item is checked.
+	Checked() bool
+
+	// SetChecked returns a copy of the item with the given checked state.
+	SetChecked(checked bool) CheckableItem
+}
+
+// ItemToggledMsg is sent by CheckboxDelegate when an item is checked or
+// unchecked.
+type ItemToggledMsg struct {
+	// Index is the index of the item in the unfiltered list.
+	Index int
+
+	// Item is the item after it's been toggled.
+	Item CheckableItem
+}
+
+// CheckboxDelegate is a delegate which renders a checkbox before each item's
+// title and toggles it with a keybinding, making it simple to build
+// multiple-choice pickers. Items must implement CheckableItem. It's otherwise
+// identical to DefaultDelegate, which it embeds.
+//
+// When an item is toggled the delegate replaces it in the list and sends an
+// ItemToggledMsg.
+type CheckboxDelegate struct {
+	DefaultDelegate
+
+	// Toggle is the keybinding used to check and uncheck the selected item.
+	Toggle key.Binding
+
+	// Checked and Unchecked are rendered before the title of checked and
+	// unchecked items respectively.
+	Checked   string
+	Unchecked string
+}
+
+// NewCheckboxDelegate creates a new checkbox delegate with default styles. The
+// description line is hidden by default.
+func NewCheckboxDelegate() CheckboxDelegate {
+	d := CheckboxDelegate{
+		DefaultDelegate: NewDefaultDelegate(),
+		Toggle: key.NewBinding(
+			key.WithKeys(" ", "x"),
+			key.WithHelp("space", "toggle"),
+		),
+		Checked:   "[x] ",
+		Unchecked: "[ ] ",
+	}
+	d.ShowDescription = false
+	d.SetSpacing(0)
+	return d
+}
+
+// Update toggles the selected item when the Toggle key is pressed. It then
+// calls the embedded DefaultDelegate's Update.
+func (d CheckboxDelegate) Update(msg tea.Msg, m *Model) tea.Cmd {
+	var cmds []tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, d.Toggle) {
+		if item, ok := m.SelectedItem().(CheckableItem); ok {
+			index := m.GlobalIndex()
+			item = item.SetChecked(!item.Checked())
+			if m.filterState != Unfiltered {
+				// Show the change right away rather than waiting for the
+				// filter to be re-run.
+				m.filteredItems[m.Index()].item = item
+			}
+			cmds = append(cmds, m.SetItem(index, item), func() tea.Msg {
+				return ItemToggledMsg{Index: index, Item: item}
+			})
+		}
+	}
+
+	cmds = append(cmds, d.DefaultDelegate.Update(msg, m))
+	return tea.Batch(cmds...)
+}
+
+// Render prints an item with its checkbox.
+func (d CheckboxDelegate) Render(w io.Writer, m Model, index int, item Item) {
+	prefix := d.Unchecked
+	if i, ok := item.(CheckableItem); ok && i.Checked() {
+		prefix = d.Checked
+	}
+	d.render(w, m, index, item, prefix)
+}
+
+// ShortHelp returns the delegate's short help, which includes the Toggle
+// keybinding.
+func (d CheckboxDelegate) ShortHelp() []key.Binding {
+	return append([]key.Binding{d.Toggle}, d.DefaultDelegate.ShortHelp()...)
+}
+
+// FullHelp returns the delegate's full help, which includes the Toggle
+// keybinding.
+func (d CheckboxDelegate) FullHelp() [][]key.Binding {
+	return append([][]key.Binding{{d.Toggle}}, d.DefaultDelegate.FullHelp()...)
+}
+
+// CheckedItems returns the items in the list which are checked, for use with
+// CheckboxDelegate.
+func (m Model) CheckedItems() []CheckableItem {
+	var checked []CheckableItem
+	for _, item := range m.Items() {
+		if i, ok := item.(CheckableItem); ok && i.Checked() {
+			checked = append(checked, i)
+		}
+	}
+	return checked
+}
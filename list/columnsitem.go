@@ -0,0 +1,169 @@
+package list
+
+import (
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/textutil"
+)
+
+// ItemColumn describes a column rendered by ColumnsDelegate.
+type ItemColumn struct {
+	// Value returns the text of the column for an item.
+	Value func(Item) string
+
+	// Width is the width of the column in cells. Columns with a width of 0
+	// or less share the space left over by the others.
+	Width int
+
+	// Align is the alignment of the text within the column, such as
+	// lipgloss.Right for timestamps and numbers.
+	Align lipgloss.Position
+
+	// Style is applied to the column's text, on top of the item's style.
+	Style lipgloss.Style
+
+	// Filter marks the column that shows the item's FilterValue. Characters
+	// matching the current filter are highlighted in it.
+	Filter bool
+}
+
+// ColumnsDelegate is a compact delegate which renders each item on a single
+// line as aligned columns, such as a title, a tag and a right-aligned
+// timestamp. The text of each column comes from its Value accessor. Items are
+// styled with DefaultItemStyles, using the title styles.
+type ColumnsDelegate struct {
+	Columns []ItemColumn
+
+	// Separator is rendered between columns.
+	Separator string
+
+	Styles        DefaultItemStyles
+	UpdateFunc    func(tea.Msg, *Model) tea.Cmd
+	ShortHelpFunc func() []key.Binding
+	FullHelpFunc  func() [][]key.Binding
+	spacing       int
+}
+
+// NewColumnsDelegate creates a new columns delegate with the given columns
+// and default styles.
+func NewColumnsDelegate(columns ...ItemColumn) ColumnsDelegate {
+	return ColumnsDelegate{
+		Columns:   columns,
+		Separator: "  ",
+		Styles:    NewDefaultItemStyles(),
+	}
+}
+
+// Height returns the delegate's preferred height, which is always 1.
+func (d ColumnsDelegate) Height() int {
+	return 1
+}
+
+// SetSpacing sets the delegate's spacing.
+func (d *ColumnsDelegate) SetSpacing(i int) {
+	d.spacing = i
+}
+
+// Spacing returns the delegate's spacing.
+func (d ColumnsDelegate) Spacing() int {
+	return d.spacing
+}
+
+// Update checks whether the delegate's UpdateFunc is set and calls it.
+func (d ColumnsDelegate) Update(msg tea.Msg, m *Model) tea.Cmd {
+	if d.UpdateFunc == nil {
+		return nil
+	}
+	return d.UpdateFunc(msg, m)
+}
+
+// Render prints an item as a line of columns.
+func (d ColumnsDelegate) Render(w io.Writer, m Model, index int, item Item) {
+	if m.width <= 0 || len(d.Columns) == 0 {
+		return
+	}
+
+	var (
+		s           = &d.Styles
+		isSelected  = index == m.Index()
+		emptyFilter = m.FilterState() == Filtering && m.FilterValue() == ""
+		isFiltered  = m.FilterState() == Filtering || m.FilterState() == FilterApplied
+	)
+
+	style := s.NormalTitle
+	switch {
+	case emptyFilter:
+		style = s.DimmedTitle
+	case isSelected && m.FilterState() != Filtering:
+		style = s.SelectedTitle
+	}
+	text := lipgloss.NewStyle().Foreground(style.GetForeground()).Inline(true)
+
+	widths := d.columnWidths(m.width - style.GetHorizontalFrameSize())
+	cells := make([]string, len(d.Columns))
+	for i, col := range d.Columns {
+		value := textutil.Truncate(col.Value(item), widths[i], ellipsis)
+		cellStyle := col.Style.Inherit(text)
+		if col.Filter && isFiltered && !emptyFilter && index < len(m.filteredItems) {
+			// Highlight matches
+			value = lipgloss.StyleRunes(value, m.MatchesForItem(index), cellStyle.Inherit(s.FilterMatch), cellStyle)
+		} else {
+			value = cellStyle.Render(value)
+		}
+		if col.Align == lipgloss.Right {
+			cells[i] = textutil.PadLeft(value, widths[i])
+		} else {
+			cells[i] = textutil.PadRight(value, widths[i])
+		}
+	}
+
+	io.WriteString(w, style.Render(strings.Join(cells, d.Separator))) //nolint:errcheck
+}
+
+// columnWidths returns the width of each column given the width available,
+// sharing what's left over by fixed-width columns among flexible ones.
+func (d ColumnsDelegate) columnWidths(avail int) []int {
+	widths := make([]int, len(d.Columns))
+	left := avail - textutil.Width(d.Separator)*(len(d.Columns)-1)
+	var flex int
+	for i, col := range d.Columns {
+		if col.Width > 0 {
+			widths[i] = col.Width
+			left -= col.Width
+		} else {
+			flex++
+		}
+	}
+	if flex == 0 {
+		return widths
+	}
+	left = max(0, left)
+	for i, col := range d.Columns {
+		if col.Width <= 0 {
+			widths[i] = left / flex
+			left -= widths[i]
+			flex--
+		}
+	}
+	return widths
+}
+
+// ShortHelp returns the delegate's short help.
+func (d ColumnsDelegate) ShortHelp() []key.Binding {
+	if d.ShortHelpFunc != nil {
+		return d.ShortHelpFunc()
+	}
+	return nil
+}
+
+// FullHelp returns the delegate's full help.
+func (d ColumnsDelegate) FullHelp() [][]key.Binding {
+	if d.FullHelpFunc != nil {
+		return d.FullHelpFunc()
+	}
+	return nil
+}
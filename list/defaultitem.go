@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/textutil"
 )
 
 // DefaultItemStyles defines styling for a default list item.
@@ -138,6 +139,12 @@ func (d DefaultDelegate) Update(msg tea.Msg, m *Model) tea.Cmd {
 
 // Render prints an item.
 func (d DefaultDelegate) Render(w io.Writer, m Model, index int, item Item) {
+	d.render(w, m, index, item, "")
+}
+
+// render prints an item, with an optional prefix rendered before the title
+// and inside the title's style.
+func (d DefaultDelegate) render(w io.Writer, m Model, index int, item Item, prefix string) {
 	var (
 		title, desc  string
 		matchedRunes []int
@@ -158,14 +165,14 @@ func (d DefaultDelegate) Render(w io.Writer, m Model, index int, item Item) {
 
 	// Prevent text from exceeding list width
 	textwidth := m.width - s.NormalTitle.GetPaddingLeft() - s.NormalTitle.GetPaddingRight()
-	title = ansi.Truncate(title, textwidth, ellipsis)
+	title = textutil.Truncate(title, textwidth-ansi.StringWidth(prefix), ellipsis)
 	if d.ShowDescription {
 		var lines []string
 		for i, line := range strings.Split(desc, "\n") {
 			if i >= d.height-1 {
 				break
 			}
-			lines = append(lines, ansi.Truncate(line, textwidth, ellipsis))
+			lines = append(lines, textutil.Truncate(line, textwidth, ellipsis))
 		}
 		desc = strings.Join(lines, "\n")
 	}
@@ -183,7 +190,7 @@ func (d DefaultDelegate) Render(w io.Writer, m Model, index int, item Item) {
 	}
 
 	if emptyFilter {
-		title = s.DimmedTitle.Render(title)
+		title = s.DimmedTitle.Render(prefix + title)
 		desc = s.DimmedDesc.Render(desc)
 	} else if isSelected && m.FilterState() != Filtering {
 		if isFiltered {
@@ -192,7 +199,7 @@ func (d DefaultDelegate) Render(w io.Writer, m Model, index int, item Item) {
 			matched := unmatched.Inherit(s.FilterMatch)
 			title = lipgloss.StyleRunes(title, matchedRunes, matched, unmatched)
 		}
-		title = s.SelectedTitle.Render(title)
+		title = s.SelectedTitle.Render(prefix + title)
 		desc = s.SelectedDesc.Render(desc)
 	} else {
 		if isFiltered {
@@ -201,7 +208,7 @@ func (d DefaultDelegate) Render(w io.Writer, m Model, index int, item Item) {
 			matched := unmatched.Inherit(s.FilterMatch)
 			title = lipgloss.StyleRunes(title, matchedRunes, matched, unmatched)
 		}
-		title = s.NormalTitle.Render(title)
+		title = s.NormalTitle.Render(prefix + title)
 		desc = s.NormalDesc.Render(desc)
 	}
 
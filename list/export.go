@@ -0,0 +1,131 @@
+package list
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mikeflynn/bubbles/clipboard"
+)
+
+// ExportFormat is a text format items can be exported as.
+type ExportFormat int
+
+// Export formats.
+const (
+	// PlainText puts each item on a line of its own. It's the default.
+	PlainText ExportFormat = iota
+
+	// JSON is an array holding each item.
+	JSON
+)
+
+// String returns the name of the format.
+func (f ExportFormat) String() string {
+	return [...]string{"plain text", "JSON"}[f]
+}
+
+// CopiedMsg is sent after the visible items are copied, with the number of
+// items and the text that was copied. Err is set if they couldn't be
+// exported or written to the clipboard.
+type CopiedMsg struct {
+	Format ExportFormat
+	Count  int
+	Text   string
+	Err    error
+}
+
+// SetCopyEnabled enables or disables the CopyItems keybinding, which copies
+// the visible items to the clipboard. See CopyVisibleItems.
+func (m *Model) SetCopyEnabled(v bool) {
+	m.copyEnabled = v
+	m.updateKeybindings()
+}
+
+// CopyEnabled returns whether the CopyItems keybinding is enabled.
+func (m Model) CopyEnabled() bool {
+	return m.copyEnabled
+}
+
+// VisibleItemsAsStrings returns the items matching the filter, in the order
+// they're shown, as plain text without styling. An item is given by its
+// String method if it has one, its title if it's a DefaultItem, and its
+// FilterValue otherwise.
+func (m Model) VisibleItemsAsStrings() []string {
+	items := m.VisibleItems()
+	s := make([]string, len(items))
+	for i, item := range items {
+		s[i] = itemString(item)
+	}
+	return s
+}
+
+// Export returns the items matching the filter, in the order they're shown,
+// in the given format. In JSON, items implementing json.Marshaler are
+// encoded as they encode themselves, DefaultItems as an object with their
+// title and description, and other items as in VisibleItemsAsStrings.
+func (m Model) Export(format ExportFormat) (string, error) {
+	if format != JSON {
+		lines := m.VisibleItemsAsStrings()
+		newlines := strings.NewReplacer("\r\n", " ", "\n", " ")
+		for i := range lines {
+			lines[i] = newlines.Replace(lines[i])
+		}
+		return strings.Join(lines, "\n"), nil
+	}
+
+	items := m.VisibleItems()
+	values := make([]any, len(items))
+	for i, item := range items {
+		switch item := item.(type) {
+		case json.Marshaler:
+			values[i] = item
+		case DefaultItem:
+			values[i] = struct {
+				Title       string `json:"title"`
+				Description string `json:"description,omitempty"`
+			}{ansi.Strip(item.Title()), ansi.Strip(item.Description())}
+		default:
+			values[i] = itemString(item)
+		}
+	}
+	b, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("list: %w", err)
+	}
+	return string(b), nil
+}
+
+// CopyVisibleItems returns a command which copies the items matching the
+// filter, in the order they're shown, in the list's CopyFormat, to its
+// Clipboard. It sends a CopiedMsg with the text.
+func (m Model) CopyVisibleItems() tea.Cmd {
+	format, cb := m.CopyFormat, clipboard.Or(m.Clipboard)
+	count := len(m.VisibleItems())
+	text, err := m.Export(format)
+
+	return func() tea.Msg {
+		msg := CopiedMsg{Format: format, Count: count, Text: text, Err: err}
+		if err != nil {
+			return msg
+		}
+		if err := cb.WriteAll(text); err != nil {
+			msg.Err = fmt.Errorf("list: %w", err)
+		}
+		return msg
+	}
+}
+
+// itemString returns an item as plain text. See VisibleItemsAsStrings.
+func itemString(item Item) string {
+	switch item := item.(type) {
+	case fmt.Stringer:
+		return ansi.Strip(item.String())
+	case DefaultItem:
+		return ansi.Strip(item.Title())
+	default:
+		return ansi.Strip(item.FilterValue())
+	}
+}
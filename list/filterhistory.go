@@ -0,0 +1,99 @@
+package list
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxFilterHistory is the number of applied filters kept in the history.
+// When more are applied, the oldest is dropped.
+const maxFilterHistory = 50
+
+// FilterAppliedMsg is sent when the user applies a filter, such as for an
+// app to save it and restore it later with SetFilterText.
+type FilterAppliedMsg struct {
+	Text string
+}
+
+// FilterClearedMsg is sent when the user clears the filter.
+type FilterClearedMsg struct{}
+
+// FilterText returns the text of the applied filter, or an empty string if
+// no filter is applied, including while one is being typed. See FilterValue
+// for the text of the filter input.
+func (m Model) FilterText() string {
+	if m.filterState != FilterApplied {
+		return ""
+	}
+	return m.FilterInput.Value()
+}
+
+// FilterHistory returns the filters applied so far, oldest first.
+func (m Model) FilterHistory() []string {
+	return slices.Clone(m.filterHistory)
+}
+
+// SetFilterHistory replaces the filters applied so far, oldest first, such as
+// with a history saved from FilterHistory. While the filter input has focus,
+// the history is stepped through with the PrevFilter and NextFilter keys.
+func (m *Model) SetFilterHistory(h []string) {
+	m.filterHistory = nil
+	for _, f := range h {
+		m.addFilterHistory(f)
+	}
+	m.historyPos = len(m.filterHistory)
+	m.updateKeybindings()
+}
+
+// addFilterHistory adds a filter to the end of the history, moving it there
+// if it's in the history already.
+func (m *Model) addFilterHistory(f string) {
+	if f == "" {
+		return
+	}
+	m.filterHistory = slices.DeleteFunc(slices.Clone(m.filterHistory), func(h string) bool {
+		return h == f
+	})
+	m.filterHistory = append(m.filterHistory, f)
+	if over := len(m.filterHistory) - maxFilterHistory; over > 0 {
+		m.filterHistory = m.filterHistory[over:]
+	}
+}
+
+// stepFilterHistory shows the filter before or after the one shown in the
+// filter input. Past the newest filter, the text typed before stepping
+// through the history is shown again.
+func (m *Model) stepFilterHistory(step int) tea.Cmd {
+	pos := m.historyPos + step
+	if pos < 0 || pos > len(m.filterHistory) {
+		return nil
+	}
+	if m.historyPos == len(m.filterHistory) {
+		m.historyDraft = m.FilterInput.Value()
+	}
+	m.historyPos = pos
+
+	text := m.historyDraft
+	if pos < len(m.filterHistory) {
+		text = m.filterHistory[pos]
+	}
+	m.FilterInput.SetValue(text)
+	m.FilterInput.CursorEnd()
+	m.KeyMap.AcceptWhileFiltering.SetEnabled(text != "")
+	return filterItems(*m)
+}
+
+// filterAppliedCmd records the applied filter in the history and reports it.
+func (m *Model) filterAppliedCmd() tea.Cmd {
+	text := m.FilterInput.Value()
+	m.addFilterHistory(text)
+	m.historyPos = len(m.filterHistory)
+	return func() tea.Msg {
+		return FilterAppliedMsg{Text: text}
+	}
+}
+
+func filterClearedCmd() tea.Msg {
+	return FilterClearedMsg{}
+}
@@ -0,0 +1,95 @@
+package list
+
+// VariableHeightDelegate is an ItemDelegate whose items can differ in height,
+// such as when descriptions wrap or an item is expanded. Pages are then
+// filled with as many items as their heights allow, rather than with a fixed
+// number of items of the delegate's Height.
+type VariableHeightDelegate interface {
+	ItemDelegate
+
+	// ItemHeight returns the height of the item at the given index of the
+	// visible items. Heights are read again whenever the items, the list's
+	// size or the filter change, and after each call to the delegate's
+	// Update, so an item can be expanded there.
+	ItemHeight(m Model, index int, item Item) int
+}
+
+// updatePages fills pages with items by their heights, if the delegate has
+// variable heights. It returns false if it doesn't.
+func (m *Model) updatePages(availHeight int) bool {
+	d, ok := m.delegate.(VariableHeightDelegate)
+	if !ok {
+		m.pageStarts = nil
+		return false
+	}
+
+	items := m.VisibleItems()
+	m.pageStarts = []int{0}
+	m.pageHeights = []int{0}
+	perPage, onPage := 1, 0
+	for i, item := range items {
+		h := max(1, d.ItemHeight(*m, i, item))
+		used := &m.pageHeights[len(m.pageHeights)-1]
+		if onPage > 0 {
+			// An item taller than the list gets a page of its own.
+			if *used+m.delegate.Spacing()+h > availHeight {
+				m.pageStarts = append(m.pageStarts, i)
+				m.pageHeights = append(m.pageHeights, h)
+				onPage = 1
+				continue
+			}
+			*used += m.delegate.Spacing()
+		}
+		*used += h
+		onPage++
+		perPage = max(perPage, onPage)
+	}
+
+	m.Paginator.PerPage = perPage
+	m.Paginator.TotalPages = len(m.pageStarts)
+	return true
+}
+
+// pageBounds returns the indexes of the first item on a page and of the item
+// after the last one.
+func (m Model) pageBounds(page int) (start, end int) {
+	total := len(m.VisibleItems())
+	if m.pageStarts == nil {
+		return m.Paginator.GetSliceBounds(total)
+	}
+	if page < 0 || page >= len(m.pageStarts) {
+		return total, total
+	}
+	start, end = m.pageStarts[page], total
+	if page+1 < len(m.pageStarts) {
+		end = m.pageStarts[page+1]
+	}
+	return start, end
+}
+
+// itemsOnPage returns the number of items on the current page.
+func (m Model) itemsOnPage() int {
+	start, end := m.pageBounds(m.Paginator.Page)
+	return end - start
+}
+
+// pageOf returns the page an item is on, and its position on the page.
+func (m Model) pageOf(index int) (page, cursor int) {
+	if m.pageStarts == nil {
+		return index / m.Paginator.PerPage, index % m.Paginator.PerPage
+	}
+	page = len(m.pageStarts) - 1
+	for page > 0 && m.pageStarts[page] > index {
+		page--
+	}
+	return page, index - m.pageStarts[page]
+}
+
+// pageFiller returns the number of blank lines which fill the current page
+// below its items, when the delegate has variable heights.
+func (m Model) pageFiller(availHeight int) int {
+	if page := m.Paginator.Page; page < len(m.pageHeights) {
+		return max(0, availHeight-m.pageHeights[page])
+	}
+	return max(0, availHeight)
+}
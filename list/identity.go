@@ -0,0 +1,30 @@
+package list
+
+// Identifiable can be implemented by items to keep them selected when the
+// list's items are replaced with SetItems, such as when a list is refreshed
+// from a backend and the selected item has moved or been rebuilt. Without
+// it, the selection stays at the same index.
+type Identifiable interface {
+	ID() string
+}
+
+// selectedID returns the ID of the selected item, if it has one.
+func (m Model) selectedID() (string, bool) {
+	item, ok := m.SelectedItem().(Identifiable)
+	if !ok {
+		return "", false
+	}
+	return item.ID(), true
+}
+
+// selectID selects the visible item with the given ID, going to its page. It
+// returns false if there's no such item.
+func (m *Model) selectID(id string) bool {
+	for i, item := range m.VisibleItems() {
+		if item, ok := item.(Identifiable); ok && item.ID() == id {
+			m.Select(i)
+			return true
+		}
+	}
+	return false
+}
@@ -15,10 +15,30 @@ type KeyMap struct {
 	Filter      key.Binding
 	ClearFilter key.Binding
 
+	// Pins or unpins the selected item when pinning is enabled.
+	TogglePin key.Binding
+
+	// Copies the visible items to the clipboard. This is only enabled by
+	// Model.SetCopyEnabled.
+	CopyItems key.Binding
+
+	// Open the selected item's action menu, and choose an action from it or
+	// close it. These are only enabled when items have actions. See
+	// Model.SetActions.
+	OpenActions  key.Binding
+	ChooseAction key.Binding
+	CloseActions key.Binding
+
 	// Keybindings used when setting a filter.
 	CancelWhileFiltering key.Binding
 	AcceptWhileFiltering key.Binding
 
+	// Step through the filters applied before while setting a filter. These
+	// are only enabled once there's a filter history, and take precedence
+	// over AcceptWhileFiltering.
+	PrevFilter key.Binding
+	NextFilter key.Binding
+
 	// Help toggle keybindings.
 	ShowFullHelp  key.Binding
 	CloseFullHelp key.Binding
@@ -66,6 +86,31 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("esc"),
 			key.WithHelp("esc", "clear filter"),
 		),
+		TogglePin: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "pin/unpin"),
+			key.WithDisabled(),
+		),
+		CopyItems: key.NewBinding(
+			key.WithKeys("ctrl+y"),
+			key.WithHelp("ctrl+y", "copy items"),
+			key.WithDisabled(),
+		),
+		OpenActions: key.NewBinding(
+			key.WithKeys("."),
+			key.WithHelp(".", "actions"),
+			key.WithDisabled(),
+		),
+		ChooseAction: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "choose action"),
+			key.WithDisabled(),
+		),
+		CloseActions: key.NewBinding(
+			key.WithKeys("esc", "."),
+			key.WithHelp("esc", "close actions"),
+			key.WithDisabled(),
+		),
 
 		// Filtering.
 		CancelWhileFiltering: key.NewBinding(
@@ -76,6 +121,16 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("enter", "tab", "shift+tab", "ctrl+k", "up", "ctrl+j", "down"),
 			key.WithHelp("enter", "apply filter"),
 		),
+		PrevFilter: key.NewBinding(
+			key.WithKeys("up", "ctrl+p"),
+			key.WithHelp("↑", "prev filter"),
+			key.WithDisabled(),
+		),
+		NextFilter: key.NewBinding(
+			key.WithKeys("down", "ctrl+n"),
+			key.WithHelp("↓", "next filter"),
+			key.WithDisabled(),
+		),
 
 		// Toggle help.
 		ShowFullHelp: key.NewBinding(
@@ -15,11 +15,14 @@ import (
 	"github.com/charmbracelet/x/ansi"
 	"github.com/sahilm/fuzzy"
 
+	"github.com/mikeflynn/bubbles/clipboard"
 	"github.com/mikeflynn/bubbles/help"
 	"github.com/mikeflynn/bubbles/key"
 	"github.com/mikeflynn/bubbles/paginator"
+	"github.com/mikeflynn/bubbles/render"
 	"github.com/mikeflynn/bubbles/spinner"
 	"github.com/mikeflynn/bubbles/textinput"
+	"github.com/mikeflynn/bubbles/textutil"
 )
 
 // Item is an item that appears in the list.
@@ -114,8 +117,6 @@ func UnsortedFilter(term string, targets []string) []Rank {
 	return result
 }
 
-type statusMessageTimeoutMsg struct{}
-
 // FilterState describes the current filtering state on the model.
 type FilterState int
 
@@ -177,12 +178,12 @@ type Model struct {
 	FilterInput textinput.Model
 	filterState FilterState
 
-	// How long status messages should stay visible. By default this is
-	// 1 second.
+	// How long status messages should stay visible, unless they set their
+	// own lifetime. By default this is 1 second.
 	StatusMessageLifetime time.Duration
 
-	statusMessage      string
-	statusMessageTimer *time.Timer
+	// Status messages waiting to be shown and the one being shown.
+	statusQueue []queuedStatus
 
 	// The master set of items we're working with.
 	items []Item
@@ -193,6 +194,42 @@ type Model struct {
 	filteredItems filteredItems
 
 	delegate ItemDelegate
+
+	// pageStarts holds the index of the first item on each page, and
+	// pageHeights the height of its items, when the delegate has variable
+	// heights.
+	pageStarts  []int
+	pageHeights []int
+
+	// The ID of the item to select once the filter's been rerun after
+	// SetItems. See Identifiable.
+	reselectID string
+
+	// Pinned items and the times items were last used, keyed by itemKey.
+	pinningEnabled bool
+	sortByRecency  bool
+	pinned         map[string]bool
+	lastUsed       map[string]time.Time
+
+	// Filters applied so far, oldest first. historyPos is the position in
+	// the history of the filter shown while stepping through it, and
+	// historyDraft the text typed before stepping.
+	filterHistory []string
+	historyPos    int
+	historyDraft  string
+
+	// The actions of items without their own, and whether the selected
+	// item's action menu is open, with the action at its cursor.
+	actions      []Action
+	showActions  bool
+	actionCursor int
+
+	// CopyFormat is the format the visible items are copied in, and
+	// Clipboard is where they're copied to. If nil, the system clipboard is
+	// used. See CopyVisibleItems.
+	CopyFormat  ExportFormat
+	Clipboard   clipboard.Clipboard
+	copyEnabled bool
 }
 
 // New returns a new model with sensible defaults.
@@ -273,7 +310,8 @@ func (m *Model) SetShowTitle(v bool) {
 
 // SetFilterText explicitly sets the filter text without relying on user input.
 // It also sets the filterState to a sane default of FilterApplied, but this
-// can be changed with SetFilterState.
+// can be changed with SetFilterState. The filter is added to the history,
+// but no FilterAppliedMsg is sent.
 func (m *Model) SetFilterText(filter string) {
 	m.filterState = Filtering
 	m.FilterInput.SetValue(filter)
@@ -285,6 +323,8 @@ func (m *Model) SetFilterText(filter string) {
 	m.Paginator.Page = 0
 	m.cursor = 0
 	m.FilterInput.CursorEnd()
+	m.addFilterHistory(filter)
+	m.historyPos = len(m.filterHistory)
 	m.updatePagination()
 	m.updateKeybindings()
 }
@@ -375,25 +415,37 @@ func (m Model) Items() []Item {
 	return m.items
 }
 
-// SetItems sets the items available in the list. This returns a command.
+// SetItems sets the items available in the list. If the selected item
+// implements Identifiable, the item with the same ID stays selected, wherever
+// it is in the new items. This returns a command.
 func (m *Model) SetItems(i []Item) tea.Cmd {
 	var cmd tea.Cmd
+	id, keepSelection := m.selectedID()
 	m.items = i
+	m.sortItems()
 
 	if m.filterState != Unfiltered {
 		m.filteredItems = nil
 		cmd = filterItems(*m)
+
+		// The item is selected again once the filter's been rerun.
+		if keepSelection {
+			m.reselectID = id
+		}
+		keepSelection = false
 	}
 
 	m.updatePagination()
+	if keepSelection {
+		m.selectID(id)
+	}
 	m.updateKeybindings()
 	return cmd
 }
 
 // Select selects the given index of the list and goes to its respective page.
 func (m *Model) Select(index int) {
-	m.Paginator.Page = index / m.Paginator.PerPage
-	m.cursor = index % m.Paginator.PerPage
+	m.Paginator.Page, m.cursor = m.pageOf(index)
 }
 
 // ResetSelected resets the selected item to the first item in the first page of the list.
@@ -490,7 +542,8 @@ func (m Model) MatchesForItem(index int) []int {
 // Using this value with SetItem() might be incorrect, consider using
 // GlobalIndex() instead.
 func (m Model) Index() int {
-	return m.Paginator.Page*m.Paginator.PerPage + m.cursor
+	start, _ := m.pageBounds(m.Paginator.Page)
+	return start + m.cursor
 }
 
 // GlobalIndex returns the index of the currently selected item as it is stored
@@ -520,7 +573,7 @@ func (m *Model) CursorUp() {
 		// if infinite scrolling is enabled, go to the last item
 		if m.InfiniteScrolling {
 			m.Paginator.Page = m.Paginator.TotalPages - 1
-			m.cursor = m.Paginator.ItemsOnPage(len(m.VisibleItems())) - 1
+			m.cursor = m.itemsOnPage() - 1
 			return
 		}
 
@@ -535,13 +588,13 @@ func (m *Model) CursorUp() {
 
 	// Go to the previous page
 	m.Paginator.PrevPage()
-	m.cursor = m.Paginator.ItemsOnPage(len(m.VisibleItems())) - 1
+	m.cursor = m.itemsOnPage() - 1
 }
 
 // CursorDown moves the cursor down. This can also advance the state to the
 // next page.
 func (m *Model) CursorDown() {
-	itemsOnPage := m.Paginator.ItemsOnPage(len(m.VisibleItems()))
+	itemsOnPage := m.itemsOnPage()
 
 	m.cursor++
 
@@ -637,7 +690,9 @@ func (m *Model) ToggleSpinner() tea.Cmd {
 	return nil
 }
 
-// StartSpinner starts the spinner. Note that this returns a command.
+// StartSpinner starts the spinner. Note that this returns a command, which
+// must be returned from the Update of the model holding this list. See
+// StartSpinnerCmd to start it from elsewhere.
 func (m *Model) StartSpinner() tea.Cmd {
 	m.showSpinner = true
 	return m.spinner.Tick
@@ -656,28 +711,26 @@ func (m *Model) DisableQuitKeybindings() {
 	m.KeyMap.ForceQuit.SetEnabled(false)
 }
 
-// NewStatusMessage sets a new status message, which will show for a limited
-// amount of time. Note that this also returns a command.
-func (m *Model) NewStatusMessage(s string) tea.Cmd {
-	m.statusMessage = s
-	if m.statusMessageTimer != nil {
-		m.statusMessageTimer.Stop()
-	}
-
-	m.statusMessageTimer = time.NewTimer(m.StatusMessageLifetime)
-
-	// Wait for timeout
-	return func() tea.Msg {
-		<-m.statusMessageTimer.C
-		return statusMessageTimeoutMsg{}
-	}
-}
-
 // SetSize sets the width and height of this component.
 func (m *Model) SetSize(width, height int) {
 	m.setSize(width, height)
 }
 
+// HandleWindowSize sizes the list to fill the window, given the
+// tea.WindowSizeMsg the program received and the style the list is rendered
+// in, whose margins, borders and padding are taken off:
+//
+//	case tea.WindowSizeMsg:
+//	    m.list.HandleWindowSize(msg, docStyle)
+//
+// The list lays out its title bar, filter line, status bar, pagination and
+// help within that size as they're shown, hidden or toggled, so they needn't
+// be taken off too. Full help that would leave no room for an item is shown
+// as short help.
+func (m *Model) HandleWindowSize(msg tea.WindowSizeMsg, frame lipgloss.Style) {
+	m.setSize(max(0, msg.Width-frame.GetHorizontalFrameSize()), max(0, msg.Height-frame.GetVerticalFrameSize()))
+}
+
 // SetWidth sets the width of this component.
 func (m *Model) SetWidth(v int) {
 	m.setSize(v, m.height)
@@ -732,8 +785,15 @@ func (m *Model) updateKeybindings() {
 		m.KeyMap.GoToEnd.SetEnabled(false)
 		m.KeyMap.Filter.SetEnabled(false)
 		m.KeyMap.ClearFilter.SetEnabled(false)
+		m.KeyMap.TogglePin.SetEnabled(false)
+		m.KeyMap.CopyItems.SetEnabled(false)
+		m.KeyMap.OpenActions.SetEnabled(false)
+		m.KeyMap.ChooseAction.SetEnabled(false)
+		m.KeyMap.CloseActions.SetEnabled(false)
 		m.KeyMap.CancelWhileFiltering.SetEnabled(true)
 		m.KeyMap.AcceptWhileFiltering.SetEnabled(m.FilterInput.Value() != "")
+		m.KeyMap.PrevFilter.SetEnabled(len(m.filterHistory) > 0)
+		m.KeyMap.NextFilter.SetEnabled(len(m.filterHistory) > 0)
 		m.KeyMap.Quit.SetEnabled(false)
 		m.KeyMap.ShowFullHelp.SetEnabled(false)
 		m.KeyMap.CloseFullHelp.SetEnabled(false)
@@ -752,8 +812,15 @@ func (m *Model) updateKeybindings() {
 
 		m.KeyMap.Filter.SetEnabled(m.filteringEnabled && hasItems)
 		m.KeyMap.ClearFilter.SetEnabled(m.filterState == FilterApplied)
+		m.KeyMap.TogglePin.SetEnabled(m.pinningEnabled && hasItems)
+		m.KeyMap.CopyItems.SetEnabled(m.copyEnabled && hasItems)
+		m.KeyMap.OpenActions.SetEnabled(!m.showActions && hasItems && m.hasActions())
+		m.KeyMap.ChooseAction.SetEnabled(m.showActions)
+		m.KeyMap.CloseActions.SetEnabled(m.showActions)
 		m.KeyMap.CancelWhileFiltering.SetEnabled(false)
 		m.KeyMap.AcceptWhileFiltering.SetEnabled(false)
+		m.KeyMap.PrevFilter.SetEnabled(false)
+		m.KeyMap.NextFilter.SetEnabled(false)
 		m.KeyMap.Quit.SetEnabled(!m.disableQuitKeybindings)
 
 		if m.Help.ShowAll {
@@ -767,35 +834,52 @@ func (m *Model) updateKeybindings() {
 	}
 }
 
-// Update pagination according to the amount of items for the current state.
-func (m *Model) updatePagination() {
-	index := m.Index()
-	availHeight := m.height
+// itemsHeight returns the height available for items, after the title, status
+// bar, pagination and help.
+func (m Model) itemsHeight() int {
+	h := m.height - m.barsHeight()
+	if m.showHelp {
+		h -= lipgloss.Height(m.helpView())
+	}
+	return h
+}
 
+// barsHeight returns the height taken up by the title, status bar and
+// pagination.
+func (m Model) barsHeight() int {
+	var h int
 	if m.showTitle || (m.showFilter && m.filteringEnabled) {
-		availHeight -= lipgloss.Height(m.titleView())
+		h += lipgloss.Height(m.titleView())
 	}
 	if m.showStatusBar {
-		availHeight -= lipgloss.Height(m.statusView())
+		h += lipgloss.Height(m.statusView())
 	}
 	if m.showPagination {
-		availHeight -= lipgloss.Height(m.paginationView())
+		h += lipgloss.Height(m.paginationView())
 	}
-	if m.showHelp {
-		availHeight -= lipgloss.Height(m.helpView())
+	return h
+}
+
+// Update pagination according to the amount of items for the current state.
+func (m *Model) updatePagination() {
+	index := m.Index()
+	availHeight := m.itemsHeight()
+	if m.pinnedCount() > 0 {
+		availHeight-- // the divider below pinned items
 	}
 
-	m.Paginator.PerPage = max(1, availHeight/(m.delegate.Height()+m.delegate.Spacing()))
+	if !m.updatePages(availHeight) {
+		m.Paginator.PerPage = max(1, availHeight/(m.delegate.Height()+m.delegate.Spacing()))
 
-	if pages := len(m.VisibleItems()); pages < 1 {
-		m.Paginator.SetTotalPages(1)
-	} else {
-		m.Paginator.SetTotalPages(pages)
+		if pages := len(m.VisibleItems()); pages < 1 {
+			m.Paginator.SetTotalPages(1)
+		} else {
+			m.Paginator.SetTotalPages(pages)
+		}
 	}
 
 	// Restore index
-	m.Paginator.Page = index / m.Paginator.PerPage
-	m.cursor = index % m.Paginator.PerPage
+	m.Select(index)
 
 	// Make sure the page stays in bounds
 	if m.Paginator.Page >= m.Paginator.TotalPages-1 {
@@ -803,17 +887,14 @@ func (m *Model) updatePagination() {
 	}
 }
 
-func (m *Model) hideStatusMessage() {
-	m.statusMessage = ""
-	if m.statusMessageTimer != nil {
-		m.statusMessageTimer.Stop()
-	}
-}
-
 // Update is the Bubble Tea update loop.
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	if m.ownsSpinnerMsg(msg) {
+		return m, m.updateSpinner(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if key.Matches(msg, m.KeyMap.ForceQuit) {
@@ -822,20 +903,20 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 	case FilterMatchesMsg:
 		m.filteredItems = filteredItems(msg)
-		return m, nil
-
-	case spinner.TickMsg:
-		newSpinnerModel, cmd := m.spinner.Update(msg)
-		m.spinner = newSpinnerModel
-		if m.showSpinner {
-			cmds = append(cmds, cmd)
+		if m.reselectID != "" {
+			m.updatePagination()
+			m.selectID(m.reselectID)
+			m.reselectID = ""
 		}
+		return m, nil
 
 	case statusMessageTimeoutMsg:
-		m.hideStatusMessage()
+		cmds = append(cmds, m.expireStatusMessage(msg.id))
 	}
 
-	if m.filterState == Filtering {
+	if _, ok := msg.(tea.KeyMsg); ok && m.showActions {
+		cmds = append(cmds, m.handleActions(msg))
+	} else if m.filterState == Filtering {
 		cmds = append(cmds, m.handleFiltering(msg))
 	} else {
 		cmds = append(cmds, m.handleBrowsing(msg))
@@ -847,7 +928,6 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 // Updates for when a user is browsing the list.
 func (m *Model) handleBrowsing(msg tea.Msg) tea.Cmd {
 	var cmds []tea.Cmd
-	numItems := len(m.VisibleItems())
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -856,6 +936,7 @@ func (m *Model) handleBrowsing(msg tea.Msg) tea.Cmd {
 		// both mapped to escape.
 		case key.Matches(msg, m.KeyMap.ClearFilter):
 			m.resetFiltering()
+			return filterClearedCmd
 
 		case key.Matches(msg, m.KeyMap.Quit):
 			return tea.Quit
@@ -878,7 +959,16 @@ func (m *Model) handleBrowsing(msg tea.Msg) tea.Cmd {
 
 		case key.Matches(msg, m.KeyMap.GoToEnd):
 			m.Paginator.Page = m.Paginator.TotalPages - 1
-			m.cursor = m.Paginator.ItemsOnPage(numItems) - 1
+			m.cursor = m.itemsOnPage() - 1
+
+		case key.Matches(msg, m.KeyMap.TogglePin):
+			m.TogglePin(m.GlobalIndex())
+
+		case key.Matches(msg, m.KeyMap.CopyItems):
+			cmds = append(cmds, m.CopyVisibleItems())
+
+		case key.Matches(msg, m.KeyMap.OpenActions):
+			m.OpenActions()
 
 		case key.Matches(msg, m.KeyMap.Filter):
 			m.hideStatusMessage()
@@ -889,6 +979,7 @@ func (m *Model) handleBrowsing(msg tea.Msg) tea.Cmd {
 			m.Paginator.Page = 0
 			m.cursor = 0
 			m.filterState = Filtering
+			m.historyPos = len(m.filterHistory)
 			m.FilterInput.CursorEnd()
 			m.FilterInput.Focus()
 			m.updateKeybindings()
@@ -905,8 +996,13 @@ func (m *Model) handleBrowsing(msg tea.Msg) tea.Cmd {
 	cmd := m.delegate.Update(msg, m)
 	cmds = append(cmds, cmd)
 
+	// The delegate may have changed the height of items.
+	if m.pageStarts != nil {
+		m.updatePagination()
+	}
+
 	// Keep the index in bounds when paginating
-	itemsOnPage := m.Paginator.ItemsOnPage(len(m.VisibleItems()))
+	itemsOnPage := m.itemsOnPage()
 	if m.cursor > itemsOnPage-1 {
 		m.cursor = max(0, itemsOnPage-1)
 	}
@@ -925,6 +1021,17 @@ func (m *Model) handleFiltering(msg tea.Msg) tea.Cmd {
 			m.resetFiltering()
 			m.KeyMap.Filter.SetEnabled(true)
 			m.KeyMap.ClearFilter.SetEnabled(false)
+			cmds = append(cmds, filterClearedCmd)
+
+		case key.Matches(msg, m.KeyMap.PrevFilter):
+			cmd := m.stepFilterHistory(-1)
+			m.updatePagination()
+			return cmd
+
+		case key.Matches(msg, m.KeyMap.NextFilter):
+			cmd := m.stepFilterHistory(1)
+			m.updatePagination()
+			return cmd
 
 		case key.Matches(msg, m.KeyMap.AcceptWhileFiltering):
 			m.hideStatusMessage()
@@ -938,6 +1045,7 @@ func (m *Model) handleFiltering(msg tea.Msg) tea.Cmd {
 			// If we've filtered down to nothing, clear the filter
 			if len(h) == 0 {
 				m.resetFiltering()
+				cmds = append(cmds, filterClearedCmd)
 				break
 			}
 
@@ -947,7 +1055,10 @@ func (m *Model) handleFiltering(msg tea.Msg) tea.Cmd {
 
 			if m.FilterInput.Value() == "" {
 				m.resetFiltering()
+				cmds = append(cmds, filterClearedCmd)
+				break
 			}
+			cmds = append(cmds, m.filterAppliedCmd())
 		}
 	}
 
@@ -992,6 +1103,9 @@ func (m Model) ShortHelp() []key.Binding {
 		m.KeyMap.ClearFilter,
 		m.KeyMap.AcceptWhileFiltering,
 		m.KeyMap.CancelWhileFiltering,
+		m.KeyMap.OpenActions,
+		m.KeyMap.ChooseAction,
+		m.KeyMap.CloseActions,
 	)
 
 	if !filtering && m.AdditionalShortHelpKeys != nil {
@@ -1029,8 +1143,15 @@ func (m Model) FullHelp() [][]key.Binding {
 	listLevelBindings := []key.Binding{
 		m.KeyMap.Filter,
 		m.KeyMap.ClearFilter,
+		m.KeyMap.TogglePin,
+		m.KeyMap.CopyItems,
+		m.KeyMap.OpenActions,
+		m.KeyMap.ChooseAction,
+		m.KeyMap.CloseActions,
 		m.KeyMap.AcceptWhileFiltering,
 		m.KeyMap.CancelWhileFiltering,
+		m.KeyMap.PrevFilter,
+		m.KeyMap.NextFilter,
 	}
 
 	if !filtering && m.AdditionalFullHelpKeys != nil {
@@ -1090,6 +1211,13 @@ func (m Model) View() string {
 	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
 
+// RenderString renders the list deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	m.SetSize(width, height)
+	return render.View(m.View, width, height)
+}
+
 func (m Model) titleView() string {
 	var (
 		view          string
@@ -1117,8 +1245,8 @@ func (m Model) titleView() string {
 
 		// Status message
 		if m.filterState != Filtering {
-			view += "  " + m.statusMessage
-			view = ansi.Truncate(view, m.width-spinnerWidth, ellipsis)
+			view += "  " + m.statusMessageView()
+			view = textutil.Truncate(view, m.width-spinnerWidth, ellipsis)
 		}
 	}
 
@@ -1169,7 +1297,7 @@ func (m Model) statusView() string {
 
 		if filtered {
 			f := strings.TrimSpace(m.FilterInput.Value())
-			f = ansi.Truncate(f, 10, "…") //nolint:mnd
+			f = textutil.Truncate(f, 10, textutil.Ellipsis) //nolint:mnd
 			status += fmt.Sprintf("“%s” ", f)
 		}
 
@@ -1211,6 +1339,7 @@ func (m Model) populatedView() string {
 	items := m.VisibleItems()
 
 	var b strings.Builder
+	first, last := -1, -1
 
 	// Empty states
 	if len(items) == 0 {
@@ -1221,21 +1350,43 @@ func (m Model) populatedView() string {
 	}
 
 	if len(items) > 0 {
-		start, end := m.Paginator.GetSliceBounds(len(items))
+		start, end := m.pageBounds(m.Paginator.Page)
 		docs := items[start:end]
 
+		pinned := m.pinnedCount()
+		divided := false
 		for i, item := range docs {
+			selected := i+start == m.Index()
+			if selected {
+				first = strings.Count(b.String(), "\n")
+			}
 			m.delegate.Render(&b, m, i+start, item)
+			if selected {
+				last = strings.Count(b.String(), "\n")
+			}
 			if i != len(docs)-1 {
+				if i+start == pinned-1 {
+					fmt.Fprint(&b, "\n"+m.pinDividerView())
+					divided = true
+				}
 				fmt.Fprint(&b, strings.Repeat("\n", m.delegate.Spacing()+1))
 			}
 		}
+
+		// Make up for the line reserved for the divider on pages without it.
+		if pinned > 0 && !divided {
+			fmt.Fprint(&b, "\n")
+		}
 	}
 
 	// If there aren't enough items to fill up this page (always the last page)
 	// then we need to add some newlines to fill up the space where items would
 	// have been.
-	itemsOnPage := m.Paginator.ItemsOnPage(len(items))
+	if m.pageStarts != nil {
+		fmt.Fprint(&b, strings.Repeat("\n", m.pageFiller(m.itemsHeight()-min(1, m.pinnedCount()))))
+		return m.overlayActionMenu(b.String(), first, last)
+	}
+	itemsOnPage := m.itemsOnPage()
 	if itemsOnPage < m.Paginator.PerPage {
 		n := (m.Paginator.PerPage - itemsOnPage) * (m.delegate.Height() + m.delegate.Spacing())
 		if len(items) == 0 {
@@ -1244,11 +1395,17 @@ func (m Model) populatedView() string {
 		fmt.Fprint(&b, strings.Repeat("\n", n))
 	}
 
-	return b.String()
+	return m.overlayActionMenu(b.String(), first, last)
 }
 
 func (m Model) helpView() string {
-	return m.Styles.HelpStyle.Render(m.Help.View(m))
+	help := m.Styles.HelpStyle.Render(m.Help.View(m))
+	if m.Help.ShowAll && m.height-m.barsHeight()-lipgloss.Height(help) < m.delegate.Height() {
+		short := m.Help
+		short.ShowAll = false
+		return m.Styles.HelpStyle.Render(short.View(m))
+	}
+	return help
 }
 
 func (m Model) spinnerView() string {
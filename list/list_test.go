@@ -1,15 +1,31 @@
 package list
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/mikeflynn/bubbles/clipboard"
+	"github.com/mikeflynn/bubbles/spinner"
 )
 
+func TestMain(m *testing.M) {
+	// The list's spinner animates unless the environment asks for reduced
+	// motion.
+	os.Unsetenv(spinner.ReducedMotionEnv) //nolint:errcheck
+	os.Exit(m.Run())
+}
+
 type item string
 
 func (i item) FilterValue() string { return string(i) }
@@ -135,3 +151,568 @@ func TestSetFilterState(t *testing.T) {
 		t.Fatalf("Error: expected view to contain '%s'", expected)
 	}
 }
+
+type checkItem struct {
+	title   string
+	checked bool
+}
+
+func (i checkItem) FilterValue() string { return i.title }
+func (i checkItem) Title() string       { return i.title }
+func (i checkItem) Description() string { return "" }
+func (i checkItem) Checked() bool       { return i.checked }
+func (i checkItem) SetChecked(c bool) CheckableItem {
+	i.checked = c
+	return i
+}
+
+func TestCheckboxDelegate(t *testing.T) {
+	list := New([]Item{checkItem{title: "foo"}, checkItem{title: "bar"}}, NewCheckboxDelegate(), 20, 10)
+
+	list.CursorDown()
+	list, cmd := list.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+
+	toggled, ok := cmd().(ItemToggledMsg)
+	if !ok {
+		t.Fatalf("expected an ItemToggledMsg, got %T", cmd())
+	}
+	if toggled.Index != 1 || !toggled.Item.Checked() {
+		t.Fatalf("unexpected toggle message: %+v", toggled)
+	}
+
+	checked := list.CheckedItems()
+	if len(checked) != 1 || checked[0].Title() != "bar" {
+		t.Fatalf("expected bar to be checked, got %v", checked)
+	}
+
+	view := list.View()
+	if !strings.Contains(view, "[ ] foo") || !strings.Contains(view, "[x] bar") {
+		t.Fatalf("expected checkboxes to be rendered, got:\n%s", view)
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	items := []Item{item("apple"), item("banana"), item("cherry")}
+
+	t.Run("default", func(t *testing.T) {
+		m := New(items, itemDelegate{}, 0, 0)
+		m.Title = "Fruit"
+		golden.RequireEqual(t, []byte(RenderString(m, 30, 12)))
+	})
+	t.Run("filtered", func(t *testing.T) {
+		m := New(items, itemDelegate{}, 0, 0)
+		m.Title = "Fruit"
+		m.SetFilterText("an")
+		golden.RequireEqual(t, []byte(RenderString(m, 30, 12)))
+	})
+	t.Run("empty", func(t *testing.T) {
+		m := New(nil, itemDelegate{}, 0, 0)
+		m.Title = "Fruit"
+		golden.RequireEqual(t, []byte(RenderString(m, 30, 12)))
+	})
+}
+
+func TestNewWithOptions(t *testing.T) {
+	items := []Item{item("foo"), item("bar")}
+
+	m, err := NewWithOptions(items,
+		WithSize(40, 20),
+		WithDelegate(itemDelegate{}),
+		WithTitle("Things"),
+		WithStatusBarItemName("thing", "things"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Width() != 40 || m.Height() != 20 || m.Title != "Things" {
+		t.Fatalf("options were not applied: %dx%d %q", m.Width(), m.Height(), m.Title)
+	}
+	if len(m.VisibleItems()) != 2 {
+		t.Fatalf("expected 2 visible items, got %d", len(m.VisibleItems()))
+	}
+
+	for name, opts := range map[string][]Option{
+		"negative size": {WithSize(-1, 10)},
+		"nil delegate":  {WithDelegate(nil)},
+		"too short":     {WithSize(40, 4)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewWithOptions(items, opts...); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestPinning(t *testing.T) {
+	items := []Item{item("apple"), item("banana"), item("cherry"), item("date")}
+	m := New(items, itemDelegate{}, 30, 14)
+	m.SetPinningEnabled(true)
+
+	m.Select(2)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if got := m.Items()[0]; got != item("cherry") || !m.IsPinned(got) {
+		t.Fatalf("expected cherry to be pinned at the top, got %v", m.Items())
+	}
+	if m.SelectedItem() != item("cherry") {
+		t.Fatalf("expected cherry to stay selected, got %v", m.SelectedItem())
+	}
+	golden.RequireEqual(t, []byte(RenderString(m, 30, 14)))
+
+	m.TogglePin(0)
+	if m.IsPinned(item("cherry")) || m.Items()[0] != item("cherry") {
+		t.Fatalf("expected cherry to be unpinned in place, got %v", m.Items())
+	}
+}
+
+func TestRecency(t *testing.T) {
+	items := []Item{item("apple"), item("banana"), item("cherry")}
+	m := New(items, itemDelegate{}, 30, 14)
+	m.SetPinningEnabled(true)
+	m.SetSortByRecency(true)
+
+	now := time.Now()
+	m.SetState(State{
+		Pinned: []string{"cherry"},
+		LastUsed: map[string]time.Time{
+			"apple":  now.Add(-time.Hour),
+			"banana": now,
+		},
+	})
+	if got := fmt.Sprint(m.Items()); got != "[cherry banana apple]" {
+		t.Fatalf("expected pinned then most recent first, got %s", got)
+	}
+
+	m.MarkUsed(2)
+	if got := fmt.Sprint(m.Items()); got != "[cherry apple banana]" {
+		t.Fatalf("expected apple to move up after use, got %s", got)
+	}
+
+	b, err := json.Marshal(m.State())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s State
+	if err := json.Unmarshal(b, &s); err != nil {
+		t.Fatal(err)
+	}
+	other := New([]Item{item("apple"), item("banana"), item("cherry")}, itemDelegate{}, 30, 14)
+	other.SetPinningEnabled(true)
+	other.SetSortByRecency(true)
+	other.SetState(s)
+	if got := fmt.Sprint(other.Items()); got != "[cherry apple banana]" {
+		t.Fatalf("expected the restored state to give the same order, got %s", got)
+	}
+}
+
+type task struct {
+	title, tag string
+	due        time.Time
+}
+
+func (t task) FilterValue() string { return t.title }
+
+func TestColumnsDelegate(t *testing.T) {
+	due := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	d := NewColumnsDelegate(
+		ItemColumn{Value: func(i Item) string { return i.(task).title }, Filter: true},
+		ItemColumn{Value: func(i Item) string { return i.(task).tag }, Width: 6},
+		ItemColumn{Value: func(i Item) string { return i.(task).due.Format("Jan 2") }, Width: 6, Align: lipgloss.Right},
+	)
+	items := []Item{
+		task{"Write the quarterly report", "work", due},
+		task{"Buy milk", "home", due.AddDate(0, 0, 9)},
+		task{"Fix the bike", "home", due.AddDate(0, 1, 0)},
+	}
+
+	t.Run("default", func(t *testing.T) {
+		m := New(items, d, 0, 0)
+		m.Title = "Tasks"
+		golden.RequireEqual(t, []byte(RenderString(m, 36, 10)))
+	})
+	t.Run("filtered", func(t *testing.T) {
+		m := New(items, d, 0, 0)
+		m.Title = "Tasks"
+		m.SetFilterText("bi")
+		golden.RequireEqual(t, []byte(RenderString(m, 36, 10)))
+	})
+}
+
+func TestStatusMessages(t *testing.T) {
+	l := New([]Item{item("foo")}, itemDelegate{}, 40, 10)
+	l.StatusMessageLifetime = time.Millisecond
+
+	current := func() string {
+		msg, _ := l.CurrentStatusMessage()
+		return msg.Text
+	}
+
+	l.QueueStatusMessage(StatusMessage{ID: "sync", Text: "syncing", Sticky: true})
+	cmd := l.QueueStatusMessage(StatusMessage{Text: "saved"})
+	l.QueueStatusMessage(StatusMessage{Text: "disk full", Level: StatusError})
+	if got := current(); got != "saved" {
+		t.Fatalf("expected the first transient message to be shown, got %q", got)
+	}
+	if !strings.Contains(l.View(), "saved") {
+		t.Fatalf("expected the view to contain the status message")
+	}
+
+	// Expire "saved", which reports it and starts the timer of the next one.
+	l, cmd = l.Update(cmd())
+	msgs := cmd().(tea.BatchMsg)
+	if msg, ok := msgs[0]().(StatusExpiredMsg); !ok || msg.Message.Text != "saved" {
+		t.Fatalf("expected a StatusExpiredMsg for the expired message, got %#v", msg)
+	}
+	if got := current(); got != "disk full" {
+		t.Fatalf("expected the next message to be shown, got %q", got)
+	}
+
+	l, _ = l.Update(msgs[1]())
+	if got := current(); got != "syncing" {
+		t.Fatalf("expected the sticky message once the others expired, got %q", got)
+	}
+
+	l.ClearStatusMessage("sync")
+	if _, ok := l.CurrentStatusMessage(); ok {
+		t.Fatalf("expected no status message after clearing, got %v", l.StatusMessages())
+	}
+
+	// NewStatusMessage replaces transient messages but keeps sticky ones.
+	l.QueueStatusMessage(StatusMessage{Text: "a"})
+	l.QueueStatusMessage(StatusMessage{Text: "b", Sticky: true})
+	l.NewStatusMessage("c")
+	if got := len(l.StatusMessages()); got != 2 || current() != "c" {
+		t.Fatalf("expected c and the sticky message, got %v", l.StatusMessages())
+	}
+}
+
+type idItem struct {
+	id, title string
+}
+
+func (i idItem) ID() string          { return i.id }
+func (i idItem) FilterValue() string { return i.title }
+
+func TestSetItemsKeepsSelection(t *testing.T) {
+	items := func(ids ...string) []Item {
+		var items []Item
+		for _, id := range ids {
+			items = append(items, idItem{id: id, title: "item " + id})
+		}
+		return items
+	}
+	m := New(items("a", "b", "c", "d", "e", "f"), NewDefaultDelegate(), 20, 14)
+	m.Select(4)
+
+	m.SetItems(items("x", "y", "a", "b", "c", "d", "e", "f"))
+	if got := m.SelectedItem().(idItem).id; got != "e" {
+		t.Fatalf("expected e to stay selected, got %s", got)
+	}
+	if m.Paginator.Page != m.Index()/m.Paginator.PerPage {
+		t.Fatalf("expected the selected item's page to be shown")
+	}
+
+	// Without the item, the selection stays at its index.
+	m.SetItems(items("a", "b", "c", "d", "f", "g", "h", "i"))
+	if got := m.Index(); got != 6 {
+		t.Fatalf("expected the index to be kept, got %d", got)
+	}
+
+	// While filtering, the item is selected once the filter's been rerun.
+	m.SetFilterText("item")
+	m.Select(1)
+	cmd := m.SetItems(items("z", "a", "b"))
+	m, _ = m.Update(cmd())
+	if got := m.SelectedItem().(idItem).id; got != "b" {
+		t.Fatalf("expected b to stay selected while filtering, got %s", got)
+	}
+}
+
+// tallDelegate renders each item on as many lines as its length.
+type tallDelegate struct{ itemDelegate }
+
+func (d tallDelegate) ItemHeight(_ Model, _ int, listItem Item) int {
+	return len(listItem.FilterValue())
+}
+
+func (d tallDelegate) Render(w io.Writer, _ Model, _ int, listItem Item) {
+	s := listItem.FilterValue()
+	fmt.Fprint(w, strings.TrimSuffix(strings.Repeat(s+"\n", len(s)), "\n"))
+}
+
+func TestVariableHeights(t *testing.T) {
+	items := []Item{item("a"), item("bbb"), item("cc"), item("d"), item("eeeee")}
+	m := New(items, tallDelegate{}, 10, 4)
+	m.SetShowTitle(false)
+	m.SetShowFilter(false)
+	m.SetShowStatusBar(false)
+	m.SetShowPagination(false)
+	m.SetShowHelp(false)
+
+	if !reflect.DeepEqual(m.pageStarts, []int{0, 2, 4}) {
+		t.Fatalf("expected pages to start at [0 2 4], got %v", m.pageStarts)
+	}
+	if m.Paginator.TotalPages != 3 {
+		t.Fatalf("expected 3 pages, got %d", m.Paginator.TotalPages)
+	}
+
+	m.Select(3)
+	if m.Paginator.Page != 1 || m.Cursor() != 1 || m.Index() != 3 {
+		t.Fatalf("expected item 3 on page 1 at 1, got page %d at %d, index %d", m.Paginator.Page, m.Cursor(), m.Index())
+	}
+	if view, expected := m.populatedView(), "cc\ncc\nd\n"; view != expected {
+		t.Fatalf("expected view %q, got %q", expected, view)
+	}
+
+	// The last item is taller than the list, so it gets a page of its own.
+	m.CursorDown()
+	if m.Paginator.Page != 2 || m.Index() != 4 {
+		t.Fatalf("expected item 4 on page 2, got page %d, index %d", m.Paginator.Page, m.Index())
+	}
+
+	m.CursorUp()
+	if m.Paginator.Page != 1 || m.Index() != 3 {
+		t.Fatalf("expected item 3 on page 1, got page %d, index %d", m.Paginator.Page, m.Index())
+	}
+
+	m.SetHeight(6)
+	if !reflect.DeepEqual(m.pageStarts, []int{0, 3}) || m.Index() != 3 {
+		t.Fatalf("expected pages [0 3] with item 3 selected, got %v and %d", m.pageStarts, m.Index())
+	}
+}
+
+func TestSpinnerRouting(t *testing.T) {
+	a := New([]Item{item("foo")}, itemDelegate{}, 20, 10)
+	b := New([]Item{item("bar")}, itemDelegate{}, 20, 10)
+
+	var cmd tea.Cmd
+	a, cmd = a.Update(a.StartSpinnerCmd()())
+	if !a.showSpinner || cmd == nil {
+		t.Fatal("expected the spinner to start")
+	}
+	b, _ = b.Update(b.StopSpinnerCmd()())
+	b, _ = b.Update(a.StartSpinnerCmd()())
+	if b.showSpinner {
+		t.Fatal("expected another list's message to be ignored")
+	}
+
+	tick := cmd()
+	before := b.spinnerView()
+	if b, cmd = b.Update(tick); cmd != nil || b.spinnerView() != before {
+		t.Fatal("expected another list's tick to be ignored")
+	}
+	before = a.spinnerView()
+	if a, cmd = a.Update(tick); cmd == nil || a.spinnerView() == before {
+		t.Fatal("expected the list's tick to advance its spinner")
+	}
+
+	a, _ = a.Update(a.StopSpinnerCmd()())
+	if a.showSpinner {
+		t.Fatal("expected the spinner to stop")
+	}
+}
+
+func TestFilterHistory(t *testing.T) {
+	m := New([]Item{item("foo"), item("bar"), item("baz")}, itemDelegate{}, 20, 10)
+
+	// cmdMsgs runs a command and the commands it batches, returning their
+	// messages, but for the filter results which run in the background.
+	var cmdMsgs func(tea.Cmd) []tea.Msg
+	cmdMsgs = func(cmd tea.Cmd) []tea.Msg {
+		if cmd == nil {
+			return nil
+		}
+		switch msg := cmd().(type) {
+		case tea.BatchMsg:
+			var msgs []tea.Msg
+			for _, c := range msg {
+				msgs = append(msgs, cmdMsgs(c)...)
+			}
+			return msgs
+		default:
+			return []tea.Msg{msg}
+		}
+	}
+	apply := func(filter string) []tea.Msg {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(filter)})
+		m, _ = m.Update(filterItems(m)())
+		var cmd tea.Cmd
+		m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		return cmdMsgs(cmd)
+	}
+
+	if msgs := apply("ba"); !slices.Contains(msgs, tea.Msg(FilterAppliedMsg{Text: "ba"})) {
+		t.Fatalf("expected the filter to be reported applied, got %v", msgs)
+	}
+	if m.FilterText() != "ba" {
+		t.Fatalf("expected filter text %q, got %q", "ba", m.FilterText())
+	}
+	var cmd tea.Cmd
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if msgs := cmdMsgs(cmd); !slices.Contains(msgs, tea.Msg(FilterClearedMsg{})) || m.FilterText() != "" {
+		t.Fatalf("expected the filter to be reported cleared, got %v", msgs)
+	}
+
+	apply("fo")
+	m.SetFilterText("ba")
+	if want := []string{"fo", "ba"}; !reflect.DeepEqual(m.FilterHistory(), want) {
+		t.Fatalf("expected history %v, got %v", want, m.FilterHistory())
+	}
+
+	m.ResetFilter()
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	for _, step := range []struct {
+		key  tea.KeyType
+		want string
+	}{
+		{tea.KeyUp, "ba"},
+		{tea.KeyUp, "fo"},
+		{tea.KeyUp, "fo"},
+		{tea.KeyDown, "ba"},
+		{tea.KeyDown, "z"},
+		{tea.KeyDown, "z"},
+	} {
+		m, _ = m.Update(tea.KeyMsg{Type: step.key})
+		if m.FilterValue() != step.want {
+			t.Fatalf("expected filter %q, got %q", step.want, m.FilterValue())
+		}
+	}
+}
+
+type actionItem string
+
+func (i actionItem) FilterValue() string { return string(i) }
+func (i actionItem) Actions() []Action   { return []Action{{Name: "restore"}} }
+
+func TestActions(t *testing.T) {
+	m := New([]Item{item("foo"), item("bar"), actionItem("baz")}, itemDelegate{}, 30, 12)
+	m.SetActions([]Action{
+		{Name: "open", Title: "Open"},
+		{Name: "rename", Title: "Rename", Key: "r"},
+		{Name: "delete", Title: "Delete", Key: "d"},
+	})
+	press := func(s string) tea.Cmd {
+		var cmd tea.Cmd
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+		switch s {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		}
+		m, cmd = m.Update(msg)
+		return cmd
+	}
+	chosen := func(cmd tea.Cmd) ActionChosenMsg {
+		t.Helper()
+		if cmd == nil {
+			t.Fatal("expected an action to be chosen")
+		}
+		msg, ok := cmd().(ActionChosenMsg)
+		if !ok {
+			t.Fatalf("expected an ActionChosenMsg, got %#v", cmd())
+		}
+		return msg
+	}
+
+	press("down")
+	press(".")
+	if !m.ShowingActions() {
+		t.Fatal("expected the action menu to open")
+	}
+	golden.RequireEqual(t, []byte(RenderString(m, 30, 12)))
+
+	press("down")
+	if msg := chosen(press("enter")); msg.Action.Name != "rename" || msg.Item != item("bar") || msg.Index != 1 {
+		t.Fatalf("expected bar to be renamed, got %+v", msg)
+	}
+	if m.ShowingActions() {
+		t.Fatal("expected the menu to close once an action is chosen")
+	}
+
+	press(".")
+	if msg := chosen(press("d")); msg.Action.Name != "delete" {
+		t.Fatalf("expected the action's key to choose it, got %+v", msg)
+	}
+
+	press("down")
+	press(".")
+	if msg := chosen(press("enter")); msg.Action.Name != "restore" || msg.Item != actionItem("baz") {
+		t.Fatalf("expected the item's own actions, got %+v", msg)
+	}
+}
+
+func TestHandleWindowSize(t *testing.T) {
+	items := make([]Item, 30)
+	for i := range items {
+		items[i] = item(fmt.Sprint(i))
+	}
+	m := New(items, NewDefaultDelegate(), 0, 0)
+	frame := lipgloss.NewStyle().Margin(1, 2).Border(lipgloss.NormalBorder())
+
+	m.HandleWindowSize(tea.WindowSizeMsg{Width: 40, Height: 12}, frame)
+	if m.Width() != 34 || m.Height() != 8 {
+		t.Fatalf("expected the frame to be taken off, got %dx%d", m.Width(), m.Height())
+	}
+	if h := lipgloss.Height(m.View()); h != m.Height() {
+		t.Fatalf("expected the view to be %d rows, got %d", m.Height(), h)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	if h := lipgloss.Height(m.View()); h != m.Height() {
+		t.Fatalf("expected full help that doesn't fit to be left out, got %d rows", h)
+	}
+
+	m.HandleWindowSize(tea.WindowSizeMsg{Width: 40, Height: 30}, frame)
+	if v := m.View(); lipgloss.Height(v) != m.Height() || !strings.Contains(v, "close help") {
+		t.Fatalf("expected the full help to show in %d rows, got %q", m.Height(), v)
+	}
+}
+
+func TestExport(t *testing.T) {
+	items := []Item{item("foo"), checkItem{title: "bar\nbaz"}, item("bat")}
+	m, err := NewWithOptions(items, WithCopyEnabled(true), WithSize(20, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetFilterText("ba")
+	m.SetFilterState(FilterApplied)
+
+	if got, want := m.VisibleItemsAsStrings(), []string{"bat", "bar\nbaz"}; !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got, _ := m.Export(PlainText); got != "bat\nbar baz" {
+		t.Errorf("plain text: expected one item per line in ranked order, got %q", got)
+	}
+	got, err := m.Export(JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []any
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+		t.Fatalf("expected valid JSON, got %q: %v", got, err)
+	}
+	if want := []any{"bat", map[string]any{"title": "bar\nbaz"}}; !reflect.DeepEqual(decoded, want) {
+		t.Errorf("JSON: expected %v, got %v", want, decoded)
+	}
+
+	var out strings.Builder
+	m.Clipboard = clipboard.NewOSC52(&out)
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlY})
+	copied, ok := cmd().(CopiedMsg)
+	if !ok {
+		t.Fatal("expected the items to be copied")
+	}
+	if copied.Text != "bat\nbar baz" || copied.Count != 2 || copied.Err != nil {
+		t.Fatalf("unexpected message %#v", copied)
+	}
+	if want := ansi.SetSystemClipboard(copied.Text); out.String() != want {
+		t.Errorf("expected an OSC 52 sequence, got %q", out.String())
+	}
+
+	m.SetCopyEnabled(false)
+	if m.KeyMap.CopyItems.Enabled() {
+		t.Error("expected the copy keybinding to be disabled")
+	}
+}
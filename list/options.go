@@ -0,0 +1,121 @@
+package list
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Option is used to set options in NewWithOptions. For example:
+//
+//	l, err := NewWithOptions(items,
+//		WithSize(40, 20),
+//		WithDelegate(NewDefaultDelegate()),
+//		WithTitle("Groceries"),
+//	)
+type Option func(*Model)
+
+// WithSize sets the width and height of the list.
+func WithSize(width, height int) Option {
+	return func(m *Model) {
+		m.width = width
+		m.height = height
+	}
+}
+
+// WithDelegate sets the delegate used to render and update items. It defaults
+// to a DefaultDelegate.
+func WithDelegate(d ItemDelegate) Option {
+	return func(m *Model) {
+		m.delegate = d
+	}
+}
+
+// WithStyles sets the styles of the list, including those of the spinner,
+// filter input and paginator it contains.
+func WithStyles(s Styles) Option {
+	return func(m *Model) {
+		m.Styles = s
+		m.spinner.Style = s.Spinner
+		m.FilterInput.PromptStyle = s.FilterPrompt
+		m.FilterInput.Cursor.Style = s.FilterCursor
+		m.Paginator.ActiveDot = s.ActivePaginationDot.String()
+		m.Paginator.InactiveDot = s.InactivePaginationDot.String()
+	}
+}
+
+// WithTitle sets the title of the list.
+func WithTitle(title string) Option {
+	return func(m *Model) {
+		m.Title = title
+	}
+}
+
+// WithKeyMap sets the keymap of the list.
+func WithKeyMap(km KeyMap) Option {
+	return func(m *Model) {
+		m.KeyMap = km
+	}
+}
+
+// WithFilteringEnabled sets whether the list can be filtered.
+func WithFilteringEnabled(v bool) Option {
+	return func(m *Model) {
+		m.filteringEnabled = v
+	}
+}
+
+// WithStatusBarItemName sets the singular and plural names of items shown in
+// the status bar.
+func WithStatusBarItemName(singular, plural string) Option {
+	return func(m *Model) {
+		m.itemNameSingular = singular
+		m.itemNamePlural = plural
+	}
+}
+
+// WithCopyEnabled sets whether the CopyItems keybinding copies the visible
+// items. See SetCopyEnabled.
+func WithCopyEnabled(v bool) Option {
+	return func(m *Model) {
+		m.copyEnabled = v
+	}
+}
+
+// NewWithOptions returns a new list configured by the given options. Unlike
+// New it checks the configuration up front, returning an error if it can't be
+// rendered, such as when the list is too short to show a single item.
+func NewWithOptions(items []Item, opts ...Option) (Model, error) {
+	m := New(items, NewDefaultDelegate(), 0, 0)
+	for _, opt := range opts {
+		opt(&m)
+	}
+
+	if err := m.validate(); err != nil {
+		return Model{}, err
+	}
+
+	m.setSize(m.width, m.height)
+	m.updateKeybindings()
+	return m, nil
+}
+
+// validate returns an error if the list's configuration can't be rendered.
+func (m Model) validate() error {
+	if m.width < 0 || m.height < 0 {
+		return fmt.Errorf("list: invalid size %dx%d", m.width, m.height)
+	}
+	if m.delegate == nil {
+		return errors.New("list: delegate is nil")
+	}
+	if m.delegate.Height() < 1 {
+		return fmt.Errorf("list: delegate height must be at least 1, got %d", m.delegate.Height())
+	}
+	if m.Filter == nil && m.filteringEnabled {
+		return errors.New("list: filtering is enabled but the filter is nil")
+	}
+	if h := m.itemsHeight(); m.height > 0 && h < m.delegate.Height() {
+		return fmt.Errorf("list: height %d leaves %d lines for items, but the delegate needs %d",
+			m.height, max(0, h), m.delegate.Height())
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package list
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Keyer can be implemented by items to identify them for pinning and recency
+// tracking. Items that don't implement it are identified by their
+// FilterValue.
+type Keyer interface {
+	Key() string
+}
+
+func itemKey(i Item) string {
+	if k, ok := i.(Keyer); ok {
+		return k.Key()
+	}
+	return i.FilterValue()
+}
+
+// State holds the pinned items and the times items were last used, keyed as
+// described by Keyer. It's meant to be persisted between runs, such as by
+// encoding it with encoding/json, and given back to the list with SetState.
+type State struct {
+	Pinned   []string             `json:"pinned,omitempty"`
+	LastUsed map[string]time.Time `json:"last_used,omitempty"`
+}
+
+// State returns the list's pinned items and the times items were last used.
+func (m Model) State() State {
+	var s State
+	for k := range m.pinned {
+		s.Pinned = append(s.Pinned, k)
+	}
+	sort.Strings(s.Pinned)
+	if len(m.lastUsed) > 0 {
+		s.LastUsed = make(map[string]time.Time, len(m.lastUsed))
+		for k, t := range m.lastUsed {
+			s.LastUsed[k] = t
+		}
+	}
+	return s
+}
+
+// SetState restores the pinned items and the times items were last used, as
+// returned by State, and reorders the items accordingly.
+func (m *Model) SetState(s State) {
+	m.pinned = make(map[string]bool, len(s.Pinned))
+	for _, k := range s.Pinned {
+		m.pinned[k] = true
+	}
+	m.lastUsed = make(map[string]time.Time, len(s.LastUsed))
+	for k, t := range s.LastUsed {
+		m.lastUsed[k] = t
+	}
+	m.sortItems()
+}
+
+// SetPinningEnabled enables or disables pinning. When enabled, pinned items
+// are sorted to a section at the top of the list, set apart by a divider,
+// and the TogglePin keybinding pins and unpins the selected item.
+func (m *Model) SetPinningEnabled(v bool) {
+	m.pinningEnabled = v
+	m.sortItems()
+	m.updatePagination()
+	m.updateKeybindings()
+}
+
+// PinningEnabled returns whether pinning is enabled.
+func (m Model) PinningEnabled() bool {
+	return m.pinningEnabled
+}
+
+// SetSortByRecency sets whether items are ordered by the time they were last
+// used, as recorded by MarkUsed, most recent first. Items that were never
+// used keep their order after those that were. Pinned items still come first.
+func (m *Model) SetSortByRecency(v bool) {
+	m.sortByRecency = v
+	m.sortItems()
+}
+
+// SortByRecency returns whether items are ordered by the time they were last
+// used.
+func (m Model) SortByRecency() bool {
+	return m.sortByRecency
+}
+
+// IsPinned returns whether the given item is pinned.
+func (m Model) IsPinned(item Item) bool {
+	return m.pinned[itemKey(item)]
+}
+
+// TogglePin pins or unpins the item at the given index in the unfiltered list
+// of items, as returned by GlobalIndex. The item stays selected as it moves.
+func (m *Model) TogglePin(index int) {
+	if index < 0 || index >= len(m.items) {
+		return
+	}
+	k := itemKey(m.items[index])
+	if m.pinned == nil {
+		m.pinned = make(map[string]bool)
+	}
+	if m.pinned[k] {
+		delete(m.pinned, k)
+	} else {
+		m.pinned[k] = true
+	}
+	m.sortItems()
+	m.updatePagination()
+}
+
+// MarkUsed records the item at the given index in the unfiltered list of
+// items as used now, such as when it's chosen. If the list is sorted by
+// recency the item moves accordingly.
+func (m *Model) MarkUsed(index int) {
+	if index < 0 || index >= len(m.items) {
+		return
+	}
+	if m.lastUsed == nil {
+		m.lastUsed = make(map[string]time.Time)
+	}
+	m.lastUsed[itemKey(m.items[index])] = time.Now()
+	m.sortItems()
+}
+
+// sortItems moves pinned items to the top and, if enabled, orders items by
+// recency, keeping the selected item selected and the current filter
+// results pointing at the right items.
+func (m *Model) sortItems() {
+	if (!m.pinningEnabled && !m.sortByRecency) || len(m.items) == 0 {
+		return
+	}
+
+	selected := m.GlobalIndex()
+	order := make([]int, len(m.items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := itemKey(m.items[order[i]]), itemKey(m.items[order[j]])
+		if m.pinningEnabled && m.pinned[a] != m.pinned[b] {
+			return m.pinned[a]
+		}
+		if m.sortByRecency {
+			return m.lastUsed[a].After(m.lastUsed[b])
+		}
+		return false
+	})
+
+	items := make([]Item, len(m.items))
+	moved := make([]int, len(m.items))
+	for i, from := range order {
+		items[i] = m.items[from]
+		moved[from] = i
+	}
+	m.items = items
+
+	for i := range m.filteredItems {
+		m.filteredItems[i].index = moved[m.filteredItems[i].index]
+	}
+	if m.filterState == Unfiltered && selected >= 0 && selected < len(moved) {
+		m.Select(moved[selected])
+	}
+}
+
+// pinnedCount returns the number of items in the pinned section when the
+// divider below it should be shown, or 0 otherwise.
+func (m Model) pinnedCount() int {
+	if !m.pinningEnabled || m.filterState != Unfiltered {
+		return 0
+	}
+	n := 0
+	for _, item := range m.items {
+		if m.pinned[itemKey(item)] {
+			n++
+		}
+	}
+	if n == len(m.items) {
+		return 0
+	}
+	return n
+}
+
+func (m Model) pinDividerView() string {
+	w := max(0, m.width-m.Styles.PinDivider.GetHorizontalFrameSize())
+	return m.Styles.PinDivider.Render(strings.Repeat("─", w))
+}
@@ -0,0 +1,68 @@
+package list
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mikeflynn/bubbles/spinner"
+)
+
+// spinnerStateMsg starts or stops the spinner of the list with the given
+// spinner ID.
+type spinnerStateMsg struct {
+	id   int
+	show bool
+}
+
+// StartSpinnerCmd returns a command which starts the spinner once its message
+// reaches the list's Update. Unlike StartSpinner, it doesn't change the model
+// it's called on, so it's safe to use from models which only hold a copy of
+// the list, such as a parent sending it to a list it embeds. Other lists
+// ignore the message.
+func (m Model) StartSpinnerCmd() tea.Cmd {
+	return m.spinnerStateCmd(true)
+}
+
+// StopSpinnerCmd returns a command which stops the spinner once its message
+// reaches the list's Update. See StartSpinnerCmd.
+func (m Model) StopSpinnerCmd() tea.Cmd {
+	return m.spinnerStateCmd(false)
+}
+
+func (m Model) spinnerStateCmd(show bool) tea.Cmd {
+	id := m.spinner.ID()
+	return func() tea.Msg {
+		return spinnerStateMsg{id: id, show: show}
+	}
+}
+
+// ownsSpinnerMsg returns whether a message is for the list's own spinner.
+// Ticks of other spinners, such as those of other lists or of items, are
+// left for the delegate.
+func (m Model) ownsSpinnerMsg(msg tea.Msg) bool {
+	switch msg := msg.(type) {
+	case spinner.TickMsg:
+		return msg.ID == m.spinner.ID()
+	case spinnerStateMsg:
+		return msg.id == m.spinner.ID()
+	}
+	return false
+}
+
+// updateSpinner handles a message for the list's own spinner.
+func (m *Model) updateSpinner(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		if m.showSpinner {
+			return cmd
+		}
+	case spinnerStateMsg:
+		switch {
+		case !msg.show:
+			m.StopSpinner()
+		case !m.showSpinner:
+			return m.StartSpinner()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package list
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxStatusMessages is the number of transient status messages which can
+// wait to be shown. When more are queued, the oldest waiting one is dropped.
+const maxStatusMessages = 5
+
+// lastStatusID is used to tell the timers of status messages apart.
+var lastStatusID int64
+
+func nextStatusID() int {
+	return int(atomic.AddInt64(&lastStatusID, 1))
+}
+
+// StatusLevel is the severity of a status message, which determines how it's
+// styled.
+type StatusLevel int
+
+// Status message levels.
+const (
+	StatusInfo StatusLevel = iota
+	StatusWarn
+	StatusError
+)
+
+// String returns a human-readable name for the level.
+func (l StatusLevel) String() string {
+	return [...]string{
+		"info",
+		"warn",
+		"error",
+	}[l]
+}
+
+// StatusMessage is a message shown next to the title of the list.
+type StatusMessage struct {
+	// ID optionally identifies the message, so that it can be removed with
+	// ClearStatusMessage or recognized in a StatusExpiredMsg.
+	ID string
+
+	Text  string
+	Level StatusLevel
+
+	// Lifetime is how long the message is shown for. If it's zero, the
+	// list's StatusMessageLifetime is used.
+	Lifetime time.Duration
+
+	// Sticky messages don't expire, and are shown whenever there's no
+	// transient message to show until they're removed with
+	// ClearStatusMessage or ClearStatusMessages.
+	Sticky bool
+}
+
+// StatusExpiredMsg is sent when a transient status message has been shown
+// for its lifetime and is removed.
+type StatusExpiredMsg struct {
+	Message StatusMessage
+}
+
+type statusMessageTimeoutMsg struct {
+	id int
+}
+
+// queuedStatus is a status message waiting to be shown or being shown.
+type queuedStatus struct {
+	StatusMessage
+	id      int
+	started bool
+}
+
+// NewStatusMessage shows an info message, replacing any transient messages
+// shown or queued, for StatusMessageLifetime. Note that this also returns a
+// command.
+func (m *Model) NewStatusMessage(s string) tea.Cmd {
+	m.hideStatusMessage()
+	return m.QueueStatusMessage(StatusMessage{Text: s})
+}
+
+// QueueStatusMessage adds a status message to the queue. Transient messages
+// are shown one after the other, each for its lifetime, while a sticky
+// message is shown once there are no transient ones left. If several sticky
+// messages are queued, the latest is shown. Note that this also returns a
+// command.
+func (m *Model) QueueStatusMessage(msg StatusMessage) tea.Cmd {
+	m.statusQueue = append(m.statusQueue, queuedStatus{StatusMessage: msg, id: nextStatusID()})
+
+	var waiting []int
+	for i, s := range m.statusQueue {
+		if !s.Sticky && !s.started {
+			waiting = append(waiting, i)
+		}
+	}
+	if len(waiting) > maxStatusMessages {
+		m.statusQueue = slices.Delete(slices.Clone(m.statusQueue), waiting[0], waiting[0]+1)
+	}
+	return m.startStatusTimer()
+}
+
+// ClearStatusMessage removes the status messages with the given ID, whether
+// they're sticky or not. Note that this also returns a command.
+func (m *Model) ClearStatusMessage(id string) tea.Cmd {
+	m.statusQueue = slices.DeleteFunc(slices.Clone(m.statusQueue), func(s queuedStatus) bool {
+		return s.ID == id
+	})
+	return m.startStatusTimer()
+}
+
+// ClearStatusMessages removes all status messages, including sticky ones.
+func (m *Model) ClearStatusMessages() {
+	m.statusQueue = nil
+}
+
+// StatusMessages returns the queued status messages, in the order they were
+// queued, including the one being shown.
+func (m Model) StatusMessages() []StatusMessage {
+	msgs := make([]StatusMessage, len(m.statusQueue))
+	for i, s := range m.statusQueue {
+		msgs[i] = s.StatusMessage
+	}
+	return msgs
+}
+
+// CurrentStatusMessage returns the status message being shown, if any.
+func (m Model) CurrentStatusMessage() (StatusMessage, bool) {
+	if i := m.currentStatus(); i >= 0 {
+		return m.statusQueue[i].StatusMessage, true
+	}
+	return StatusMessage{}, false
+}
+
+// currentStatus returns the index of the status message being shown: the
+// first transient one, or else the last sticky one. It returns -1 if the
+// queue is empty.
+func (m Model) currentStatus() int {
+	for i, s := range m.statusQueue {
+		if !s.Sticky {
+			return i
+		}
+	}
+	return len(m.statusQueue) - 1
+}
+
+// startStatusTimer starts the timer of the status message being shown, if
+// it's transient and its timer isn't running already.
+func (m *Model) startStatusTimer() tea.Cmd {
+	i := m.currentStatus()
+	if i < 0 || m.statusQueue[i].Sticky || m.statusQueue[i].started {
+		return nil
+	}
+	m.statusQueue[i].started = true
+
+	id := m.statusQueue[i].id
+	lifetime := m.statusQueue[i].Lifetime
+	if lifetime <= 0 {
+		lifetime = m.StatusMessageLifetime
+	}
+	return tea.Tick(lifetime, func(time.Time) tea.Msg {
+		return statusMessageTimeoutMsg{id: id}
+	})
+}
+
+// expireStatusMessage removes the status message with the given ID once its
+// lifetime is up, reporting it with a StatusExpiredMsg, and moves on to the
+// next one.
+func (m *Model) expireStatusMessage(id int) tea.Cmd {
+	for i, s := range m.statusQueue {
+		if s.id != id {
+			continue
+		}
+		m.statusQueue = slices.Delete(slices.Clone(m.statusQueue), i, i+1)
+		expired := func() tea.Msg {
+			return StatusExpiredMsg{Message: s.StatusMessage}
+		}
+		return tea.Batch(expired, m.startStatusTimer())
+	}
+	return nil
+}
+
+// hideStatusMessage removes the transient status messages, leaving sticky
+// ones.
+func (m *Model) hideStatusMessage() {
+	m.statusQueue = slices.DeleteFunc(slices.Clone(m.statusQueue), func(s queuedStatus) bool {
+		return !s.Sticky
+	})
+}
+
+// statusMessageView renders the status message being shown, styled for its
+// level.
+func (m Model) statusMessageView() string {
+	msg, ok := m.CurrentStatusMessage()
+	if !ok {
+		return ""
+	}
+	switch msg.Level {
+	case StatusWarn:
+		return m.Styles.StatusMessageWarn.Render(msg.Text)
+	case StatusError:
+		return m.Styles.StatusMessageError.Render(msg.Text)
+	default:
+		return m.Styles.StatusMessageInfo.Render(msg.Text)
+	}
+}
@@ -2,11 +2,12 @@ package list
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/textutil"
 )
 
 const (
 	bullet   = "•"
-	ellipsis = "…"
+	ellipsis = textutil.Ellipsis
 )
 
 // Styles contains style definitions for this list component. By default, these
@@ -27,6 +28,11 @@ type Styles struct {
 	StatusBarActiveFilter lipgloss.Style
 	StatusBarFilterCount  lipgloss.Style
 
+	// Status messages shown next to the title, by level.
+	StatusMessageInfo  lipgloss.Style
+	StatusMessageWarn  lipgloss.Style
+	StatusMessageError lipgloss.Style
+
 	NoItems lipgloss.Style
 
 	PaginationStyle lipgloss.Style
@@ -37,6 +43,16 @@ type Styles struct {
 	InactivePaginationDot lipgloss.Style
 	ArabicPagination      lipgloss.Style
 	DividerDot            lipgloss.Style
+
+	// The divider between pinned items and the rest.
+	PinDivider lipgloss.Style
+
+	// The action menu of the selected item, its actions, the one at the
+	// menu's cursor and the keys choosing them.
+	ActionMenu     lipgloss.Style
+	Action         lipgloss.Style
+	SelectedAction lipgloss.Style
+	ActionKey      lipgloss.Style
 }
 
 // DefaultStyles returns a set of default style definitions for this list
@@ -74,6 +90,14 @@ func DefaultStyles() (s Styles) {
 
 	s.StatusBarFilterCount = lipgloss.NewStyle().Foreground(verySubduedColor)
 
+	s.StatusMessageInfo = lipgloss.NewStyle()
+
+	s.StatusMessageWarn = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#E8A317", Dark: "#B37A00"})
+
+	s.StatusMessageError = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#FF4672", Dark: "#ED567A"})
+
 	s.NoItems = lipgloss.NewStyle().
 		Foreground(lipgloss.AdaptiveColor{Light: "#909090", Dark: "#626262"})
 
@@ -95,5 +119,19 @@ func DefaultStyles() (s Styles) {
 		Foreground(verySubduedColor).
 		SetString(" " + bullet + " ")
 
+	s.PinDivider = lipgloss.NewStyle().
+		Foreground(verySubduedColor).
+		PaddingLeft(2) //nolint:mnd
+
+	s.ActionMenu = lipgloss.NewStyle().PaddingLeft(4) //nolint:mnd
+
+	s.Action = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"})
+
+	s.SelectedAction = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#EE6FF8", Dark: "#EE6FF8"})
+
+	s.ActionKey = lipgloss.NewStyle().Foreground(subduedColor)
+
 	return s
 }
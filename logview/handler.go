@@ -0,0 +1,110 @@
+package logview
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Handler returns a slog.Handler which sends the records it handles to the
+// log viewer as EntryMsg, through send, which is typically the Send method of
+// the tea.Program. Records below level are dropped; a nil level handles
+// everything from slog.LevelDebug up.
+//
+// Attributes in groups are flattened into dotted keys, as slog.TextHandler
+// does.
+func (m Model) Handler(send func(tea.Msg), level slog.Leveler) slog.Handler {
+	if level == nil {
+		level = slog.LevelDebug
+	}
+	return &handler{id: m.id, send: send, level: level}
+}
+
+type handler struct {
+	id     int
+	send   func(tea.Msg)
+	level  slog.Leveler
+	attrs  []slog.Attr
+	prefix string
+}
+
+func (h *handler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= h.level.Level()
+}
+
+func (h *handler) Handle(_ context.Context, r slog.Record) error {
+	e := Entry{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		Attrs:   slices.Clone(h.attrs),
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		e.Attrs = appendAttr(e.Attrs, h.prefix, a)
+		return true
+	})
+	h.send(EntryMsg{ID: h.id, Entry: e})
+	return nil
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = slices.Clip(h.attrs)
+	for _, a := range attrs {
+		h2.attrs = appendAttr(h2.attrs, h.prefix, a)
+	}
+	return &h2
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix += name + "."
+	return &h2
+}
+
+// entryFromRecord converts a slog record into an entry.
+func entryFromRecord(r slog.Record) Entry {
+	e := Entry{Time: r.Time, Level: r.Level, Message: r.Message}
+	r.Attrs(func(a slog.Attr) bool {
+		e.Attrs = appendAttr(e.Attrs, "", a)
+		return true
+	})
+	return e
+}
+
+// appendAttr appends an attribute to attrs with its key prefixed, flattening
+// groups and dropping empty attributes as slog handlers do.
+func appendAttr(attrs []slog.Attr, prefix string, a slog.Attr) []slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			attrs = appendAttr(attrs, prefix, ga)
+		}
+		return attrs
+	}
+	a.Key = prefix + a.Key
+	return append(attrs, a)
+}
+
+// formatAttr returns the key and formatted value of an attribute, quoting the
+// value if it has spaces or is empty.
+func formatAttr(a slog.Attr) (string, string) {
+	v := a.Value.Resolve().String()
+	if v == "" || strings.ContainsAny(v, " \t\n\"=") {
+		v = strconv.Quote(v)
+	}
+	return a.Key, v
+}
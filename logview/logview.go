@@ -0,0 +1,732 @@
+// Package logview provides a viewer for log output in Bubble Tea
+// applications. Entries can be appended as lines of text, which are parsed
+// for their time and level, or as log/slog records, and are colored by level.
+// The log can be filtered by level and by text as the user types, follows new
+// entries as they arrive, and lets the user jump from error to error.
+package logview
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
+	"github.com/mikeflynn/bubbles/textinput"
+	"github.com/mikeflynn/bubbles/textutil"
+	"github.com/mikeflynn/bubbles/viewport"
+)
+
+var lastID int64
+
+func nextID() int {
+	return int(atomic.AddInt64(&lastID, 1))
+}
+
+// DefaultMaxEntries is the number of entries kept by a new model.
+const DefaultMaxEntries = 10000
+
+// levelWidth is the width of the level column, which fits the names of the
+// standard slog levels.
+const levelWidth = 5
+
+// Entry is a single entry of the log.
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   []slog.Attr
+}
+
+// EntryMsg appends an entry to the log with the given ID. It's sent by the
+// handler returned by Model.Handler.
+type EntryMsg struct {
+	ID    int
+	Entry Entry
+}
+
+// KeyMap is the key bindings for scrolling the log, filtering it and jumping
+// between errors. It satisfies the help.KeyMap interface.
+type KeyMap struct {
+	LineUp      key.Binding
+	LineDown    key.Binding
+	PageUp      key.Binding
+	PageDown    key.Binding
+	GotoTop     key.Binding
+	GotoBottom  key.Binding
+	ScrollLeft  key.Binding
+	ScrollRight key.Binding
+
+	CycleLevel   key.Binding
+	Search       key.Binding
+	AcceptSearch key.Binding
+	CancelSearch key.Binding
+
+	NextError        key.Binding
+	PrevError        key.Binding
+	ToggleFollow     key.Binding
+	ToggleTimestamps key.Binding
+}
+
+// ShortHelp implements the KeyMap interface.
+func (km KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		km.LineUp, km.LineDown, km.CycleLevel, km.Search, km.AcceptSearch,
+		km.CancelSearch, km.NextError, km.ToggleFollow,
+	}
+}
+
+// FullHelp implements the KeyMap interface.
+func (km KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{km.LineUp, km.LineDown, km.PageUp, km.PageDown, km.GotoTop, km.GotoBottom},
+		{km.ScrollLeft, km.ScrollRight, km.ToggleFollow, km.ToggleTimestamps},
+		{km.CycleLevel, km.Search, km.AcceptSearch, km.CancelSearch},
+		{km.NextError, km.PrevError},
+	}
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		LineUp: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		LineDown: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup", "b"),
+			key.WithHelp("b/pgup", "page up"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown", "f"),
+			key.WithHelp("f/pgdn", "page down"),
+		),
+		GotoTop: key.NewBinding(
+			key.WithKeys("home", "g"),
+			key.WithHelp("g/home", "go to start"),
+		),
+		GotoBottom: key.NewBinding(
+			key.WithKeys("end", "G"),
+			key.WithHelp("G/end", "go to end"),
+		),
+		ScrollLeft: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "scroll left"),
+		),
+		ScrollRight: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "scroll right"),
+		),
+		CycleLevel: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "min level"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter"),
+		),
+		AcceptSearch: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "apply filter"),
+			key.WithDisabled(),
+		),
+		CancelSearch: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "clear filter"),
+			key.WithDisabled(),
+		),
+		NextError: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next error"),
+		),
+		PrevError: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "previous error"),
+		),
+		ToggleFollow: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "follow"),
+		),
+		ToggleTimestamps: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "timestamps"),
+		),
+	}
+}
+
+// Styles are the styles of log entries, by level, of their times and
+// attributes, and of the status line. DefaultStyles returns the defaults.
+type Styles struct {
+	Timestamp lipgloss.Style
+
+	// Debug, Info, Warn and Error style the level of entries at or above
+	// each level.
+	Debug lipgloss.Style
+	Info  lipgloss.Style
+	Warn  lipgloss.Style
+	Error lipgloss.Style
+
+	Message   lipgloss.Style
+	AttrKey   lipgloss.Style
+	AttrValue lipgloss.Style
+
+	// Selected styles the whole of the entry selected by jumping to an
+	// error.
+	Selected lipgloss.Style
+
+	Status lipgloss.Style
+	Empty  lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this
+// component.
+func DefaultStyles() Styles {
+	subdued := lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}
+	return Styles{
+		Timestamp: lipgloss.NewStyle().Foreground(subdued),
+		Debug:     lipgloss.NewStyle().Foreground(subdued),
+		Info:      lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#3C7DD9", Dark: "#6C9EE8"}),
+		Warn:      lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#E8A317", Dark: "#B37A00"}),
+		Error:     lipgloss.NewStyle().Bold(true).Foreground(lipgloss.AdaptiveColor{Light: "#FF4672", Dark: "#ED567A"}),
+		Message:   lipgloss.NewStyle(),
+		AttrKey:   lipgloss.NewStyle().Foreground(subdued),
+		AttrValue: lipgloss.NewStyle(),
+		Selected:  lipgloss.NewStyle().Background(lipgloss.AdaptiveColor{Light: "#DDDADA", Dark: "#3C3C3C"}),
+		Status:    lipgloss.NewStyle().Foreground(subdued),
+		Empty:     lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#909090", Dark: "#626262"}),
+	}
+}
+
+// Model is the state of a log viewer.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+
+	// TimeFormat is the layout used for entry times. If empty, times are
+	// not shown.
+	TimeFormat string
+
+	// MaxEntries is the number of entries kept. Once there are more, the
+	// oldest are dropped. Zero keeps every entry.
+	MaxEntries int
+
+	// EmptyText is shown when there are no entries, and NoMatchText when
+	// there are entries but none match the filters.
+	EmptyText   string
+	NoMatchText string
+
+	id        int
+	entries   []Entry
+	visible   []int    // indexes into entries matching the filters
+	lines     []string // rendered visible entries
+	minLevel  slog.Level
+	showTimes bool
+	follow    bool
+	selected  int // index into entries, -1 if none is selected
+
+	searching bool
+	search    textinput.Model
+
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+// New returns a model with default settings.
+func New() Model {
+	vp := viewport.New(0, 0)
+	vp.KeyMap = viewport.KeyMap{}
+
+	search := textinput.New()
+	search.Prompt = "/"
+
+	m := Model{
+		KeyMap:      DefaultKeyMap(),
+		Styles:      DefaultStyles(),
+		TimeFormat:  "15:04:05",
+		MaxEntries:  DefaultMaxEntries,
+		EmptyText:   "No log entries.",
+		NoMatchText: "No entries match the filter.",
+		id:          nextID(),
+		minLevel:    slog.LevelDebug,
+		showTimes:   true,
+		follow:      true,
+		selected:    -1,
+		search:      search,
+		viewport:    vp,
+	}
+	m.updateKeybindings()
+	return m
+}
+
+// ID returns the component's unique ID, which is carried by EntryMsg.
+func (m Model) ID() int {
+	return m.id
+}
+
+// SetSize sets the dimensions of the component. The last line is taken by
+// the status line.
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.viewport.Width = width
+	m.viewport.Height = max(0, height-1)
+	m.search.Width = max(0, width-lipgloss.Width(m.search.Prompt)-1)
+	m.setContent()
+}
+
+// Width returns the width of the component.
+func (m Model) Width() int {
+	return m.width
+}
+
+// Height returns the height of the component.
+func (m Model) Height() int {
+	return m.height
+}
+
+// Append adds an entry to the end of the log.
+func (m *Model) Append(e Entry) {
+	m.entries = append(m.entries, e)
+	if i := len(m.entries) - 1; m.matches(e) {
+		m.visible = append(m.visible, i)
+		m.lines = append(m.lines, m.renderEntry(i))
+	}
+	if m.MaxEntries > 0 && len(m.entries) > m.MaxEntries {
+		m.drop(len(m.entries) - m.MaxEntries)
+	}
+	m.setContent()
+}
+
+// AppendLine parses a line of log output with ParseLine and adds it to the
+// end of the log. Text with several lines is added as one entry per line.
+func (m *Model) AppendLine(line string) {
+	for _, l := range strings.Split(strings.TrimSuffix(line, "\n"), "\n") {
+		m.Append(ParseLine(l))
+	}
+}
+
+// AppendRecord adds a slog record to the end of the log. See also Handler,
+// which sends records to the log from a slog.Logger.
+func (m *Model) AppendRecord(r slog.Record) {
+	m.Append(entryFromRecord(r))
+}
+
+// Entries returns the entries in the log, oldest first.
+func (m Model) Entries() []Entry {
+	return append([]Entry(nil), m.entries...)
+}
+
+// VisibleEntries returns the entries matching the filters, oldest first.
+func (m Model) VisibleEntries() []Entry {
+	entries := make([]Entry, len(m.visible))
+	for i, e := range m.visible {
+		entries[i] = m.entries[e]
+	}
+	return entries
+}
+
+// Clear removes every entry from the log.
+func (m *Model) Clear() {
+	m.entries = nil
+	m.selected = -1
+	m.refresh()
+}
+
+// MinLevel returns the lowest level of the entries shown.
+func (m Model) MinLevel() slog.Level {
+	return m.minLevel
+}
+
+// SetMinLevel hides entries below the given level.
+func (m *Model) SetMinLevel(l slog.Level) {
+	m.minLevel = l
+	m.refresh()
+}
+
+// Query returns the text entries are filtered by.
+func (m Model) Query() string {
+	return m.search.Value()
+}
+
+// SetQuery shows only the entries whose message or attributes contain the
+// given text, ignoring case. An empty query removes the filter.
+func (m *Model) SetQuery(q string) {
+	m.search.SetValue(q)
+	m.refresh()
+	m.updateKeybindings()
+}
+
+// ShowTimestamps returns whether entry times are shown.
+func (m Model) ShowTimestamps() bool {
+	return m.showTimes
+}
+
+// SetShowTimestamps sets whether entry times are shown.
+func (m *Model) SetShowTimestamps(v bool) {
+	m.showTimes = v
+	m.refresh()
+}
+
+// Following returns whether the log follows new entries, staying scrolled to
+// the bottom as they arrive.
+func (m Model) Following() bool {
+	return m.follow
+}
+
+// SetFollow sets whether the log follows new entries. Following scrolls to
+// the bottom; scrolling up stops following.
+func (m *Model) SetFollow(v bool) {
+	m.follow = v
+	if v {
+		m.viewport.GotoBottom()
+	}
+}
+
+// Selected returns the entry selected by jumping to an error, and false if
+// there's none.
+func (m Model) Selected() (Entry, bool) {
+	if m.selected < 0 {
+		return Entry{}, false
+	}
+	return m.entries[m.selected], true
+}
+
+// NextError selects the next visible entry at slog.LevelError or above,
+// after the selected entry or from the top of the view, and scrolls it into
+// view. It returns false if there's none.
+func (m *Model) NextError() bool {
+	start := m.viewport.YOffset
+	if m.selected >= 0 {
+		start = sort.SearchInts(m.visible, m.selected+1)
+	}
+	for p := start; p < len(m.visible); p++ {
+		if m.entries[m.visible[p]].Level >= slog.LevelError {
+			m.selectLine(p)
+			return true
+		}
+	}
+	return false
+}
+
+// PrevError selects the previous visible entry at slog.LevelError or above,
+// before the selected entry or from the bottom of the view, and scrolls it
+// into view. It returns false if there's none.
+func (m *Model) PrevError() bool {
+	start := min(m.viewport.YOffset+m.viewport.Height, len(m.visible)) - 1
+	if m.selected >= 0 {
+		start = sort.SearchInts(m.visible, m.selected) - 1
+	}
+	for p := start; p >= 0; p-- {
+		if m.entries[m.visible[p]].Level >= slog.LevelError {
+			m.selectLine(p)
+			return true
+		}
+	}
+	return false
+}
+
+// Init exists to satisfy the tea.Model interface.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case EntryMsg:
+		if msg.ID == m.id {
+			m.Append(msg.Entry)
+		}
+	case tea.KeyMsg:
+		if m.searching {
+			m, cmd = m.handleSearching(msg)
+			break
+		}
+		if key.Matches(msg, m.KeyMap.Search) {
+			m.searching = true
+			cmd = m.search.Focus()
+			break
+		}
+		m.handleKey(msg)
+	case tea.MouseMsg:
+		m.viewport, cmd = m.viewport.Update(msg)
+		m.follow = m.follow && m.viewport.AtBottom()
+	}
+
+	m.updateKeybindings()
+	return m, cmd
+}
+
+func (m *Model) handleKey(msg tea.KeyMsg) {
+	switch {
+	case key.Matches(msg, m.KeyMap.LineUp):
+		m.viewport.ScrollUp(1)
+	case key.Matches(msg, m.KeyMap.LineDown):
+		m.viewport.ScrollDown(1)
+	case key.Matches(msg, m.KeyMap.PageUp):
+		m.viewport.PageUp()
+	case key.Matches(msg, m.KeyMap.PageDown):
+		m.viewport.PageDown()
+	case key.Matches(msg, m.KeyMap.GotoTop):
+		m.viewport.GotoTop()
+	case key.Matches(msg, m.KeyMap.GotoBottom):
+		m.SetFollow(true)
+	case key.Matches(msg, m.KeyMap.ScrollLeft):
+		m.viewport.ScrollLeft(1)
+	case key.Matches(msg, m.KeyMap.ScrollRight):
+		m.viewport.ScrollRight(1)
+	case key.Matches(msg, m.KeyMap.CycleLevel):
+		m.SetMinLevel(nextLevel(m.minLevel))
+	case key.Matches(msg, m.KeyMap.CancelSearch):
+		m.SetQuery("")
+	case key.Matches(msg, m.KeyMap.NextError):
+		m.NextError()
+	case key.Matches(msg, m.KeyMap.PrevError):
+		m.PrevError()
+	case key.Matches(msg, m.KeyMap.ToggleFollow):
+		m.SetFollow(!m.follow)
+	case key.Matches(msg, m.KeyMap.ToggleTimestamps):
+		m.SetShowTimestamps(!m.showTimes)
+	}
+	m.follow = m.follow && m.viewport.AtBottom()
+}
+
+func (m Model) handleSearching(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.KeyMap.AcceptSearch):
+		m.searching = false
+		m.search.Blur()
+		return m, nil
+	case key.Matches(msg, m.KeyMap.CancelSearch):
+		m.searching = false
+		m.search.Blur()
+		m.SetQuery("")
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	prev := m.search.Value()
+	m.search, cmd = m.search.Update(msg)
+	if m.search.Value() != prev {
+		m.refresh()
+	}
+	return m, cmd
+}
+
+// View renders the component.
+func (m Model) View() string {
+	var body string
+	switch {
+	case len(m.visible) > 0:
+		body = m.viewport.View()
+	default:
+		text := m.EmptyText
+		if len(m.entries) > 0 {
+			text = m.NoMatchText
+		}
+		body = lipgloss.NewStyle().
+			Width(m.width).MaxWidth(m.width).
+			Height(m.viewport.Height).MaxHeight(m.viewport.Height).
+			Render(m.Styles.Empty.Render(text))
+	}
+	return body + "\n" + m.statusView()
+}
+
+// RenderString renders the log deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	m.SetSize(width, height)
+	return render.View(m.View, width, height)
+}
+
+// matches returns whether an entry passes the level and text filters.
+func (m Model) matches(e Entry) bool {
+	if e.Level < m.minLevel {
+		return false
+	}
+	q := strings.ToLower(m.search.Value())
+	if q == "" {
+		return true
+	}
+	if strings.Contains(strings.ToLower(e.Message), q) {
+		return true
+	}
+	for _, a := range e.Attrs {
+		k, v := formatAttr(a)
+		if strings.Contains(strings.ToLower(k+"="+v), q) {
+			return true
+		}
+	}
+	return false
+}
+
+// refresh renders the entries matching the filters and updates the content
+// of the viewport.
+func (m *Model) refresh() {
+	m.visible, m.lines = nil, nil
+	for i, e := range m.entries {
+		if m.matches(e) {
+			m.visible = append(m.visible, i)
+			m.lines = append(m.lines, m.renderEntry(i))
+		}
+	}
+	m.setContent()
+}
+
+// setContent updates the content of the viewport, following the bottom of
+// the log if following is on.
+func (m *Model) setContent() {
+	m.viewport.SetContent(strings.Join(m.lines, "\n"))
+	if m.follow {
+		m.viewport.GotoBottom()
+	}
+}
+
+// drop removes the n oldest entries.
+func (m *Model) drop(n int) {
+	m.entries = append([]Entry(nil), m.entries[n:]...)
+	first := sort.SearchInts(m.visible, n)
+	visible := make([]int, 0, len(m.visible)-first)
+	for _, i := range m.visible[first:] {
+		visible = append(visible, i-n)
+	}
+	m.visible = visible
+	m.lines = m.lines[first:]
+	if m.selected -= n; m.selected < 0 {
+		m.selected = -1
+	}
+}
+
+// selectLine selects the entry on the given line of the content and scrolls
+// it into view.
+func (m *Model) selectLine(line int) {
+	if prev := sort.SearchInts(m.visible, m.selected); m.selected >= 0 &&
+		prev < len(m.visible) && m.visible[prev] == m.selected {
+		m.selected = -1
+		m.lines[prev] = m.renderEntry(m.visible[prev])
+	}
+	m.selected = m.visible[line]
+	m.lines[line] = m.renderEntry(m.selected)
+
+	m.follow = false
+	m.setContent()
+	switch {
+	case line < m.viewport.YOffset:
+		m.viewport.SetYOffset(line)
+	case line >= m.viewport.YOffset+m.viewport.Height:
+		m.viewport.SetYOffset(line - m.viewport.Height + 1)
+	}
+}
+
+// renderEntry renders the entry at the given index on a single line.
+func (m Model) renderEntry(i int) string {
+	e := m.entries[i]
+	styled := i != m.selected
+	style := func(s lipgloss.Style, text string) string {
+		if !styled {
+			return text
+		}
+		return s.Render(text)
+	}
+
+	var b strings.Builder
+	if m.showTimes && m.TimeFormat != "" {
+		stamp := strings.Repeat(" ", textutil.Width(time.Time{}.Format(m.TimeFormat)))
+		if !e.Time.IsZero() {
+			stamp = e.Time.Format(m.TimeFormat)
+		}
+		b.WriteString(style(m.Styles.Timestamp, stamp) + " ")
+	}
+	b.WriteString(style(m.levelStyle(e.Level), textutil.PadRight(e.Level.String(), levelWidth)))
+	if e.Message != "" {
+		b.WriteString(" " + style(m.Styles.Message, strings.ReplaceAll(e.Message, "\n", " ")))
+	}
+	for _, a := range e.Attrs {
+		k, v := formatAttr(a)
+		b.WriteString(" " + style(m.Styles.AttrKey, k+"=") + style(m.Styles.AttrValue, strings.ReplaceAll(v, "\n", " ")))
+	}
+
+	if !styled {
+		return m.Styles.Selected.Render(b.String())
+	}
+	return b.String()
+}
+
+// levelStyle returns the style of the given level.
+func (m Model) levelStyle(l slog.Level) lipgloss.Style {
+	switch {
+	case l >= slog.LevelError:
+		return m.Styles.Error
+	case l >= slog.LevelWarn:
+		return m.Styles.Warn
+	case l >= slog.LevelInfo:
+		return m.Styles.Info
+	default:
+		return m.Styles.Debug
+	}
+}
+
+// nextLevel returns the standard level after the given minimum level,
+// wrapping around to slog.LevelDebug after slog.LevelError.
+func nextLevel(l slog.Level) slog.Level {
+	switch {
+	case l < slog.LevelInfo:
+		return slog.LevelInfo
+	case l < slog.LevelWarn:
+		return slog.LevelWarn
+	case l < slog.LevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelDebug
+	}
+}
+
+func (m Model) statusView() string {
+	if m.searching {
+		return m.search.View()
+	}
+
+	status := fmt.Sprintf("%d entries", len(m.entries))
+	if len(m.visible) != len(m.entries) {
+		status = fmt.Sprintf("%d of %d entries", len(m.visible), len(m.entries))
+	}
+	if m.minLevel > slog.LevelDebug {
+		status += " • level ≥ " + m.minLevel.String()
+	}
+	if q := m.search.Value(); q != "" {
+		status += fmt.Sprintf(" • matching %q", q)
+	}
+	if m.follow {
+		status += " • following"
+	}
+	if m.width > 0 {
+		status = textutil.Truncate(status, m.width, textutil.Ellipsis)
+	}
+	return m.Styles.Status.Render(status)
+}
+
+func (m *Model) updateKeybindings() {
+	for _, b := range []*key.Binding{
+		&m.KeyMap.LineUp, &m.KeyMap.LineDown, &m.KeyMap.PageUp,
+		&m.KeyMap.PageDown, &m.KeyMap.GotoTop, &m.KeyMap.GotoBottom,
+		&m.KeyMap.ScrollLeft, &m.KeyMap.ScrollRight, &m.KeyMap.CycleLevel,
+		&m.KeyMap.Search, &m.KeyMap.NextError, &m.KeyMap.PrevError,
+		&m.KeyMap.ToggleFollow, &m.KeyMap.ToggleTimestamps,
+	} {
+		b.SetEnabled(!m.searching)
+	}
+	m.KeyMap.AcceptSearch.SetEnabled(m.searching)
+	m.KeyMap.CancelSearch.SetEnabled(m.searching || m.search.Value() != "")
+}
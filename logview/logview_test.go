@@ -0,0 +1,219 @@
+package logview
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+var start = time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+
+func newLog() Model {
+	m := New()
+	m.AppendLine(`time=2024-05-01T09:30:00Z level=INFO msg="server started" addr=:8080`)
+	m.AppendLine(`{"time":"2024-05-01T09:30:01Z","level":"DEBUG","msg":"loading config","path":"/etc/app.toml"}`)
+	m.AppendLine(`2024/05/01 09:30:02 WARN cache is cold`)
+	m.AppendLine(`time=2024-05-01T09:30:03Z level=ERROR msg="query failed" err="db: timeout"`)
+	m.AppendLine(`2024/05/01 09:30:04 [info] request handled`)
+	m.AppendLine(`time=2024-05-01T09:30:05Z level=ERROR msg="query failed" err="db: refused"`)
+	return m
+}
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line  string
+		want  Entry
+		attrs string
+	}{
+		{
+			line:  `time=2024-05-01T09:30:00Z level=WARN msg="disk low" free="10 GB"`,
+			want:  Entry{Time: start, Level: slog.LevelWarn, Message: "disk low"},
+			attrs: `free="10 GB"`,
+		},
+		{
+			line:  `{"time":"2024-05-01T09:30:00Z","level":"ERROR","msg":"boom","code":500}`,
+			want:  Entry{Time: start, Level: slog.LevelError, Message: "boom"},
+			attrs: "code=500",
+		},
+		{
+			line: "2024/05/01 09:30:00 ERROR: boom",
+			want: Entry{Time: start, Level: slog.LevelError, Message: "boom"},
+		},
+		{
+			line: "[warn] deprecated flag",
+			want: Entry{Level: slog.LevelWarn, Message: "deprecated flag"},
+		},
+		{
+			line: "DEBUG-2 verbose",
+			want: Entry{Level: slog.LevelDebug - 2, Message: "verbose"},
+		},
+		{
+			line: "plain output with level=unknown",
+			want: Entry{Level: slog.LevelInfo, Message: "plain output with level=unknown"},
+		},
+	}
+	for _, tt := range tests {
+		got := ParseLine(tt.line)
+		if !got.Time.Equal(tt.want.Time) || got.Level != tt.want.Level || got.Message != tt.want.Message {
+			t.Errorf("ParseLine(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+		var attrs string
+		for i, a := range got.Attrs {
+			if i > 0 {
+				attrs += " "
+			}
+			k, v := formatAttr(a)
+			attrs += k + "=" + v
+		}
+		if attrs != tt.attrs {
+			t.Errorf("ParseLine(%q) attrs = %q, want %q", tt.line, attrs, tt.attrs)
+		}
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(newLog(), 60, 8)))
+	})
+	t.Run("filtered", func(t *testing.T) {
+		m := newLog()
+		m.SetMinLevel(slog.LevelWarn)
+		m.SetQuery("failed")
+		golden.RequireEqual(t, []byte(RenderString(m, 60, 8)))
+	})
+	t.Run("no timestamps", func(t *testing.T) {
+		m := newLog()
+		m.SetShowTimestamps(false)
+		golden.RequireEqual(t, []byte(RenderString(m, 60, 8)))
+	})
+	t.Run("empty", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(New(), 40, 3)))
+	})
+}
+
+func TestFiltering(t *testing.T) {
+	m := newLog()
+	m.SetSize(60, 8)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	if m.MinLevel() != slog.LevelInfo || len(m.VisibleEntries()) != 5 {
+		t.Fatalf("expected debug entries to be hidden, got level %v and %d entries", m.MinLevel(), len(m.VisibleEntries()))
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	for _, r := range "REFUSED" {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if got := m.VisibleEntries(); len(got) != 1 || got[0].Message != "query failed" {
+		t.Fatalf("expected the filter to apply as it's typed, got %+v", got)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.Query() != "REFUSED" {
+		t.Fatalf("expected the filter to be kept, got %q", m.Query())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.Query() != "" || len(m.VisibleEntries()) != 5 {
+		t.Fatalf("expected the filter to be cleared, got %q", m.Query())
+	}
+}
+
+func TestFollow(t *testing.T) {
+	m := newLog()
+	m.SetSize(60, 4)
+	if !m.Following() || !m.viewport.AtBottom() {
+		t.Fatal("expected the log to follow new entries")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if m.Following() {
+		t.Fatal("expected scrolling up to stop following")
+	}
+	offset := m.viewport.YOffset
+	m.AppendLine("INFO more")
+	if m.viewport.YOffset != offset {
+		t.Fatal("expected the view to stay put while not following")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("F")})
+	m.AppendLine("INFO and more")
+	if !m.Following() || !m.viewport.AtBottom() {
+		t.Fatal("expected the log to follow again")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	m := newLog()
+	m.SetSize(60, 8)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if e, ok := m.Selected(); !ok || e.Attrs[0].Value.String() != "db: timeout" {
+		t.Fatalf("expected the first error to be selected, got %+v", e)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if e, _ := m.Selected(); e.Attrs[0].Value.String() != "db: refused" {
+		t.Fatalf("expected the second error to be selected, got %+v", e)
+	}
+	if m.NextError() {
+		t.Fatal("expected no error after the last one")
+	}
+	if !m.PrevError() {
+		t.Fatal("expected to go back to the first error")
+	}
+	if e, _ := m.Selected(); e.Attrs[0].Value.String() != "db: timeout" {
+		t.Fatalf("expected the first error to be selected, got %+v", e)
+	}
+	if m.Following() {
+		t.Fatal("expected jumping to an error to stop following")
+	}
+}
+
+func TestMaxEntries(t *testing.T) {
+	m := newLog()
+	m.MaxEntries = 3
+	m.AppendLine("ERROR last")
+	got := m.Entries()
+	if len(got) != 3 || got[0].Message != "request handled" || got[2].Message != "last" {
+		t.Fatalf("expected the oldest entries to be dropped, got %+v", got)
+	}
+	if len(m.VisibleEntries()) != 3 {
+		t.Fatalf("expected 3 visible entries, got %d", len(m.VisibleEntries()))
+	}
+}
+
+func TestHandler(t *testing.T) {
+	m := New()
+	var msgs []tea.Msg
+	logger := slog.New(m.Handler(func(msg tea.Msg) { msgs = append(msgs, msg) }, slog.LevelInfo))
+
+	logger.Debug("dropped")
+	logger.With("svc", "api").WithGroup("req").Info("handled", "id", 7, slog.Group("user", "name", "ann"))
+	if !logger.Handler().Enabled(context.Background(), slog.LevelWarn) {
+		t.Fatal("expected warnings to be enabled")
+	}
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(msgs))
+	}
+	for _, msg := range msgs {
+		m, _ = m.Update(msg)
+	}
+	m, _ = m.Update(EntryMsg{ID: m.ID() + 1, Entry: Entry{Message: "elsewhere"}})
+
+	got := m.Entries()
+	if len(got) != 1 || got[0].Message != "handled" {
+		t.Fatalf("unexpected entries: %+v", got)
+	}
+	var keys []string
+	for _, a := range got[0].Attrs {
+		keys = append(keys, a.Key)
+	}
+	if want := []string{"svc", "req.id", "req.user.name"}; len(keys) != len(want) ||
+		keys[0] != want[0] || keys[1] != want[1] || keys[2] != want[2] {
+		t.Fatalf("expected attrs %v, got %v", want, keys)
+	}
+}
@@ -0,0 +1,236 @@
+package logview
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// timeLayouts are the layouts of the timestamps recognized at the start of
+// plain lines, with the number of space-separated fields each one spans.
+var timeLayouts = []struct {
+	layout string
+	fields int
+}{
+	{time.RFC3339Nano, 1},
+	{"2006/01/02 15:04:05.000000", 2},
+	{"2006/01/02 15:04:05", 2},
+	{"2006-01-02 15:04:05.000", 2},
+	{"2006-01-02 15:04:05", 2},
+	{time.DateTime, 2},
+}
+
+// levelNames maps the level names used by common loggers, in lower case, to
+// the closest slog level.
+var levelNames = map[string]slog.Level{
+	"trace":    slog.LevelDebug,
+	"debug":    slog.LevelDebug,
+	"dbg":      slog.LevelDebug,
+	"info":     slog.LevelInfo,
+	"inf":      slog.LevelInfo,
+	"notice":   slog.LevelInfo,
+	"warn":     slog.LevelWarn,
+	"warning":  slog.LevelWarn,
+	"wrn":      slog.LevelWarn,
+	"error":    slog.LevelError,
+	"err":      slog.LevelError,
+	"eror":     slog.LevelError,
+	"fatal":    slog.LevelError,
+	"panic":    slog.LevelError,
+	"crit":     slog.LevelError,
+	"critical": slog.LevelError,
+}
+
+// ParseLevel parses a level name such as "INFO", "warning", "ERR" or
+// "DEBUG-2", as written by log/slog and other common loggers. It returns
+// false if the name isn't a level.
+func ParseLevel(s string) (slog.Level, bool) {
+	if l, ok := levelNames[strings.ToLower(s)]; ok {
+		return l, true
+	}
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(s)); err != nil {
+		return 0, false
+	}
+	return l, true
+}
+
+// ParseLine parses a line of log output into an entry. It understands the
+// JSON and text formats of log/slog, other logfmt output, and plain lines
+// starting with an optional timestamp and a level such as "ERROR:" or
+// "[warn]". Lines without a level are read as info entries with the whole
+// line as their message.
+func ParseLine(line string) Entry {
+	line = strings.TrimRight(line, "\r\n")
+	if e, ok := parseJSON(line); ok {
+		return e
+	}
+	if e, ok := parseLogfmt(line); ok {
+		return e
+	}
+	return parsePlain(line)
+}
+
+// parseJSON parses a line written by slog.JSONHandler, or another logger
+// writing JSON objects with "time", "level" and "msg" fields.
+func parseJSON(line string) (Entry, bool) {
+	if !strings.HasPrefix(line, "{") {
+		return Entry{}, false
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return Entry{}, false
+	}
+
+	e := Entry{Level: slog.LevelInfo}
+	keys := make([]string, 0, len(fields))
+	for k, v := range fields {
+		s, isString := v.(string)
+		switch {
+		case k == slog.TimeKey && isString:
+			if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+				e.Time = t
+				continue
+			}
+		case (k == slog.LevelKey || k == "lvl") && isString:
+			if l, ok := ParseLevel(s); ok {
+				e.Level = l
+				continue
+			}
+		case (k == slog.MessageKey || k == "message") && isString:
+			e.Message = s
+			continue
+		}
+		keys = append(keys, k)
+	}
+
+	// Maps lose the order of the fields, so attributes are sorted by key to
+	// keep the output stable.
+	sort.Strings(keys)
+	for _, k := range keys {
+		e.Attrs = append(e.Attrs, slog.Any(k, fields[k]))
+	}
+	return e, true
+}
+
+// parseLogfmt parses a line of key=value pairs, such as those written by
+// slog.TextHandler. A line is only taken for logfmt if every field is a pair
+// and there's a level or message among them.
+func parseLogfmt(line string) (Entry, bool) {
+	pairs, ok := splitLogfmt(line)
+	if !ok {
+		return Entry{}, false
+	}
+
+	e := Entry{Level: slog.LevelInfo}
+	var structured bool
+	for _, p := range pairs {
+		switch p[0] {
+		case slog.TimeKey, "ts":
+			if t, err := time.Parse(time.RFC3339Nano, p[1]); err == nil {
+				e.Time = t
+				continue
+			}
+		case slog.LevelKey, "lvl":
+			if l, ok := ParseLevel(p[1]); ok {
+				e.Level = l
+				structured = true
+				continue
+			}
+		case slog.MessageKey:
+			e.Message = p[1]
+			structured = true
+			continue
+		}
+		e.Attrs = append(e.Attrs, slog.String(p[0], p[1]))
+	}
+	return e, structured
+}
+
+// splitLogfmt splits a line into key=value pairs, unquoting quoted values.
+// It returns false if the line has a field which isn't a pair.
+func splitLogfmt(line string) ([][2]string, bool) {
+	var pairs [][2]string
+	s := strings.TrimSpace(line)
+	for s != "" {
+		eq := strings.IndexAny(s, "= ")
+		if eq <= 0 || s[eq] != '=' {
+			return nil, false
+		}
+		k := s[:eq]
+		s = s[eq+1:]
+
+		var v string
+		if strings.HasPrefix(s, `"`) {
+			end := quotedEnd(s)
+			if end < 0 {
+				return nil, false
+			}
+			unquoted, err := strconv.Unquote(s[:end])
+			if err != nil {
+				return nil, false
+			}
+			v, s = unquoted, s[end:]
+		} else {
+			end := strings.IndexByte(s, ' ')
+			if end < 0 {
+				end = len(s)
+			}
+			v, s = s[:end], s[end:]
+		}
+		pairs = append(pairs, [2]string{k, v})
+		s = strings.TrimLeft(s, " ")
+	}
+	return pairs, len(pairs) > 0
+}
+
+// quotedEnd returns the index just past the closing quote of the quoted
+// string s starts with, or -1 if it isn't closed.
+func quotedEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// parsePlain parses a line of unstructured output, taking a leading
+// timestamp and level off the message if there are any.
+func parsePlain(line string) Entry {
+	e := Entry{Level: slog.LevelInfo, Message: line}
+	rest := line
+	for _, tl := range timeLayouts {
+		fields := strings.SplitN(rest, " ", tl.fields+1)
+		if len(fields) < tl.fields {
+			continue
+		}
+		if t, err := time.Parse(tl.layout, strings.Join(fields[:tl.fields], " ")); err == nil {
+			e.Time = t
+			rest = ""
+			if len(fields) > tl.fields {
+				rest = fields[tl.fields]
+			}
+			break
+		}
+	}
+
+	rest = strings.TrimLeft(rest, " ")
+	word, after, _ := strings.Cut(rest, " ")
+	name := strings.TrimFunc(word, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '-'
+	})
+	if l, ok := ParseLevel(name); ok && name != "" {
+		e.Level = l
+		rest = strings.TrimLeft(after, " ")
+	}
+	e.Message = rest
+	return e
+}
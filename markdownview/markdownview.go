@@ -0,0 +1,455 @@
+// Package markdownview provides a scrollable view of Markdown text for Bubble
+// Tea applications, such as help pages and release notes. It renders the
+// commonly used parts of Markdown, word wrapped to the width of the view:
+// headings, paragraphs, emphasis, inline code, links, lists, block quotes,
+// fenced code blocks and horizontal rules. Anything else is shown as text.
+package markdownview
+
+import (
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mikeflynn/bubbles/render"
+	"github.com/mikeflynn/bubbles/viewport"
+)
+
+// RenderFunc renders Markdown at the given width. It can be used to plug in
+// another renderer, such as one with syntax highlighting.
+type RenderFunc func(markdown string, width int) string
+
+// Styles are the styles of the Markdown elements, such as headings, code and
+// links, as returned by DefaultStyles by default.
+type Styles struct {
+	// Heading1 is used for top-level headings, and Heading for the others.
+	Heading1 lipgloss.Style
+	Heading  lipgloss.Style
+
+	Strong   lipgloss.Style
+	Emphasis lipgloss.Style
+	Code     lipgloss.Style
+
+	// CodeBlock is used for each line of a fenced or indented code block.
+	CodeBlock lipgloss.Style
+
+	// Link is used for the text of links, and LinkURL for their address,
+	// which follows the text unless it's the same.
+	Link    lipgloss.Style
+	LinkURL lipgloss.Style
+
+	// Bullet is used for list markers, QuoteMarker for the bar in front of
+	// block quotes and Quote for their text.
+	Bullet      lipgloss.Style
+	QuoteMarker lipgloss.Style
+	Quote       lipgloss.Style
+
+	// Rule is used for horizontal rules.
+	Rule lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this view.
+func DefaultStyles() Styles {
+	accent := lipgloss.AdaptiveColor{Light: "#F793FF", Dark: "#AD58B4"}
+	subdued := lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}
+	code := lipgloss.AdaptiveColor{Light: "#FF4672", Dark: "#ED567A"}
+
+	return Styles{
+		Heading1:    lipgloss.NewStyle().Foreground(accent).Bold(true).Underline(true),
+		Heading:     lipgloss.NewStyle().Foreground(accent).Bold(true),
+		Strong:      lipgloss.NewStyle().Bold(true),
+		Emphasis:    lipgloss.NewStyle().Italic(true),
+		Code:        lipgloss.NewStyle().Foreground(code),
+		CodeBlock:   lipgloss.NewStyle().Foreground(subdued),
+		Link:        lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#00A66D", Dark: "#04B575"}).Underline(true),
+		LinkURL:     lipgloss.NewStyle().Foreground(subdued),
+		Bullet:      lipgloss.NewStyle().Foreground(accent),
+		QuoteMarker: lipgloss.NewStyle().Foreground(subdued),
+		Quote:       lipgloss.NewStyle().Italic(true),
+		Rule:        lipgloss.NewStyle().Foreground(subdued),
+	}
+}
+
+// Model is the Bubble Tea model for a Markdown view. It scrolls like a
+// viewport.
+type Model struct {
+	// KeyMap is the keybindings for scrolling.
+	KeyMap viewport.KeyMap
+
+	// Styles are applied when the content is rendered, so changes take
+	// effect on the next call to SetContent or SetSize.
+	Styles Styles
+
+	// Renderer, if set, renders the content in place of the built-in
+	// renderer.
+	Renderer RenderFunc
+
+	markdown string
+	rendered string
+	viewport viewport.Model
+}
+
+// New returns a Markdown view of the given dimensions.
+func New(width, height int) Model {
+	return Model{
+		KeyMap:   viewport.DefaultKeyMap(),
+		Styles:   DefaultStyles(),
+		viewport: viewport.New(width, height),
+	}
+}
+
+// SetContent sets the Markdown shown, keeping the scroll position where it
+// can.
+func (m *Model) SetContent(markdown string) {
+	m.markdown = markdown
+	m.refresh()
+}
+
+// Content returns the Markdown shown.
+func (m Model) Content() string {
+	return m.markdown
+}
+
+// Rendered returns the content as it's shown, at the width of the view.
+func (m Model) Rendered() string {
+	return m.rendered
+}
+
+// SetSize sets the dimensions of the view, rewrapping the content to its
+// width.
+func (m *Model) SetSize(width, height int) {
+	resized := width != m.viewport.Width
+	m.viewport.Width, m.viewport.Height = width, height
+	if resized {
+		m.refresh()
+	}
+}
+
+// Width returns the width of the view.
+func (m Model) Width() int {
+	return m.viewport.Width
+}
+
+// Height returns the height of the view.
+func (m Model) Height() int {
+	return m.viewport.Height
+}
+
+// YOffset returns the index of the first line shown.
+func (m Model) YOffset() int {
+	return m.viewport.YOffset
+}
+
+// SetYOffset scrolls so that the line at the given index is the first shown.
+func (m *Model) SetYOffset(n int) {
+	m.viewport.SetYOffset(n)
+}
+
+// GotoTop scrolls to the top of the content.
+func (m *Model) GotoTop() {
+	m.viewport.GotoTop()
+}
+
+// GotoBottom scrolls to the bottom of the content.
+func (m *Model) GotoBottom() {
+	m.viewport.GotoBottom()
+}
+
+// AtTop returns whether the view is scrolled to the top.
+func (m Model) AtTop() bool {
+	return m.viewport.AtTop()
+}
+
+// AtBottom returns whether the view is scrolled to the bottom.
+func (m Model) AtBottom() bool {
+	return m.viewport.AtBottom()
+}
+
+// ScrollPercent returns how far the view is scrolled, from 0 to 1.
+func (m Model) ScrollPercent() float64 {
+	return m.viewport.ScrollPercent()
+}
+
+func (m *Model) refresh() {
+	if m.Renderer != nil {
+		m.rendered = m.Renderer(m.markdown, m.viewport.Width)
+	} else {
+		m.rendered = Render(m.markdown, m.viewport.Width, m.Styles)
+	}
+	m.viewport.SetContent(m.rendered)
+}
+
+// Init exists to satisfy the tea.Model interface.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update scrolls the view in response to keys and the mouse wheel.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.viewport.KeyMap = m.KeyMap
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View renders the view.
+func (m Model) View() string {
+	return m.viewport.View()
+}
+
+// RenderString renders the view deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	m.SetSize(width, height)
+	return render.View(m.View, width, height)
+}
+
+var (
+	headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	fenceRe   = regexp.MustCompile("^(```+|~~~+)")
+	ruleRe    = regexp.MustCompile(`^(?:(?:\*\s*){3,}|(?:-\s*){3,}|(?:_\s*){3,})$`)
+	listRe    = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+)
+
+// Render renders Markdown at the given width with the given styles. Words
+// longer than the width are broken, while code blocks are left as they are.
+func Render(markdown string, width int, styles Styles) string {
+	r := renderer{styles: styles, width: max(1, width)}
+	r.blocks(strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n"), "")
+	return strings.Join(r.out, "\n")
+}
+
+// renderer renders blocks of Markdown to lines of output.
+type renderer struct {
+	styles Styles
+	width  int
+	out    []string
+}
+
+// blocks renders lines of Markdown, prefixing each line of output with
+// prefix, as for block quotes.
+func (r *renderer) blocks(lines []string, prefix string) {
+	start := len(r.out)
+	blank := strings.TrimRight(prefix, " ")
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			r.paragraph(strings.Join(para, " "), prefix)
+			para = nil
+		}
+	}
+	// gap separates blocks with an empty line.
+	gap := func() {
+		flush()
+		if len(r.out) > start && r.out[len(r.out)-1] != blank {
+			r.out = append(r.out, blank)
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			gap()
+		case fenceRe.MatchString(trimmed):
+			gap()
+			fence := fenceRe.FindString(trimmed)
+			i++
+			for ; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				r.out = append(r.out, prefix+r.styles.CodeBlock.Render(expandTabs(lines[i])))
+			}
+			gap()
+		case listRe.MatchString(line):
+			flush()
+			m := listRe.FindStringSubmatch(line)
+			indent := strings.Repeat("  ", len(expandTabs(m[1]))/2) //nolint:mnd
+			bullet := "•"
+			if !strings.ContainsAny(m[2], "-*+") {
+				bullet = m[2]
+			}
+			item := []string{m[3]}
+			// Continuation lines are indented, or lazily not, until the
+			// next item, blank line or block.
+			for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" && !listRe.MatchString(lines[i+1]) && !startsBlock(lines[i+1]) {
+				i++
+				item = append(item, strings.TrimSpace(lines[i]))
+			}
+			r.listItem(strings.Join(item, " "), prefix+indent, bullet)
+		case len(para) == 0 && strings.HasPrefix(line, "    "):
+			gap()
+			for ; i < len(lines) && (strings.HasPrefix(lines[i], "    ") || strings.TrimSpace(lines[i]) == ""); i++ {
+				r.out = append(r.out, prefix+r.styles.CodeBlock.Render(expandTabs(strings.TrimPrefix(lines[i], "    "))))
+			}
+			i--
+			gap()
+		case headingRe.MatchString(trimmed):
+			gap()
+			m := headingRe.FindStringSubmatch(trimmed)
+			style := r.styles.Heading
+			if len(m[1]) == 1 {
+				style = r.styles.Heading1
+			}
+			for _, l := range r.wrap(r.inline(m[2]), len(prefix)) {
+				r.out = append(r.out, prefix+style.Render(l))
+			}
+			gap()
+		case ruleRe.MatchString(trimmed):
+			gap()
+			r.out = append(r.out, prefix+r.styles.Rule.Render(strings.Repeat("─", max(1, r.width-ansi.StringWidth(prefix)))))
+			gap()
+		case strings.HasPrefix(trimmed, ">"):
+			gap()
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				l := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quote = append(quote, strings.TrimPrefix(l, " "))
+			}
+			i--
+			first := len(r.out)
+			marker := prefix + r.styles.QuoteMarker.Render("│") + " "
+			r.blocks(quote, marker)
+			for j := first; j < len(r.out); j++ {
+				if text := strings.TrimPrefix(r.out[j], marker); text != r.out[j] {
+					r.out[j] = marker + r.styles.Quote.Render(text)
+				}
+			}
+			gap()
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+
+	// Drop the gap after the last block.
+	for len(r.out) > start && r.out[len(r.out)-1] == blank {
+		r.out = r.out[:len(r.out)-1]
+	}
+}
+
+// startsBlock returns whether a line starts a block other than a paragraph.
+func startsBlock(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return fenceRe.MatchString(trimmed) || headingRe.MatchString(trimmed) ||
+		ruleRe.MatchString(trimmed) || strings.HasPrefix(trimmed, ">")
+}
+
+// paragraph renders a paragraph of text, word wrapped.
+func (r *renderer) paragraph(text, prefix string) {
+	for _, l := range r.wrap(r.inline(text), ansi.StringWidth(prefix)) {
+		r.out = append(r.out, prefix+l)
+	}
+}
+
+// listItem renders a list item, with continuation lines aligned with its
+// text.
+func (r *renderer) listItem(text, prefix, bullet string) {
+	marker := r.styles.Bullet.Render(bullet) + " "
+	hang := strings.Repeat(" ", ansi.StringWidth(bullet)+1)
+	for i, l := range r.wrap(r.inline(text), ansi.StringWidth(prefix+hang)) {
+		if i == 0 {
+			r.out = append(r.out, prefix+marker+l)
+		} else {
+			r.out = append(r.out, prefix+hang+l)
+		}
+	}
+}
+
+// wrap word wraps styled text to the width left after an indent.
+func (r *renderer) wrap(text string, indent int) []string {
+	return strings.Split(ansi.Wrap(text, max(1, r.width-indent), ""), "\n")
+}
+
+// inline renders the inline elements of text: code spans, strong text,
+// emphasis, links and backslash escapes.
+func (r *renderer) inline(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("\\`*_[]()#+-.!>", rune(rest[1])):
+			b.WriteByte(rest[1])
+			i += 2
+			continue
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				b.WriteString(r.styles.Code.Render(rest[1 : end+1]))
+				i += end + 2 //nolint:mnd
+				continue
+			}
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if end := strings.Index(rest[2:], rest[:2]); end > 0 {
+				b.WriteString(r.styles.Strong.Render(unescape(rest[2 : end+2])))
+				i += end + 4 //nolint:mnd
+				continue
+			}
+		case rest[0] == '*' || rest[0] == '_':
+			// Underscores within words, as in snake_case, aren't emphasis.
+			if rest[0] == '_' && i > 0 && isWordByte(text[i-1]) {
+				break
+			}
+			if end := strings.IndexByte(rest[1:], rest[0]); end > 0 && rest[1] != ' ' {
+				b.WriteString(r.styles.Emphasis.Render(unescape(rest[1 : end+1])))
+				i += end + 2 //nolint:mnd
+				continue
+			}
+		case rest[0] == '[':
+			if label, url, n, ok := parseLink(rest); ok {
+				b.WriteString(r.styles.Link.Render(unescape(label)))
+				if url != "" && url != label {
+					b.WriteString(" " + r.styles.LinkURL.Render("("+url+")"))
+				}
+				i += n
+				continue
+			}
+		}
+		b.WriteByte(text[i])
+		i++
+	}
+	return b.String()
+}
+
+// parseLink parses a link of the form [label](url) at the start of s,
+// returning its label and address and its length.
+func parseLink(s string) (label, url string, n int, ok bool) {
+	closing := strings.Index(s, "](")
+	if closing < 0 {
+		return "", "", 0, false
+	}
+	end := strings.IndexByte(s[closing+2:], ')')
+	if end < 0 {
+		return "", "", 0, false
+	}
+	label = s[1:closing]
+	url = strings.TrimSpace(s[closing+2 : closing+2+end])
+	if i := strings.IndexByte(url, ' '); i >= 0 {
+		url = url[:i] // drop a title
+	}
+	return label, url, closing + 3 + end, true //nolint:mnd
+}
+
+// unescape removes backslash escapes from text.
+func unescape(text string) string {
+	if !strings.Contains(text, `\`) {
+		return text
+	}
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' && i+1 < len(text) {
+			i++
+		}
+		b.WriteByte(text[i])
+	}
+	return b.String()
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// expandTabs replaces tabs with four spaces, as terminals differ in how they
+// show them.
+func expandTabs(s string) string {
+	return strings.ReplaceAll(s, "\t", "    ")
+}
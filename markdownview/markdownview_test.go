@@ -0,0 +1,112 @@
+package markdownview
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+const doc = `# Markdown view
+
+Some *emphasis*, **strong** text and ` + "`code`" + `, with a
+[link](https://example.com) and snake_case words.
+
+## Lists
+
+- one
+- two, which is long enough to wrap
+  - nested
+1. first
+2. second
+
+> A quote
+> over two lines.
+
+` + "```" + `
+func main() {}
+` + "```" + `
+
+---
+
+The end.`
+
+func TestRender(t *testing.T) {
+	got := ansi.Strip(Render(doc, 30, DefaultStyles()))
+	want := `Markdown view
+
+Some emphasis, strong text and
+code, with a link
+(https://example.com) and
+snake_case words.
+
+Lists
+
+• one
+• two, which is long enough to
+  wrap
+  • nested
+1. first
+2. second
+
+│ A quote over two lines.
+
+func main() {}
+
+──────────────────────────────
+
+The end.`
+	if got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestInline(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{`\*not emphasis\*`, "*not emphasis*"},
+		{"a * b * c", "a * b * c"},
+		{"[same](same)", "same"},
+		{"[text](url \"title\")", "text (url)"},
+		{"unclosed `code", "unclosed `code"},
+		{"## Heading ##", "Heading"},
+	}
+	for _, tt := range tests {
+		if got := ansi.Strip(Render(tt.in, 40, DefaultStyles())); got != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.in, tt.want, got)
+		}
+	}
+}
+
+func TestScroll(t *testing.T) {
+	m := New(30, 5)
+	m.SetContent(doc)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if m.YOffset() != 1 {
+		t.Fatalf("expected to scroll down a line, got %d", m.YOffset())
+	}
+
+	// Resizing rewraps the content without scrolling back to the top.
+	m.SetSize(20, 5)
+	if m.YOffset() != 1 {
+		t.Errorf("expected the scroll position to be kept, got %d", m.YOffset())
+	}
+	if !strings.Contains(ansi.Strip(m.Rendered()), "────────────────────\n") {
+		t.Errorf("expected the content to be rewrapped, got:\n%s", m.Rendered())
+	}
+
+	m.Renderer = func(markdown string, width int) string {
+		return strings.ToUpper(markdown)
+	}
+	m.SetContent("plain")
+	if m.Rendered() != "PLAIN" {
+		t.Errorf("expected the custom renderer to be used, got %q", m.Rendered())
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	m := New(0, 0)
+	m.SetContent(doc)
+	golden.RequireEqual(t, []byte(RenderString(m, 40, 12)))
+}
@@ -0,0 +1,628 @@
+// Package mergeview provides a merge conflict resolver for Bubble Tea
+// applications. It presents "ours" and "theirs" panes for each conflict hunk
+// alongside an editable result pane, and emits the resolved text once every
+// hunk has been dealt with.
+package mergeview
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/help"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
+	"github.com/mikeflynn/bubbles/textarea"
+	"github.com/mikeflynn/bubbles/viewport"
+)
+
+// Conflict markers, as written by git.
+const (
+	markerOurs   = "<<<<<<<"
+	markerBase   = "|||||||"
+	markerSep    = "======="
+	markerTheirs = ">>>>>>>"
+)
+
+// Resolution describes how a conflict hunk has been resolved.
+type Resolution int
+
+// Available resolutions.
+const (
+	Unresolved Resolution = iota
+	AcceptOurs
+	AcceptTheirs
+	AcceptBoth
+	Edited
+)
+
+// String returns a human-readable name for the resolution.
+func (r Resolution) String() string {
+	names := [...]string{
+		"unresolved",
+		"ours",
+		"theirs",
+		"both",
+		"edited",
+	}
+	if r < 0 || int(r) >= len(names) {
+		return fmt.Sprintf("Resolution(%d)", int(r))
+	}
+	return names[r]
+}
+
+// Hunk is a single conflict region.
+type Hunk struct {
+	// Labels written after the conflict markers, usually branch names.
+	OursLabel   string
+	TheirsLabel string
+	BaseLabel   string
+
+	// The conflicting sides. Base is only populated for diff3-style
+	// conflicts, for which it's non-nil even when the base is empty.
+	Ours   []string
+	Base   []string
+	Theirs []string
+
+	// Resolution is how the hunk has been resolved, if at all.
+	Resolution Resolution
+
+	// Result holds the resolved lines. It's only meaningful when the hunk has
+	// been resolved.
+	Result []string
+}
+
+// Resolved returns whether the hunk has been resolved.
+func (h Hunk) Resolved() bool {
+	return h.Resolution != Unresolved
+}
+
+// segment is either a run of context lines or a conflict hunk.
+type segment struct {
+	lines []string
+	hunk  int // index into Model.hunks, or -1 for context
+}
+
+// ResolvedMsg is sent when the user confirms the merge and every hunk has been
+// resolved.
+type ResolvedMsg struct {
+	Text string
+}
+
+// KeyMap is the key bindings for moving between conflict hunks and
+// resolving them. It satisfies the help.KeyMap interface.
+type KeyMap struct {
+	NextHunk     key.Binding
+	PrevHunk     key.Binding
+	AcceptOurs   key.Binding
+	AcceptTheirs key.Binding
+	AcceptBoth   key.Binding
+	Reset        key.Binding
+	Edit         key.Binding
+	FinishEdit   key.Binding
+	Confirm      key.Binding
+}
+
+// ShortHelp implements the KeyMap interface.
+func (km KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{km.NextHunk, km.PrevHunk, km.AcceptOurs, km.AcceptTheirs, km.AcceptBoth, km.Confirm}
+}
+
+// FullHelp implements the KeyMap interface.
+func (km KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{km.NextHunk, km.PrevHunk},
+		{km.AcceptOurs, km.AcceptTheirs, km.AcceptBoth, km.Reset},
+		{km.Edit, km.FinishEdit, km.Confirm},
+	}
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		NextHunk: key.NewBinding(
+			key.WithKeys("n", "]", "down"),
+			key.WithHelp("n", "next hunk"),
+		),
+		PrevHunk: key.NewBinding(
+			key.WithKeys("p", "[", "up"),
+			key.WithHelp("p", "prev hunk"),
+		),
+		AcceptOurs: key.NewBinding(
+			key.WithKeys("o", "left"),
+			key.WithHelp("o", "accept ours"),
+		),
+		AcceptTheirs: key.NewBinding(
+			key.WithKeys("t", "right"),
+			key.WithHelp("t", "accept theirs"),
+		),
+		AcceptBoth: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "accept both"),
+		),
+		Reset: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "unresolve"),
+		),
+		Edit: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "edit result"),
+		),
+		FinishEdit: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "finish editing"),
+			key.WithDisabled(),
+		),
+		Confirm: key.NewBinding(
+			key.WithKeys("ctrl+s"),
+			key.WithHelp("ctrl+s", "confirm"),
+		),
+	}
+}
+
+// Styles are the styles of the ours, theirs and result panes and of the
+// status line counting resolved hunks. DefaultStyles returns the defaults.
+type Styles struct {
+	Pane        lipgloss.Style
+	ActivePane  lipgloss.Style
+	PaneTitle   lipgloss.Style
+	Status      lipgloss.Style
+	Unresolved  lipgloss.Style
+	Resolved    lipgloss.Style
+	HelpSection lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this
+// component.
+func DefaultStyles() Styles {
+	border := lipgloss.AdaptiveColor{Light: "#DDDADA", Dark: "#3C3C3C"}
+	active := lipgloss.AdaptiveColor{Light: "#F793FF", Dark: "#AD58B4"}
+
+	return Styles{
+		Pane:        lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(border),
+		ActivePane:  lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(active),
+		PaneTitle:   lipgloss.NewStyle().Bold(true),
+		Status:      lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}),
+		Unresolved:  lipgloss.NewStyle().Foreground(lipgloss.Color("203")),
+		Resolved:    lipgloss.NewStyle().Foreground(lipgloss.Color("42")),
+		HelpSection: lipgloss.NewStyle().PaddingTop(1),
+	}
+}
+
+// Model is the state of a merge conflict resolver.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+	Help   help.Model
+
+	// ShowHelp determines whether the help view is rendered beneath the
+	// panes.
+	ShowHelp bool
+
+	segments []segment
+	hunks    []Hunk
+	current  int
+	editing  bool
+
+	// editSeed is the text the result pane held when editing started.
+	editSeed string
+
+	width  int
+	height int
+
+	ours   viewport.Model
+	theirs viewport.Model
+	result textarea.Model
+}
+
+// New returns a model for the given text, which should contain git-style
+// conflict markers.
+func New(text string) Model {
+	ta := textarea.New()
+	ta.ShowLineNumbers = false
+	ta.Prompt = ""
+
+	m := Model{
+		KeyMap:   DefaultKeyMap(),
+		Styles:   DefaultStyles(),
+		Help:     help.New(),
+		ShowHelp: true,
+		ours:     viewport.New(0, 0),
+		theirs:   viewport.New(0, 0),
+		result:   ta,
+	}
+	m.ours.KeyMap = viewport.KeyMap{}
+	m.theirs.KeyMap = viewport.KeyMap{}
+	m.SetContent(text)
+	return m
+}
+
+// SetContent replaces the text being merged, parsing its conflict markers.
+func (m *Model) SetContent(text string) {
+	m.segments, m.hunks = parse(text)
+	m.current = 0
+	m.editing = false
+	m.updatePanes()
+}
+
+// parse splits text into context segments and conflict hunks.
+func parse(text string) ([]segment, []Hunk) {
+	var (
+		segments []segment
+		hunks    []Hunk
+		context  []string
+		h        *Hunk
+		side     *[]string
+	)
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	for _, l := range strings.Split(text, "\n") {
+		switch {
+		case h == nil && strings.HasPrefix(l, markerOurs):
+			if len(context) > 0 {
+				segments = append(segments, segment{lines: context, hunk: -1})
+				context = nil
+			}
+			h = &Hunk{OursLabel: markerLabel(l)}
+			side = &h.Ours
+		case h != nil && strings.HasPrefix(l, markerBase):
+			h.BaseLabel = markerLabel(l)
+			h.Base = []string{}
+			side = &h.Base
+		case h != nil && strings.HasPrefix(l, markerSep):
+			side = &h.Theirs
+		case h != nil && strings.HasPrefix(l, markerTheirs):
+			h.TheirsLabel = markerLabel(l)
+			segments = append(segments, segment{hunk: len(hunks)})
+			hunks = append(hunks, *h)
+			h, side = nil, nil
+		case h != nil:
+			*side = append(*side, l)
+		default:
+			context = append(context, l)
+		}
+	}
+
+	// An unterminated conflict is treated as context so no text is lost.
+	if h != nil {
+		context = append(context, markerOurs+labelSuffix(h.OursLabel))
+		context = append(context, h.Ours...)
+		if h.Base != nil {
+			context = append(context, markerBase+labelSuffix(h.BaseLabel))
+			context = append(context, h.Base...)
+		}
+		if side == &h.Theirs {
+			context = append(context, markerSep)
+			context = append(context, h.Theirs...)
+		}
+	}
+	if len(context) > 0 {
+		segments = append(segments, segment{lines: context, hunk: -1})
+	}
+
+	return segments, hunks
+}
+
+func markerLabel(l string) string {
+	return strings.TrimSpace(l[len(markerOurs):])
+}
+
+func labelSuffix(label string) string {
+	if label == "" {
+		return ""
+	}
+	return " " + label
+}
+
+// Hunks returns the conflict hunks and their current resolutions.
+func (m Model) Hunks() []Hunk {
+	return m.hunks
+}
+
+// Current returns the index of the selected hunk.
+func (m Model) Current() int {
+	return m.current
+}
+
+// SetCurrent selects the hunk at the given index.
+func (m *Model) SetCurrent(i int) {
+	if len(m.hunks) == 0 {
+		return
+	}
+	m.current = clamp(i, 0, len(m.hunks)-1)
+	m.updatePanes()
+}
+
+// Unresolved returns the number of hunks that haven't been resolved yet.
+func (m Model) Unresolved() int {
+	var n int
+	for _, h := range m.hunks {
+		if !h.Resolved() {
+			n++
+		}
+	}
+	return n
+}
+
+// Resolved returns whether every hunk has been resolved.
+func (m Model) Resolved() bool {
+	return m.Unresolved() == 0
+}
+
+// Editing returns whether the result pane is being edited.
+func (m Model) Editing() bool {
+	return m.editing
+}
+
+// Resolve resolves the current hunk with the given resolution. Passing
+// Unresolved clears any previous resolution. To resolve a hunk with custom
+// text use SetResult instead.
+func (m *Model) Resolve(r Resolution) {
+	if len(m.hunks) == 0 {
+		return
+	}
+	h := &m.hunks[m.current]
+	h.Resolution = r
+	switch r {
+	case AcceptOurs:
+		h.Result = cloneLines(h.Ours)
+	case AcceptTheirs:
+		h.Result = cloneLines(h.Theirs)
+	case AcceptBoth:
+		h.Result = append(cloneLines(h.Ours), h.Theirs...)
+	case Edited:
+		// Keep the existing result; it's been edited in place.
+	case Unresolved:
+		h.Result = nil
+	}
+	m.updatePanes()
+}
+
+// SetResult resolves the current hunk with the given text.
+func (m *Model) SetResult(text string) {
+	if len(m.hunks) == 0 {
+		return
+	}
+	h := &m.hunks[m.current]
+	h.Resolution = Edited
+	h.Result = strings.Split(text, "\n")
+	m.updatePanes()
+}
+
+// Value returns the merged text. Hunks which have not been resolved are
+// written back out with their conflict markers.
+func (m Model) Value() string {
+	var lines []string
+	for _, s := range m.segments {
+		if s.hunk < 0 {
+			lines = append(lines, s.lines...)
+			continue
+		}
+		h := m.hunks[s.hunk]
+		if h.Resolved() {
+			lines = append(lines, h.Result...)
+			continue
+		}
+		lines = append(lines, markerOurs+labelSuffix(h.OursLabel))
+		lines = append(lines, h.Ours...)
+		if h.Base != nil {
+			lines = append(lines, markerBase+labelSuffix(h.BaseLabel))
+			lines = append(lines, h.Base...)
+		}
+		lines = append(lines, markerSep)
+		lines = append(lines, h.Theirs...)
+		lines = append(lines, markerTheirs+labelSuffix(h.TheirsLabel))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SetSize sets the dimensions of the component.
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.Help.Width = width
+	m.updatePanes()
+}
+
+// Width returns the width of the component.
+func (m Model) Width() int {
+	return m.width
+}
+
+// Height returns the height of the component.
+func (m Model) Height() int {
+	return m.height
+}
+
+// Init exists to satisfy the tea.Model interface.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if m.editing {
+		if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.KeyMap.FinishEdit) {
+			m.finishEditing()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.result, cmd = m.result.Update(msg)
+		return m, cmd
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.KeyMap.NextHunk):
+		m.SetCurrent(m.current + 1)
+	case key.Matches(keyMsg, m.KeyMap.PrevHunk):
+		m.SetCurrent(m.current - 1)
+	case key.Matches(keyMsg, m.KeyMap.AcceptOurs):
+		m.Resolve(AcceptOurs)
+	case key.Matches(keyMsg, m.KeyMap.AcceptTheirs):
+		m.Resolve(AcceptTheirs)
+	case key.Matches(keyMsg, m.KeyMap.AcceptBoth):
+		m.Resolve(AcceptBoth)
+	case key.Matches(keyMsg, m.KeyMap.Reset):
+		m.Resolve(Unresolved)
+	case key.Matches(keyMsg, m.KeyMap.Edit):
+		if len(m.hunks) == 0 {
+			break
+		}
+		m.startEditing()
+		return m, m.result.Focus()
+	case key.Matches(keyMsg, m.KeyMap.Confirm):
+		if !m.Resolved() {
+			break
+		}
+		text := m.Value()
+		return m, func() tea.Msg {
+			return ResolvedMsg{Text: text}
+		}
+	}
+
+	return m, nil
+}
+
+// startEditing starts editing the result of the current hunk. An unresolved
+// hunk starts out with both sides, ours first, to pick lines from.
+func (m *Model) startEditing() {
+	h := m.hunks[m.current]
+	if !h.Resolved() {
+		m.result.SetValue(strings.Join(append(cloneLines(h.Ours), h.Theirs...), "\n"))
+	}
+	m.editSeed = m.result.Value()
+	m.editing = true
+	m.updateKeybindings()
+}
+
+// finishEditing stops editing, resolving the current hunk with the edited
+// text. If it wasn't changed, the hunk keeps its resolution.
+func (m *Model) finishEditing() {
+	m.editing = false
+	m.result.Blur()
+	if v := m.result.Value(); v != m.editSeed {
+		m.SetResult(v)
+	} else {
+		m.updatePanes()
+	}
+	m.updateKeybindings()
+}
+
+func (m *Model) updateKeybindings() {
+	m.KeyMap.FinishEdit.SetEnabled(m.editing)
+	for _, b := range []*key.Binding{
+		&m.KeyMap.NextHunk, &m.KeyMap.PrevHunk, &m.KeyMap.AcceptOurs,
+		&m.KeyMap.AcceptTheirs, &m.KeyMap.AcceptBoth, &m.KeyMap.Reset,
+		&m.KeyMap.Edit, &m.KeyMap.Confirm,
+	} {
+		b.SetEnabled(!m.editing)
+	}
+}
+
+// updatePanes syncs the panes with the current hunk and dimensions.
+func (m *Model) updatePanes() {
+	frameW := m.Styles.Pane.GetHorizontalFrameSize()
+	frameH := m.Styles.Pane.GetVerticalFrameSize()
+
+	// The panes share the height left over after the status line and help,
+	// with the top row getting half and the result pane the remainder.
+	avail := m.height - 1 // status line
+	if m.ShowHelp {
+		avail -= lipgloss.Height(m.helpView())
+	}
+	topH := max(0, avail/2-frameH-1)          //nolint:mnd
+	bottomH := max(0, avail-avail/2-frameH-1) //nolint:mnd
+
+	halfW := m.width / 2 //nolint:mnd
+	m.ours.Width = max(0, halfW-frameW)
+	m.ours.Height = topH
+	m.theirs.Width = max(0, m.width-halfW-frameW)
+	m.theirs.Height = topH
+	m.result.SetWidth(max(0, m.width-frameW))
+	m.result.SetHeight(bottomH)
+
+	if len(m.hunks) == 0 {
+		m.ours.SetContent("")
+		m.theirs.SetContent("")
+		m.result.SetValue("")
+		return
+	}
+
+	h := m.hunks[m.current]
+	m.ours.SetContent(strings.Join(h.Ours, "\n"))
+	m.theirs.SetContent(strings.Join(h.Theirs, "\n"))
+	if !m.editing {
+		m.result.SetValue(strings.Join(h.Result, "\n"))
+	}
+}
+
+// View renders the component.
+func (m Model) View() string {
+	oursStyle, theirsStyle, resultStyle := m.Styles.Pane, m.Styles.Pane, m.Styles.Pane
+	if m.editing {
+		resultStyle = m.Styles.ActivePane
+	}
+
+	var h Hunk
+	if len(m.hunks) > 0 {
+		h = m.hunks[m.current]
+	}
+
+	top := lipgloss.JoinHorizontal(lipgloss.Top,
+		oursStyle.Render(m.paneTitle("ours", h.OursLabel)+"\n"+m.ours.View()),
+		theirsStyle.Render(m.paneTitle("theirs", h.TheirsLabel)+"\n"+m.theirs.View()),
+	)
+	bottom := resultStyle.Render(m.paneTitle("result", h.Resolution.String()) + "\n" + m.result.View())
+
+	sections := []string{top, bottom, m.statusView()}
+	if m.ShowHelp {
+		sections = append(sections, m.helpView())
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// RenderString renders the merge view deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	m.SetSize(width, height)
+	return render.View(m.View, width, height)
+}
+
+func (m Model) paneTitle(name, label string) string {
+	if label != "" {
+		name += " (" + label + ")"
+	}
+	return m.Styles.PaneTitle.Render(name)
+}
+
+func (m Model) statusView() string {
+	if len(m.hunks) == 0 {
+		return m.Styles.Status.Render("No conflicts")
+	}
+
+	status := m.Styles.Status.Render(fmt.Sprintf("Hunk %d/%d", m.current+1, len(m.hunks))) + " "
+	if n := m.Unresolved(); n > 0 {
+		status += m.Styles.Unresolved.Render(fmt.Sprintf("%d unresolved", n))
+	} else {
+		status += m.Styles.Resolved.Render("all resolved")
+	}
+	return status
+}
+
+func (m Model) helpView() string {
+	return m.Styles.HelpSection.Render(m.Help.View(m.KeyMap))
+}
+
+func cloneLines(l []string) []string {
+	return append([]string(nil), l...)
+}
+
+func clamp(v, low, high int) int {
+	return min(max(v, low), high)
+}
@@ -0,0 +1,174 @@
+package mergeview
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+const conflicted = `header
+<<<<<<< HEAD
+ours one
+ours two
+=======
+theirs one
+>>>>>>> feature
+middle
+<<<<<<< HEAD
+a
+||||||| base
+b
+=======
+c
+>>>>>>> feature
+footer`
+
+func keyPress(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestParse(t *testing.T) {
+	m := New(conflicted)
+
+	hunks := m.Hunks()
+	if len(hunks) != 2 {
+		t.Fatalf("expected 2 hunks, got %d", len(hunks))
+	}
+
+	first := hunks[0]
+	if first.OursLabel != "HEAD" || first.TheirsLabel != "feature" {
+		t.Errorf("unexpected labels: %q, %q", first.OursLabel, first.TheirsLabel)
+	}
+	if !reflect.DeepEqual(first.Ours, []string{"ours one", "ours two"}) {
+		t.Errorf("unexpected ours: %v", first.Ours)
+	}
+	if !reflect.DeepEqual(first.Theirs, []string{"theirs one"}) {
+		t.Errorf("unexpected theirs: %v", first.Theirs)
+	}
+
+	second := hunks[1]
+	if second.BaseLabel != "base" || !reflect.DeepEqual(second.Base, []string{"b"}) {
+		t.Errorf("expected diff3 base to be parsed, got %q %v", second.BaseLabel, second.Base)
+	}
+
+	// An untouched merge must round-trip exactly.
+	if got := m.Value(); got != conflicted {
+		t.Errorf("expected unresolved value to round-trip, got:\n%s", got)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	m := New(conflicted)
+
+	m, _ = m.Update(keyPress("o"))
+	if m.Hunks()[0].Resolution != AcceptOurs {
+		t.Fatalf("expected first hunk to accept ours, got %s", m.Hunks()[0].Resolution)
+	}
+
+	m, _ = m.Update(keyPress("n"))
+	if m.Current() != 1 {
+		t.Fatalf("expected to move to the second hunk, got %d", m.Current())
+	}
+
+	// Confirming with unresolved hunks does nothing.
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd != nil {
+		t.Fatal("expected no command while hunks are unresolved")
+	}
+
+	m, _ = m.Update(keyPress("b"))
+	if !m.Resolved() {
+		t.Fatal("expected all hunks to be resolved")
+	}
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd == nil {
+		t.Fatal("expected a command when confirming")
+	}
+	msg, ok := cmd().(ResolvedMsg)
+	if !ok {
+		t.Fatalf("expected ResolvedMsg, got %T", cmd())
+	}
+
+	expected := strings.Join([]string{
+		"header", "ours one", "ours two", "middle", "a", "c", "footer",
+	}, "\n")
+	if msg.Text != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, msg.Text)
+	}
+}
+
+func TestEditResult(t *testing.T) {
+	m := New(conflicted)
+	m.SetSize(80, 30)
+
+	m, _ = m.Update(keyPress("t"))
+	m, _ = m.Update(keyPress("e"))
+	if !m.Editing() {
+		t.Fatal("expected to be editing")
+	}
+
+	m, _ = m.Update(keyPress("!"))
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if m.Editing() {
+		t.Fatal("expected editing to finish")
+	}
+
+	h := m.Hunks()[0]
+	if h.Resolution != Edited {
+		t.Fatalf("expected hunk to be edited, got %s", h.Resolution)
+	}
+	if !reflect.DeepEqual(h.Result, []string{"theirs one!"}) {
+		t.Errorf("unexpected result: %v", h.Result)
+	}
+}
+
+func TestEditUnchanged(t *testing.T) {
+	m := New(conflicted)
+	m.SetSize(80, 30)
+
+	m, _ = m.Update(keyPress("e"))
+	if got, want := m.result.Value(), "ours one\nours two\ntheirs one"; got != want {
+		t.Fatalf("expected the editor to start with both sides, got %q", got)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if h := m.Hunks()[0]; h.Resolved() {
+		t.Fatalf("expected the hunk to stay unresolved, got %s", h.Resolution)
+	}
+
+	m, _ = m.Update(keyPress("o"))
+	m, _ = m.Update(keyPress("e"))
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if h := m.Hunks()[0]; h.Resolution != AcceptOurs {
+		t.Fatalf("expected the hunk to keep its resolution, got %s", h.Resolution)
+	}
+}
+
+func TestEmptyBase(t *testing.T) {
+	text := "<<<<<<< HEAD\na\n||||||| base\n=======\nb\n>>>>>>> feature"
+	m := New(text)
+	if h := m.Hunks()[0]; h.Base == nil || len(h.Base) != 0 {
+		t.Fatalf("expected an empty base, got %#v", h.Base)
+	}
+	if got := m.Value(); got != text {
+		t.Errorf("expected the empty base to round-trip, got:\n%s", got)
+	}
+}
+
+func TestResolutionString(t *testing.T) {
+	if got := AcceptTheirs.String(); got != "theirs" {
+		t.Errorf("expected theirs, got %q", got)
+	}
+	if got := Resolution(42).String(); got != "Resolution(42)" {
+		t.Errorf("expected Resolution(42), got %q", got)
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	t.Run("unresolved", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(New(conflicted), 60, 16)))
+	})
+}
@@ -0,0 +1,34 @@
+package paginator
+
+// PerPageForHeight returns the number of items that fit in the given height,
+// given the height of each item and the rows reserved for headers, footers
+// and the paginator itself. An item height of 0 or less counts as 1. At least
+// one item is always shown.
+func PerPageForHeight(height, itemHeight, reserved int) int {
+	return max(1, (height-reserved)/max(1, itemHeight))
+}
+
+// SetHeight sets PerPage to the number of items that fit in the given height,
+// according to ItemHeight and ReservedHeight, such as when the terminal is
+// resized. The page changes to the one with the item that was first on the
+// current page, so that it stays on screen. If SetTotalPages was called
+// before, the total number of pages is worked out again from the same number
+// of items.
+func (m *Model) SetHeight(height int) {
+	first := m.Page * m.PerPage
+	m.PerPage = PerPageForHeight(height, m.ItemHeight, m.ReservedHeight)
+	m.Page = first / m.PerPage
+	if m.items > 0 {
+		m.SetTotalPages(m.items)
+	}
+	m.Page = min(m.Page, max(0, m.TotalPages-1))
+}
+
+// WithItemHeight sets the height of each item and the rows reserved around
+// them, used by SetHeight.
+func WithItemHeight(itemHeight, reserved int) Option {
+	return func(m *Model) {
+		m.ItemHeight = itemHeight
+		m.ReservedHeight = reserved
+	}
+}
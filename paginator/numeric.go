@@ -0,0 +1,114 @@
+package paginator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles are the styles used under the Numeric display type.
+type Styles struct {
+	// Page is used for page numbers away from the current page, such as the
+	// first and last pages.
+	Page lipgloss.Style
+	// Current is used for the current page.
+	Current lipgloss.Style
+	// Adjacent is used for the pages within Window of the current page.
+	Adjacent lipgloss.Style
+	// Ellipsis is used for the marker standing in for skipped pages.
+	Ellipsis lipgloss.Style
+}
+
+// DefaultStyles returns the default styles for the Numeric display type.
+func DefaultStyles() Styles {
+	subdued := lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}
+	return Styles{
+		Page:     lipgloss.NewStyle().Foreground(subdued),
+		Current:  lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212")),
+		Adjacent: lipgloss.NewStyle(),
+		Ellipsis: lipgloss.NewStyle().Foreground(subdued),
+	}
+}
+
+// ellipsisPage marks a run of skipped pages in the result of numericPages.
+const ellipsisPage = -1
+
+// numericPages returns the pages to show under the Numeric display type, in
+// order, with ellipsisPage standing in for skipped runs. The first and last
+// pages are always shown, along with Window pages on either side of the
+// current page. A run of a single page is shown rather than skipped, since
+// the ellipsis would take as much room.
+func (m Model) numericPages() []int {
+	if m.TotalPages < 1 {
+		return nil
+	}
+	window := max(0, m.Window)
+	from := max(0, m.Page-window)
+	to := min(m.TotalPages-1, m.Page+window)
+
+	var pages []int
+	switch {
+	case from > 2: //nolint:mnd
+		pages = append(pages, 0, ellipsisPage)
+	default:
+		from = 0
+	}
+	for p := from; p <= to; p++ {
+		pages = append(pages, p)
+	}
+	switch {
+	case to < m.TotalPages-3: //nolint:mnd
+		pages = append(pages, ellipsisPage, m.TotalPages-1)
+	default:
+		for p := to + 1; p < m.TotalPages; p++ {
+			pages = append(pages, p)
+		}
+	}
+	return pages
+}
+
+// numericItem renders an entry from numericPages.
+func (m Model) numericItem(page int) string {
+	switch {
+	case page == ellipsisPage:
+		return m.Styles.Ellipsis.Render(m.Ellipsis)
+	case page == m.Page:
+		return m.Styles.Current.Render(fmt.Sprintf(m.CurrentFormat, page+1))
+	case page >= m.Page-m.Window && page <= m.Page+m.Window:
+		return m.Styles.Adjacent.Render(strconv.Itoa(page + 1))
+	default:
+		return m.Styles.Page.Render(strconv.Itoa(page + 1))
+	}
+}
+
+func (m Model) numericView() string {
+	pages := m.numericPages()
+	items := make([]string, len(pages))
+	for i, p := range pages {
+		items[i] = m.numericItem(p)
+	}
+	return strings.Join(items, " ")
+}
+
+// PageAt returns the page whose number is rendered at the given column of the
+// Numeric view, counting from zero at its left edge. It returns false if
+// there's no page number there, such as over an ellipsis or a space.
+func (m Model) PageAt(x int) (page int, ok bool) {
+	if x < 0 {
+		return 0, false
+	}
+	var left int
+	for _, p := range m.numericPages() {
+		w := lipgloss.Width(m.numericItem(p))
+		if x < left+w {
+			return p, p != ellipsisPage
+		}
+		left += w + 1
+		if x < left {
+			return 0, false
+		}
+	}
+	return 0, false
+}
@@ -9,6 +9,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
 )
 
 // Type specifies the way we render pagination.
@@ -18,6 +19,7 @@ type Type int
 const (
 	Arabic Type = iota
 	Dots
+	Numeric
 )
 
 // KeyMap is the key bindings for different actions within the paginator.
@@ -35,7 +37,7 @@ var DefaultKeyMap = KeyMap{
 
 // Model is the Bubble Tea model for this user interface.
 type Model struct {
-	// Type configures how the pagination is rendered (Arabic, Dots).
+	// Type configures how the pagination is rendered (Arabic, Dots, Numeric).
 	Type Type
 	// Page is the current page number.
 	Page int
@@ -43,6 +45,11 @@ type Model struct {
 	PerPage int
 	// TotalPages is the total number of pages.
 	TotalPages int
+	// ItemHeight is the height of each item in rows, and ReservedHeight the
+	// rows taken up by anything else, such as headers, footers and the
+	// paginator itself. SetHeight uses them to work out PerPage.
+	ItemHeight     int
+	ReservedHeight int
 	// ActiveDot is used to mark the current page under the Dots display type.
 	ActiveDot string
 	// InactiveDot is used to mark inactive pages under the Dots display type.
@@ -50,6 +57,21 @@ type Model struct {
 	// ArabicFormat is the printf-style format to use for the Arabic display type.
 	ArabicFormat string
 
+	// Window is the number of pages shown on either side of the current page
+	// under the Numeric display type.
+	Window int
+	// Ellipsis marks skipped pages under the Numeric display type.
+	Ellipsis string
+	// CurrentFormat is the printf-style format used for the current page
+	// under the Numeric display type.
+	CurrentFormat string
+	// Styles are used under the Numeric display type.
+	Styles Styles
+
+	// X and Y are the position of the paginator on screen. They're used to
+	// work out which page number was clicked under the Numeric display type.
+	X, Y int
+
 	// KeyMap encodes the keybindings recognized by the widget.
 	KeyMap KeyMap
 
@@ -63,6 +85,9 @@ type Model struct {
 	UseHLKeys bool
 	// Deprecated: customize [KeyMap] instead.
 	UseJKKeys bool
+
+	// items is the number of items last passed to SetTotalPages.
+	items int
 }
 
 // SetTotalPages is a helper function for calculating the total number of pages
@@ -73,6 +98,7 @@ func (m *Model) SetTotalPages(items int) int {
 	if items < 1 {
 		return m.TotalPages
 	}
+	m.items = items
 	n := items / m.PerPage
 	if items%m.PerPage > 0 {
 		n++
@@ -140,10 +166,16 @@ func New(opts ...Option) Model {
 		Page:         0,
 		PerPage:      1,
 		TotalPages:   1,
+		ItemHeight:   1,
 		KeyMap:       DefaultKeyMap,
 		ActiveDot:    "•",
 		InactiveDot:  "○",
 		ArabicFormat: "%d/%d",
+
+		Window:        1,
+		Ellipsis:      "…",
+		CurrentFormat: "[%d]",
+		Styles:        DefaultStyles(),
 	}
 
 	for _, opt := range opts {
@@ -172,6 +204,21 @@ func WithPerPage(perPage int) Option {
 	}
 }
 
+// WithType sets how the pagination is rendered.
+func WithType(t Type) Option {
+	return func(m *Model) {
+		m.Type = t
+	}
+}
+
+// WithWindow sets the number of pages shown on either side of the current
+// page under the Numeric display type.
+func WithWindow(n int) Option {
+	return func(m *Model) {
+		m.Window = n
+	}
+}
+
 // Update is the Tea update function which binds keystrokes to pagination.
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -182,6 +229,14 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		case key.Matches(msg, m.KeyMap.PrevPage):
 			m.PrevPage()
 		}
+
+	case tea.MouseMsg:
+		if m.Type != Numeric || msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft || msg.Y != m.Y {
+			break
+		}
+		if page, ok := m.PageAt(msg.X - m.X); ok {
+			m.Page = page
+		}
 	}
 
 	return m, nil
@@ -192,11 +247,19 @@ func (m Model) View() string {
 	switch m.Type { //nolint:exhaustive
 	case Dots:
 		return m.dotsView()
+	case Numeric:
+		return m.numericView()
 	default:
 		return m.arabicView()
 	}
 }
 
+// RenderString renders the paginator deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	return render.View(m.View, width, height)
+}
+
 func (m Model) dotsView() string {
 	var s string
 	for i := 0; i < m.TotalPages; i++ {
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/golden"
 )
 
 func TestNew(t *testing.T) {
@@ -178,3 +180,99 @@ func TestItemsOnPage(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderString(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		typ  Type
+	}{
+		{"dots", Dots},
+		{"arabic", Arabic},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := New(WithTotalPages(5))
+			m.Type = tc.typ
+			m.Page = 2
+			golden.RequireEqual(t, []byte(RenderString(m, 10, 1)))
+		})
+	}
+}
+
+func TestNumeric(t *testing.T) {
+	tests := []struct {
+		page, total, window int
+		expected            string
+	}{
+		{4, 20, 1, "1 … 4 [5] 6 … 20"},
+		{0, 20, 1, "[1] 2 … 20"},
+		{19, 20, 1, "1 … 19 [20]"},
+		{3, 20, 1, "1 2 3 [4] 5 … 20"},
+		{9, 20, 2, "1 … 8 9 [10] 11 12 … 20"},
+		{2, 5, 1, "1 2 [3] 4 5"},
+		{0, 1, 1, "[1]"},
+	}
+	for _, tt := range tests {
+		m := New(WithType(Numeric), WithTotalPages(tt.total), WithWindow(tt.window))
+		m.Page = tt.page
+		if got := ansi.Strip(m.View()); got != tt.expected {
+			t.Errorf("page %d of %d: expected %q, got %q", tt.page+1, tt.total, tt.expected, got)
+		}
+	}
+
+	t.Run("golden", func(t *testing.T) {
+		m := New(WithType(Numeric), WithTotalPages(20))
+		m.Page = 4
+		golden.RequireEqual(t, []byte(RenderString(m, 16, 1)))
+	})
+
+	m := New(WithType(Numeric), WithTotalPages(20))
+	m.Page = 4
+	m.X, m.Y = 10, 3
+	click := func(x, y int) {
+		m, _ = m.Update(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	}
+
+	click(12, 3) // the ellipsis
+	if m.Page != 4 {
+		t.Fatalf("expected clicking the ellipsis to do nothing, got page %d", m.Page)
+	}
+	click(10+15, 3) // "20"
+	if m.Page != 19 {
+		t.Fatalf("expected to go to the last page, got page %d", m.Page+1)
+	}
+	click(10, 4)
+	if m.Page != 19 {
+		t.Fatalf("expected clicks on other lines to be ignored, got page %d", m.Page+1)
+	}
+	click(10, 3)
+	if m.Page != 0 {
+		t.Fatalf("expected to go to the first page, got page %d", m.Page+1)
+	}
+}
+
+func TestSetHeight(t *testing.T) {
+	if got := PerPageForHeight(20, 2, 3); got != 8 {
+		t.Errorf("expected 8 items of 2 rows in 17, got %d", got)
+	}
+	if got := PerPageForHeight(2, 3, 4); got != 1 {
+		t.Errorf("expected at least one item, got %d", got)
+	}
+
+	m := New(WithItemHeight(2, 2))
+	m.SetHeight(22)
+	m.SetTotalPages(95)
+	if m.PerPage != 10 || m.TotalPages != 10 {
+		t.Fatalf("expected 10 pages of 10 items, got %d pages of %d", m.TotalPages, m.PerPage)
+	}
+
+	// Item 50, first on page 5, stays on screen as the height changes.
+	m.Page = 5
+	m.SetHeight(10)
+	if m.PerPage != 4 || m.Page != 12 || m.TotalPages != 24 {
+		t.Errorf("expected page 12 of 24 with 4 items, got page %d of %d with %d", m.Page, m.TotalPages, m.PerPage)
+	}
+	m.SetHeight(42)
+	if m.PerPage != 20 || m.Page != 2 || m.TotalPages != 5 {
+		t.Errorf("expected page 2 of 5 with 20 items, got page %d of %d with %d", m.Page, m.TotalPages, m.PerPage)
+	}
+}
@@ -0,0 +1,670 @@
+// Package proclist provides a top-like process list for Bubble Tea
+// applications. The host application supplies the processes; the component
+// handles sorting, searching, selection and rendering, and emits messages when
+// the user asks to send a signal to one or more processes.
+package proclist
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/help"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
+	"github.com/mikeflynn/bubbles/textinput"
+	"github.com/mikeflynn/bubbles/textutil"
+)
+
+// Process is a single row in the list.
+type Process struct {
+	PID     int
+	User    string
+	Command string
+
+	// CPU and Mem are usage percentages.
+	CPU float64
+	Mem float64
+}
+
+// SignalMsg is sent when the user asks to send a signal to processes. The
+// component doesn't send signals itself; it's up to the host application to
+// act on this message.
+type SignalMsg struct {
+	PIDs   []int
+	Signal syscall.Signal
+}
+
+// SortKey is the column the list is sorted by.
+type SortKey int
+
+// Available sort keys.
+const (
+	SortCPU SortKey = iota
+	SortMem
+	SortPID
+	SortCommand
+)
+
+// String returns a human-readable name for the sort key.
+func (s SortKey) String() string {
+	return [...]string{
+		"cpu",
+		"mem",
+		"pid",
+		"command",
+	}[s]
+}
+
+// sparkRunes are used to draw CPU sparklines, from lowest to highest.
+var sparkRunes = []rune("▁▂▃▄▅▆▇█")
+
+// KeyMap is the key bindings for moving through the process list, sorting it
+// and signalling processes. It satisfies the help.KeyMap interface.
+type KeyMap struct {
+	CursorUp   key.Binding
+	CursorDown key.Binding
+	PageUp     key.Binding
+	PageDown   key.Binding
+
+	ToggleSelect   key.Binding
+	ClearSelection key.Binding
+
+	CycleSort   key.Binding
+	ReverseSort key.Binding
+
+	Search       key.Binding
+	AcceptSearch key.Binding
+	CancelSearch key.Binding
+
+	Terminate key.Binding
+	Kill      key.Binding
+}
+
+// ShortHelp implements the KeyMap interface.
+func (km KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		km.CursorUp, km.CursorDown, km.ToggleSelect, km.CycleSort,
+		km.Search, km.AcceptSearch, km.CancelSearch, km.Terminate, km.Kill,
+	}
+}
+
+// FullHelp implements the KeyMap interface.
+func (km KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{km.CursorUp, km.CursorDown, km.PageUp, km.PageDown},
+		{km.ToggleSelect, km.ClearSelection, km.CycleSort, km.ReverseSort},
+		{km.Search, km.AcceptSearch, km.CancelSearch},
+		{km.Terminate, km.Kill},
+	}
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		CursorUp: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		CursorDown: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup", "b"),
+			key.WithHelp("b/pgup", "page up"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown", "f"),
+			key.WithHelp("f/pgdn", "page down"),
+		),
+		ToggleSelect: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "select"),
+		),
+		ClearSelection: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "clear selection"),
+		),
+		CycleSort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sort"),
+		),
+		ReverseSort: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "reverse sort"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "search"),
+		),
+		AcceptSearch: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "apply search"),
+			key.WithDisabled(),
+		),
+		CancelSearch: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel search"),
+			key.WithDisabled(),
+		),
+		Terminate: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "terminate"),
+		),
+		Kill: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "kill"),
+		),
+	}
+}
+
+// Styles are the styles of the process list's header, rows and status line.
+// DefaultStyles returns the defaults.
+type Styles struct {
+	Header      lipgloss.Style
+	Row         lipgloss.Style
+	Cursor      lipgloss.Style
+	Selected    lipgloss.Style
+	Sparkline   lipgloss.Style
+	Status      lipgloss.Style
+	HelpSection lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this
+// component.
+func DefaultStyles() Styles {
+	return Styles{
+		Header:      lipgloss.NewStyle().Bold(true),
+		Row:         lipgloss.NewStyle(),
+		Cursor:      lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Bold(true),
+		Selected:    lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#00A66D", Dark: "#04B575"}),
+		Sparkline:   lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#3C7DD9", Dark: "#6C9EE8"}),
+		Status:      lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}),
+		HelpSection: lipgloss.NewStyle().PaddingTop(1),
+	}
+}
+
+// Model is the state of a process list.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+	Help   help.Model
+
+	// ShowHelp determines whether the help view is rendered beneath the list.
+	ShowHelp bool
+
+	// HistoryLength is the number of CPU samples kept per process for the
+	// sparkline column. Samples are recorded each time SetProcesses is called.
+	HistoryLength int
+
+	processes []Process
+	history   map[int][]float64
+	visible   []int // indices into processes, sorted and filtered
+	selected  map[int]bool
+
+	sortKey    SortKey
+	descending bool
+
+	searching bool
+	search    textinput.Model
+
+	cursor int
+	offset int
+	width  int
+	height int
+}
+
+// New returns a model with default settings.
+func New() Model {
+	search := textinput.New()
+	search.Prompt = "/"
+
+	m := Model{
+		KeyMap:        DefaultKeyMap(),
+		Styles:        DefaultStyles(),
+		Help:          help.New(),
+		ShowHelp:      true,
+		HistoryLength: 10, //nolint:mnd
+		history:       map[int][]float64{},
+		selected:      map[int]bool{},
+		descending:    true,
+		search:        search,
+	}
+	m.updateKeybindings()
+	return m
+}
+
+// SetProcesses replaces the processes, recording a CPU sample for each one.
+// The cursor stays on the same process if it's still present, and processes
+// which have gone away are removed from the selection.
+func (m *Model) SetProcesses(processes []Process) {
+	var current int
+	if p, ok := m.Current(); ok {
+		current = p.PID
+	}
+
+	m.processes = processes
+
+	alive := make(map[int]bool, len(processes))
+	for _, p := range processes {
+		alive[p.PID] = true
+		h := append(m.history[p.PID], p.CPU)
+		if m.HistoryLength > 0 && len(h) > m.HistoryLength {
+			h = h[len(h)-m.HistoryLength:]
+		}
+		m.history[p.PID] = h
+	}
+	for pid := range m.history {
+		if !alive[pid] {
+			delete(m.history, pid)
+		}
+	}
+	for pid := range m.selected {
+		if !alive[pid] {
+			delete(m.selected, pid)
+		}
+	}
+
+	m.refresh()
+	m.selectPID(current)
+}
+
+// Processes returns the processes in their current sort order, with any
+// search applied.
+func (m Model) Processes() []Process {
+	procs := make([]Process, len(m.visible))
+	for i, idx := range m.visible {
+		procs[i] = m.processes[idx]
+	}
+	return procs
+}
+
+// Current returns the process under the cursor.
+func (m Model) Current() (Process, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.visible) {
+		return Process{}, false
+	}
+	return m.processes[m.visible[m.cursor]], true
+}
+
+// History returns the recorded CPU samples for the given process, oldest
+// first.
+func (m Model) History(pid int) []float64 {
+	return m.history[pid]
+}
+
+// SetSort sets the column to sort by and the sort direction.
+func (m *Model) SetSort(k SortKey, descending bool) {
+	var current int
+	if p, ok := m.Current(); ok {
+		current = p.PID
+	}
+	m.sortKey = k
+	m.descending = descending
+	m.refresh()
+	m.selectPID(current)
+}
+
+// Sort returns the column the list is sorted by and the sort direction.
+func (m Model) Sort() (SortKey, bool) {
+	return m.sortKey, m.descending
+}
+
+// SetQuery filters the list to processes whose PID, user or command contains
+// the given query.
+func (m *Model) SetQuery(q string) {
+	m.search.SetValue(q)
+	m.refresh()
+}
+
+// Query returns the current search query.
+func (m Model) Query() string {
+	return m.search.Value()
+}
+
+// Selected returns the PIDs of the selected processes in list order.
+func (m Model) Selected() []int {
+	var pids []int
+	for _, idx := range m.visible {
+		if pid := m.processes[idx].PID; m.selected[pid] {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+// ToggleSelected toggles the selection of the process under the cursor.
+func (m *Model) ToggleSelected() {
+	p, ok := m.Current()
+	if !ok {
+		return
+	}
+	if m.selected[p.PID] {
+		delete(m.selected, p.PID)
+	} else {
+		m.selected[p.PID] = true
+	}
+}
+
+// ClearSelection deselects all processes.
+func (m *Model) ClearSelection() {
+	m.selected = map[int]bool{}
+}
+
+// Signal returns a command which asks the host application to send the given
+// signal to the selected processes, or to the process under the cursor if
+// nothing is selected.
+func (m Model) Signal(sig syscall.Signal) tea.Cmd {
+	pids := m.Selected()
+	if len(pids) == 0 {
+		p, ok := m.Current()
+		if !ok {
+			return nil
+		}
+		pids = []int{p.PID}
+	}
+	return func() tea.Msg {
+		return SignalMsg{PIDs: pids, Signal: sig}
+	}
+}
+
+// SetSize sets the dimensions of the component.
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.Help.Width = width
+	m.search.Width = max(0, width-lipgloss.Width(m.search.Prompt)-1)
+	m.updateOffset()
+}
+
+// refresh rebuilds the visible rows from the processes, search and sort.
+func (m *Model) refresh() {
+	q := strings.ToLower(m.search.Value())
+
+	m.visible = nil
+	for i, p := range m.processes {
+		if q != "" &&
+			!strings.Contains(strings.ToLower(p.Command), q) &&
+			!strings.Contains(strings.ToLower(p.User), q) &&
+			!strings.Contains(strconv.Itoa(p.PID), q) {
+			continue
+		}
+		m.visible = append(m.visible, i)
+	}
+
+	sort.SliceStable(m.visible, func(i, j int) bool {
+		a, b := m.processes[m.visible[i]], m.processes[m.visible[j]]
+		if m.descending {
+			a, b = b, a
+		}
+		switch m.sortKey {
+		case SortCPU:
+			return a.CPU < b.CPU
+		case SortMem:
+			return a.Mem < b.Mem
+		case SortPID:
+			return a.PID < b.PID
+		case SortCommand:
+			return a.Command < b.Command
+		}
+		return false
+	})
+
+	m.cursor = clamp(m.cursor, 0, max(0, len(m.visible)-1))
+	m.updateOffset()
+}
+
+// selectPID moves the cursor to the given process, if it's visible.
+func (m *Model) selectPID(pid int) {
+	for i, idx := range m.visible {
+		if m.processes[idx].PID == pid {
+			m.cursor = i
+			m.updateOffset()
+			return
+		}
+	}
+}
+
+// bodyHeight returns the number of rows available for processes.
+func (m Model) bodyHeight() int {
+	h := m.height - 2 // header and status line
+	if m.ShowHelp {
+		h -= lipgloss.Height(m.helpView())
+	}
+	return max(0, h)
+}
+
+// updateOffset keeps the cursor within the visible window.
+func (m *Model) updateOffset() {
+	h := m.bodyHeight()
+	if h == 0 {
+		m.offset = 0
+		return
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+h {
+		m.offset = m.cursor - h + 1
+	}
+	m.offset = clamp(m.offset, 0, max(0, len(m.visible)-h))
+}
+
+func (m *Model) moveCursor(delta int) {
+	m.cursor = clamp(m.cursor+delta, 0, max(0, len(m.visible)-1))
+	m.updateOffset()
+}
+
+func (m *Model) updateKeybindings() {
+	for _, b := range []*key.Binding{
+		&m.KeyMap.CursorUp, &m.KeyMap.CursorDown, &m.KeyMap.PageUp,
+		&m.KeyMap.PageDown, &m.KeyMap.ToggleSelect, &m.KeyMap.CycleSort,
+		&m.KeyMap.ReverseSort, &m.KeyMap.Search, &m.KeyMap.Terminate,
+		&m.KeyMap.Kill,
+	} {
+		b.SetEnabled(!m.searching)
+	}
+	m.KeyMap.ClearSelection.SetEnabled(!m.searching && len(m.selected) > 0)
+	m.KeyMap.AcceptSearch.SetEnabled(m.searching)
+	m.KeyMap.CancelSearch.SetEnabled(m.searching || m.search.Value() != "")
+}
+
+// Init exists to satisfy the tea.Model interface.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if m.searching {
+		m, cmd = m.handleSearching(msg)
+	} else {
+		m, cmd = m.handleBrowsing(msg)
+	}
+	m.updateKeybindings()
+	return m, cmd
+}
+
+func (m Model) handleBrowsing(msg tea.Msg) (Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.KeyMap.CursorUp):
+		m.moveCursor(-1)
+	case key.Matches(keyMsg, m.KeyMap.CursorDown):
+		m.moveCursor(1)
+	case key.Matches(keyMsg, m.KeyMap.PageUp):
+		m.moveCursor(-max(1, m.bodyHeight()))
+	case key.Matches(keyMsg, m.KeyMap.PageDown):
+		m.moveCursor(max(1, m.bodyHeight()))
+	case key.Matches(keyMsg, m.KeyMap.ToggleSelect):
+		m.ToggleSelected()
+		m.moveCursor(1)
+	case key.Matches(keyMsg, m.KeyMap.CycleSort):
+		// Usage columns sort highest first, the others alphabetically.
+		next := (m.sortKey + 1) % (SortCommand + 1)
+		m.SetSort(next, next <= SortMem)
+	case key.Matches(keyMsg, m.KeyMap.ReverseSort):
+		m.SetSort(m.sortKey, !m.descending)
+	case key.Matches(keyMsg, m.KeyMap.CancelSearch):
+		m.SetQuery("")
+	case key.Matches(keyMsg, m.KeyMap.ClearSelection):
+		m.ClearSelection()
+	case key.Matches(keyMsg, m.KeyMap.Search):
+		m.searching = true
+		return m, m.search.Focus()
+	case key.Matches(keyMsg, m.KeyMap.Terminate):
+		return m, m.Signal(syscall.SIGTERM)
+	case key.Matches(keyMsg, m.KeyMap.Kill):
+		return m, m.Signal(syscall.SIGKILL)
+	}
+
+	return m, nil
+}
+
+func (m Model) handleSearching(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.KeyMap.AcceptSearch):
+			m.searching = false
+			m.search.Blur()
+			return m, nil
+		case key.Matches(msg, m.KeyMap.CancelSearch):
+			m.searching = false
+			m.search.Blur()
+			m.SetQuery("")
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	prev := m.search.Value()
+	m.search, cmd = m.search.Update(msg)
+	if m.search.Value() != prev {
+		m.refresh()
+	}
+	return m, cmd
+}
+
+// View renders the component.
+func (m Model) View() string {
+	sparkWidth := max(1, m.HistoryLength)
+	header := fmt.Sprintf("%7s %-10s %6s %6s %-*s %s",
+		m.columnTitle("PID", SortPID),
+		"USER",
+		m.columnTitle("CPU%", SortCPU),
+		m.columnTitle("MEM%", SortMem),
+		sparkWidth, "",
+		m.columnTitle("COMMAND", SortCommand),
+	)
+	lines := []string{m.Styles.Header.Render(m.truncate(header))}
+
+	end := min(len(m.visible), m.offset+m.bodyHeight())
+	for i := m.offset; i < end; i++ {
+		p := m.processes[m.visible[i]]
+
+		mark := " "
+		if m.selected[p.PID] {
+			mark = "*"
+		}
+
+		row := fmt.Sprintf("%s%6d %-10s %6.1f %6.1f ",
+			mark, p.PID, textutil.Truncate(p.User, 10, textutil.Ellipsis), p.CPU, p.Mem) //nolint:mnd
+		spark := m.Styles.Sparkline.Render(fmt.Sprintf("%-*s", sparkWidth, sparkline(m.history[p.PID])))
+		row = m.truncate(row + spark + " " + p.Command)
+
+		style := m.Styles.Row
+		switch {
+		case i == m.cursor:
+			style = m.Styles.Cursor
+		case m.selected[p.PID]:
+			style = m.Styles.Selected
+		}
+		lines = append(lines, style.Render(row))
+	}
+
+	// Pad the body so the status line stays put.
+	for len(lines) < m.bodyHeight()+1 {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, m.statusView())
+	if m.ShowHelp {
+		lines = append(lines, m.helpView())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RenderString renders the process list deterministically at the given size,
+// for golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	m.SetSize(width, height)
+	return render.View(m.View, width, height)
+}
+
+func (m Model) columnTitle(title string, k SortKey) string {
+	if k != m.sortKey {
+		return title
+	}
+	if m.descending {
+		return title + "▼"
+	}
+	return title + "▲"
+}
+
+func (m Model) truncate(s string) string {
+	if m.width <= 0 {
+		return s
+	}
+	return textutil.Truncate(s, m.width, textutil.Ellipsis)
+}
+
+func (m Model) statusView() string {
+	if m.searching {
+		return m.search.View()
+	}
+
+	status := fmt.Sprintf("%d processes", len(m.visible))
+	if n := len(m.selected); n > 0 {
+		status += fmt.Sprintf(" • %d selected", n)
+	}
+	if q := m.search.Value(); q != "" {
+		status += fmt.Sprintf(" • matching %q", q)
+	}
+	return m.Styles.Status.Render(m.truncate(status))
+}
+
+func (m Model) helpView() string {
+	return m.Styles.HelpSection.Render(m.Help.View(m.KeyMap))
+}
+
+// sparkline renders CPU samples as a sparkline. Samples are scaled against
+// 100%, or the highest sample if it's greater, as usage can exceed 100% on
+// multi-core machines.
+func sparkline(samples []float64) string {
+	top := 100.0
+	for _, s := range samples {
+		top = math.Max(top, s)
+	}
+
+	var b strings.Builder
+	for _, s := range samples {
+		i := int(math.Round(math.Max(0, s) / top * float64(len(sparkRunes)-1)))
+		b.WriteRune(sparkRunes[clamp(i, 0, len(sparkRunes)-1)])
+	}
+	return b.String()
+}
+
+func clamp(v, low, high int) int {
+	return min(max(v, low), high)
+}
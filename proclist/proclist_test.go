@@ -0,0 +1,109 @@
+package proclist
+
+import (
+	"reflect"
+	"syscall"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+var testProcesses = []Process{
+	{PID: 1, User: "root", Command: "init", CPU: 0.1, Mem: 0.5},
+	{PID: 42, User: "alice", Command: "vim notes.txt", CPU: 2, Mem: 1.5},
+	{PID: 99, User: "bob", Command: "cargo build", CPU: 180, Mem: 12},
+}
+
+func pids(procs []Process) []int {
+	var pids []int
+	for _, p := range procs {
+		pids = append(pids, p.PID)
+	}
+	return pids
+}
+
+func TestSort(t *testing.T) {
+	m := New()
+	m.SetSize(80, 20)
+	m.SetProcesses(testProcesses)
+
+	if got := pids(m.Processes()); !reflect.DeepEqual(got, []int{99, 42, 1}) {
+		t.Fatalf("expected processes sorted by CPU, got %v", got)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if k, desc := m.Sort(); k != SortPID || desc {
+		t.Fatalf("expected ascending PID sort, got %s (descending: %t)", k, desc)
+	}
+	if got := pids(m.Processes()); !reflect.DeepEqual(got, []int{1, 42, 99}) {
+		t.Fatalf("expected processes sorted by PID, got %v", got)
+	}
+
+	// The cursor follows the process it was on.
+	m.SetSort(SortCPU, true)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.SetSort(SortPID, false)
+	if p, _ := m.Current(); p.PID != 42 {
+		t.Fatalf("expected cursor to stay on PID 42, got %d", p.PID)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	m := New()
+	m.SetProcesses(testProcesses)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ali")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if got := pids(m.Processes()); !reflect.DeepEqual(got, []int{42}) {
+		t.Fatalf("expected search to match alice's process, got %v", got)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if len(m.Processes()) != len(testProcesses) {
+		t.Fatal("expected search to be cleared")
+	}
+}
+
+func TestSignal(t *testing.T) {
+	m := New()
+	m.SetProcesses(testProcesses)
+
+	// Without a selection the process under the cursor is signalled.
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if msg := cmd().(SignalMsg); !reflect.DeepEqual(msg, SignalMsg{PIDs: []int{99}, Signal: syscall.SIGTERM}) {
+		t.Fatalf("unexpected signal message: %+v", msg)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+	if msg := cmd().(SignalMsg); !reflect.DeepEqual(msg, SignalMsg{PIDs: []int{99, 1}, Signal: syscall.SIGKILL}) {
+		t.Fatalf("unexpected signal message: %+v", msg)
+	}
+
+	// Processes that go away are dropped from the selection.
+	m.SetProcesses(testProcesses[1:])
+	if got := m.Selected(); !reflect.DeepEqual(got, []int{99}) {
+		t.Fatalf("expected exited process to be deselected, got %v", got)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline([]float64{0, 50, 100}); got != "▁▅█" {
+		t.Errorf("unexpected sparkline: %q", got)
+	}
+	if got := sparkline([]float64{100, 200}); got != "▅█" {
+		t.Errorf("expected samples over 100%% to rescale, got %q", got)
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	m := New()
+	m.SetProcesses(testProcesses)
+	golden.RequireEqual(t, []byte(RenderString(m, 60, 8)))
+}
@@ -0,0 +1,178 @@
+package progress
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// BytesMsg reports the number of bytes which have passed through a Writer or
+// Reader. Pass its Percent to SetPercent, and run Next to receive the next
+// update:
+//
+//	case progress.BytesMsg:
+//	    cmd := m.progress.SetPercent(msg.Percent())
+//	    return m, tea.Batch(cmd, msg.Next())
+type BytesMsg struct {
+	// ID is the ID of the Writer or Reader sending the message.
+	ID int
+
+	// N is the number of bytes so far, out of Total. Total is zero or less
+	// if it's unknown.
+	N     int64
+	Total int64
+
+	// Done is set once all bytes have passed through, or the Writer or
+	// Reader has been closed. No more messages follow.
+	Done bool
+
+	next tea.Cmd
+}
+
+// Percent returns the portion of the bytes which have passed through, from 0
+// to 1. It's 0 while the total is unknown, until done.
+func (m BytesMsg) Percent() float64 {
+	switch {
+	case m.Total > 0:
+		return min(1, float64(m.N)/float64(m.Total))
+	case m.Done:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Next returns a command waiting for the next update, or nil when done.
+func (m BytesMsg) Next() tea.Cmd {
+	if m.Done {
+		return nil
+	}
+	return m.next
+}
+
+// byteCounter counts the bytes passing through a Writer or Reader, sending
+// updates as they change.
+type byteCounter struct {
+	id      int
+	total   int64
+	n       atomic.Int64
+	changed chan struct{}
+	closed  chan struct{}
+	once    sync.Once
+}
+
+func newByteCounter(total int64) *byteCounter {
+	return &byteCounter{
+		id:      nextID(),
+		total:   total,
+		changed: make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+	}
+}
+
+// ID returns the ID sent with its messages.
+func (c *byteCounter) ID() int {
+	return c.id
+}
+
+// N returns the number of bytes which have passed through.
+func (c *byteCounter) N() int64 {
+	return c.n.Load()
+}
+
+// Close marks the transfer done, such as when it's failed or the total
+// wasn't known, sending a last BytesMsg. It always returns nil.
+func (c *byteCounter) Close() error {
+	c.once.Do(func() {
+		close(c.closed)
+	})
+	return nil
+}
+
+// Cmd returns a command waiting for the bytes passed through to change,
+// returning a BytesMsg. Updates are coalesced, so the messages keep pace with
+// the program rather than with each write. Run it to start receiving updates,
+// then run BytesMsg.Next after each.
+func (c *byteCounter) Cmd() tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case <-c.changed:
+		case <-c.closed:
+		}
+		return c.msg()
+	}
+}
+
+func (c *byteCounter) msg() BytesMsg {
+	n := c.n.Load()
+	done := c.total > 0 && n >= c.total
+	select {
+	case <-c.closed:
+		done = true
+	default:
+	}
+	return BytesMsg{ID: c.id, N: n, Total: c.total, Done: done, next: c.Cmd()}
+}
+
+func (c *byteCounter) add(n int) {
+	if n <= 0 {
+		return
+	}
+	c.n.Add(int64(n))
+	select {
+	case c.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Writer is an io.Writer counting the bytes written to it, to drive a
+// progress bar. Use it with io.MultiWriter or io.TeeReader to follow a copy,
+// such as of a download:
+//
+//	w := progress.NewWriter(resp.ContentLength)
+//	go func() {
+//	    io.Copy(io.MultiWriter(f, w), resp.Body)
+//	    w.Close()
+//	}()
+//	return w.Cmd()
+type Writer struct {
+	*byteCounter
+}
+
+// NewWriter returns a Writer expecting total bytes, or an unknown number if
+// total is zero or less.
+func NewWriter(total int64) *Writer {
+	return &Writer{newByteCounter(total)}
+}
+
+// Write counts the bytes in p. It never fails.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.add(len(p))
+	return len(p), nil
+}
+
+// Reader wraps an io.Reader, counting the bytes read from it to drive a
+// progress bar. See Writer.
+type Reader struct {
+	*byteCounter
+	r io.Reader
+}
+
+// NewReader returns a Reader reading from r, expecting total bytes, or an
+// unknown number if total is zero or less. The transfer is done once total
+// bytes are read, or r returns an error, including io.EOF.
+func NewReader(r io.Reader, total int64) *Reader {
+	return &Reader{byteCounter: newByteCounter(total), r: r}
+}
+
+// Read reads from the wrapped reader, counting the bytes read.
+func (r *Reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.add(n)
+	if err != nil {
+		r.Close() //nolint:errcheck
+	}
+	return n, err
+}
@@ -0,0 +1,99 @@
+package progress
+
+// PercentPosition is where the percentage is drawn relative to the bar.
+type PercentPosition int
+
+// Percentage positions.
+const (
+	// PercentRight draws the percentage after the bar. It's the default.
+	PercentRight PercentPosition = iota
+
+	// PercentLeft draws the percentage before the bar.
+	PercentLeft
+
+	// PercentInside draws the percentage over the middle of the bar, which
+	// then takes the whole width. It's left out if the bar is too narrow.
+	PercentInside
+
+	// PercentHidden hides the percentage, like setting ShowPercentage to
+	// false.
+	PercentHidden
+)
+
+// Braille characters for the bar. Each cell has two columns of dots, which
+// fill from the left, over a track along the bottom row.
+const (
+	brailleFull  = '⣿'
+	brailleHalf  = '⣇'
+	brailleEmpty = '⣀'
+)
+
+// WithFillPatterns sets patterns the full and empty cells of the bar cycle
+// through, character by character, such as "▰" and "▱" or "=-". An empty
+// pattern falls back to the Full or Empty character.
+func WithFillPatterns(full, empty string) Option {
+	return func(m *Model) {
+		m.FullPattern = full
+		m.EmptyPattern = empty
+	}
+}
+
+// WithBraille draws the bar with braille dots. Each cell holds two columns of
+// dots which fill one at a time, so a braille bar moves in steps as fine as
+// a bar of whole cells twice its width, which suits dense dashboards. It
+// takes precedence over the fill characters and patterns.
+func WithBraille() Option {
+	return func(m *Model) {
+		m.Braille = true
+	}
+}
+
+// WithPercentPosition sets where the percentage is drawn.
+func WithPercentPosition(p PercentPosition) Option {
+	return func(m *Model) {
+		m.PercentPosition = p
+	}
+}
+
+// partialCells returns the characters for partly filled cells, in order of
+// how full they are. Each cell has one more step than there are partial
+// cells.
+func (m Model) partialCells() []rune {
+	switch {
+	case m.Braille:
+		return []rune{brailleHalf}
+	case m.SubCells && m.Full == '█' && m.FullPattern == "":
+		return eighthBlocks
+	default:
+		return nil
+	}
+}
+
+// fullCell returns the character for the filled cell at position i.
+func (m Model) fullCell(i int) string {
+	switch {
+	case m.Braille:
+		return string(brailleFull)
+	case m.FullPattern != "":
+		return patternCell(m.FullPattern, i)
+	default:
+		return string(m.Full)
+	}
+}
+
+// emptyCell returns the character for the empty cell at position i.
+func (m Model) emptyCell(i int) string {
+	switch {
+	case m.Braille:
+		return string(brailleEmpty)
+	case m.EmptyPattern != "":
+		return patternCell(m.EmptyPattern, i)
+	default:
+		return string(m.Empty)
+	}
+}
+
+func patternCell(pattern string, i int) string {
+	runes := []rune(pattern)
+	return string(runes[i%len(runes)])
+}
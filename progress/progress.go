@@ -13,6 +13,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/lucasb-eyer/go-colorful"
+	"github.com/mikeflynn/bubbles/render"
 	"github.com/muesli/termenv"
 )
 
@@ -81,6 +82,15 @@ func WithFillCharacters(full rune, empty rune) Option {
 	}
 }
 
+// WithoutSubCells renders the bar in whole cells, for terminals or fonts
+// without good support for the eighth-block characters used for the leading
+// edge of the bar by default. See SubCells.
+func WithoutSubCells() Option {
+	return func(m *Model) {
+		m.SubCells = false
+	}
+}
+
 // WithoutPercentage hides the numeric percentage.
 func WithoutPercentage() Option {
 	return func(m *Model) {
@@ -109,6 +119,12 @@ func WithSpringOptions(frequency, damping float64) Option {
 	}
 }
 
+// WithSpringPreset sets the initial spring options for the progress bar's
+// built-in animation to one of the named presets, such as SpringSnappy.
+func WithSpringPreset(p SpringPreset) Option {
+	return WithSpringOptions(p.Frequency, p.Damping)
+}
+
 // WithColorProfile sets the color profile to use for the progress bar.
 func WithColorProfile(p termenv.Profile) Option {
 	return func(m *Model) {
@@ -116,6 +132,33 @@ func WithColorProfile(p termenv.Profile) Option {
 	}
 }
 
+// SpringPreset is a named set of spring options for the progress bar's
+// animation. Frequency corresponds to speed, and damping to bounciness.
+type SpringPreset struct {
+	Frequency float64
+	Damping   float64
+}
+
+// Spring presets for the progress bar's animation.
+var (
+	// SpringSnappy moves quickly and overshoots the target slightly.
+	SpringSnappy = SpringPreset{Frequency: 30, Damping: 0.75} //nolint:mnd
+
+	// SpringGentle eases slowly into the target without overshooting.
+	SpringGentle = SpringPreset{Frequency: 8, Damping: 1.2} //nolint:mnd
+
+	// SpringCriticallyDamped reaches the target as fast as possible without
+	// overshooting. It's the default.
+	SpringCriticallyDamped = SpringPreset{Frequency: defaultFrequency, Damping: defaultDamping}
+)
+
+// CompleteMsg is sent when the animation comes to rest at 100%. Unlike setting
+// the percentage, it marks when the bar visibly fills, so it can be used to
+// chain actions to the end of the animation.
+type CompleteMsg struct {
+	ID int
+}
+
 // FrameMsg indicates that an animation step should occur.
 type FrameMsg struct {
 	id  int
@@ -142,11 +185,35 @@ type Model struct {
 	Empty      rune
 	EmptyColor string
 
+	// SubCells draws the leading edge of the bar with one of the eighth-block
+	// characters ▏▎▍▌▋▊▉, so that it moves in eighths of a cell rather than
+	// whole cells. It only applies while Full is the full block █.
+	SubCells bool
+
+	// FullPattern and EmptyPattern, if set, are cycled through cell by cell
+	// in place of Full and Empty, such as "▰" and "▱" or "=-". See
+	// WithFillPatterns.
+	FullPattern  string
+	EmptyPattern string
+
+	// Braille draws the bar with braille dots instead of the fill
+	// characters. See WithBraille.
+	Braille bool
+
 	// Settings for rendering the numeric percentage.
 	ShowPercentage  bool
+	PercentPosition PercentPosition
 	PercentFormat   string // a fmt string for a float
 	PercentageStyle lipgloss.Style
 
+	// The state of the operation, and how the bar is drawn in the paused
+	// and error states. See SetState.
+	state       State
+	PausedColor string // if empty, the usual fill is dimmed
+	PausedGlyph string
+	ErrorColor  string
+	ErrorGlyph  string
+
 	// Members for animated transitions.
 	spring           harmonica.Spring
 	springCustomized bool
@@ -177,8 +244,12 @@ func New(opts ...Option) Model {
 		FullColor:      "#7571F9",
 		Empty:          '░',
 		EmptyColor:     "#606060",
+		SubCells:       true,
 		ShowPercentage: true,
 		PercentFormat:  " %3.0f%%",
+		PausedGlyph:    "‖",
+		ErrorColor:     "#ED567A",
+		ErrorGlyph:     "✗",
 		colorProfile:   termenv.ColorProfile(),
 	}
 
@@ -216,10 +287,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// If we've more or less reached equilibrium, stop updating.
 		if !m.IsAnimating() {
-			return m, nil
+			return m, m.complete()
 		}
 
 		m.percentShown, m.velocity = m.spring.Update(m.percentShown, m.velocity, m.targetPercent)
+		if !m.IsAnimating() {
+			return m, m.complete()
+		}
 		return m, m.nextFrame()
 
 	default:
@@ -227,6 +301,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// complete returns a command which sends a CompleteMsg if the bar has come to
+// rest at 100%.
+func (m Model) complete() tea.Cmd {
+	if m.targetPercent < 1 {
+		return nil
+	}
+	id := m.id
+	return func() tea.Msg {
+		return CompleteMsg{ID: id}
+	}
+}
+
+// ID returns the unique ID of the model.
+func (m Model) ID() int {
+	return m.id
+}
+
+// SetSpringPreset sets the spring options to one of the named presets, such as
+// SpringSnappy.
+func (m *Model) SetSpringPreset(p SpringPreset) {
+	m.SetSpringOptions(p.Frequency, p.Damping)
+}
+
 // SetSpringOptions sets the frequency and damping for the current spring.
 // Frequency corresponds to speed, and damping to bounciness. For details see:
 //
@@ -278,69 +375,151 @@ func (m Model) View() string {
 // ViewAs renders the progress bar with a given percentage.
 func (m Model) ViewAs(percent float64) string {
 	b := strings.Builder{}
+	b.WriteString(m.glyphView())
+	glyphWidth := m.glyphWidth()
 	percentView := m.percentageView(percent)
-	m.barView(&b, percent, ansi.StringWidth(percentView))
-	b.WriteString(percentView)
+	switch m.PercentPosition {
+	case PercentLeft:
+		b.WriteString(percentView)
+		m.barView(&b, percent, glyphWidth+ansi.StringWidth(percentView), "")
+	case PercentInside:
+		m.barView(&b, percent, glyphWidth, strings.TrimSpace(m.percentText(percent)))
+	default:
+		m.barView(&b, percent, glyphWidth+ansi.StringWidth(percentView), "")
+		b.WriteString(percentView)
+	}
 	return b.String()
 }
 
+// RenderString renders the progress bar deterministically at the given size,
+// for golden-file tests. The bar is drawn at its target percentage rather than
+// mid-animation, with render.Profile in place of the bar's own color profile.
+// See the render package for details.
+func RenderString(m Model, width, height int) string {
+	m.Width = width
+	m.colorProfile = render.Profile
+	return render.View(func() string { return m.ViewAs(m.Percent()) }, width, height)
+}
+
 func (m *Model) nextFrame() tea.Cmd {
 	return tea.Tick(time.Second/time.Duration(fps), func(time.Time) tea.Msg {
 		return FrameMsg{id: m.id, tag: m.tag}
 	})
 }
 
-func (m Model) barView(b *strings.Builder, percent float64, textWidth int) {
+// eighthBlocks are the characters for one to seven eighths of a cell, used
+// for the leading edge of the bar.
+var eighthBlocks = []rune("▏▎▍▌▋▊▉")
+
+// barView renders the bar, leaving textWidth for the percentage. inside is
+// text drawn over the middle of the bar, if it fits.
+func (m Model) barView(b *strings.Builder, percent float64, textWidth int, inside string) {
 	var (
-		tw = max(0, m.Width-textWidth)                // total width
-		fw = int(math.Round((float64(tw) * percent))) // filled width
+		tw = max(0, m.Width-textWidth) // total width
+		fw int                         // filled width
+		pw int                         // steps filled in the partial cell
 		p  float64
 	)
 
-	fw = max(0, min(tw, fw))
+	partials := m.partialCells()
+	steps := len(partials) + 1
+	filled := int(math.Round(float64(tw) * percent * float64(steps)))
+	filled = max(0, min(tw*steps, filled))
+	fw, pw = filled/steps, filled%steps
+
+	// The partial cell, if any, is drawn as part of the fill.
+	cells := fw
+	if pw > 0 {
+		cells++
+	}
+	fill := func(i int) string {
+		if i == fw {
+			return string(partials[pw-1])
+		}
+		return m.fullCell(i)
+	}
+
+	// Cells covered by the text inside the bar.
+	text := []rune(inside)
+	start := (tw - len(text)) / 2 //nolint:mnd
+	if len(text) > tw {
+		text = nil
+	}
+	over := func(i int) (string, bool) {
+		if i < start || i >= start+len(text) {
+			return "", false
+		}
+		return m.PercentageStyle.Inline(true).Render(string(text[i-start])), true
+	}
 
 	if m.useRamp {
 		// Gradient fill
-		for i := 0; i < fw; i++ {
-			if fw == 1 {
+		for i := 0; i < cells; i++ {
+			if s, ok := over(i); ok {
+				b.WriteString(s)
+				continue
+			}
+			if cells == 1 {
 				// this is up for debate: in a gradient of width=1, should the
 				// single character rendered be the first color, the last color
 				// or exactly 50% in between? I opted for 50%
 				p = 0.5
 			} else if m.scaleRamp {
-				p = float64(i) / float64(fw-1)
+				p = float64(i) / float64(cells-1)
 			} else {
 				p = float64(i) / float64(tw-1)
 			}
-			c := m.rampColorA.BlendLuv(m.rampColorB, p).Hex()
+			c := m.stateColor(m.rampColorA.BlendLuv(m.rampColorB, p).Hex())
 			b.WriteString(termenv.
-				String(string(m.Full)).
+				String(fill(i)).
 				Foreground(m.color(c)).
 				String(),
 			)
 		}
 	} else {
 		// Solid fill
-		s := termenv.String(string(m.Full)).Foreground(m.color(m.FullColor)).String()
-		b.WriteString(strings.Repeat(s, fw))
+		for i := 0; i < cells; i++ {
+			if s, ok := over(i); ok {
+				b.WriteString(s)
+				continue
+			}
+			b.WriteString(termenv.String(fill(i)).Foreground(m.color(m.stateColor(m.FullColor))).String())
+		}
 	}
 
 	// Empty fill
-	e := termenv.String(string(m.Empty)).Foreground(m.color(m.EmptyColor)).String()
-	n := max(0, tw-fw)
-	b.WriteString(strings.Repeat(e, n))
+	for i := cells; i < tw; i++ {
+		if s, ok := over(i); ok {
+			b.WriteString(s)
+			continue
+		}
+		b.WriteString(termenv.String(m.emptyCell(i)).Foreground(m.color(m.EmptyColor)).String())
+	}
 }
 
 func (m Model) percentageView(percent float64) string {
-	if !m.ShowPercentage {
+	percentage := m.percentText(percent)
+	if percentage == "" {
 		return ""
 	}
-	percent = math.Max(0, math.Min(1, percent))
-	percentage := fmt.Sprintf(m.PercentFormat, percent*100) //nolint:mnd
+	if m.PercentPosition == PercentLeft && strings.HasPrefix(percentage, " ") {
+		// Keep the space separating the percentage from the bar between them.
+		percentage = percentage[1:] + " "
+	}
 	percentage = m.PercentageStyle.Inline(true).Render(percentage)
 	return percentage
 }
 
+// percentText returns the percentage formatted with PercentFormat, or
+// nothing if it's hidden.
+func (m Model) percentText(percent float64) string {
+	if !m.ShowPercentage || m.PercentPosition == PercentHidden {
+		return ""
+	}
+	percent = math.Max(0, math.Min(1, percent))
+	return fmt.Sprintf(m.PercentFormat, percent*100) //nolint:mnd
+}
+
 func (m *Model) setRamp(colorA, colorB string, scaled bool) {
 	// In the event of an error colors here will default to black. For
 	// usability's sake, and because such an error is only cosmetic, we're
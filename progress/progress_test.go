@@ -1,9 +1,13 @@
 package progress
 
 import (
+	"fmt"
+	"io"
 	"strings"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/golden"
 	"github.com/muesli/termenv"
 )
 
@@ -64,3 +68,148 @@ func TestGradient(t *testing.T) {
 	}
 
 }
+
+func TestRenderString(t *testing.T) {
+	for _, p := range []float64{0, 0.5, 1} {
+		t.Run(fmt.Sprintf("%.0f percent", p*100), func(t *testing.T) {
+			m := New()
+			m.SetPercent(p)
+			golden.RequireEqual(t, []byte(RenderString(m, 30, 1)))
+		})
+	}
+}
+
+func TestComplete(t *testing.T) {
+	for name, preset := range map[string]SpringPreset{
+		"snappy":           SpringSnappy,
+		"gentle":           SpringGentle,
+		"critical damping": SpringCriticallyDamped,
+	} {
+		t.Run(name, func(t *testing.T) {
+			m := New(WithSpringPreset(preset))
+
+			animate := func(target float64) tea.Cmd {
+				m.SetPercent(target)
+				for range 1000 {
+					model, cmd := m.Update(FrameMsg{id: m.id, tag: m.tag})
+					m = model.(Model)
+					if !m.IsAnimating() {
+						return cmd
+					}
+				}
+				t.Fatal("expected animation to come to rest")
+				return nil
+			}
+
+			if cmd := animate(0.5); cmd != nil {
+				t.Fatalf("expected no message short of 100%%, got %#v", cmd())
+			}
+			cmd := animate(1)
+			if cmd == nil {
+				t.Fatal("expected a CompleteMsg at 100%")
+			}
+			if msg, ok := cmd().(CompleteMsg); !ok || msg.ID != m.ID() {
+				t.Fatalf("expected CompleteMsg, got %#v", cmd())
+			}
+		})
+	}
+}
+
+func TestSubCells(t *testing.T) {
+	for _, tc := range []struct {
+		opts    []Option
+		percent float64
+		want    string
+	}{
+		{nil, 0, "░░░░░░░░░░"},
+		{nil, 0.0125, "▏░░░░░░░░░"},
+		{nil, 0.5, "█████░░░░░"},
+		{nil, 0.55, "█████▌░░░░"},
+		{nil, 0.99, "█████████▉"},
+		{nil, 1, "██████████"},
+		{[]Option{WithoutSubCells()}, 0.55, "██████░░░░"},
+		{[]Option{WithFillCharacters('#', '-')}, 0.55, "######----"},
+	} {
+		opts := append([]Option{WithoutPercentage(), WithWidth(10), WithColorProfile(termenv.Ascii)}, tc.opts...)
+		m := New(opts...)
+		if got := m.ViewAs(tc.percent); got != tc.want {
+			t.Errorf("at %v expected %q, got %q", tc.percent, tc.want, got)
+		}
+	}
+}
+
+func TestGlyphs(t *testing.T) {
+	for _, tc := range []struct {
+		opts    []Option
+		percent float64
+		want    string
+	}{
+		{[]Option{WithFillPatterns("▰", "▱"), WithoutPercentage()}, 0.5, "▰▰▰▰▰▱▱▱▱▱"},
+		{[]Option{WithFillPatterns("=>", "-"), WithoutPercentage()}, 0.55, "=>=>=>----"},
+		{[]Option{WithBraille(), WithoutPercentage()}, 0, "⣀⣀⣀⣀⣀⣀⣀⣀⣀⣀"},
+		{[]Option{WithBraille(), WithoutPercentage()}, 0.55, "⣿⣿⣿⣿⣿⣇⣀⣀⣀⣀"},
+		{[]Option{WithBraille(), WithoutPercentage()}, 1, "⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿"},
+		{[]Option{WithFillCharacters('#', '-'), WithPercentPosition(PercentRight)}, 0.5, "###--  50%"},
+		{[]Option{WithFillCharacters('#', '-'), WithPercentPosition(PercentLeft)}, 0.5, " 50% ###--"},
+		{[]Option{WithFillCharacters('#', '-'), WithPercentPosition(PercentInside)}, 0.5, "###50%----"},
+		{[]Option{WithFillCharacters('#', '-'), WithPercentPosition(PercentHidden)}, 0.5, "#####-----"},
+	} {
+		opts := append([]Option{WithWidth(10), WithColorProfile(termenv.Ascii)}, tc.opts...)
+		m := New(opts...)
+		if got := m.ViewAs(tc.percent); got != tc.want {
+			t.Errorf("at %v expected %q, got %q", tc.percent, tc.want, got)
+		}
+	}
+}
+
+func TestState(t *testing.T) {
+	m := New(WithFillCharacters('#', '-'), WithWidth(12), WithColorProfile(termenv.Ascii))
+	for _, tc := range []struct {
+		state State
+		want  string
+	}{
+		{StateNormal, "####---  50%"},
+		{StatePaused, "‖ ###--  50%"},
+		{StateError, "✗ ###--  50%"},
+	} {
+		m.SetState(tc.state)
+		if got := m.ViewAs(0.5); got != tc.want {
+			t.Errorf("in state %d expected %q, got %q", tc.state, tc.want, got)
+		}
+	}
+
+	m = New(WithColorProfile(termenv.TrueColor), WithSolidFill("#0000FF"), WithoutPercentage(), WithWidth(4))
+	normal := m.ViewAs(1)
+	m.SetState(StatePaused)
+	if paused := m.ViewAs(1); paused == normal || strings.Contains(paused, "0;0;255m") {
+		t.Errorf("expected the paused fill to be dimmed, got %q", paused)
+	}
+	m.SetState(StateError)
+	want := termenv.String("").Foreground(m.color(m.ErrorColor)).String()
+	if failed := m.ViewAs(1); !strings.Contains(failed, strings.TrimSuffix(want, AnsiReset)) {
+		t.Errorf("expected the error fill to be red, got %q", failed)
+	}
+}
+
+func TestBytes(t *testing.T) {
+	w := NewWriter(10)
+	if _, err := w.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	msg := w.Cmd()().(BytesMsg)
+	if msg.ID != w.ID() || msg.N != 5 || msg.Percent() != 0.5 || msg.Done || msg.Next() == nil {
+		t.Fatalf("expected half done, got %+v", msg)
+	}
+	w.Write([]byte("hello, world")) //nolint:errcheck
+	if msg = msg.Next()().(BytesMsg); msg.Percent() != 1 || !msg.Done || msg.Next() != nil {
+		t.Fatalf("expected done, got %+v", msg)
+	}
+
+	r := NewReader(strings.NewReader("hello"), 0)
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Fatal(err)
+	}
+	if msg = r.Cmd()().(BytesMsg); msg.N != 5 || msg.Percent() != 1 || !msg.Done {
+		t.Fatalf("expected the reader to be done at EOF, got %+v", msg)
+	}
+}
@@ -0,0 +1,101 @@
+package progress
+
+import (
+	"github.com/charmbracelet/x/ansi"
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/muesli/termenv"
+)
+
+// State is the state of the operation the bar shows progress for, which
+// changes how it's drawn.
+type State int
+
+// States of the operation.
+const (
+	// StateNormal draws the bar with its usual fill. It's the default.
+	StateNormal State = iota
+
+	// StatePaused dims the fill and marks the bar with PausedGlyph.
+	StatePaused
+
+	// StateError fills the bar with ErrorColor and marks it with
+	// ErrorGlyph.
+	StateError
+)
+
+// pausedDim is how far the fill's colors are blended towards the empty color
+// while paused, from 0 to 1.
+const pausedDim = 0.6
+
+// WithState sets the initial state of the bar. See SetState.
+func WithState(s State) Option {
+	return func(m *Model) {
+		m.state = s
+	}
+}
+
+// WithStateColors sets the fill colors of the paused and error states. An
+// empty paused color dims the usual fill.
+func WithStateColors(paused, failed string) Option {
+	return func(m *Model) {
+		m.PausedColor = paused
+		m.ErrorColor = failed
+	}
+}
+
+// State returns the state of the bar.
+func (m Model) State() State {
+	return m.state
+}
+
+// SetState sets the state of the bar, such as StateError when the operation
+// fails. The percentage is kept, so the bar shows how far it got.
+func (m *Model) SetState(s State) {
+	m.state = s
+}
+
+// stateColor returns the color of a filled cell in the current state, given
+// its usual color.
+func (m Model) stateColor(c string) string {
+	switch m.state {
+	case StatePaused:
+		if m.PausedColor != "" {
+			return m.PausedColor
+		}
+		full, err := colorful.Hex(c)
+		if err != nil {
+			return c
+		}
+		empty, err := colorful.Hex(m.EmptyColor)
+		if err != nil {
+			return c
+		}
+		return full.BlendLuv(empty, pausedDim).Hex()
+	case StateError:
+		return m.ErrorColor
+	default:
+		return c
+	}
+}
+
+// glyphView returns the glyph of the current state, followed by a space
+// separating it from the bar, or nothing in the normal state.
+func (m Model) glyphView() string {
+	var glyph, color string
+	switch m.state {
+	case StatePaused:
+		glyph, color = m.PausedGlyph, m.stateColor(m.FullColor)
+	case StateError:
+		glyph, color = m.ErrorGlyph, m.ErrorColor
+	}
+	if glyph == "" {
+		return ""
+	}
+	return termenv.String(glyph).Foreground(m.color(color)).String() + " "
+}
+
+// glyphWidth returns the width of the glyph of the current state and the
+// space after it.
+func (m Model) glyphWidth() int {
+	return ansi.StringWidth(m.glyphView())
+}
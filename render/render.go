@@ -0,0 +1,59 @@
+// Package render provides deterministic rendering of views to strings. It's
+// used by each bubble's RenderString helper and is intended for golden-file
+// tests, where output must not depend on the terminal the tests run in.
+package render
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/textutil"
+	"github.com/muesli/termenv"
+)
+
+// Profile is the color profile views are rendered with. It defaults to
+// termenv.Ascii, which renders plain text, so golden files only capture
+// layout. Set it to termenv.ANSI256 or termenv.TrueColor to capture styling
+// as well.
+var Profile = termenv.Ascii
+
+// mu serializes rendering, since the color profile is set on the default
+// lipgloss renderer, which is shared.
+var mu sync.Mutex
+
+// View calls view with the default lipgloss renderer set to Profile and a dark
+// background, then fits the result to exactly width by height cells. Lines are
+// truncated or padded with spaces and lines are dropped or blank lines added
+// as needed. If width or height is 0 or less, that dimension is left as is.
+func View(view func() string, width, height int) string {
+	mu.Lock()
+	r := lipgloss.DefaultRenderer()
+	profile, dark := r.ColorProfile(), r.HasDarkBackground()
+	r.SetColorProfile(Profile)
+	r.SetHasDarkBackground(true)
+	s := view()
+	r.SetColorProfile(profile)
+	r.SetHasDarkBackground(dark)
+	mu.Unlock()
+
+	return Fit(s, width, height)
+}
+
+// Fit pads or truncates s to exactly width by height cells. If width or height
+// is 0 or less, that dimension is left as is.
+func Fit(s string, width, height int) string {
+	lines := strings.Split(s, "\n")
+	if height > 0 {
+		for len(lines) < height {
+			lines = append(lines, "")
+		}
+		lines = lines[:height]
+	}
+	if width > 0 {
+		for i, l := range lines {
+			lines[i] = textutil.PadRight(textutil.Truncate(l, width, ""), width)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,23 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestView(t *testing.T) {
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000")).Bold(true)
+	view := func() string { return style.Render("hello") + "\nworld, wide" }
+
+	if got, want := View(view, 8, 3), "hello   \nworld, w\n        "; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	Profile = termenv.ANSI
+	defer func() { Profile = termenv.Ascii }()
+	if got, want := View(view, 0, 0), "\x1b[1;91mhello\x1b[0m\nworld, wide"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
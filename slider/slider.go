@@ -0,0 +1,427 @@
+// Package slider provides a slider for choosing a number from a range in
+// Bubble Tea applications, such as a volume or a threshold. The value is
+// adjusted with the keyboard or the mouse, in steps, and shown with a label
+// and optional tick marks.
+package slider
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/help"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
+)
+
+var lastID int64
+
+func nextID() int {
+	return int(atomic.AddInt64(&lastID, 1))
+}
+
+// ChangedMsg is sent when the user changes the value, either with the
+// keyboard or the mouse.
+type ChangedMsg struct {
+	ID    int
+	Value float64
+}
+
+// FormatFunc formats the value for its label.
+type FormatFunc func(v float64) string
+
+// KeyMap is the key bindings for moving the slider's value. It satisfies the
+// help.KeyMap interface.
+type KeyMap struct {
+	Decrease     key.Binding
+	Increase     key.Binding
+	PageDecrease key.Binding
+	PageIncrease key.Binding
+	Min          key.Binding
+	Max          key.Binding
+}
+
+// ShortHelp implements the KeyMap interface.
+func (km KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{km.Decrease, km.Increase}
+}
+
+// FullHelp implements the KeyMap interface.
+func (km KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{km.Decrease, km.Increase},
+		{km.PageDecrease, km.PageIncrease, km.Min, km.Max},
+	}
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Decrease: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←", "less"),
+		),
+		Increase: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→", "more"),
+		),
+		PageDecrease: key.NewBinding(
+			key.WithKeys("pgdown", "H"),
+			key.WithHelp("pgdn", "much less"),
+		),
+		PageIncrease: key.NewBinding(
+			key.WithKeys("pgup", "L"),
+			key.WithHelp("pgup", "much more"),
+		),
+		Min: key.NewBinding(
+			key.WithKeys("home", "0"),
+			key.WithHelp("home", "min"),
+		),
+		Max: key.NewBinding(
+			key.WithKeys("end", "$"),
+			key.WithHelp("end", "max"),
+		),
+	}
+}
+
+// Styles are the styles of the filled and empty parts of the slider's track,
+// its handle, tick marks and value. DefaultStyles returns the defaults.
+type Styles struct {
+	Filled      lipgloss.Style
+	Empty       lipgloss.Style
+	Handle      lipgloss.Style
+	Tick        lipgloss.Style
+	Value       lipgloss.Style
+	HelpSection lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this
+// component.
+func DefaultStyles() Styles {
+	accent := lipgloss.AdaptiveColor{Light: "#AD58B4", Dark: "#F793FF"}
+	subdued := lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}
+
+	return Styles{
+		Filled:      lipgloss.NewStyle().Foreground(accent),
+		Empty:       lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#DDDADA", Dark: "#3C3C3C"}),
+		Handle:      lipgloss.NewStyle().Foreground(accent).Bold(true),
+		Tick:        lipgloss.NewStyle().Foreground(subdued),
+		Value:       lipgloss.NewStyle(),
+		HelpSection: lipgloss.NewStyle().PaddingTop(1),
+	}
+}
+
+// Icons are the characters used to draw the slider.
+type Icons struct {
+	Filled string
+	Empty  string
+	Handle string
+	Tick   string
+}
+
+// DefaultIcons returns the default set of icons.
+func DefaultIcons() Icons {
+	return Icons{
+		Filled: "━",
+		Empty:  "─",
+		Handle: "●",
+		Tick:   "╵",
+	}
+}
+
+// Model is the state of a slider.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+	Icons  Icons
+	Help   help.Model
+
+	// ShowHelp determines whether the help view is rendered beneath the
+	// slider.
+	ShowHelp bool
+
+	// Width is the width of the slider, including the value's label.
+	Width int
+
+	// Step is the smallest change in value. Values are snapped to steps from
+	// the minimum. If it's 0 or less, values change by a hundredth of the
+	// range.
+	Step float64
+
+	// PageStep is how far the page keys move the value. If it's 0 or less,
+	// they move it by a tenth of the range.
+	PageStep float64
+
+	// Precision is the number of decimal places in the value's label, and
+	// values are rounded to it.
+	Precision int
+
+	// Ticks is the number of intervals marked with ticks under the slider,
+	// such as 4 to mark the quarters. If 0, there are no ticks.
+	Ticks int
+
+	// Format formats the value for its label, such as to add a unit. If nil,
+	// the value is shown with Precision decimal places. If it returns an
+	// empty string, there's no label.
+	Format FormatFunc
+
+	// MouseWheelEnabled sets whether the mouse wheel over the slider changes
+	// the value, by Step.
+	MouseWheelEnabled bool
+
+	// X and Y are the position of the component on screen. They're used to
+	// work out what mouse events refer to.
+	X, Y int
+
+	id       int
+	min      float64
+	max      float64
+	value    float64
+	dragging bool
+}
+
+// New returns a slider for values from minimum to maximum, set to the
+// minimum.
+func New(minimum, maximum float64) Model {
+	if maximum < minimum {
+		minimum, maximum = maximum, minimum
+	}
+	return Model{
+		KeyMap:            DefaultKeyMap(),
+		Styles:            DefaultStyles(),
+		Icons:             DefaultIcons(),
+		Help:              help.New(),
+		ShowHelp:          true,
+		Width:             40, //nolint:mnd
+		MouseWheelEnabled: true,
+		id:                nextID(),
+		min:               minimum,
+		max:               maximum,
+		value:             minimum,
+	}
+}
+
+// ID returns the unique ID of the model.
+func (m Model) ID() int {
+	return m.id
+}
+
+// Value returns the value.
+func (m Model) Value() float64 {
+	return m.value
+}
+
+// Range returns the minimum and maximum values.
+func (m Model) Range() (minimum, maximum float64) {
+	return m.min, m.max
+}
+
+// SetRange sets the minimum and maximum values, clamping the value to them.
+func (m *Model) SetRange(minimum, maximum float64) {
+	if maximum < minimum {
+		minimum, maximum = maximum, minimum
+	}
+	m.min, m.max = minimum, maximum
+	m.value = m.snap(m.value)
+}
+
+// SetValue sets the value, snapped to a step and clamped to the range,
+// without sending a ChangedMsg. Use it to keep the slider in sync with what
+// it controls.
+func (m *Model) SetValue(v float64) {
+	m.value = m.snap(v)
+}
+
+// Percent returns how far the value is through the range, from 0 to 1.
+func (m Model) Percent() float64 {
+	if m.max <= m.min {
+		return 0
+	}
+	return (m.value - m.min) / (m.max - m.min)
+}
+
+// change sets the value, returning a command which sends a ChangedMsg if it
+// changed.
+func (m *Model) change(v float64) tea.Cmd {
+	v = m.snap(v)
+	if v == m.value {
+		return nil
+	}
+	m.value = v
+	id := m.id
+	return func() tea.Msg {
+		return ChangedMsg{ID: id, Value: v}
+	}
+}
+
+// step returns the smallest change in value.
+func (m Model) step() float64 {
+	if m.Step > 0 {
+		return m.Step
+	}
+	return (m.max - m.min) / 100 //nolint:mnd
+}
+
+// pageStep returns how far the page keys move the value.
+func (m Model) pageStep() float64 {
+	if m.PageStep > 0 {
+		return m.PageStep
+	}
+	return (m.max - m.min) / 10 //nolint:mnd
+}
+
+// snap clamps a value to the range and snaps it to a step and to Precision.
+func (m Model) snap(v float64) float64 {
+	v = clamp(v, m.min, m.max)
+	if m.Step > 0 {
+		v = m.min + math.Round((v-m.min)/m.Step)*m.Step
+		if v > m.max {
+			v -= m.Step
+		}
+	}
+	scale := math.Pow(10, float64(max(0, m.Precision))) //nolint:mnd
+	return clamp(math.Round(v*scale)/scale, m.min, m.max)
+}
+
+// Init exists to satisfy the tea.Model interface.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.Decrease):
+			return m, m.change(m.value - m.step())
+		case key.Matches(msg, m.KeyMap.Increase):
+			return m, m.change(m.value + m.step())
+		case key.Matches(msg, m.KeyMap.PageDecrease):
+			return m, m.change(m.value - m.pageStep())
+		case key.Matches(msg, m.KeyMap.PageIncrease):
+			return m, m.change(m.value + m.pageStep())
+		case key.Matches(msg, m.KeyMap.Min):
+			return m, m.change(m.min)
+		case key.Matches(msg, m.KeyMap.Max):
+			return m, m.change(m.max)
+		}
+
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
+	}
+
+	return m, nil
+}
+
+func (m Model) updateMouse(msg tea.MouseMsg) (Model, tea.Cmd) {
+	if msg.Action == tea.MouseActionRelease {
+		m.dragging = false
+		return m, nil
+	}
+
+	width := m.trackWidth()
+	x := msg.X - m.X
+	onTrack := msg.Y == m.Y && x >= 0 && x < width
+
+	switch {
+	case m.dragging && msg.Action == tea.MouseActionMotion:
+		return m, m.change(m.valueAt(x, width))
+	case !onTrack || msg.Action != tea.MouseActionPress:
+		return m, nil
+	case msg.Button == tea.MouseButtonWheelUp || msg.Button == tea.MouseButtonWheelRight:
+		if m.MouseWheelEnabled {
+			return m, m.change(m.value + m.step())
+		}
+	case msg.Button == tea.MouseButtonWheelDown || msg.Button == tea.MouseButtonWheelLeft:
+		if m.MouseWheelEnabled {
+			return m, m.change(m.value - m.step())
+		}
+	case msg.Button == tea.MouseButtonLeft:
+		m.dragging = true
+		return m, m.change(m.valueAt(x, width))
+	}
+	return m, nil
+}
+
+// valueAt returns the value for the given cell of the track.
+func (m Model) valueAt(x, width int) float64 {
+	if width <= 1 {
+		return m.min
+	}
+	x = clamp(x, 0, width-1)
+	return m.min + (m.max-m.min)*float64(x)/float64(width-1)
+}
+
+// label returns the formatted value.
+func (m Model) label(v float64) string {
+	if m.Format != nil {
+		return m.Format(v)
+	}
+	return strconv.FormatFloat(v, 'f', max(0, m.Precision), 64)
+}
+
+// labelWidth returns the width of the value's label, wide enough for any
+// value so that the track doesn't change width as the value changes.
+func (m Model) labelWidth() int {
+	return max(lipgloss.Width(m.label(m.min)), lipgloss.Width(m.label(m.max)), lipgloss.Width(m.label(m.value)))
+}
+
+// trackWidth returns the width of the track.
+func (m Model) trackWidth() int {
+	if w := m.labelWidth(); w > 0 {
+		return max(1, m.Width-w-1)
+	}
+	return max(1, m.Width)
+}
+
+// View renders the component.
+func (m Model) View() string {
+	width := m.trackWidth()
+	view := m.trackView(width)
+	if label := m.label(m.value); label != "" {
+		view += " " + m.Styles.Value.Render(lipgloss.PlaceHorizontal(m.labelWidth(), lipgloss.Right, label))
+	}
+	if m.Ticks > 0 {
+		view += "\n" + m.ticksView(width)
+	}
+
+	if !m.ShowHelp {
+		return view
+	}
+	m.Help.Width = m.Width
+	return view + "\n" + m.Styles.HelpSection.Render(m.Help.View(m.KeyMap))
+}
+
+// RenderString renders the slider deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	m.Width = width
+	return render.View(m.View, width, height)
+}
+
+func (m Model) trackView(width int) string {
+	handle := clamp(int(math.Round(float64(width-1)*m.Percent())), 0, width-1)
+	return m.Styles.Filled.Render(strings.Repeat(m.Icons.Filled, handle)) +
+		m.Styles.Handle.Render(m.Icons.Handle) +
+		m.Styles.Empty.Render(strings.Repeat(m.Icons.Empty, width-handle-1))
+}
+
+func (m Model) ticksView(width int) string {
+	cells := []rune(strings.Repeat(" ", width))
+	tick := []rune(m.Icons.Tick)
+	if len(tick) == 0 {
+		return ""
+	}
+	for i := 0; i <= m.Ticks; i++ {
+		cells[clamp(int(math.Round(float64(i)*float64(width-1)/float64(m.Ticks))), 0, width-1)] = tick[0]
+	}
+	return m.Styles.Tick.Render(string(cells))
+}
+
+func clamp[T int | float64](v, low, high T) T {
+	return min(max(v, low), high)
+}
@@ -0,0 +1,81 @@
+package slider
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+func TestKeys(t *testing.T) {
+	m := New(0, 1)
+	m.Step = 0.1
+	m.Precision = 1
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if msg, ok := cmd().(ChangedMsg); !ok || msg.Value != 0.1 || msg.ID != m.ID() {
+		t.Fatalf("expected ChangedMsg at 0.1, got %#v", cmd())
+	}
+	for range 3 {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	}
+	if m.Value() != 0.4 {
+		t.Fatalf("expected steps to add up without rounding errors, got %v", m.Value())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	if m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRight}); m.Value() != 1 || cmd != nil {
+		t.Fatalf("expected the value to stay at the maximum without a message, got %v", m.Value())
+	}
+
+	// SetValue snaps to a step without sending a message.
+	m.SetValue(0.33)
+	if m.Value() != 0.3 {
+		t.Fatalf("expected the value to snap to 0.3, got %v", m.Value())
+	}
+	m.SetRange(0, 0.2)
+	if m.Value() != 0.2 {
+		t.Fatalf("expected the value to be clamped to the new range, got %v", m.Value())
+	}
+}
+
+func TestMouse(t *testing.T) {
+	m := New(0, 100)
+	m.Width = 24
+	m.X, m.Y = 10, 5
+	m.ShowHelp = false
+
+	width := m.trackWidth()
+	click := func(x int, action tea.MouseAction) tea.MouseMsg {
+		return tea.MouseMsg{X: m.X + x, Y: m.Y, Button: tea.MouseButtonLeft, Action: action}
+	}
+
+	m, _ = m.Update(click(width-1, tea.MouseActionPress))
+	if m.Value() != 100 {
+		t.Fatalf("expected a click at the end to set the maximum, got %v", m.Value())
+	}
+	m, _ = m.Update(click(-5, tea.MouseActionMotion))
+	if m.Value() != 0 {
+		t.Fatalf("expected dragging to follow, got %v", m.Value())
+	}
+	m, _ = m.Update(click(0, tea.MouseActionRelease))
+
+	m, _ = m.Update(tea.MouseMsg{X: m.X + 3, Y: m.Y, Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress})
+	if m.Value() != 1 {
+		t.Fatalf("expected the wheel to move by a step, got %v", m.Value())
+	}
+	m, _ = m.Update(tea.MouseMsg{X: m.X + 3, Y: m.Y + 1, Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress})
+	if m.Value() != 1 {
+		t.Fatalf("expected the wheel off the slider to be ignored, got %v", m.Value())
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	m := New(0, 100)
+	m.Step = 5
+	m.Ticks = 4
+	m.Format = func(v float64) string { return fmt.Sprintf("%.0f%%", v) }
+	m.SetValue(50)
+	golden.RequireEqual(t, []byte(RenderString(m, 30, 4)))
+}
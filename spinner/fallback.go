@@ -0,0 +1,175 @@
+package spinner
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mikeflynn/bubbles/textutil"
+)
+
+// ReducedMotionEnv is the environment variable which turns on ReducedMotion
+// for new spinners when it's set to anything other than "", "0" or "false".
+const ReducedMotionEnv = "REDUCED_MOTION"
+
+// GlyphMode is whether a spinner draws its frames as they are or swaps them
+// for ASCII equivalents.
+type GlyphMode int
+
+// Glyph modes.
+const (
+	// GlyphsUnicode draws the frames as they are. It's the default.
+	GlyphsUnicode GlyphMode = iota
+
+	// GlyphsASCII draws ASCII equivalents of the frames. See ASCII.
+	GlyphsASCII
+
+	// GlyphsAuto draws ASCII equivalents when the environment suggests the
+	// terminal can't show Unicode, which is the case when the locale isn't
+	// UTF-8 or TERM is "linux" or "dumb". The environment is checked once,
+	// by New.
+	GlyphsAuto
+)
+
+// Static indicators shown in place of the spinner with ReducedMotion.
+const (
+	defaultStatic      = "…"
+	defaultASCIIStatic = "..."
+)
+
+// Some ASCII spinners standing in for the ones above.
+var (
+	asciiPulse = []string{"#", "=", "-", "."}
+	asciiPoint = []string{"...", "o..", ".o.", "..o"}
+	asciiMeter = []string{"---", "=--", "==-", "===", "==-", "=--", "---"}
+)
+
+// ASCII returns a spinner with the same speed as s whose frames are ASCII.
+// The spinners in this package have their own equivalents, and other spinners
+// with frames outside of ASCII turn into Line. Frames are padded to the width
+// of the originals so that the text around the spinner doesn't move when
+// switching between them.
+func ASCII(s Spinner) Spinner {
+	if isASCII(s.Frames) {
+		return s
+	}
+
+	frames := Line.Frames
+	switch {
+	case slices.Equal(s.Frames, Pulse.Frames):
+		frames = asciiPulse
+	case slices.Equal(s.Frames, Points.Frames):
+		frames = asciiPoint
+	case slices.Equal(s.Frames, Meter.Frames):
+		frames = asciiMeter
+	}
+
+	var width int
+	for _, f := range s.Frames {
+		width = max(width, textutil.Width(f))
+	}
+	padded := make([]string, len(frames))
+	for i, f := range frames {
+		padded[i] = textutil.PadRight(f, width)
+	}
+	return Spinner{Frames: padded, FPS: s.FPS}
+}
+
+func isASCII(frames []string) bool {
+	for _, f := range frames {
+		for i := range len(f) {
+			if f[i] >= utf8.RuneSelf {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// UnicodeSupported reports whether the environment suggests the terminal can
+// show Unicode: the locale, from LC_ALL, LC_CTYPE or LANG, must be UTF-8, and
+// TERM mustn't be "linux" or "dumb", as the Linux console's font lacks most
+// of the glyphs used by spinners.
+func UnicodeSupported() bool {
+	switch os.Getenv("TERM") {
+	case "linux", "dumb":
+		return false
+	}
+	for _, v := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(v); locale != "" {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return false
+}
+
+// reducedMotionFromEnv reports whether ReducedMotionEnv asks for reduced
+// motion.
+func reducedMotionFromEnv() bool {
+	switch strings.ToLower(os.Getenv(ReducedMotionEnv)) {
+	case "", "0", "false":
+		return false
+	}
+	return true
+}
+
+// ascii reports whether the spinner should draw ASCII frames.
+func (m Model) ascii() bool {
+	switch m.Glyphs {
+	case GlyphsASCII:
+		return true
+	case GlyphsAuto:
+		return m.autoASCII
+	}
+	return false
+}
+
+// frames returns the frames to draw, according to Glyphs.
+func (m Model) frames() []string {
+	switch {
+	case !m.ascii():
+		return m.Spinner.Frames
+	case slices.Equal(m.asciiSource, m.Spinner.Frames):
+		return m.asciiFrames
+	}
+	return ASCII(m.Spinner).Frames
+}
+
+// cacheASCII works out the ASCII frames to draw, if they're drawn and the
+// spinner changed since they were last worked out.
+func (m *Model) cacheASCII() {
+	if m.ascii() && !slices.Equal(m.asciiSource, m.Spinner.Frames) {
+		m.asciiSource = m.Spinner.Frames
+		m.asciiFrames = ASCII(m.Spinner).Frames
+	}
+}
+
+// staticView returns the indicator drawn instead of the spinner with
+// ReducedMotion.
+func (m Model) staticView() string {
+	switch {
+	case m.Static != "" && (!m.ascii() || isASCII([]string{m.Static})):
+		return m.Static
+	case m.ascii():
+		return defaultASCIIStatic
+	}
+	return defaultStatic
+}
+
+// WithGlyphs is an option to set whether the spinner swaps its frames for ASCII
+// equivalents.
+func WithGlyphs(mode GlyphMode) Option {
+	return func(m *Model) {
+		m.Glyphs = mode
+	}
+}
+
+// WithReducedMotion is an option to draw a static indicator instead of
+// animating, whatever ReducedMotionEnv is set to.
+func WithReducedMotion(v bool) Option {
+	return func(m *Model) {
+		m.ReducedMotion = v
+	}
+}
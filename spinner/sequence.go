@@ -0,0 +1,258 @@
+package spinner
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/render"
+	"github.com/mikeflynn/bubbles/textutil"
+)
+
+// ErrStepFailed is the error of a sequence whose step was failed without an
+// error, with Fail(nil).
+var ErrStepFailed = errors.New("spinner: step failed")
+
+// StepState is the state of a step in a sequence.
+type StepState int
+
+// Step states.
+const (
+	StepPending StepState = iota
+	StepRunning
+	StepDone
+	StepFailed
+)
+
+// Step is a step in a sequence.
+type Step struct {
+	Title string
+	State StepState
+
+	// Err is the error the step failed with, if it failed.
+	Err error
+}
+
+// StepDoneMsg completes the running step of the sequence with the given ID
+// and starts the next one. See SequenceModel.Next.
+type StepDoneMsg struct {
+	ID int
+}
+
+// StepFailedMsg fails the running step of the sequence with the given ID,
+// stopping the sequence. See SequenceModel.Fail.
+type StepFailedMsg struct {
+	ID  int
+	Err error
+}
+
+// SequenceDoneMsg is sent when a sequence finishes, either because its last
+// step completed or because a step failed, in which case Err is the step's
+// error.
+type SequenceDoneMsg struct {
+	ID  int
+	Err error
+}
+
+// SequenceModel is a checklist of steps run one after another, as seen in
+// installers and deploy tools: the running step is drawn with the spinner,
+// completed steps with a check and failed ones with a cross and their error.
+//
+// Steps are advanced by messages, so the work of a step can be done in a
+// command which returns the sequence's Next or Fail message when it's done:
+//
+//	func install(seq spinner.SequenceModel) tea.Cmd {
+//	    return func() tea.Msg {
+//	        if err := runInstall(); err != nil {
+//	            return seq.Fail(err)
+//	        }
+//	        return seq.Next()
+//	    }
+//	}
+type SequenceModel struct {
+	Model
+
+	// DoneMark, FailedMark and PendingMark are drawn in place of the
+	// spinner for steps which aren't running. They're swapped for ASCII
+	// equivalents along with the spinner's frames.
+	DoneMark    string
+	FailedMark  string
+	PendingMark string
+
+	// DoneStyle and FailedStyle style the marks of completed and failed
+	// steps, and PendingStyle the whole line of a pending step.
+	DoneStyle    lipgloss.Style
+	FailedStyle  lipgloss.Style
+	PendingStyle lipgloss.Style
+
+	// ErrorStyle styles the error shown under a failed step.
+	ErrorStyle lipgloss.Style
+
+	steps   []Step
+	current int
+	err     error
+}
+
+// NewSequence returns a sequence of steps with the given titles, the first of
+// which is running. The options are the same as for New. Start the spinner
+// with Tick.
+func NewSequence(titles []string, opts ...Option) SequenceModel {
+	m := SequenceModel{
+		Model:       New(opts...),
+		DoneMark:    "✓",
+		FailedMark:  "✗",
+		PendingMark: "•",
+		DoneStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "#00A66D", Dark: "#04B575"}),
+		FailedStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "#FF4672", Dark: "#ED567A"}),
+		PendingStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}),
+		ErrorStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "#FF4672", Dark: "#ED567A"}),
+	}
+	m.steps = make([]Step, len(titles))
+	for i, t := range titles {
+		m.steps[i].Title = t
+	}
+	if len(m.steps) > 0 {
+		m.steps[0].State = StepRunning
+	}
+	return m
+}
+
+// Next returns the message completing the running step and starting the
+// next one. It can be used as a command itself.
+func (m SequenceModel) Next() tea.Msg {
+	return StepDoneMsg{ID: m.id}
+}
+
+// Fail returns the message failing the running step with the given error,
+// which stops the sequence. If err is nil, the sequence fails with
+// ErrStepFailed and no error is shown under the step.
+func (m SequenceModel) Fail(err error) tea.Msg {
+	return StepFailedMsg{ID: m.id, Err: err}
+}
+
+// Steps returns the steps of the sequence.
+func (m SequenceModel) Steps() []Step {
+	return slices.Clone(m.steps)
+}
+
+// Current returns the index of the running step, or of the failed step if
+// the sequence failed. It returns the number of steps once they've all
+// completed.
+func (m SequenceModel) Current() int {
+	return m.current
+}
+
+// Finished returns whether the sequence has finished, because its steps all
+// completed or one of them failed.
+func (m SequenceModel) Finished() bool {
+	return m.err != nil || m.current >= len(m.steps)
+}
+
+// Err returns the error of the failed step, or nil if no step failed.
+func (m SequenceModel) Err() error {
+	return m.err
+}
+
+// Update is the Tea update function. The spinner stops once the sequence has
+// finished.
+func (m SequenceModel) Update(msg tea.Msg) (SequenceModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case StepDoneMsg:
+		if msg.ID != m.id || m.Finished() {
+			return m, nil
+		}
+		m.steps = slices.Clone(m.steps)
+		m.steps[m.current].State = StepDone
+		m.current++
+		if m.current < len(m.steps) {
+			m.steps[m.current].State = StepRunning
+			return m, nil
+		}
+		return m, m.doneCmd()
+
+	case StepFailedMsg:
+		if msg.ID != m.id || m.Finished() {
+			return m, nil
+		}
+		m.steps = slices.Clone(m.steps)
+		m.steps[m.current].State = StepFailed
+		m.steps[m.current].Err = msg.Err
+		m.err = msg.Err
+		if m.err == nil {
+			m.err = ErrStepFailed
+		}
+		return m, m.doneCmd()
+
+	case TickMsg:
+		if m.Finished() && msg.ID == m.id {
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.Model, cmd = m.Model.Update(msg)
+	return m, cmd
+}
+
+// View renders the steps, one per line, with the error of a failed step
+// under it. Titles are lined up whatever the width of the spinner.
+func (m SequenceModel) View() string {
+	spin := m.Model.View()
+	marks := map[StepState]string{
+		StepDone:    m.mark(m.DoneMark, "+"),
+		StepFailed:  m.mark(m.FailedMark, "x"),
+		StepPending: m.mark(m.PendingMark, "-"),
+	}
+	width := textutil.Width(spin)
+	for _, mark := range marks {
+		width = max(width, textutil.Width(mark))
+	}
+	indent := strings.Repeat(" ", width+1)
+
+	lines := make([]string, 0, len(m.steps))
+	for _, s := range m.steps {
+		mark := textutil.PadRight(marks[s.State], width)
+		switch s.State {
+		case StepRunning:
+			lines = append(lines, textutil.PadRight(spin, width)+" "+s.Title)
+		case StepDone:
+			lines = append(lines, m.DoneStyle.Render(mark)+" "+s.Title)
+		case StepFailed:
+			lines = append(lines, m.FailedStyle.Render(mark)+" "+s.Title)
+			if s.Err != nil {
+				lines = append(lines, indent+m.ErrorStyle.Render(s.Err.Error()))
+			}
+		default:
+			lines = append(lines, m.PendingStyle.Render(mark+" "+s.Title))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RenderSequenceString renders the steps of a sequence deterministically at
+// the given size, for golden-file tests. See the render package for details.
+func RenderSequenceString(m SequenceModel, width, height int) string {
+	return render.View(m.View, width, height)
+}
+
+// mark returns a mark to draw, or the given ASCII equivalent if the spinner
+// draws ASCII frames and the mark isn't ASCII.
+func (m SequenceModel) mark(mark, ascii string) string {
+	if m.ascii() && !isASCII([]string{mark}) {
+		return ascii
+	}
+	return mark
+}
+
+func (m SequenceModel) doneCmd() tea.Cmd {
+	id, err := m.id, m.err
+	return func() tea.Msg {
+		return SequenceDoneMsg{ID: id, Err: err}
+	}
+}
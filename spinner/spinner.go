@@ -7,6 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/render"
 )
 
 // Internal ID management. Used during animating to ensure that frame messages
@@ -96,9 +97,31 @@ type Model struct {
 	// https://github.com/charmbracelet/lipgloss
 	Style lipgloss.Style
 
+	// Glyphs sets whether the frames are swapped for ASCII equivalents, for
+	// terminals without the glyphs most spinners use. GlyphsAuto goes by the
+	// environment when the spinner was created with New.
+	Glyphs GlyphMode
+
+	// ReducedMotion draws Static instead of animating, for users who find
+	// motion distracting. Ticks are dropped while it's set, so the spinner
+	// must be started again with Tick if it's turned off later. New sets it
+	// from ReducedMotionEnv.
+	ReducedMotion bool
+
+	// Static is the indicator drawn with ReducedMotion. If it's empty, or
+	// isn't ASCII when ASCII frames are drawn, an ellipsis is drawn.
+	Static string
+
 	frame int
 	id    int
 	tag   int
+
+	// autoASCII is whether GlyphsAuto draws ASCII frames, and asciiFrames
+	// the ASCII equivalents of the frames in asciiSource, so they aren't
+	// worked out on every frame.
+	autoASCII   bool
+	asciiSource []string
+	asciiFrames []string
 }
 
 // ID returns the spinner's unique ID.
@@ -109,13 +132,16 @@ func (m Model) ID() int {
 // New returns a model with default values.
 func New(opts ...Option) Model {
 	m := Model{
-		Spinner: Line,
-		id:      nextID(),
+		Spinner:       Line,
+		ReducedMotion: reducedMotionFromEnv(),
+		id:            nextID(),
+		autoASCII:     !UnicodeSupported(),
 	}
 
 	for _, opt := range opts {
 		opt(&m)
 	}
+	m.cacheASCII()
 
 	return m
 }
@@ -149,12 +175,17 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.ReducedMotion {
+			return m, nil
+		}
+
 		m.frame++
 		if m.frame >= len(m.Spinner.Frames) {
 			m.frame = 0
 		}
 
 		m.tag++
+		m.cacheASCII()
 		return m, m.tick(m.id, m.tag)
 	default:
 		return m, nil
@@ -163,11 +194,23 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 // View renders the model's view.
 func (m Model) View() string {
-	if m.frame >= len(m.Spinner.Frames) {
+	if m.ReducedMotion {
+		return m.Style.Render(m.staticView())
+	}
+
+	// ASCII equivalents may have fewer frames than the spinner.
+	frames := m.frames()
+	if m.frame >= len(m.Spinner.Frames) || len(frames) == 0 {
 		return "(error)"
 	}
 
-	return m.Style.Render(m.Spinner.Frames[m.frame])
+	return m.Style.Render(frames[m.frame%len(frames)])
+}
+
+// RenderString renders the spinner's current frame deterministically at the
+// given size, for golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	return render.View(m.View, width, height)
 }
 
 // Tick is the command used to advance the spinner one frame. Use this command
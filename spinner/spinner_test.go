@@ -1,11 +1,21 @@
 package spinner_test
 
 import (
+	"errors"
+	"os"
+	"strings"
 	"testing"
 
+	"github.com/charmbracelet/x/exp/golden"
 	"github.com/mikeflynn/bubbles/spinner"
 )
 
+func TestMain(m *testing.M) {
+	// Spinners animate unless the environment asks for reduced motion.
+	os.Unsetenv(spinner.ReducedMotionEnv) //nolint:errcheck
+	os.Exit(m.Run())
+}
+
 func TestSpinnerNew(t *testing.T) {
 	assertEqualSpinner := func(t *testing.T, exp, got spinner.Spinner) {
 		t.Helper()
@@ -59,3 +69,181 @@ func TestSpinnerNew(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderString(t *testing.T) {
+	t.Run("line", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(spinner.RenderString(spinner.New(), 2, 1)))
+	})
+	t.Run("dot", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(spinner.RenderString(spinner.New(spinner.WithSpinner(spinner.Dot)), 2, 1)))
+	})
+}
+
+func TestStatus(t *testing.T) {
+	m := spinner.NewStatus()
+	m.HistorySize = 3
+	for _, s := range []string{"Building", "Pushing image", "Migrating", "Deploying"} {
+		m.SetStatus(s)
+	}
+
+	if got := m.Status(); got != "Deploying" {
+		t.Fatalf("expected the latest status, got %q", got)
+	}
+	if got := strings.Join(m.History(), ","); got != "Pushing image,Migrating,Deploying" {
+		t.Fatalf("expected the oldest status to be dropped, got %q", got)
+	}
+
+	t.Run("collapsed", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(spinner.RenderStatusString(m, 20, 1)))
+	})
+	m.SetExpanded(true)
+	t.Run("expanded", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(spinner.RenderStatusString(m, 20, 3)))
+	})
+}
+
+func TestASCII(t *testing.T) {
+	for _, s := range []spinner.Spinner{spinner.Dot, spinner.Globe, spinner.Points, spinner.Meter, spinner.Line} {
+		a := spinner.ASCII(s)
+		if a.FPS != s.FPS {
+			t.Errorf("expected the FPS to be kept, got %v", a.FPS)
+		}
+		for i, f := range a.Frames {
+			for _, r := range f {
+				if r > 127 {
+					t.Fatalf("expected ASCII frames, got %q", f)
+				}
+			}
+			if len(f) != len(a.Frames[0]) {
+				t.Errorf("expected frame %d of %v to be as wide as the first, got %q", i, a.Frames, f)
+			}
+		}
+	}
+	if got := spinner.ASCII(spinner.Globe).Frames[0]; got != "| " {
+		t.Errorf("expected the frame to be padded to the width of an emoji, got %q", got)
+	}
+
+	m := spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithGlyphs(spinner.GlyphsASCII))
+	for range 7 {
+		m, _ = m.Update(m.Tick())
+	}
+	if got := m.View(); got != "\\ " {
+		t.Errorf("expected the frame to wrap around the ASCII frames, got %q", got)
+	}
+
+	t.Setenv("TERM", "xterm")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_CTYPE", "")
+	t.Setenv("LANG", "C")
+	auto := spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithGlyphs(spinner.GlyphsAuto))
+	if spinner.UnicodeSupported() || auto.View() != "| " {
+		t.Error("expected ASCII frames in the C locale")
+	}
+
+	// The environment is checked when the spinner is created.
+	t.Setenv("LANG", "en_US.UTF-8")
+	if auto.View() != "| " {
+		t.Error("expected the glyphs to be kept when the environment changes")
+	}
+	auto = spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithGlyphs(spinner.GlyphsAuto))
+	if !spinner.UnicodeSupported() || auto.View() != spinner.Dot.Frames[0] {
+		t.Error("expected Unicode frames in a UTF-8 locale")
+	}
+
+	// Changing the spinner changes the ASCII frames.
+	m = spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithGlyphs(spinner.GlyphsASCII))
+	m.Spinner = spinner.Points
+	m, _ = m.Update(m.Tick())
+	if got := m.View(); got != "o.." {
+		t.Errorf("expected the ASCII frames of the new spinner, got %q", got)
+	}
+}
+
+func TestReducedMotion(t *testing.T) {
+	t.Setenv(spinner.ReducedMotionEnv, "1")
+	m := spinner.New()
+	if !m.ReducedMotion {
+		t.Fatal("expected reduced motion to be set from the environment")
+	}
+	m, cmd := m.Update(m.Tick())
+	if cmd != nil {
+		t.Error("expected the spinner to stop ticking")
+	}
+	if got := m.View(); got != "…" {
+		t.Errorf("expected the static indicator, got %q", got)
+	}
+
+	m.Glyphs = spinner.GlyphsASCII
+	if got := m.View(); got != "..." {
+		t.Errorf("expected the ASCII static indicator, got %q", got)
+	}
+	m.Static = "*"
+	if got := m.View(); got != "*" {
+		t.Errorf("expected the custom static indicator, got %q", got)
+	}
+
+	t.Setenv(spinner.ReducedMotionEnv, "false")
+	if spinner.New().ReducedMotion {
+		t.Error("expected reduced motion to be off")
+	}
+	if !spinner.New(spinner.WithReducedMotion(true)).ReducedMotion {
+		t.Error("expected the option to turn on reduced motion")
+	}
+}
+
+func TestSequence(t *testing.T) {
+	m := spinner.NewSequence([]string{"Download", "Verify", "Install"}, spinner.WithSpinner(spinner.Dot))
+	m, cmd := m.Update(m.Next())
+	if cmd != nil || m.Current() != 1 || m.Finished() {
+		t.Fatalf("expected the second step to run, got step %d", m.Current())
+	}
+	t.Run("running", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(spinner.RenderSequenceString(m, 20, 3)))
+	})
+
+	// Messages for other sequences are ignored.
+	other := spinner.NewSequence([]string{"Other"})
+	if m, _ = m.Update(other.Next()); m.Current() != 1 {
+		t.Fatal("expected another sequence's message to be ignored")
+	}
+
+	failed, cmd := m.Update(m.Fail(errors.New("checksum mismatch")))
+	if !failed.Finished() || failed.Err() == nil || failed.Steps()[1].State != spinner.StepFailed {
+		t.Fatalf("expected the sequence to fail, got %+v", failed.Steps())
+	}
+	if msg, ok := cmd().(spinner.SequenceDoneMsg); !ok || msg.Err == nil || msg.ID != m.ID() {
+		t.Errorf("expected the sequence to report its error, got %#v", msg)
+	}
+	if _, cmd := failed.Update(failed.Tick()); cmd != nil {
+		t.Error("expected the spinner to stop once the sequence failed")
+	}
+	t.Run("failed", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(spinner.RenderSequenceString(failed, 20, 4)))
+	})
+
+	m, _ = m.Update(m.Next())
+	m, cmd = m.Update(m.Next())
+	if !m.Finished() || m.Err() != nil || m.Current() != 3 {
+		t.Fatalf("expected the sequence to complete, got step %d", m.Current())
+	}
+	if msg, ok := cmd().(spinner.SequenceDoneMsg); !ok || msg.Err != nil {
+		t.Errorf("expected the sequence to report completion, got %#v", msg)
+	}
+	if m.Steps()[1].State != spinner.StepDone || failed.Steps()[1].State != spinner.StepFailed {
+		t.Error("expected copies of the sequence not to share their steps")
+	}
+}
+
+func TestSequenceFailWithoutError(t *testing.T) {
+	m := spinner.NewSequence([]string{"Download", "Install"})
+	m, cmd := m.Update(m.Fail(nil))
+	if !m.Finished() || !errors.Is(m.Err(), spinner.ErrStepFailed) || m.Steps()[0].Err != nil {
+		t.Fatalf("expected the sequence to fail without a step error, got %v", m.Err())
+	}
+	if msg, ok := cmd().(spinner.SequenceDoneMsg); !ok || !errors.Is(msg.Err, spinner.ErrStepFailed) {
+		t.Errorf("expected the sequence to report the failure, got %#v", msg)
+	}
+	if m, _ = m.Update(m.Next()); m.Current() != 0 || m.Steps()[1].State != spinner.StepPending {
+		t.Fatal("expected a failed sequence not to carry on")
+	}
+}
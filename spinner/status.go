@@ -0,0 +1,122 @@
+package spinner
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/render"
+	"github.com/mikeflynn/bubbles/textutil"
+)
+
+const defaultHistorySize = 5
+
+// StatusModel is a spinner with a rolling status message, as seen in deploy
+// tools: the latest status is shown beside the spinner and the previous ones
+// are kept in a short history which can be expanded into a log above it.
+type StatusModel struct {
+	Model
+
+	// HistorySize is the number of statuses to keep, including the current
+	// one. If 0 or less, 5 are kept.
+	HistorySize int
+
+	// StatusStyle styles the current status.
+	StatusStyle lipgloss.Style
+
+	// HistoryStyle styles previous statuses in the expanded log.
+	HistoryStyle lipgloss.Style
+
+	history  []string
+	expanded bool
+}
+
+// NewStatus returns a spinner with a rolling status. The options are the same
+// as for New.
+func NewStatus(opts ...Option) StatusModel {
+	return StatusModel{
+		Model: New(opts...),
+		HistoryStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}),
+	}
+}
+
+// SetStatus sets the current status, moving the previous one into the
+// history. The oldest status is dropped once the history is full.
+func (m *StatusModel) SetStatus(s string) {
+	m.history = append(m.history, s)
+	if n := m.historySize(); len(m.history) > n {
+		m.history = append(m.history[:0], m.history[len(m.history)-n:]...)
+	}
+}
+
+// Status returns the current status, or an empty string if none is set.
+func (m StatusModel) Status() string {
+	if len(m.history) == 0 {
+		return ""
+	}
+	return m.history[len(m.history)-1]
+}
+
+// History returns the kept statuses, oldest first. The last one is the
+// current status.
+func (m StatusModel) History() []string {
+	return append([]string(nil), m.history...)
+}
+
+// ClearStatus removes the current status and the history.
+func (m *StatusModel) ClearStatus() {
+	m.history = nil
+}
+
+// SetExpanded sets whether previous statuses are rendered above the current
+// one.
+func (m *StatusModel) SetExpanded(v bool) {
+	m.expanded = v
+}
+
+// Expanded returns whether previous statuses are rendered.
+func (m StatusModel) Expanded() bool {
+	return m.expanded
+}
+
+// Update is the Tea update function.
+func (m StatusModel) Update(msg tea.Msg) (StatusModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.Model, cmd = m.Model.Update(msg)
+	return m, cmd
+}
+
+// View renders the spinner beside the current status, preceded by the
+// previous statuses if the log is expanded. Previous statuses are indented to
+// line up with the current one.
+func (m StatusModel) View() string {
+	spin := m.Model.View()
+	if len(m.history) == 0 {
+		return spin
+	}
+
+	var b strings.Builder
+	if m.expanded {
+		indent := strings.Repeat(" ", textutil.Width(spin)+1)
+		for _, s := range m.history[:len(m.history)-1] {
+			b.WriteString(indent + m.HistoryStyle.Render(s) + "\n")
+		}
+	}
+	b.WriteString(spin + " " + m.StatusStyle.Render(m.Status()))
+	return b.String()
+}
+
+// RenderStatusString renders the spinner and its statuses deterministically
+// at the given size, for golden-file tests. See the render package for
+// details.
+func RenderStatusString(m StatusModel, width, height int) string {
+	return render.View(m.View, width, height)
+}
+
+func (m StatusModel) historySize() int {
+	if m.HistorySize <= 0 {
+		return defaultHistorySize
+	}
+	return m.HistorySize
+}
@@ -0,0 +1,463 @@
+// Package split provides a container for Bubble Tea applications which lays
+// out two child models side by side or stacked, with a divider between them
+// that can be dragged with the mouse or moved with the keyboard.
+package split
+
+import (
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
+)
+
+// Orientation is how the panes are laid out.
+type Orientation int
+
+// Orientations.
+const (
+	// Horizontal lays the panes out side by side, with a vertical divider.
+	Horizontal Orientation = iota
+
+	// Vertical stacks the panes, with a horizontal divider.
+	Vertical
+)
+
+// Pane identifies one of the two panes.
+type Pane int
+
+// Panes.
+const (
+	// First is the left or top pane.
+	First Pane = iota
+
+	// Second is the right or bottom pane.
+	Second
+)
+
+// noPane marks that no pane is collapsed.
+const noPane Pane = -1
+
+// KeyMap is the key bindings for switching focus between the panes and
+// moving the divider. It satisfies the help.KeyMap interface.
+type KeyMap struct {
+	FocusNext      key.Binding
+	Grow           key.Binding
+	Shrink         key.Binding
+	ToggleCollapse key.Binding
+}
+
+// ShortHelp implements the KeyMap interface.
+func (km KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{km.FocusNext, km.Grow, km.Shrink, km.ToggleCollapse}
+}
+
+// FullHelp implements the KeyMap interface.
+func (km KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{km.FocusNext, km.Grow, km.Shrink, km.ToggleCollapse}}
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		FocusNext: key.NewBinding(
+			key.WithKeys("ctrl+w"),
+			key.WithHelp("ctrl+w", "switch pane"),
+		),
+		Grow: key.NewBinding(
+			key.WithKeys("alt+right", "alt+down"),
+			key.WithHelp("alt+→", "grow pane"),
+		),
+		Shrink: key.NewBinding(
+			key.WithKeys("alt+left", "alt+up"),
+			key.WithHelp("alt+←", "shrink pane"),
+		),
+		ToggleCollapse: key.NewBinding(
+			key.WithKeys("alt+z"),
+			key.WithHelp("alt+z", "zoom pane"),
+		),
+	}
+}
+
+// Styles are the styles of the divider, which is drawn differently while
+// it's being dragged. DefaultStyles returns the defaults.
+type Styles struct {
+	Divider lipgloss.Style
+
+	// Dragging is used for the divider while it's being dragged.
+	Dragging lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this
+// component.
+func DefaultStyles() Styles {
+	return Styles{
+		Divider:  lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}),
+		Dragging: lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#AD58B4", Dark: "#F793FF"}),
+	}
+}
+
+// Model is a split container holding two panes. Key messages go to the
+// focused pane, mouse messages to the pane under the pointer, with their
+// coordinates made relative to it, and other messages to both panes. Each
+// pane is sent a tea.WindowSizeMsg with its own size whenever the split is
+// resized or the divider moves.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+
+	Orientation Orientation
+
+	// MinFirst and MinSecond are the smallest sizes the panes can be resized
+	// to, in columns when side by side and rows when stacked.
+	MinFirst  int
+	MinSecond int
+
+	// Step is how far the divider moves with the Grow and Shrink
+	// keybindings.
+	Step int
+
+	// X and Y are the position of the split on screen, used to make mouse
+	// coordinates relative to it. They should be updated whenever the
+	// parent's layout changes.
+	X, Y int
+
+	panes     [2]tea.Model
+	ratio     float64
+	focus     Pane
+	collapsed Pane
+	dragging  bool
+
+	width  int
+	height int
+}
+
+// New returns a split with the given panes, side by side and evenly sized.
+func New(first, second tea.Model) Model {
+	return Model{
+		KeyMap:    DefaultKeyMap(),
+		Styles:    DefaultStyles(),
+		MinFirst:  1,
+		MinSecond: 1,
+		Step:      2, //nolint:mnd
+		panes:     [2]tea.Model{first, second},
+		ratio:     0.5, //nolint:mnd
+		collapsed: noPane,
+	}
+}
+
+// Pane returns the model in a pane.
+func (m Model) Pane(p Pane) tea.Model {
+	return m.panes[p]
+}
+
+// SetPane replaces the model in a pane, sending it the pane's size. Note
+// that this also returns a command.
+func (m *Model) SetPane(p Pane, model tea.Model) tea.Cmd {
+	m.panes[p] = model
+	return m.resizePane(p)
+}
+
+// Focused returns the pane which receives key messages.
+func (m Model) Focused() Pane {
+	return m.focus
+}
+
+// SetFocus sets the pane which receives key messages. Focusing a collapsed
+// pane expands it.
+func (m *Model) SetFocus(p Pane) tea.Cmd {
+	m.focus = p
+	if m.collapsed == p {
+		return m.Expand()
+	}
+	return nil
+}
+
+// SetSize sets the size of the split, dividing it between the panes. Note
+// that this also returns a command.
+func (m *Model) SetSize(width, height int) tea.Cmd {
+	m.width = width
+	m.height = height
+	return m.resize()
+}
+
+// Width returns the width of the split.
+func (m Model) Width() int {
+	return m.width
+}
+
+// Height returns the height of the split.
+func (m Model) Height() int {
+	return m.height
+}
+
+// SetRatio sets the share of the space taken by the first pane, from 0 to 1.
+// The panes' minimum sizes still apply. Note that this also returns a
+// command.
+func (m *Model) SetRatio(r float64) tea.Cmd {
+	m.ratio = math.Max(0, math.Min(1, r))
+	return m.resize()
+}
+
+// Ratio returns the share of the space taken by the first pane.
+func (m Model) Ratio() float64 {
+	return m.ratio
+}
+
+// Collapse hides a pane, giving all of the space to the other one, which is
+// focused. Note that this also returns a command.
+func (m *Model) Collapse(p Pane) tea.Cmd {
+	m.collapsed = p
+	m.focus = other(p)
+	m.dragging = false
+	return m.resize()
+}
+
+// Expand shows a collapsed pane again, at its previous size. Note that this
+// also returns a command.
+func (m *Model) Expand() tea.Cmd {
+	m.collapsed = noPane
+	return m.resize()
+}
+
+// Collapsed returns the collapsed pane, or false if neither is collapsed.
+func (m Model) Collapsed() (Pane, bool) {
+	return m.collapsed, m.collapsed != noPane
+}
+
+// Sizes returns the sizes of the panes, in columns when side by side and rows
+// when stacked. A collapsed pane has a size of 0.
+func (m Model) Sizes() (first, second int) {
+	total := m.total()
+	switch m.collapsed {
+	case First:
+		return 0, total
+	case Second:
+		return total, 0
+	}
+
+	// One cell goes to the divider.
+	avail := max(0, total-1)
+	first = int(math.Round(m.ratio * float64(avail)))
+	first = min(first, avail-m.MinSecond)
+	first = max(first, m.MinFirst)
+	first = min(first, avail)
+	return first, avail - first
+}
+
+// total returns the length of the split along its orientation.
+func (m Model) total() int {
+	if m.Orientation == Vertical {
+		return m.height
+	}
+	return m.width
+}
+
+// paneSize returns the width and height of a pane.
+func (m Model) paneSize(p Pane) (width, height int) {
+	first, second := m.Sizes()
+	size := first
+	if p == Second {
+		size = second
+	}
+	if m.Orientation == Vertical {
+		return m.width, size
+	}
+	return size, m.height
+}
+
+// moveDivider moves the divider by n cells, growing the first pane if n is
+// positive.
+func (m *Model) moveDivider(n int) tea.Cmd {
+	first, _ := m.Sizes()
+	return m.setDivider(first + n)
+}
+
+// setDivider moves the divider so that the first pane has the given size.
+func (m *Model) setDivider(first int) tea.Cmd {
+	avail := max(1, m.total()-1)
+	m.ratio = math.Max(0, math.Min(1, float64(first)/float64(avail)))
+	return m.resize()
+}
+
+// resize sends each visible pane its size.
+func (m *Model) resize() tea.Cmd {
+	return tea.Batch(m.resizePane(First), m.resizePane(Second))
+}
+
+func (m *Model) resizePane(p Pane) tea.Cmd {
+	if m.panes[p] == nil || m.collapsed == p {
+		return nil
+	}
+	w, h := m.paneSize(p)
+	var cmd tea.Cmd
+	m.panes[p], cmd = m.panes[p].Update(tea.WindowSizeMsg{Width: w, Height: h})
+	return cmd
+}
+
+// Init initializes both panes.
+func (m Model) Init() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, p := range m.panes {
+		if p != nil {
+			cmds = append(cmds, p.Init())
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		return m, m.SetSize(msg.Width, msg.Height)
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.FocusNext):
+			return m, m.SetFocus(other(m.focus))
+		case key.Matches(msg, m.KeyMap.Grow):
+			return m, m.moveDivider(m.growth(m.Step))
+		case key.Matches(msg, m.KeyMap.Shrink):
+			return m, m.moveDivider(m.growth(-m.Step))
+		case key.Matches(msg, m.KeyMap.ToggleCollapse):
+			if m.collapsed != noPane {
+				return m, m.Expand()
+			}
+			return m, m.Collapse(other(m.focus))
+		}
+		return m, m.updatePane(m.focus, msg)
+
+	case tea.MouseMsg:
+		return m, m.updateMouse(msg)
+	}
+
+	return m, tea.Batch(m.updatePane(First, msg), m.updatePane(Second, msg))
+}
+
+// growth returns how far the divider moves to grow the focused pane by n
+// cells.
+func (m Model) growth(n int) int {
+	if m.focus == Second {
+		return -n
+	}
+	return n
+}
+
+func (m *Model) updatePane(p Pane, msg tea.Msg) tea.Cmd {
+	if m.panes[p] == nil {
+		return nil
+	}
+	var cmd tea.Cmd
+	m.panes[p], cmd = m.panes[p].Update(msg)
+	return cmd
+}
+
+// updateMouse drags the divider, or passes the message on to the pane under
+// the pointer, focusing it on a click.
+func (m *Model) updateMouse(msg tea.MouseMsg) tea.Cmd {
+	x, y := msg.X-m.X, msg.Y-m.Y
+	pos := x
+	if m.Orientation == Vertical {
+		pos = y
+	}
+	first, _ := m.Sizes()
+
+	switch {
+	case m.dragging && msg.Action == tea.MouseActionMotion:
+		return m.setDivider(pos)
+	case m.dragging && msg.Action == tea.MouseActionRelease:
+		m.dragging = false
+		return nil
+	case x < 0 || y < 0 || x >= m.width || y >= m.height:
+		return nil
+	}
+
+	pane := First
+	switch {
+	case m.collapsed == First:
+		pane = Second
+	case m.collapsed == Second:
+	case pos == first:
+		// On the divider.
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			m.dragging = true
+		}
+		return nil
+	case pos > first:
+		pane = Second
+		pos -= first + 1
+	}
+
+	if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+		m.focus = pane
+	}
+	local := msg
+	local.X, local.Y = x, y
+	if m.Orientation == Vertical {
+		local.Y = pos
+	} else {
+		local.X = pos
+	}
+	return m.updatePane(pane, local)
+}
+
+// Dragging returns whether the divider is being dragged.
+func (m Model) Dragging() bool {
+	return m.dragging
+}
+
+// View renders the panes and the divider between them.
+func (m Model) View() string {
+	first, second := m.Sizes()
+	style := m.Styles.Divider
+	if m.dragging {
+		style = m.Styles.Dragging
+	}
+
+	views := make([]string, 0, 3) //nolint:mnd
+	if first > 0 {
+		views = append(views, m.paneView(First))
+	}
+	if first > 0 && second > 0 || m.collapsed == noPane {
+		if m.Orientation == Vertical {
+			views = append(views, style.Render(strings.Repeat("─", m.width)))
+		} else {
+			views = append(views, style.Render(strings.TrimSuffix(strings.Repeat("│\n", m.height), "\n")))
+		}
+	}
+	if second > 0 {
+		views = append(views, m.paneView(Second))
+	}
+
+	if m.Orientation == Vertical {
+		return lipgloss.JoinVertical(lipgloss.Left, views...)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, views...)
+}
+
+// paneView renders a pane, cut or padded to its size.
+func (m Model) paneView(p Pane) string {
+	w, h := m.paneSize(p)
+	var view string
+	if m.panes[p] != nil {
+		view = m.panes[p].View()
+	}
+	return render.Fit(view, w, h)
+}
+
+// RenderString renders the split deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	m.SetSize(width, height)
+	return render.View(m.View, width, height)
+}
+
+func other(p Pane) Pane {
+	if p == First {
+		return Second
+	}
+	return First
+}
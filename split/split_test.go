@@ -0,0 +1,139 @@
+package split
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+// pane is a child model which fills its size with a letter and records the
+// messages it's sent.
+type pane struct {
+	letter        string
+	width, height int
+	keys          []string
+	clicks        []tea.MouseMsg
+}
+
+func (p pane) Init() tea.Cmd { return nil }
+
+func (p pane) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.width, p.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		p.keys = append(p.keys, msg.String())
+	case tea.MouseMsg:
+		p.clicks = append(p.clicks, msg)
+	}
+	return p, nil
+}
+
+func (p pane) View() string {
+	line := strings.Repeat(p.letter, p.width)
+	return strings.TrimSuffix(strings.Repeat(line+"\n", p.height), "\n")
+}
+
+func newSplit() Model {
+	m := New(pane{letter: "a"}, pane{letter: "b"})
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 21, Height: 3})
+	return m
+}
+
+func size(m Model, p Pane) (int, int) {
+	child := m.Pane(p).(pane)
+	return child.width, child.height
+}
+
+func TestSizes(t *testing.T) {
+	m := newSplit()
+	if w, h := size(m, First); w != 10 || h != 3 {
+		t.Fatalf("expected the first pane to be 10x3, got %dx%d", w, h)
+	}
+	if w, h := size(m, Second); w != 10 || h != 3 {
+		t.Fatalf("expected the second pane to be 10x3, got %dx%d", w, h)
+	}
+
+	m.MinSecond = 4
+	m.SetRatio(1)
+	if w, _ := size(m, Second); w != 4 {
+		t.Fatalf("expected the second pane to keep its minimum width, got %d", w)
+	}
+
+	m.Orientation = Vertical
+	m.SetRatio(0.5)
+	if w, h := size(m, First); w != 21 || h != 1 {
+		t.Fatalf("expected the first pane to be 21x1 when stacked, got %dx%d", w, h)
+	}
+}
+
+func TestKeyboard(t *testing.T) {
+	m := newSplit()
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if keys := m.Pane(First).(pane).keys; len(keys) != 1 || keys[0] != "x" {
+		t.Fatalf("expected the first pane to get x, got %v", keys)
+	}
+	if keys := m.Pane(Second).(pane).keys; len(keys) != 1 || keys[0] != "y" {
+		t.Fatalf("expected the second pane to get y, got %v", keys)
+	}
+
+	// Growing the focused second pane moves the divider left.
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight, Alt: true})
+	if w, _ := size(m, Second); w != 12 {
+		t.Fatalf("expected the second pane to grow to 12, got %d", w)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z"), Alt: true})
+	if p, ok := m.Collapsed(); !ok || p != First {
+		t.Fatal("expected the first pane to be collapsed")
+	}
+	if w, _ := size(m, Second); w != 21 {
+		t.Fatalf("expected the second pane to take the full width, got %d", w)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z"), Alt: true})
+	if _, ok := m.Collapsed(); ok {
+		t.Fatal("expected the pane to be expanded")
+	}
+	if w, _ := size(m, Second); w != 12 {
+		t.Fatalf("expected the second pane to get its size back, got %d", w)
+	}
+}
+
+func TestMouse(t *testing.T) {
+	m := newSplit()
+	m.X, m.Y = 2, 1
+
+	click := tea.MouseMsg{X: 15, Y: 2, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress}
+	m, _ = m.Update(click)
+	if m.Focused() != Second {
+		t.Fatal("expected a click to focus the second pane")
+	}
+	if clicks := m.Pane(Second).(pane).clicks; len(clicks) != 1 || clicks[0].X != 2 || clicks[0].Y != 1 {
+		t.Fatalf("expected the click to be relative to the pane, got %v", clicks)
+	}
+
+	// Drag the divider from column 10 to 5.
+	m, _ = m.Update(tea.MouseMsg{X: 12, Y: 1, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	if !m.Dragging() {
+		t.Fatal("expected the divider to be dragged")
+	}
+	m, _ = m.Update(tea.MouseMsg{X: 7, Y: 1, Action: tea.MouseActionMotion})
+	m, _ = m.Update(tea.MouseMsg{X: 7, Y: 1, Action: tea.MouseActionRelease})
+	if m.Dragging() {
+		t.Fatal("expected the drag to end")
+	}
+	if first, second := m.Sizes(); first != 5 || second != 15 {
+		t.Fatalf("expected sizes 5 and 15, got %d and %d", first, second)
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	m := New(pane{letter: "a"}, pane{letter: "b"})
+	m.SetRatio(0.3)
+	golden.RequireEqual(t, []byte(RenderString(m, 21, 3)))
+}
@@ -0,0 +1,86 @@
+package stopwatch
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// State is the state of a stopwatch, as returned by Snapshot. It's meant to
+// be persisted between runs, such as by encoding it with encoding/json, and
+// given back to the stopwatch with Restore, so that time tracking carries on
+// across restarts.
+type State struct {
+	// Elapsed is the time elapsed when the snapshot was taken.
+	Elapsed time.Duration `json:"elapsed"`
+
+	Running bool `json:"running,omitempty"`
+	Paused  bool `json:"paused,omitempty"`
+
+	// Start is the wall-clock time a running stopwatch counts from: when the
+	// snapshot was taken, less Elapsed. A stopwatch restored while running
+	// counts the time since then as elapsed, including the time the program
+	// wasn't running.
+	Start time.Time `json:"start,omitempty"`
+
+	// Started and Active are the time the stopwatch was first started and
+	// the time it had been running when the snapshot was taken. See
+	// Breakdown.
+	Started time.Time     `json:"started,omitempty"`
+	Active  time.Duration `json:"active,omitempty"`
+}
+
+// Snapshot returns the state of the stopwatch, to be persisted and given back
+// to Restore.
+func (m Model) Snapshot() State {
+	return m.snapshot(time.Now())
+}
+
+func (m Model) snapshot(now time.Time) State {
+	s := State{
+		Elapsed: m.d,
+		Running: m.running,
+		Paused:  m.paused,
+		Started: m.started,
+		Active:  m.active,
+	}
+	if m.running {
+		s.Start = now.Add(-m.d)
+		s.Active += now.Sub(m.since)
+	}
+	return s
+}
+
+// Restore sets the stopwatch to a state returned by Snapshot. If the
+// stopwatch was running, the time elapsed is counted from the state's Start,
+// rounded down to the Interval, and the returned command carries on ticking.
+func (m *Model) Restore(s State) tea.Cmd {
+	return m.restore(s, time.Now())
+}
+
+func (m *Model) restore(s State, now time.Time) tea.Cmd {
+	m.d = s.Elapsed
+	m.running = s.Running
+	m.paused = s.Paused && !s.Running
+	m.started = s.Started
+	m.active = s.Active
+	m.since = time.Time{}
+
+	// Reject the ticks of the state the stopwatch was in.
+	m.tag++
+	if !m.running {
+		return nil
+	}
+
+	d := now.Sub(s.Start)
+	if m.Interval > 0 {
+		d = d.Truncate(m.Interval)
+	}
+	m.d = max(s.Elapsed, d)
+	m.active += now.Sub(s.Start.Add(s.Elapsed))
+	m.since = now
+	if m.started.IsZero() {
+		m.started = s.Start
+	}
+	return tick(m.id, m.tag, m.Interval)
+}
@@ -0,0 +1,107 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// roundTrip snapshots m at taken, encodes the state as JSON and restores it
+// into a new stopwatch at restored.
+func roundTrip(t *testing.T, m Model, taken, restored time.Time) (Model, bool) {
+	t.Helper()
+	b, err := json.Marshal(m.snapshot(taken))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s State
+	if err := json.Unmarshal(b, &s); err != nil {
+		t.Fatal(err)
+	}
+	r := New()
+	cmd := r.restore(s, restored)
+	return r, cmd != nil
+}
+
+func TestRestoreRunning(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := New()
+	m.start(t0)
+	m.d = 10 * time.Second
+
+	// The time until the snapshot was restored counts as elapsed, rounded
+	// down to the interval.
+	r, ticking := roundTrip(t, m, t0.Add(10*time.Second), t0.Add(70*time.Second+300*time.Millisecond))
+	if !r.Running() || r.Paused() || !ticking {
+		t.Fatal("expected a running stopwatch to carry on ticking")
+	}
+	if r.Elapsed() != 70*time.Second {
+		t.Fatalf("expected 70s elapsed, got %v", r.Elapsed())
+	}
+	if !r.started.Equal(t0) {
+		t.Fatalf("expected the first start to be kept, got %v", r.started)
+	}
+	want := Breakdown{Active: 80 * time.Second, Wall: 80 * time.Second}
+	if b := r.breakdown(t0.Add(80 * time.Second)); b != want {
+		t.Fatalf("expected %+v, got %+v", want, b)
+	}
+}
+
+func TestRestorePaused(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := New()
+	m.start(t0)
+	m.d = 5 * time.Second
+	m.stop(t0.Add(5 * time.Second))
+	m.paused = true
+
+	r, ticking := roundTrip(t, m, t0.Add(6*time.Second), t0.Add(time.Hour))
+	if r.Running() || !r.Paused() || ticking {
+		t.Fatal("expected a paused stopwatch to stay paused")
+	}
+	if r.Elapsed() != 5*time.Second {
+		t.Fatalf("expected the elapsed time to be kept, got %v", r.Elapsed())
+	}
+	if r.Resume() == nil {
+		t.Fatal("expected a restored paused stopwatch to be resumable")
+	}
+	want := Breakdown{Active: 5 * time.Second, Idle: time.Hour - 5*time.Second, Wall: time.Hour}
+	if b := r.breakdown(t0.Add(time.Hour)); b != want {
+		t.Fatalf("expected %+v, got %+v", want, b)
+	}
+}
+
+func TestRestoreStopped(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := New()
+	m.start(t0)
+	m.d = 3 * time.Second
+	m.stop(t0.Add(3 * time.Second))
+
+	r, ticking := roundTrip(t, m, t0.Add(4*time.Second), t0.Add(time.Minute))
+	if r.Running() || r.Paused() || ticking {
+		t.Fatal("expected a stopped stopwatch to stay stopped")
+	}
+	if r.Elapsed() != 3*time.Second {
+		t.Fatalf("expected the elapsed time to be kept, got %v", r.Elapsed())
+	}
+
+	// A stopwatch that was never started restores to its zero state.
+	r, ticking = roundTrip(t, New(), t0, t0.Add(time.Minute))
+	if r.Running() || ticking || r.Elapsed() != 0 || r.breakdown(t0) != (Breakdown{}) {
+		t.Fatal("expected an unstarted stopwatch to restore as unstarted")
+	}
+}
+
+func TestRestoreRejectsOldTicks(t *testing.T) {
+	m := New()
+	m, _ = m.Update(StartStopMsg{ID: m.ID(), Running: true})
+	m, _ = m.Update(TickMsg{ID: m.ID()})
+	old := TickMsg{ID: m.ID(), tag: m.tag}
+
+	m.Restore(m.Snapshot())
+	m, _ = m.Update(old)
+	if m.Elapsed() != time.Second {
+		t.Fatalf("expected a tick from before restoring to be rejected, got %v", m.Elapsed())
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mikeflynn/bubbles/render"
 )
 
 var lastID int64
@@ -29,8 +30,21 @@ type TickMsg struct {
 
 // StartStopMsg is sent when the stopwatch should start or stop.
 type StartStopMsg struct {
-	ID      int
-	running bool
+	ID int
+
+	// Running is true if the stopwatch should start and false if it should
+	// stop.
+	Running bool
+}
+
+// PauseMsg is sent when the stopwatch should pause.
+type PauseMsg struct {
+	ID int
+}
+
+// ResumeMsg is sent when a paused stopwatch should resume.
+type ResumeMsg struct {
+	ID int
 }
 
 // ResetMsg is sent when the stopwatch should reset.
@@ -38,12 +52,34 @@ type ResetMsg struct {
 	ID int
 }
 
+// Breakdown splits the wall-clock time since a stopwatch was first started
+// into the time it spent running and the time it spent paused or stopped.
+type Breakdown struct {
+	// Active is the time the stopwatch has been running.
+	Active time.Duration
+
+	// Idle is the time the stopwatch has been paused or stopped.
+	Idle time.Duration
+
+	// Wall is the time since the stopwatch was first started, or last reset
+	// while running. It's the sum of Active and Idle.
+	Wall time.Duration
+}
+
 // Model for the stopwatch component.
 type Model struct {
 	d       time.Duration
 	id      int
 	tag     int
 	running bool
+	paused  bool
+
+	// Wall-clock accounting. started is when the stopwatch was first started
+	// since it was reset, since is when it last started or resumed, and
+	// active is the running time accumulated before since.
+	started time.Time
+	since   time.Time
+	active  time.Duration
 
 	// How long to wait before every tick. Defaults to 1 second.
 	Interval time.Duration
@@ -76,17 +112,36 @@ func (m Model) Init() tea.Cmd {
 // Start starts the stopwatch.
 func (m Model) Start() tea.Cmd {
 	return tea.Sequence(func() tea.Msg {
-		return StartStopMsg{ID: m.id, running: true}
+		return StartStopMsg{ID: m.id, Running: true}
 	}, tick(m.id, m.tag, m.Interval))
 }
 
 // Stop stops the stopwatch.
 func (m Model) Stop() tea.Cmd {
 	return func() tea.Msg {
-		return StartStopMsg{ID: m.id, running: false}
+		return StartStopMsg{ID: m.id, Running: false}
+	}
+}
+
+// Pause pauses a running stopwatch. Unlike Stop, the stopwatch remembers that
+// it was paused, so that Resume can pick up where it left off.
+func (m Model) Pause() tea.Cmd {
+	return func() tea.Msg {
+		return PauseMsg{ID: m.id}
 	}
 }
 
+// Resume resumes a paused stopwatch. It has no effect on a stopwatch which
+// isn't paused.
+func (m Model) Resume() tea.Cmd {
+	if !m.paused {
+		return nil
+	}
+	return tea.Sequence(func() tea.Msg {
+		return ResumeMsg{ID: m.id}
+	}, tick(m.id, m.tag, m.Interval))
+}
+
 // Toggle stops the stopwatch if it is running and starts it if it is stopped.
 func (m Model) Toggle() tea.Cmd {
 	if m.Running() {
@@ -107,6 +162,11 @@ func (m Model) Running() bool {
 	return m.running
 }
 
+// Paused returns true if the stopwatch has been paused and not yet resumed.
+func (m Model) Paused() bool {
+	return m.paused
+}
+
 // Update handles the timer tick.
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -114,12 +174,34 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		if msg.ID != m.id {
 			return m, nil
 		}
-		m.running = msg.running
+		if msg.Running {
+			m.start(time.Now())
+		} else {
+			m.stop(time.Now())
+		}
+	case PauseMsg:
+		if msg.ID != m.id || !m.running {
+			return m, nil
+		}
+		m.stop(time.Now())
+		m.paused = true
+	case ResumeMsg:
+		if msg.ID != m.id || !m.paused {
+			return m, nil
+		}
+		m.start(time.Now())
 	case ResetMsg:
 		if msg.ID != m.id {
 			return m, nil
 		}
 		m.d = 0
+		m.active = 0
+		m.paused = false
+		m.started = time.Time{}
+		if m.running {
+			m.started = time.Now()
+			m.since = m.started
+		}
 	case TickMsg:
 		if !m.running || msg.ID != m.id {
 			break
@@ -140,16 +222,65 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	return m, nil
 }
 
-// Elapsed returns the time elapsed.
+// start marks the stopwatch as running from now.
+func (m *Model) start(now time.Time) {
+	if m.running {
+		return
+	}
+	if m.started.IsZero() {
+		m.started = now
+	}
+	m.since = now
+	m.running = true
+	m.paused = false
+}
+
+// stop marks the stopwatch as stopped from now, adding the time since it was
+// started to the running time. A paused stopwatch is no longer paused.
+func (m *Model) stop(now time.Time) {
+	m.paused = false
+	if !m.running {
+		return
+	}
+	m.active += now.Sub(m.since)
+	m.running = false
+}
+
+// Elapsed returns the time elapsed, counted in ticks.
 func (m Model) Elapsed() time.Duration {
 	return m.d
 }
 
+// Breakdown returns the wall-clock time since the stopwatch was first started,
+// split into running and idle time. Unlike Elapsed, it doesn't depend on
+// ticks being delivered on time.
+func (m Model) Breakdown() Breakdown {
+	return m.breakdown(time.Now())
+}
+
+func (m Model) breakdown(now time.Time) Breakdown {
+	if m.started.IsZero() {
+		return Breakdown{}
+	}
+	b := Breakdown{Active: m.active, Wall: now.Sub(m.started)}
+	if m.running {
+		b.Active += now.Sub(m.since)
+	}
+	b.Idle = b.Wall - b.Active
+	return b
+}
+
 // View of the timer component.
 func (m Model) View() string {
 	return m.d.String()
 }
 
+// RenderString renders the stopwatch deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	return render.View(m.View, width, height)
+}
+
 func tick(id int, tag int, d time.Duration) tea.Cmd {
 	return tea.Tick(d, func(_ time.Time) tea.Msg {
 		return TickMsg{ID: id, tag: tag}
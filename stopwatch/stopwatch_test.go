@@ -0,0 +1,112 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+func TestPauseResume(t *testing.T) {
+	m := New()
+	m, _ = m.Update(StartStopMsg{ID: m.ID(), Running: true})
+	if !m.Running() || m.Paused() {
+		t.Fatal("expected the stopwatch to be running")
+	}
+	if m.Resume() != nil {
+		t.Fatal("expected Resume to do nothing unless paused")
+	}
+
+	m, _ = m.Update(PauseMsg{ID: m.ID()})
+	if m.Running() || !m.Paused() {
+		t.Fatal("expected the stopwatch to be paused")
+	}
+	if m.Resume() == nil {
+		t.Fatal("expected Resume to resume a paused stopwatch")
+	}
+
+	// Messages for other stopwatches are ignored.
+	m, _ = m.Update(ResumeMsg{ID: m.ID() + 1})
+	if !m.Paused() {
+		t.Fatal("expected a resume for another stopwatch to be ignored")
+	}
+
+	m, _ = m.Update(ResumeMsg{ID: m.ID()})
+	if !m.Running() || m.Paused() {
+		t.Fatal("expected the stopwatch to be running again")
+	}
+
+	// Stopping forgets that it was paused, so Resume does nothing.
+	m, _ = m.Update(PauseMsg{ID: m.ID()})
+	m, _ = m.Update(StartStopMsg{ID: m.ID(), Running: false})
+	if m.Paused() || m.Resume() != nil {
+		t.Fatal("expected a stopped stopwatch not to be paused")
+	}
+
+	// Pausing a stopped stopwatch does nothing.
+	m, _ = m.Update(PauseMsg{ID: m.ID()})
+	if m.Paused() {
+		t.Fatal("expected pausing a stopped stopwatch to do nothing")
+	}
+}
+
+func TestBreakdown(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return t0.Add(d) }
+
+	m := New()
+	if b := m.breakdown(at(time.Hour)); b != (Breakdown{}) {
+		t.Fatalf("expected an empty breakdown before starting, got %+v", b)
+	}
+
+	m.start(at(0))
+	m.stop(at(10 * time.Second))
+	m.paused = true
+	m.start(at(15 * time.Second))
+
+	want := Breakdown{Active: 20 * time.Second, Idle: 5 * time.Second, Wall: 25 * time.Second}
+	if b := m.breakdown(at(25 * time.Second)); b != want {
+		t.Fatalf("expected %+v while running, got %+v", want, b)
+	}
+
+	m.stop(at(30 * time.Second))
+	want = Breakdown{Active: 25 * time.Second, Idle: 15 * time.Second, Wall: 40 * time.Second}
+	if b := m.breakdown(at(40 * time.Second)); b != want {
+		t.Fatalf("expected %+v while stopped, got %+v", want, b)
+	}
+
+	// Starting again doesn't move the time it was first started.
+	m.start(at(40 * time.Second))
+	if m.started != t0 {
+		t.Fatalf("expected the first start to be kept, got %v", m.started)
+	}
+}
+
+func TestReset(t *testing.T) {
+	m := New()
+	m, _ = m.Update(StartStopMsg{ID: m.ID(), Running: true})
+	m, _ = m.Update(TickMsg{ID: m.ID()})
+	m, _ = m.Update(PauseMsg{ID: m.ID()})
+	if m.Elapsed() != time.Second {
+		t.Fatalf("expected a tick to add the interval, got %v", m.Elapsed())
+	}
+
+	m, _ = m.Update(ResetMsg{ID: m.ID()})
+	if m.Elapsed() != 0 || m.Paused() || m.Breakdown() != (Breakdown{}) {
+		t.Fatalf("expected the stopwatch to be reset, got %v %+v", m.Elapsed(), m.Breakdown())
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	m := NewWithInterval(time.Second)
+	t.Run("zero", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(m, 10, 1)))
+	})
+	m, _ = m.Update(StartStopMsg{ID: m.ID(), Running: true})
+	for range 75 {
+		m, _ = m.Update(TickMsg{ID: m.ID()})
+	}
+	t.Run("running", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(m, 10, 1)))
+	})
+}
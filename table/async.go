@@ -0,0 +1,299 @@
+package table
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var lastID int64
+
+func nextID() int {
+	return int(atomic.AddInt64(&lastID, 1))
+}
+
+// CompareFunc compares two values of the column rows are sorted by. It
+// returns a negative number if a sorts before b, a positive number if it
+// sorts after b, and zero if they keep their order.
+type CompareFunc func(a, b string) int
+
+// CompareValues compares values as numbers if both are numbers, and as text
+// ignoring case otherwise, with numbers before text. It's used by default.
+func CompareValues(a, b string) int {
+	x, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	y, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(x, y)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	if c := strings.Compare(strings.ToLower(a), strings.ToLower(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// SortedMsg is sent by the command returned by SortCmd once the rows are
+// sorted. Order holds the indexes in Rows of the rows in their new order.
+// Passing it to Update reorders the rows, unless the sort was canceled or the
+// rows were set since.
+type SortedMsg struct {
+	Column     int
+	Descending bool
+	Order      []int
+
+	id  int
+	tag int
+}
+
+// FilteredMsg is sent by the command returned by FilterCmd once the rows
+// matching Term are found. Matches holds their indexes in Rows. Passing it to
+// Update applies the filter, unless it was canceled or the rows were set
+// since.
+type FilteredMsg struct {
+	Term    string
+	Matches []int
+
+	highlights [][][]int
+	id         int
+	tag        int
+}
+
+// pendingJob is a sort or filter running in the background.
+type pendingJob struct {
+	tag    int
+	cancel context.CancelFunc
+
+	// column is the column being sorted, or -1 for a filter.
+	column int
+}
+
+// WithCompareFunc sets the function used to compare values when sorting. See
+// SetCompareFunc.
+func WithCompareFunc(f CompareFunc) Option {
+	return func(m *Model) {
+		m.compareFunc = f
+	}
+}
+
+// SetCompareFunc sets the function used to compare the values of a column
+// when sorting by it. Passing nil restores CompareValues.
+func (m *Model) SetCompareFunc(f CompareFunc) {
+	m.compareFunc = f
+}
+
+// SortCmd returns a command sorting the rows by a column, given by its index
+// in Columns, without blocking the UI, which matters for tables of many
+// rows. The command sends a SortedMsg, which reorders the rows once passed to
+// Update. Rows compare equal keep their order.
+//
+// Until then, the column's header shows the Pending style, and the
+// CancelPending key cancels the sort. Starting another sort or filter
+// cancels it too, as does setting the rows.
+func (m *Model) SortCmd(column int, descending bool) tea.Cmd {
+	ctx := m.startPending(column)
+	rows := m.Rows()
+	compare := m.compareFunc
+	if compare == nil {
+		compare = CompareValues
+	}
+	msg := SortedMsg{Column: column, Descending: descending, id: m.id, tag: m.pending.tag}
+	return func() tea.Msg {
+		order := make([]int, len(rows))
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortStableFunc(order, func(a, b int) int {
+			// Once canceled, every row compares equal so the sort ends
+			// quickly.
+			if ctx.Err() != nil {
+				return 0
+			}
+			c := compare(cell(rows[a], column), cell(rows[b], column))
+			if descending {
+				return -c
+			}
+			return c
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		msg.Order = order
+		return msg
+	}
+}
+
+// FilterCmd returns a command finding the rows matching a term, like
+// SetFilter, without blocking the UI, which matters for tables of many rows.
+// The command sends a FilteredMsg, which applies the filter once passed to
+// Update. An empty term removes the filter right away.
+//
+// Until then, the header of the first column shows the Pending style, and
+// the CancelPending key cancels the filter. Starting another sort or filter
+// cancels it too, as do setting the rows and changing the filter otherwise.
+func (m *Model) FilterCmd(term string) tea.Cmd {
+	if term == "" {
+		m.ResetFilter()
+		return nil
+	}
+	ctx := m.startPending(-1)
+	rows := m.Rows()
+	filter := m.rowFilter(term)
+	msg := FilteredMsg{Term: term, id: m.id, tag: m.pending.tag}
+	return func() tea.Msg {
+		matches, highlights := filter.match(ctx, rows)
+		if ctx.Err() != nil {
+			return nil
+		}
+		msg.Matches, msg.highlights = matches, highlights
+		return msg
+	}
+}
+
+// Pending returns whether a sort or filter started with SortCmd or FilterCmd
+// is running.
+func (m Model) Pending() bool {
+	return m.pending.cancel != nil
+}
+
+// CancelPending cancels the sort or filter running in the background, if
+// any. Its result is never sent.
+func (m *Model) CancelPending() {
+	if m.pending.cancel != nil {
+		m.pending.cancel()
+	}
+	m.pending = pendingJob{tag: m.pending.tag + 1}
+	m.KeyMap.CancelPending.SetEnabled(false)
+}
+
+// startPending cancels any sort or filter running in the background and
+// starts tracking a new one, returning its context.
+func (m *Model) startPending(column int) context.Context {
+	m.CancelPending()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.pending.cancel, m.pending.column = cancel, column
+	m.KeyMap.CancelPending.SetEnabled(true)
+	return ctx
+}
+
+// updatePending applies the result of a sort or filter run in the
+// background. It returns false for other messages.
+func (m *Model) updatePending(msg tea.Msg) bool {
+	switch msg := msg.(type) {
+	case SortedMsg:
+		if m.finishPending(msg.id, msg.tag) {
+			m.applySort(msg.Order)
+		}
+	case FilteredMsg:
+		if m.finishPending(msg.id, msg.tag) {
+			m.filterInput.SetValue(msg.Term)
+			if m.filterState != Filtering {
+				m.filterInput.Blur()
+				m.setFilterState(FilterApplied)
+			}
+			m.setMatches(msg.Matches, msg.highlights)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// finishPending stops tracking the sort or filter with the given tag,
+// returning whether it's still the one running.
+func (m *Model) finishPending(id, tag int) bool {
+	if id != m.id || tag != m.pending.tag || !m.Pending() {
+		return false
+	}
+	m.CancelPending()
+	return true
+}
+
+// applySort reorders the rows, given the indexes in Rows of the rows in
+// their new order. The selected row and any selected range stay selected,
+// and a filter stays applied.
+func (m *Model) applySort(order []int) {
+	rows := m.Rows()
+	if len(order) != len(rows) {
+		return
+	}
+	selected := m.SourceIndex(m.Cursor())
+	key, _ := m.SelectedGroup()
+
+	sorted := make([]Row, len(rows))
+	moved := make([]int, len(rows))
+	for i, j := range order {
+		sorted[i], moved[j] = rows[j], i
+	}
+
+	if m.sourceIndex == nil {
+		m.rows = sorted
+		if selected >= 0 {
+			m.cursor = moved[selected]
+		}
+	} else {
+		// visible holds one more than the index of each matching row among
+		// the matching rows, or zero for the others.
+		visible := make([]int, len(rows))
+		for i, j := range m.sourceIndex {
+			visible[j] = i + 1
+		}
+		m.unfiltered = sorted
+		matching := make([]Row, 0, len(m.rows))
+		sourceIndex := make([]int, 0, len(m.sourceIndex))
+		highlights := make([][][]int, 0, len(m.highlights))
+		for i, j := range order {
+			v := visible[j] - 1
+			if v < 0 {
+				continue
+			}
+			if j == selected {
+				m.cursor = len(matching)
+			}
+			matching = append(matching, sorted[i])
+			sourceIndex = append(sourceIndex, i)
+			highlights = append(highlights, m.highlights[v])
+		}
+		m.rows = matching
+		m.sourceIndex, m.highlights = sourceIndex, highlights
+	}
+
+	if m.selection != nil {
+		selection := make(map[int]bool, len(m.selection))
+		for r := range m.selection {
+			if r >= 0 && r < len(moved) {
+				selection[moved[r]] = true
+			}
+		}
+		m.selection = selection
+	}
+	if m.anchor >= 0 && m.anchor < len(moved) {
+		m.anchor = moved[m.anchor]
+	}
+
+	m.regroup(selected >= 0, key)
+	m.scrollToCursor()
+}
+
+// pendingColumn returns the index of the column whose header shows that a
+// sort or filter is running, or -1 if none is.
+func (m Model) pendingColumn() int {
+	switch {
+	case !m.Pending():
+		return -1
+	case m.pending.column >= 0:
+		return m.pending.column
+	}
+	if cols := m.visibleColumns(); len(cols) > 0 {
+		return cols[0]
+	}
+	return -1
+}
@@ -0,0 +1,204 @@
+package table
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mikeflynn/bubbles/clipboard"
+)
+
+// ExportFormat is a text format rows can be exported as.
+type ExportFormat int
+
+// Export formats.
+const (
+	// TSV separates cells with tabs and rows with newlines. Tabs and
+	// newlines within cells are replaced with spaces. It's the default, as
+	// spreadsheets accept it when pasted.
+	TSV ExportFormat = iota
+
+	// CSV is comma-separated values as written by encoding/csv.
+	CSV
+
+	// Markdown is a GitHub-flavored Markdown table.
+	Markdown
+)
+
+// String returns the name of the format.
+func (f ExportFormat) String() string {
+	return [...]string{"TSV", "CSV", "Markdown"}[f]
+}
+
+// CopyScope is what's copied to the clipboard.
+type CopyScope int
+
+// Copy scopes.
+const (
+	// CopyRow copies the selected row.
+	CopyRow CopyScope = iota
+
+	// CopyCell copies the selected row's cell in the selected column, which
+	// is the first shown column unless another one is selected while editing
+	// columns.
+	CopyCell
+
+	// CopyTable copies the header and every row matching the filter.
+	CopyTable
+)
+
+// CopiedMsg is sent after the table is copied, with the text that was
+// copied. Err is set if it couldn't be written to the clipboard.
+type CopiedMsg struct {
+	Scope  CopyScope
+	Format ExportFormat
+	Text   string
+	Err    error
+}
+
+// Copy returns a command which copies the selected row, the selected cell or
+// the whole table, in the table's CopyFormat, to its Clipboard. It sends a
+// CopiedMsg with the text. Hidden columns are
+// left out, and the others are in the order they're displayed.
+func (m Model) Copy(scope CopyScope) tea.Cmd {
+	format, cb := m.CopyFormat, clipboard.Or(m.Clipboard)
+
+	var text string
+	switch scope {
+	case CopyRow:
+		if m.SelectedRow() == nil {
+			return nil
+		}
+		text = m.ExportRow(format)
+	case CopyCell:
+		if m.SelectedRow() == nil {
+			return nil
+		}
+		text = m.SelectedCell()
+	case CopyTable:
+		text = m.Export(format)
+	}
+
+	return func() tea.Msg {
+		msg := CopiedMsg{Scope: scope, Format: format, Text: text}
+		if err := cb.WriteAll(text); err != nil {
+			msg.Err = fmt.Errorf("table: %w", err)
+		}
+		return msg
+	}
+}
+
+// Export returns the header and the rows matching the filter in the given
+// format. Hidden columns are left out, and the others are in the order
+// they're displayed.
+func (m Model) Export(format ExportFormat) string {
+	return m.export(format, m.rows, true)
+}
+
+// ExportRow returns the selected row in the given format. Markdown includes
+// the header, as a table needs one, while TSV and CSV don't.
+func (m Model) ExportRow(format ExportFormat) string {
+	row := m.SelectedRow()
+	if row == nil {
+		return ""
+	}
+	return m.export(format, []Row{row}, format == Markdown)
+}
+
+// SelectedCell returns the value of the selected row's cell in the selected
+// column, without styling. See CopyCell.
+func (m Model) SelectedCell() string {
+	row := m.SelectedRow()
+	cols := m.exportColumns()
+	if row == nil || len(cols) == 0 {
+		return ""
+	}
+	col := cols[0]
+	if order := m.columnOrder(); m.colCursor < len(order) && !m.cols[order[m.colCursor]].Hidden {
+		col = order[m.colCursor]
+	}
+	return cell(row, col)
+}
+
+// exportColumns returns the indexes of the shown columns, in display order.
+func (m Model) exportColumns() []int {
+	var cols []int
+	for _, i := range m.columnOrder() {
+		if !m.cols[i].Hidden {
+			cols = append(cols, i)
+		}
+	}
+	return cols
+}
+
+func (m Model) export(format ExportFormat, rows []Row, header bool) string {
+	cols := m.exportColumns()
+	records := make([][]string, 0, len(rows)+1)
+	if header {
+		titles := make([]string, len(cols))
+		for i, c := range cols {
+			titles[i] = ansi.Strip(m.cols[c].Title)
+		}
+		records = append(records, titles)
+	}
+	for _, r := range rows {
+		record := make([]string, len(cols))
+		for i, c := range cols {
+			record[i] = cell(r, c)
+		}
+		records = append(records, record)
+	}
+
+	switch format {
+	case CSV:
+		var b bytes.Buffer
+		w := csv.NewWriter(&b)
+		_ = w.WriteAll(records) // Writing to a bytes.Buffer can't fail.
+		return strings.TrimSuffix(b.String(), "\n")
+	case Markdown:
+		return markdownTable(records)
+	default:
+		lines := make([]string, len(records))
+		tsv := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ")
+		for i, record := range records {
+			for j := range record {
+				record[j] = tsv.Replace(record[j])
+			}
+			lines[i] = strings.Join(record, "\t")
+		}
+		return strings.Join(lines, "\n")
+	}
+}
+
+// markdownTable renders records as a Markdown table, the first being the
+// header.
+func markdownTable(records [][]string) string {
+	if len(records) == 0 {
+		return ""
+	}
+	escape := strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ")
+	lines := make([]string, 0, len(records)+1)
+	for i, record := range records {
+		cells := make([]string, len(record))
+		for j, c := range record {
+			cells[j] = escape.Replace(c)
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", len(record)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// cell returns the value of a cell without styling, or an empty string if the
+// row is short of cells.
+func cell(r Row, col int) string {
+	if col < 0 || col >= len(r) {
+		return ""
+	}
+	return ansi.Strip(r[col])
+}
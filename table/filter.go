@@ -0,0 +1,382 @@
+package table
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/textinput"
+)
+
+// FilterState describes the current filtering state of the table.
+type FilterState int
+
+// Possible filter states.
+const (
+	Unfiltered    FilterState = iota // no filter set
+	Filtering                        // user is actively setting a filter
+	FilterApplied                    // a filter is applied and user is not editing filter
+)
+
+// String returns a human-readable string of the current filter state.
+func (f FilterState) String() string {
+	return [...]string{
+		"unfiltered",
+		"filtering",
+		"filter applied",
+	}[f]
+}
+
+// FilterFunc reports whether a row matches the filter term. It's given the
+// values of the columns being filtered, and returns the indexes of the runes
+// that matched in each of them, which are highlighted.
+type FilterFunc func(term string, values []string) (matches [][]int, ok bool)
+
+// SubstringFilter matches rows with a value containing the term, ignoring
+// case. It's used by default.
+func SubstringFilter(term string, values []string) ([][]int, bool) {
+	needle := string(lowerRunes(term))
+	n := len([]rune(term))
+	matches := make([][]int, len(values))
+	var ok bool
+	for i, v := range values {
+		haystack := string(lowerRunes(v))
+		j := strings.Index(haystack, needle)
+		if j < 0 {
+			continue
+		}
+		start := len([]rune(haystack[:j]))
+		for k := range n {
+			matches[i] = append(matches[i], start+k)
+		}
+		ok = true
+	}
+	return matches, ok
+}
+
+// FuzzyFilter matches rows with a value containing the characters of the term
+// in order, but not necessarily next to each other, like list.DefaultFilter.
+func FuzzyFilter(term string, values []string) ([][]int, bool) {
+	matches := make([][]int, len(values))
+	var ok bool
+	for i, v := range values {
+		if found := fuzzy.Find(term, []string{v}); len(found) > 0 {
+			matches[i] = found[0].MatchedIndexes
+			ok = true
+		}
+	}
+	return matches, ok
+}
+
+// lowerRunes lower-cases s rune by rune, so rune indexes are unchanged.
+func lowerRunes(s string) []rune {
+	r := []rune(s)
+	for i := range r {
+		r[i] = unicode.ToLower(r[i])
+	}
+	return r
+}
+
+// WithFiltering enables filtering. See SetFilteringEnabled.
+func WithFiltering(v bool) Option {
+	return func(m *Model) {
+		m.filteringEnabled = v
+		m.updateFilterKeys()
+	}
+}
+
+// WithFilterFunc sets the function used to match rows. See SetFilterFunc.
+func WithFilterFunc(f FilterFunc) Option {
+	return func(m *Model) {
+		m.filterFunc = f
+	}
+}
+
+// SetFilteringEnabled enables or disables the keybinding which opens the
+// filter input. Disabling filtering removes any filter.
+func (m *Model) SetFilteringEnabled(v bool) {
+	m.filteringEnabled = v
+	if !v {
+		m.ResetFilter()
+	}
+	m.updateFilterKeys()
+}
+
+// FilteringEnabled returns whether the rows can be filtered.
+func (m Model) FilteringEnabled() bool {
+	return m.filteringEnabled
+}
+
+// SetFilterFunc sets the function used to match rows against the filter,
+// such as FuzzyFilter. Passing nil restores SubstringFilter.
+func (m *Model) SetFilterFunc(f FilterFunc) {
+	m.filterFunc = f
+	m.applyFilter()
+}
+
+// SetFilterColumns sets the indexes of the columns the filter is matched
+// against, as given to SetColumns. With none, all columns are matched,
+// including hidden ones.
+func (m *Model) SetFilterColumns(cols ...int) {
+	m.filterCols = slices.Clone(cols)
+	m.applyFilter()
+}
+
+// SetFilter applies a filter, as if it had been typed into the filter input
+// and accepted. An empty term removes the filter.
+func (m *Model) SetFilter(term string) {
+	m.filterInput.SetValue(term)
+	m.filterInput.Blur()
+	if term == "" {
+		m.ResetFilter()
+		return
+	}
+	m.setFilterState(FilterApplied)
+	m.applyFilter()
+}
+
+// ResetFilter removes the filter, showing all rows again. The selected row
+// stays selected.
+func (m *Model) ResetFilter() {
+	m.filterInput.Reset()
+	m.filterInput.Blur()
+	m.setFilterState(Unfiltered)
+	m.applyFilter()
+}
+
+// FilterState returns the current filter state.
+func (m Model) FilterState() FilterState {
+	return m.filterState
+}
+
+// FilterValue returns the current value of the filter.
+func (m Model) FilterValue() string {
+	return m.filterInput.Value()
+}
+
+// VisibleRows returns the rows matching the filter, or all rows if there's no
+// filter. Cursor and SetCursor count these rows.
+func (m Model) VisibleRows() []Row {
+	return m.rows
+}
+
+// SourceIndex returns the index in Rows of the visible row at the given
+// index, such as the one returned by Cursor. It returns -1 if there's no such
+// row.
+func (m Model) SourceIndex(i int) int {
+	switch {
+	case i < 0 || i >= len(m.rows):
+		return -1
+	case m.sourceIndex != nil:
+		return m.sourceIndex[i]
+	default:
+		return i
+	}
+}
+
+func newFilterInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "Filter: "
+	ti.CharLimit = 64 //nolint:mnd
+	return ti
+}
+
+// startFiltering opens the filter input.
+func (m *Model) startFiltering() tea.Cmd {
+	m.setFilterState(Filtering)
+	m.filterInput.CursorEnd()
+	return m.filterInput.Focus()
+}
+
+// updateFiltering handles keys while the filter input is open, narrowing the
+// rows as the filter is typed.
+func (m Model) updateFiltering(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.KeyMap.CancelWhileFiltering):
+		m.ResetFilter()
+		return m, nil
+	case key.Matches(msg, m.KeyMap.AcceptWhileFiltering):
+		m.SetFilter(m.filterInput.Value())
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	m.applyFilter()
+	return m, cmd
+}
+
+// setFilterState changes the filter state, shrinking or growing the viewport
+// so the table keeps its height as the filter input is shown and hidden.
+func (m *Model) setFilterState(s FilterState) {
+	prev := m.filterHeight()
+	m.filterState = s
+	m.viewport.Height -= m.filterHeight() - prev
+	m.updateFilterKeys()
+}
+
+// updateFilterKeys enables the filter keybindings which apply in the current
+// filter state.
+func (m *Model) updateFilterKeys() {
+	m.KeyMap.Filter.SetEnabled(m.filteringEnabled && m.filterState != Filtering)
+	m.KeyMap.ClearFilter.SetEnabled(m.filteringEnabled && m.filterState == FilterApplied)
+	m.KeyMap.CancelWhileFiltering.SetEnabled(m.filterState == Filtering)
+	m.KeyMap.AcceptWhileFiltering.SetEnabled(m.filterState == Filtering)
+}
+
+// filterHeight returns the height taken up by the filter input.
+func (m Model) filterHeight() int {
+	if m.filterState == Unfiltered {
+		return 0
+	}
+	return 1
+}
+
+func (m Model) filterView() string {
+	ti := m.filterInput
+	ti.PromptStyle = m.styles.FilterPrompt
+	if m.viewport.Width > 0 {
+		ti.Width = max(1, m.viewport.Width-lipgloss.Width(ti.Prompt)-1)
+	}
+	return ti.View()
+}
+
+// applyFilter narrows the rows to those matching the filter, keeping the
+// selected row selected if it still matches. It cancels any filter running
+// in the background, which would be outdated.
+func (m *Model) applyFilter() {
+	if m.Pending() && m.pending.column < 0 {
+		m.CancelPending()
+	}
+	term := m.filterInput.Value()
+	if m.filterState == Unfiltered || term == "" {
+		m.setMatches(nil, nil)
+		return
+	}
+	m.setMatches(m.rowFilter(term).match(context.Background(), m.Rows()))
+}
+
+// setMatches narrows the rows to those at the given indexes in Rows, with
+// the matched runes of each of their cells, keeping the selected row
+// selected if it's among them. Nil indexes show all the rows.
+func (m *Model) setMatches(matches []int, highlights [][][]int) {
+	selected := m.SourceIndex(m.Cursor())
+	key, _ := m.SelectedGroup()
+	rows := m.Rows()
+
+	if matches == nil {
+		m.rows, m.unfiltered, m.sourceIndex, m.highlights = rows, nil, nil, nil
+		m.cursor = clamp(selected, 0, len(rows)-1)
+	} else {
+		m.unfiltered = rows
+		m.rows = make([]Row, len(matches))
+		m.sourceIndex, m.highlights = matches, highlights
+		m.cursor = 0
+		for i, j := range matches {
+			m.rows[i] = rows[j]
+			if j == selected {
+				m.cursor = i
+			}
+		}
+		m.cursor = clamp(m.cursor, 0, len(m.rows)-1)
+	}
+	m.regroup(selected >= 0, key)
+	m.scrollToCursor()
+}
+
+// rowFilter matches rows against a term, in the given columns.
+type rowFilter struct {
+	term   string
+	filter FilterFunc
+	cols   []int
+	ncols  int
+}
+
+// rowFilter returns a filter matching rows against a term with the table's
+// filter function and columns.
+func (m Model) rowFilter(term string) rowFilter {
+	f := rowFilter{term: term, filter: m.filterFunc, cols: m.filterCols, ncols: len(m.cols)}
+	if f.filter == nil {
+		f.filter = SubstringFilter
+	}
+	if len(f.cols) == 0 {
+		f.cols = make([]int, len(m.cols))
+		for i := range f.cols {
+			f.cols[i] = i
+		}
+	}
+	return f
+}
+
+// match returns the indexes of the rows matching the term, along with the
+// matched runes of each of their cells. It returns nil if ctx is canceled
+// first.
+func (f rowFilter) match(ctx context.Context, rows []Row) ([]int, [][][]int) {
+	matches := []int{}
+	var highlights [][][]int
+	values := make([]string, len(f.cols))
+	for i, row := range rows {
+		if ctx.Err() != nil {
+			return nil, nil
+		}
+		for j, c := range f.cols {
+			values[j] = ""
+			if c >= 0 && c < len(row) {
+				values[j] = row[c]
+			}
+		}
+		runes, ok := f.filter(f.term, values)
+		if !ok {
+			continue
+		}
+		cells := make([][]int, f.ncols)
+		for j, c := range f.cols {
+			if j < len(runes) && c >= 0 && c < len(cells) {
+				cells[c] = runes[j]
+			}
+		}
+		matches = append(matches, i)
+		highlights = append(highlights, cells)
+	}
+	return matches, highlights
+}
+
+// scrollToCursor renders the rows and scrolls the viewport so the selected
+// row is in view.
+func (m *Model) scrollToCursor() {
+	m.UpdateViewport()
+	line := m.cursor - m.start
+	switch {
+	case line < m.viewport.YOffset:
+		m.viewport.SetYOffset(max(0, line))
+	case line >= m.viewport.YOffset+m.viewport.Height:
+		m.viewport.SetYOffset(line - m.viewport.Height + 1)
+	}
+}
+
+// cellHighlights returns the indexes of the runes of a cell matching the
+// filter.
+func (m Model) cellHighlights(row, col int) []int {
+	if row >= len(m.highlights) || col >= len(m.highlights[row]) {
+		return nil
+	}
+	return m.highlights[row][col]
+}
+
+// highlight styles the runes of a cell matching the filter. The rest of the
+// cell is styled with base, the style of the cell, and like its row, since
+// the matched runes' styling resets it.
+func (m Model) highlight(value string, matches []int, selected bool, base lipgloss.Style) string {
+	unmatched := base.Inline(true).Inherit(m.styles.Cell)
+	if selected {
+		unmatched = unmatched.Inherit(m.styles.Selected)
+	}
+	matched := m.styles.FilterMatch.Inline(true).Inherit(unmatched)
+	return lipgloss.StyleRunes(value, matches, matched, unmatched)
+}
@@ -0,0 +1,94 @@
+package table
+
+// SetFrozenFirstColumn sets whether the first column stays in place, such as
+// an ID column, while the PanLeft and PanRight keybindings pan the remaining
+// columns one at a time. The header, footer and selected row pan along with
+// the rows.
+func (m *Model) SetFrozenFirstColumn(v bool) {
+	m.setFrozen(v)
+	m.UpdateViewport()
+}
+
+func (m *Model) setFrozen(v bool) {
+	m.frozen = v
+	m.colOffset = 0
+	m.KeyMap.PanLeft.SetEnabled(v)
+	m.KeyMap.PanRight.SetEnabled(v)
+}
+
+// FrozenFirstColumn returns whether the first column stays in place while
+// the others are panned.
+func (m Model) FrozenFirstColumn() bool {
+	return m.frozen
+}
+
+// ColumnOffset returns the number of columns after the frozen first column
+// that have been panned past.
+func (m Model) ColumnOffset() int {
+	return m.colOffset
+}
+
+// PanLeft brings the previous column after the frozen first column back into
+// view.
+func (m *Model) PanLeft() {
+	if !m.frozen {
+		return
+	}
+	m.colOffset = max(0, m.colOffset-1)
+	m.UpdateViewport()
+}
+
+// PanRight pans past the first column after the frozen first column. It stops
+// once the last column is in view.
+func (m *Model) PanRight() {
+	if !m.frozen || m.lastColumnVisible() {
+		return
+	}
+	m.colOffset++
+	m.UpdateViewport()
+}
+
+// visibleColumns returns the indexes of the columns to render, in display
+// order. With a frozen first column these are the first column and as many
+// of the columns after the panned ones as fit in the table's width, always
+// including at least one.
+func (m Model) visibleColumns() []int {
+	cols := make([]int, 0, len(m.cols))
+	for _, i := range m.columnOrder() {
+		if m.cols[i].Width > 0 && !m.cols[i].Hidden {
+			cols = append(cols, i)
+		}
+	}
+	if !m.frozen || len(cols) < 2 { //nolint:mnd
+		return cols
+	}
+
+	panned := cols[1+min(m.colOffset, len(cols)-2):]
+	visible := []int{cols[0]}
+	width := m.cellWidth(cols[0])
+	for _, i := range panned {
+		width += m.cellWidth(i)
+		if m.viewport.Width > 0 && width > m.viewport.Width && len(visible) > 1 {
+			break
+		}
+		visible = append(visible, i)
+	}
+	return visible
+}
+
+// lastColumnVisible reports whether the last column is being rendered.
+func (m Model) lastColumnVisible() bool {
+	visible := m.visibleColumns()
+	last := -1
+	for _, i := range m.columnOrder() {
+		if m.cols[i].Width > 0 && !m.cols[i].Hidden {
+			last = i
+		}
+	}
+	return len(visible) == 0 || visible[len(visible)-1] == last
+}
+
+// cellWidth returns the width a column takes up, including the cell padding.
+func (m Model) cellWidth(i int) int {
+	return m.cols[i].Width + m.styles.Cell.GetHorizontalFrameSize()
+}
@@ -0,0 +1,309 @@
+package table
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/textutil"
+)
+
+// GroupFunc returns the key of the group a row belongs to. Rows with the same
+// key are shown together under a header, in the order their first row
+// appears.
+type GroupFunc func(row Row) string
+
+// AggregateFunc computes the cells of a group's header from the rows in the
+// group, for example to show totals. The first shown column of a header
+// always holds the group's key and the number of rows in it.
+type AggregateFunc func(key string, rows []Row) Row
+
+// rowGroup is a group of rows, as indexes into the visible rows.
+type rowGroup struct {
+	key  string
+	rows []int
+}
+
+// groupLine is a line of a grouped table: either a group's header, when row
+// is -1, or one of the visible rows.
+type groupLine struct {
+	group int
+	row   int
+}
+
+// WithGroupFunc groups the rows by the given function. See SetGroupFunc.
+func WithGroupFunc(f GroupFunc) Option {
+	return func(m *Model) {
+		m.groupFunc = f
+		m.updateGroupKeys()
+	}
+}
+
+// WithAggregateFunc sets the function computing the cells of group headers.
+// See SetAggregateFunc.
+func WithAggregateFunc(f AggregateFunc) Option {
+	return func(m *Model) {
+		m.aggregateFunc = f
+	}
+}
+
+// SetGroupFunc groups the rows by the given function, showing each group
+// under a header which can be selected and collapsed. Passing nil removes
+// the grouping.
+//
+// While rows are grouped, Cursor returns -1 when a header is selected, and
+// SelectedRow returns nil. See SelectedGroup.
+func (m *Model) SetGroupFunc(f GroupFunc) {
+	row := m.Cursor()
+	m.groupFunc = f
+	m.updateGroupKeys()
+	if f == nil {
+		m.groups, m.lines = nil, nil
+		m.cursor = clamp(row, 0, len(m.rows)-1)
+		m.scrollToCursor()
+		return
+	}
+	m.groupRows(row, "")
+	m.scrollToCursor()
+}
+
+// SetAggregateFunc sets the function computing the cells of group headers.
+// Passing nil leaves them empty but for the key and count.
+func (m *Model) SetAggregateFunc(f AggregateFunc) {
+	m.aggregateFunc = f
+	m.UpdateViewport()
+}
+
+// Groups returns the keys of the groups, in the order they're shown.
+func (m Model) Groups() []string {
+	keys := make([]string, len(m.groups))
+	for i, g := range m.groups {
+		keys[i] = g.key
+	}
+	return keys
+}
+
+// SelectedGroup returns the key of the group the selection is in, whether
+// it's the group's header or one of its rows. It returns false if the rows
+// aren't grouped.
+func (m Model) SelectedGroup() (string, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.lines) {
+		return "", false
+	}
+	return m.groups[m.lines[m.cursor].group].key, true
+}
+
+// GroupCollapsed returns whether the group with the given key is collapsed.
+func (m Model) GroupCollapsed(key string) bool {
+	return m.collapsed[key]
+}
+
+// SetGroupCollapsed collapses or expands the group with the given key. A
+// collapsed group only shows its header. If the selected row is in a group
+// being collapsed, its header is selected instead.
+func (m *Model) SetGroupCollapsed(key string, collapsed bool) {
+	states := maps.Clone(m.collapsed)
+	if states == nil {
+		states = make(map[string]bool)
+	}
+	states[key] = collapsed
+	m.setCollapsed(states)
+}
+
+// SetAllGroupsCollapsed collapses or expands all groups, including any which
+// appear later as rows change.
+func (m *Model) SetAllGroupsCollapsed(collapsed bool) {
+	states := make(map[string]bool, len(m.groups))
+	for _, g := range m.groups {
+		states[g.key] = collapsed
+	}
+	m.collapseNew = collapsed
+	m.setCollapsed(states)
+}
+
+// ToggleGroup collapses the selected group, or expands it if it's collapsed.
+func (m *Model) ToggleGroup() {
+	if key, ok := m.SelectedGroup(); ok {
+		m.SetGroupCollapsed(key, !m.collapsed[key])
+	}
+}
+
+// ToggleAllGroups collapses all groups, or expands them if they're all
+// collapsed already.
+func (m *Model) ToggleAllGroups() {
+	for _, g := range m.groups {
+		if !m.collapsed[g.key] {
+			m.SetAllGroupsCollapsed(true)
+			return
+		}
+	}
+	m.SetAllGroupsCollapsed(false)
+}
+
+// NextGroup selects the header of the group after the selected one.
+func (m *Model) NextGroup() {
+	for i := m.cursor + 1; i < len(m.lines); i++ {
+		if m.lines[i].row < 0 {
+			m.MoveDown(i - m.cursor)
+			return
+		}
+	}
+}
+
+// PrevGroup selects the header of the selected group, or of the group before
+// it if the header is selected already.
+func (m *Model) PrevGroup() {
+	for i := min(m.cursor, len(m.lines)) - 1; i >= 0; i-- {
+		if m.lines[i].row < 0 {
+			m.MoveUp(m.cursor - i)
+			return
+		}
+	}
+}
+
+// setCollapsed sets whether each group is collapsed, keeping the selection
+// in the same group.
+func (m *Model) setCollapsed(states map[string]bool) {
+	row := m.Cursor()
+	key, _ := m.SelectedGroup()
+	m.collapsed = states
+	m.groupRows(row, key)
+	m.scrollToCursor()
+}
+
+// updateGroupKeys enables the group keybindings if the rows are grouped.
+func (m *Model) updateGroupKeys() {
+	grouped := m.groupFunc != nil
+	m.KeyMap.ToggleGroup.SetEnabled(grouped)
+	m.KeyMap.ToggleAllGroups.SetEnabled(grouped)
+	m.KeyMap.PrevGroup.SetEnabled(grouped)
+	m.KeyMap.NextGroup.SetEnabled(grouped)
+}
+
+// groupRows groups the visible rows and lays out the lines showing them,
+// selecting the line of the visible row at index row, or if it's -1 or in
+// a collapsed group, the header of its group or of the group with the given
+// key.
+func (m *Model) groupRows(row int, key string) {
+	if m.groupFunc == nil {
+		return
+	}
+
+	index := make(map[string]int)
+	m.groups = nil
+	for i, r := range m.rows {
+		k := m.groupFunc(r)
+		g, ok := index[k]
+		if !ok {
+			g = len(m.groups)
+			index[k] = g
+			m.groups = append(m.groups, rowGroup{key: k})
+			if _, ok := m.collapsed[k]; !ok && m.collapseNew {
+				m.collapsed = maps.Clone(m.collapsed)
+				if m.collapsed == nil {
+					m.collapsed = make(map[string]bool)
+				}
+				m.collapsed[k] = true
+			}
+		}
+		m.groups[g].rows = append(m.groups[g].rows, i)
+		if i == row {
+			key = k
+		}
+	}
+
+	m.lines = make([]groupLine, 0, len(m.groups)+len(m.rows))
+	m.cursor = 0
+	for g, group := range m.groups {
+		if group.key == key {
+			m.cursor = len(m.lines)
+		}
+		m.lines = append(m.lines, groupLine{group: g, row: -1})
+		if m.collapsed[group.key] {
+			continue
+		}
+		for _, r := range group.rows {
+			if r == row {
+				m.cursor = len(m.lines)
+			}
+			m.lines = append(m.lines, groupLine{group: g, row: r})
+		}
+	}
+	if len(m.lines) == 0 {
+		m.cursor = -1
+	}
+}
+
+// regroup groups the visible rows again after they've changed, selecting
+// the row at the cursor, or if a header was selected, the header of the
+// group with the given key.
+func (m *Model) regroup(onRow bool, key string) {
+	row := m.cursor
+	if !onRow {
+		row = -1
+	}
+	m.groupRows(row, key)
+}
+
+// lineCount returns the number of lines the cursor moves over: the visible
+// rows, with the headers of their groups if they're grouped.
+func (m Model) lineCount() int {
+	if m.groupFunc != nil {
+		return len(m.lines)
+	}
+	return len(m.rows)
+}
+
+// rowAt returns the index in the visible rows of the row shown on a line, or
+// -1 if there's none, such as for a group header.
+func (m Model) rowAt(line int) int {
+	switch {
+	case m.groupFunc == nil:
+		if line < 0 || line >= len(m.rows) {
+			return -1
+		}
+		return line
+	case line < 0 || line >= len(m.lines):
+		return -1
+	default:
+		return m.lines[line].row
+	}
+}
+
+// groupHeaderView renders the header of a group.
+func (m Model) groupHeaderView(g int, selected bool) string {
+	group := m.groups[g]
+	var cells Row
+	if m.aggregateFunc != nil {
+		rows := make([]Row, len(group.rows))
+		for i, r := range group.rows {
+			rows[i] = m.rows[r]
+		}
+		cells = m.aggregateFunc(group.key, rows)
+	}
+
+	marker := "▾"
+	if m.collapsed[group.key] {
+		marker = "▸"
+	}
+
+	s := make([]string, 0, len(m.cols))
+	for j, i := range m.visibleColumns() {
+		var value string
+		switch {
+		case j == 0:
+			value = fmt.Sprintf("%s %s (%d)", marker, group.key, len(group.rows))
+		case i < len(cells):
+			value = cells[i]
+		}
+		style := lipgloss.NewStyle().Width(m.cols[i].Width).MaxWidth(m.cols[i].Width).Inline(true)
+		renderedCell := style.Render(textutil.Truncate(value, m.cols[i].Width, textutil.Ellipsis))
+		s = append(s, m.styles.GroupHeader.Render(renderedCell))
+	}
+
+	row := lipgloss.JoinHorizontal(lipgloss.Top, s...)
+	if selected {
+		return m.styles.Selected.Render(row)
+	}
+	return row
+}
@@ -0,0 +1,158 @@
+package table
+
+import "slices"
+
+// Layout is the order and visibility of a table's columns, identified by
+// their titles. It's meant to be saved to an app's config, such as by
+// encoding it with encoding/json, and given back to the table with SetLayout.
+type Layout struct {
+	Order  []string `json:"order"`
+	Hidden []string `json:"hidden,omitempty"`
+}
+
+// Layout returns the current order and visibility of the columns.
+func (m Model) Layout() Layout {
+	var l Layout
+	for _, i := range m.columnOrder() {
+		l.Order = append(l.Order, m.cols[i].Title)
+		if m.cols[i].Hidden {
+			l.Hidden = append(l.Hidden, m.cols[i].Title)
+		}
+	}
+	return l
+}
+
+// SetLayout reorders and hides columns as described by a Layout. Columns the
+// layout doesn't mention keep their relative order after those it does, and
+// titles that don't match a column are ignored, so a saved layout keeps
+// working as columns are added and removed.
+func (m *Model) SetLayout(l Layout) {
+	order := make([]int, 0, len(m.cols))
+	for _, title := range l.Order {
+		for i, col := range m.cols {
+			if col.Title == title && !slices.Contains(order, i) {
+				order = append(order, i)
+				break
+			}
+		}
+	}
+	for i := range m.cols {
+		if !slices.Contains(order, i) {
+			order = append(order, i)
+		}
+	}
+
+	m.cols = slices.Clone(m.cols)
+	for i := range m.cols {
+		m.cols[i].Hidden = slices.Contains(l.Hidden, m.cols[i].Title)
+	}
+	m.order = order
+	m.colCursor = clamp(m.colCursor, 0, len(order)-1)
+	m.UpdateViewport()
+}
+
+// ColumnOrder returns the indexes of the columns in the order they're
+// displayed, including hidden ones.
+func (m Model) ColumnOrder() []int {
+	return slices.Clone(m.columnOrder())
+}
+
+// SetColumnVisible shows or hides the column at the given index, as given
+// to SetColumns.
+func (m *Model) SetColumnVisible(index int, visible bool) {
+	if index < 0 || index >= len(m.cols) {
+		return
+	}
+	m.cols = slices.Clone(m.cols)
+	m.cols[index].Hidden = !visible
+	m.UpdateViewport()
+}
+
+// ColumnVisible returns whether the column at the given index is shown.
+func (m Model) ColumnVisible(index int) bool {
+	return index >= 0 && index < len(m.cols) && !m.cols[index].Hidden
+}
+
+// MoveColumn moves the column displayed at position from to position to,
+// shifting the columns in between. Positions count hidden columns too.
+func (m *Model) MoveColumn(from, to int) {
+	order := slices.Clone(m.columnOrder())
+	if from < 0 || from >= len(order) {
+		return
+	}
+	to = clamp(to, 0, len(order)-1)
+	i := order[from]
+	order = slices.Insert(slices.Delete(order, from, from+1), to, i)
+	m.order = order
+	if m.colCursor == from {
+		m.colCursor = to
+	}
+	m.UpdateViewport()
+}
+
+// SetColumnEditing enables or disables the keybindings to select, move, hide
+// and show columns. While enabled, the header of the selected column is
+// highlighted.
+func (m *Model) SetColumnEditing(v bool) {
+	m.columnEditing = v
+	m.KeyMap.PrevColumn.SetEnabled(v)
+	m.KeyMap.NextColumn.SetEnabled(v)
+	m.KeyMap.MoveColumnLeft.SetEnabled(v)
+	m.KeyMap.MoveColumnRight.SetEnabled(v)
+	m.KeyMap.HideColumn.SetEnabled(v)
+	m.KeyMap.ShowAllColumns.SetEnabled(v)
+	m.UpdateViewport()
+}
+
+// ColumnCursor returns the display position of the selected column.
+func (m Model) ColumnCursor() int {
+	return m.colCursor
+}
+
+// moveColumnCursor selects the next shown column in the given direction.
+func (m *Model) moveColumnCursor(dir int) {
+	order := m.columnOrder()
+	for p := m.colCursor + dir; p >= 0 && p < len(order); p += dir {
+		if !m.cols[order[p]].Hidden {
+			m.colCursor = p
+			return
+		}
+	}
+}
+
+// hideSelectedColumn hides the selected column, unless it's the last one
+// shown, and selects the next shown one.
+func (m *Model) hideSelectedColumn() {
+	order := m.columnOrder()
+	if m.colCursor >= len(order) || len(m.visibleColumns()) < 2 { //nolint:mnd
+		return
+	}
+	m.SetColumnVisible(order[m.colCursor], false)
+	prev := m.colCursor
+	m.moveColumnCursor(1)
+	if m.colCursor == prev {
+		m.moveColumnCursor(-1)
+	}
+}
+
+// showAllColumns shows every hidden column.
+func (m *Model) showAllColumns() {
+	m.cols = slices.Clone(m.cols)
+	for i := range m.cols {
+		m.cols[i].Hidden = false
+	}
+	m.UpdateViewport()
+}
+
+// columnOrder returns the display order of the columns, which is their
+// natural order until they're moved.
+func (m Model) columnOrder() []int {
+	if len(m.order) == len(m.cols) {
+		return m.order
+	}
+	order := make([]int, len(m.cols))
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
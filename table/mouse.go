@@ -0,0 +1,118 @@
+package table
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// defaultMouseWheelDelta is the number of rows the mouse wheel moves by
+// default.
+const defaultMouseWheelDelta = 3
+
+// HeaderClickedMsg is sent when a column's header is clicked, such as for
+// an app to sort the rows by the column.
+type HeaderClickedMsg struct {
+	// Column is the index of the column in Columns.
+	Column int
+}
+
+// WithMouseWheel sets whether the mouse wheel moves the selection, and by
+// how many rows. See MouseWheelEnabled.
+func WithMouseWheel(enabled bool, delta int) Option {
+	return func(m *Model) {
+		m.MouseWheelEnabled = enabled
+		m.MouseWheelDelta = delta
+	}
+}
+
+// updateMouse handles mouse events over the table. The wheel moves the
+// selection through the rows under the header, which stays in place, a
+// click on a row selects it, and a click on a header sends a
+// HeaderClickedMsg.
+func (m *Model) updateMouse(msg tea.MouseMsg) tea.Cmd {
+	if msg.Action != tea.MouseActionPress {
+		return nil
+	}
+	x, y := msg.X-m.X, msg.Y-m.Y
+
+	switch msg.Button { //nolint:exhaustive
+	case tea.MouseButtonWheelUp:
+		if m.MouseWheelEnabled && m.overBody(x, y) {
+			m.MoveUp(m.MouseWheelDelta)
+		}
+	case tea.MouseButtonWheelDown:
+		if m.MouseWheelEnabled && m.overBody(x, y) {
+			m.MoveDown(m.MouseWheelDelta)
+		}
+	case tea.MouseButtonLeft:
+		if line, ok := m.lineAt(x, y); ok {
+			m.moveTo(line)
+			return nil
+		}
+		if col, ok := m.headerAt(x, y); ok {
+			return func() tea.Msg {
+				return HeaderClickedMsg{Column: col}
+			}
+		}
+	}
+	return nil
+}
+
+// bodyTop returns the row of the table on which the rows start, below the
+// filter input and the header.
+func (m Model) bodyTop() int {
+	top := lipgloss.Height(m.headersView())
+	if m.filterState != Unfiltered {
+		top += lipgloss.Height(m.filterView())
+	}
+	return top
+}
+
+// overBody reports whether a position relative to the table is over the
+// rows.
+func (m Model) overBody(x, y int) bool {
+	top := m.bodyTop()
+	return x >= 0 && (m.viewport.Width <= 0 || x < m.viewport.Width) &&
+		y >= top && y < top+m.viewport.Height
+}
+
+// lineAt returns the line shown at a position relative to the table.
+func (m Model) lineAt(x, y int) (int, bool) {
+	if !m.overBody(x, y) {
+		return 0, false
+	}
+	line := m.start + m.viewport.YOffset + y - m.bodyTop()
+	if line >= m.end {
+		return 0, false
+	}
+	return line, true
+}
+
+// headerAt returns the index of the column whose header is at a position
+// relative to the table.
+func (m Model) headerAt(x, y int) (int, bool) {
+	top := 0
+	if m.filterState != Unfiltered {
+		top = lipgloss.Height(m.filterView())
+	}
+	if x < 0 || y < top || y >= top+lipgloss.Height(m.headersView()) {
+		return 0, false
+	}
+	frame := m.styles.Header.GetHorizontalFrameSize()
+	for _, i := range m.visibleColumns() {
+		if x < m.cols[i].Width+frame {
+			return i, true
+		}
+		x -= m.cols[i].Width + frame
+	}
+	return 0, false
+}
+
+// moveTo moves the selection to a line.
+func (m *Model) moveTo(line int) {
+	if line < m.cursor {
+		m.MoveUp(m.cursor - line)
+	} else if line > m.cursor {
+		m.MoveDown(line - m.cursor)
+	}
+}
@@ -0,0 +1,176 @@
+package table
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PageChangedMsg is sent when the user moves a paged table to another page,
+// such as for an app to fetch the page's rows. See SetPageSize.
+type PageChangedMsg struct {
+	// Page is the index of the new page, starting at 0.
+	Page int
+
+	// PerPage is the number of rows on each page.
+	PerPage int
+}
+
+// WithPageSize shows the rows n at a time. See SetPageSize.
+func WithPageSize(n int) Option {
+	return func(m *Model) {
+		m.setPageSize(n)
+	}
+}
+
+// SetPageSize shows the rows n rows per page, with the Paginator rendered
+// beneath them, rather than scrolling through them continuously. Moving the
+// cursor past the first or last row of a page turns the page, and the PageUp
+// and PageDown keybindings move a page at a time. Update returns a command
+// sending PageChangedMsg whenever the user turns the page. Pages taller than
+// the table scroll to keep the selected row in view.
+//
+// Passing 0 goes back to scrolling continuously.
+func (m *Model) SetPageSize(n int) {
+	m.setPageSize(n)
+	m.UpdateViewport()
+}
+
+func (m *Model) setPageSize(n int) {
+	prev := m.paginationHeight()
+	m.pageSize = max(0, n)
+	m.viewport.Height -= m.paginationHeight() - prev
+	m.viewport.YOffset = 0
+	if m.pageSize == 0 {
+		m.Paginator.Page = 0
+	}
+}
+
+// PageSize returns the number of rows on each page, or 0 if the table isn't
+// paged.
+func (m Model) PageSize() int {
+	return m.pageSize
+}
+
+// SetTotalRows sets the number of rows across all pages of a paged table
+// whose rows are fetched a page at a time, such as from an API. The rows set
+// with SetRows are then those of the current page only, and the cursor is
+// their index on it. The app fetches the rows of a page when it receives
+// PageChangedMsg, until which the previous page's rows are shown.
+//
+// Passing 0 goes back to paging through all the rows set with SetRows.
+func (m *Model) SetTotalRows(n int) {
+	m.totalRows = max(0, n)
+	m.UpdateViewport()
+}
+
+// TotalRows returns the number of rows across all pages set with
+// SetTotalRows.
+func (m Model) TotalRows() int {
+	return m.totalRows
+}
+
+// Page returns the index of the current page of a paged table, starting
+// at 0.
+func (m Model) Page() int {
+	return m.Paginator.Page
+}
+
+// SetPage moves to the first row of a page of a paged table. It doesn't send
+// PageChangedMsg: an app fetching its rows fetches those of the new page
+// itself.
+func (m *Model) SetPage(page int) {
+	if m.pageSize == 0 {
+		return
+	}
+	if m.totalRows > 0 {
+		m.Paginator.Page = page
+		m.cursor = 0
+		m.UpdateViewport()
+		return
+	}
+	m.cursor = clamp(page*m.pageSize, 0, m.lineCount()-1)
+	m.UpdateViewport()
+}
+
+// PageCount returns the number of pages of a paged table.
+func (m Model) PageCount() int {
+	return m.Paginator.TotalPages
+}
+
+// paginationHeight returns the height taken up by the paginator.
+func (m Model) paginationHeight() int {
+	if m.pageSize == 0 {
+		return 0
+	}
+	return 1
+}
+
+// pageHeight returns the number of lines the PageUp and PageDown
+// keybindings move by.
+func (m Model) pageHeight() int {
+	if m.pageSize > 0 {
+		return m.pageSize
+	}
+	return m.viewport.Height
+}
+
+// movePage moves the cursor of a paged table by n lines, turning the page if
+// it leaves the current one.
+func (m *Model) movePage(n int) {
+	if m.totalRows == 0 {
+		m.cursor = clamp(m.cursor+n, 0, m.lineCount()-1)
+		m.UpdateViewport()
+		return
+	}
+
+	line := clamp(m.Paginator.Page*m.pageSize+m.cursor+n, 0, m.totalRows-1)
+	page := line / m.pageSize
+	m.cursor = line - page*m.pageSize
+	if page == m.Paginator.Page {
+		m.cursor = min(m.cursor, m.lineCount()-1)
+	}
+	m.Paginator.Page = page
+	m.UpdateViewport()
+}
+
+// updatePage renders the lines of the current page, scrolling the viewport
+// so the selected row is in view.
+func (m *Model) updatePage() {
+	total := m.lineCount()
+	if m.totalRows > 0 {
+		total = m.totalRows
+	} else {
+		m.Paginator.Page = max(0, m.cursor) / m.pageSize
+	}
+	m.Paginator.PerPage = m.pageSize
+	m.Paginator.TotalPages = max(1, (total+m.pageSize-1)/m.pageSize)
+	m.Paginator.Page = clamp(m.Paginator.Page, 0, m.Paginator.TotalPages-1)
+
+	m.start, m.end = 0, min(m.pageSize, m.lineCount())
+	if m.totalRows == 0 {
+		m.start, m.end = m.Paginator.GetSliceBounds(m.lineCount())
+	}
+	rendered := make([]string, 0, m.end-m.start)
+	for i := m.start; i < m.end; i++ {
+		rendered = append(rendered, m.renderRow(i))
+	}
+	m.viewport.SetContent(lipgloss.JoinVertical(lipgloss.Left, rendered...))
+
+	line := m.cursor - m.start
+	offset := clamp(m.viewport.YOffset, line-m.viewport.Height+1, line)
+	m.viewport.SetYOffset(max(0, offset))
+}
+
+// paginationView renders the paginator beneath the rows.
+func (m Model) paginationView() string {
+	return m.styles.Pagination.Render(m.Paginator.View())
+}
+
+// pageChanged returns a command sending a PageChangedMsg for the current
+// page.
+func (m Model) pageChanged() tea.Cmd {
+	msg := PageChangedMsg{Page: m.Paginator.Page, PerPage: m.pageSize}
+	return func() tea.Msg {
+		return msg
+	}
+}
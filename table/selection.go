@@ -0,0 +1,136 @@
+package table
+
+// WithRangeSelection sets whether ranges of rows can be selected. See
+// SetRangeSelection.
+func WithRangeSelection(v bool) Option {
+	return func(m *Model) {
+		m.setRangeSelection(v)
+	}
+}
+
+// SetRangeSelection enables or disables selecting ranges of rows for bulk
+// operations, with the ExtendUp, ExtendDown and SelectAll keybindings.
+// Selected rows are drawn with the RangeSelected style, and moving the cursor
+// without extending the selection clears it. See SelectedRows.
+func (m *Model) SetRangeSelection(v bool) {
+	m.setRangeSelection(v)
+	m.UpdateViewport()
+}
+
+func (m *Model) setRangeSelection(v bool) {
+	m.rangeSelection = v
+	m.KeyMap.ExtendUp.SetEnabled(v)
+	m.KeyMap.ExtendDown.SetEnabled(v)
+	m.KeyMap.SelectAll.SetEnabled(v)
+	if !v {
+		m.selection, m.anchor = nil, -1
+	}
+}
+
+// RangeSelection returns whether ranges of rows can be selected.
+func (m Model) RangeSelection() bool {
+	return m.rangeSelection
+}
+
+// ExtendSelection moves the cursor by n rows, down if n is positive and up if
+// it's negative, selecting the rows between it and the row where the
+// selection started.
+func (m *Model) ExtendSelection(n int) {
+	anchor := m.lineOf(m.anchor)
+	if anchor < 0 {
+		anchor = m.cursor
+		m.anchor = m.SourceIndex(m.rowAt(m.cursor))
+	}
+	if n < 0 {
+		m.MoveUp(-n)
+	} else {
+		m.MoveDown(n)
+	}
+
+	m.selection = make(map[int]bool)
+	for line := min(anchor, m.cursor); line <= max(anchor, m.cursor); line++ {
+		if r := m.rowAt(line); r >= 0 {
+			m.selection[m.SourceIndex(r)] = true
+		}
+	}
+	m.UpdateViewport()
+}
+
+// SelectAll selects all rows matching the filter, or all rows if there's no
+// filter, including those in collapsed groups.
+func (m *Model) SelectAll() {
+	m.selection = make(map[int]bool, len(m.rows))
+	for r := range m.rows {
+		m.selection[m.SourceIndex(r)] = true
+	}
+	m.anchor = -1
+	m.UpdateViewport()
+}
+
+// ClearSelection deselects all rows.
+func (m *Model) ClearSelection() {
+	if m.selection == nil && m.anchor < 0 {
+		return
+	}
+	m.selection, m.anchor = nil, -1
+	m.UpdateViewport()
+}
+
+// SelectedRows returns the rows in the selected range, or those selected with
+// SelectAll, in the order they appear in Rows. It returns nil if no rows are
+// selected, in which case SelectedRow is the one to act on.
+func (m Model) SelectedRows() []Row {
+	indexes := m.SelectedIndexes()
+	if indexes == nil {
+		return nil
+	}
+	rows := m.Rows()
+	selected := make([]Row, len(indexes))
+	for i, r := range indexes {
+		selected[i] = rows[r]
+	}
+	return selected
+}
+
+// SelectedIndexes returns the indexes in Rows of the selected rows, in order.
+// See SelectedRows.
+func (m Model) SelectedIndexes() []int {
+	if len(m.selection) == 0 {
+		return nil
+	}
+	var indexes []int
+	for r := range len(m.Rows()) {
+		if m.selection[r] {
+			indexes = append(indexes, r)
+		}
+	}
+	return indexes
+}
+
+// rowSelected returns whether the visible row at index r is selected.
+func (m Model) rowSelected(r int) bool {
+	return m.selection[m.SourceIndex(r)]
+}
+
+// lineOf returns the line showing the row at the given index in Rows, or -1
+// if it isn't shown.
+func (m Model) lineOf(source int) int {
+	if source < 0 {
+		return -1
+	}
+	for line := range m.lineCount() {
+		if r := m.rowAt(line); r >= 0 && m.SourceIndex(r) == source {
+			return line
+		}
+	}
+	return -1
+}
+
+// clearSelectionOnMove deselects the rows before the cursor moves without
+// extending the selection.
+func (m *Model) clearSelectionOnMove() {
+	if !m.rangeSelection || (m.selection == nil && m.anchor < 0) {
+		return
+	}
+	m.selection, m.anchor = nil, -1
+}
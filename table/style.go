@@ -0,0 +1,64 @@
+package table
+
+import "github.com/charmbracelet/lipgloss"
+
+// RowStyleFunc returns the style of a row, given the row and its index in
+// Rows, such as to color rows reporting errors. It's called each time the row
+// is rendered. Row styles are meant for colors and text attributes: they're
+// applied to each cell within its column's width, and to the cell's padding
+// where the Cell style doesn't set its own colors.
+type RowStyleFunc func(row Row, index int) lipgloss.Style
+
+// CellStyleFunc returns the style of a cell, given the index in Rows of its
+// row, the index of its column and its value, such as to highlight values
+// over a threshold. It's called each time the cell is rendered, and the
+// style is applied after the value is truncated to the column's width, over
+// the row's style.
+type CellStyleFunc func(row, col int, value string) lipgloss.Style
+
+// WithRowStyleFunc sets the function styling rows. See SetRowStyleFunc.
+func WithRowStyleFunc(f RowStyleFunc) Option {
+	return func(m *Model) {
+		m.rowStyleFunc = f
+	}
+}
+
+// WithCellStyleFunc sets the function styling cells. See SetCellStyleFunc.
+func WithCellStyleFunc(f CellStyleFunc) Option {
+	return func(m *Model) {
+		m.cellStyleFunc = f
+	}
+}
+
+// SetRowStyleFunc sets the function styling rows, which is applied when they
+// are rendered rather than baked into their values, so that truncation still
+// works. Passing nil removes it.
+func (m *Model) SetRowStyleFunc(f RowStyleFunc) {
+	m.rowStyleFunc = f
+	m.UpdateViewport()
+}
+
+// SetCellStyleFunc sets the function styling cells, which is applied when
+// they are rendered rather than baked into their values, so that truncation
+// still works. Passing nil removes it.
+func (m *Model) SetCellStyleFunc(f CellStyleFunc) {
+	m.cellStyleFunc = f
+	m.UpdateViewport()
+}
+
+// rowStyle returns the style of the visible row at index r.
+func (m Model) rowStyle(r int) lipgloss.Style {
+	if m.rowStyleFunc == nil {
+		return lipgloss.NewStyle()
+	}
+	return m.rowStyleFunc(m.rows[r], m.SourceIndex(r))
+}
+
+// cellStyle returns the style of the cell in the given column of the visible
+// row at index r.
+func (m Model) cellStyle(r, col int) lipgloss.Style {
+	if m.cellStyleFunc == nil || col >= len(m.rows[r]) {
+		return lipgloss.NewStyle()
+	}
+	return m.cellStyleFunc(m.SourceIndex(r), col, m.rows[r][col])
+}
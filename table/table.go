@@ -2,14 +2,20 @@
 package table
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/mattn/go-runewidth"
 
+	"github.com/mikeflynn/bubbles/clipboard"
 	"github.com/mikeflynn/bubbles/help"
 	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/paginator"
+	"github.com/mikeflynn/bubbles/render"
+	"github.com/mikeflynn/bubbles/textinput"
+	"github.com/mikeflynn/bubbles/textutil"
 	"github.com/mikeflynn/bubbles/viewport"
 )
 
@@ -18,6 +24,24 @@ type Model struct {
 	KeyMap KeyMap
 	Help   help.Model
 
+	// CopyFormat is the format rows are copied in, and Clipboard is where
+	// they're copied to. If nil, the system clipboard is used. See
+	// clipboard.NewOSC52 for copying over SSH.
+	CopyFormat ExportFormat
+	Clipboard  clipboard.Clipboard
+
+	// X and Y are the position of the table on screen. They're used to work
+	// out which row or header is under the mouse.
+	X, Y int
+
+	// MouseWheelEnabled sets whether the mouse wheel moves the selection
+	// while over the rows, and MouseWheelDelta by how many rows.
+	MouseWheelEnabled bool
+	MouseWheelDelta   int
+
+	// Paginator shows the page of a paged table. See SetPageSize.
+	Paginator paginator.Model
+
 	cols   []Column
 	rows   []Row
 	cursor int
@@ -27,15 +51,84 @@ type Model struct {
 	viewport viewport.Model
 	start    int
 	end      int
+
+	footer     Row
+	footerFunc FooterFunc
+
+	// frozen keeps the first column in place while the others are panned,
+	// and colOffset is the number of columns panned past.
+	frozen    bool
+	colOffset int
+
+	// order is the display order of the columns, and colCursor the display
+	// position of the column selected for editing.
+	order         []int
+	colCursor     int
+	columnEditing bool
+
+	// While a filter is applied, rows holds the matching rows and unfiltered
+	// all of them. sourceIndex maps each matching row to its index in
+	// unfiltered, and highlights holds the matched runes of each of its
+	// cells.
+	filteringEnabled bool
+	filterState      FilterState
+	filterInput      textinput.Model
+	filterFunc       FilterFunc
+	filterCols       []int
+	unfiltered       []Row
+	sourceIndex      []int
+	highlights       [][][]int
+
+	// While rows are grouped, the cursor moves over lines, which are each
+	// either a group's header or one of the visible rows. collapsed holds
+	// whether groups are collapsed by key, and collapseNew whether groups
+	// not in it yet are.
+	groupFunc     GroupFunc
+	aggregateFunc AggregateFunc
+	groups        []rowGroup
+	lines         []groupLine
+	collapsed     map[string]bool
+	collapseNew   bool
+
+	rowStyleFunc  RowStyleFunc
+	cellStyleFunc CellStyleFunc
+
+	// selection holds the selected rows by their index in Rows, and anchor
+	// the index of the row where the selected range started, or -1.
+	rangeSelection bool
+	selection      map[int]bool
+	anchor         int
+
+	// pageSize is the number of rows on each page, or 0 if the rows scroll
+	// continuously, and totalRows the number of rows across all pages if
+	// they're fetched a page at a time.
+	pageSize  int
+	totalRows int
+
+	// id identifies the table in the results of SortCmd and FilterCmd,
+	// pending is the sort or filter running in the background, if any, and
+	// compareFunc compares values when sorting.
+	id          int
+	pending     pendingJob
+	compareFunc CompareFunc
 }
 
 // Row represents one line in the table.
 type Row []string
 
+// FooterFunc computes the footer row from the table's rows, for example to
+// show totals or counts. It's called each time the table is rendered, so it
+// always reflects the current rows. While a filter is applied, it's given
+// only the matching rows.
+type FooterFunc func(rows []Row) Row
+
 // Column defines the table structure.
 type Column struct {
 	Title string
 	Width int
+
+	// Hidden columns aren't rendered.
+	Hidden bool
 }
 
 // KeyMap defines keybindings. It satisfies to the help.KeyMap interface, which
@@ -49,11 +142,54 @@ type KeyMap struct {
 	HalfPageDown key.Binding
 	GotoTop      key.Binding
 	GotoBottom   key.Binding
+
+	// Pan the columns after a frozen first column. These are only enabled
+	// by SetFrozenFirstColumn.
+	PanLeft  key.Binding
+	PanRight key.Binding
+
+	// Select, move, hide and show columns. These are only enabled by
+	// SetColumnEditing.
+	PrevColumn      key.Binding
+	NextColumn      key.Binding
+	MoveColumnLeft  key.Binding
+	MoveColumnRight key.Binding
+	HideColumn      key.Binding
+	ShowAllColumns  key.Binding
+
+	// Filter the rows. These are only enabled by SetFilteringEnabled.
+	Filter               key.Binding
+	ClearFilter          key.Binding
+	CancelWhileFiltering key.Binding
+	AcceptWhileFiltering key.Binding
+
+	// Collapse and expand groups of rows, and move between them. These are
+	// only enabled by SetGroupFunc.
+	ToggleGroup     key.Binding
+	ToggleAllGroups key.Binding
+	PrevGroup       key.Binding
+	NextGroup       key.Binding
+
+	// Copy the selected row, the selected cell or the whole table to the
+	// clipboard.
+	CopyRow   key.Binding
+	CopyCell  key.Binding
+	CopyTable key.Binding
+
+	// Select a range of rows, or all of them. These are only enabled by
+	// SetRangeSelection.
+	ExtendUp   key.Binding
+	ExtendDown key.Binding
+	SelectAll  key.Binding
+
+	// Cancel a sort or filter running in the background. This is only
+	// enabled while one is, as started by SortCmd or FilterCmd.
+	CancelPending key.Binding
 }
 
 // ShortHelp implements the KeyMap interface.
 func (km KeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{km.LineUp, km.LineDown}
+	return []key.Binding{km.LineUp, km.LineDown, km.Filter, km.ClearFilter, km.AcceptWhileFiltering, km.CancelWhileFiltering, km.CancelPending}
 }
 
 // FullHelp implements the KeyMap interface.
@@ -61,6 +197,13 @@ func (km KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{km.LineUp, km.LineDown, km.GotoTop, km.GotoBottom},
 		{km.PageUp, km.PageDown, km.HalfPageUp, km.HalfPageDown},
+		{km.PanLeft, km.PanRight},
+		{km.PrevColumn, km.NextColumn, km.MoveColumnLeft, km.MoveColumnRight, km.HideColumn, km.ShowAllColumns},
+		{km.Filter, km.ClearFilter, km.AcceptWhileFiltering, km.CancelWhileFiltering},
+		{km.ToggleGroup, km.ToggleAllGroups, km.PrevGroup, km.NextGroup},
+		{km.CopyRow, km.CopyCell, km.CopyTable},
+		{km.ExtendUp, km.ExtendDown, km.SelectAll},
+		{km.CancelPending},
 	}
 }
 
@@ -100,6 +243,118 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("end", "G"),
 			key.WithHelp("G/end", "go to end"),
 		),
+		PanLeft: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "pan left"),
+			key.WithDisabled(),
+		),
+		PanRight: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "pan right"),
+			key.WithDisabled(),
+		),
+		PrevColumn: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "prev column"),
+			key.WithDisabled(),
+		),
+		NextColumn: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next column"),
+			key.WithDisabled(),
+		),
+		MoveColumnLeft: key.NewBinding(
+			key.WithKeys("{"),
+			key.WithHelp("{", "move column left"),
+			key.WithDisabled(),
+		),
+		MoveColumnRight: key.NewBinding(
+			key.WithKeys("}"),
+			key.WithHelp("}", "move column right"),
+			key.WithDisabled(),
+		),
+		HideColumn: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "hide column"),
+			key.WithDisabled(),
+		),
+		ShowAllColumns: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "show all columns"),
+			key.WithDisabled(),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter"),
+			key.WithDisabled(),
+		),
+		ClearFilter: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "clear filter"),
+			key.WithDisabled(),
+		),
+		CancelWhileFiltering: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+			key.WithDisabled(),
+		),
+		AcceptWhileFiltering: key.NewBinding(
+			key.WithKeys("enter", "tab", "up", "down"),
+			key.WithHelp("enter", "apply filter"),
+			key.WithDisabled(),
+		),
+		ToggleGroup: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "toggle group"),
+			key.WithDisabled(),
+		),
+		ToggleAllGroups: key.NewBinding(
+			key.WithKeys("Z"),
+			key.WithHelp("Z", "toggle all groups"),
+			key.WithDisabled(),
+		),
+		PrevGroup: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "prev group"),
+			key.WithDisabled(),
+		),
+		NextGroup: key.NewBinding(
+			key.WithKeys("J"),
+			key.WithHelp("J", "next group"),
+			key.WithDisabled(),
+		),
+		CopyRow: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "copy row"),
+		),
+		CopyCell: key.NewBinding(
+			key.WithKeys("Y"),
+			key.WithHelp("Y", "copy cell"),
+		),
+		CopyTable: key.NewBinding(
+			key.WithKeys("ctrl+y"),
+			key.WithHelp("ctrl+y", "copy table"),
+		),
+		ExtendUp: key.NewBinding(
+			key.WithKeys("shift+up"),
+			key.WithHelp("shift+↑", "extend selection up"),
+			key.WithDisabled(),
+		),
+		ExtendDown: key.NewBinding(
+			key.WithKeys("shift+down"),
+			key.WithHelp("shift+↓", "extend selection down"),
+			key.WithDisabled(),
+		),
+		SelectAll: key.NewBinding(
+			key.WithKeys("ctrl+a"),
+			key.WithHelp("ctrl+a", "select all"),
+			key.WithDisabled(),
+		),
+		CancelPending: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+			key.WithDisabled(),
+		),
 	}
 }
 
@@ -109,6 +364,31 @@ type Styles struct {
 	Header   lipgloss.Style
 	Cell     lipgloss.Style
 	Selected lipgloss.Style
+	Footer   lipgloss.Style
+
+	// SelectedHeader is the header of the column selected while editing
+	// columns.
+	SelectedHeader lipgloss.Style
+
+	// FilterPrompt is the prompt of the filter input, and FilterMatch is
+	// used for the characters of cells matching the filter.
+	FilterPrompt lipgloss.Style
+	FilterMatch  lipgloss.Style
+
+	// GroupHeader is used for the cells of group headers.
+	GroupHeader lipgloss.Style
+
+	// RangeSelected is used for the rows selected with SetRangeSelection,
+	// under the Selected style of the row at the cursor.
+	RangeSelected lipgloss.Style
+
+	// Pagination is used for the paginator of a paged table.
+	Pagination lipgloss.Style
+
+	// Pending is shown after the title of a column while the rows are
+	// sorted by it in the background, or after the first one while they're
+	// filtered. See SortCmd.
+	Pending lipgloss.Style
 }
 
 // DefaultStyles returns a set of default style definitions for this table.
@@ -117,6 +397,24 @@ func DefaultStyles() Styles {
 		Selected: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212")),
 		Header:   lipgloss.NewStyle().Bold(true).Padding(0, 1),
 		Cell:     lipgloss.NewStyle().Padding(0, 1),
+		Footer:   lipgloss.NewStyle().Bold(true).Padding(0, 1),
+
+		SelectedHeader: lipgloss.NewStyle().Bold(true).Padding(0, 1).
+			Foreground(lipgloss.Color("212")).Underline(true),
+
+		FilterPrompt: lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#AD58B4", Dark: "#F793FF"}),
+		FilterMatch:  lipgloss.NewStyle().Underline(true),
+
+		GroupHeader: lipgloss.NewStyle().Bold(true).Padding(0, 1).
+			Foreground(lipgloss.AdaptiveColor{Light: "#AD58B4", Dark: "#F793FF"}),
+
+		RangeSelected: lipgloss.NewStyle().Background(lipgloss.AdaptiveColor{Light: "#DDDADA", Dark: "#3C3C3C"}),
+
+		Pagination: lipgloss.NewStyle().PaddingLeft(1).
+			Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}),
+
+		Pending: lipgloss.NewStyle().PaddingLeft(1).SetString("⋯").
+			Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}),
 	}
 }
 
@@ -133,6 +431,14 @@ type Option func(*Model)
 
 // New creates a new model for the table widget.
 func New(opts ...Option) Model {
+	m := newModel(opts...)
+	m.UpdateViewport()
+	return m
+}
+
+// newModel creates a table with the given options applied, without rendering
+// its rows.
+func newModel(opts ...Option) Model {
 	m := Model{
 		cursor:   0,
 		viewport: viewport.New(0, 20), //nolint:mnd
@@ -140,14 +446,21 @@ func New(opts ...Option) Model {
 		KeyMap: DefaultKeyMap(),
 		Help:   help.New(),
 		styles: DefaultStyles(),
+
+		Paginator: paginator.New(),
+
+		filterInput: newFilterInput(),
+		anchor:      -1,
+		id:          nextID(),
+
+		MouseWheelEnabled: true,
+		MouseWheelDelta:   defaultMouseWheelDelta,
 	}
 
 	for _, opt := range opts {
 		opt(&m)
 	}
-
-	m.UpdateViewport()
-
+	m.groupRows(m.cursor, "")
 	return m
 }
 
@@ -168,7 +481,7 @@ func WithRows(rows []Row) Option {
 // WithHeight sets the height of the table.
 func WithHeight(h int) Option {
 	return func(m *Model) {
-		m.viewport.Height = h - lipgloss.Height(m.headersView())
+		m.viewport.Height = h - lipgloss.Height(m.headersView()) - m.footerHeight() - m.filterHeight() - m.paginationHeight()
 	}
 }
 
@@ -179,6 +492,22 @@ func WithWidth(w int) Option {
 	}
 }
 
+// WithFooter sets a static footer row, which stays visible below the rows
+// while they scroll.
+func WithFooter(r Row) Option {
+	return func(m *Model) {
+		m.setFooter(r, nil)
+	}
+}
+
+// WithFooterFunc sets a function used to compute the footer row, which stays
+// visible below the rows while they scroll.
+func WithFooterFunc(f FooterFunc) Option {
+	return func(m *Model) {
+		m.setFooter(nil, f)
+	}
+}
+
 // WithFocused sets the focus state of the table.
 func WithFocused(f bool) Option {
 	return func(m *Model) {
@@ -186,6 +515,14 @@ func WithFocused(f bool) Option {
 	}
 }
 
+// WithFrozenFirstColumn keeps the first column in place while the others are
+// panned. See SetFrozenFirstColumn.
+func WithFrozenFirstColumn(v bool) Option {
+	return func(m *Model) {
+		m.setFrozen(v)
+	}
+}
+
 // WithStyles sets the table styles.
 func WithStyles(s Styles) Option {
 	return func(m *Model) {
@@ -200,32 +537,142 @@ func WithKeyMap(km KeyMap) Option {
 	}
 }
 
+// NewWithOptions creates a new table like New, but checks the configuration up
+// front, returning an error if it can't be rendered, such as when a row has
+// more cells than there are columns.
+func NewWithOptions(opts ...Option) (Model, error) {
+	m := newModel(opts...)
+	if err := m.validate(); err != nil {
+		return Model{}, err
+	}
+	m.UpdateViewport()
+	return m, nil
+}
+
+// validate returns an error if the table's configuration can't be rendered.
+func (m Model) validate() error {
+	if m.viewport.Width < 0 {
+		return fmt.Errorf("table: invalid width %d", m.viewport.Width)
+	}
+	if m.viewport.Height < 0 {
+		return errors.New("table: height leaves no room for rows after the header and footer")
+	}
+	if m.MouseWheelDelta < 0 {
+		return fmt.Errorf("table: invalid mouse wheel delta %d", m.MouseWheelDelta)
+	}
+	for i, c := range m.cols {
+		if c.Width < 0 {
+			return fmt.Errorf("table: column %d (%q) has negative width %d", i, c.Title, c.Width)
+		}
+	}
+	for i, r := range m.rows {
+		if len(r) > len(m.cols) {
+			return fmt.Errorf("table: row %d has %d cells but there are only %d columns", i, len(r), len(m.cols))
+		}
+	}
+	if len(m.footer) > len(m.cols) {
+		return fmt.Errorf("table: footer has %d cells but there are only %d columns", len(m.footer), len(m.cols))
+	}
+	return nil
+}
+
 // Update is the Bubble Tea update loop.
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	page := m.Paginator.Page
+	m, cmd := m.update(msg)
+	if m.pageSize > 0 && m.Paginator.Page != page {
+		cmd = tea.Batch(cmd, m.pageChanged())
+	}
+	return m, cmd
+}
+
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
+	if m.updatePending(msg) {
+		return m, nil
+	}
 	if !m.focus {
 		return m, nil
 	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if key.Matches(msg, m.KeyMap.CancelPending) {
+			m.CancelPending()
+			return m, nil
+		}
+		if m.filterState == Filtering {
+			return m.updateFiltering(msg)
+		}
+		if key.Matches(msg, m.KeyMap.LineUp, m.KeyMap.LineDown, m.KeyMap.PageUp, m.KeyMap.PageDown,
+			m.KeyMap.HalfPageUp, m.KeyMap.HalfPageDown, m.KeyMap.GotoTop, m.KeyMap.GotoBottom) {
+			m.clearSelectionOnMove()
+		}
 		switch {
+		case key.Matches(msg, m.KeyMap.Filter):
+			return m, m.startFiltering()
+		case key.Matches(msg, m.KeyMap.ClearFilter):
+			m.ResetFilter()
 		case key.Matches(msg, m.KeyMap.LineUp):
 			m.MoveUp(1)
 		case key.Matches(msg, m.KeyMap.LineDown):
 			m.MoveDown(1)
 		case key.Matches(msg, m.KeyMap.PageUp):
-			m.MoveUp(m.viewport.Height)
+			m.MoveUp(m.pageHeight())
 		case key.Matches(msg, m.KeyMap.PageDown):
-			m.MoveDown(m.viewport.Height)
+			m.MoveDown(m.pageHeight())
 		case key.Matches(msg, m.KeyMap.HalfPageUp):
-			m.MoveUp(m.viewport.Height / 2) //nolint:mnd
+			m.MoveUp(m.pageHeight() / 2) //nolint:mnd
 		case key.Matches(msg, m.KeyMap.HalfPageDown):
-			m.MoveDown(m.viewport.Height / 2) //nolint:mnd
+			m.MoveDown(m.pageHeight() / 2) //nolint:mnd
 		case key.Matches(msg, m.KeyMap.GotoTop):
 			m.GotoTop()
 		case key.Matches(msg, m.KeyMap.GotoBottom):
 			m.GotoBottom()
+		case key.Matches(msg, m.KeyMap.PanLeft):
+			m.PanLeft()
+		case key.Matches(msg, m.KeyMap.PanRight):
+			m.PanRight()
+		case key.Matches(msg, m.KeyMap.PrevColumn):
+			m.moveColumnCursor(-1)
+			m.UpdateViewport()
+		case key.Matches(msg, m.KeyMap.NextColumn):
+			m.moveColumnCursor(1)
+			m.UpdateViewport()
+		case key.Matches(msg, m.KeyMap.MoveColumnLeft):
+			m.MoveColumn(m.colCursor, m.colCursor-1)
+		case key.Matches(msg, m.KeyMap.MoveColumnRight):
+			m.MoveColumn(m.colCursor, m.colCursor+1)
+		case key.Matches(msg, m.KeyMap.HideColumn):
+			m.hideSelectedColumn()
+		case key.Matches(msg, m.KeyMap.ShowAllColumns):
+			m.showAllColumns()
+		case key.Matches(msg, m.KeyMap.ExtendUp):
+			m.ExtendSelection(-1)
+		case key.Matches(msg, m.KeyMap.ExtendDown):
+			m.ExtendSelection(1)
+		case key.Matches(msg, m.KeyMap.SelectAll):
+			m.SelectAll()
+		case key.Matches(msg, m.KeyMap.ToggleGroup):
+			m.ToggleGroup()
+		case key.Matches(msg, m.KeyMap.ToggleAllGroups):
+			m.ToggleAllGroups()
+		case key.Matches(msg, m.KeyMap.PrevGroup):
+			m.PrevGroup()
+		case key.Matches(msg, m.KeyMap.NextGroup):
+			m.NextGroup()
+		case key.Matches(msg, m.KeyMap.CopyRow):
+			return m, m.Copy(CopyRow)
+		case key.Matches(msg, m.KeyMap.CopyCell):
+			return m, m.Copy(CopyCell)
+		case key.Matches(msg, m.KeyMap.CopyTable):
+			return m, m.Copy(CopyTable)
+		}
+
+	case tea.MouseMsg:
+		if m.filterState == Filtering {
+			break
 		}
+		return m, m.updateMouse(msg)
 	}
 
 	return m, nil
@@ -251,7 +698,25 @@ func (m *Model) Blur() {
 
 // View renders the component.
 func (m Model) View() string {
-	return m.headersView() + "\n" + m.viewport.View()
+	view := m.headersView() + "\n" + m.viewport.View()
+	if m.filterState != Unfiltered {
+		view = m.filterView() + "\n" + view
+	}
+	if footer := m.Footer(); footer != nil {
+		view += "\n" + m.footerView(footer)
+	}
+	if m.pageSize > 0 {
+		view += "\n" + m.paginationView()
+	}
+	return view
+}
+
+// RenderString renders the table deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	m.SetWidth(width)
+	m.SetHeight(height)
+	return render.View(m.View, width, height)
 }
 
 // HelpView is a helper method for rendering the help menu from the keymap.
@@ -264,6 +729,10 @@ func (m Model) HelpView() string {
 // UpdateViewport updates the list content based on the previously defined
 // columns and rows.
 func (m *Model) UpdateViewport() {
+	if m.pageSize > 0 {
+		m.updatePage()
+		return
+	}
 	renderedRows := make([]string, 0, len(m.rows))
 
 	// Render only rows from: m.cursor-m.viewport.Height to: m.cursor+m.viewport.Height
@@ -274,7 +743,7 @@ func (m *Model) UpdateViewport() {
 	} else {
 		m.start = 0
 	}
-	m.end = clamp(m.cursor+m.viewport.Height, m.cursor, len(m.rows))
+	m.end = clamp(m.cursor+m.viewport.Height, m.cursor, m.lineCount())
 	for i := m.start; i < m.end; i++ {
 		renderedRows = append(renderedRows, m.renderRow(i))
 	}
@@ -284,18 +753,24 @@ func (m *Model) UpdateViewport() {
 	)
 }
 
-// SelectedRow returns the selected row.
+// SelectedRow returns the selected row, which is one of the rows matching the
+// filter if one is applied.
 // You can cast it to your own implementation.
 func (m Model) SelectedRow() Row {
-	if m.cursor < 0 || m.cursor >= len(m.rows) {
+	r := m.rowAt(m.cursor)
+	if r < 0 {
 		return nil
 	}
 
-	return m.rows[m.cursor]
+	return m.rows[r]
 }
 
-// Rows returns the current rows.
+// Rows returns the current rows, including those hidden by a filter. See
+// VisibleRows.
 func (m Model) Rows() []Row {
+	if m.sourceIndex != nil {
+		return m.unfiltered
+	}
 	return m.rows
 }
 
@@ -304,13 +779,29 @@ func (m Model) Columns() []Column {
 	return m.cols
 }
 
-// SetRows sets a new rows state.
+// SetRows sets a new rows state. If a filter is applied, it's applied to the
+// new rows too. Any selected range of rows is cleared, and any sort or filter
+// running in the background canceled.
 func (m *Model) SetRows(r []Row) {
+	if m.Pending() {
+		m.CancelPending()
+	}
+	m.selection, m.anchor = nil, -1
+	if m.sourceIndex != nil {
+		m.unfiltered = r
+		m.applyFilter()
+		return
+	}
+	row := m.Cursor()
+	key, _ := m.SelectedGroup()
 	m.rows = r
 
 	if m.cursor > len(m.rows)-1 {
 		m.cursor = len(m.rows) - 1
 	}
+	if m.groupFunc != nil {
+		m.groupRows(min(row, len(m.rows)-1), key)
+	}
 
 	m.UpdateViewport()
 }
@@ -329,10 +820,50 @@ func (m *Model) SetWidth(w int) {
 
 // SetHeight sets the height of the viewport of the table.
 func (m *Model) SetHeight(h int) {
-	m.viewport.Height = h - lipgloss.Height(m.headersView())
+	m.viewport.Height = h - lipgloss.Height(m.headersView()) - m.footerHeight() - m.filterHeight() - m.paginationHeight()
 	m.UpdateViewport()
 }
 
+// SetFooter sets a static footer row, replacing any footer function. Passing
+// nil removes the footer.
+func (m *Model) SetFooter(r Row) {
+	m.setFooter(r, nil)
+	m.UpdateViewport()
+}
+
+// SetFooterFunc sets a function used to compute the footer row, replacing any
+// static footer. Passing nil removes the footer.
+func (m *Model) SetFooterFunc(f FooterFunc) {
+	m.setFooter(nil, f)
+	m.UpdateViewport()
+}
+
+// setFooter replaces the footer, shrinking or growing the viewport so the
+// table keeps its height.
+func (m *Model) setFooter(r Row, f FooterFunc) {
+	prev := m.footerHeight()
+	m.footer = r
+	m.footerFunc = f
+	m.viewport.Height -= m.footerHeight() - prev
+}
+
+// Footer returns the footer row, computing it if a footer function is set. It
+// returns nil if the table has no footer.
+func (m Model) Footer() Row {
+	if m.footerFunc != nil {
+		return m.footerFunc(m.rows)
+	}
+	return m.footer
+}
+
+// footerHeight returns the height taken up by the footer.
+func (m Model) footerHeight() int {
+	if m.footer == nil && m.footerFunc == nil {
+		return 0
+	}
+	return lipgloss.Height(m.footerView(nil))
+}
+
 // Height returns the viewport height of the table.
 func (m Model) Height() int {
 	return m.viewport.Height
@@ -343,13 +874,23 @@ func (m Model) Width() int {
 	return m.viewport.Width
 }
 
-// Cursor returns the index of the selected row.
+// Cursor returns the index of the selected row. While rows are grouped, it
+// returns -1 if a group header is selected.
 func (m Model) Cursor() int {
+	if m.groupFunc != nil {
+		return m.rowAt(m.cursor)
+	}
 	return m.cursor
 }
 
-// SetCursor sets the cursor position in the table.
+// SetCursor sets the cursor position in the table. While rows are grouped,
+// the header of the row's group is selected if the group is collapsed.
 func (m *Model) SetCursor(n int) {
+	if m.groupFunc != nil {
+		m.groupRows(clamp(n, 0, len(m.rows)-1), "")
+		m.UpdateViewport()
+		return
+	}
 	m.cursor = clamp(n, 0, len(m.rows)-1)
 	m.UpdateViewport()
 }
@@ -357,7 +898,11 @@ func (m *Model) SetCursor(n int) {
 // MoveUp moves the selection up by any number of rows.
 // It can not go above the first row.
 func (m *Model) MoveUp(n int) {
-	m.cursor = clamp(m.cursor-n, 0, len(m.rows)-1)
+	if m.pageSize > 0 {
+		m.movePage(-n)
+		return
+	}
+	m.cursor = clamp(m.cursor-n, 0, m.lineCount()-1)
 	switch {
 	case m.start == 0:
 		m.viewport.SetYOffset(clamp(m.viewport.YOffset, 0, m.cursor))
@@ -372,11 +917,15 @@ func (m *Model) MoveUp(n int) {
 // MoveDown moves the selection down by any number of rows.
 // It can not go below the last row.
 func (m *Model) MoveDown(n int) {
-	m.cursor = clamp(m.cursor+n, 0, len(m.rows)-1)
+	if m.pageSize > 0 {
+		m.movePage(n)
+		return
+	}
+	m.cursor = clamp(m.cursor+n, 0, m.lineCount()-1)
 	m.UpdateViewport()
 
 	switch {
-	case m.end == len(m.rows) && m.viewport.YOffset > 0:
+	case m.end == m.lineCount() && m.viewport.YOffset > 0:
 		m.viewport.SetYOffset(clamp(m.viewport.YOffset-n, 1, m.viewport.Height))
 	case m.cursor > (m.end-m.start)/2 && m.viewport.YOffset > 0:
 		m.viewport.SetYOffset(clamp(m.viewport.YOffset-n, 1, m.cursor))
@@ -388,12 +937,12 @@ func (m *Model) MoveDown(n int) {
 
 // GotoTop moves the selection to the first row.
 func (m *Model) GotoTop() {
-	m.MoveUp(m.cursor)
+	m.MoveUp(m.Paginator.Page*m.pageSize + m.cursor)
 }
 
 // GotoBottom moves the selection to the last row.
 func (m *Model) GotoBottom() {
-	m.MoveDown(len(m.rows))
+	m.MoveDown(max(m.lineCount(), m.totalRows))
 }
 
 // FromValues create the table rows from a simple string. It uses `\n` by
@@ -414,31 +963,79 @@ func (m *Model) FromValues(value, separator string) {
 
 func (m Model) headersView() string {
 	s := make([]string, 0, len(m.cols))
-	for _, col := range m.cols {
-		if col.Width <= 0 {
+	selected := -1
+	if m.columnEditing && m.colCursor < len(m.columnOrder()) {
+		selected = m.columnOrder()[m.colCursor]
+	}
+	pending := m.pendingColumn()
+	for _, i := range m.visibleColumns() {
+		col := m.cols[i]
+		style := lipgloss.NewStyle().Width(col.Width).MaxWidth(col.Width).Inline(true)
+		title := textutil.Truncate(col.Title, col.Width, textutil.Ellipsis)
+		if i == pending {
+			indicator := m.styles.Pending.String()
+			title = textutil.Truncate(col.Title, max(0, col.Width-lipgloss.Width(indicator)), textutil.Ellipsis) + indicator
+		}
+		renderedCell := style.Render(title)
+		if i == selected {
+			s = append(s, m.styles.SelectedHeader.Render(renderedCell))
 			continue
 		}
-		style := lipgloss.NewStyle().Width(col.Width).MaxWidth(col.Width).Inline(true)
-		renderedCell := style.Render(runewidth.Truncate(col.Title, col.Width, "…"))
 		s = append(s, m.styles.Header.Render(renderedCell))
 	}
 	return lipgloss.JoinHorizontal(lipgloss.Top, s...)
 }
 
-func (m *Model) renderRow(r int) string {
+func (m Model) footerView(footer Row) string {
 	s := make([]string, 0, len(m.cols))
-	for i, value := range m.rows[r] {
-		if m.cols[i].Width <= 0 {
-			continue
+	for _, i := range m.visibleColumns() {
+		col := m.cols[i]
+		var value string
+		if i < len(footer) {
+			value = footer[i]
+		}
+		style := lipgloss.NewStyle().Width(col.Width).MaxWidth(col.Width).Inline(true)
+		renderedCell := style.Render(textutil.Truncate(value, col.Width, textutil.Ellipsis))
+		s = append(s, m.styles.Footer.Render(renderedCell))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, s...)
+}
+
+func (m *Model) renderRow(line int) string {
+	selected := line == m.cursor
+	r := line
+	if m.groupFunc != nil {
+		if l := m.lines[line]; l.row < 0 {
+			return m.groupHeaderView(l.group, selected)
+		}
+		r = m.lines[line].row
+	}
+
+	rowStyle := m.rowStyle(r)
+	if m.rowSelected(r) {
+		rowStyle = m.styles.RangeSelected.Inherit(rowStyle)
+	}
+	cellStyle := m.styles.Cell.Inherit(rowStyle)
+	s := make([]string, 0, len(m.cols))
+	for _, i := range m.visibleColumns() {
+		var value string
+		if i < len(m.rows[r]) {
+			value = m.rows[r][i]
+		}
+		value = textutil.Truncate(value, m.cols[i].Width, textutil.Ellipsis)
+		if matches := m.cellHighlights(r, i); len(matches) > 0 {
+			value = m.highlight(value, matches, selected, m.cellStyle(r, i).Inherit(rowStyle))
+		} else if m.cellStyleFunc != nil {
+			value = m.cellStyle(r, i).Inherit(rowStyle).Inline(true).Render(value)
 		}
-		style := lipgloss.NewStyle().Width(m.cols[i].Width).MaxWidth(m.cols[i].Width).Inline(true)
-		renderedCell := m.styles.Cell.Render(style.Render(runewidth.Truncate(value, m.cols[i].Width, "…")))
+		style := rowStyle.Width(m.cols[i].Width).MaxWidth(m.cols[i].Width).Inline(true)
+		renderedCell := cellStyle.Render(style.Render(value))
 		s = append(s, renderedCell)
 	}
 
 	row := lipgloss.JoinHorizontal(lipgloss.Top, s...)
 
-	if r == m.cursor {
+	if selected {
 		return m.styles.Selected.Render(row)
 	}
 
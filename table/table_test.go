@@ -1,11 +1,18 @@
 package table
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/charmbracelet/x/exp/golden"
+	"github.com/mikeflynn/bubbles/clipboard"
+	"github.com/mikeflynn/bubbles/help"
+	"github.com/mikeflynn/bubbles/key"
 )
 
 func TestFromValues(t *testing.T) {
@@ -157,3 +164,699 @@ func TestTableAlignment(t *testing.T) {
 		golden.RequireEqual(t, []byte(got))
 	})
 }
+
+func TestTableFooter(t *testing.T) {
+	rows := []Row{
+		{"Chocolate Digestives", "3"},
+		{"Tim Tams", "5"},
+		{"Hobnobs", "2"},
+		{"Jaffa Cakes", "7"},
+	}
+	total := func(rows []Row) Row {
+		var n int
+		for _, r := range rows {
+			v, _ := strconv.Atoi(r[1])
+			n += v
+		}
+		return Row{fmt.Sprintf("%d biscuits", len(rows)), strconv.Itoa(n)}
+	}
+
+	biscuits := New(
+		WithHeight(4),
+		WithColumns([]Column{
+			{Title: "Name", Width: 20},
+			{Title: "Eaten", Width: 6},
+		}),
+		WithRows(rows),
+		WithFooterFunc(total),
+		WithFocused(true),
+	)
+
+	lines := strings.Split(ansi.Strip(biscuits.View()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected the footer to fit within the table height, got %d lines:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	if want := " 4 biscuits            17     "; lines[len(lines)-1] != want {
+		t.Errorf("expected footer %q, got %q", want, lines[len(lines)-1])
+	}
+
+	// The footer stays put while the rows scroll.
+	biscuits.GotoBottom()
+	lines = strings.Split(ansi.Strip(biscuits.View()), "\n")
+	if !strings.Contains(lines[len(lines)-1], "4 biscuits") {
+		t.Errorf("expected footer to remain visible, got %q", lines[len(lines)-1])
+	}
+
+	// Footers follow the current rows.
+	biscuits.SetRows(rows[:2])
+	if got := biscuits.Footer(); got[0] != "2 biscuits" || got[1] != "8" {
+		t.Errorf("expected footer to be recomputed, got %v", got)
+	}
+
+	biscuits.SetFooter(nil)
+	if biscuits.Height() != 3 {
+		t.Errorf("expected removing the footer to give its line back, got height %d", biscuits.Height())
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	newTable := func() Model {
+		return New(
+			WithColumns([]Column{{Title: "Name", Width: 10}, {Title: "Country", Width: 12}}),
+			WithRows([]Row{{"Tokyo", "Japan"}, {"Delhi", "India"}, {"Shanghai", "China"}}),
+		)
+	}
+
+	t.Run("default", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(newTable(), 26, 5)))
+	})
+	t.Run("scrolled", func(t *testing.T) {
+		m := newTable()
+		m.SetHeight(3)
+		m.MoveDown(2)
+		golden.RequireEqual(t, []byte(RenderString(m, 26, 3)))
+	})
+	t.Run("footer", func(t *testing.T) {
+		m := newTable()
+		m.SetFooter(Row{"Total", "3"})
+		golden.RequireEqual(t, []byte(RenderString(m, 26, 6)))
+	})
+}
+
+func TestNewWithOptions(t *testing.T) {
+	cols := []Column{{Title: "Name", Width: 10}, {Title: "Age", Width: 3}}
+
+	m, err := NewWithOptions(
+		WithColumns(cols),
+		WithRows([]Row{{"Alice", "30"}, {"Bob"}}),
+		WithHeight(5),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(m.Rows()) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(m.Rows()))
+	}
+
+	for name, opts := range map[string][]Option{
+		"negative width":        {WithColumns(cols), WithWidth(-1)},
+		"negative column width": {WithColumns([]Column{{Title: "Name", Width: -1}})},
+		"too many cells":        {WithColumns(cols), WithRows([]Row{{"Alice", "30", "extra"}})},
+		"footer too long":       {WithColumns(cols), WithFooter(Row{"a", "b", "c"})},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewWithOptions(opts...); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestFrozenFirstColumn(t *testing.T) {
+	m := New(
+		WithColumns([]Column{
+			{Title: "ID", Width: 4},
+			{Title: "Name", Width: 8},
+			{Title: "Country", Width: 8},
+			{Title: "Population", Width: 10},
+		}),
+		WithRows([]Row{
+			{"1", "Tokyo", "Japan", "37400068"},
+			{"2", "Delhi", "India", "28514000"},
+		}),
+		WithFrozenFirstColumn(true),
+		WithFocused(true),
+	)
+
+	t.Run("start", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(m, 28, 3)))
+	})
+
+	m.SetWidth(28)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if m.ColumnOffset() != 1 {
+		t.Fatalf("expected to pan past 1 column, got %d", m.ColumnOffset())
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if m.ColumnOffset() != 1 {
+		t.Fatalf("expected panning to stop at the last column, got %d", m.ColumnOffset())
+	}
+	t.Run("panned", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(m, 28, 3)))
+	})
+
+	m.PanLeft()
+	if m.ColumnOffset() != 0 {
+		t.Fatalf("expected to pan back, got %d", m.ColumnOffset())
+	}
+}
+
+func TestColumnLayout(t *testing.T) {
+	m := New(
+		WithColumns([]Column{
+			{Title: "Name", Width: 8},
+			{Title: "Country", Width: 8},
+			{Title: "Population", Width: 10},
+		}),
+		WithRows([]Row{{"Tokyo", "Japan", "37400068"}}),
+		WithFocused(true),
+	)
+	m.SetColumnEditing(true)
+
+	for _, k := range []string{"]", "}", "]", "x"} {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+	want := Layout{Order: []string{"Name", "Population", "Country"}, Hidden: []string{"Country"}}
+	if got := m.Layout(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected layout %v, got %v", want, got)
+	}
+	golden.RequireEqual(t, []byte(RenderString(m, 24, 2)))
+
+	restored := New(WithColumns([]Column{
+		{Title: "Name", Width: 8},
+		{Title: "Country", Width: 8},
+		{Title: "Population", Width: 10},
+		{Title: "Area", Width: 6},
+	}))
+	restored.SetLayout(want)
+	if got := restored.ColumnOrder(); fmt.Sprint(got) != "[0 2 1 3]" {
+		t.Fatalf("expected new columns to follow the saved ones, got %v", got)
+	}
+	if restored.ColumnVisible(1) || !restored.ColumnVisible(3) {
+		t.Fatal("expected only Country to be hidden")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+	if !m.ColumnVisible(1) {
+		t.Fatal("expected all columns to be shown again")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	m := New(
+		WithColumns([]Column{{Title: "City", Width: 10}, {Title: "Country", Width: 10}}),
+		WithRows([]Row{
+			{"Tokyo", "Japan"},
+			{"Delhi", "India"},
+			{"Shanghai", "China"},
+			{"Osaka", "Japan"},
+			{"Dhaka", "Bangladesh"},
+		}),
+		WithHeight(6),
+		WithFiltering(true),
+		WithFocused(true),
+	)
+	m.SetCursor(3)
+
+	typeKeys := func(s string) {
+		for _, r := range s {
+			m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+	visible := func() string {
+		var cities []string
+		for _, r := range m.VisibleRows() {
+			cities = append(cities, r[0])
+		}
+		return strings.Join(cities, ",")
+	}
+
+	typeKeys("/jap")
+	if m.FilterState() != Filtering {
+		t.Fatalf("expected to be filtering, got %s", m.FilterState())
+	}
+	if got := visible(); got != "Tokyo,Osaka" {
+		t.Fatalf("expected rows to be narrowed, got %s", got)
+	}
+	if got := m.SelectedRow()[0]; got != "Osaka" {
+		t.Fatalf("expected the selected row to stay selected, got %s", got)
+	}
+	golden.RequireEqual(t, []byte(RenderString(m, 24, 6)))
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.FilterState() != FilterApplied || m.FilterValue() != "jap" {
+		t.Fatalf("expected the filter to be applied, got %s %q", m.FilterState(), m.FilterValue())
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if got := m.SelectedRow()[0]; got != "Tokyo" || m.SourceIndex(m.Cursor()) != 0 {
+		t.Fatalf("expected to move within the matching rows, got %s", got)
+	}
+	if len(m.Rows()) != 5 {
+		t.Fatalf("expected Rows to include the filtered rows, got %d", len(m.Rows()))
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.FilterState() != Unfiltered || len(m.VisibleRows()) != 5 {
+		t.Fatalf("expected the filter to be cleared, got %s with %d rows", m.FilterState(), len(m.VisibleRows()))
+	}
+	if m.Cursor() != 0 {
+		t.Fatalf("expected Tokyo to stay selected, got row %d", m.Cursor())
+	}
+	if m.Height() != 5 {
+		t.Fatalf("expected the filter input's line to be given back, got height %d", m.Height())
+	}
+
+	m.SetFilterColumns(0)
+	m.SetFilterFunc(FuzzyFilter)
+	m.SetFilter("dk")
+	if got := visible(); got != "Dhaka" {
+		t.Fatalf("expected a fuzzy match on the first column, got %s", got)
+	}
+	m.SetRows(append(m.Rows(), Row{"Dakar", "Senegal"}))
+	if got := visible(); got != "Dhaka,Dakar" {
+		t.Fatalf("expected new rows to be filtered, got %s", got)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	m := New(
+		WithColumns([]Column{{Title: "City", Width: 14}, {Title: "Pop", Width: 5}}),
+		WithRows([]Row{
+			{"Tokyo", "37"},
+			{"Delhi", "32"},
+			{"Osaka", "19"},
+			{"Mumbai", "21"},
+			{"Shanghai", "29"},
+		}),
+		WithHeight(9),
+		WithFocused(true),
+		WithGroupFunc(func(r Row) string {
+			switch r[0] {
+			case "Tokyo", "Osaka":
+				return "Japan"
+			case "Delhi", "Mumbai":
+				return "India"
+			}
+			return "China"
+		}),
+		WithAggregateFunc(func(_ string, rows []Row) Row {
+			total := 0
+			for _, r := range rows {
+				n, _ := strconv.Atoi(r[1])
+				total += n
+			}
+			return Row{"", strconv.Itoa(total)}
+		}),
+	)
+	press := func(s string) {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+	}
+
+	if got := strings.Join(m.Groups(), ","); got != "Japan,India,China" {
+		t.Fatalf("expected groups in order of their first rows, got %s", got)
+	}
+	if got := m.SelectedRow()[0]; got != "Tokyo" || m.Cursor() != 0 {
+		t.Fatalf("expected Tokyo to stay selected, got %s", got)
+	}
+	m.MoveUp(1)
+	if m.Cursor() != -1 || m.SelectedRow() != nil {
+		t.Fatalf("expected the Japan header to be selected, got row %d", m.Cursor())
+	}
+	m.MoveDown(2)
+	if got := m.SelectedRow()[0]; got != "Osaka" || m.Cursor() != 2 {
+		t.Fatalf("expected Osaka after Tokyo, got %s at %d", got, m.Cursor())
+	}
+
+	press("J")
+	if key, _ := m.SelectedGroup(); key != "India" || m.Cursor() != -1 {
+		t.Fatalf("expected the India header to be selected, got %s", key)
+	}
+	press("j")
+	press("z")
+	if !m.GroupCollapsed("India") || m.Cursor() != -1 {
+		t.Fatalf("expected India to be collapsed with its header selected")
+	}
+	golden.RequireEqual(t, []byte(RenderString(m, 24, 9)))
+
+	press("J")
+	press("j")
+	if got := m.SelectedRow()[0]; got != "Shanghai" {
+		t.Fatalf("expected to skip the collapsed rows, got %s", got)
+	}
+	press("K")
+	press("K")
+	if key, _ := m.SelectedGroup(); key != "India" {
+		t.Fatalf("expected to move back to the India header, got %s", key)
+	}
+
+	m.SetCursor(3)
+	if key, _ := m.SelectedGroup(); key != "India" || m.Cursor() != -1 {
+		t.Fatalf("expected a row in a collapsed group to select its header, got %s", key)
+	}
+
+	press("Z")
+	for _, key := range m.Groups() {
+		if !m.GroupCollapsed(key) {
+			t.Fatalf("expected %s to be collapsed", key)
+		}
+	}
+	m.SetRows(append(m.Rows(), Row{"Lagos", "16"}))
+	if got := strings.Join(m.Groups(), ","); got != "Japan,India,China" || len(m.VisibleRows()) != 6 {
+		t.Fatalf("expected Lagos to join China, got %s", got)
+	}
+	if key, _ := m.SelectedGroup(); key != "India" {
+		t.Fatalf("expected the selected header to stay selected, got %s", key)
+	}
+
+	m.SetGroupFunc(nil)
+	if _, ok := m.SelectedGroup(); ok || m.KeyMap.ToggleGroup.Enabled() {
+		t.Fatalf("expected grouping to be removed")
+	}
+}
+
+func TestExport(t *testing.T) {
+	m := New(
+		WithColumns([]Column{{Title: "Name", Width: 10}, {Title: "Note", Width: 10}, {Title: "ID", Width: 4}}),
+		WithRows([]Row{{"alice", "a|b", "1"}, {"bob", "x, y", "2"}}),
+		WithFocused(true),
+	)
+	m.SetColumnVisible(2, false)
+	m.MoveColumn(1, 0)
+
+	if got, want := m.Export(TSV), "Note\tName\na|b\talice\nx, y\tbob"; got != want {
+		t.Errorf("TSV: expected %q, got %q", want, got)
+	}
+	if got, want := m.Export(CSV), "Note,Name\na|b,alice\n\"x, y\",bob"; got != want {
+		t.Errorf("CSV: expected %q, got %q", want, got)
+	}
+	if got, want := m.ExportRow(Markdown), "| Note | Name |\n| --- | --- |\n| a\\|b | alice |"; got != want {
+		t.Errorf("Markdown: expected %q, got %q", want, got)
+	}
+
+	m.SetFilteringEnabled(true)
+	m.SetFilter("bob")
+	if got, want := m.Export(TSV), "Note\tName\nx, y\tbob"; got != want {
+		t.Errorf("expected only the matching rows, got %q", got)
+	}
+
+	var out strings.Builder
+	m.Clipboard = clipboard.NewOSC52(&out)
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Y")})
+	msg, ok := cmd().(CopiedMsg)
+	if !ok || msg.Text != "x, y" || msg.Scope != CopyCell || msg.Err != nil {
+		t.Fatalf("unexpected message %#v", msg)
+	}
+	if want := ansi.SetSystemClipboard("x, y"); out.String() != want {
+		t.Errorf("expected an OSC 52 sequence, got %q", out.String())
+	}
+
+	out.Reset()
+	m.Clipboard = clipboard.Discard
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if msg := cmd().(CopiedMsg); msg.Text != "x, y\tbob" || out.Len() != 0 {
+		t.Errorf("expected the row to be copied without touching the clipboard, got %q", msg.Text)
+	}
+}
+
+func TestMouse(t *testing.T) {
+	rows := make([]Row, 20)
+	for i := range rows {
+		rows[i] = Row{strconv.Itoa(i), "row " + strconv.Itoa(i)}
+	}
+	m := New(
+		WithColumns([]Column{{Title: "ID", Width: 4}, {Title: "Name", Width: 10}}),
+		WithRows(rows),
+		WithHeight(6),
+		WithFocused(true),
+	)
+	m.Y = 2
+	mouse := func(b tea.MouseButton, x, y int) tea.Cmd {
+		var cmd tea.Cmd
+		m, cmd = m.Update(tea.MouseMsg{Action: tea.MouseActionPress, Button: b, X: x, Y: y})
+		return cmd
+	}
+
+	mouse(tea.MouseButtonWheelDown, 1, 4)
+	if m.Cursor() != 3 {
+		t.Fatalf("expected the wheel to move to row 3, got %d", m.Cursor())
+	}
+	mouse(tea.MouseButtonWheelDown, 1, 2)
+	if m.Cursor() != 3 {
+		t.Fatalf("expected the wheel over the header to be ignored, got row %d", m.Cursor())
+	}
+	before := m.headersView()
+	mouse(tea.MouseButtonWheelDown, 1, 4)
+	mouse(tea.MouseButtonWheelDown, 1, 4)
+	if m.Cursor() != 9 || !strings.HasPrefix(m.View(), before) {
+		t.Fatalf("expected row 9 with the header in place, got %d:\n%s", m.Cursor(), m.View())
+	}
+
+	mouse(tea.MouseButtonLeft, 8, 3)
+	if got := m.SelectedRow()[0]; !strings.HasPrefix(strings.Split(m.View(), "\n")[1], " "+got) {
+		t.Fatalf("expected the clicked row %s to be selected:\n%s", got, m.View())
+	}
+
+	cmd := mouse(tea.MouseButtonLeft, 8, 2)
+	if cmd == nil {
+		t.Fatal("expected a header click to send a message")
+	}
+	if msg, ok := cmd().(HeaderClickedMsg); !ok || msg.Column != 1 {
+		t.Fatalf("expected a click on column 1, got %#v", cmd())
+	}
+}
+
+func TestStyleFuncs(t *testing.T) {
+	upper := lipgloss.NewStyle().Transform(strings.ToUpper)
+	flag := lipgloss.NewStyle().Transform(func(s string) string { return "!" + s })
+	m := New(
+		WithColumns([]Column{{Title: "Service", Width: 6}, {Title: "Latency", Width: 5}}),
+		WithRows([]Row{{"api", "12"}, {"worker", "950"}, {"cron", "40"}}),
+		WithHeight(5),
+		WithStyles(Styles{}),
+		WithRowStyleFunc(func(row Row, index int) lipgloss.Style {
+			if row[0] == "worker" {
+				return upper
+			}
+			return lipgloss.NewStyle()
+		}),
+		WithCellStyleFunc(func(row, col int, value string) lipgloss.Style {
+			if n, _ := strconv.Atoi(value); col == 1 && n > 100 {
+				return flag
+			}
+			return lipgloss.NewStyle()
+		}),
+	)
+
+	lines := strings.Split(ansi.Strip(m.View()), "\n")
+	want := []string{"api   12   ", "WORKER!950 ", "cron  40   "}
+	for i, w := range want {
+		if got := lines[i+1]; got != w {
+			t.Errorf("row %d: expected %q, got %q", i, w, got)
+		}
+	}
+
+	// Styles are applied after truncation, so cells keep their width.
+	m.SetRows([]Row{{"scheduler", "12345678"}})
+	if got := ansi.Strip(strings.Split(m.View(), "\n")[1]); ansi.StringWidth(got) != 11 || !strings.HasPrefix(got, "sched…!1234") {
+		t.Errorf("expected the styled cells to be truncated to their columns, got %q", got)
+	}
+
+	// Rows are styled by their index in Rows while filtered.
+	var indexes []int
+	m.SetRows([]Row{{"api", "1"}, {"worker", "2"}, {"cron", "3"}})
+	m.SetRowStyleFunc(func(row Row, index int) lipgloss.Style {
+		indexes = append(indexes, index)
+		return lipgloss.NewStyle()
+	})
+	m.SetFilteringEnabled(true)
+	m.SetFilter("cron")
+	indexes = nil
+	m.UpdateViewport()
+	if len(indexes) != 1 || indexes[0] != 2 {
+		t.Errorf("expected the filtered row to be styled by its source index, got %v", indexes)
+	}
+}
+
+func TestGroupAndRangeKeysDontConflict(t *testing.T) {
+	km := DefaultKeyMap()
+	for _, b := range []*key.Binding{&km.PrevGroup, &km.NextGroup, &km.ExtendUp, &km.ExtendDown} {
+		b.SetEnabled(true)
+	}
+	groupKeys := KeyMap{PrevGroup: km.PrevGroup, NextGroup: km.NextGroup}
+	rangeKeys := KeyMap{ExtendUp: km.ExtendUp, ExtendDown: km.ExtendDown}
+	if conflicts := help.Validate(groupKeys, rangeKeys); len(conflicts) > 0 {
+		t.Fatalf("expected group and range selection keys not to overlap, got %v", conflicts)
+	}
+}
+
+func TestRangeSelection(t *testing.T) {
+	m := New(
+		WithColumns([]Column{{Title: "Name", Width: 10}}),
+		WithRows([]Row{{"alpha"}, {"beta"}, {"gamma"}, {"delta"}, {"epsilon"}}),
+		WithHeight(7),
+		WithFocused(true),
+		WithFiltering(true),
+		WithRangeSelection(true),
+	)
+	names := func() string {
+		var s []string
+		for _, r := range m.SelectedRows() {
+			s = append(s, r[0])
+		}
+		return strings.Join(s, ",")
+	}
+	press := func(k tea.KeyType) {
+		m, _ = m.Update(tea.KeyMsg{Type: k})
+	}
+
+	m.SetCursor(1)
+	press(tea.KeyShiftDown)
+	press(tea.KeyShiftDown)
+	if got := names(); got != "beta,gamma,delta" {
+		t.Fatalf("expected three rows selected down from the cursor, got %q", got)
+	}
+	press(tea.KeyShiftUp)
+	press(tea.KeyShiftUp)
+	press(tea.KeyShiftUp)
+	if got := names(); got != "alpha,beta" {
+		t.Fatalf("expected the selection to extend up from where it started, got %q", got)
+	}
+
+	press(tea.KeyDown)
+	if m.SelectedRows() != nil {
+		t.Fatalf("expected moving the cursor to clear the selection, got %q", names())
+	}
+
+	m.SetFilter("ta")
+	press(tea.KeyCtrlA)
+	if got := names(); got != "beta,delta" {
+		t.Fatalf("expected all rows matching the filter to be selected, got %q", got)
+	}
+	if got := m.SelectedIndexes(); len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Fatalf("expected indexes into Rows, got %v", got)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	rows := make([]Row, 7)
+	for i := range rows {
+		rows[i] = Row{strconv.Itoa(i)}
+	}
+	m := New(
+		WithColumns([]Column{{Title: "N", Width: 4}}),
+		WithRows(rows),
+		WithHeight(5),
+		WithFocused(true),
+		WithPageSize(3),
+	)
+	var pages []int
+	press := func(k tea.KeyType) {
+		var cmd tea.Cmd
+		m, cmd = m.Update(tea.KeyMsg{Type: k})
+		if cmd == nil {
+			return
+		}
+		if msg, ok := cmd().(PageChangedMsg); ok {
+			pages = append(pages, msg.Page)
+		}
+	}
+
+	if m.PageCount() != 3 || m.Height() != 3 {
+		t.Fatalf("expected 3 pages of 3 rows, got %d pages and height %d", m.PageCount(), m.Height())
+	}
+	golden.RequireEqual(t, []byte(m.View()))
+
+	press(tea.KeyDown)
+	press(tea.KeyDown)
+	press(tea.KeyDown)
+	if m.Page() != 1 || m.SelectedRow()[0] != "3" {
+		t.Fatalf("expected moving past the last row of a page to turn it, got page %d", m.Page())
+	}
+	press(tea.KeyPgDown)
+	if m.Page() != 2 || m.SelectedRow()[0] != "6" {
+		t.Fatalf("expected the next page, got page %d and row %v", m.Page(), m.SelectedRow())
+	}
+	press(tea.KeyHome)
+	if m.Page() != 0 || m.Cursor() != 0 {
+		t.Fatalf("expected the first page, got page %d", m.Page())
+	}
+	if want := []int{1, 2, 0}; fmt.Sprint(pages) != fmt.Sprint(want) {
+		t.Fatalf("expected page changes %v, got %v", want, pages)
+	}
+
+	// Rows fetched a page at a time.
+	pages = nil
+	m.SetTotalRows(30)
+	m.SetRows(rows[:3])
+	press(tea.KeyEnd)
+	if m.Page() != 9 || m.Cursor() != 2 || m.PageCount() != 10 {
+		t.Fatalf("expected the last row of the last page, got page %d and cursor %d", m.Page(), m.Cursor())
+	}
+	m.SetRows(rows[4:7])
+	press(tea.KeyUp)
+	press(tea.KeyUp)
+	press(tea.KeyUp)
+	if m.Page() != 8 || m.Cursor() != 2 {
+		t.Fatalf("expected the last row of the previous page, got page %d and cursor %d", m.Page(), m.Cursor())
+	}
+	if want := []int{9, 8}; fmt.Sprint(pages) != fmt.Sprint(want) {
+		t.Fatalf("expected page changes %v, got %v", want, pages)
+	}
+}
+
+func TestAsync(t *testing.T) {
+	m := New(
+		WithColumns([]Column{{Title: "City", Width: 10}, {Title: "People", Width: 8}}),
+		WithRows([]Row{
+			{"Tokyo", "37.4"},
+			{"Delhi", "31"},
+			{"Shanghai", "27.1"},
+			{"Osaka", "19.1"},
+			{"Dhaka", "21.7"},
+		}),
+		WithHeight(6),
+		WithFocused(true),
+	)
+	m.SetCursor(1)
+	cities := func() string {
+		var cities []string
+		for _, r := range m.VisibleRows() {
+			cities = append(cities, r[0])
+		}
+		return strings.Join(cities, ",")
+	}
+
+	cmd := m.SortCmd(1, true)
+	if !m.Pending() || !strings.Contains(ansi.Strip(m.headersView()), "People ⋯") {
+		t.Fatalf("expected the sort to be shown as pending, got %q", ansi.Strip(m.headersView()))
+	}
+	m, _ = m.Update(cmd())
+	if got := cities(); got != "Tokyo,Delhi,Shanghai,Dhaka,Osaka" {
+		t.Fatalf("expected the rows to be sorted by population, got %s", got)
+	}
+	if m.Pending() || m.SelectedRow()[0] != "Delhi" {
+		t.Fatalf("expected the sort to finish with Delhi selected, got %s", m.SelectedRow()[0])
+	}
+
+	cmd = m.FilterCmd("ha")
+	m, _ = m.Update(cmd())
+	if got := cities(); got != "Shanghai,Dhaka" || m.FilterState() != FilterApplied {
+		t.Fatalf("expected the rows to be filtered, got %s", got)
+	}
+	m, _ = m.Update(m.SortCmd(0, false)())
+	if got := cities(); got != "Dhaka,Shanghai" {
+		t.Fatalf("expected the matching rows to be sorted, got %s", got)
+	}
+	if got := m.SourceIndex(0); got != 1 || m.Rows()[4][0] != "Tokyo" {
+		t.Fatalf("expected all rows to be sorted, got %v", m.Rows())
+	}
+
+	stale := m.SortCmd(1, false)
+	m.SetRows(m.Rows())
+	if m.Pending() {
+		t.Fatal("expected setting the rows to cancel the sort")
+	}
+	m, _ = m.Update(stale())
+	if got := cities(); got != "Dhaka,Shanghai" {
+		t.Fatalf("expected a stale sort to be ignored, got %s", got)
+	}
+
+	cmd = m.FilterCmd("o")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.Pending() || cmd() != nil {
+		t.Fatal("expected esc to cancel the filter")
+	}
+	if m.FilterValue() != "ha" {
+		t.Fatalf("expected the filter to be kept, got %q", m.FilterValue())
+	}
+}
@@ -0,0 +1,169 @@
+package textarea
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/key"
+)
+
+// BlockSelection returns the bounds of the current block selection as line
+// indices and rune columns. The bottom and right bounds are exclusive. A
+// selection with no width acts as a cursor on each of its lines. ok is false
+// if no block is selected.
+func (m Model) BlockSelection() (top, left, bottom, right int, ok bool) {
+	if !m.block {
+		return 0, 0, 0, 0, false
+	}
+	return min(m.blockRow, m.row), min(m.blockCol, m.blockHead),
+		max(m.blockRow, m.row) + 1, max(m.blockCol, m.blockHead), true
+}
+
+// ClearBlockSelection deselects the current block, if any, leaving the cursor
+// where it is.
+func (m *Model) ClearBlockSelection() {
+	m.block = false
+}
+
+// updateBlock handles keys which create, extend or edit a block selection. It
+// returns false if the key should be handled normally, in which case any
+// selection has been cleared.
+func (m *Model) updateBlock(msg tea.KeyMsg) bool {
+	switch {
+	case key.Matches(msg, m.KeyMap.BlockSelectUp):
+		m.startBlock()
+		m.row = max(0, m.row-1)
+	case key.Matches(msg, m.KeyMap.BlockSelectDown):
+		m.startBlock()
+		m.row = min(len(m.value)-1, m.row+1)
+	case key.Matches(msg, m.KeyMap.BlockSelectLeft):
+		m.startBlock()
+		m.blockHead = max(0, m.blockHead-1)
+	case key.Matches(msg, m.KeyMap.BlockSelectRight):
+		m.startBlock()
+		top, _, bottom, _, _ := m.BlockSelection()
+		var widest int
+		for _, l := range m.value[top:bottom] {
+			widest = max(widest, len(l))
+		}
+		m.blockHead = min(widest, m.blockHead+1)
+	case !m.block:
+		return false
+	case key.Matches(msg, m.KeyMap.DeleteCharacterBackward):
+		m.blockDelete(true)
+	case key.Matches(msg, m.KeyMap.DeleteCharacterForward):
+		m.blockDelete(false)
+	case msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace:
+		m.blockInsert(msg.Runes)
+	default:
+		m.block = false
+		return false
+	}
+
+	m.col = clamp(m.blockHead, 0, len(m.value[m.row]))
+	return true
+}
+
+// startBlock anchors a new block selection at the cursor, unless one is
+// already in progress.
+func (m *Model) startBlock() {
+	if m.block {
+		return
+	}
+	m.block = true
+	m.blockRow = m.row
+	m.blockCol = m.col
+	m.blockHead = m.col
+}
+
+// collapseBlock shrinks the selection to no width at the given column, keeping
+// its lines.
+func (m *Model) collapseBlock(col int) {
+	m.blockCol = col
+	m.blockHead = col
+}
+
+// blockInsert replaces the selected rectangle with the given runes on every
+// selected line. Lines shorter than the selection are padded with spaces.
+func (m *Model) blockInsert(runes []rune) {
+	runes = m.san().Sanitize(runes)
+	filtered := runes[:0]
+	for _, r := range runes {
+		if r != '\n' {
+			filtered = append(filtered, r)
+		}
+	}
+	runes = filtered
+
+	if _, left, _, right, _ := m.BlockSelection(); right > left {
+		m.blockDelete(false)
+	}
+
+	top, left, bottom, _, _ := m.BlockSelection()
+	if m.CharLimit > 0 && m.Length()+len(runes)*(bottom-top) > m.CharLimit {
+		return
+	}
+
+	for row := top; row < bottom; row++ {
+		l := m.value[row]
+		if len(l) < left {
+			l = append(l, repeatSpaces(left-len(l))...)
+		}
+		nl := make([]rune, 0, len(l)+len(runes))
+		nl = append(nl, l[:left]...)
+		nl = append(nl, runes...)
+		nl = append(nl, l[left:]...)
+		m.value[row] = nl
+	}
+	m.collapseBlock(left + len(runes))
+}
+
+// blockDelete removes the selected rectangle from every selected line. If the
+// selection has no width, a single character is removed from each line
+// instead, either before or after the selection.
+func (m *Model) blockDelete(backward bool) {
+	top, left, bottom, right, _ := m.BlockSelection()
+	if right == left {
+		if backward {
+			if left == 0 {
+				return
+			}
+			left--
+		} else {
+			right++
+		}
+	}
+
+	for row := top; row < bottom; row++ {
+		l := m.value[row]
+		if left >= len(l) {
+			continue
+		}
+		m.value[row] = append(l[:left], l[min(right, len(l)):]...)
+	}
+	m.collapseBlock(left)
+}
+
+// renderRunes renders part of line l, beginning at the given rune offset,
+// highlighting any cells within the block selection or the selected text.
+func (m Model) renderRunes(l, offset int, runes []rune, style lipgloss.Style) string {
+	top, left, bottom, right, ok := m.BlockSelection()
+	switch {
+	case !ok:
+		if left, right, ok = m.lineSelection(l); !ok {
+			return style.Render(string(runes))
+		}
+	case l < top || l >= bottom:
+		return style.Render(string(runes))
+	case right == left:
+		// The cursor already marks its own line.
+		if l == m.row {
+			return style.Render(string(runes))
+		}
+		right++
+	}
+
+	lo := clamp(left-offset, 0, len(runes))
+	hi := clamp(right-offset, 0, len(runes))
+	sel := m.style.computedSelection().Inherit(style)
+	return style.Render(string(runes[:lo])) + sel.Render(string(runes[lo:hi])) + style.Render(string(runes[hi:]))
+}
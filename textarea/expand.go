@@ -0,0 +1,86 @@
+package textarea
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// ResizeMsg is sent when an expanding textarea grows or shrinks to fit its
+// content, so that the layout around it can adjust. Height is the new height
+// in rows, and ID the ID of the textarea. See SetExpand.
+type ResizeMsg struct {
+	ID     int
+	Height int
+}
+
+// WithExpand sets whether the textarea expands as you type. See
+// Model.SetExpand.
+func WithExpand(v bool) Option {
+	return func(m *Model) {
+		m.expand = v
+	}
+}
+
+// SetExpand sets whether the textarea expands as you type, as chat inputs do.
+// Its height then follows the number of rows the content takes up, wrapped
+// lines included, from MinHeight up to MaxHeight, past which the content
+// scrolls. Update returns a command sending ResizeMsg whenever the height
+// changes. Setting the value resizes the textarea too, and SetHeight only
+// lasts until the next update.
+//
+// While expanding, MaxHeight limits the height but not the number of lines.
+func (m *Model) SetExpand(v bool) {
+	m.expand = v
+	m.fitHeight()
+}
+
+// Expand returns whether the textarea expands as you type.
+func (m Model) Expand() bool {
+	return m.expand
+}
+
+// ID returns the textarea's unique ID, which is carried by ResizeMsg.
+func (m Model) ID() int {
+	return m.id
+}
+
+// contentRows returns the number of rows the content takes up, counting each
+// row of wrapped lines.
+func (m Model) contentRows() int {
+	rows := 0
+	for _, l := range m.value {
+		rows += len(m.memoizedWrap(l, m.width))
+	}
+	return rows
+}
+
+// fitHeight sets the height to that of the content, within MinHeight and
+// MaxHeight, if the textarea expands. It returns whether the height changed.
+func (m *Model) fitHeight() bool {
+	if !m.expand {
+		return false
+	}
+	rows := m.contentRows()
+	h := max(rows, m.MinHeight, minHeight)
+	if m.MaxHeight > 0 {
+		h = min(h, m.MaxHeight)
+	}
+	if h == m.height {
+		return false
+	}
+	m.SetHeight(h)
+	m.viewport.YOffset = clamp(m.viewport.YOffset, 0, max(0, rows-m.height))
+	m.repositionView()
+	return true
+}
+
+// resizeCmd returns a command sending a ResizeMsg for the current height.
+func (m Model) resizeCmd() tea.Cmd {
+	msg := ResizeMsg{ID: m.id, Height: m.height}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// maxHeightLimitsLines returns whether MaxHeight limits the number of lines,
+// as it does unless the textarea expands.
+func (m Model) maxHeightLimitsLines() bool {
+	return m.MaxHeight > 0 && !m.expand
+}
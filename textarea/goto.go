@@ -0,0 +1,134 @@
+package textarea
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mikeflynn/bubbles/key"
+)
+
+// gotoHighlightDuration is how long the target line of a jump stays
+// highlighted.
+const gotoHighlightDuration = time.Second
+
+var lastID int64
+
+func nextID() int {
+	return int(atomic.AddInt64(&lastID, 1))
+}
+
+// gotoHighlightMsg clears the highlight of a jump once it has been shown.
+type gotoHighlightMsg struct {
+	id  int
+	tag int
+}
+
+// MoveTo moves the cursor to the given line and column, both counted from
+// zero, and centers the view on it. Out of range values are clamped to the
+// input.
+func (m *Model) MoveTo(line, col int) {
+	m.block = false
+	m.row = clamp(line, 0, len(m.value)-1)
+	m.SetCursor(col)
+	m.centerView()
+}
+
+// GotoActive returns whether the go-to-line prompt is open.
+func (m Model) GotoActive() bool {
+	return m.gotoActive
+}
+
+// centerView scrolls the view so that the cursor line is in the middle.
+func (m *Model) centerView() {
+	m.viewport.YOffset = max(0, m.cursorLineNumber()-m.viewport.Height/2) //nolint:mnd
+}
+
+// highlight marks the cursor line and returns a command which clears the mark
+// after a moment.
+func (m *Model) highlight() tea.Cmd {
+	if m.id == 0 {
+		m.id = nextID()
+	}
+	m.highlightTag++
+	m.highlighted = true
+	m.highlightRow = m.row
+	id, tag := m.id, m.highlightTag
+	return tea.Tick(gotoHighlightDuration, func(time.Time) tea.Msg {
+		return gotoHighlightMsg{id: id, tag: tag}
+	})
+}
+
+// updateGoto handles the go-to-line prompt. It returns false if the key should
+// be handled normally.
+func (m *Model) updateGoto(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if !m.gotoActive {
+		if !key.Matches(msg, m.KeyMap.GotoLine) {
+			return nil, false
+		}
+		m.block = false
+		m.gotoActive = true
+		m.gotoInput = ""
+		m.gotoFromRow, m.gotoFromCol = m.row, m.col
+		return nil, true
+	}
+
+	switch {
+	case msg.Type == tea.KeyEnter:
+		m.gotoActive = false
+		if _, ok := m.parseGoto(); ok {
+			return m.highlight(), true
+		}
+		m.MoveTo(m.gotoFromRow, m.gotoFromCol)
+		return nil, true
+	case msg.Type == tea.KeyEsc, key.Matches(msg, m.KeyMap.GotoLine):
+		m.gotoActive = false
+		m.MoveTo(m.gotoFromRow, m.gotoFromCol)
+		return nil, true
+	case key.Matches(msg, m.KeyMap.DeleteCharacterBackward):
+		if n := len(m.gotoInput); n > 0 {
+			m.gotoInput = m.gotoInput[:n-1]
+		}
+	case msg.Type == tea.KeyRunes:
+		for _, r := range msg.Runes {
+			if (r >= '0' && r <= '9') || r == ':' {
+				m.gotoInput += string(r)
+			}
+		}
+	default:
+		return nil, true
+	}
+
+	// Preview the target as it's typed.
+	if pos, ok := m.parseGoto(); ok {
+		m.MoveTo(pos[0], pos[1])
+	} else {
+		m.MoveTo(m.gotoFromRow, m.gotoFromCol)
+	}
+	return nil, true
+}
+
+// parseGoto parses the go-to-line input, which is a line number and an
+// optional column separated by a colon, both counted from one. A leading colon
+// is allowed, as in ":42".
+func (m Model) parseGoto() ([2]int, bool) {
+	parts := strings.Split(strings.TrimPrefix(m.gotoInput, ":"), ":")
+	line, err := strconv.Atoi(parts[0])
+	if err != nil || len(parts) > 2 { //nolint:mnd
+		return [2]int{}, false
+	}
+	col := 1
+	if len(parts) == 2 && parts[1] != "" { //nolint:mnd
+		if col, err = strconv.Atoi(parts[1]); err != nil {
+			return [2]int{}, false
+		}
+	}
+	return [2]int{line - 1, col - 1}, true
+}
+
+// gotoView renders the go-to-line prompt.
+func (m Model) gotoView() string {
+	return m.style.computedGotoPrompt().Render("Go to line: " + m.gotoInput)
+}
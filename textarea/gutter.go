@@ -0,0 +1,91 @@
+package textarea
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rivo/uniseg"
+)
+
+// Marker is a glyph drawn in the gutter beside a line, such as to flag a
+// diagnostic or a breakpoint.
+type Marker struct {
+	Glyph string
+	Style lipgloss.Style
+}
+
+// Some markers for common diagnostics.
+var (
+	ErrorMarker = Marker{
+		Glyph: "✖",
+		Style: lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#FF4672", Dark: "#ED567A"}),
+	}
+	WarningMarker = Marker{
+		Glyph: "▲",
+		Style: lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#E8A317", Dark: "#B37A00"}),
+	}
+	BreakpointMarker = Marker{
+		Glyph: "●",
+		Style: lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#FF4672", Dark: "#ED567A"}),
+	}
+)
+
+// MarkerFunc returns the marker for a line, by its index in the value, or
+// false if the line has none.
+type MarkerFunc func(line int) (Marker, bool)
+
+// SetMarkerFunc sets a function returning the marker drawn in the gutter
+// beside each line, before its line number. It's called each time the
+// textarea is rendered, so markers can follow diagnostics as they change.
+// width is the width of the marker column, which glyphs are padded to. The
+// text narrows to make room for the column, keeping the textarea's width.
+func (m *Model) SetMarkerFunc(width int, fn MarkerFunc) {
+	m.markerFunc = fn
+	m.markerWidth = max(0, width)
+	if fn == nil {
+		m.markerWidth = 0
+	}
+	m.fitGutter()
+}
+
+// markerView renders the marker column for a row of text. Only the first row
+// of a wrapped line gets a marker.
+func (m Model) markerView(line int, first bool, style lipgloss.Style) string {
+	if m.markerFunc == nil {
+		return ""
+	}
+	if first {
+		if marker, ok := m.markerFunc(line); ok {
+			pad := max(0, m.markerWidth-uniseg.StringWidth(marker.Glyph))
+			return style.Render(marker.Style.Inherit(style).Render(marker.Glyph) + strings.Repeat(" ", pad))
+		}
+	}
+	return style.Render(strings.Repeat(" ", m.markerWidth))
+}
+
+// lineNumberDigits returns the number of digits line numbers are padded to,
+// enough for MaxHeight or, past it, the number of lines.
+func (m Model) lineNumberDigits() int {
+	return len(strconv.Itoa(max(m.MaxHeight, len(m.value))))
+}
+
+// gutterWidth returns the width of the markers and line numbers.
+func (m Model) gutterWidth() int {
+	var w int
+	if m.markerFunc != nil {
+		w += m.markerWidth
+	}
+	if m.ShowLineNumbers {
+		w += m.lineNumberDigits() + 2 //nolint:mnd
+	}
+	return w
+}
+
+// fitGutter narrows or widens the text when the gutter or measured prompts
+// change width, such as when the number of lines gains a digit.
+func (m *Model) fitGutter() {
+	if m.gutter != m.gutterWidth() || (m.promptMeasured && m.promptWidth != m.measurePrompts()) {
+		m.SetWidth(m.requestedWidth)
+	}
+}
@@ -0,0 +1,163 @@
+package textarea
+
+import (
+	"fmt"
+
+	"github.com/rivo/uniseg"
+)
+
+// Option is used to set options in NewWithOptions. For example:
+//
+//	ta, err := NewWithOptions(
+//		WithWidth(60),
+//		WithHeight(10),
+//		WithShowLineNumbers(false),
+//	)
+type Option func(*Model)
+
+// WithWidth sets the width of the textarea, including the prompt and line
+// numbers.
+func WithWidth(w int) Option {
+	return func(m *Model) {
+		m.optWidth = w
+	}
+}
+
+// WithHeight sets the height of the textarea.
+func WithHeight(h int) Option {
+	return func(m *Model) {
+		m.optHeight = h
+	}
+}
+
+// WithPrompt sets the prompt printed at the beginning of each line.
+func WithPrompt(prompt string) Option {
+	return func(m *Model) {
+		m.Prompt = prompt
+	}
+}
+
+// WithPlaceholder sets the text displayed when the textarea is empty.
+func WithPlaceholder(placeholder string) Option {
+	return func(m *Model) {
+		m.Placeholder = placeholder
+	}
+}
+
+// WithShowLineNumbers sets whether line numbers are shown.
+func WithShowLineNumbers(v bool) Option {
+	return func(m *Model) {
+		m.ShowLineNumbers = v
+	}
+}
+
+// WithCharLimit sets the maximum number of characters the textarea accepts.
+func WithCharLimit(n int) Option {
+	return func(m *Model) {
+		m.CharLimit = n
+	}
+}
+
+// WithMaxWidth sets the maximum width of the textarea.
+func WithMaxWidth(w int) Option {
+	return func(m *Model) {
+		m.MaxWidth = w
+	}
+}
+
+// WithMaxHeight sets the maximum height of the textarea.
+func WithMaxHeight(h int) Option {
+	return func(m *Model) {
+		m.MaxHeight = h
+	}
+}
+
+// WithKeyMap sets the keymap of the textarea.
+func WithKeyMap(km KeyMap) Option {
+	return func(m *Model) {
+		m.KeyMap = km
+	}
+}
+
+// WithStyles sets the focused and blurred styles of the textarea.
+func WithStyles(focused, blurred Style) Option {
+	return func(m *Model) {
+		m.FocusedStyle = focused
+		m.BlurredStyle = blurred
+	}
+}
+
+// WithReadOnly sets whether the textarea is read-only. See
+// Model.SetReadOnly.
+func WithReadOnly(v bool) Option {
+	return func(m *Model) {
+		m.SetReadOnly(v)
+	}
+}
+
+// WithRuler sets the maximum width of a line, at which a guide is drawn, and
+// whether input may go past it. See Model.Ruler.
+func WithRuler(col int, mode RulerMode) Option {
+	return func(m *Model) {
+		m.Ruler = col
+		m.RulerMode = mode
+	}
+}
+
+// WithValidate sets the function used to validate the value. See
+// Model.Validate.
+func WithValidate(fn ValidateFunc) Option {
+	return func(m *Model) {
+		m.Validate = fn
+	}
+}
+
+// NewWithOptions returns a new textarea configured by the given options. The
+// size is applied last, so options can be given in any order. Unlike New it
+// checks the configuration up front, returning an error if it can't be
+// honored, such as a width too narrow for the prompt and line numbers.
+func NewWithOptions(opts ...Option) (Model, error) {
+	m := New()
+	m.optWidth = defaultWidth
+	m.optHeight = defaultHeight
+	for _, opt := range opts {
+		opt(&m)
+	}
+
+	if err := m.validate(); err != nil {
+		return Model{}, err
+	}
+
+	m.style = &m.BlurredStyle
+	m.SetHeight(m.optHeight)
+	m.SetWidth(m.optWidth)
+	m.fitHeight()
+	return m, nil
+}
+
+// validate returns an error if the options given to NewWithOptions can't be
+// honored.
+func (m Model) validate() error {
+	if m.optWidth < 0 || m.optHeight < 0 {
+		return fmt.Errorf("textarea: invalid size %dx%d", m.optWidth, m.optHeight)
+	}
+	if m.CharLimit < 0 {
+		return fmt.Errorf("textarea: invalid character limit %d", m.CharLimit)
+	}
+	if m.MaxWidth > 0 && m.optWidth > m.MaxWidth {
+		return fmt.Errorf("textarea: width %d exceeds the maximum width %d", m.optWidth, m.MaxWidth)
+	}
+	if !m.expand && m.MaxHeight > 0 && m.optHeight > m.MaxHeight {
+		return fmt.Errorf("textarea: height %d exceeds the maximum height %d", m.optHeight, m.MaxHeight)
+	}
+
+	reserved := uniseg.StringWidth(m.Prompt) + m.FocusedStyle.Base.GetHorizontalFrameSize()
+	if m.ShowLineNumbers {
+		reserved += 4 //nolint:mnd
+	}
+	if m.optWidth <= reserved {
+		return fmt.Errorf("textarea: width %d leaves no room for text after the prompt and line numbers, which need %d",
+			m.optWidth, reserved)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package textarea
+
+import "github.com/rivo/uniseg"
+
+// PromptInfo describes the row of the textarea a prompt is drawn on.
+type PromptInfo struct {
+	// Line is the index in the value of the line on the row, or -1 for rows
+	// past the end of the value. While the placeholder is shown, its rows
+	// belong to the first line.
+	Line int
+
+	// Row is the index of the row within its line when the line wraps.
+	// Rows after the first, where Row is above 0, continue the line.
+	Row int
+
+	// DisplayLine is the index of the row among all the rows drawn, as
+	// passed to the function set with SetPromptFunc.
+	DisplayLine int
+
+	// Focused reports whether the textarea is focused.
+	Focused bool
+}
+
+// PromptFunc returns the prompt drawn at the beginning of a row.
+type PromptFunc func(info PromptInfo) string
+
+// SetPromptInfoFunc supersedes the Prompt field and sets a dynamic prompt
+// instead, which can tell the first row of a line from the rows continuing
+// it when it wraps, such as to draw "> " before lines and "… " before their
+// continuations.
+//
+// Unlike with SetPromptFunc, prompts may differ in width: the prompt column
+// is as wide as the widest prompt for the lines of the value, measured each
+// time the textarea is rendered, and shorter prompts are padded to the left.
+// The text narrows or widens as the column does, keeping the textarea's
+// width.
+func (m *Model) SetPromptInfoFunc(fn PromptFunc) {
+	m.promptFunc = fn
+	m.promptMeasured = fn != nil
+	m.SetWidth(m.requestedWidth)
+}
+
+// measurePrompts returns the width of the widest prompt for the lines of the
+// value, their continuations and the rows past the end of the value.
+func (m Model) measurePrompts() int {
+	if m.promptFunc == nil {
+		return uniseg.StringWidth(m.Prompt)
+	}
+	info := PromptInfo{Line: -1, Focused: m.focus}
+	w := uniseg.StringWidth(m.promptFunc(info))
+	for l := range max(1, len(m.value)) {
+		info.Line = l
+		for row := range 2 {
+			info.Row = row
+			w = max(w, uniseg.StringWidth(m.promptFunc(info)))
+		}
+	}
+	return w
+}
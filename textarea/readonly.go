@@ -0,0 +1,60 @@
+package textarea
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/key"
+)
+
+// DefaultReadOnlyStyle returns the default style for a focused, read-only
+// textarea. It's the focused style with a dimmer prompt and cursor line, so
+// that it's clear the text can't be edited.
+func DefaultReadOnlyStyle() Style {
+	s, _ := DefaultStyles()
+	s.CursorLine = lipgloss.NewStyle().Background(lipgloss.AdaptiveColor{Light: "254", Dark: "235"})
+	s.Prompt = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	return s
+}
+
+// SetReadOnly sets whether the textarea is read-only. A read-only textarea
+// can still be navigated, including block selection and going to a line, but
+// keys which would change its value are ignored. The value can still be set
+// programmatically. While focused, it's styled with ReadOnlyStyle.
+func (m *Model) SetReadOnly(v bool) {
+	m.readOnly = v
+	if m.focus {
+		m.style = m.focusedStyle()
+	}
+}
+
+// ReadOnly returns whether the textarea is read-only.
+func (m Model) ReadOnly() bool {
+	return m.readOnly
+}
+
+// focusedStyle returns the style to use while focused.
+func (m *Model) focusedStyle() *Style {
+	if m.readOnly {
+		return &m.ReadOnlyStyle
+	}
+	return &m.FocusedStyle
+}
+
+// isEdit reports whether a key would change the value.
+func (m Model) isEdit(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace || key.Matches(msg,
+		m.KeyMap.DeleteAfterCursor,
+		m.KeyMap.DeleteBeforeCursor,
+		m.KeyMap.DeleteCharacterBackward,
+		m.KeyMap.DeleteCharacterForward,
+		m.KeyMap.DeleteWordBackward,
+		m.KeyMap.DeleteWordForward,
+		m.KeyMap.InsertNewline,
+		m.KeyMap.Paste,
+		m.KeyMap.Cut,
+		m.KeyMap.UppercaseWordForward,
+		m.KeyMap.LowercaseWordForward,
+		m.KeyMap.CapitalizeWordForward,
+		m.KeyMap.TransposeCharacterBackward,
+	)
+}
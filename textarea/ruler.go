@@ -0,0 +1,137 @@
+package textarea
+
+import (
+	"slices"
+	"unicode"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/rivo/uniseg"
+)
+
+// RulerMode is whether typing and pasting may make lines wider than the
+// Ruler.
+type RulerMode int
+
+// Ruler modes.
+const (
+	// RulerGuide only draws the ruler. It's the default.
+	RulerGuide RulerMode = iota
+
+	// RulerBlock rejects input which would make a line wider than the ruler.
+	RulerBlock
+
+	// RulerWrap hard-wraps lines at the last space before the ruler as input
+	// makes them too wide, or at the ruler itself if a word doesn't fit on a
+	// line.
+	RulerWrap
+)
+
+// lineWidth returns the width of a row of the value in cells.
+func (m Model) lineWidth(row int) int {
+	return uniseg.StringWidth(string(m.value[row]))
+}
+
+// insertRunesWithRuler inserts runes typed or pasted by the user, then wraps
+// the lines they were inserted into, or takes them back out, according to
+// RulerMode.
+func (m *Model) insertRunesWithRuler(runes []rune) {
+	if m.Ruler <= 0 || m.RulerMode == RulerGuide {
+		m.insertRunesFromUserInput(runes)
+		return
+	}
+
+	row, col, rows := m.row, m.col, len(m.value)
+	orig := slices.Clone(m.value[row])
+	limit := max(m.Ruler, m.lineWidth(row))
+
+	m.insertRunesFromUserInput(runes)
+
+	ok := true
+	if m.RulerMode == RulerWrap {
+		ok = m.wrapToRuler(row)
+	} else {
+		for r := row; r <= m.row; r++ {
+			if m.lineWidth(r) > limit {
+				ok = false
+				break
+			}
+		}
+	}
+	if ok {
+		return
+	}
+
+	m.value = slices.Delete(m.value, row+1, row+1+len(m.value)-rows)
+	m.value[row] = orig
+	m.row = row
+	m.SetCursor(col)
+}
+
+// wrapToRuler breaks the rows from first to the cursor's row until none are
+// wider than the ruler. It returns false if there's no room for the new rows.
+func (m *Model) wrapToRuler(first int) bool {
+	for r := first; r <= m.row; r++ {
+		// A single character can't be broken, even if it's wider than the
+		// ruler.
+		for m.lineWidth(r) > m.Ruler && len(m.value[r]) > 1 {
+			if len(m.value) >= maxLines || (m.maxHeightLimitsLines() && len(m.value) >= m.MaxHeight) {
+				return false
+			}
+			m.breakLine(r)
+		}
+	}
+	return true
+}
+
+// breakLine moves the end of a row wider than the ruler onto a new row below
+// it, breaking at the last space which leaves the row within the ruler, or
+// else after the last rune that fits. The space is dropped.
+func (m *Model) breakLine(row int) {
+	line := m.value[row]
+
+	at, skip := 0, 0
+	width := 0
+	for i, r := range line {
+		if width > m.Ruler {
+			break
+		}
+		if unicode.IsSpace(r) && i > 0 {
+			at, skip = i, 1
+		}
+		width += uniseg.StringWidth(string(r))
+		if at == 0 && width > m.Ruler {
+			at = max(1, i)
+		}
+	}
+
+	tail := slices.Clone(line[min(len(line), at+skip):])
+	m.value = slices.Insert(m.value, row+1, tail)
+	m.value[row] = line[:at:at]
+
+	switch {
+	case m.row > row:
+		m.row++
+	case m.row == row && m.col >= at+skip:
+		m.row++
+		m.col -= at + skip
+	case m.row == row && m.col > at:
+		m.col = at
+	}
+}
+
+// rulerView draws the ruler over a rendered row of text, in the column past
+// Ruler. Blank cells show RulerCharacter, while text under the ruler keeps its
+// characters and takes on the Ruler style. cursor is the column of the cursor
+// on the row, or -1, since the cursor is drawn over the ruler.
+func (m Model) rulerView(line string, cursor int) string {
+	if m.Ruler <= 0 || m.Ruler >= m.width || cursor == m.Ruler {
+		return line
+	}
+	cell := ansi.Strip(ansi.Cut(line, m.Ruler, m.Ruler+1))
+	if cell == " " || cell == "" {
+		cell = string(m.RulerCharacter)
+	}
+	return ansi.Cut(line, 0, m.Ruler) +
+		m.style.computedRuler().Render(cell) +
+		ansi.Cut(line, m.Ruler+1, ansi.StringWidth(line))
+}
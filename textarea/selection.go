@@ -0,0 +1,208 @@
+package textarea
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mikeflynn/bubbles/clipboard"
+	"github.com/mikeflynn/bubbles/key"
+)
+
+// copyErrMsg reports that text couldn't be written to the clipboard.
+type copyErrMsg struct{ error }
+
+// WithClipboard sets the clipboard text is copied to and pasted from.
+func WithClipboard(c clipboard.Clipboard) Option {
+	return func(m *Model) {
+		m.Clipboard = c
+	}
+}
+
+// Selection returns the bounds of the current selection as line indices and
+// rune columns, from its start to its end, whichever way it was made. The end
+// column is exclusive. ok is false if nothing is selected.
+func (m Model) Selection() (startRow, startCol, endRow, endCol int, ok bool) {
+	if !m.selecting {
+		return 0, 0, 0, 0, false
+	}
+	ar, ac := m.selRow, clamp(m.selCol, 0, len(m.value[m.selRow]))
+	cr, cc := m.row, clamp(m.col, 0, len(m.value[m.row]))
+	if ar == cr && ac == cc {
+		return 0, 0, 0, 0, false
+	}
+	if ar < cr || ar == cr && ac < cc {
+		return ar, ac, cr, cc, true
+	}
+	return cr, cc, ar, ac, true
+}
+
+// SelectedText returns the selected text, or an empty string if nothing is
+// selected.
+func (m Model) SelectedText() string {
+	startRow, startCol, endRow, endCol, ok := m.Selection()
+	if !ok {
+		return ""
+	}
+	if startRow == endRow {
+		return string(m.value[startRow][startCol:endCol])
+	}
+	var b strings.Builder
+	b.WriteString(string(m.value[startRow][startCol:]))
+	for _, l := range m.value[startRow+1 : endRow] {
+		b.WriteByte('\n')
+		b.WriteString(string(l))
+	}
+	b.WriteByte('\n')
+	b.WriteString(string(m.value[endRow][:endCol]))
+	return b.String()
+}
+
+// SelectAll selects all of the text, leaving the cursor at its end.
+func (m *Model) SelectAll() {
+	m.block = false
+	m.selecting = true
+	m.selRow, m.selCol = 0, 0
+	m.moveToEnd()
+}
+
+// ClearSelection deselects the selected text, if any, leaving the cursor
+// where it is.
+func (m *Model) ClearSelection() {
+	m.selecting = false
+}
+
+// Copy returns a command copying the selected text to the clipboard. It
+// returns nil if nothing is selected.
+func (m Model) Copy() tea.Cmd {
+	text := m.SelectedText()
+	if text == "" {
+		return nil
+	}
+	cb := clipboard.Or(m.Clipboard)
+	return func() tea.Msg {
+		if err := cb.WriteAll(text); err != nil {
+			return copyErrMsg{err}
+		}
+		return nil
+	}
+}
+
+// Cut removes the selected text, returning a command copying it to the
+// clipboard. It returns nil if nothing is selected, or if the textarea is
+// read-only.
+func (m *Model) Cut() tea.Cmd {
+	if m.readOnly {
+		return nil
+	}
+	cmd := m.Copy()
+	m.deleteSelection()
+	m.validateValue()
+	return cmd
+}
+
+// pasteCmd returns a command pasting from the clipboard.
+func (m Model) pasteCmd() tea.Cmd {
+	if m.Clipboard == nil {
+		return Paste
+	}
+	cb := m.Clipboard
+	return func() tea.Msg {
+		str, err := cb.ReadAll()
+		if err != nil {
+			return pasteErrMsg{err}
+		}
+		return pasteMsg(str)
+	}
+}
+
+// updateSelection handles keys which make, extend or act on a selection. It
+// returns false if the key should be handled normally. Typing and pasting
+// replace the selection, and other keys clear it.
+func (m *Model) updateSelection(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.KeyMap.SelectCharacterForward):
+		m.extendSelection(m.characterRight)
+	case key.Matches(msg, m.KeyMap.SelectCharacterBackward):
+		m.extendSelection(func() { m.characterLeft(false /* insideLine */) })
+	case key.Matches(msg, m.KeyMap.SelectWordForward):
+		m.extendSelection(m.wordRight)
+	case key.Matches(msg, m.KeyMap.SelectWordBackward):
+		m.extendSelection(func() {
+			if m.row > 0 || m.col > 0 {
+				m.wordLeft()
+			}
+		})
+	case key.Matches(msg, m.KeyMap.SelectLineNext):
+		m.extendSelection(m.CursorDown)
+	case key.Matches(msg, m.KeyMap.SelectLinePrevious):
+		m.extendSelection(m.CursorUp)
+	case key.Matches(msg, m.KeyMap.SelectLineStart):
+		m.extendSelection(m.CursorStart)
+	case key.Matches(msg, m.KeyMap.SelectLineEnd):
+		m.extendSelection(m.CursorEnd)
+	case key.Matches(msg, m.KeyMap.SelectAll):
+		m.SelectAll()
+	case !m.selecting:
+		return nil, false
+	case key.Matches(msg, m.KeyMap.Copy):
+		return m.Copy(), true
+	case key.Matches(msg, m.KeyMap.Cut):
+		return m.Cut(), true
+	case key.Matches(msg, m.KeyMap.DeleteCharacterBackward, m.KeyMap.DeleteCharacterForward):
+		m.deleteSelection()
+	case msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace || key.Matches(msg, m.KeyMap.InsertNewline):
+		m.deleteSelection()
+		return nil, false
+	case key.Matches(msg, m.KeyMap.Paste):
+		// The selection's replaced when the pasted text arrives.
+		return nil, false
+	default:
+		m.selecting = false
+		return nil, false
+	}
+	return nil, true
+}
+
+// extendSelection anchors a selection at the cursor, unless one is in
+// progress, and moves the cursor with move.
+func (m *Model) extendSelection(move func()) {
+	m.block = false
+	if !m.selecting {
+		m.selecting = true
+		m.selRow, m.selCol = m.row, clamp(m.col, 0, len(m.value[m.row]))
+	}
+	move()
+}
+
+// deleteSelection removes the selected text, leaving the cursor where it
+// started.
+func (m *Model) deleteSelection() {
+	startRow, startCol, endRow, endCol, ok := m.Selection()
+	m.selecting = false
+	if !ok {
+		return
+	}
+	head := m.value[startRow][:startCol:startCol]
+	m.value[startRow] = append(head, m.value[endRow][endCol:]...)
+	m.value = append(m.value[:startRow+1], m.value[endRow+1:]...)
+	m.row = startRow
+	m.SetCursor(startCol)
+}
+
+// lineSelection returns the rune columns of line l which are selected. The
+// right bound is exclusive, and is one past the end of the line if its line
+// break is selected.
+func (m Model) lineSelection(l int) (left, right int, ok bool) {
+	startRow, startCol, endRow, endCol, ok := m.Selection()
+	if !ok || l < startRow || l > endRow {
+		return 0, 0, false
+	}
+	if l == startRow {
+		left = startCol
+	}
+	right = len(m.value[l]) + 1
+	if l == endRow {
+		right = endCol
+	}
+	return left, right, right > left
+}
@@ -9,15 +9,17 @@ import (
 	"strings"
 	"unicode"
 
-	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
 	rw "github.com/mattn/go-runewidth"
+	"github.com/mikeflynn/bubbles/clipboard"
 	"github.com/mikeflynn/bubbles/cursor"
 	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
 	"github.com/mikeflynn/bubbles/runeutil"
 	"github.com/mikeflynn/bubbles/textarea/memoization"
+	"github.com/mikeflynn/bubbles/textutil"
 	"github.com/mikeflynn/bubbles/viewport"
 	"github.com/rivo/uniseg"
 )
@@ -40,6 +42,9 @@ type (
 	pasteErrMsg struct{ error }
 )
 
+// ValidateFunc is a function that returns an error if the input is invalid.
+type ValidateFunc func(string) error
+
 // KeyMap is the key bindings for different actions within the textarea.
 type KeyMap struct {
 	CharacterBackward       key.Binding
@@ -66,6 +71,35 @@ type KeyMap struct {
 	CapitalizeWordForward key.Binding
 
 	TransposeCharacterBackward key.Binding
+
+	// Block selection. While a block is selected, typing inserts on every
+	// selected line and deleting removes the selected rectangle.
+	BlockSelectUp    key.Binding
+	BlockSelectDown  key.Binding
+	BlockSelectLeft  key.Binding
+	BlockSelectRight key.Binding
+
+	// GotoLine opens a prompt for a line number, and an optional column, to
+	// jump to. The cursor follows the number as it's typed.
+	GotoLine key.Binding
+
+	// Selection. The select keys move the cursor like their unselecting
+	// counterparts, extending the selection from where it started. While
+	// text is selected, typing, pasting and deleting replace it.
+	SelectCharacterForward  key.Binding
+	SelectCharacterBackward key.Binding
+	SelectWordForward       key.Binding
+	SelectWordBackward      key.Binding
+	SelectLineNext          key.Binding
+	SelectLinePrevious      key.Binding
+	SelectLineStart         key.Binding
+	SelectLineEnd           key.Binding
+	SelectAll               key.Binding
+
+	// Copy and Cut copy the selected text to the clipboard, and Cut removes
+	// it too. See Model.Clipboard.
+	Copy key.Binding
+	Cut  key.Binding
 }
 
 // DefaultKeyMap is the default set of key bindings for navigating and acting
@@ -95,6 +129,26 @@ var DefaultKeyMap = KeyMap{
 	UppercaseWordForward:  key.NewBinding(key.WithKeys("alt+u"), key.WithHelp("alt+u", "uppercase word forward")),
 
 	TransposeCharacterBackward: key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "transpose character backward")),
+
+	BlockSelectUp:    key.NewBinding(key.WithKeys("alt+shift+up"), key.WithHelp("alt+shift+up", "extend block selection up")),
+	BlockSelectDown:  key.NewBinding(key.WithKeys("alt+shift+down"), key.WithHelp("alt+shift+down", "extend block selection down")),
+	BlockSelectLeft:  key.NewBinding(key.WithKeys("alt+shift+left"), key.WithHelp("alt+shift+left", "extend block selection left")),
+	BlockSelectRight: key.NewBinding(key.WithKeys("alt+shift+right"), key.WithHelp("alt+shift+right", "extend block selection right")),
+
+	GotoLine: key.NewBinding(key.WithKeys("ctrl+g"), key.WithHelp("ctrl+g", "go to line")),
+
+	SelectCharacterForward:  key.NewBinding(key.WithKeys("shift+right"), key.WithHelp("shift+right", "select character forward")),
+	SelectCharacterBackward: key.NewBinding(key.WithKeys("shift+left"), key.WithHelp("shift+left", "select character backward")),
+	SelectWordForward:       key.NewBinding(key.WithKeys("ctrl+shift+right"), key.WithHelp("ctrl+shift+right", "select word forward")),
+	SelectWordBackward:      key.NewBinding(key.WithKeys("ctrl+shift+left"), key.WithHelp("ctrl+shift+left", "select word backward")),
+	SelectLineNext:          key.NewBinding(key.WithKeys("shift+down"), key.WithHelp("shift+down", "select to next line")),
+	SelectLinePrevious:      key.NewBinding(key.WithKeys("shift+up"), key.WithHelp("shift+up", "select to previous line")),
+	SelectLineStart:         key.NewBinding(key.WithKeys("shift+home"), key.WithHelp("shift+home", "select to line start")),
+	SelectLineEnd:           key.NewBinding(key.WithKeys("shift+end"), key.WithHelp("shift+end", "select to line end")),
+	SelectAll:               key.NewBinding(key.WithKeys("alt+a"), key.WithHelp("alt+a", "select all")),
+
+	Copy: key.NewBinding(key.WithKeys("alt+w"), key.WithHelp("alt+w", "copy")),
+	Cut:  key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "cut")),
 }
 
 // LineInfo is a helper for keeping track of line information regarding
@@ -138,6 +192,10 @@ type Style struct {
 	Placeholder      lipgloss.Style
 	Prompt           lipgloss.Style
 	Text             lipgloss.Style
+	Selection        lipgloss.Style
+	Highlight        lipgloss.Style
+	GotoPrompt       lipgloss.Style
+	Ruler            lipgloss.Style
 }
 
 func (s Style) computedCursorLine() lipgloss.Style {
@@ -171,6 +229,22 @@ func (s Style) computedText() lipgloss.Style {
 	return s.Text.Inherit(s.Base).Inline(true)
 }
 
+func (s Style) computedSelection() lipgloss.Style {
+	return s.Selection.Inherit(s.Base).Inline(true)
+}
+
+func (s Style) computedHighlight() lipgloss.Style {
+	return s.Highlight.Inherit(s.CursorLine).Inherit(s.Base).Inline(true)
+}
+
+func (s Style) computedGotoPrompt() lipgloss.Style {
+	return s.GotoPrompt.Inherit(s.Base).Inline(true)
+}
+
+func (s Style) computedRuler() lipgloss.Style {
+	return s.Ruler.Inherit(s.Base).Inline(true)
+}
+
 // line is the input to the text wrapping function. This is stored in a struct
 // so that it can be hashed and memoized.
 type line struct {
@@ -217,6 +291,10 @@ type Model struct {
 	// focused and blurred states.
 	FocusedStyle Style
 	BlurredStyle Style
+
+	// ReadOnlyStyle is used in place of FocusedStyle while the textarea is
+	// read-only. See SetReadOnly.
+	ReadOnlyStyle Style
 	// style is the current styling to use.
 	// It is used to abstract the differences in focus state when styling the
 	// model, since we can simply assign the set of styles to this variable
@@ -234,16 +312,47 @@ type Model struct {
 	// there's no limit.
 	MaxHeight int
 
+	// MinHeight is the height an expanding text area shrinks to when it has
+	// little content. If 0 or less, it's a single row. See SetExpand.
+	MinHeight int
+
 	// MaxWidth is the maximum width of the text area in columns. If 0 or less,
 	// there's no limit.
 	MaxWidth int
 
+	// Ruler is the maximum width of a line in cells, such as 72 for commit
+	// messages, and a guide is drawn in the column past it. If 0 or less,
+	// there's no ruler. RulerMode sets whether input may go past it.
+	Ruler     int
+	RulerMode RulerMode
+
+	// RulerCharacter is drawn for the ruler where there's no text under it.
+	RulerCharacter rune
+
+	// Validate is a function that checks whether or not the text within the
+	// textarea is valid. If it is not valid, the `Err` field will be set to
+	// the error returned by the function. If the function is not defined, all
+	// input is considered valid.
+	Validate ValidateFunc
+
 	// If promptFunc is set, it replaces Prompt as a generator for
 	// prompt strings at the beginning of each line.
-	promptFunc func(line int) string
+	promptFunc PromptFunc
+
+	// promptWidth is the width of the prompt, which is measured from the
+	// prompts if promptMeasured is set.
+	promptWidth    int
+	promptMeasured bool
+
+	// markerFunc returns the gutter marker for each line, drawn in a column
+	// markerWidth wide.
+	markerFunc  MarkerFunc
+	markerWidth int
 
-	// promptWidth is the width of the prompt.
-	promptWidth int
+	// requestedWidth is the width last given to SetWidth, and gutter the
+	// width it reserved for markers and line numbers.
+	requestedWidth int
+	gutter         int
 
 	// width is the maximum number of characters that can be displayed at once.
 	// If 0 or less this setting is ignored.
@@ -275,8 +384,51 @@ type Model struct {
 	// input.
 	viewport *viewport.Model
 
+	// Block selection state. The selected rectangle spans from the anchor at
+	// blockRow and blockCol to the cursor row and blockHead. Columns are rune
+	// offsets and may lie past the end of shorter lines.
+	block     bool
+	blockRow  int
+	blockCol  int
+	blockHead int
+
+	// Go-to-line state. While the prompt is open, the cursor previews the
+	// typed position and returns to gotoFromRow and gotoFromCol if it's
+	// cancelled. After a jump, highlightRow stays highlighted until the
+	// gotoHighlightMsg carrying highlightTag arrives.
+	id           int
+	gotoActive   bool
+	gotoInput    string
+	gotoFromRow  int
+	gotoFromCol  int
+	highlighted  bool
+	highlightRow int
+	highlightTag int
+
+	// expand is whether the height follows the content. See SetExpand.
+	expand bool
+
+	// Selection state. The selection spans from the anchor at selRow and
+	// selCol to the cursor.
+	selecting bool
+	selRow    int
+	selCol    int
+
+	// Clipboard is where text is copied, cut and pasted. If nil, the
+	// system clipboard is used. See clipboard.NewOSC52 for copying over SSH.
+	Clipboard clipboard.Clipboard
+
+	// readOnly rejects keys which would change the value.
+	readOnly bool
+
 	// rune sanitizer for input.
 	rsan runeutil.Sanitizer
+
+	// optWidth and optHeight are the size requested with WithWidth and
+	// WithHeight. NewWithOptions applies them after the other options, so
+	// that the prompt and line numbers are accounted for.
+	optWidth  int
+	optHeight int
 }
 
 // New creates a new model with default settings.
@@ -294,9 +446,11 @@ func New() Model {
 		Prompt:               lipgloss.ThickBorder().Left + " ",
 		style:                &blurredStyle,
 		FocusedStyle:         focusedStyle,
+		ReadOnlyStyle:        DefaultReadOnlyStyle(),
 		BlurredStyle:         blurredStyle,
 		cache:                memoization.NewMemoCache[line, [][]rune](maxLines),
 		EndOfBufferCharacter: ' ',
+		RulerCharacter:       '│',
 		ShowLineNumbers:      true,
 		Cursor:               cur,
 		KeyMap:               DefaultKeyMap,
@@ -305,6 +459,7 @@ func New() Model {
 		focus: false,
 		col:   0,
 		row:   0,
+		id:    nextID(),
 
 		viewport: &vp,
 	}
@@ -327,6 +482,10 @@ func DefaultStyles() (Style, Style) {
 		Placeholder:      lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
 		Prompt:           lipgloss.NewStyle().Foreground(lipgloss.Color("7")),
 		Text:             lipgloss.NewStyle(),
+		Selection:        lipgloss.NewStyle().Reverse(true),
+		Highlight:        lipgloss.NewStyle().Background(lipgloss.AdaptiveColor{Light: "225", Dark: "53"}),
+		GotoPrompt:       lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#AD58B4", Dark: "#F793FF"}),
+		Ruler:            lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "252", Dark: "238"}),
 	}
 	blurred := Style{
 		Base:             lipgloss.NewStyle(),
@@ -337,6 +496,10 @@ func DefaultStyles() (Style, Style) {
 		Placeholder:      lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
 		Prompt:           lipgloss.NewStyle().Foreground(lipgloss.Color("7")),
 		Text:             lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "245", Dark: "7"}),
+		Selection:        lipgloss.NewStyle().Reverse(true),
+		Highlight:        lipgloss.NewStyle().Background(lipgloss.AdaptiveColor{Light: "225", Dark: "53"}),
+		GotoPrompt:       lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#AD58B4", Dark: "#F793FF"}),
+		Ruler:            lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "252", Dark: "238"}),
 	}
 
 	return focused, blurred
@@ -346,16 +509,19 @@ func DefaultStyles() (Style, Style) {
 func (m *Model) SetValue(s string) {
 	m.Reset()
 	m.InsertString(s)
+	m.fitHeight()
 }
 
 // InsertString inserts a string at the cursor position.
 func (m *Model) InsertString(s string) {
 	m.insertRunesFromUserInput([]rune(s))
+	m.validateValue()
 }
 
 // InsertRune inserts a rune at the cursor position.
 func (m *Model) InsertRune(r rune) {
 	m.insertRunesFromUserInput([]rune{r})
+	m.validateValue()
 }
 
 // insertRunesFromUserInput inserts runes at the current cursor position.
@@ -580,7 +746,7 @@ func (m Model) Focused() bool {
 // receive keyboard input and the cursor will be hidden.
 func (m *Model) Focus() tea.Cmd {
 	m.focus = true
-	m.style = &m.FocusedStyle
+	m.style = m.focusedStyle()
 	return m.Cursor.Focus()
 }
 
@@ -597,6 +763,8 @@ func (m *Model) Reset() {
 	m.value = make([][]rune, minHeight, maxLines)
 	m.col = 0
 	m.row = 0
+	m.block = false
+	m.selecting = false
 	m.viewport.GotoTop()
 	m.SetCursor(0)
 }
@@ -890,10 +1058,12 @@ func (m *Model) moveToEnd() {
 // It is important that the width of the textarea be exactly the given width
 // and no more.
 func (m *Model) SetWidth(w int) {
-	// Update prompt width only if there is no prompt function as SetPromptFunc
-	// updates the prompt width when it is called.
-	if m.promptFunc == nil {
-		m.promptWidth = uniseg.StringWidth(m.Prompt)
+	m.requestedWidth = w
+
+	// Update prompt width only if there is no prompt function or it's
+	// measured, as SetPromptFunc updates the prompt width when it is called.
+	if m.promptFunc == nil || m.promptMeasured {
+		m.promptWidth = m.measurePrompts()
 	}
 
 	// Add base style borders and padding to reserved outer width.
@@ -902,11 +1072,9 @@ func (m *Model) SetWidth(w int) {
 	// Add prompt width to reserved inner width.
 	reservedInner := m.promptWidth
 
-	// Add line number width to reserved inner width.
-	if m.ShowLineNumbers {
-		const lnWidth = 4 // Up to 3 digits for line number plus 1 margin.
-		reservedInner += lnWidth
-	}
+	// Add marker and line number width to reserved inner width.
+	m.gutter = m.gutterWidth()
+	reservedInner += m.gutter
 
 	// Input width must be at least one more than the reserved inner and outer
 	// width. This gives us a minimum input width of 1.
@@ -924,6 +1092,7 @@ func (m *Model) SetWidth(w int) {
 
 	m.viewport.Width = inputWidth - reservedOuter
 	m.width = inputWidth - reservedOuter - reservedInner
+	m.fitHeight()
 }
 
 // SetPromptFunc supersedes the Prompt field and sets a dynamic prompt
@@ -933,9 +1102,18 @@ func (m *Model) SetWidth(w int) {
 // If it returns a prompt that is longer, display artifacts
 // may occur; the caller is responsible for computing an adequate
 // promptWidth.
+//
+// See SetPromptInfoFunc for prompts which tell wrapped lines apart or differ
+// in width.
 func (m *Model) SetPromptFunc(promptWidth int, fn func(lineIdx int) string) {
-	m.promptFunc = fn
+	m.promptFunc = nil
+	if fn != nil {
+		m.promptFunc = func(info PromptInfo) string {
+			return fn(info.DisplayLine)
+		}
+	}
 	m.promptWidth = promptWidth
+	m.promptMeasured = false
 }
 
 // Height returns the current height of the textarea.
@@ -956,6 +1134,13 @@ func (m *Model) SetHeight(h int) {
 
 // Update is the Bubble Tea update loop.
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(gotoHighlightMsg); ok {
+		if msg.id == m.id && msg.tag == m.highlightTag {
+			m.highlighted = false
+		}
+		return m, nil
+	}
+
 	if !m.focus {
 		m.Cursor.Blur()
 		return m, nil
@@ -976,6 +1161,21 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.readOnly && !m.gotoActive && m.isEdit(msg) {
+			break
+		}
+		if cmd, ok := m.updateGoto(msg); ok {
+			cmds = append(cmds, cmd)
+			break
+		}
+		if m.updateBlock(msg) {
+			m.selecting = false
+			break
+		}
+		if cmd, ok := m.updateSelection(msg); ok {
+			cmds = append(cmds, cmd)
+			break
+		}
 		switch {
 		case key.Matches(msg, m.KeyMap.DeleteAfterCursor):
 			m.col = clamp(m.col, 0, len(m.value[m.row]))
@@ -1025,7 +1225,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			}
 			m.deleteWordRight()
 		case key.Matches(msg, m.KeyMap.InsertNewline):
-			if m.MaxHeight > 0 && len(m.value) >= m.MaxHeight {
+			if m.maxHeightLimitsLines() && len(m.value) >= m.MaxHeight {
 				return m, nil
 			}
 			m.col = clamp(m.col, 0, len(m.value[m.row]))
@@ -1041,7 +1241,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		case key.Matches(msg, m.KeyMap.WordForward):
 			m.wordRight()
 		case key.Matches(msg, m.KeyMap.Paste):
-			return m, Paste
+			return m, m.pasteCmd()
 		case key.Matches(msg, m.KeyMap.CharacterBackward):
 			m.characterLeft(false /* insideLine */)
 		case key.Matches(msg, m.KeyMap.LinePrevious):
@@ -1062,14 +1262,31 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			m.transposeLeft()
 
 		default:
-			m.insertRunesFromUserInput(msg.Runes)
+			m.insertRunesWithRuler(msg.Runes)
 		}
 
 	case pasteMsg:
-		m.insertRunesFromUserInput([]rune(msg))
+		if m.readOnly {
+			break
+		}
+		if m.block && !strings.Contains(string(msg), "\n") {
+			m.blockInsert([]rune(msg))
+			break
+		}
+		m.block = false
+		m.deleteSelection()
+		m.insertRunesWithRuler([]rune(msg))
 
 	case pasteErrMsg:
 		m.Err = msg
+
+	case copyErrMsg:
+		m.Err = msg
+	}
+
+	switch msg.(type) {
+	case tea.KeyMsg, pasteMsg:
+		m.validateValue()
 	}
 
 	vp, cmd := m.viewport.Update(msg)
@@ -1084,13 +1301,26 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	}
 	cmds = append(cmds, cmd)
 
+	m.fitGutter()
+	if m.fitHeight() {
+		cmds = append(cmds, m.resizeCmd())
+	}
 	m.repositionView()
 
 	return m, tea.Batch(cmds...)
 }
 
+// validateValue runs the Validate function, if any, against the current
+// value, storing the result in Err.
+func (m *Model) validateValue() {
+	if m.Validate != nil {
+		m.Err = m.Validate(m.Value())
+	}
+}
+
 // View renders the text area in its current state.
 func (m Model) View() string {
+	m.fitGutter()
 	if m.Value() == "" && m.row == 0 && m.col == 0 && m.Placeholder != "" {
 		return m.placeholderView()
 	}
@@ -1108,38 +1338,37 @@ func (m Model) View() string {
 	for l, line := range m.value {
 		wrappedLines := m.memoizedWrap(line, m.width)
 
-		if m.row == l {
+		if m.highlighted && m.highlightRow == l {
+			style = m.style.computedHighlight()
+		} else if m.row == l {
 			style = m.style.computedCursorLine()
 		} else {
 			style = m.style.computedText()
 		}
 
+		var start int
 		for wl, wrappedLine := range wrappedLines {
-			prompt := m.getPromptString(displayLine)
+			offset := start
+			start += len(wrappedLine)
+
+			prompt := m.getPromptString(PromptInfo{Line: l, Row: wl, DisplayLine: displayLine, Focused: m.focus})
 			prompt = m.style.computedPrompt().Render(prompt)
 			s.WriteString(style.Render(prompt))
 			displayLine++
 
-			var ln string
-			if m.ShowLineNumbers { //nolint:nestif
+			ln := m.markerView(l, wl == 0, style)
+			if m.ShowLineNumbers {
+				lnStyle := m.style.computedLineNumber()
+				if m.row == l {
+					lnStyle = m.style.computedCursorLineNumber()
+				}
+				var num any = " "
 				if wl == 0 {
-					if m.row == l {
-						ln = style.Render(m.style.computedCursorLineNumber().Render(m.formatLineNumber(l + 1)))
-						s.WriteString(ln)
-					} else {
-						ln = style.Render(m.style.computedLineNumber().Render(m.formatLineNumber(l + 1)))
-						s.WriteString(ln)
-					}
-				} else {
-					if m.row == l {
-						ln = style.Render(m.style.computedCursorLineNumber().Render(m.formatLineNumber(" ")))
-						s.WriteString(ln)
-					} else {
-						ln = style.Render(m.style.computedLineNumber().Render(m.formatLineNumber(" ")))
-						s.WriteString(ln)
-					}
+					num = l + 1
 				}
+				ln += style.Render(lnStyle.Render(m.formatLineNumber(num)))
 			}
+			s.WriteString(ln)
 
 			// Note the widest line number for padding purposes later.
 			lnw := lipgloss.Width(ln)
@@ -1160,20 +1389,24 @@ func (m Model) View() string {
 				wrappedLine = []rune(strings.TrimSuffix(string(wrappedLine), " "))
 				padding -= m.width - strwidth
 			}
+			var text strings.Builder
+			cursorCol := -1
 			if m.row == l && lineInfo.RowOffset == wl {
-				s.WriteString(style.Render(string(wrappedLine[:lineInfo.ColumnOffset])))
+				cursorCol = uniseg.StringWidth(string(wrappedLine[:lineInfo.ColumnOffset]))
+				text.WriteString(m.renderRunes(l, offset, wrappedLine[:lineInfo.ColumnOffset], style))
 				if m.col >= len(line) && lineInfo.CharOffset >= m.width {
 					m.Cursor.SetChar(" ")
-					s.WriteString(m.Cursor.View())
+					text.WriteString(m.Cursor.View())
 				} else {
 					m.Cursor.SetChar(string(wrappedLine[lineInfo.ColumnOffset]))
-					s.WriteString(style.Render(m.Cursor.View()))
-					s.WriteString(style.Render(string(wrappedLine[lineInfo.ColumnOffset+1:])))
+					text.WriteString(style.Render(m.Cursor.View()))
+					text.WriteString(m.renderRunes(l, offset+lineInfo.ColumnOffset+1, wrappedLine[lineInfo.ColumnOffset+1:], style))
 				}
 			} else {
-				s.WriteString(style.Render(string(wrappedLine)))
+				text.WriteString(m.renderRunes(l, offset, wrappedLine, style))
 			}
-			s.WriteString(style.Render(strings.Repeat(" ", max(0, padding))))
+			text.WriteString(style.Render(strings.Repeat(" ", max(0, padding))))
+			s.WriteString(m.rulerView(text.String(), cursorCol))
 			s.WriteRune('\n')
 			newLines++
 		}
@@ -1182,7 +1415,7 @@ func (m Model) View() string {
 	// Always show at least `m.Height` lines at all times.
 	// To do this we can simply pad out a few extra new lines in the view.
 	for i := 0; i < m.height; i++ {
-		prompt := m.getPromptString(displayLine)
+		prompt := m.getPromptString(PromptInfo{Line: -1, DisplayLine: displayLine, Focused: m.focus})
 		prompt = m.style.computedPrompt().Render(prompt)
 		s.WriteString(prompt)
 		displayLine++
@@ -1196,24 +1429,41 @@ func (m Model) View() string {
 	}
 
 	m.viewport.SetContent(s.String())
-	return m.style.Base.Render(m.viewport.View())
+	view := m.viewport.View()
+	if m.gotoActive {
+		// The prompt takes the place of the bottom line.
+		if i := strings.LastIndex(view, "\n"); i >= 0 {
+			view = view[:i+1]
+		} else {
+			view = ""
+		}
+		view += textutil.Fit(m.gotoView(), m.viewport.Width, textutil.Ellipsis)
+	}
+	return m.style.Base.Render(view)
+}
+
+// RenderString renders the textarea deterministically at the given size, for
+// golden-file tests. The cursor is drawn without blinking. See the render
+// package for details.
+func RenderString(m Model, width, height int) string {
+	m.SetWidth(width)
+	m.SetHeight(height)
+	m.Cursor.SetMode(cursor.CursorStatic)
+	return render.View(m.View, width, height)
 }
 
 // formatLineNumber formats the line number for display dynamically based on
 // the maximum number of lines.
 func (m Model) formatLineNumber(x any) string {
-	// XXX: ultimately we should use a max buffer height, which has yet to be
-	// implemented.
-	digits := len(strconv.Itoa(m.MaxHeight))
-	return fmt.Sprintf(" %*v ", digits, x)
+	return fmt.Sprintf(" %*v ", m.lineNumberDigits(), x)
 }
 
-func (m Model) getPromptString(displayLine int) (prompt string) {
+func (m Model) getPromptString(info PromptInfo) (prompt string) {
 	prompt = m.Prompt
 	if m.promptFunc == nil {
 		return prompt
 	}
-	prompt = m.promptFunc(displayLine)
+	prompt = m.promptFunc(info)
 	pl := uniseg.StringWidth(prompt)
 	if pl < m.promptWidth {
 		prompt = fmt.Sprintf("%*s%s", m.promptWidth-pl, "", prompt)
@@ -1245,9 +1495,16 @@ func (m Model) placeholderView() string {
 		}
 
 		// render prompt
-		prompt := m.getPromptString(i)
+		info := PromptInfo{Line: -1, DisplayLine: i, Focused: m.focus}
+		if len(plines) > i {
+			info.Line, info.Row = 0, i
+		}
+		prompt := m.getPromptString(info)
 		prompt = m.style.computedPrompt().Render(prompt)
 		s.WriteString(lineStyle.Render(prompt))
+		if m.markerFunc != nil && len(plines) > i {
+			s.WriteString(lineStyle.Render(strings.Repeat(" ", m.markerWidth)))
+		}
 
 		// when show line numbers enabled:
 		// - render line number for only the cursor line
@@ -1276,8 +1533,9 @@ func (m Model) placeholderView() string {
 			m.Cursor.SetChar(ch)
 			s.WriteString(lineStyle.Render(m.Cursor.View()))
 
-			// the rest of the first line
-			s.WriteString(lineStyle.Render(style.Render(rest)))
+			// the rest of the first line, padded like the lines after it
+			pad := max(0, m.width-uniseg.StringWidth(plines[0]))
+			s.WriteString(lineStyle.Render(style.Render(rest + strings.Repeat(" ", pad))))
 		// remaining lines
 		case len(plines) > i:
 			// current line placeholder text
@@ -1388,7 +1646,7 @@ func (m *Model) splitLine(row, col int) {
 
 // Paste is a command for pasting from the clipboard into the text input.
 func Paste() tea.Msg {
-	str, err := clipboard.ReadAll()
+	str, err := clipboard.System.ReadAll()
 	if err != nil {
 		return pasteErrMsg{err}
 	}
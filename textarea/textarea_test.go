@@ -1,6 +1,8 @@
 package textarea
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"unicode"
@@ -10,6 +12,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/mikeflynn/bubbles/cursor"
 )
 
 func TestVerticalScrolling(t *testing.T) {
@@ -1763,3 +1767,493 @@ func stripString(str string) string {
 
 	return strings.Join(lines, "\n")
 }
+
+func TestBlockSelection(t *testing.T) {
+	altShift := func(typ tea.KeyType) tea.Msg {
+		return tea.KeyMsg{Type: typ, Alt: true}
+	}
+
+	textarea := newTextArea()
+	textarea.SetValue("foo = 1\nbar = 2\nbazz = 3")
+	textarea, _ = textarea.Update(tea.KeyMsg{Type: tea.KeyCtrlHome})
+
+	// Select a zero-width column across all three lines and type into it.
+	textarea, _ = textarea.Update(altShift(tea.KeyShiftDown))
+	textarea, _ = textarea.Update(altShift(tea.KeyShiftDown))
+	textarea = sendString(textarea, "x.")
+
+	if got, want := textarea.Value(), "x.foo = 1\nx.bar = 2\nx.bazz = 3"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	// Backspace removes a character from each line.
+	textarea, _ = textarea.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if got, want := textarea.Value(), "xfoo = 1\nxbar = 2\nxbazz = 3"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	// Widen the selection to cover the next three columns and delete them.
+	for range 3 {
+		textarea, _ = textarea.Update(altShift(tea.KeyShiftRight))
+	}
+	top, left, bottom, right, ok := textarea.BlockSelection()
+	if !ok || top != 0 || bottom != 3 || left != 1 || right != 4 {
+		t.Fatalf("unexpected selection: %d %d %d %d %v", top, left, bottom, right, ok)
+	}
+	if view := stripString(textarea.View()); !strings.Contains(view, "xbazz = 3") {
+		t.Fatalf("expected selection to render the underlying text, got:\n%s", view)
+	}
+	textarea, _ = textarea.Update(tea.KeyMsg{Type: tea.KeyDelete})
+	if got, want := textarea.Value(), "x = 1\nx = 2\nxz = 3"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	// Typing replaces the selected rectangle.
+	for range 2 {
+		textarea, _ = textarea.Update(altShift(tea.KeyShiftRight))
+	}
+	textarea = sendString(textarea, "y")
+	if got, want := textarea.Value(), "xy 1\nxy 2\nxy= 3"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	// Any other key clears the selection and behaves normally.
+	textarea, _ = textarea.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	if _, _, _, _, ok := textarea.BlockSelection(); ok {
+		t.Fatal("expected selection to be cleared")
+	}
+	textarea = sendString(textarea, "!")
+	if got, want := textarea.Value(), "xy 1\nxy 2\nxy= 3!"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	t.Run("placeholder", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(newTextArea(), 30, 4)))
+	})
+	t.Run("value", func(t *testing.T) {
+		m := newTextArea()
+		m.SetValue("Hello\nWorld, this line should wrap around")
+		golden.RequireEqual(t, []byte(RenderString(m, 30, 4)))
+	})
+	t.Run("no line numbers", func(t *testing.T) {
+		m := newTextArea()
+		m.ShowLineNumbers = false
+		m.SetValue("Hello\nWorld")
+		golden.RequireEqual(t, []byte(RenderString(m, 30, 4)))
+	})
+}
+
+func TestNewWithOptions(t *testing.T) {
+	m, err := NewWithOptions(
+		WithHeight(3),
+		WithWidth(20),
+		WithPrompt("> "),
+		WithShowLineNumbers(false),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Width() != 18 || m.Height() != 3 {
+		t.Fatalf("expected an 18x3 text area, got %dx%d", m.Width(), m.Height())
+	}
+
+	for name, opts := range map[string][]Option{
+		"negative size":       {WithHeight(-1)},
+		"above max height":    {WithMaxHeight(5), WithHeight(10)},
+		"above max width":     {WithMaxWidth(10), WithWidth(20)},
+		"no room for text":    {WithPrompt("> "), WithWidth(6)},
+		"negative char limit": {WithCharLimit(-1)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewWithOptions(opts...); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestGotoLine(t *testing.T) {
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	textarea := newTextArea()
+	textarea.SetHeight(5)
+	textarea.SetValue(strings.Join(lines, "\n"))
+	textarea, _ = textarea.Update(tea.KeyMsg{Type: tea.KeyCtrlHome})
+
+	textarea, _ = textarea.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	if !textarea.GotoActive() {
+		t.Fatal("expected the go-to prompt to be open")
+	}
+	textarea = sendString(textarea, ":12:3")
+	if textarea.Line() != 11 || textarea.LineInfo().ColumnOffset != 2 {
+		t.Fatalf("expected the cursor to preview line 12 column 3, got %d:%d", textarea.Line(), textarea.LineInfo().ColumnOffset)
+	}
+	golden.RequireEqual(t, []byte(RenderString(textarea, 30, 5)))
+
+	textarea, cmd := textarea.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if textarea.GotoActive() || cmd == nil {
+		t.Fatal("expected the prompt to close and the line to be highlighted")
+	}
+	if !textarea.highlighted || textarea.highlightRow != 11 {
+		t.Fatalf("expected line 12 to be highlighted")
+	}
+	textarea, _ = textarea.Update(gotoHighlightMsg{id: textarea.id, tag: textarea.highlightTag})
+	if textarea.highlighted {
+		t.Fatal("expected the highlight to clear")
+	}
+
+	// Cancelling returns to where the cursor was.
+	textarea, _ = textarea.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	textarea = sendString(textarea, "2")
+	textarea, _ = textarea.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if textarea.Line() != 11 {
+		t.Fatalf("expected cancelling to restore line 12, got %d", textarea.Line()+1)
+	}
+
+	textarea.MoveTo(99, 99)
+	if textarea.Line() != 19 || textarea.LineInfo().ColumnOffset != len("line 20") {
+		t.Fatal("expected MoveTo to clamp to the end of the input")
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	textarea := newTextArea()
+	textarea.SetValue("foo\nbar")
+	textarea.SetReadOnly(true)
+	textarea.Focus()
+	if textarea.style != &textarea.ReadOnlyStyle {
+		t.Fatal("expected the read-only style to be used while focused")
+	}
+
+	textarea, _ = textarea.Update(tea.KeyMsg{Type: tea.KeyCtrlHome})
+	textarea = sendString(textarea, "x ")
+	for _, k := range []tea.KeyType{tea.KeyBackspace, tea.KeyDelete, tea.KeyEnter, tea.KeyCtrlK, tea.KeyCtrlT} {
+		textarea, _ = textarea.Update(tea.KeyMsg{Type: k})
+	}
+	textarea, _ = textarea.Update(pasteMsg("pasted"))
+	if got := textarea.Value(); got != "foo\nbar" {
+		t.Fatalf("expected edits to be ignored, got %q", got)
+	}
+
+	// Navigation and block selection still work.
+	textarea, _ = textarea.Update(tea.KeyMsg{Type: tea.KeyDown})
+	textarea, _ = textarea.Update(tea.KeyMsg{Type: tea.KeyRight})
+	textarea, _ = textarea.Update(tea.KeyMsg{Type: tea.KeyShiftRight, Alt: true})
+	if textarea.Line() != 1 || textarea.LineInfo().ColumnOffset != 2 {
+		t.Fatalf("expected the cursor to move, got %d:%d", textarea.Line(), textarea.LineInfo().ColumnOffset)
+	}
+	if _, left, _, right, ok := textarea.BlockSelection(); !ok || left != 1 || right != 2 {
+		t.Fatal("expected a block to be selected")
+	}
+	textarea, _ = textarea.Update(tea.KeyMsg{Type: tea.KeyDelete})
+	if got := textarea.Value(); got != "foo\nbar" {
+		t.Fatalf("expected the selection not to be deleted, got %q", got)
+	}
+
+	textarea.SetReadOnly(false)
+	textarea = sendString(textarea, "!")
+	if got := textarea.Value(); got != "foo\nb!r" {
+		t.Fatalf("expected editing to work again, got %q", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	textarea := newTextArea()
+	textarea.Validate = func(s string) error {
+		if strings.Count(s, "\n") > 1 {
+			return errors.New("too many lines")
+		}
+		return nil
+	}
+	textarea.Focus()
+
+	textarea.SetValue("foo\nbar")
+	if textarea.Err != nil {
+		t.Fatalf("expected no error, got %v", textarea.Err)
+	}
+	textarea, _ = textarea.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if textarea.Err == nil {
+		t.Fatal("expected a third line to be invalid")
+	}
+	textarea, _ = textarea.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if textarea.Err != nil {
+		t.Fatalf("expected the error to clear, got %v", textarea.Err)
+	}
+}
+
+func TestRuler(t *testing.T) {
+	t.Run("wrap", func(t *testing.T) {
+		textarea := newTextArea()
+		textarea.Ruler = 10
+		textarea.RulerMode = RulerWrap
+		textarea.Focus()
+
+		textarea = sendString(textarea, "the quick brown fox jumps")
+		if want := "the quick\nbrown fox\njumps"; textarea.Value() != want {
+			t.Fatalf("expected %q, got %q", want, textarea.Value())
+		}
+		if textarea.Line() != 2 || textarea.LineInfo().ColumnOffset != len("jumps") {
+			t.Fatalf("expected the cursor to follow the text, got %d:%d", textarea.Line(), textarea.LineInfo().ColumnOffset)
+		}
+
+		// A word longer than the ruler is broken at it.
+		textarea.Reset()
+		textarea = sendString(textarea, "abcdefghijklm")
+		if want := "abcdefghij\nklm"; textarea.Value() != want {
+			t.Fatalf("expected %q, got %q", want, textarea.Value())
+		}
+	})
+
+	t.Run("block", func(t *testing.T) {
+		textarea := newTextArea()
+		textarea.Ruler = 10
+		textarea.RulerMode = RulerBlock
+		textarea.Focus()
+
+		textarea = sendString(textarea, "the quick brown")
+		if want := "the quick "; textarea.Value() != want {
+			t.Fatalf("expected %q, got %q", want, textarea.Value())
+		}
+		textarea, _ = textarea.Update(pasteMsg("a\nbcdefghijkl"))
+		if want := "the quick "; textarea.Value() != want {
+			t.Fatalf("expected the paste to be rejected, got %q", textarea.Value())
+		}
+		textarea, _ = textarea.Update(pasteMsg("\nfox"))
+		if want := "the quick \nfox"; textarea.Value() != want {
+			t.Fatalf("expected %q, got %q", want, textarea.Value())
+		}
+	})
+
+	t.Run("guide", func(t *testing.T) {
+		textarea := newTextArea()
+		textarea.Ruler = 12
+		textarea.SetValue("short\nthis line goes past the ruler")
+		golden.RequireEqual(t, []byte(RenderString(textarea, 30, 4)))
+	})
+}
+
+func TestGutterMarkers(t *testing.T) {
+	textarea := newTextArea()
+	textarea.SetValue("one\ntwo\nthree")
+	textarea.SetMarkerFunc(2, func(line int) (Marker, bool) {
+		switch line {
+		case 0:
+			return ErrorMarker, true
+		case 2:
+			return BreakpointMarker, true
+		}
+		return Marker{}, false
+	})
+	golden.RequireEqual(t, []byte(RenderString(textarea, 20, 4)))
+
+	// Without a limit on the number of lines, the line numbers widen as the
+	// lines gain digits, and the text narrows to keep the width.
+	textarea = newTextArea()
+	textarea.MaxHeight = 0
+	textarea.SetWidth(20)
+	width := textarea.Width()
+	textarea.Focus()
+	textarea.SetValue(strings.Repeat("x\n", 9))
+	textarea, _ = textarea.Update(nil)
+	if textarea.Width() != width-1 {
+		t.Fatalf("expected the text to narrow by a column, got %d from %d", textarea.Width(), width)
+	}
+}
+
+// memClipboard is a Clipboard holding its text in memory.
+type memClipboard struct{ text string }
+
+func (c *memClipboard) ReadAll() (string, error) { return c.text, nil }
+
+func (c *memClipboard) WriteAll(text string) error {
+	c.text = text
+	return nil
+}
+
+func TestSelection(t *testing.T) {
+	cb := &memClipboard{}
+	textarea := newTextArea()
+	textarea.Clipboard = cb
+	textarea.SetValue("foo bar\nbaz qux")
+	textarea, _ = textarea.Update(tea.KeyMsg{Type: tea.KeyCtrlHome})
+
+	// run sends a key and runs the command it returns, feeding its message
+	// back.
+	run := func(msg tea.Msg) {
+		var cmd tea.Cmd
+		textarea, cmd = textarea.Update(msg)
+		if cmd == nil {
+			return
+		}
+		if msg := cmd(); msg != nil {
+			textarea, _ = textarea.Update(msg)
+		}
+	}
+
+	run(tea.KeyMsg{Type: tea.KeyCtrlShiftRight})
+	run(tea.KeyMsg{Type: tea.KeyShiftDown})
+	if got, want := textarea.SelectedText(), "foo bar\nbaz"; got != want {
+		t.Fatalf("expected %q selected, got %q", want, got)
+	}
+	if startRow, startCol, endRow, endCol, ok := textarea.Selection(); !ok || startRow != 0 || startCol != 0 || endRow != 1 || endCol != 3 {
+		t.Fatalf("unexpected selection: %d %d %d %d %v", startRow, startCol, endRow, endCol, ok)
+	}
+	if view := stripString(textarea.View()); !strings.Contains(view, "foo bar") {
+		t.Fatalf("expected selection to render the underlying text, got:\n%s", view)
+	}
+
+	run(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w"), Alt: true})
+	if cb.text != "foo bar\nbaz" {
+		t.Fatalf("expected the selection to be copied, got %q", cb.text)
+	}
+
+	run(tea.KeyMsg{Type: tea.KeyCtrlX})
+	if got, want := textarea.Value(), " qux"; got != want || cb.text != "foo bar\nbaz" {
+		t.Fatalf("expected %q after cut, got %q", want, got)
+	}
+
+	// Pasting replaces the selection.
+	run(tea.KeyMsg{Type: tea.KeyShiftEnd})
+	run(tea.KeyMsg{Type: tea.KeyCtrlV})
+	if got, want := textarea.Value(), "foo bar\nbaz"; got != want {
+		t.Fatalf("expected %q after paste, got %q", want, got)
+	}
+
+	// Typing replaces the selection, and moving clears it.
+	run(tea.KeyMsg{Type: tea.KeyShiftLeft})
+	run(tea.KeyMsg{Type: tea.KeyShiftLeft})
+	textarea = sendString(textarea, "r")
+	if got, want := textarea.Value(), "foo bar\nbr"; got != want {
+		t.Fatalf("expected %q after typing, got %q", want, got)
+	}
+	run(tea.KeyMsg{Type: tea.KeyShiftLeft})
+	run(tea.KeyMsg{Type: tea.KeyLeft})
+	if _, _, _, _, ok := textarea.Selection(); ok {
+		t.Fatal("expected moving to clear the selection")
+	}
+
+	textarea.SelectAll()
+	run(tea.KeyMsg{Type: tea.KeyBackspace})
+	if textarea.Value() != "" {
+		t.Fatalf("expected everything to be deleted, got %q", textarea.Value())
+	}
+}
+
+func TestPromptInfoFunc(t *testing.T) {
+	m := newTextArea()
+	m.ShowLineNumbers = false
+	m.SetWidth(12)
+	m.SetHeight(4)
+	m.SetPromptInfoFunc(func(info PromptInfo) string {
+		switch {
+		case info.Line < 0:
+			return ""
+		case info.Row > 0:
+			return "… "
+		default:
+			return fmt.Sprintf("%d> ", info.Line+1)
+		}
+	})
+	m.SetValue("one two three\nfour")
+
+	want := "1> one two\n … three\n2> four"
+	if got := stripString(m.View()); got != want {
+		t.Errorf("expected continuation prompts:\n%s\ngot:\n%s", want, got)
+	}
+
+	// The prompt column widens when a prompt does, keeping the width.
+	for range 8 {
+		m.InsertString("\nx")
+	}
+	m, _ = m.Update(nil)
+	if m.promptWidth != 4 || m.Width() != 8 {
+		t.Errorf("expected the prompts to widen to 4 cells leaving 8 for text, got %d and %d", m.promptWidth, m.Width())
+	}
+	if got := stripString(m.View()); !strings.HasPrefix(got, " 4> x\n 5> x") {
+		t.Errorf("expected prompts padded to the widest, got:\n%s", got)
+	}
+
+	// Placeholder rows continue the first line.
+	m.Reset()
+	m.Placeholder = "type a message here"
+	want = "1> type a\n … message\n … here"
+	if got := stripString(m.View()); got != want {
+		t.Errorf("expected the placeholder to wrap with continuation prompts:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	textarea, err := NewWithOptions(WithExpand(true), WithMaxHeight(3), WithWidth(20), WithShowLineNumbers(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	textarea.Cursor.SetMode(cursor.CursorStatic)
+	textarea.Focus()
+	if textarea.Height() != 1 {
+		t.Fatalf("expected an empty textarea to be 1 row high, got %d", textarea.Height())
+	}
+
+	// resized returns the heights reported by the command, if any.
+	var resized func(tea.Cmd) []int
+	resized = func(cmd tea.Cmd) []int {
+		if cmd == nil {
+			return nil
+		}
+		switch msg := cmd().(type) {
+		case tea.BatchMsg:
+			var heights []int
+			for _, c := range msg {
+				heights = append(heights, resized(c)...)
+			}
+			return heights
+		case ResizeMsg:
+			if msg.ID != textarea.ID() {
+				t.Fatalf("expected the textarea's ID, got %d", msg.ID)
+			}
+			return []int{msg.Height}
+		}
+		return nil
+	}
+
+	var heights []int
+	for _, msg := range []tea.Msg{
+		keyPress('a'), tea.KeyMsg{Type: tea.KeyEnter},
+		keyPress('b'), tea.KeyMsg{Type: tea.KeyEnter},
+		keyPress('c'), tea.KeyMsg{Type: tea.KeyEnter},
+		keyPress('d'),
+	} {
+		var cmd tea.Cmd
+		textarea, cmd = textarea.Update(msg)
+		heights = append(heights, resized(cmd)...)
+		textarea.View()
+	}
+	if want := []int{2, 3}; fmt.Sprint(heights) != fmt.Sprint(want) {
+		t.Fatalf("expected the textarea to grow to %v, got %v", want, heights)
+	}
+	if textarea.Value() != "a\nb\nc\nd" {
+		t.Fatalf("expected lines past MaxHeight to be accepted, got %q", textarea.Value())
+	}
+	if view := stripString(textarea.View()); !strings.Contains(view, "d") || strings.Contains(view, "a") {
+		t.Fatalf("expected the content to scroll to the cursor, got %q", view)
+	}
+
+	// Long lines wrap onto more rows.
+	textarea.SetValue("")
+	textarea, _ = textarea.Update(nil)
+	textarea = sendString(textarea, strings.Repeat("x", 30))
+	if textarea.Height() != 2 {
+		t.Fatalf("expected a wrapped line to take 2 rows, got %d", textarea.Height())
+	}
+
+	// It shrinks back as content is deleted, down to MinHeight.
+	textarea.MinHeight = 2
+	var cmd tea.Cmd
+	textarea, cmd = textarea.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	if textarea.Height() != 2 || len(resized(cmd)) != 0 {
+		t.Fatalf("expected the textarea to stay at MinHeight, got %d", textarea.Height())
+	}
+}
@@ -0,0 +1,48 @@
+package textinput
+
+import (
+	"strconv"
+
+	"github.com/rivo/uniseg"
+)
+
+// prefixView returns the prefix drawn between the prompt and the value.
+func (m Model) prefixView() string {
+	if m.Prefix == "" {
+		return ""
+	}
+	return m.PrefixStyle.Inline(true).Render(m.Prefix)
+}
+
+// suffixView returns the suffix and character count drawn after the value.
+func (m Model) suffixView() string {
+	s := m.Suffix
+	if m.ShowCount {
+		count := strconv.Itoa(len(m.value))
+		if m.CharLimit > 0 {
+			count += "/" + strconv.Itoa(m.CharLimit)
+		}
+		s += " " + count
+	}
+	if s == "" {
+		return ""
+	}
+	return m.SuffixStyle.Inline(true).Render(s)
+}
+
+// autoSize sets Width from the width of the value, or of the placeholder if
+// the value is empty, between MinWidth and MaxWidth, if AutoWidth is on.
+func (m *Model) autoSize() {
+	if !m.AutoWidth {
+		return
+	}
+	w := m.displayWidth(m.value)
+	if len(m.value) == 0 {
+		w = uniseg.StringWidth(m.Placeholder)
+	}
+	w = max(w, m.MinWidth)
+	if m.MaxWidth > 0 {
+		w = min(w, m.MaxWidth)
+	}
+	m.Width = w
+}
@@ -0,0 +1,75 @@
+package textinput
+
+import (
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var lastID int64
+
+func nextID() int {
+	return int(atomic.AddInt64(&lastID, 1))
+}
+
+// ChangedMsg is sent when the user changes the value of the input, such as
+// by typing, deleting or pasting, so that consumers like search-as-you-type
+// lists can react without comparing values on every key press. Value is the
+// new value, and ID the ID of the input it belongs to.
+//
+// If ChangeDebounce is set, it's only sent once the value has stopped
+// changing for that long. Changes made with SetValue and other methods don't
+// send it, and neither do secure inputs, which would copy their value out of
+// locked memory.
+type ChangedMsg struct {
+	ID    int
+	Value string
+}
+
+// changeDebounceMsg is sent after ChangeDebounce has passed since a change.
+// It only results in a ChangedMsg if no change was made since, in which case
+// tag is still the current change tag.
+type changeDebounceMsg struct {
+	id  int
+	tag int
+}
+
+// ID returns the input's unique ID, which is carried by ChangedMsg.
+func (m Model) ID() int {
+	return m.id
+}
+
+// changed returns a command sending a ChangedMsg for the current value, or
+// one waiting for ChangeDebounce to pass first.
+func (m *Model) changed() tea.Cmd {
+	m.changeTag++
+	if m.ChangeDebounce > 0 {
+		id, tag := m.id, m.changeTag
+		return tea.Tick(m.ChangeDebounce, func(time.Time) tea.Msg {
+			return changeDebounceMsg{id: id, tag: tag}
+		})
+	}
+	return m.changedMsg()
+}
+
+// changedMsg returns a command sending a ChangedMsg for the current value.
+func (m Model) changedMsg() tea.Cmd {
+	msg := ChangedMsg{ID: m.id, Value: string(m.value)}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// updateChange handles the debounce timer of a change. It returns false for
+// other messages.
+func (m Model) updateChange(msg tea.Msg) (tea.Cmd, bool) {
+	debounce, ok := msg.(changeDebounceMsg)
+	if !ok {
+		return nil, false
+	}
+	if debounce.id != m.id || debounce.tag != m.changeTag {
+		return nil, true
+	}
+	return m.changedMsg(), true
+}
@@ -0,0 +1,36 @@
+package textinput
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// CharStyleFunc returns the style of a character of the value, given its
+// index in the value's runes and the rune, such as to color the invalid part
+// of an expression or the brackets matching the one under the cursor. For a
+// character made of several runes, such as an emoji sequence, it's given the
+// first one.
+type CharStyleFunc func(index int, r rune) lipgloss.Style
+
+// styleValue renders the runes of the value from index start, in TextStyle
+// and the styles given by CharStyleFunc, if set. Characters are styled as
+// they're echoed, so that masked characters are styled too.
+func (m Model) styleValue(runes []rune, start int) string {
+	if m.CharStyleFunc == nil {
+		return m.TextStyle.Inline(true).Render(m.echoTransform(string(runes)))
+	}
+	var b strings.Builder
+	bounds := graphemeBounds(runes)
+	for i := 1; i < len(bounds); i++ {
+		from, to := bounds[i-1], bounds[i]
+		b.WriteString(m.charStyle(start+from, runes[from]).Render(m.echoTransform(string(runes[from:to]))))
+	}
+	return b.String()
+}
+
+// charStyle returns the style of the character at the given index, given
+// the first rune of it.
+func (m Model) charStyle(index int, r rune) lipgloss.Style {
+	return m.CharStyleFunc(index, r).Inherit(m.TextStyle).Inline(true)
+}
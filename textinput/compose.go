@@ -0,0 +1,109 @@
+package textinput
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// CompositionMsg reports text being composed with an input method (IME),
+// such as kana typed before being converted to kanji. Text is the whole of
+// the composition so far, and is sent again each time it changes. It's drawn
+// at the cursor, in CompositionStyle, but isn't part of the value until it's
+// committed with CompositionCommitMsg.
+//
+// Bubble Tea doesn't report compositions itself, as most terminals draw them
+// and only send the committed text. These messages are for apps and terminal
+// integrations which receive composition events, such as from a terminal
+// that forwards them or an IME bridge.
+type CompositionMsg struct {
+	Text string
+}
+
+// CompositionCommitMsg ends a composition, inserting Text at the cursor.
+type CompositionCommitMsg struct {
+	Text string
+}
+
+// CompositionCancelMsg ends a composition without inserting anything, and
+// restores the value and cursor to how they were when it started.
+type CompositionCancelMsg struct{}
+
+// Composing returns whether text is being composed with an input method. Key
+// presses are ignored meanwhile, as they belong to the input method, so apps
+// may want to hold back actions such as submitting on enter too.
+func (m Model) Composing() bool {
+	return m.composing
+}
+
+// updateComposition handles composition messages, and key presses while
+// composing. It returns false for other messages.
+func (m *Model) updateComposition(msg tea.Msg) bool {
+	switch msg := msg.(type) {
+	case CompositionMsg:
+		if !m.composing {
+			m.composing = true
+			m.composeValue = slices.Clone(m.value)
+			m.composePos = m.pos
+		}
+		clear(m.composition)
+		m.composition = m.san().Sanitize([]rune(msg.Text))
+	case CompositionCommitMsg:
+		m.endComposition()
+		m.insertRunesFromUserInput([]rune(msg.Text))
+		m.updateSuggestions()
+		m.keepSecure()
+		m.handleOverflow()
+	case CompositionCancelMsg:
+		m.cancelComposition()
+	case tea.KeyMsg:
+		return m.composing
+	default:
+		return false
+	}
+	return true
+}
+
+// cancelComposition ends the composition, if any, restoring the value and
+// cursor to how they were when it started.
+func (m *Model) cancelComposition() {
+	if !m.composing {
+		return
+	}
+	if !slices.Equal(m.value, m.composeValue) {
+		m.setValueInternal(slices.Clone(m.composeValue), nil)
+		m.Err = m.validate(m.value)
+	}
+	m.SetCursor(m.composePos)
+	m.endComposition()
+	m.handleOverflow()
+}
+
+func (m *Model) endComposition() {
+	clear(m.composition)
+	clear(m.composeValue)
+	m.composing = false
+	m.composition, m.composeValue, m.composePos = nil, nil, 0
+}
+
+// withComposition returns the model with the composition inserted into the
+// value at the cursor, and the cursor after it, for rendering. The visible
+// part of the value is moved so that the composition is in view.
+func (m Model) withComposition() Model {
+	v := make([]rune, 0, len(m.value)+len(m.composition))
+	v = append(v, m.value[:m.pos]...)
+	v = append(v, m.composition...)
+	v = append(v, m.value[m.pos:]...)
+	m.value = v
+	m.pos += len(m.composition)
+	m.handleOverflow()
+	return m
+}
+
+// compositionView renders the part of the composition in view.
+func (m Model) compositionView(composed []rune) string {
+	if len(composed) == 0 {
+		return ""
+	}
+	return m.CompositionStyle.Inline(true).Render(m.echoTransform(string(composed)))
+}
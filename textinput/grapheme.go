@@ -0,0 +1,87 @@
+package textinput
+
+import (
+	"strings"
+
+	rw "github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
+)
+
+// The value is stored as runes, but the cursor moves and deletes by grapheme
+// cluster, so that an emoji sequence such as 👩‍👩‍👧‍👦 or a letter with combining
+// accents is edited as the single character it's displayed as.
+
+// graphemeBounds returns the rune indexes at which the grapheme clusters of v
+// start, followed by len(v).
+func graphemeBounds(v []rune) []int {
+	bounds := []int{0}
+	g := uniseg.NewGraphemes(string(v))
+	n := 0
+	for g.Next() {
+		n += len(g.Runes())
+		bounds = append(bounds, n)
+	}
+	return bounds
+}
+
+// graphemeStart returns the start of the grapheme cluster before pos, or 0.
+func graphemeStart(v []rune, pos int) int {
+	start := 0
+	for _, b := range graphemeBounds(v) {
+		if b >= pos {
+			break
+		}
+		start = b
+	}
+	return start
+}
+
+// graphemeEnd returns the end of the grapheme cluster starting at or
+// containing pos, or len(v).
+func graphemeEnd(v []rune, pos int) int {
+	for _, b := range graphemeBounds(v) {
+		if b > pos {
+			return b
+		}
+	}
+	return len(v)
+}
+
+// graphemeFloor returns the start of the grapheme cluster containing pos, so
+// that a cursor never lands in the middle of one.
+func graphemeFloor(v []rune, pos int) int {
+	floor := 0
+	for _, b := range graphemeBounds(v) {
+		if b > pos {
+			break
+		}
+		floor = b
+	}
+	return floor
+}
+
+// graphemePrefix returns the longest prefix of v which is at most n runes long
+// and doesn't split a grapheme cluster.
+func graphemePrefix(v []rune, n int) []rune {
+	if n >= len(v) {
+		return v
+	}
+	return v[:graphemeFloor(v, n)]
+}
+
+// displayWidth returns the number of cells v takes up once echoed.
+func (m Model) displayWidth(v []rune) int {
+	switch m.EchoMode {
+	case EchoPassword:
+		return uniseg.GraphemeClusterCount(string(v)) * rw.RuneWidth(m.EchoCharacter)
+	case EchoNone:
+		return 0
+	default:
+		return uniseg.StringWidth(string(v))
+	}
+}
+
+// maskGraphemes returns one echo character per grapheme cluster of v.
+func (m Model) maskGraphemes(v string) string {
+	return strings.Repeat(string(m.EchoCharacter), uniseg.GraphemeClusterCount(v))
+}
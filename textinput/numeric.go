@@ -0,0 +1,320 @@
+package textinput
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mikeflynn/bubbles/key"
+)
+
+// NumberFormat describes how numbers are written in a locale.
+type NumberFormat struct {
+	Decimal   rune
+	Thousands rune
+}
+
+// Number formats for common locales.
+var (
+	NumberFormatEnglish = NumberFormat{Decimal: '.', Thousands: ','}
+	NumberFormatGerman  = NumberFormat{Decimal: ',', Thousands: '.'}
+	NumberFormatFrench  = NumberFormat{Decimal: ',', Thousands: ' '}
+	NumberFormatPlain   = NumberFormat{Decimal: '.'}
+)
+
+// Numeric configures the numeric mode of a text input, set with SetNumeric.
+// In numeric mode only digits, a leading minus sign and the decimal separator
+// can be typed, thousands separators are inserted as the user types, and the
+// Increment and Decrement keybindings and the mouse wheel step the value.
+type Numeric struct {
+	// Min and Max bound the value. They're only enforced when Min < Max.
+	// Stepping clamps the value to them, while typing a value outside them
+	// sets Err.
+	Min, Max float64
+
+	// Step is the amount the value changes by per step. If 0 or less it
+	// defaults to 1.
+	Step float64
+
+	// Precision is the number of decimal places values are rounded to when
+	// stepping. If 0, the value is an integer and no decimal separator can
+	// be typed.
+	Precision int
+
+	// LargeStep is the amount the value changes by per step with the
+	// IncrementLarge and DecrementLarge keybindings. If 0 or less it defaults
+	// to ten steps.
+	LargeStep float64
+
+	// Unit is displayed after the value, such as "ms" or "%".
+	Unit string
+
+	// Gauge is the width of a gauge displayed after the value and unit,
+	// showing where the value sits between Min and Max. If 0 or less, or if
+	// the value isn't bounded, no gauge is displayed.
+	Gauge int
+
+	// Format is the number format used to display and parse the value. It
+	// defaults to NumberFormatEnglish.
+	Format NumberFormat
+}
+
+// SetNumeric turns on numeric mode with the given options and reformats the
+// current value accordingly.
+func (m *Model) SetNumeric(n Numeric) {
+	if n.Step <= 0 {
+		n.Step = 1
+	}
+	if n.LargeStep <= 0 {
+		n.LargeStep = 10 * n.Step //nolint:mnd
+	}
+	if n.Format == (NumberFormat{}) {
+		n.Format = NumberFormatEnglish
+	}
+	m.numeric = &n
+	m.regroup()
+	m.Err = m.validate(m.value)
+}
+
+// ClearNumeric turns off numeric mode. The value is kept as displayed.
+func (m *Model) ClearNumeric() {
+	m.numeric = nil
+}
+
+// Numeric returns the numeric mode options, and whether numeric mode is on.
+func (m Model) Numeric() (Numeric, bool) {
+	if m.numeric == nil {
+		return Numeric{}, false
+	}
+	return *m.numeric, true
+}
+
+// Float parses the value in numeric mode, ignoring thousands separators.
+func (m Model) Float() (float64, error) {
+	if m.numeric == nil {
+		return 0, errors.New("textinput: not in numeric mode")
+	}
+	return m.numeric.parse(m.value)
+}
+
+// SetFloat sets the value in numeric mode, clamped to the bounds and
+// formatted to the precision.
+func (m *Model) SetFloat(v float64) {
+	if m.numeric == nil {
+		return
+	}
+	m.value = []rune(m.numeric.format(m.numeric.clamp(v)))
+	m.SetCursor(len(m.value))
+	m.Err = m.validate(m.value)
+	m.handleOverflow()
+}
+
+// step changes the value by n times the given step. An empty or unparsable
+// value is treated as the lower bound, or 0 if there's none.
+func (m *Model) step(n int, step float64) {
+	v, err := m.Float()
+	if err != nil {
+		v = m.numeric.clamp(0)
+		n = 0
+	}
+	m.SetFloat(v + float64(n)*step)
+}
+
+// updateNumeric handles the stepping keys and mouse wheel in numeric mode,
+// reporting whether the message was handled.
+func (m *Model) updateNumeric(msg tea.Msg) bool {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.Increment):
+			m.step(1, m.numeric.Step)
+			return true
+		case key.Matches(msg, m.KeyMap.Decrement):
+			m.step(-1, m.numeric.Step)
+			return true
+		case key.Matches(msg, m.KeyMap.IncrementLarge):
+			m.step(1, m.numeric.LargeStep)
+			return true
+		case key.Matches(msg, m.KeyMap.DecrementLarge):
+			m.step(-1, m.numeric.LargeStep)
+			return true
+		}
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress {
+			break
+		}
+		switch msg.Button { //nolint:exhaustive
+		case tea.MouseButtonWheelUp:
+			m.step(1, m.numeric.Step)
+			return true
+		case tea.MouseButtonWheelDown:
+			m.step(-1, m.numeric.Step)
+			return true
+		}
+	}
+	return false
+}
+
+// numericRunes drops the runes that can't be typed in numeric mode. A minus
+// sign is only kept at the start of the value, and a decimal separator only
+// if there's none yet and the precision allows it.
+func (m Model) numericRunes(v []rune) []rune {
+	f := m.numeric.Format
+	out := v[:0:0]
+	hasDecimal := strings.ContainsRune(string(m.value), f.Decimal)
+	for i, r := range v {
+		switch {
+		case r >= '0' && r <= '9':
+		case r == '-' && m.pos == 0 && i == 0 && (len(m.value) == 0 || m.value[0] != '-'):
+		case r == f.Decimal && m.numeric.Precision > 0 && !hasDecimal:
+			hasDecimal = true
+		default:
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// regroup reinserts the thousands separators into the value, keeping the
+// cursor after the same digit.
+func (m *Model) regroup() {
+	if m.numeric == nil || m.numeric.Format.Thousands == 0 {
+		return
+	}
+	sep := m.numeric.Format.Thousands
+	before := 0
+	for _, r := range m.value[:m.pos] {
+		if r != sep {
+			before++
+		}
+	}
+
+	m.value = []rune(m.numeric.group(string(m.value)))
+
+	pos := 0
+	for before > 0 && pos < len(m.value) {
+		if m.value[pos] != sep {
+			before--
+		}
+		pos++
+	}
+	m.pos = pos
+	m.handleOverflow()
+}
+
+// unitView returns the unit and gauge displayed after the value in numeric
+// mode.
+func (m Model) unitView() string {
+	if m.numeric == nil {
+		return ""
+	}
+	var s string
+	if m.numeric.Unit != "" {
+		s += m.UnitStyle.Inline(true).Render(" " + m.numeric.Unit)
+	}
+	return s + m.gaugeView()
+}
+
+// gaugeView returns the gauge showing where the value sits between the
+// bounds, with the filled part in GaugeStyle and the rest in UnitStyle.
+func (m Model) gaugeView() string {
+	n := m.numeric
+	if n.Gauge <= 0 || !n.bounded() {
+		return ""
+	}
+	filled := 0
+	if v, err := m.Float(); err == nil {
+		pct := (n.clamp(v) - n.Min) / (n.Max - n.Min)
+		filled = int(math.Round(pct * float64(n.Gauge)))
+	}
+	return " " + m.GaugeStyle.Inline(true).Render(strings.Repeat("█", filled)) +
+		m.UnitStyle.Inline(true).Render(strings.Repeat("░", n.Gauge-filled))
+}
+
+func (n Numeric) bounded() bool {
+	return n.Min < n.Max
+}
+
+func (n Numeric) clamp(v float64) float64 {
+	if !n.bounded() {
+		return v
+	}
+	return math.Max(n.Min, math.Min(n.Max, v))
+}
+
+// parse parses a value written in the number format, ignoring thousands
+// separators.
+func (n Numeric) parse(v []rune) (float64, error) {
+	var b strings.Builder
+	for _, r := range v {
+		switch r {
+		case n.Format.Thousands:
+		case n.Format.Decimal:
+			b.WriteRune('.')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	f, err := strconv.ParseFloat(b.String(), 64)
+	if err != nil {
+		return 0, fmt.Errorf("textinput: invalid number %q", string(v))
+	}
+	return f, nil
+}
+
+// format writes v in the number format, rounded to the precision.
+func (n Numeric) format(v float64) string {
+	s := strconv.FormatFloat(v, 'f', n.Precision, 64)
+	if s == "-0" || strings.HasPrefix(s, "-0.") && strings.Trim(s[3:], "0") == "" {
+		s = s[1:]
+	}
+	return n.group(strings.Replace(s, ".", string(n.Format.Decimal), 1))
+}
+
+// group strips any thousands separators from s and inserts them again
+// between every three digits of the integer part.
+func (n Numeric) group(s string) string {
+	sep := n.Format.Thousands
+	if sep == 0 {
+		return s
+	}
+	s = strings.ReplaceAll(s, string(sep), "")
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	integer, fraction := s, ""
+	if i := strings.IndexRune(s, n.Format.Decimal); i >= 0 {
+		integer, fraction = s[:i], s[i:]
+	}
+
+	var b strings.Builder
+	for i, r := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteRune(sep)
+		}
+		b.WriteRune(r)
+	}
+	return sign + b.String() + fraction
+}
+
+// validate reports whether a value is a number within the bounds. An empty
+// value or a lone minus sign is allowed while typing.
+func (n Numeric) validate(v []rune) error {
+	if len(v) == 0 || string(v) == "-" {
+		return nil
+	}
+	f, err := n.parse(v)
+	if err != nil {
+		return err
+	}
+	if n.bounded() && (f < n.Min || f > n.Max) {
+		return fmt.Errorf("textinput: %s is outside %s to %s", string(v), n.format(n.Min), n.format(n.Max))
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package textinput
+
+import (
+	"fmt"
+	"runtime"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// defaultSecureCapacity is the number of runes a secure buffer holds before it
+// has to grow, when there's no CharLimit.
+const defaultSecureCapacity = 256
+
+// secureBuffer holds the value of a secure input in memory which is locked,
+// where the platform allows, so that it isn't swapped to disk, and which is
+// zeroed as soon as the value moves out of it or is wiped.
+type secureBuffer struct {
+	runes  []rune
+	locked bool
+}
+
+func newSecureBuffer(capacity int) *secureBuffer {
+	b := &secureBuffer{runes: make([]rune, 0, capacity)}
+	b.locked = lockMemory(b.bytes()) == nil
+	runtime.SetFinalizer(b, (*secureBuffer).release)
+	return b
+}
+
+// bytes returns the buffer's whole capacity as bytes.
+func (b *secureBuffer) bytes() []byte {
+	full := b.runes[:cap(b.runes)]
+	return unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(full))), len(full)*int(unsafe.Sizeof(rune(0))))
+}
+
+// contains reports whether v's memory starts within the buffer.
+func (b *secureBuffer) contains(v []rune) bool {
+	if cap(v) == 0 || cap(b.runes) == 0 {
+		return false
+	}
+	full := b.runes[:cap(b.runes)]
+	start := uintptr(unsafe.Pointer(unsafe.SliceData(v[:1])))
+	base := uintptr(unsafe.Pointer(unsafe.SliceData(full)))
+	return start >= base && start < base+uintptr(len(full))*unsafe.Sizeof(rune(0))
+}
+
+// keep copies v into the buffer, growing it if needed, and returns the part
+// of the buffer holding it. Since edits made within the buffer's capacity
+// happen in place, the copy only leaves the buffer when it grows, in which
+// case v is zeroed after being copied. The rest of the buffer is zeroed too,
+// so nothing is left of deleted runes.
+func (b *secureBuffer) keep(v []rune) []rune {
+	if len(v) > cap(b.runes) {
+		grown := newSecureBuffer(max(2*cap(b.runes), len(v)))
+		copy(grown.runes[:len(v)], v)
+		clear(v)
+		b.release()
+		*b = *grown
+		grown.runes, grown.locked = nil, false
+		b.runes = b.runes[:len(v)]
+		return b.runes
+	}
+
+	full := b.runes[:cap(b.runes)]
+	inside := b.contains(v)
+	copy(full, v)
+	if !inside {
+		clear(v)
+	}
+	clear(full[len(v):])
+	b.runes = full[:len(v)]
+	return b.runes
+}
+
+// release zeroes the buffer and unlocks its memory.
+func (b *secureBuffer) release() {
+	if cap(b.runes) == 0 {
+		return
+	}
+	clear(b.runes[:cap(b.runes)])
+	if b.locked {
+		_ = unlockMemory(b.bytes())
+	}
+	b.runes, b.locked = nil, false
+}
+
+// SetSecure sets whether the value is kept in secure memory, for credential
+// prompts using EchoPassword or EchoNone. Secure memory is locked where the
+// platform allows, so that it isn't swapped to disk, and copies of the value
+// made while editing it are zeroed. Secure inputs don't record history, and
+// their value is left out when the model is printed. Turning it off zeroes
+// the secure memory.
+//
+// Note that Value returns a string, which can't be zeroed. Use ValueBytes and
+// zero the result once it's been used, and call Wipe when the value is no
+// longer needed.
+//
+// Copies of a secure model share its secure memory, so keep editing only one
+// of them: an edit, Wipe or SetSecure(false) made through one copy changes
+// the value seen by the others.
+func (m *Model) SetSecure(v bool) {
+	switch {
+	case v && m.secure == nil:
+		m.secure = newSecureBuffer(max(m.CharLimit, defaultSecureCapacity))
+		m.value = m.secure.keep(m.value)
+		clear(m.historyDraft)
+		m.historyDraft = nil
+	case !v && m.secure != nil:
+		m.value = append([]rune(nil), m.value...)
+		m.secure.release()
+		m.secure = nil
+	}
+}
+
+// Secure returns whether the value is kept in secure memory.
+func (m Model) Secure() bool {
+	return m.secure != nil
+}
+
+// keepSecure moves the value into secure memory, if the input is secure.
+func (m *Model) keepSecure() {
+	if m.secure != nil {
+		m.value = m.secure.keep(m.value)
+	}
+}
+
+// ValueBytes returns the value as UTF-8 in a new slice which the caller can
+// zero once it's done with it, unlike the string returned by Value.
+func (m Model) ValueBytes() []byte {
+	b := make([]byte, 0, len(m.value)*utf8.UTFMax)
+	for _, r := range m.value {
+		b = utf8.AppendRune(b, r)
+	}
+	return b
+}
+
+// Wipe zeroes the value in memory and empties the input. Secure inputs stay
+// secure.
+func (m *Model) Wipe() {
+	if m.secure != nil {
+		m.value = m.secure.keep(nil)
+	} else {
+		clear(m.value)
+	}
+	clear(m.historyDraft)
+	clear(m.historyQuery)
+	m.Reset()
+}
+
+// printable has the fields of Model without its methods, so that formatting
+// it doesn't call Format again.
+type printable Model
+
+// String implements fmt.Stringer. The value of a secure input is left out.
+func (m Model) String() string {
+	return fmt.Sprint(m.redacted())
+}
+
+// Format implements fmt.Formatter, so that the value of a secure input is
+// left out whichever verb the model is printed with, including %#v.
+func (m Model) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, fmt.FormatString(f, verb), m.redacted())
+}
+
+// redacted returns the model without the value, if it's secure.
+func (m Model) redacted() printable {
+	if m.secure != nil {
+		m.value = nil
+		m.historyDraft = nil
+		m.historyQuery = nil
+		m.composition = nil
+		m.composeValue = nil
+	}
+	return printable(m)
+}
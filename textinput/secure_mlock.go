@@ -0,0 +1,15 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package textinput
+
+import "syscall"
+
+// lockMemory keeps b from being swapped to disk.
+func lockMemory(b []byte) error {
+	return syscall.Mlock(b) //nolint:wrapcheck
+}
+
+func unlockMemory(b []byte) error {
+	return syscall.Munlock(b) //nolint:wrapcheck
+}
@@ -0,0 +1,16 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package textinput
+
+import "errors"
+
+// lockMemory is unsupported on this platform, so secure memory may be
+// swapped to disk.
+func lockMemory([]byte) error {
+	return errors.New("textinput: locking memory is unsupported")
+}
+
+func unlockMemory([]byte) error {
+	return nil
+}
@@ -11,10 +11,11 @@ import (
 	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	rw "github.com/mattn/go-runewidth"
 	"github.com/mikeflynn/bubbles/cursor"
 	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
 	"github.com/mikeflynn/bubbles/runeutil"
+	"github.com/mikeflynn/bubbles/textutil"
 	"github.com/rivo/uniseg"
 )
 
@@ -32,7 +33,8 @@ const (
 	EchoNormal EchoMode = iota
 
 	// EchoPassword displays the EchoCharacter mask instead of actual
-	// characters. This is commonly used for password fields.
+	// characters, one per grapheme cluster. This is commonly used for
+	// password fields.
 	EchoPassword
 
 	// EchoNone displays nothing as characters are entered. This is commonly
@@ -61,6 +63,14 @@ type KeyMap struct {
 	AcceptSuggestion        key.Binding
 	NextSuggestion          key.Binding
 	PrevSuggestion          key.Binding
+	PrevHistory             key.Binding
+	NextHistory             key.Binding
+	SearchHistory           key.Binding
+	CancelHistorySearch     key.Binding
+	Increment               key.Binding
+	Decrement               key.Binding
+	IncrementLarge          key.Binding
+	DecrementLarge          key.Binding
 }
 
 // DefaultKeyMap is the default set of key bindings for navigating and acting
@@ -82,6 +92,14 @@ var DefaultKeyMap = KeyMap{
 	AcceptSuggestion:        key.NewBinding(key.WithKeys("tab")),
 	NextSuggestion:          key.NewBinding(key.WithKeys("down", "ctrl+n")),
 	PrevSuggestion:          key.NewBinding(key.WithKeys("up", "ctrl+p")),
+	PrevHistory:             key.NewBinding(key.WithKeys("up", "ctrl+p")),
+	NextHistory:             key.NewBinding(key.WithKeys("down", "ctrl+n")),
+	SearchHistory:           key.NewBinding(key.WithKeys("ctrl+r")),
+	CancelHistorySearch:     key.NewBinding(key.WithKeys("esc", "ctrl+g")),
+	Increment:               key.NewBinding(key.WithKeys("up")),
+	Decrement:               key.NewBinding(key.WithKeys("down")),
+	IncrementLarge:          key.NewBinding(key.WithKeys("alt+up")),
+	DecrementLarge:          key.NewBinding(key.WithKeys("alt+down")),
 }
 
 // Model is the Bubble Tea model for this text input element.
@@ -104,8 +122,18 @@ type Model struct {
 	// https://github.com/charmbracelet/lipgloss
 	PromptStyle      lipgloss.Style
 	TextStyle        lipgloss.Style
+	PrefixStyle      lipgloss.Style
+	SuffixStyle      lipgloss.Style
 	PlaceholderStyle lipgloss.Style
 	CompletionStyle  lipgloss.Style
+	UnitStyle        lipgloss.Style
+	GaugeStyle       lipgloss.Style
+	CompositionStyle lipgloss.Style
+
+	// CharStyleFunc, if set, styles each character of the value over
+	// TextStyle, as it's drawn after EchoMode is applied. It's called each
+	// time the input is rendered.
+	CharStyleFunc CharStyleFunc
 
 	// Deprecated: use Cursor.Style instead.
 	CursorStyle lipgloss.Style
@@ -119,6 +147,29 @@ type Model struct {
 	// viewport. If 0 or less this setting is ignored.
 	Width int
 
+	// AutoWidth sets Width from the width of the value as it changes, so
+	// the input grows and shrinks with its content, between MinWidth and
+	// MaxWidth. While the value is empty the placeholder's width is used.
+	// If MaxWidth is 0 or less the input grows without limit; otherwise
+	// the value scrolls once it's wider.
+	AutoWidth bool
+	MinWidth  int
+	MaxWidth  int
+
+	// Prefix and Suffix are drawn before and after the value, such as an
+	// icon, in PrefixStyle and SuffixStyle. They don't count towards Width.
+	Prefix string
+	Suffix string
+
+	// ShowCount draws the number of characters in the value after the
+	// suffix, out of CharLimit if it's set, such as "12/80".
+	ShowCount bool
+
+	// ChangeDebounce, if set, holds back ChangedMsg until the value has
+	// stopped changing for this long, so that expensive work such as a
+	// search isn't redone on every key press.
+	ChangeDebounce time.Duration
+
 	// KeyMap encodes the keybindings recognized by the widget.
 	KeyMap KeyMap
 
@@ -154,6 +205,43 @@ type Model struct {
 	suggestions            [][]rune
 	matchedSuggestions     [][]rune
 	currentSuggestionIndex int
+
+	// HistoryLimit is the maximum number of history entries to keep. When
+	// exceeded, the oldest entries are discarded. If 0 or less, there's no
+	// limit.
+	HistoryLimit int
+
+	// history holds previously entered values, oldest first. historyIndex is
+	// the entry being shown; when it equals len(history) the in-progress
+	// value, kept in historyDraft, is being edited.
+	history      [][]rune
+	historyIndex int
+	historyDraft []rune
+
+	// Reverse incremental history search state.
+	historySearching bool
+	historyQuery     []rune
+	historyFailed    bool
+
+	// numeric holds the numeric mode options, if it's on.
+	numeric *Numeric
+
+	// secure holds the value in secure mode.
+	secure *secureBuffer
+
+	// Input method composition state: the text being composed, and the
+	// value and cursor position to restore if it's canceled. See
+	// CompositionMsg.
+	composing    bool
+	composition  []rune
+	composeValue []rune
+	composePos   int
+
+	// id identifies the input in ChangedMsg, and changeTag the latest
+	// change, so that only the last of a burst of changes is reported once
+	// ChangeDebounce has passed.
+	id        int
+	changeTag int
 }
 
 // New creates a new model with default settings.
@@ -165,8 +253,12 @@ func New() Model {
 		PlaceholderStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
 		ShowSuggestions:  false,
 		CompletionStyle:  lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+		UnitStyle:        lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+		GaugeStyle:       lipgloss.NewStyle().Foreground(lipgloss.Color("212")),
+		CompositionStyle: lipgloss.NewStyle().Underline(true),
 		Cursor:           cursor.New(),
 		KeyMap:           DefaultKeyMap,
+		id:               nextID(),
 
 		suggestions: [][]rune{},
 		value:       nil,
@@ -187,6 +279,7 @@ func (m *Model) SetValue(s string) {
 	runes := m.san().Sanitize([]rune(s))
 	err := m.validate(runes)
 	m.setValueInternal(runes, err)
+	m.regroup()
 }
 
 func (m *Model) setValueInternal(runes []rune, err error) {
@@ -195,13 +288,15 @@ func (m *Model) setValueInternal(runes []rune, err error) {
 	empty := len(m.value) == 0
 
 	if m.CharLimit > 0 && len(runes) > m.CharLimit {
-		m.value = runes[:m.CharLimit]
+		m.value = graphemePrefix(runes, m.CharLimit)
 	} else {
 		m.value = runes
 	}
+	m.keepSecure()
 	if (m.pos == 0 && empty) || m.pos > len(m.value) {
 		m.SetCursor(len(m.value))
 	}
+	m.pos = graphemeFloor(m.value, m.pos)
 	m.handleOverflow()
 }
 
@@ -216,8 +311,16 @@ func (m Model) Position() int {
 }
 
 // SetCursor moves the cursor to the given position. If the position is
-// out of bounds the cursor will be moved to the start or end accordingly.
+// out of bounds the cursor will be moved to the start or end accordingly, and
+// if it's within a grapheme cluster, such as an emoji sequence, to the start
+// of the cluster.
 func (m *Model) SetCursor(pos int) {
+	m.setCursor(graphemeFloor(m.value, clamp(pos, 0, len(m.value))))
+}
+
+// setCursor moves the cursor without regard for grapheme clusters, for
+// stepping over runes which are known to stand alone, such as spaces.
+func (m *Model) setCursor(pos int) {
 	m.pos = clamp(pos, 0, len(m.value))
 	m.handleOverflow()
 }
@@ -247,14 +350,20 @@ func (m *Model) Focus() tea.Cmd {
 // Blur removes the focus state on the model.  When the model is blurred it can
 // not receive keyboard input and the cursor will be hidden.
 func (m *Model) Blur() {
+	m.cancelComposition()
 	m.focus = false
 	m.Cursor.Blur()
 }
 
 // Reset sets the input to its default state with no input.
 func (m *Model) Reset() {
+	m.endComposition()
 	m.value = nil
+	m.keepSecure()
 	m.SetCursor(0)
+	m.historyIndex = len(m.history)
+	m.historyDraft = nil
+	m.historySearching = false
 }
 
 // SetSuggestions sets the suggestions for the input.
@@ -267,6 +376,155 @@ func (m *Model) SetSuggestions(suggestions []string) {
 	m.updateSuggestions()
 }
 
+// SetHistory sets the history entries for the input, oldest first. Any
+// in-progress history navigation is reset.
+func (m *Model) SetHistory(history []string) {
+	m.history = make([][]rune, len(history))
+	for i, h := range history {
+		m.history[i] = []rune(h)
+	}
+	m.trimHistory()
+	m.historyIndex = len(m.history)
+	m.historyDraft = nil
+	m.historySearching = false
+}
+
+// AddHistory appends an entry to the history, typically after the value has
+// been submitted. Empty values and values identical to the most recent entry
+// are ignored, as are all values of secure inputs. Any in-progress history
+// navigation is reset.
+func (m *Model) AddHistory(s string) {
+	if m.secure == nil && s != "" && (len(m.history) == 0 || string(m.history[len(m.history)-1]) != s) {
+		m.history = append(m.history, []rune(s))
+		m.trimHistory()
+	}
+	m.historyIndex = len(m.history)
+	m.historyDraft = nil
+	m.historySearching = false
+}
+
+// History returns the history entries, oldest first.
+func (m Model) History() []string {
+	history := make([]string, len(m.history))
+	for i, h := range m.history {
+		history[i] = string(h)
+	}
+	return history
+}
+
+// HistorySearching returns whether a reverse history search is in progress.
+func (m Model) HistorySearching() bool {
+	return m.historySearching
+}
+
+// trimHistory discards the oldest entries beyond HistoryLimit.
+func (m *Model) trimHistory() {
+	if m.HistoryLimit > 0 && len(m.history) > m.HistoryLimit {
+		m.history = m.history[len(m.history)-m.HistoryLimit:]
+	}
+}
+
+// historyEnabled returns whether the history keys should navigate the
+// history. Matched suggestions take precedence unless the history is already
+// being navigated.
+func (m *Model) historyEnabled() bool {
+	return m.secure == nil && len(m.history) > 0 &&
+		(m.historyIndex < len(m.history) || !m.canAcceptSuggestion())
+}
+
+// prevHistory replaces the value with the previous history entry, saving the
+// in-progress value the first time.
+func (m *Model) prevHistory() {
+	if m.historyIndex <= 0 {
+		return
+	}
+	if m.historyIndex >= len(m.history) {
+		m.historyDraft = append([]rune(nil), m.value...)
+		m.historyIndex = len(m.history)
+	}
+	m.historyIndex--
+	m.setHistoryValue(m.history[m.historyIndex])
+}
+
+// nextHistory replaces the value with the next history entry, restoring the
+// in-progress value once the end of the history is reached.
+func (m *Model) nextHistory() {
+	if m.historyIndex >= len(m.history) {
+		return
+	}
+	m.historyIndex++
+	if m.historyIndex == len(m.history) {
+		m.setHistoryValue(m.historyDraft)
+		m.historyDraft = nil
+		return
+	}
+	m.setHistoryValue(m.history[m.historyIndex])
+}
+
+func (m *Model) setHistoryValue(v []rune) {
+	m.SetValue(string(v))
+	m.CursorEnd()
+}
+
+// startHistorySearch begins a reverse incremental search of the history.
+func (m *Model) startHistorySearch() {
+	if m.historyIndex >= len(m.history) {
+		m.historyDraft = append([]rune(nil), m.value...)
+		m.historyIndex = len(m.history)
+	}
+	m.historySearching = true
+	m.historyQuery = nil
+	m.historyFailed = false
+}
+
+// searchHistory finds the most recent entry at or before the given index
+// which contains the search query, and shows it with the cursor at the match.
+func (m *Model) searchHistory(from int) {
+	if len(m.historyQuery) == 0 {
+		m.historyFailed = false
+		return
+	}
+	q := string(m.historyQuery)
+	for i := min(from, len(m.history)-1); i >= 0; i-- {
+		h := string(m.history[i])
+		if idx := strings.Index(h, q); idx >= 0 {
+			m.historyIndex = i
+			m.historyFailed = false
+			m.SetValue(h)
+			m.SetCursor(len([]rune(h[:idx])))
+			return
+		}
+	}
+	m.historyFailed = true
+}
+
+// handleHistorySearch processes a key press during a reverse history search.
+// It returns false if the key should go on to be handled as regular input, in
+// which case the search has been ended and the match accepted.
+func (m *Model) handleHistorySearch(msg tea.KeyMsg) bool {
+	switch {
+	case key.Matches(msg, m.KeyMap.SearchHistory):
+		m.searchHistory(m.historyIndex - 1)
+	case key.Matches(msg, m.KeyMap.CancelHistorySearch):
+		m.historySearching = false
+		m.historyIndex = len(m.history)
+		m.setHistoryValue(m.historyDraft)
+		m.historyDraft = nil
+	case key.Matches(msg, m.KeyMap.DeleteCharacterBackward):
+		if len(m.historyQuery) > 0 {
+			m.historyQuery = m.historyQuery[:len(m.historyQuery)-1]
+			m.searchHistory(len(m.history) - 1)
+		}
+	case msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace:
+		m.historyQuery = append(m.historyQuery, m.san().Sanitize(msg.Runes)...)
+		m.searchHistory(m.historyIndex)
+	default:
+		m.historySearching = false
+		return false
+	}
+	return true
+}
+
 // rsan initializes or retrieves the rune sanitizer.
 func (m *Model) san() runeutil.Sanitizer {
 	if m.rsan == nil {
@@ -283,6 +541,9 @@ func (m *Model) insertRunesFromUserInput(v []rune) {
 	// clipboard. This avoids bugs due to e.g. tab characters and
 	// whatnot.
 	paste := m.san().Sanitize(v)
+	if m.numeric != nil {
+		paste = m.numericRunes(paste)
+	}
 
 	var availSpace int
 	if m.CharLimit > 0 {
@@ -296,7 +557,7 @@ func (m *Model) insertRunesFromUserInput(v []rune) {
 		// If there's not enough space to paste the whole thing cut the pasted
 		// runes down so they'll fit.
 		if availSpace < len(paste) {
-			paste = paste[:availSpace]
+			paste = graphemePrefix(paste, availSpace)
 		}
 	}
 
@@ -320,14 +581,20 @@ func (m *Model) insertRunesFromUserInput(v []rune) {
 
 	// Put it all back together
 	value := append(head, tail...)
+	if m.secure != nil {
+		clear(tail)
+		clear(paste)
+	}
 	inputErr := m.validate(value)
 	m.setValueInternal(value, inputErr)
 }
 
 // If a max width is defined, perform some logic to treat the visible area
-// as a horizontally scrolling viewport.
+// as a horizontally scrolling viewport. The visible area always starts and
+// ends on grapheme cluster boundaries.
 func (m *Model) handleOverflow() {
-	if m.Width <= 0 || uniseg.StringWidth(string(m.value)) <= m.Width {
+	m.autoSize()
+	if m.Width <= 0 || m.displayWidth(m.value) <= m.Width {
 		m.offset = 0
 		m.offsetRight = len(m.value)
 		return
@@ -340,32 +607,28 @@ func (m *Model) handleOverflow() {
 		m.offset = m.pos
 
 		w := 0
-		i := 0
-		runes := m.value[m.offset:]
-
-		for i < len(runes) && w <= m.Width {
-			w += rw.RuneWidth(runes[i])
-			if w <= m.Width+1 {
-				i++
+		m.offsetRight = m.offset
+		for m.offsetRight < len(m.value) {
+			next := graphemeEnd(m.value, m.offsetRight)
+			w += m.displayWidth(m.value[m.offsetRight:next])
+			if w > m.Width+1 {
+				break
 			}
+			m.offsetRight = next
 		}
-
-		m.offsetRight = m.offset + i
 	} else if m.pos >= m.offsetRight {
 		m.offsetRight = m.pos
 
 		w := 0
-		runes := m.value[:m.offsetRight]
-		i := len(runes) - 1
-
-		for i > 0 && w < m.Width {
-			w += rw.RuneWidth(runes[i])
-			if w <= m.Width {
-				i--
+		m.offset = m.offsetRight
+		for m.offset > 0 {
+			prev := graphemeStart(m.value, m.offset)
+			w += m.displayWidth(m.value[prev:m.offset])
+			if w > m.Width {
+				break
 			}
+			m.offset = prev
 		}
-
-		m.offset = m.offsetRight - (len(runes) - 1 - i)
 	}
 }
 
@@ -402,22 +665,22 @@ func (m *Model) deleteWordBackward() {
 	// call into the corresponding if clause does not apply here.
 	oldPos := m.pos
 
-	m.SetCursor(m.pos - 1)
+	m.setCursor(m.pos - 1)
 	for unicode.IsSpace(m.value[m.pos]) {
 		if m.pos <= 0 {
 			break
 		}
 		// ignore series of whitespace before cursor
-		m.SetCursor(m.pos - 1)
+		m.setCursor(m.pos - 1)
 	}
 
 	for m.pos > 0 {
 		if !unicode.IsSpace(m.value[m.pos]) {
-			m.SetCursor(m.pos - 1)
+			m.setCursor(m.pos - 1)
 		} else {
 			if m.pos > 0 {
 				// keep the previous space
-				m.SetCursor(m.pos + 1)
+				m.setCursor(m.pos + 1)
 			}
 			break
 		}
@@ -445,10 +708,10 @@ func (m *Model) deleteWordForward() {
 	}
 
 	oldPos := m.pos
-	m.SetCursor(m.pos + 1)
+	m.setCursor(m.pos + 1)
 	for unicode.IsSpace(m.value[m.pos]) {
 		// ignore series of whitespace after cursor
-		m.SetCursor(m.pos + 1)
+		m.setCursor(m.pos + 1)
 
 		if m.pos >= len(m.value) {
 			break
@@ -457,7 +720,7 @@ func (m *Model) deleteWordForward() {
 
 	for m.pos < len(m.value) {
 		if !unicode.IsSpace(m.value[m.pos]) {
-			m.SetCursor(m.pos + 1)
+			m.setCursor(m.pos + 1)
 		} else {
 			break
 		}
@@ -470,7 +733,7 @@ func (m *Model) deleteWordForward() {
 	}
 	m.Err = m.validate(m.value)
 
-	m.SetCursor(oldPos)
+	m.setCursor(oldPos)
 }
 
 // wordBackward moves the cursor one word to the left. If input is masked, move
@@ -488,7 +751,7 @@ func (m *Model) wordBackward() {
 	i := m.pos - 1
 	for i >= 0 {
 		if unicode.IsSpace(m.value[i]) {
-			m.SetCursor(m.pos - 1)
+			m.setCursor(m.pos - 1)
 			i--
 		} else {
 			break
@@ -497,7 +760,7 @@ func (m *Model) wordBackward() {
 
 	for i >= 0 {
 		if !unicode.IsSpace(m.value[i]) {
-			m.SetCursor(m.pos - 1)
+			m.setCursor(m.pos - 1)
 			i--
 		} else {
 			break
@@ -520,7 +783,7 @@ func (m *Model) wordForward() {
 	i := m.pos
 	for i < len(m.value) {
 		if unicode.IsSpace(m.value[i]) {
-			m.SetCursor(m.pos + 1)
+			m.setCursor(m.pos + 1)
 			i++
 		} else {
 			break
@@ -529,7 +792,7 @@ func (m *Model) wordForward() {
 
 	for i < len(m.value) {
 		if !unicode.IsSpace(m.value[i]) {
-			m.SetCursor(m.pos + 1)
+			m.setCursor(m.pos + 1)
 			i++
 		} else {
 			break
@@ -540,7 +803,7 @@ func (m *Model) wordForward() {
 func (m Model) echoTransform(v string) string {
 	switch m.EchoMode {
 	case EchoPassword:
-		return strings.Repeat(string(m.EchoCharacter), uniseg.StringWidth(v))
+		return m.maskGraphemes(v)
 	case EchoNone:
 		return ""
 	case EchoNormal:
@@ -550,14 +813,40 @@ func (m Model) echoTransform(v string) string {
 	}
 }
 
-// Update is the Bubble Tea update loop.
+// Update is the Bubble Tea update loop. When the user changes the value, the
+// returned command sends a ChangedMsg.
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if cmd, ok := m.updateChange(msg); ok {
+		return m, cmd
+	}
+	if !m.focus || m.secure != nil {
+		return m.update(msg)
+	}
+
+	prev := string(m.value)
+	m, cmd := m.update(msg)
+	if string(m.value) != prev {
+		cmd = tea.Batch(cmd, m.changed())
+	}
+	return m, cmd
+}
+
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
 	if !m.focus {
 		return m, nil
 	}
 
 	// Need to check for completion before, because key is configurable and might be double assigned
+	if m.updateComposition(msg) {
+		return m, nil
+	}
 	keyMsg, ok := msg.(tea.KeyMsg)
+	if ok && m.historySearching && m.handleHistorySearch(keyMsg) {
+		return m, nil
+	}
+	if m.numeric != nil && m.updateNumeric(msg) {
+		return m, nil
+	}
 	if ok && key.Matches(keyMsg, m.KeyMap.AcceptSuggestion) {
 		if m.canAcceptSuggestion() {
 			m.value = append(m.value, m.matchedSuggestions[m.currentSuggestionIndex][len(m.value):]...)
@@ -577,29 +866,28 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		case key.Matches(msg, m.KeyMap.DeleteCharacterBackward):
 			m.Err = nil
 			if len(m.value) > 0 {
-				m.value = append(m.value[:max(0, m.pos-1)], m.value[m.pos:]...)
+				start := graphemeStart(m.value, m.pos)
+				m.value = append(m.value[:start], m.value[m.pos:]...)
 				m.Err = m.validate(m.value)
-				if m.pos > 0 {
-					m.SetCursor(m.pos - 1)
-				}
+				m.SetCursor(start)
 			}
 		case key.Matches(msg, m.KeyMap.WordBackward):
 			m.wordBackward()
 		case key.Matches(msg, m.KeyMap.CharacterBackward):
 			if m.pos > 0 {
-				m.SetCursor(m.pos - 1)
+				m.SetCursor(graphemeStart(m.value, m.pos))
 			}
 		case key.Matches(msg, m.KeyMap.WordForward):
 			m.wordForward()
 		case key.Matches(msg, m.KeyMap.CharacterForward):
 			if m.pos < len(m.value) {
-				m.SetCursor(m.pos + 1)
+				m.SetCursor(graphemeEnd(m.value, m.pos))
 			}
 		case key.Matches(msg, m.KeyMap.LineStart):
 			m.CursorStart()
 		case key.Matches(msg, m.KeyMap.DeleteCharacterForward):
 			if len(m.value) > 0 && m.pos < len(m.value) {
-				m.value = append(m.value[:m.pos], m.value[m.pos+1:]...)
+				m.value = append(m.value[:m.pos], m.value[graphemeEnd(m.value, m.pos):]...)
 				m.Err = m.validate(m.value)
 			}
 		case key.Matches(msg, m.KeyMap.LineEnd):
@@ -612,6 +900,12 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m, Paste
 		case key.Matches(msg, m.KeyMap.DeleteWordForward):
 			m.deleteWordForward()
+		case key.Matches(msg, m.KeyMap.SearchHistory) && len(m.history) > 0 && m.secure == nil:
+			m.startHistorySearch()
+		case key.Matches(msg, m.KeyMap.PrevHistory) && m.historyEnabled():
+			m.prevHistory()
+		case key.Matches(msg, m.KeyMap.NextHistory) && m.historyEnabled():
+			m.nextHistory()
 		case key.Matches(msg, m.KeyMap.NextSuggestion):
 			m.nextSuggestion()
 		case key.Matches(msg, m.KeyMap.PrevSuggestion):
@@ -635,6 +929,8 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	var cmd tea.Cmd
 
+	m.regroup()
+
 	m.Cursor, cmd = m.Cursor.Update(msg)
 	cmds = append(cmds, cmd)
 
@@ -643,12 +939,29 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		cmds = append(cmds, m.Cursor.BlinkCmd())
 	}
 
+	m.keepSecure()
 	m.handleOverflow()
 	return m, tea.Batch(cmds...)
 }
 
 // View renders the textinput in its current state.
 func (m Model) View() string {
+	m.autoSize()
+	if m.historySearching {
+		m.Prompt = m.historySearchPrompt()
+	}
+
+	// The composition is drawn before the cursor, as if it were typed. The
+	// characters after it are offset by its length from their index in the
+	// value.
+	composed, shift := 0, 0
+	if m.composing {
+		m = m.withComposition()
+		defer clear(m.value)
+		composed = min(len(m.composition), m.pos-m.offset)
+		shift = len(m.composition)
+	}
+
 	// Placeholder text
 	if len(m.value) == 0 && m.Placeholder != "" {
 		return m.placeholderView()
@@ -658,14 +971,19 @@ func (m Model) View() string {
 
 	value := m.value[m.offset:m.offsetRight]
 	pos := max(0, m.pos-m.offset)
-	v := styleText(m.echoTransform(string(value[:pos])))
+	v := m.styleValue(value[:pos-composed], m.offset)
+	v += m.compositionView(value[pos-composed : pos])
 
 	if pos < len(value) { //nolint:nestif
-		char := m.echoTransform(string(value[pos]))
+		end := graphemeEnd(value, pos)
+		char := m.echoTransform(string(value[pos:end]))
+		if m.CharStyleFunc != nil {
+			m.Cursor.TextStyle = m.charStyle(m.offset+pos-shift, value[pos])
+		}
 		m.Cursor.SetChar(char)
-		v += m.Cursor.View()                                   // cursor and text under it
-		v += styleText(m.echoTransform(string(value[pos+1:]))) // text after cursor
-		v += m.completionView(0)                               // suggested completion
+		v += m.Cursor.View()                               // cursor and text under it
+		v += m.styleValue(value[end:], m.offset+end-shift) // text after cursor
+		v += m.completionView(0)                           // suggested completion
 	} else {
 		if m.focus && m.canAcceptSuggestion() {
 			suggestion := m.matchedSuggestions[m.currentSuggestionIndex]
@@ -686,7 +1004,7 @@ func (m Model) View() string {
 
 	// If a max width and background color were set fill the empty spaces with
 	// the background color.
-	valWidth := uniseg.StringWidth(string(value))
+	valWidth := m.displayWidth(value)
 	if m.Width > 0 && valWidth <= m.Width {
 		padding := max(0, m.Width-valWidth)
 		if valWidth+padding <= m.Width && pos < len(value) {
@@ -695,7 +1013,28 @@ func (m Model) View() string {
 		v += styleText(strings.Repeat(" ", padding))
 	}
 
-	return m.PromptStyle.Render(m.Prompt) + v
+	return m.PromptStyle.Render(m.Prompt) + m.prefixView() + v + m.suffixView() + m.unitView()
+}
+
+// RenderString renders the input deterministically at the given size, for
+// golden-file tests. The cursor is drawn without blinking. See the render
+// package for details.
+func RenderString(m Model, width, height int) string {
+	if !m.AutoWidth {
+		m.Width = max(0, width-uniseg.StringWidth(m.Prompt)-lipgloss.Width(m.prefixView()+m.suffixView()+m.unitView())-1)
+	}
+	m.Cursor.SetMode(cursor.CursorStatic)
+	return render.View(m.View, width, height)
+}
+
+// historySearchPrompt returns the readline-style prompt shown during a
+// reverse history search.
+func (m Model) historySearchPrompt() string {
+	prompt := "(reverse-i-search)`" + string(m.historyQuery) + "': "
+	if m.historyFailed {
+		prompt = "(failed " + prompt[1:]
+	}
+	return prompt
 }
 
 // placeholderView returns the prompt and placeholder view, if any.
@@ -714,29 +1053,20 @@ func (m Model) placeholderView() string {
 
 	// If the entire placeholder is already set and no padding is needed, finish
 	if m.Width < 1 && len(p) <= 1 {
-		return m.PromptStyle.Render(m.Prompt) + v
+		return m.PromptStyle.Render(m.Prompt) + m.prefixView() + v + m.suffixView()
 	}
 
-	// If Width is set then size placeholder accordingly
+	// If Width is set then size placeholder accordingly, leaving a cell for
+	// the cursor at the end like the value does.
 	if m.Width > 0 {
-		// available width is width - len + cursor offset of 1
-		minWidth := lipgloss.Width(m.Placeholder)
-		availWidth := m.Width - minWidth + 1
-
-		// if width < len, 'subtract'(add) number to len and dont add padding
-		if availWidth < 0 {
-			minWidth += availWidth
-			availWidth = 0
-		}
-		// append placeholder[len] - cursor, append padding
-		v += style(string(p[1:minWidth]))
-		v += style(strings.Repeat(" ", availWidth))
+		rest := string([]rune(m.Placeholder)[1:])
+		v += style(textutil.Fit(rest, m.Width+1-uniseg.StringWidth(string(p[:1])), ""))
 	} else {
 		// if there is no width, the placeholder can be any length
 		v += style(string(p[1:]))
 	}
 
-	return m.PromptStyle.Render(m.Prompt) + v
+	return m.PromptStyle.Render(m.Prompt) + m.prefixView() + v + m.suffixView()
 }
 
 // Blink is a command used to initialize cursor blinking.
@@ -891,6 +1221,11 @@ func (m *Model) previousSuggestion() {
 }
 
 func (m Model) validate(v []rune) error {
+	if m.numeric != nil {
+		if err := m.numeric.validate(v); err != nil {
+			return err
+		}
+	}
 	if m.Validate != nil {
 		return m.Validate(string(v))
 	}
@@ -5,6 +5,13 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/mikeflynn/bubbles/cursor"
 )
 
 func Test_CurrentSuggestion(t *testing.T) {
@@ -78,3 +85,493 @@ func ExampleValidateFunc() {
 		return err
 	}
 }
+
+func Test_History(t *testing.T) {
+	textinput := New()
+	textinput.Focus()
+	textinput.SetHistory([]string{"ls", "cd /tmp", "echo hi"})
+	textinput.SetValue("draft")
+
+	up := tea.KeyMsg{Type: tea.KeyUp}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+
+	textinput, _ = textinput.Update(up)
+	if textinput.Value() != "echo hi" {
+		t.Fatalf("expected most recent entry, got %q", textinput.Value())
+	}
+	textinput, _ = textinput.Update(up)
+	textinput, _ = textinput.Update(up)
+	textinput, _ = textinput.Update(up)
+	if textinput.Value() != "ls" {
+		t.Fatalf("expected oldest entry, got %q", textinput.Value())
+	}
+
+	for range 3 {
+		textinput, _ = textinput.Update(down)
+	}
+	if textinput.Value() != "draft" {
+		t.Fatalf("expected in-progress value to be restored, got %q", textinput.Value())
+	}
+}
+
+func Test_HistoryLimit(t *testing.T) {
+	textinput := New()
+	textinput.HistoryLimit = 2
+	textinput.SetHistory([]string{"one", "two"})
+	textinput.AddHistory("three")
+	textinput.AddHistory("three")
+
+	if got := strings.Join(textinput.History(), ","); got != "two,three" {
+		t.Fatalf("expected history to be capped, got %q", got)
+	}
+}
+
+func Test_HistorySearch(t *testing.T) {
+	textinput := New()
+	textinput.Focus()
+	textinput.SetHistory([]string{"git status", "go test ./...", "git push"})
+	textinput.SetValue("draft")
+
+	textinput, _ = textinput.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if !textinput.HistorySearching() {
+		t.Fatal("expected history search to start")
+	}
+	for _, r := range "git" {
+		textinput, _ = textinput.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if textinput.Value() != "git push" {
+		t.Fatalf("expected most recent match, got %q", textinput.Value())
+	}
+
+	textinput, _ = textinput.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if textinput.Value() != "git status" {
+		t.Fatalf("expected older match, got %q", textinput.Value())
+	}
+	if !strings.HasPrefix(textinput.View(), "(reverse-i-search)`git'") {
+		t.Fatalf("expected search prompt, got %q", textinput.View())
+	}
+
+	// Cancelling restores the in-progress value.
+	textinput, _ = textinput.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if textinput.HistorySearching() || textinput.Value() != "draft" {
+		t.Fatalf("expected search to be cancelled, got %q", textinput.Value())
+	}
+
+	// Any other key accepts the match.
+	textinput, _ = textinput.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	textinput, _ = textinput.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("test")})
+	textinput, _ = textinput.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	if textinput.HistorySearching() || textinput.Value() != "go test ./..." {
+		t.Fatalf("expected match to be accepted, got %q", textinput.Value())
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	t.Run("placeholder", func(t *testing.T) {
+		m := New()
+		m.Placeholder = "Name"
+		m.Focus()
+		golden.RequireEqual(t, []byte(RenderString(m, 20, 1)))
+	})
+	t.Run("value", func(t *testing.T) {
+		m := New()
+		m.SetValue("Bubble Tea")
+		golden.RequireEqual(t, []byte(RenderString(m, 20, 1)))
+	})
+	t.Run("password", func(t *testing.T) {
+		m := New()
+		m.EchoMode = EchoPassword
+		m.SetValue("secret")
+		golden.RequireEqual(t, []byte(RenderString(m, 20, 1)))
+	})
+}
+
+func TestPlaceholderWidth(t *testing.T) {
+	for _, placeholder := range []string{"Name", "作業ディレクトリを指定してください", "👋 hello there"} {
+		m := New()
+		m.Prompt = ""
+		m.Placeholder = placeholder
+		m.Width = 10
+		if w := ansi.StringWidth(m.View()); w != m.Width+1 {
+			t.Errorf("placeholder %q rendered %d cells wide, want %d", placeholder, w, m.Width+1)
+		}
+	}
+}
+
+func TestNumeric(t *testing.T) {
+	m := New()
+	m.Prompt = ""
+	m.Focus()
+	m.SetNumeric(Numeric{Min: -10, Max: 100000, Step: 2.5, Precision: 1, Unit: "ms"})
+
+	typeRunes := func(s string) {
+		for _, r := range s {
+			m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+
+	typeRunes("12a34.5.6")
+	if got := m.Value(); got != "1,234.56" {
+		t.Fatalf("expected grouped value 1,234.56, got %q", got)
+	}
+	if v, err := m.Float(); err != nil || v != 1234.56 {
+		t.Fatalf("expected 1234.56, got %v (%v)", v, err)
+	}
+
+	m.CursorStart()
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	typeRunes("0")
+	if got := m.Value(); got != "10,234.56" || m.Position() != 2 {
+		t.Fatalf("expected 10,234.56 with the cursor after the 0, got %q at %d", m.Value(), m.Position())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if got := m.Value(); got != "10,237.1" {
+		t.Fatalf("expected a step up to 10,237.1, got %q", got)
+	}
+	m, _ = m.Update(tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonWheelDown})
+	if got := m.Value(); got != "10,234.6" {
+		t.Fatalf("expected a step down to 10,234.6, got %q", got)
+	}
+
+	m.SetFloat(1e9)
+	if got := m.Value(); got != "100,000.0" {
+		t.Fatalf("expected the value to be clamped to 100,000.0, got %q", got)
+	}
+	if !strings.HasSuffix(m.View(), "ms") {
+		t.Fatalf("expected the unit to be displayed, got %q", m.View())
+	}
+
+	m.SetValue("200000")
+	if m.Err == nil {
+		t.Fatal("expected an error for a value out of bounds")
+	}
+
+	t.Run("german", func(t *testing.T) {
+		m := New()
+		m.SetNumeric(Numeric{Precision: 2, Format: NumberFormatGerman})
+		m.SetFloat(-1234567.891)
+		if got := m.Value(); got != "-1.234.567,89" {
+			t.Fatalf("expected -1.234.567,89, got %q", got)
+		}
+	})
+}
+
+func TestNumericGauge(t *testing.T) {
+	m := New()
+	m.Focus()
+	m.SetNumeric(Numeric{Min: 0, Max: 100, Step: 1, Unit: "%", Gauge: 10})
+	m.SetFloat(20)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp, Alt: true})
+	if got := m.Value(); got != "30" {
+		t.Fatalf("expected a large step up to 30, got %q", got)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown, Alt: true})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown, Alt: true})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown, Alt: true})
+	if got := m.Value(); got != "0" {
+		t.Fatalf("expected large steps to stop at 0, got %q", got)
+	}
+
+	m.SetFloat(40)
+	golden.RequireEqual(t, []byte(RenderString(m, 30, 1)))
+}
+
+func TestGraphemeClusters(t *testing.T) {
+	const family = "👩‍👩‍👧‍👦"
+	key := func(m Model, k tea.KeyType) Model {
+		m, _ = m.Update(tea.KeyMsg{Type: k})
+		return m
+	}
+
+	m := New()
+	m.Focus()
+	m.SetValue("a" + family + "é")
+
+	m = key(m, tea.KeyLeft)
+	if m.Position() != len([]rune("a"+family)) {
+		t.Fatalf("expected the cursor to move over the accented letter, got %d", m.Position())
+	}
+	m = key(m, tea.KeyLeft)
+	if m.Position() != 1 {
+		t.Fatalf("expected the cursor to move over the whole emoji, got %d", m.Position())
+	}
+	m.SetCursor(3)
+	if m.Position() != 1 {
+		t.Fatalf("expected SetCursor not to land inside the emoji, got %d", m.Position())
+	}
+	m = key(m, tea.KeyDelete)
+	if m.Value() != "aé" {
+		t.Fatalf("expected delete to remove the whole emoji, got %q", m.Value())
+	}
+
+	m.SetValue("a" + family)
+	m.CursorEnd()
+	m = key(m, tea.KeyBackspace)
+	if m.Value() != "a" || m.Position() != 1 {
+		t.Fatalf("expected backspace to remove the whole emoji, got %q at %d", m.Value(), m.Position())
+	}
+
+	m.EchoMode = EchoPassword
+	m.Prompt = ""
+	m.SetValue(family + "é")
+	if got := strings.TrimSpace(ansi.Strip(m.View())); got != "**" {
+		t.Fatalf("expected one mask character per grapheme, got %q", got)
+	}
+
+	m = New()
+	m.Prompt = ""
+	m.Width = 5
+	m.SetValue(strings.Repeat(family, 5))
+	for _, r := range []int{m.offset, m.offsetRight} {
+		if r%len([]rune(family)) != 0 {
+			t.Fatalf("expected the visible area not to split an emoji, got %d:%d", m.offset, m.offsetRight)
+		}
+	}
+	if w := ansi.StringWidth(m.View()); w > m.Width+1 {
+		t.Fatalf("expected the view to fit in %d cells, got %d", m.Width+1, w)
+	}
+}
+
+func TestSecure(t *testing.T) {
+	m := New()
+	m.EchoMode = EchoPassword
+	m.SetValue("hunter")
+	old := m.value
+	m.SetSecure(true)
+	m.Focus()
+	if string(old) != "\x00\x00\x00\x00\x00\x00" {
+		t.Fatalf("expected the value to be zeroed when it moved, got %q", string(old))
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("22")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if got := string(m.ValueBytes()); got != "hunter2" {
+		t.Fatalf("expected hunter2, got %q", got)
+	}
+	if full := m.secure.runes[:cap(m.secure.runes)]; full[len(m.value)] != 0 {
+		t.Fatal("expected the deleted rune to be zeroed")
+	}
+
+	for _, s := range []string{m.String(), fmt.Sprintf("%v", m), fmt.Sprintf("%+v", m), fmt.Sprintf("%#v", m)} {
+		if strings.Contains(s, "hunter") || strings.Contains(s, "104, 117") {
+			t.Fatalf("expected the value to be redacted, got %s", s)
+		}
+	}
+
+	m.AddHistory(m.Value())
+	if len(m.History()) != 0 {
+		t.Fatal("expected secure inputs not to record history")
+	}
+
+	buf := m.value[:cap(m.value)]
+	m.Wipe()
+	if m.Value() != "" || m.Position() != 0 {
+		t.Fatalf("expected the input to be emptied, got %q", m.Value())
+	}
+	for _, r := range buf {
+		if r != 0 {
+			t.Fatal("expected the secure memory to be zeroed")
+		}
+	}
+	if !m.Secure() {
+		t.Fatal("expected the input to stay secure")
+	}
+
+	m.SetValue(strings.Repeat("x", defaultSecureCapacity+1))
+	if len(m.Value()) != defaultSecureCapacity+1 {
+		t.Fatal("expected the secure memory to grow")
+	}
+}
+
+func TestComposition(t *testing.T) {
+	m := New()
+	m.Cursor.SetMode(cursor.CursorStatic)
+	m.Focus()
+	m.SetValue("ab")
+	m.SetCursor(1)
+
+	m, _ = m.Update(CompositionMsg{Text: "に"})
+	m, _ = m.Update(CompositionMsg{Text: "にほ"})
+	if !m.Composing() || m.Value() != "ab" {
+		t.Fatalf("expected the composition to stay out of the value, got %q", m.Value())
+	}
+	if view := ansi.Strip(m.View()); !strings.HasPrefix(view, "> aにほb") {
+		t.Fatalf("expected the composition at the cursor, got %q", view)
+	}
+
+	// Keys belong to the input method while composing.
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if m.Value() != "ab" {
+		t.Fatalf("expected keys to be ignored while composing, got %q", m.Value())
+	}
+
+	m, _ = m.Update(CompositionCommitMsg{Text: "日本"})
+	if m.Composing() || m.Value() != "a日本b" || m.Position() != 3 {
+		t.Fatalf("expected the composition to be committed, got %q at %d", m.Value(), m.Position())
+	}
+
+	m.SetValue("abc")
+	m.CursorEnd()
+	m, _ = m.Update(CompositionMsg{Text: "かな"})
+	m.SetValue("abcかな")
+	m, _ = m.Update(CompositionCancelMsg{})
+	if m.Composing() || m.Value() != "abc" || m.Position() != 3 {
+		t.Fatalf("expected the value to be restored, got %q at %d", m.Value(), m.Position())
+	}
+
+	// The composition is kept in view, measured in cells.
+	m.Width = 6
+	m.SetValue("0123456789")
+	m.CursorStart()
+	m, _ = m.Update(CompositionMsg{Text: "日本語"})
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "日本語") || ansi.StringWidth(view) > len(m.Prompt)+m.Width+1 {
+		t.Fatalf("expected the composition in view within the width, got %q", view)
+	}
+}
+
+func TestAdornments(t *testing.T) {
+	m := New()
+	m.Prompt = ""
+	m.Prefix = "@ "
+	m.Suffix = " ↵"
+	m.ShowCount = true
+	m.CharLimit = 80
+	m.SetValue("bubbles")
+	if got := ansi.Strip(m.View()); got != "@ bubbles  ↵ 7/80" {
+		t.Errorf("expected the value between its adornments, got %q", got)
+	}
+	golden.RequireEqual(t, []byte(RenderString(m, 24, 1)))
+
+	m.Placeholder = "user"
+	m.Width = 5
+	m.SetValue("")
+	if got := ansi.Strip(m.View()); got != "@ user   ↵ 0/80" {
+		t.Errorf("expected the placeholder between its adornments, got %q", got)
+	}
+}
+
+func TestAutoWidth(t *testing.T) {
+	m := New()
+	m.Prompt = ""
+	m.AutoWidth = true
+	m.MinWidth = 4
+	m.MaxWidth = 8
+	m.Focus()
+
+	if got := ansi.StringWidth(m.View()); got != 5 {
+		t.Errorf("expected the empty input to be the minimum width, got %d cells", got)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("hello")})
+	if m.Width != 5 {
+		t.Errorf("expected the input to grow with its value, got width %d", m.Width)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" world")})
+	if m.Width != 8 {
+		t.Fatalf("expected the input to stop at the maximum width, got %d", m.Width)
+	}
+	if got := ansi.Strip(m.View()); got != "lo world " {
+		t.Errorf("expected the value to scroll, got %q", got)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyHome})
+	if got := ansi.Strip(m.View()); got != "hello wor" {
+		t.Errorf("expected the value to scroll back, got %q", got)
+	}
+
+	for range 8 {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDelete})
+	}
+	if m.Width != 4 || ansi.Strip(m.View()) != "rld  " {
+		t.Errorf("expected the input to shrink to its value, got width %d and %q", m.Width, ansi.Strip(m.View()))
+	}
+}
+
+func TestChangedMsg(t *testing.T) {
+	m := New()
+	m.Cursor.SetMode(cursor.CursorStatic)
+	m.Focus()
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if cmd == nil {
+		t.Fatal("expected a command after a change")
+	}
+	if msg, ok := cmd().(ChangedMsg); !ok || msg.Value != "a" || msg.ID != m.ID() {
+		t.Fatalf("expected a ChangedMsg with the new value, got %#v", msg)
+	}
+
+	// Moving the cursor doesn't change the value.
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if cmd != nil {
+		t.Fatalf("expected no change, got %#v", cmd())
+	}
+
+	// With a debounce, only the last of a burst of changes is reported.
+	m.ChangeDebounce = time.Millisecond
+	m.CursorEnd()
+	m, first := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	m, second := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m, cmd = m.Update(first())
+	if cmd != nil {
+		t.Fatalf("expected the first change to be superseded, got %#v", cmd())
+	}
+	m, cmd = m.Update(second())
+	if cmd == nil {
+		t.Fatal("expected the last change to be reported")
+	}
+	if msg, ok := cmd().(ChangedMsg); !ok || msg.Value != "abc" {
+		t.Fatalf("expected a ChangedMsg with the final value, got %#v", msg)
+	}
+
+	// Debounce timers of other inputs are ignored.
+	other := New()
+	other.Cursor.SetMode(cursor.CursorStatic)
+	other.Focus()
+	other.ChangeDebounce = time.Millisecond
+	_, tick := other.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	msg, ok := tick().(changeDebounceMsg)
+	if !ok {
+		t.Fatal("expected a debounce timer")
+	}
+	if _, cmd = m.Update(msg); cmd != nil {
+		t.Fatalf("expected another input's timer to be ignored, got %#v", cmd())
+	}
+}
+
+func TestCharStyleFunc(t *testing.T) {
+	upper := lipgloss.NewStyle().Transform(strings.ToUpper)
+	m := New()
+	m.Prompt = ""
+	m.Cursor.SetMode(cursor.CursorStatic)
+	m.Focus()
+	m.CharStyleFunc = func(i int, _ rune) lipgloss.Style {
+		if i%2 == 1 {
+			return upper
+		}
+		return lipgloss.NewStyle()
+	}
+
+	m.SetValue("abcdef")
+	if got := ansi.Strip(m.View()); got != "aBcDeF " {
+		t.Fatalf("expected odd characters to be styled, got %q", got)
+	}
+
+	// Characters keep their index in the value while it scrolls.
+	m.Width = 3
+	m.SetValue("abcdef")
+	if got := ansi.Strip(m.View()); !strings.HasPrefix(got, "DeF") {
+		t.Fatalf("expected styles to follow the value's indexes, got %q", got)
+	}
+
+	m.Width = 0
+	m.EchoMode = EchoPassword
+	m.EchoCharacter = 'x'
+	m.SetValue("abc")
+	if got := ansi.Strip(m.View()); got != "xXx " {
+		t.Fatalf("expected masked characters to be styled, got %q", got)
+	}
+}
@@ -0,0 +1,96 @@
+// Package textutil provides width-aware helpers to truncate and pad text for
+// display in Bubbles. All of them measure strings in terminal cells, treating
+// wide characters and emoji as the grapheme clusters they render as, and
+// leave ANSI escape sequences intact.
+package textutil
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Ellipsis is the tail Bubbles conventionally use for truncated text.
+const Ellipsis = "…"
+
+// Width returns the number of cells s occupies in a terminal.
+func Width(s string) int {
+	return ansi.StringWidth(s)
+}
+
+// Truncate shortens s to at most width cells, ending it with tail if it was
+// cut. If tail doesn't fit either, s is cut without it. A wide character
+// that would straddle the limit is dropped, so the result may be a cell
+// narrower than width.
+func Truncate(s string, width int, tail string) string {
+	if width <= 0 {
+		return ""
+	}
+	if Width(s) <= width {
+		return s
+	}
+	if Width(tail) > width {
+		tail = ""
+	}
+	return ansi.Truncate(s, width, tail)
+}
+
+// TruncateLeft shortens s to at most width cells by cutting its beginning,
+// starting it with head if it was cut. It suits text whose end matters most,
+// such as paths.
+func TruncateLeft(s string, width int, head string) string {
+	if width <= 0 {
+		return ""
+	}
+	sw := Width(s)
+	if sw <= width {
+		return s
+	}
+	if Width(head) > width {
+		head = ""
+	}
+	return ansi.TruncateLeft(s, sw-width+Width(head), head)
+}
+
+// TruncateMiddle shortens s to at most width cells by replacing its middle
+// with ellipsis, keeping both its beginning and end.
+func TruncateMiddle(s string, width int, ellipsis string) string {
+	if width <= 0 {
+		return ""
+	}
+	sw := Width(s)
+	if sw <= width {
+		return s
+	}
+	avail := width - Width(ellipsis)
+	if avail <= 0 {
+		return Truncate(s, width, "")
+	}
+	left := (avail + 1) / 2 //nolint:mnd
+	right := avail - left
+	return ansi.Truncate(s, left, "") + ellipsis + ansi.TruncateLeft(s, sw-right, "")
+}
+
+// PadRight pads s with spaces on the right to width cells. Text already as
+// wide as width is returned as is.
+func PadRight(s string, width int) string {
+	if n := width - Width(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// PadLeft pads s with spaces on the left to width cells. Text already as
+// wide as width is returned as is.
+func PadLeft(s string, width int) string {
+	if n := width - Width(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
+
+// Fit truncates s with tail and pads it on the right so that it occupies
+// exactly width cells.
+func Fit(s string, width int, tail string) string {
+	return PadRight(Truncate(s, width, tail), width)
+}
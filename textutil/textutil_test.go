@@ -0,0 +1,122 @@
+package textutil
+
+import (
+	"testing"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		width int
+		tail  string
+		want  string
+	}{
+		{"fits", "hello", 5, Ellipsis, "hello"},
+		{"ascii", "hello world", 8, Ellipsis, "hello w…"},
+		{"zero width", "hello", 0, Ellipsis, ""},
+		{"negative width", "hello", -1, Ellipsis, ""},
+		{"tail too wide", "hello", 2, "...", "he"},
+		{"wide characters", "日本語テキスト", 7, Ellipsis, "日本語…"},
+		{"wide character at limit", "日本語テキスト", 6, Ellipsis, "日本…"},
+		{"emoji", "👋🌍🎉✨", 5, Ellipsis, "👋🌍…"},
+		{"emoji with modifier", "👍🏽👍🏽👍🏽", 3, Ellipsis, "👍🏽…"},
+		{"combining marks", "éééé", 3, Ellipsis, "éé…"},
+		{"ansi", "\x1b[1mhello world\x1b[0m", 6, Ellipsis, "\x1b[1mhello…\x1b[0m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Truncate(tt.input, tt.width, tt.tail)
+			if got != tt.want {
+				t.Errorf("Truncate(%q, %d, %q) = %q, want %q", tt.input, tt.width, tt.tail, got, tt.want)
+			}
+			if tt.width > 0 && Width(got) > tt.width {
+				t.Errorf("Truncate(%q, %d, %q) is %d cells wide", tt.input, tt.width, tt.tail, Width(got))
+			}
+		})
+	}
+}
+
+func TestTruncateLeft(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		width int
+		want  string
+	}{
+		{"fits", "a/b", 3, "a/b"},
+		{"path", "/usr/local/bin/go", 8, "…/bin/go"},
+		{"zero width", "hello", 0, ""},
+		{"wide characters", "日本語テキスト", 7, "…キスト"},
+		{"emoji", "👋🌍🎉✨", 5, "…🎉✨"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateLeft(tt.input, tt.width, Ellipsis)
+			if got != tt.want {
+				t.Errorf("TruncateLeft(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateMiddle(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		width int
+		want  string
+	}{
+		{"fits", "hello", 5, "hello"},
+		{"ascii", "hello world", 7, "hel…rld"},
+		{"uneven", "hello world", 6, "hel…ld"},
+		{"only ellipsis", "hello", 1, "h"},
+		{"wide characters", "日本語テキスト", 9, "日本…スト"},
+		{"emoji", "👋🌍🎉✨", 5, "👋…✨"},
+		{"ansi", "\x1b[1mhello world\x1b[0m", 7, "\x1b[1mhel\x1b[0m…\x1b[1mrld\x1b[0m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateMiddle(tt.input, tt.width, Ellipsis)
+			if got != tt.want {
+				t.Errorf("TruncateMiddle(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+			}
+			if Width(got) > tt.width {
+				t.Errorf("TruncateMiddle(%q, %d) is %d cells wide", tt.input, tt.width, Width(got))
+			}
+		})
+	}
+}
+
+func TestPad(t *testing.T) {
+	if got := PadRight("日本", 6); got != "日本  " {
+		t.Errorf("PadRight = %q", got)
+	}
+	if got := PadLeft("👋", 4); got != "  👋" {
+		t.Errorf("PadLeft = %q", got)
+	}
+	if got := PadRight("\x1b[1mhi\x1b[0m", 4); got != "\x1b[1mhi\x1b[0m  " {
+		t.Errorf("PadRight with ansi = %q", got)
+	}
+	if got := PadRight("hello", 3); got != "hello" {
+		t.Errorf("PadRight of wider text = %q", got)
+	}
+}
+
+func TestFit(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		width int
+	}{
+		{"short", 10},
+		{"exactly 10", 10},
+		{"much too long", 10},
+		{"日本語テキスト", 6},
+		{"日本語テキスト", 5},
+		{"👋🌍🎉✨", 4},
+	} {
+		if got := Fit(tt.input, tt.width, Ellipsis); Width(got) != tt.width {
+			t.Errorf("Fit(%q, %d) = %q, which is %d cells wide", tt.input, tt.width, got, Width(got))
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package timer
+
+import "time"
+
+// NewDeadline creates a timer which times out at the given time, ticking every
+// second. Unlike other timers, which count down by subtracting the interval on
+// every tick, it works out the time left from the deadline on the wall clock.
+// It's therefore unaffected by ticks running late, and by the system being
+// suspended, such as a laptop going to sleep: if the deadline passes in the
+// meantime, the first tick after the system resumes times out.
+//
+// Timeout is rounded up to a whole number of intervals, as a countdown clock
+// would show it. Stopping the timer pauses the countdown, and starting it
+// again moves the deadline on by as long as it was stopped.
+func NewDeadline(deadline time.Time) Model {
+	m := New(0)
+	m.wallClock = true
+	m.deadline = deadline.Round(0)
+	m.Timeout = m.remaining()
+	return m
+}
+
+// WallClock returns whether the timer counts down to a deadline on the wall
+// clock. See NewDeadline.
+func (m Model) WallClock() bool {
+	return m.wallClock
+}
+
+// Deadline returns the time the timer times out at, if it counts down to a
+// deadline on the wall clock and is running. It's the zero time otherwise.
+func (m Model) Deadline() time.Time {
+	if !m.running {
+		return time.Time{}
+	}
+	return m.deadline
+}
+
+// now returns the time on the wall clock. It drops the monotonic clock
+// reading, which stops while the system is suspended on some platforms. It's
+// a variable so tests can stop the clock.
+var now = func() time.Time {
+	return time.Now().Round(0)
+}
+
+// left returns the exact time until the deadline.
+func (m Model) left() time.Duration {
+	if m.deadline.IsZero() {
+		return m.paused
+	}
+	return m.deadline.Sub(now())
+}
+
+// remaining returns the time until the deadline, rounded up to a whole number
+// of intervals.
+func (m Model) remaining() time.Duration {
+	left := m.left()
+	if left <= 0 || m.Interval <= 0 {
+		return left
+	}
+	if r := left % m.Interval; r > 0 {
+		left += m.Interval - r
+	}
+	return left
+}
+
+// wait returns how long until the next tick. Ticks of a deadline timer fall
+// on whole intervals before the deadline, so that the last one lands on it.
+func (m Model) wait() time.Duration {
+	if !m.wallClock || m.Interval <= 0 {
+		return m.Interval
+	}
+	if r := m.left() % m.Interval; r > 0 {
+		return r
+	}
+	return m.Interval
+}
+
+// setRunning pauses and resumes the countdown to the deadline.
+func (m *Model) setRunning(running bool) {
+	switch {
+	case running && m.deadline.IsZero():
+		m.deadline = now().Add(m.paused)
+	case !running && !m.deadline.IsZero():
+		m.paused = m.deadline.Sub(now())
+		m.deadline = time.Time{}
+	}
+}
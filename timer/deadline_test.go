@@ -0,0 +1,89 @@
+package timer
+
+import (
+	"testing"
+	"time"
+)
+
+// setNow stops the clock at t for the rest of the test.
+func setNow(t *testing.T, at *time.Time) {
+	t.Helper()
+	orig := now
+	now = func() time.Time { return *at }
+	t.Cleanup(func() { now = orig })
+}
+
+func TestDeadlineRounding(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	setNow(t, &clock)
+
+	m := NewDeadline(clock.Add(2500 * time.Millisecond))
+	if !m.WallClock() {
+		t.Fatal("expected a wall-clock timer")
+	}
+	for _, tc := range []struct {
+		at        time.Duration
+		remaining time.Duration
+		wait      time.Duration
+	}{
+		{0, 3 * time.Second, 500 * time.Millisecond},
+		{500 * time.Millisecond, 2 * time.Second, time.Second},
+		{2400 * time.Millisecond, time.Second, 100 * time.Millisecond},
+		{2500 * time.Millisecond, 0, time.Second},
+		{5 * time.Second, -2500 * time.Millisecond, time.Second},
+	} {
+		clock = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC).Add(tc.at)
+		if got := m.remaining(); got != tc.remaining {
+			t.Errorf("at %v: expected %v remaining, got %v", tc.at, tc.remaining, got)
+		}
+		if got := m.wait(); got != tc.wait {
+			t.Errorf("at %v: expected to wait %v, got %v", tc.at, tc.wait, got)
+		}
+	}
+}
+
+func TestDeadlineTimesOutAfterSuspend(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	setNow(t, &clock)
+
+	m := NewDeadline(clock.Add(time.Minute))
+	clock = clock.Add(time.Hour)
+	m, _ = m.Update(TickMsg{ID: m.ID()})
+	if !m.Timedout() {
+		t.Fatalf("expected the first tick after the deadline to time out, got %v", m.Timeout)
+	}
+}
+
+func TestDeadlinePause(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := t0
+	setNow(t, &clock)
+
+	m := NewDeadline(t0.Add(2500 * time.Millisecond))
+	if !m.Deadline().Equal(t0.Add(2500 * time.Millisecond)) {
+		t.Fatalf("expected the deadline to be set, got %v", m.Deadline())
+	}
+
+	clock = t0.Add(time.Second)
+	m, _ = m.Update(StartStopMsg{ID: m.ID(), running: false})
+	if m.Running() || !m.Deadline().IsZero() {
+		t.Fatal("expected a stopped timer to have no deadline")
+	}
+	if m.Timeout != 2*time.Second {
+		t.Fatalf("expected 1.5s left rounded up to 2s, got %v", m.Timeout)
+	}
+
+	// Time passing while stopped doesn't count down.
+	clock = t0.Add(10 * time.Second)
+	if m.remaining() != 2*time.Second {
+		t.Fatalf("expected the time left to be kept while stopped, got %v", m.remaining())
+	}
+
+	m, _ = m.Update(StartStopMsg{ID: m.ID(), running: true})
+	if want := t0.Add(11500 * time.Millisecond); !m.Deadline().Equal(want) {
+		t.Fatalf("expected the deadline to move on to %v, got %v", want, m.Deadline())
+	}
+	if m.Timeout != 2*time.Second || m.wait() != 500*time.Millisecond {
+		t.Fatalf("expected 2s left with the next tick in 500ms, got %v and %v", m.Timeout, m.wait())
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mikeflynn/bubbles/render"
 )
 
 var lastID int64
@@ -62,10 +63,16 @@ type TickMsg struct {
 	// alternatively listen for TimeoutMsg.
 	Timeout bool
 
+	// Overshoot is how late the tick fired relative to when it was
+	// scheduled. It can be used to correct for drift in clocks and
+	// countdowns.
+	Overshoot time.Duration
+
 	tag int
 }
 
-// TimeoutMsg is a message that is sent once when the timer times out.
+// TimeoutMsg is a message that is sent once when the timer times out. For
+// repeating timers it's sent every time the interval elapses.
 //
 // It's a convenience message sent alongside a TickMsg with the Timeout value
 // set to true.
@@ -84,6 +91,17 @@ type Model struct {
 	id      int
 	tag     int
 	running bool
+
+	// period is the duration the timeout is reset to after it elapses for
+	// repeating timers. It's zero for one-shot timers.
+	period time.Duration
+
+	// For timers counting down to a deadline on the wall clock, the
+	// deadline while running, and the time left while stopped. See
+	// NewDeadline.
+	wallClock bool
+	deadline  time.Time
+	paused    time.Duration
 }
 
 // NewWithInterval creates a new timer with the given timeout and tick interval.
@@ -101,6 +119,20 @@ func New(timeout time.Duration) Model {
 	return NewWithInterval(timeout, time.Second)
 }
 
+// NewRepeating creates a timer which times out every interval, sending a
+// TimeoutMsg each time, until it's stopped. It doesn't need to be
+// re-initialized between timeouts.
+func NewRepeating(interval time.Duration) Model {
+	m := NewWithInterval(interval, interval)
+	m.period = interval
+	return m
+}
+
+// Repeating returns whether the timer restarts itself after timing out.
+func (m Model) Repeating() bool {
+	return m.period > 0
+}
+
 // ID returns the model's identifier. This can be used to determine if messages
 // belong to this timer instance when there are multiple timers.
 func (m Model) ID() int {
@@ -134,6 +166,10 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m, nil
 		}
 		m.running = msg.running
+		if m.wallClock {
+			m.setRunning(msg.running)
+			m.Timeout = m.remaining()
+		}
 		return m, m.tick()
 	case TickMsg:
 		if !m.Running() || (msg.ID != 0 && msg.ID != m.id) {
@@ -147,8 +183,18 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m, nil
 		}
 
-		m.Timeout -= m.Interval
-		return m, tea.Batch(m.tick(), m.timedout())
+		if m.wallClock {
+			m.Timeout = m.remaining()
+		} else {
+			m.Timeout -= m.Interval
+		}
+		timedout := m.timedout()
+		if m.Repeating() && m.Timedout() {
+			// Repeating timers tick once a period, so start the next one by
+			// resetting the timeout to the period.
+			m.Timeout = m.period
+		}
+		return m, tea.Batch(m.tick(), timedout)
 	}
 
 	return m, nil
@@ -159,6 +205,12 @@ func (m Model) View() string {
 	return m.Timeout.String()
 }
 
+// RenderString renders the timer deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	return render.View(m.View, width, height)
+}
+
 // Start resumes the timer. Has no effect if the timer has timed out.
 func (m *Model) Start() tea.Cmd {
 	return m.startStop(true)
@@ -175,11 +227,23 @@ func (m *Model) Toggle() tea.Cmd {
 }
 
 func (m Model) tick() tea.Cmd {
-	return tea.Tick(m.Interval, func(_ time.Time) tea.Msg {
-		return TickMsg{ID: m.id, tag: m.tag, Timeout: m.Timedout()}
+	wait := m.wait()
+	scheduled := time.Now().Add(wait)
+	return tea.Tick(wait, func(t time.Time) tea.Msg {
+		return m.tickMsg(scheduled, t)
 	})
 }
 
+// tickMsg returns the tick scheduled for one time and fired at another.
+func (m Model) tickMsg(scheduled, fired time.Time) TickMsg {
+	return TickMsg{
+		ID:        m.id,
+		tag:       m.tag,
+		Timeout:   m.Timedout(),
+		Overshoot: max(0, fired.Sub(scheduled)),
+	}
+}
+
 func (m Model) timedout() tea.Cmd {
 	if !m.Timedout() {
 		return nil
@@ -0,0 +1,82 @@
+package timer
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+// timedOut returns whether the command returned with a tick sends a
+// TimeoutMsg. If it doesn't, the command is the next tick, which this waits
+// for.
+func timedOut(cmd tea.Cmd) bool {
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		return false
+	}
+	_, ok = batch[len(batch)-1]().(TimeoutMsg)
+	return ok
+}
+
+func TestRepeating(t *testing.T) {
+	m := NewRepeating(5 * time.Millisecond)
+	if !m.Repeating() || New(time.Second).Repeating() {
+		t.Fatal("expected only NewRepeating to repeat")
+	}
+
+	for i := range 3 {
+		var cmd tea.Cmd
+		m, cmd = m.Update(TickMsg{ID: m.ID()})
+		if !timedOut(cmd) {
+			t.Fatalf("expected tick %d to time out", i)
+		}
+		if m.Timeout != 5*time.Millisecond || !m.Running() {
+			t.Fatalf("expected the timeout to be reset to the period, got %v", m.Timeout)
+		}
+	}
+}
+
+func TestOneShot(t *testing.T) {
+	m := NewWithInterval(2*time.Second, time.Second)
+	m, cmd := m.Update(TickMsg{ID: m.ID()})
+	if m.Timedout() || cmd == nil {
+		t.Fatal("expected the timer to keep ticking")
+	}
+	m, cmd = m.Update(TickMsg{ID: m.ID()})
+	if !m.Timedout() || m.Running() || !timedOut(cmd) {
+		t.Fatal("expected the timer to time out")
+	}
+
+	// Ticks after timing out are ignored.
+	if _, cmd := m.Update(TickMsg{ID: m.ID()}); cmd != nil {
+		t.Fatal("expected no more ticks after timing out")
+	}
+}
+
+func TestOvershoot(t *testing.T) {
+	m := New(time.Minute)
+	scheduled := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if msg := m.tickMsg(scheduled, scheduled.Add(40*time.Millisecond)); msg.Overshoot != 40*time.Millisecond {
+		t.Fatalf("expected a late tick to report how late it was, got %v", msg.Overshoot)
+	}
+	if msg := m.tickMsg(scheduled, scheduled.Add(-time.Millisecond)); msg.Overshoot != 0 {
+		t.Fatalf("expected an early tick not to overshoot, got %v", msg.Overshoot)
+	}
+	if msg := m.tickMsg(scheduled, scheduled); msg.ID != m.ID() || msg.Timeout {
+		t.Fatalf("unexpected tick %+v", msg)
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	m := NewWithInterval(90*time.Second, time.Second)
+	t.Run("running", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(m, 10, 1)))
+	})
+	m.Timeout = 0
+	t.Run("timed out", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(m, 10, 1)))
+	})
+}
@@ -0,0 +1,343 @@
+// Package tour provides a step-by-step onboarding tour for Bubble Tea
+// applications. Each step shows a callout pointing at a named region of the
+// parent view, with everything else dimmed.
+package tour
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mikeflynn/bubbles/help"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
+	"github.com/mikeflynn/bubbles/textutil"
+)
+
+// Region is a rectangular area of the parent view, in cells.
+type Region struct {
+	X, Y          int
+	Width, Height int
+}
+
+// Step is a single callout in the tour.
+type Step struct {
+	Title string
+	Body  string
+
+	// Region is the name of the region the step points at. If it's empty, or
+	// no region with that name has been set, the callout is centered and
+	// nothing is highlighted.
+	Region string
+}
+
+// CompletedMsg is sent when the tour ends, either because the last step was
+// passed or because the user skipped it.
+type CompletedMsg struct {
+	// Skipped is true if the user left the tour before the last step.
+	Skipped bool
+
+	// Step is the index of the step the tour ended on.
+	Step int
+}
+
+// KeyMap is the key bindings for moving through the tour's steps and
+// leaving it. It satisfies the help.KeyMap interface.
+type KeyMap struct {
+	Next key.Binding
+	Prev key.Binding
+	Skip key.Binding
+}
+
+// ShortHelp implements the KeyMap interface.
+func (km KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{km.Next, km.Prev, km.Skip}
+}
+
+// FullHelp implements the KeyMap interface.
+func (km KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{km.Next, km.Prev, km.Skip}}
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Next: key.NewBinding(
+			key.WithKeys("right", "l", "enter", " "),
+			key.WithHelp("→", "next"),
+		),
+		Prev: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←", "back"),
+		),
+		Skip: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc", "skip tour"),
+		),
+	}
+}
+
+// Styles are the styles of the callout and of the dimmed parts of the view,
+// as returned by DefaultStyles by default.
+type Styles struct {
+	Callout  lipgloss.Style
+	Title    lipgloss.Style
+	Body     lipgloss.Style
+	Progress lipgloss.Style
+	Pointer  lipgloss.Style
+
+	// Dim is applied to everything outside the highlighted region.
+	Dim lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this
+// component.
+func DefaultStyles() Styles {
+	accent := lipgloss.AdaptiveColor{Light: "#F793FF", Dark: "#AD58B4"}
+	subdued := lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}
+
+	return Styles{
+		Callout:  lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(accent).Padding(0, 1),
+		Title:    lipgloss.NewStyle().Bold(true).Foreground(accent),
+		Body:     lipgloss.NewStyle(),
+		Progress: lipgloss.NewStyle().Foreground(subdued),
+		Pointer:  lipgloss.NewStyle().Foreground(accent),
+		Dim:      lipgloss.NewStyle().Faint(true).Foreground(subdued),
+	}
+}
+
+// Model is the state of an onboarding tour.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+	Help   help.Model
+
+	// CalloutWidth is the maximum width of the callout box, including its
+	// frame.
+	CalloutWidth int
+
+	steps   []Step
+	regions map[string]Region
+	current int
+	active  bool
+
+	width  int
+	height int
+}
+
+// New returns a model for the given steps. The tour isn't shown until Start is
+// called.
+func New(steps ...Step) Model {
+	return Model{
+		KeyMap:       DefaultKeyMap(),
+		Styles:       DefaultStyles(),
+		Help:         help.New(),
+		CalloutWidth: 40, //nolint:mnd
+		steps:        steps,
+		regions:      map[string]Region{},
+	}
+}
+
+// SetRegion sets the position of a named region in the parent view. Regions
+// should be updated whenever the parent's layout changes.
+func (m *Model) SetRegion(name string, r Region) {
+	if m.regions == nil {
+		m.regions = map[string]Region{}
+	}
+	m.regions[name] = r
+}
+
+// SetSize sets the size of the parent view the tour is overlaid on.
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Start shows the tour from its first step.
+func (m *Model) Start() {
+	m.current = 0
+	m.active = len(m.steps) > 0
+}
+
+// Active returns whether the tour is being shown.
+func (m Model) Active() bool {
+	return m.active
+}
+
+// Current returns the index of the current step.
+func (m Model) Current() int {
+	return m.current
+}
+
+// Steps returns the tour's steps.
+func (m Model) Steps() []Step {
+	return m.steps
+}
+
+// Init exists to satisfy the tea.Model interface.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if !m.active {
+		return m, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.KeyMap.Next):
+		if m.current < len(m.steps)-1 {
+			m.current++
+			return m, nil
+		}
+		return m, m.complete(false)
+	case key.Matches(keyMsg, m.KeyMap.Prev):
+		m.current = max(0, m.current-1)
+	case key.Matches(keyMsg, m.KeyMap.Skip):
+		return m, m.complete(true)
+	}
+
+	return m, nil
+}
+
+func (m *Model) complete(skipped bool) tea.Cmd {
+	m.active = false
+	msg := CompletedMsg{Skipped: skipped, Step: m.current}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// View renders the callout for the current step on its own. Most
+// applications will want to use Overlay instead.
+func (m Model) View() string {
+	if !m.active {
+		return ""
+	}
+
+	step := m.steps[m.current]
+	frame := m.Styles.Callout.GetHorizontalFrameSize()
+	width := m.CalloutWidth
+	if m.width > 0 {
+		width = min(width, m.width)
+	}
+	inner := max(1, width-frame)
+
+	var sections []string
+	if step.Title != "" {
+		sections = append(sections, m.Styles.Title.Width(inner).Render(step.Title))
+	}
+	if step.Body != "" {
+		sections = append(sections, m.Styles.Body.Width(inner).Render(step.Body))
+	}
+
+	m.Help.Width = inner
+	progress := fmt.Sprintf("%d/%d", m.current+1, len(m.steps))
+	sections = append(sections, "", m.Styles.Progress.Render(progress)+"  "+m.Help.View(m.KeyMap))
+
+	return m.Styles.Callout.Render(strings.Join(sections, "\n"))
+}
+
+// Overlay renders the tour on top of the parent's view. Everything outside the
+// current step's region is dimmed and the callout is placed next to the
+// region. If the tour isn't active the view is returned unchanged.
+func (m Model) Overlay(view string) string {
+	if !m.active {
+		return view
+	}
+
+	lines := strings.Split(view, "\n")
+	width, height := m.width, m.height
+	if width <= 0 {
+		for _, l := range lines {
+			width = max(width, ansi.StringWidth(l))
+		}
+	}
+	if height <= 0 {
+		height = len(lines)
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+
+	region, highlight := m.regions[m.steps[m.current].Region]
+
+	// Dim everything but the highlighted region.
+	for y, l := range lines {
+		if !highlight || y < region.Y || y >= region.Y+region.Height {
+			lines[y] = m.Styles.Dim.Render(ansi.Strip(l))
+			continue
+		}
+		left := ansi.Strip(ansi.Cut(l, 0, region.X))
+		right := ansi.Strip(ansi.Cut(l, region.X+region.Width, max(width, ansi.StringWidth(l))))
+		lines[y] = m.Styles.Dim.Render(left) + ansi.Cut(l, region.X, region.X+region.Width) + m.Styles.Dim.Render(right)
+	}
+
+	callout := strings.Split(m.View(), "\n")
+	boxW := lipgloss.Width(m.View())
+	boxH := len(callout)
+
+	// The callout goes below the region if there's room, otherwise above it,
+	// with a pointer in the gap between them. Without a region it's centered.
+	var x, y int
+	pointerY, pointer := -1, ""
+	switch {
+	case !highlight:
+		x = (width - boxW) / 2  //nolint:mnd
+		y = (height - boxH) / 2 //nolint:mnd
+	case region.Y+region.Height+boxH+1 <= height:
+		x = region.X
+		pointerY, pointer = region.Y+region.Height, "▲"
+		y = pointerY + 1
+	case region.Y-boxH-1 >= 0:
+		x = region.X
+		pointerY, pointer = region.Y-1, "▼"
+		y = pointerY - boxH
+	default:
+		// Not enough room either way, so overlap the region.
+		x, y = region.X, region.Y
+	}
+	x = clamp(x, 0, max(0, width-boxW))
+	y = max(0, y)
+
+	if pointerY >= 0 && pointerY < len(lines) {
+		px := region.X + max(0, region.Width-1)/2 //nolint:mnd
+		lines[pointerY] = place(lines[pointerY], m.Styles.Pointer.Render(pointer), px, width)
+	}
+	for i, c := range callout {
+		if y+i >= len(lines) {
+			break
+		}
+		lines[y+i] = place(lines[y+i], c, x, width)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// RenderString renders the tour over the given parent view deterministically
+// at the given size, for golden-file tests. See the render package for
+// details.
+func RenderString(m Model, view string, width, height int) string {
+	m.SetSize(width, height)
+	return render.View(func() string { return m.Overlay(view) }, width, height)
+}
+
+// place draws s over line starting at column x.
+func place(line, s string, x, width int) string {
+	line = textutil.PadRight(line, x)
+	left := textutil.Truncate(line, x, "")
+	right := ansi.Cut(line, x+ansi.StringWidth(s), max(width, ansi.StringWidth(line)))
+	return left + s + right
+}
+
+func clamp(v, low, high int) int {
+	return min(max(v, low), high)
+}
@@ -0,0 +1,110 @@
+package tour
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+func newTour() Model {
+	m := New(
+		Step{Title: "Sidebar", Body: "Your files live here.", Region: "sidebar"},
+		Step{Title: "Welcome", Body: "That's it!"},
+	)
+	m.CalloutWidth = 30
+	m.SetSize(60, 20)
+	m.SetRegion("sidebar", Region{X: 0, Y: 0, Width: 10, Height: 3})
+	return m
+}
+
+func background() string {
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = strings.Repeat("x", 60)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestNavigation(t *testing.T) {
+	m := newTour()
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRight}); cmd != nil || m.Active() {
+		t.Fatal("expected inactive tour to ignore input")
+	}
+
+	m.Start()
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if m.Current() != 1 {
+		t.Fatalf("expected to move to step 1, got %d", m.Current())
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if m.Current() != 0 {
+		t.Fatalf("expected to stay on the first step, got %d", m.Current())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if m.Active() {
+		t.Fatal("expected tour to end after the last step")
+	}
+	if msg := cmd().(CompletedMsg); msg.Skipped || msg.Step != 1 {
+		t.Fatalf("unexpected completion message: %+v", msg)
+	}
+
+	m.Start()
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if msg := cmd().(CompletedMsg); !msg.Skipped || msg.Step != 0 {
+		t.Fatalf("unexpected skip message: %+v", msg)
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	m := newTour()
+	bg := background()
+	if m.Overlay(bg) != bg {
+		t.Fatal("expected inactive tour to leave the view untouched")
+	}
+
+	m.Start()
+	lines := strings.Split(ansi.Strip(m.Overlay(bg)), "\n")
+	if len(lines) != 20 {
+		t.Fatalf("expected overlay to keep the view height, got %d lines", len(lines))
+	}
+
+	// The callout sits below the region, with a pointer in between.
+	if !strings.HasPrefix(lines[3], "xxxx▲x") {
+		t.Errorf("expected pointer below the region, got %q", lines[3])
+	}
+	if !strings.HasPrefix(lines[4], "╭") || !strings.Contains(lines[5], "Sidebar") {
+		t.Errorf("expected callout below the pointer, got:\n%s", strings.Join(lines[4:7], "\n"))
+	}
+	for i, l := range lines {
+		if w := ansi.StringWidth(l); w != 60 {
+			t.Errorf("line %d: expected width 60, got %d", i, w)
+		}
+	}
+
+	// Steps without a region are centered.
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	view := ansi.Strip(m.Overlay(bg))
+	if !strings.Contains(strings.Split(view, "\n")[9], "That's it!") {
+		t.Errorf("expected centered callout, got:\n%s", view)
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	t.Run("region", func(t *testing.T) {
+		m := newTour()
+		m.Start()
+		golden.RequireEqual(t, []byte(RenderString(m, background(), 60, 20)))
+	})
+	t.Run("centered", func(t *testing.T) {
+		m := newTour()
+		m.Start()
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+		golden.RequireEqual(t, []byte(RenderString(m, background(), 60, 20)))
+	})
+}
@@ -0,0 +1,463 @@
+// Package transport provides media-style transport controls for Bubble Tea
+// applications: play, pause and stop, a seek bar and elapsed and total times.
+// It works equally well for audio and video players and for anything else
+// that plays back over time, such as log replay.
+package transport
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/help"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
+)
+
+var lastID int64
+
+func nextID() int {
+	return int(atomic.AddInt64(&lastID, 1))
+}
+
+// State is the playback state.
+type State int
+
+// Playback states.
+const (
+	Stopped State = iota
+	Playing
+	Paused
+)
+
+// String returns a human-readable name for the state.
+func (s State) String() string {
+	return [...]string{"stopped", "playing", "paused"}[s]
+}
+
+// StateChangedMsg is sent when playback starts, pauses or stops.
+type StateChangedMsg struct {
+	ID    int
+	State State
+}
+
+// PositionChangedMsg is sent when the user seeks, either with the keyboard or
+// the mouse.
+type PositionChangedMsg struct {
+	ID       int
+	Position time.Duration
+}
+
+// tickMsg advances the position while playing.
+type tickMsg struct {
+	id  int
+	tag int
+}
+
+// KeyMap is the key bindings for playing, pausing, stopping and seeking. It
+// satisfies the help.KeyMap interface.
+type KeyMap struct {
+	PlayPause    key.Binding
+	Stop         key.Binding
+	SeekForward  key.Binding
+	SeekBackward key.Binding
+	SeekStart    key.Binding
+	SeekEnd      key.Binding
+}
+
+// ShortHelp implements the KeyMap interface.
+func (km KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{km.PlayPause, km.Stop, km.SeekBackward, km.SeekForward}
+}
+
+// FullHelp implements the KeyMap interface.
+func (km KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{km.PlayPause, km.Stop},
+		{km.SeekBackward, km.SeekForward, km.SeekStart, km.SeekEnd},
+	}
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		PlayPause: key.NewBinding(
+			key.WithKeys(" ", "k"),
+			key.WithHelp("space", "play/pause"),
+		),
+		Stop: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "stop"),
+		),
+		SeekForward: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→", "forward"),
+		),
+		SeekBackward: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←", "back"),
+		),
+		SeekStart: key.NewBinding(
+			key.WithKeys("home", "0"),
+			key.WithHelp("home", "start"),
+		),
+		SeekEnd: key.NewBinding(
+			key.WithKeys("end", "$"),
+			key.WithHelp("end", "end"),
+		),
+	}
+}
+
+// Styles are the styles of the transport buttons, the seek bar and the
+// times. DefaultStyles returns the defaults.
+type Styles struct {
+	Button      lipgloss.Style
+	Time        lipgloss.Style
+	Filled      lipgloss.Style
+	Empty       lipgloss.Style
+	Handle      lipgloss.Style
+	HelpSection lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this
+// component.
+func DefaultStyles() Styles {
+	accent := lipgloss.AdaptiveColor{Light: "#F793FF", Dark: "#AD58B4"}
+
+	return Styles{
+		Button:      lipgloss.NewStyle().Foreground(accent).Bold(true),
+		Time:        lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}),
+		Filled:      lipgloss.NewStyle().Foreground(accent),
+		Empty:       lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#DDDADA", Dark: "#3C3C3C"}),
+		Handle:      lipgloss.NewStyle().Foreground(accent).Bold(true),
+		HelpSection: lipgloss.NewStyle().PaddingTop(1),
+	}
+}
+
+// Icons are the characters used to draw the controls.
+type Icons struct {
+	Play   string
+	Pause  string
+	Stop   string
+	Filled string
+	Empty  string
+	Handle string
+}
+
+// DefaultIcons returns the default set of icons.
+func DefaultIcons() Icons {
+	return Icons{
+		Play:   "▶",
+		Pause:  "⏸",
+		Stop:   "■",
+		Filled: "━",
+		Empty:  "─",
+		Handle: "●",
+	}
+}
+
+// Model is the state of a set of transport controls.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+	Icons  Icons
+	Help   help.Model
+
+	// ShowHelp determines whether the help view is rendered beneath the
+	// controls.
+	ShowHelp bool
+
+	// Width is the width of the controls, including the times.
+	Width int
+
+	// Interval is how often the position advances while playing. Defaults to
+	// 1 second.
+	Interval time.Duration
+
+	// Step is how far the seek keys and mouse wheel move the position.
+	// Defaults to 5 seconds.
+	Step time.Duration
+
+	// X and Y are the position of the component on screen. They're used to
+	// work out what mouse events refer to.
+	X, Y int
+
+	id       int
+	tag      int
+	state    State
+	position time.Duration
+	duration time.Duration
+	dragging bool
+}
+
+// New returns a model for media of the given duration.
+func New(duration time.Duration) Model {
+	return Model{
+		KeyMap:   DefaultKeyMap(),
+		Styles:   DefaultStyles(),
+		Icons:    DefaultIcons(),
+		Help:     help.New(),
+		ShowHelp: true,
+		Width:    60, //nolint:mnd
+		Interval: time.Second,
+		Step:     5 * time.Second, //nolint:mnd
+		id:       nextID(),
+		duration: duration,
+	}
+}
+
+// ID returns the unique ID of the model.
+func (m Model) ID() int {
+	return m.id
+}
+
+// State returns the playback state.
+func (m Model) State() State {
+	return m.state
+}
+
+// Position returns the playback position.
+func (m Model) Position() time.Duration {
+	return m.position
+}
+
+// Duration returns the total duration.
+func (m Model) Duration() time.Duration {
+	return m.duration
+}
+
+// SetDuration sets the total duration, clamping the position to it.
+func (m *Model) SetDuration(d time.Duration) {
+	m.duration = max(0, d)
+	m.position = clamp(m.position, 0, m.duration)
+}
+
+// SetPosition sets the playback position without sending a
+// PositionChangedMsg. Use it to keep the controls in sync with the media.
+func (m *Model) SetPosition(d time.Duration) {
+	m.position = clamp(d, 0, m.duration)
+}
+
+// Play starts or resumes playback. If playback has reached the end it starts
+// again from the beginning.
+func (m *Model) Play() tea.Cmd {
+	if m.state == Playing {
+		return nil
+	}
+	if m.position >= m.duration {
+		m.position = 0
+	}
+	m.tag++
+	return tea.Batch(m.setState(Playing), m.tick())
+}
+
+// Pause pauses playback.
+func (m *Model) Pause() tea.Cmd {
+	if m.state != Playing {
+		return nil
+	}
+	return m.setState(Paused)
+}
+
+// Toggle pauses playback if it's playing and plays it otherwise.
+func (m *Model) Toggle() tea.Cmd {
+	if m.state == Playing {
+		return m.Pause()
+	}
+	return m.Play()
+}
+
+// Stop stops playback and returns to the beginning.
+func (m *Model) Stop() tea.Cmd {
+	m.position = 0
+	if m.state == Stopped {
+		return nil
+	}
+	return m.setState(Stopped)
+}
+
+// Seek moves the playback position and sends a PositionChangedMsg.
+func (m *Model) Seek(d time.Duration) tea.Cmd {
+	m.position = clamp(d, 0, m.duration)
+	id, pos := m.id, m.position
+	return func() tea.Msg {
+		return PositionChangedMsg{ID: id, Position: pos}
+	}
+}
+
+func (m *Model) setState(s State) tea.Cmd {
+	m.state = s
+	id := m.id
+	return func() tea.Msg {
+		return StateChangedMsg{ID: id, State: s}
+	}
+}
+
+func (m Model) tick() tea.Cmd {
+	id, tag := m.id, m.tag
+	return tea.Tick(m.Interval, func(time.Time) tea.Msg {
+		return tickMsg{id: id, tag: tag}
+	})
+}
+
+// Init exists to satisfy the tea.Model interface.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tickMsg:
+		if msg.id != m.id || msg.tag != m.tag || m.state != Playing {
+			return m, nil
+		}
+		m.position = min(m.duration, m.position+m.Interval)
+		if m.position >= m.duration {
+			return m, m.setState(Stopped)
+		}
+		return m, m.tick()
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.PlayPause):
+			return m, m.Toggle()
+		case key.Matches(msg, m.KeyMap.Stop):
+			return m, m.Stop()
+		case key.Matches(msg, m.KeyMap.SeekForward):
+			return m, m.Seek(m.position + m.Step)
+		case key.Matches(msg, m.KeyMap.SeekBackward):
+			return m, m.Seek(m.position - m.Step)
+		case key.Matches(msg, m.KeyMap.SeekStart):
+			return m, m.Seek(0)
+		case key.Matches(msg, m.KeyMap.SeekEnd):
+			return m, m.Seek(m.duration)
+		}
+
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
+	}
+
+	return m, nil
+}
+
+func (m Model) updateMouse(msg tea.MouseMsg) (Model, tea.Cmd) {
+	if msg.Action == tea.MouseActionRelease {
+		m.dragging = false
+		return m, nil
+	}
+
+	barX, barWidth := m.barBounds()
+	x := msg.X - m.X
+	onControls := msg.Y == m.Y && x >= 0 && x < m.Width
+
+	switch {
+	case m.dragging && msg.Action == tea.MouseActionMotion:
+		return m, m.Seek(m.positionAt(x-barX, barWidth))
+	case !onControls || msg.Action != tea.MouseActionPress:
+		return m, nil
+	case msg.Button == tea.MouseButtonWheelUp || msg.Button == tea.MouseButtonWheelRight:
+		return m, m.Seek(m.position + m.Step)
+	case msg.Button == tea.MouseButtonWheelDown || msg.Button == tea.MouseButtonWheelLeft:
+		return m, m.Seek(m.position - m.Step)
+	case msg.Button != tea.MouseButtonLeft:
+		return m, nil
+	case x < lipgloss.Width(m.buttonIcon()):
+		return m, m.Toggle()
+	case x >= barX && x < barX+barWidth:
+		m.dragging = true
+		return m, m.Seek(m.positionAt(x-barX, barWidth))
+	}
+	return m, nil
+}
+
+// positionAt returns the position for the given cell of the seek bar.
+func (m Model) positionAt(x, width int) time.Duration {
+	if width <= 1 {
+		return 0
+	}
+	x = clamp(x, 0, width-1)
+	return time.Duration(float64(m.duration) * float64(x) / float64(width-1))
+}
+
+// barBounds returns the offset and width of the seek bar within the
+// controls.
+func (m Model) barBounds() (x, width int) {
+	elapsed, total := m.times()
+	x = lipgloss.Width(m.buttonIcon()) + 1 + lipgloss.Width(elapsed) + 1
+	width = m.Width - x - 1 - lipgloss.Width(total)
+	return x, max(0, width)
+}
+
+// buttonIcon returns the icon for the current state.
+func (m Model) buttonIcon() string {
+	switch m.state {
+	case Playing:
+		return m.Icons.Play
+	case Paused:
+		return m.Icons.Pause
+	default:
+		return m.Icons.Stop
+	}
+}
+
+func (m Model) times() (elapsed, total string) {
+	hours := m.duration >= time.Hour
+	return formatDuration(m.position, hours), formatDuration(m.duration, hours)
+}
+
+// formatDuration formats d as minutes and seconds, or hours, minutes and
+// seconds.
+func formatDuration(d time.Duration, hours bool) string {
+	s := int(d.Round(time.Second) / time.Second)
+	if hours {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60) //nolint:mnd
+	}
+	return fmt.Sprintf("%02d:%02d", s/60, s%60) //nolint:mnd
+}
+
+// View renders the component.
+func (m Model) View() string {
+	elapsed, total := m.times()
+	_, width := m.barBounds()
+
+	controls := m.Styles.Button.Render(m.buttonIcon()) + " " +
+		m.Styles.Time.Render(elapsed) + " " +
+		m.barView(width) + " " +
+		m.Styles.Time.Render(total)
+
+	if !m.ShowHelp {
+		return controls
+	}
+	m.Help.Width = m.Width
+	return controls + "\n" + m.Styles.HelpSection.Render(m.Help.View(m.KeyMap))
+}
+
+// RenderString renders the controls deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	m.Width = width
+	return render.View(m.View, width, height)
+}
+
+func (m Model) barView(width int) string {
+	if width <= 0 {
+		return ""
+	}
+	handle := 0
+	if m.duration > 0 {
+		handle = int(float64(width-1) * float64(m.position) / float64(m.duration))
+	}
+	handle = clamp(handle, 0, width-1)
+
+	return m.Styles.Filled.Render(strings.Repeat(m.Icons.Filled, handle)) +
+		m.Styles.Handle.Render(m.Icons.Handle) +
+		m.Styles.Empty.Render(strings.Repeat(m.Icons.Empty, width-handle-1))
+}
+
+func clamp[T int | time.Duration](v, low, high T) T {
+	return min(max(v, low), high)
+}
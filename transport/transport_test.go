@@ -0,0 +1,113 @@
+package transport
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+func TestPlayback(t *testing.T) {
+	m := New(3 * time.Second)
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	if m.State() != Playing {
+		t.Fatalf("expected to be playing, got %s", m.State())
+	}
+	if cmd == nil {
+		t.Fatal("expected a command to start ticking")
+	}
+
+	// Stale ticks are ignored.
+	m, _ = m.Update(tickMsg{id: m.id, tag: m.tag - 1})
+	if m.Position() != 0 {
+		t.Fatalf("expected stale tick to be ignored, got %s", m.Position())
+	}
+
+	for range 3 {
+		m, cmd = m.Update(tickMsg{id: m.id, tag: m.tag})
+	}
+	if m.State() != Stopped || m.Position() != 3*time.Second {
+		t.Fatalf("expected to stop at the end, got %s at %s", m.State(), m.Position())
+	}
+	if msg, ok := cmd().(StateChangedMsg); !ok || msg.State != Stopped {
+		t.Fatalf("expected StateChangedMsg, got %#v", cmd())
+	}
+}
+
+func TestSeek(t *testing.T) {
+	m := New(time.Minute)
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if msg, ok := cmd().(PositionChangedMsg); !ok || msg.Position != 5*time.Second || msg.ID != m.ID() {
+		t.Fatalf("expected PositionChangedMsg at 5s, got %#v", cmd())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if m.Position() != time.Minute {
+		t.Fatalf("expected position to be clamped to the duration, got %s", m.Position())
+	}
+
+	// SetPosition doesn't send a message.
+	m.SetPosition(30 * time.Second)
+	if m.Position() != 30*time.Second {
+		t.Fatalf("expected position to be set, got %s", m.Position())
+	}
+}
+
+func TestMouse(t *testing.T) {
+	m := New(time.Minute)
+	m.Width = 30
+	m.X, m.Y = 10, 5
+	m.ShowHelp = false
+
+	barX, barWidth := m.barBounds()
+	click := func(x int, action tea.MouseAction) tea.MouseMsg {
+		return tea.MouseMsg{X: m.X + x, Y: m.Y, Button: tea.MouseButtonLeft, Action: action}
+	}
+
+	// Clicking the end of the bar seeks to the end, and dragging follows.
+	m, _ = m.Update(click(barX+barWidth-1, tea.MouseActionPress))
+	if m.Position() != time.Minute {
+		t.Fatalf("expected to seek to the end, got %s", m.Position())
+	}
+	m, _ = m.Update(click(barX-5, tea.MouseActionMotion))
+	if m.Position() != 0 {
+		t.Fatalf("expected drag to seek to the start, got %s", m.Position())
+	}
+	m, _ = m.Update(click(barX+barWidth-1, tea.MouseActionRelease))
+	m, _ = m.Update(click(barX+barWidth-1, tea.MouseActionMotion))
+	if m.Position() != 0 {
+		t.Fatalf("expected motion after release to be ignored, got %s", m.Position())
+	}
+
+	// Clicking the button toggles playback.
+	m, _ = m.Update(click(0, tea.MouseActionPress))
+	if m.State() != Playing {
+		t.Fatalf("expected to be playing, got %s", m.State())
+	}
+
+	view := ansi.Strip(m.View())
+	if w := ansi.StringWidth(view); w != m.Width {
+		t.Errorf("expected view to be %d wide, got %d: %q", m.Width, w, view)
+	}
+	if !strings.HasPrefix(view, "▶ 00:00 ●") || !strings.HasSuffix(view, " 01:00") {
+		t.Errorf("unexpected view: %q", view)
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	t.Run("stopped", func(t *testing.T) {
+		golden.RequireEqual(t, []byte(RenderString(New(time.Minute), 40, 3)))
+	})
+	t.Run("playing", func(t *testing.T) {
+		m := New(2 * time.Hour)
+		m.Play()
+		m.SetPosition(45 * time.Minute)
+		golden.RequireEqual(t, []byte(RenderString(m, 40, 3)))
+	})
+}
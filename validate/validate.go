@@ -0,0 +1,256 @@
+// Package validate provides composable validation rules for input bubbles.
+// Rules return structured errors naming the field and the rule which failed,
+// and Field combines rules into a function which can be used as the Validate
+// function of a textinput or textarea:
+//
+//	email := textinput.New()
+//	email.Validate = validate.Field("email", validate.Required(), validate.Email())
+//
+// Errors from several fields can be gathered with Collect, for showing a
+// summary or checking that a whole form is valid.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Rule checks a value, returning an *Error if it's invalid.
+//
+// Apart from Required, the rules in this package accept empty values, so
+// that optional fields can be validated when they're filled in.
+type Rule func(value string) error
+
+// Error describes why a value is invalid.
+type Error struct {
+	// Field is the name of the field, as given to Field. It's empty for
+	// errors returned by rules on their own.
+	Field string
+
+	// Rule identifies the rule which failed, such as "required" or
+	// "max_length", for looking up translations or custom messages.
+	Rule string
+
+	// Message is a human-readable description of the problem.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// Errors is a list of validation errors, usually for several fields.
+type Errors []*Error
+
+// Error implements the error interface, joining the messages with
+// semicolons.
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Field returns the first error for the given field, or nil if the field is
+// valid.
+func (e Errors) Field(name string) *Error {
+	for _, err := range e {
+		if err.Field == name {
+			return err
+		}
+	}
+	return nil
+}
+
+// Collect gathers validation errors, such as the Err fields of several
+// inputs, into Errors. Nil errors are skipped, Errors are flattened and other
+// errors are wrapped with a rule of "error". It returns nil if there are no
+// errors, so the result can be checked with a plain nil comparison.
+func Collect(errs ...error) error {
+	var out Errors
+	for _, err := range errs {
+		var list Errors
+		var one *Error
+		switch {
+		case err == nil:
+		case errors.As(err, &list):
+			out = append(out, list...)
+		case errors.As(err, &one):
+			out = append(out, one)
+		default:
+			out = append(out, &Error{Rule: "error", Message: err.Error()})
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// Field combines rules for a named field. The returned function runs the
+// rules in order and returns the first error, with its Field set to name. It
+// can be assigned to the Validate field of a textinput or textarea.
+func Field(name string, rules ...Rule) func(string) error {
+	return func(value string) error {
+		for _, rule := range rules {
+			err := rule(value)
+			if err == nil {
+				continue
+			}
+			var e *Error
+			if !errors.As(err, &e) {
+				e = &Error{Rule: "custom", Message: err.Error()}
+			}
+			named := *e
+			named.Field = name
+			return &named
+		}
+		return nil
+	}
+}
+
+// All combines rules into one, which returns the first error.
+func All(rules ...Rule) Rule {
+	return func(value string) error {
+		for _, rule := range rules {
+			if err := rule(value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Required rejects empty and whitespace-only values.
+func Required() Rule {
+	return func(value string) error {
+		if strings.TrimSpace(value) == "" {
+			return &Error{Rule: "required", Message: "is required"}
+		}
+		return nil
+	}
+}
+
+// MinLength rejects values shorter than n characters.
+func MinLength(n int) Rule {
+	return func(value string) error {
+		if value != "" && utf8.RuneCountInString(value) < n {
+			return &Error{Rule: "min_length", Message: fmt.Sprintf("must be at least %d characters", n)}
+		}
+		return nil
+	}
+}
+
+// MaxLength rejects values longer than n characters.
+func MaxLength(n int) Rule {
+	return func(value string) error {
+		if utf8.RuneCountInString(value) > n {
+			return &Error{Rule: "max_length", Message: fmt.Sprintf("must be at most %d characters", n)}
+		}
+		return nil
+	}
+}
+
+// Number rejects values which aren't numbers.
+func Number() Rule {
+	return Range(math.Inf(-1), math.Inf(1))
+}
+
+// Range rejects values which aren't numbers between minimum and maximum,
+// inclusive.
+func Range(minimum, maximum float64) Rule {
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return &Error{Rule: "number", Message: "must be a number"}
+		}
+		if f < minimum || f > maximum {
+			return &Error{Rule: "range", Message: rangeMessage(minimum, maximum)}
+		}
+		return nil
+	}
+}
+
+func rangeMessage(minimum, maximum float64) string {
+	format := func(f float64) string {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	switch {
+	case math.IsInf(minimum, -1):
+		return "must be at most " + format(maximum)
+	case math.IsInf(maximum, 1):
+		return "must be at least " + format(minimum)
+	}
+	return fmt.Sprintf("must be between %s and %s", format(minimum), format(maximum))
+}
+
+// Match rejects values which don't match the regular expression, with the
+// given message.
+func Match(re *regexp.Regexp, message string) Rule {
+	return func(value string) error {
+		if value != "" && !re.MatchString(value) {
+			return &Error{Rule: "match", Message: message}
+		}
+		return nil
+	}
+}
+
+// Email rejects values which aren't a bare email address, such as
+// "name@example.com".
+func Email() Rule {
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+		addr, err := mail.ParseAddress(value)
+		if err != nil || addr.Address != value || !strings.Contains(value, "@") {
+			return &Error{Rule: "email", Message: "must be an email address"}
+		}
+		return nil
+	}
+}
+
+// URL rejects values which aren't absolute URLs with one of the given
+// schemes. If no schemes are given, http and https are accepted.
+func URL(schemes ...string) Rule {
+	if len(schemes) == 0 {
+		schemes = []string{"http", "https"}
+	}
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+		u, err := url.Parse(value)
+		if err != nil || u.Host == "" || !slices.Contains(schemes, strings.ToLower(u.Scheme)) {
+			return &Error{Rule: "url", Message: "must be a URL starting with " + strings.Join(schemes, ":// or ") + "://"}
+		}
+		return nil
+	}
+}
+
+// Custom returns a rule which rejects values for which ok returns false, with
+// the given rule name and message.
+func Custom(rule string, ok func(string) bool, message string) Rule {
+	return func(value string) error {
+		if !ok(value) {
+			return &Error{Rule: rule, Message: message}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package validate
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  Rule
+		value string
+		want  string // the failing rule, or empty if valid
+	}{
+		{"required", Required(), " ", "required"},
+		{"required ok", Required(), "x", ""},
+		{"min length", MinLength(3), "ab", "min_length"},
+		{"min length empty", MinLength(3), "", ""},
+		{"max length", MaxLength(2), "日本語", "max_length"},
+		{"range", Range(1, 10), "11", "range"},
+		{"range ok", Range(1, 10), "2.5", ""},
+		{"not a number", Number(), "ten", "number"},
+		{"match", Match(regexp.MustCompile(`^\d{5}$`), "must be a ZIP code"), "1234", "match"},
+		{"email", Email(), "Bob <bob@example.com>", "email"},
+		{"email ok", Email(), "bob@example.com", ""},
+		{"url", URL(), "example.com", "url"},
+		{"url scheme", URL("https"), "http://example.com", "url"},
+		{"url ok", URL(), "https://example.com/path", ""},
+		{"custom", Custom("even", func(s string) bool { return len(s)%2 == 0 }, "must be even"), "abc", "even"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rule(tc.value)
+			var e *Error
+			switch {
+			case tc.want == "" && err != nil:
+				t.Fatalf("expected %q to be valid, got %v", tc.value, err)
+			case tc.want != "" && (!errors.As(err, &e) || e.Rule != tc.want):
+				t.Fatalf("expected %q to fail %s, got %v", tc.value, tc.want, err)
+			}
+		})
+	}
+}
+
+func TestFieldAndCollect(t *testing.T) {
+	name := Field("name", Required(), MaxLength(5))
+	age := Field("age", Range(0, 150))
+	email := Field("email", Email(), func(string) error { return errors.New("already taken") })
+
+	if err := name("Ada"); err != nil {
+		t.Fatalf("expected a valid name, got %v", err)
+	}
+	if err := name(""); err == nil || err.Error() != "name: is required" {
+		t.Fatalf("expected the field to be named, got %v", err)
+	}
+
+	err := Collect(name("Augusta"), age("200"), nil, email("ada@example.com"))
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("expected three errors, got %v", err)
+	}
+	if e := errs.Field("email"); e == nil || e.Rule != "custom" || e.Message != "already taken" {
+		t.Fatalf("expected plain errors to be wrapped, got %+v", e)
+	}
+	if want := "name: must be at most 5 characters; age: must be between 0 and 150; email: already taken"; err.Error() != want {
+		t.Fatalf("expected %q, got %q", want, err.Error())
+	}
+
+	if Collect(nil, name("Ada")) != nil {
+		t.Fatal("expected no errors to collect to nil")
+	}
+}
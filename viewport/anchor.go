@@ -0,0 +1,81 @@
+package viewport
+
+import "strings"
+
+// WithAnchorBottom sets whether the scroll position is kept from the bottom
+// of the content. See SetAnchorBottom.
+func WithAnchorBottom(v bool) Option {
+	return func(m *Model) {
+		m.anchorBottom = v
+	}
+}
+
+// SetAnchorBottom sets whether SetContent keeps the scroll position as a
+// distance from the bottom of the content, such as for logs and chats, rather
+// than from the top. A viewport at the bottom then follows lines as they're
+// appended, and lines inserted above the view, such as older messages loaded
+// into a chat, don't move it. Lines appended while the viewport is scrolled
+// up don't move it either, so that what's being read stays in place.
+//
+// The distance from the bottom is given by BottomOffset.
+func (m *Model) SetAnchorBottom(v bool) {
+	m.anchorBottom = v
+}
+
+// AnchorBottom returns whether the scroll position is kept from the bottom of
+// the content.
+func (m Model) AnchorBottom() bool {
+	return m.anchorBottom
+}
+
+// BottomOffset returns the vertical scroll position as the number of lines
+// between the bottom of the view and the bottom of the content, which is 0
+// when the viewport is at the bottom.
+func (m Model) BottomOffset() int {
+	return m.ToBottomOffset(m.YOffset)
+}
+
+// SetBottomOffset sets the vertical scroll position as the number of lines
+// between the bottom of the view and the bottom of the content.
+func (m *Model) SetBottomOffset(n int) {
+	m.SetYOffset(m.ToYOffset(n))
+}
+
+// ToBottomOffset converts a YOffset, counted from the top of the content, to
+// the matching offset counted from the bottom, for the current content and
+// height.
+func (m Model) ToBottomOffset(y int) int {
+	return max(0, m.maxYOffset()-y)
+}
+
+// ToYOffset converts an offset counted from the bottom of the content to the
+// matching YOffset, counted from the top, for the current content and
+// height.
+func (m Model) ToYOffset(bottom int) int {
+	return max(0, m.maxYOffset()-bottom)
+}
+
+// reanchor restores the distance from the bottom the viewport was at before
+// its content changed from prev, unless the viewport was scrolled up and
+// lines were only appended.
+func (m *Model) reanchor(prev []string, bottom int) {
+	if bottom > 0 && appends(prev, m.content) {
+		return
+	}
+	m.SetBottomOffset(bottom)
+}
+
+// appends reports whether next is prev with text appended, to its last line
+// or as new lines.
+func appends(prev, next []string) bool {
+	if len(prev) == 0 || len(next) < len(prev) {
+		return false
+	}
+	last := len(prev) - 1
+	for i := range last {
+		if prev[i] != next[i] {
+			return false
+		}
+	}
+	return strings.HasPrefix(next[last], prev[last])
+}
@@ -0,0 +1,169 @@
+package viewport
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/parser"
+	"github.com/rivo/uniseg"
+)
+
+// segmentKind is the kind of a segment of a line.
+type segmentKind uint8
+
+const (
+	// segText is a printable character or grapheme cluster.
+	segText segmentKind = iota
+
+	// segControl is a control character, such as a tab, which takes no
+	// cells.
+	segControl
+
+	// segEscape is a run of escape sequences, such as SGR styles or
+	// hyperlinks.
+	segEscape
+)
+
+// segment is a run of bytes of a line that's kept or dropped as a whole when
+// the line is cut: a character, a control character, or escape sequences.
+type segment struct {
+	start, end int // byte offsets in the line
+	col        int // column the segment starts at
+	width      int
+	kind       segmentKind
+}
+
+// lineCut indexes the segments of a line holding escape sequences or
+// non-ASCII text, so that cutting the line for horizontal scrolling doesn't
+// parse it again on every frame. Cutting it gives the same result as
+// ansi.Cut: the characters within the columns, along with every escape
+// sequence of the line, so that styles and hyperlinks carry over the edges.
+type lineCut struct {
+	segments []segment
+
+	// escapes holds the indexes of the escape segments.
+	escapes []int
+
+	// width is the width of the line in cells.
+	width int
+}
+
+// indexLines indexes the segments of the given lines for cutting, returning
+// nil for lines of plain ASCII text, along with the width of the widest line.
+func indexLines(lines []string) ([]*lineCut, int) {
+	cuts := make([]*lineCut, len(lines))
+	widest := 0
+	for i, l := range lines {
+		cuts[i] = indexLine(l)
+		w := len(l)
+		if cuts[i] != nil {
+			w = cuts[i].width
+		}
+		widest = max(widest, w)
+	}
+	return cuts, widest
+}
+
+// indexLine indexes the segments of a line, or returns nil if it's plain
+// ASCII text, which is cut by slicing it.
+func indexLine(s string) *lineCut {
+	if isPlain(s) {
+		return nil
+	}
+
+	c := &lineCut{width: ansi.StringWidth(s)}
+	state := parser.GroundState
+	col := 0
+	for i := 0; i < len(s); {
+		next, action := parser.Table.Transition(state, s[i])
+		if next == parser.Utf8State {
+			cluster, _, width, _ := uniseg.FirstGraphemeClusterInString(s[i:], -1)
+			c.segments = append(c.segments, segment{start: i, end: i + len(cluster), col: col, width: width, kind: segText})
+			i += len(cluster)
+			col += width
+			state = parser.GroundState
+			continue
+		}
+
+		switch action {
+		case parser.PrintAction:
+			c.segments = append(c.segments, segment{start: i, end: i + 1, col: col, width: 1, kind: segText})
+			col++
+		case parser.ExecuteAction:
+			c.segments = append(c.segments, segment{start: i, end: i + 1, col: col, kind: segControl})
+		default:
+			if n := len(c.segments); n > 0 && c.segments[n-1].kind == segEscape && c.segments[n-1].end == i {
+				c.segments[n-1].end++
+				break
+			}
+			c.escapes = append(c.escapes, len(c.segments))
+			c.segments = append(c.segments, segment{start: i, end: i + 1, col: col, kind: segEscape})
+		}
+		state = next
+		i++
+	}
+	return c
+}
+
+// isPlain returns whether a line is made of printable ASCII characters only.
+func isPlain(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < ' ' || s[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// cutLine returns the columns from left up to right of the line to scroll
+// through at the given index, given as is and as it's shown, with
+// highlights applied. The line's index is only used if it's shown as is.
+func (m Model) cutLine(row int, raw, line string, left, right int) string {
+	if row < 0 || row >= len(m.cuts) || len(m.cuts) != len(m.lines) || line != raw {
+		return ansi.Cut(line, left, right)
+	}
+	return cutLine(line, m.cuts[row], left, right)
+}
+
+// cutLine returns the columns of the line from left up to right, as ansi.Cut
+// does, using the line's index if it has one.
+func cutLine(s string, c *lineCut, left, right int) string {
+	if right <= left {
+		return ""
+	}
+	if c == nil {
+		return s[min(left, len(s)):min(right, len(s))]
+	}
+
+	// Segments end in column order, so the first one ending past a column is
+	// the character covering it.
+	endsAfter := func(col int) int {
+		return sort.Search(len(c.segments), func(i int) bool {
+			s := c.segments[i]
+			return s.col+s.width > col
+		})
+	}
+	stop := len(c.segments)
+	if c.width > right {
+		stop = endsAfter(right)
+	}
+	first := 0
+	if left > 0 {
+		first = min(endsAfter(left), stop)
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	before := sort.SearchInts(c.escapes, first)
+	for _, e := range c.escapes[:before] {
+		b.WriteString(s[c.segments[e].start:c.segments[e].end])
+	}
+	if first < stop {
+		b.WriteString(s[c.segments[first].start:c.segments[stop-1].end])
+	}
+	for _, e := range c.escapes[sort.SearchInts(c.escapes, stop):] {
+		b.WriteString(s[c.segments[e].start:c.segments[e].end])
+	}
+	return b.String()
+}
@@ -0,0 +1,156 @@
+package viewport
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Fold is a range of lines which can be collapsed into a single summary line,
+// such as a function body in a source viewer or the details of a log entry.
+// Start and End are the indexes of the first and last lines of the range in
+// the content, inclusive. The whole range is replaced by the summary, so to
+// keep a heading line visible, start the fold on the line after it.
+type Fold struct {
+	Start, End int
+
+	// Folded is whether the range is collapsed.
+	Folded bool
+}
+
+// Len returns the number of lines in the fold.
+func (f Fold) Len() int {
+	return f.End - f.Start + 1
+}
+
+func (f Fold) contains(line int) bool {
+	return line >= f.Start && line <= f.End
+}
+
+// DefaultFoldSummary returns the summary shown for folded lines, such as
+// "▸ 42 lines folded".
+func DefaultFoldSummary(lines []string) string {
+	if len(lines) == 1 {
+		return "▸ 1 line folded"
+	}
+	return fmt.Sprintf("▸ %d lines folded", len(lines))
+}
+
+// SetFolds sets the ranges of lines that can be folded, replacing any set
+// before, and enables the keybindings that fold them. Ranges may be nested,
+// but shouldn't otherwise overlap. Ranges outside the content are ignored.
+func (m *Model) SetFolds(folds []Fold) {
+	m.folds = slices.Clone(folds)
+	m.KeyMap.ToggleFold.SetEnabled(len(folds) > 0)
+	m.KeyMap.ToggleAllFolds.SetEnabled(len(folds) > 0)
+	m.refold()
+}
+
+// Folds returns the foldable ranges and whether each is folded.
+func (m Model) Folds() []Fold {
+	return slices.Clone(m.folds)
+}
+
+// SetFolded folds or unfolds the innermost range containing the given line
+// of the content. It does nothing if no range contains the line.
+func (m *Model) SetFolded(line int, folded bool) {
+	if i := m.foldAt(line); i >= 0 {
+		m.folds[i].Folded = folded
+		m.refold()
+	}
+}
+
+// ToggleFold folds the innermost range containing the given line of the
+// content, or unfolds it if it's folded.
+func (m *Model) ToggleFold(line int) {
+	if i := m.foldAt(line); i >= 0 {
+		m.SetFolded(line, !m.folds[i].Folded)
+	}
+}
+
+// SetAllFolded folds or unfolds every range.
+func (m *Model) SetAllFolded(folded bool) {
+	for i := range m.folds {
+		m.folds[i].Folded = folded
+	}
+	m.refold()
+}
+
+// ContentLine returns the index in the content of the line shown at the given
+// row of the scrollable lines, such as YOffset for the top of the view. For
+// a fold's summary it returns the fold's first line.
+func (m Model) ContentLine(row int) int {
+	if m.lineIndex == nil || row < 0 || row >= len(m.lineIndex) {
+		return row
+	}
+	return m.lineIndex[row]
+}
+
+// foldAt returns the index of the innermost fold containing the given line,
+// or -1 if there's none.
+func (m Model) foldAt(line int) int {
+	best := -1
+	for i, f := range m.folds {
+		if f.contains(line) && (best < 0 || f.Len() < m.folds[best].Len()) {
+			best = i
+		}
+	}
+	return best
+}
+
+// currentFold returns the line of the fold the keybindings act on: the
+// innermost one containing the line at the top of the view, or else the first
+// one starting in view. It returns false if there's no such fold.
+func (m Model) currentFold() (line int, ok bool) {
+	top := m.ContentLine(m.YOffset)
+	if m.foldAt(top) >= 0 {
+		return top, true
+	}
+	first, bottom := -1, m.ContentLine(m.YOffset+m.Height-m.Style.GetVerticalFrameSize()-1)
+	for _, f := range m.folds {
+		if f.Start > top && f.Start <= bottom && (first < 0 || f.Start < first) {
+			first = f.Start
+		}
+	}
+	return first, first >= 0
+}
+
+// refold rebuilds the scrollable lines from the content, replacing folded
+// ranges with their summaries. The line at the top of the view stays there,
+// or the summary of the fold hiding it takes its place.
+func (m *Model) refold() {
+	if m.store != nil {
+		return
+	}
+	top, col := m.anchor()
+
+	summary := m.FoldSummaryFunc
+	if summary == nil {
+		summary = DefaultFoldSummary
+	}
+	var (
+		lines  []string
+		index  []int
+		folded bool
+	)
+	for i := 0; i < len(m.content); i++ {
+		end := -1
+		for _, f := range m.folds {
+			if f.Folded && f.Start == i && f.End > end && f.End < len(m.content) {
+				end = f.End
+			}
+		}
+		index = append(index, i)
+		if end < 0 {
+			lines = append(lines, m.content[i])
+			continue
+		}
+		lines = append(lines, m.FoldStyle.Render(summary(m.content[i:end+1])))
+		folded = true
+		i = end
+	}
+	if !folded {
+		lines, index = m.content, nil
+	}
+	m.setBase(lines, index)
+	m.SetYOffset(m.rowOf(top, col))
+}
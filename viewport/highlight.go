@@ -0,0 +1,125 @@
+package viewport
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Range is a region of the content to highlight, such as a search result, a
+// lint warning or a diff hunk. Lines are indexes into the content and columns
+// are cells, not counting escape sequences. StartLine and EndLine are
+// inclusive, while EndCol is exclusive, so a range on a single line spans
+// EndCol-StartCol cells. A range spanning several lines covers the rest of
+// its first line and the start of its last one, and EndCol may be past the
+// end of the line to cover all of it.
+type Range struct {
+	StartLine, StartCol int
+	EndLine, EndCol     int
+
+	// Style is applied to the text in the range, in place of any styling it
+	// had in the content.
+	Style lipgloss.Style
+}
+
+// SetHighlights sets the regions highlighted over the content, replacing any
+// set before. They're applied when the view is rendered, so the content is
+// left as is. Ranges shouldn't overlap, but where they do, the one starting
+// first is drawn over the other.
+func (m *Model) SetHighlights(ranges []Range) {
+	m.highlights = slices.Clone(ranges)
+}
+
+// Highlights returns the highlighted regions.
+func (m Model) Highlights() []Range {
+	return slices.Clone(m.highlights)
+}
+
+// ClearHighlights removes all highlights.
+func (m *Model) ClearHighlights() {
+	m.highlights = nil
+}
+
+// highlightLines applies the highlights to the lines to scroll through that
+// start at the given index. Fold summaries aren't highlighted.
+func (m Model) highlightLines(top int, lines []string) []string {
+	if len(m.highlights) == 0 && m.focusedLink < 0 {
+		return lines
+	}
+
+	highlighted := make([]string, len(lines))
+	for i, l := range lines {
+		highlighted[i] = l
+		if m.isFoldSummary(top + i) {
+			continue
+		}
+		highlighted[i] = m.highlightLine(m.ContentLine(top+i), m.rowCol(top+i), l)
+	}
+	return highlighted
+}
+
+// highlightLine applies the highlights on a line of the content, or on the
+// part of it starting at column col when it wraps.
+func (m Model) highlightLine(index, col int, line string) string {
+	width := ansi.StringWidth(line)
+
+	highlights := m.highlights
+	if l, ok := m.FocusedLink(); ok {
+		highlights = append(highlights[:len(highlights):len(highlights)], Range{
+			StartLine: l.Line, StartCol: l.StartCol,
+			EndLine: l.Line, EndCol: l.EndCol,
+			Style: m.LinkStyle,
+		})
+	}
+
+	var ranges []lipgloss.Range
+	for _, h := range highlights {
+		if index < h.StartLine || index > h.EndLine {
+			continue
+		}
+		start, end := 0, width
+		if index == h.StartLine {
+			start = h.StartCol - col
+		}
+		if index == h.EndLine {
+			end = h.EndCol - col
+		}
+		start, end = clamp(start, 0, width), clamp(end, 0, width)
+		if start < end {
+			ranges = append(ranges, lipgloss.NewRange(start, end, h.Style.Inline(true)))
+		}
+	}
+	if len(ranges) == 0 {
+		return line
+	}
+
+	// lipgloss.StyleRanges expects ranges in order and without overlaps.
+	slices.SortStableFunc(ranges, func(a, b lipgloss.Range) int {
+		return a.Start - b.Start
+	})
+	clipped := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := clipped[len(clipped)-1]
+		r.Start = max(r.Start, last.End)
+		if r.Start < r.End {
+			clipped = append(clipped, r)
+		}
+	}
+	return lipgloss.StyleRanges(line, clipped...)
+}
+
+// isFoldSummary reports whether the line to scroll through at the given index
+// is the summary of a folded range.
+func (m Model) isFoldSummary(row int) bool {
+	if m.lineIndex == nil || row < 0 || row >= len(m.lineIndex) {
+		return false
+	}
+	line := m.lineIndex[row]
+	for _, f := range m.folds {
+		if f.Folded && f.Start == line && f.End < len(m.content) {
+			return true
+		}
+	}
+	return false
+}
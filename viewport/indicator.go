@@ -0,0 +1,112 @@
+package viewport
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mikeflynn/bubbles/textutil"
+)
+
+// Corner is a corner of the viewport.
+type Corner int
+
+// Corners. NoCorner hides the position indicator.
+const (
+	NoCorner Corner = iota
+	TopLeft
+	TopRight
+	BottomLeft
+	BottomRight
+)
+
+// PositionInfo describes the part of the content in view.
+type PositionInfo struct {
+	// TopLine and BottomLine are the 1-based numbers of the first and last
+	// lines in view, or zero if there are none. TotalLines is the number of
+	// lines to scroll through. While ranges are folded, a folded range
+	// counts as one line.
+	TopLine    int
+	BottomLine int
+	TotalLines int
+
+	// Percent is the amount scrolled, from 0 to 1. See ScrollPercent.
+	Percent float64
+
+	// ClippedLeft and ClippedRight report whether lines in view are cut off
+	// by the horizontal scroll position. See Clipped.
+	ClippedLeft  bool
+	ClippedRight bool
+
+	// Rewrapping reports whether the content is being wrapped to a new width
+	// in the background, and RewrapPercent how much of it is done, from 0 to
+	// 1. See SetSoftWrap.
+	Rewrapping    bool
+	RewrapPercent float64
+}
+
+// PositionInfo returns the part of the content in view, such as for a pager
+// to show in its status line.
+func (m Model) PositionInfo() PositionInfo {
+	top, bottom := m.visibleRange()
+	info := PositionInfo{
+		TotalLines: m.lineCount(),
+		Percent:    m.ScrollPercent(),
+	}
+	if bottom > top {
+		info.TopLine, info.BottomLine = top+1, bottom
+	}
+	info.ClippedLeft, info.ClippedRight = m.Clipped()
+	info.RewrapPercent, info.Rewrapping = m.Rewrapping()
+	return info
+}
+
+// DefaultIndicator formats the position indicator as the percentage scrolled
+// and the number of the last line in view out of the total, such as
+// "45% ┃ 120/4532", or while the content is being wrapped again, the
+// progress, such as "rewrapping… 45%".
+func DefaultIndicator(info PositionInfo) string {
+	if info.Rewrapping {
+		return fmt.Sprintf("rewrapping… %d%%", int(math.Round(info.RewrapPercent*100))) //nolint:mnd
+	}
+	return fmt.Sprintf("%d%% ┃ %d/%d", int(math.Round(info.Percent*100)), info.BottomLine, info.TotalLines) //nolint:mnd
+}
+
+// overlayIndicator draws the position indicator over the given corner of the
+// visible lines, which fill a view of the given size.
+func (m Model) overlayIndicator(lines []string, width, height int) []string {
+	if m.IndicatorCorner == NoCorner || width <= 0 || height <= 0 {
+		return lines
+	}
+
+	format := m.IndicatorFunc
+	if format == nil {
+		format = DefaultIndicator
+	}
+	text := m.IndicatorStyle.Render(format(m.PositionInfo()))
+	if text == "" {
+		return lines
+	}
+	text = textutil.Truncate(text, width, "")
+	textWidth := textutil.Width(text)
+
+	row := 0
+	if m.IndicatorCorner == BottomLeft || m.IndicatorCorner == BottomRight {
+		row = height - 1
+	}
+	if row >= len(lines) {
+		padded := make([]string, row+1)
+		copy(padded, lines)
+		lines = padded
+	} else {
+		lines = append([]string(nil), lines...)
+	}
+
+	line := textutil.PadRight(lines[row], width)
+	if m.IndicatorCorner == TopLeft || m.IndicatorCorner == BottomLeft {
+		lines[row] = text + ansi.Cut(line, textWidth, width)
+	} else {
+		lines[row] = textutil.Truncate(line, width-textWidth, "") + text
+	}
+	return lines
+}
@@ -19,6 +19,18 @@ type KeyMap struct {
 	Up           key.Binding
 	Left         key.Binding
 	Right        key.Binding
+	LineStart    key.Binding
+	LineEnd      key.Binding
+
+	// Fold and unfold ranges of lines. These are only enabled by SetFolds.
+	ToggleFold     key.Binding
+	ToggleAllFolds key.Binding
+
+	// Move between OSC 8 hyperlinks in the content and open the focused
+	// one. These are only enabled while the content has links.
+	NextLink key.Binding
+	PrevLink key.Binding
+	OpenLink key.Binding
 }
 
 // DefaultKeyMap returns a set of pager-like default keybindings.
@@ -56,5 +68,38 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("right", "l"),
 			key.WithHelp("→/l", "move right"),
 		),
+		LineStart: key.NewBinding(
+			key.WithKeys("home"),
+			key.WithHelp("home", "line start"),
+		),
+		LineEnd: key.NewBinding(
+			key.WithKeys("end"),
+			key.WithHelp("end", "line end"),
+		),
+		ToggleFold: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "toggle fold"),
+			key.WithDisabled(),
+		),
+		ToggleAllFolds: key.NewBinding(
+			key.WithKeys("Z"),
+			key.WithHelp("Z", "toggle all folds"),
+			key.WithDisabled(),
+		),
+		NextLink: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "next link"),
+			key.WithDisabled(),
+		),
+		PrevLink: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "prev link"),
+			key.WithDisabled(),
+		),
+		OpenLink: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "open link"),
+			key.WithDisabled(),
+		),
 	}
 }
@@ -0,0 +1,202 @@
+package viewport
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Link is an OSC 8 hyperlink in the content. Line is an index into the
+// content and columns are cells, not counting escape sequences, with EndCol
+// exclusive, as for a Range.
+type Link struct {
+	Line             int
+	StartCol, EndCol int
+	URL              string
+
+	// Text is the link's text, without styling.
+	Text string
+}
+
+// OpenLinkMsg is sent when the user opens the focused link, such as for a
+// documentation browser to follow it.
+type OpenLinkMsg struct {
+	Link Link
+}
+
+// Links returns the OSC 8 hyperlinks in the content, in order. Links aren't
+// found in content set with SetLargeContent.
+func (m Model) Links() []Link {
+	return m.links
+}
+
+// FocusedLink returns the link focused with NextLink and PrevLink. It returns
+// false if no link is focused.
+func (m Model) FocusedLink() (Link, bool) {
+	if m.focusedLink < 0 || m.focusedLink >= len(m.links) {
+		return Link{}, false
+	}
+	return m.links[m.focusedLink], true
+}
+
+// NextLink focuses the link after the focused one, or if none is focused,
+// the first link in view or below it, scrolling it into view. Links inside
+// folded ranges are skipped. It wraps around to the first link.
+func (m *Model) NextLink() {
+	m.stepLink(1)
+}
+
+// PrevLink focuses the link before the focused one, or if none is focused,
+// the last link in view or above it, scrolling it into view. It wraps around
+// to the last link.
+func (m *Model) PrevLink() {
+	m.stepLink(-1)
+}
+
+// ClearLinkFocus unfocuses the focused link, if any.
+func (m *Model) ClearLinkFocus() {
+	m.focusedLink = -1
+}
+
+// stepLink focuses the next visible link in the given direction.
+func (m *Model) stepLink(dir int) {
+	n := len(m.links)
+	if n == 0 {
+		return
+	}
+
+	start := m.focusedLink
+	if start < 0 {
+		// Start from the top or bottom of the view, so that the first link
+		// focused is one the user can see.
+		start = -1
+		if dir < 0 {
+			start = n
+		}
+		top, bottom := m.ContentLine(m.YOffset), m.ContentLine(m.YOffset+m.Height-m.Style.GetVerticalFrameSize()-1)
+		for i, l := range m.links {
+			if dir > 0 && l.Line < top {
+				start = i
+			}
+			if dir < 0 && l.Line > bottom {
+				start = i
+				break
+			}
+		}
+	}
+
+	for step := 1; step <= n; step++ {
+		i := ((start+dir*step)%n + n) % n
+		if row, ok := m.linkRow(m.links[i]); ok {
+			m.focusedLink = i
+			m.scrollToLink(row, m.links[i])
+			return
+		}
+	}
+}
+
+// linkRow returns the row of the scrollable lines showing the start of a
+// link. It returns false if the line is hidden in a folded range.
+func (m Model) linkRow(l Link) (int, bool) {
+	if m.lineIndex == nil {
+		return l.Line, l.Line < m.lineCount()
+	}
+	row := m.rowOf(l.Line, l.StartCol)
+	return row, m.ContentLine(row) == l.Line && !m.isFoldSummary(row)
+}
+
+// scrollToLink scrolls the link on the given row into view.
+func (m *Model) scrollToLink(row int, l Link) {
+	h := m.Height - m.Style.GetVerticalFrameSize()
+	if row < m.YOffset {
+		m.SetYOffset(row)
+	} else if row >= m.YOffset+h {
+		m.SetYOffset(row - h + 1)
+	}
+
+	w := m.Width - m.Style.GetHorizontalFrameSize()
+	if w > 0 && (l.StartCol < m.xOffset || l.EndCol > m.xOffset+w) {
+		m.SetXOffset(l.StartCol)
+	}
+}
+
+// openLink returns a command sending an OpenLinkMsg for the focused link.
+func (m Model) openLink() tea.Cmd {
+	l, ok := m.FocusedLink()
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		return OpenLinkMsg{Link: l}
+	}
+}
+
+// updateLinks finds the links in the content, unfocusing any focused link.
+func (m *Model) updateLinks() {
+	m.links = nil
+	m.focusedLink = -1
+	for i, line := range m.content {
+		if strings.Contains(line, "\x1b]8;") {
+			m.links = append(m.links, findLinks(i, line)...)
+		}
+	}
+	m.KeyMap.NextLink.SetEnabled(len(m.links) > 0)
+	m.KeyMap.PrevLink.SetEnabled(len(m.links) > 0)
+	m.KeyMap.OpenLink.SetEnabled(len(m.links) > 0)
+}
+
+// findLinks returns the OSC 8 hyperlinks on a line of the content.
+func findLinks(index int, line string) []Link {
+	var (
+		links []Link
+		open  *Link
+		text  strings.Builder
+		col   int
+		state byte
+	)
+	closeLink := func() {
+		if open != nil && col > open.StartCol {
+			open.EndCol, open.Text = col, text.String()
+			links = append(links, *open)
+		}
+		open = nil
+		text.Reset()
+	}
+
+	for len(line) > 0 {
+		seq, width, n, newState := ansi.DecodeSequence(line, state, nil)
+		state = newState
+		line = line[n:]
+		if width > 0 {
+			col += width
+			if open != nil {
+				text.WriteString(seq)
+			}
+			continue
+		}
+
+		url, ok := hyperlinkURL(seq)
+		if !ok {
+			continue
+		}
+		closeLink()
+		if url != "" {
+			open = &Link{Line: index, StartCol: col, URL: url}
+		}
+	}
+	closeLink()
+	return links
+}
+
+// hyperlinkURL returns the URL of an OSC 8 sequence, which is empty for the
+// sequence ending a link. It returns false for other sequences.
+func hyperlinkURL(seq string) (string, bool) {
+	body, ok := strings.CutPrefix(seq, "\x1b]8;")
+	if !ok {
+		return "", false
+	}
+	body = strings.TrimSuffix(strings.TrimSuffix(body, "\a"), "\x1b\\")
+	_, url, ok := strings.Cut(body, ";")
+	return url, ok
+}
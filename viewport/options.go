@@ -0,0 +1,108 @@
+package viewport
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Option is used to set options in NewWithOptions. For example:
+//
+//	vp, err := NewWithOptions(
+//		WithWidth(80),
+//		WithHeight(20),
+//		WithContent(text),
+//	)
+type Option func(*Model)
+
+// WithWidth sets the width of the viewport.
+func WithWidth(w int) Option {
+	return func(m *Model) {
+		m.Width = w
+	}
+}
+
+// WithHeight sets the height of the viewport.
+func WithHeight(h int) Option {
+	return func(m *Model) {
+		m.Height = h
+	}
+}
+
+// WithKeyMap sets the keymap of the viewport.
+func WithKeyMap(km KeyMap) Option {
+	return func(m *Model) {
+		m.KeyMap = km
+	}
+}
+
+// WithStyle sets the style applied to the viewport.
+func WithStyle(s lipgloss.Style) Option {
+	return func(m *Model) {
+		m.Style = s
+	}
+}
+
+// WithMouseWheelDelta sets the number of lines the mouse wheel scrolls.
+func WithMouseWheelDelta(n int) Option {
+	return func(m *Model) {
+		m.MouseWheelDelta = n
+	}
+}
+
+// WithSoftWrap sets whether lines wrap. See SetSoftWrap.
+func WithSoftWrap(v bool) Option {
+	return func(m *Model) {
+		m.SetSoftWrap(v)
+	}
+}
+
+// WithContent sets the content of the viewport.
+func WithContent(s string) Option {
+	return func(m *Model) {
+		m.SetContent(s)
+	}
+}
+
+// WithIndicator draws the position indicator over the given corner.
+func WithIndicator(c Corner) Option {
+	return func(m *Model) {
+		m.IndicatorCorner = c
+	}
+}
+
+// NewWithOptions returns a new viewport configured by the given options.
+// Unlike New it checks the configuration up front, returning an error if it
+// can't be rendered, such as a size smaller than the style's frame.
+func NewWithOptions(opts ...Option) (Model, error) {
+	m := New(0, 0)
+	for _, opt := range opts {
+		opt(&m)
+	}
+	if m.softWrap {
+		// Wrap to the width set, whichever order the options came in.
+		m.layout()
+	}
+
+	if err := m.validate(); err != nil {
+		return Model{}, err
+	}
+	return m, nil
+}
+
+// validate returns an error if the viewport's configuration can't be
+// rendered.
+func (m Model) validate() error {
+	if m.Width < 0 || m.Height < 0 {
+		return fmt.Errorf("viewport: invalid size %dx%d", m.Width, m.Height)
+	}
+	if m.MouseWheelDelta < 0 {
+		return fmt.Errorf("viewport: invalid mouse wheel delta %d", m.MouseWheelDelta)
+	}
+	fw, fh := m.Style.GetFrameSize()
+	if (m.Width > 0 && m.Width < fw) || (m.Height > 0 && m.Height < fh) {
+		return fmt.Errorf("viewport: size %dx%d is smaller than the style's frame %dx%d",
+			m.Width, m.Height, fw, fh)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package viewport
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// positionTokenVersion prefixes position tokens so that their format can
+// change without misreading old ones.
+const positionTokenVersion = "v1"
+
+// PositionToken returns an opaque token describing the current scroll
+// position, which RestorePosition can later use to return to it, such as when
+// a pager reopens a document. The token identifies the content by a hash and
+// anchors the position to the first non-blank line in view, so that it can
+// be restored even after small edits to the content.
+func (m Model) PositionToken() string {
+	delta, anchor := 0, uint64(0)
+	for i, line := range m.allLines(m.YOffset) {
+		if strings.TrimSpace(ansi.Strip(line)) != "" {
+			delta, anchor = i-m.YOffset, hashLine(line)
+			break
+		}
+	}
+	return strings.Join([]string{
+		positionTokenVersion,
+		strconv.FormatUint(m.contentHash(), 16), //nolint:mnd
+		strconv.Itoa(m.YOffset),
+		strconv.Itoa(m.xOffset),
+		strconv.FormatUint(anchor, 16), //nolint:mnd
+		strconv.Itoa(delta),
+	}, ":")
+}
+
+// RestorePosition scrolls to the position described by a token from
+// PositionToken. If the content is unchanged the position is restored
+// exactly. Otherwise the viewport is scrolled to the occurrence of the
+// anchoring line nearest to where it used to be, or to the old offset if the
+// line is gone. An error is returned only for malformed tokens.
+func (m *Model) RestorePosition(token string) error {
+	parts := strings.Split(token, ":")
+	if len(parts) != 6 || parts[0] != positionTokenVersion { //nolint:mnd
+		return fmt.Errorf("viewport: invalid position token %q", token)
+	}
+	content, err1 := strconv.ParseUint(parts[1], 16, 64) //nolint:mnd
+	y, err2 := strconv.Atoi(parts[2])
+	x, err3 := strconv.Atoi(parts[3])
+	anchor, err4 := strconv.ParseUint(parts[4], 16, 64) //nolint:mnd
+	delta, err5 := strconv.Atoi(parts[5])
+	for _, err := range []error{err1, err2, err3, err4, err5} {
+		if err != nil {
+			return fmt.Errorf("viewport: invalid position token %q: %w", token, err)
+		}
+	}
+
+	m.SetXOffset(x)
+	if content == m.contentHash() || anchor == 0 {
+		m.SetYOffset(y)
+		return nil
+	}
+
+	best := -1
+	for i, line := range m.allLines(0) {
+		if hashLine(line) != anchor {
+			continue
+		}
+		if best < 0 || abs(i-(y+delta)) < abs(best-(y+delta)) {
+			best = i
+		}
+	}
+	if best < 0 {
+		m.SetYOffset(y)
+		return nil
+	}
+	m.SetYOffset(best - delta)
+	return nil
+}
+
+func (m Model) contentHash() uint64 {
+	h := fnv.New64a()
+	for i, line := range m.allLines(0) {
+		if i > 0 {
+			_, _ = h.Write([]byte{'\n'})
+		}
+		_, _ = h.Write([]byte(line))
+	}
+	return h.Sum64()
+}
+
+// hashLine hashes a line's text, ignoring styling and surrounding whitespace
+// so that restyled or reindented lines still match.
+func hashLine(line string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strings.TrimSpace(ansi.Strip(line))))
+	return h.Sum64()
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,154 @@
+package viewport
+
+import (
+	"iter"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// storeChunk is the number of lines between the offsets a lineStore indexes.
+const storeChunk = 1024
+
+// lineStore holds content set with SetLargeContent. It keeps the text as it
+// was given, rather than a string per line, and indexes the offset of every
+// storeChunk-th line as lines are first reached. Lines are sliced out of the
+// text only as they're shown. It's shared between copies of the model, which
+// is safe since the text never changes.
+type lineStore struct {
+	text  string
+	count int
+
+	// chunks holds the offset of the first line of each chunk indexed so
+	// far, in order from the start.
+	chunks []int
+
+	// widest is the width of the widest line shown so far.
+	widest int
+}
+
+func newLineStore(text string) *lineStore {
+	return &lineStore{
+		text:   text,
+		count:  strings.Count(text, "\n") + 1,
+		chunks: []int{0},
+	}
+}
+
+// start returns the offset of the line at index i. Lines in the last chunk
+// are found from the end of the text, so that the bottom of the content can
+// be shown without indexing everything before it.
+func (s *lineStore) start(i int) int {
+	if i >= s.count-storeChunk && i/storeChunk >= len(s.chunks) {
+		off := len(s.text)
+		for n := s.count - i; n > 0; n-- {
+			off = strings.LastIndexByte(s.text[:off], '\n')
+		}
+		return off + 1
+	}
+
+	for len(s.chunks) <= i/storeChunk {
+		off := s.chunks[len(s.chunks)-1]
+		for range storeChunk {
+			off += strings.IndexByte(s.text[off:], '\n') + 1
+		}
+		s.chunks = append(s.chunks, off)
+	}
+	off := s.chunks[i/storeChunk]
+	for range i % storeChunk {
+		off += strings.IndexByte(s.text[off:], '\n') + 1
+	}
+	return off
+}
+
+// all returns the lines from index from onwards, scanning the text once.
+func (s *lineStore) all(from int) iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		if from < 0 || from >= s.count {
+			return
+		}
+		off := s.start(from)
+		for i := from; i < s.count; i++ {
+			end := strings.IndexByte(s.text[off:], '\n')
+			if end < 0 {
+				end = len(s.text) - off
+			}
+			if !yield(i, strings.TrimSuffix(s.text[off:off+end], "\r")) {
+				return
+			}
+			off += end + 1
+		}
+	}
+}
+
+// lines returns the lines from index top up to bottom, noting the widest.
+func (s *lineStore) lines(top, bottom int) []string {
+	lines := make([]string, 0, max(0, bottom-top))
+	for i, l := range s.all(top) {
+		if i >= bottom {
+			break
+		}
+		lines = append(lines, l)
+		s.widest = max(s.widest, ansi.StringWidth(l))
+	}
+	return lines
+}
+
+// SetLargeContent sets the pager's text content like SetContent, for content
+// too large to split into lines up front, such as logs hundreds of megabytes
+// long. The text is kept as it's given, without copying it or holding a
+// string per line, and lines are found as they're scrolled to, so that
+// jumping to the bottom doesn't read the whole text.
+//
+// Since the content isn't measured up front, horizontal scrolling reaches as
+// far as the widest line shown so far. Folds don't apply to large content.
+func (m *Model) SetLargeContent(s string) {
+	m.store = newLineStore(s)
+	m.content, m.lines, m.lineIndex, m.cuts = nil, nil, nil, nil
+	m.base, m.baseIndex, m.rowStart, m.wrapWidth, m.wrapJob = nil, nil, nil, 0, nil
+	m.updateLinks()
+	m.longestLineWidth = 0
+
+	if m.YOffset > m.lineCount()-1 {
+		m.GotoBottom()
+	}
+}
+
+// lineCount returns the number of lines to scroll through.
+func (m Model) lineCount() int {
+	if m.store != nil {
+		return m.store.count
+	}
+	return len(m.lines)
+}
+
+// lineRange returns the lines to scroll through from index top up to bottom.
+func (m Model) lineRange(top, bottom int) []string {
+	if m.store != nil {
+		return m.store.lines(top, bottom)
+	}
+	return m.lines[top:bottom]
+}
+
+// allLines returns the lines to scroll through from index from onwards.
+func (m Model) allLines(from int) iter.Seq2[int, string] {
+	if m.store != nil {
+		return m.store.all(from)
+	}
+	return func(yield func(int, string) bool) {
+		for i := max(0, from); i < len(m.lines); i++ {
+			if !yield(i, m.lines[i]) {
+				return
+			}
+		}
+	}
+}
+
+// widestLine returns the width of the widest line, or for large content of
+// the widest line shown so far.
+func (m Model) widestLine() int {
+	if m.store != nil {
+		return m.store.widest
+	}
+	return m.longestLineWidth
+}
@@ -0,0 +1,88 @@
+package viewport
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// LineMapper converts a viewport's line offsets to and from a position
+// shared by the viewports in a SyncGroup, such as the row of an aligned diff
+// or the index of a paragraph in a translation. Nil functions leave offsets
+// as they are.
+type LineMapper struct {
+	ToShared   func(offset int) int
+	FromShared func(pos int) int
+}
+
+// SyncGroup keeps viewports shown side by side scrolled together, such as the
+// panes of a diff viewer or the source and translation in a translation tool.
+// It doesn't hold the viewports, which stay in the app's model, and is given
+// pointers to them instead, always in the same order:
+//
+//	m.sync.Update(m.focused, msg, &m.left, &m.right)
+type SyncGroup struct {
+	// Mappers map the line offsets of the viewports, by their position in
+	// the arguments to Update and Sync, to a shared position and back. If a
+	// viewport has no mapper, its offsets are shared as they are.
+	Mappers []LineMapper
+
+	// Horizontal keeps the horizontal scroll positions together too, column
+	// for column.
+	Horizontal bool
+}
+
+// NewSyncGroup returns a group mapping the line offsets of its viewports with
+// the given mappers, which keeps the horizontal scroll positions together
+// too.
+func NewSyncGroup(mappers ...LineMapper) SyncGroup {
+	return SyncGroup{Mappers: mappers, Horizontal: true}
+}
+
+// Update passes msg to the viewport at index source, such as the focused one,
+// then scrolls the others to follow it.
+func (g SyncGroup) Update(source int, msg tea.Msg, viewports ...*Model) tea.Cmd {
+	if source < 0 || source >= len(viewports) {
+		return nil
+	}
+	var cmd tea.Cmd
+	*viewports[source], cmd = viewports[source].Update(msg)
+	g.Sync(source, viewports...)
+	return cmd
+}
+
+// Sync scrolls the viewports to match the one at index source, such as after
+// scrolling it directly with SetYOffset or GotoBottom.
+func (g SyncGroup) Sync(source int, viewports ...*Model) {
+	if source < 0 || source >= len(viewports) {
+		return
+	}
+	from := viewports[source]
+	shared := g.mapper(source).toShared(from.YOffset)
+	for i, v := range viewports {
+		if i == source {
+			continue
+		}
+		v.SetYOffset(g.mapper(i).fromShared(shared))
+		if g.Horizontal {
+			v.SetXOffset(from.xOffset)
+		}
+	}
+}
+
+func (g SyncGroup) mapper(i int) LineMapper {
+	if i < len(g.Mappers) {
+		return g.Mappers[i]
+	}
+	return LineMapper{}
+}
+
+func (l LineMapper) toShared(offset int) int {
+	if l.ToShared == nil {
+		return offset
+	}
+	return l.ToShared(offset)
+}
+
+func (l LineMapper) fromShared(pos int) int {
+	if l.FromShared == nil {
+		return pos
+	}
+	return l.FromShared(pos)
+}
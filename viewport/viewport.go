@@ -8,8 +8,13 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
 )
 
+// defaultHorizontalStep is the number of columns the default key map scrolls
+// left or right.
+const defaultHorizontalStep = 6
+
 // New returns a new model with the given width and height as well as default
 // key mappings.
 func New(width, height int) (m Model) {
@@ -50,6 +55,31 @@ type Model struct {
 	// useful for setting borders, margins and padding.
 	Style lipgloss.Style
 
+	// LineStyleFunc, if set, is called at render time for each visible line
+	// with the line's index in the content and its unstyled text. The
+	// returned style is applied to the line, padded to the viewport's width.
+	// This allows striping rows, highlighting a current line or coloring diff
+	// lines without baking ANSI sequences into the content.
+	LineStyleFunc func(lineIndex int, line string) lipgloss.Style
+
+	// FoldSummaryFunc returns the line shown in place of a folded range,
+	// given the range's lines. It defaults to DefaultFoldSummary. See
+	// SetFolds.
+	FoldSummaryFunc func(lines []string) string
+
+	// FoldStyle is applied to the summaries of folded ranges.
+	FoldStyle lipgloss.Style
+
+	// IndicatorCorner is the corner the position indicator is drawn over,
+	// or NoCorner to hide it. IndicatorFunc formats the indicator, and
+	// defaults to DefaultIndicator. IndicatorStyle is applied to it.
+	IndicatorCorner Corner
+	IndicatorFunc   func(PositionInfo) string
+	IndicatorStyle  lipgloss.Style
+
+	// LinkStyle is applied to the focused hyperlink. See NextLink.
+	LinkStyle lipgloss.Style
+
 	// HighPerformanceRendering bypasses the normal Bubble Tea renderer to
 	// provide higher performance rendering. Most of the time the normal Bubble
 	// Tea rendering methods will suffice, but if you're passing content with
@@ -65,12 +95,56 @@ type Model struct {
 	initialized      bool
 	lines            []string
 	longestLineWidth int
+
+	// cuts indexes the escape sequences and characters of each of lines,
+	// or is nil for lines of plain text, so that lines can be cut for
+	// horizontal scrolling without being parsed again. See lineCut.
+	cuts []*lineCut
+
+	// content holds the lines as set by SetContent. lines holds the lines
+	// to scroll through, which differ from the content while ranges are
+	// folded or lines wrap, and lineIndex then maps each of them to its
+	// content line. base and baseIndex hold the lines before wrapping.
+	content   []string
+	lineIndex []int
+	folds     []Fold
+	base      []string
+	baseIndex []int
+
+	// While lines wrap, rowStart holds the column of its content line each
+	// row starts at, wrapWidth the width they were wrapped to, and wrapJob
+	// the wrapping in progress in the background, if any.
+	softWrap  bool
+	rowStart  []int
+	wrapWidth int
+	wrapJob   *wrapJob
+
+	// highlights are the regions of the content highlighted when rendering.
+	highlights []Range
+
+	// links are the hyperlinks in the content, and focusedLink the index
+	// of the focused one, or -1.
+	links       []Link
+	focusedLink int
+
+	// store holds the content in place of lines and content when it's set
+	// with SetLargeContent.
+	store *lineStore
+
+	// anchorBottom keeps the scroll position from the bottom of the content
+	// when it changes. See SetAnchorBottom.
+	anchorBottom bool
 }
 
 func (m *Model) setInitialValues() {
 	m.KeyMap = DefaultKeyMap()
 	m.MouseWheelEnabled = true
 	m.MouseWheelDelta = 3
+	m.horizontalStep = defaultHorizontalStep
+	m.FoldStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"})
+	m.IndicatorStyle = lipgloss.NewStyle().Reverse(true)
+	m.LinkStyle = lipgloss.NewStyle().Reverse(true)
+	m.focusedLink = -1
 	m.initialized = true
 }
 
@@ -98,12 +172,12 @@ func (m Model) PastBottom() bool {
 
 // ScrollPercent returns the amount scrolled as a float between 0 and 1.
 func (m Model) ScrollPercent() float64 {
-	if m.Height >= len(m.lines) {
+	if m.Height >= m.lineCount() {
 		return 1.0
 	}
 	y := float64(m.YOffset)
 	h := float64(m.Height)
-	t := float64(len(m.lines))
+	t := float64(m.lineCount())
 	v := y / (t - h)
 	return math.Max(0.0, math.Min(1.0, v))
 }
@@ -111,12 +185,12 @@ func (m Model) ScrollPercent() float64 {
 // HorizontalScrollPercent returns the amount horizontally scrolled as a float
 // between 0 and 1.
 func (m Model) HorizontalScrollPercent() float64 {
-	if m.xOffset >= m.longestLineWidth-m.Width {
+	if m.xOffset >= m.widestLine()-m.contentWidth() {
 		return 1.0
 	}
 	y := float64(m.xOffset)
-	h := float64(m.Width)
-	t := float64(m.longestLineWidth)
+	h := float64(m.contentWidth())
+	t := float64(m.widestLine())
 	v := y / (t - h)
 	return math.Max(0.0, math.Min(1.0, v))
 }
@@ -124,18 +198,33 @@ func (m Model) HorizontalScrollPercent() float64 {
 // SetContent set the pager's text content.
 func (m *Model) SetContent(s string) {
 	s = strings.ReplaceAll(s, "\r\n", "\n") // normalize line endings
-	m.lines = strings.Split(s, "\n")
-	m.longestLineWidth = findLongestLineWidth(m.lines)
+	prev, bottom := m.content, m.BottomOffset()
+	m.content = strings.Split(s, "\n")
+	m.store = nil
+	m.updateLinks()
+	if len(m.folds) > 0 {
+		m.refold()
+	} else {
+		wrapped := m.rowStart != nil
+		line, col := m.anchor()
+		m.setBase(m.content, nil)
+		if wrapped || m.rowStart != nil {
+			m.SetYOffset(m.rowOf(line, col))
+		}
+	}
 
-	if m.YOffset > len(m.lines)-1 {
+	if m.YOffset > m.lineCount()-1 {
 		m.GotoBottom()
 	}
+	if m.anchorBottom {
+		m.reanchor(prev, bottom)
+	}
 }
 
 // maxYOffset returns the maximum possible value of the y-offset based on the
 // viewport's content and set height.
 func (m Model) maxYOffset() int {
-	return max(0, len(m.lines)-m.Height+m.Style.GetVerticalFrameSize())
+	return max(0, m.lineCount()-m.Height+m.Style.GetVerticalFrameSize())
 }
 
 // visibleLines returns the lines that should currently be visible in the
@@ -144,21 +233,44 @@ func (m Model) visibleLines() (lines []string) {
 	h := m.Height - m.Style.GetVerticalFrameSize()
 	w := m.Width - m.Style.GetHorizontalFrameSize()
 
-	if len(m.lines) > 0 {
-		top := max(0, m.YOffset)
-		bottom := clamp(m.YOffset+h, top, len(m.lines))
-		lines = m.lines[top:bottom]
+	var (
+		top int
+		raw []string
+	)
+	if m.lineCount() > 0 {
+		top = max(0, m.YOffset)
+		bottom := clamp(m.YOffset+h, top, m.lineCount())
+		raw = m.lineRange(top, bottom)
+		lines = m.highlightLines(top, raw)
 	}
 
-	if (m.xOffset == 0 && m.longestLineWidth <= w) || w == 0 {
-		return lines
+	if (m.xOffset == 0 && m.widestLine() <= w) || w == 0 {
+		return m.styleLines(top, raw, lines, w)
 	}
 
 	cutLines := make([]string, len(lines))
 	for i := range lines {
-		cutLines[i] = ansi.Cut(lines[i], m.xOffset, m.xOffset+w)
+		cutLines[i] = m.cutLine(top+i, raw[i], lines[i], m.xOffset, m.xOffset+w)
 	}
-	return cutLines
+	return m.styleLines(top, raw, cutLines, w)
+}
+
+// styleLines applies LineStyleFunc to the visible lines, which start at the
+// given index in the content, given their unstyled text too. Lines are padded
+// to the given width first so that backgrounds span the whole row.
+func (m Model) styleLines(top int, raw, lines []string, width int) []string {
+	if m.LineStyleFunc == nil {
+		return lines
+	}
+
+	styled := make([]string, len(lines))
+	for i, l := range lines {
+		if pad := width - ansi.StringWidth(l); pad > 0 {
+			l += strings.Repeat(" ", pad)
+		}
+		styled[i] = m.LineStyleFunc(m.ContentLine(top+i), raw[i]).Render(l)
+	}
+	return styled
 }
 
 // scrollArea returns the scrollable boundaries for high performance rendering.
@@ -253,7 +365,7 @@ func (m *Model) LineDown(n int) (lines []string) {
 
 // ScrollDown moves the view down by the given number of lines.
 func (m *Model) ScrollDown(n int) (lines []string) {
-	if m.AtBottom() || n == 0 || len(m.lines) == 0 {
+	if m.AtBottom() || n == 0 || m.lineCount() == 0 {
 		return nil
 	}
 
@@ -265,9 +377,9 @@ func (m *Model) ScrollDown(n int) (lines []string) {
 	// Gather lines to send off for performance scrolling.
 	//
 	// XXX: high performance rendering is deprecated in Bubble Tea.
-	bottom := clamp(m.YOffset+m.Height, 0, len(m.lines))
+	bottom := clamp(m.YOffset+m.Height, 0, m.lineCount())
 	top := clamp(m.YOffset+m.Height-n, 0, bottom)
-	return m.lines[top:bottom]
+	return m.lineRange(top, bottom)
 }
 
 // LineUp moves the view down by the given number of lines. Returns the new
@@ -281,7 +393,7 @@ func (m *Model) LineUp(n int) (lines []string) {
 // ScrollUp moves the view down by the given number of lines. Returns the new
 // lines to show.
 func (m *Model) ScrollUp(n int) (lines []string) {
-	if m.AtTop() || n == 0 || len(m.lines) == 0 {
+	if m.AtTop() || n == 0 || m.lineCount() == 0 {
 		return nil
 	}
 
@@ -294,22 +406,32 @@ func (m *Model) ScrollUp(n int) (lines []string) {
 	// XXX: high performance rendering is deprecated in Bubble Tea.
 	top := max(0, m.YOffset)
 	bottom := clamp(m.YOffset+n, 0, m.maxYOffset())
-	return m.lines[top:bottom]
+	return m.lineRange(top, bottom)
 }
 
 // SetHorizontalStep sets the default amount of columns to scroll left or right
-// with the default viewport key map.
+// with the default viewport key map. It defaults to 6.
 //
 // If set to 0 or less, horizontal scrolling is disabled.
-//
-// On v1, horizontal scrolling is disabled by default.
 func (m *Model) SetHorizontalStep(n int) {
 	m.horizontalStep = max(n, 0)
 }
 
 // SetXOffset sets the X offset.
 func (m *Model) SetXOffset(n int) {
-	m.xOffset = clamp(n, 0, m.longestLineWidth-m.Width)
+	m.xOffset = clamp(n, 0, m.widestLine()-m.contentWidth())
+}
+
+// contentWidth returns the width left for content inside the viewport's
+// frame.
+func (m Model) contentWidth() int {
+	return m.Width - m.Style.GetHorizontalFrameSize()
+}
+
+// canScrollHorizontally returns whether horizontal scrolling is enabled and
+// some line is wider than the viewport.
+func (m Model) canScrollHorizontally() bool {
+	return m.horizontalStep > 0 && m.widestLine() > m.contentWidth()
 }
 
 // ScrollLeft moves the viewport to the left by the given number of columns.
@@ -322,9 +444,43 @@ func (m *Model) ScrollRight(n int) {
 	m.SetXOffset(m.xOffset + n)
 }
 
+// ScrollToStartOfLine scrolls the viewport all the way to the left.
+func (m *Model) ScrollToStartOfLine() {
+	m.SetXOffset(0)
+}
+
+// ScrollToEndOfLine scrolls the viewport right until the end of the widest
+// visible line lines up with its right edge. If the visible lines fit, it
+// scrolls all the way to the left.
+func (m *Model) ScrollToEndOfLine() {
+	top, bottom := m.visibleRange()
+	m.SetXOffset(findLongestLineWidth(m.lineRange(top, bottom)) - m.contentWidth())
+}
+
+// Clipped reports whether any visible line is cut off on the left or on the
+// right by the horizontal scroll position. It can be used to draw markers,
+// such as an ellipsis, at the edges of the viewport.
+func (m Model) Clipped() (left, right bool) {
+	w := m.contentWidth()
+	top, bottom := m.visibleRange()
+	for _, l := range m.lineRange(top, bottom) {
+		lw := ansi.StringWidth(l)
+		left = left || (m.xOffset > 0 && lw > 0)
+		right = right || lw > m.xOffset+w
+	}
+	return left, right
+}
+
+// visibleRange returns the range of content lines that are visible.
+func (m Model) visibleRange() (top, bottom int) {
+	h := m.Height - m.Style.GetVerticalFrameSize()
+	top = clamp(m.YOffset, 0, m.lineCount())
+	return top, clamp(m.YOffset+h, top, m.lineCount())
+}
+
 // TotalLineCount returns the total number of lines (both hidden and visible) within the viewport.
 func (m Model) TotalLineCount() int {
-	return len(m.lines)
+	return m.lineCount()
 }
 
 // VisibleLineCount returns the number of the visible lines within the viewport.
@@ -356,7 +512,7 @@ func (m *Model) GotoBottom() (lines []string) {
 //
 // Deprecated: high performance rendering is deprecated in Bubble Tea.
 func Sync(m Model) tea.Cmd {
-	if len(m.lines) == 0 {
+	if m.lineCount() == 0 {
 		return nil
 	}
 	top, bottom := m.scrollArea()
@@ -413,6 +569,7 @@ func (m Model) updateAsModel(msg tea.Msg) (Model, tea.Cmd) {
 	}
 
 	var cmd tea.Cmd
+	wrapCmd := m.updateWrap(msg)
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -453,11 +610,34 @@ func (m Model) updateAsModel(msg tea.Msg) (Model, tea.Cmd) {
 				cmd = ViewUp(m, lines)
 			}
 
-		case key.Matches(msg, m.KeyMap.Left):
+		case m.canScrollHorizontally() && key.Matches(msg, m.KeyMap.Left):
 			m.ScrollLeft(m.horizontalStep)
 
-		case key.Matches(msg, m.KeyMap.Right):
+		case m.canScrollHorizontally() && key.Matches(msg, m.KeyMap.Right):
 			m.ScrollRight(m.horizontalStep)
+
+		case key.Matches(msg, m.KeyMap.LineStart):
+			m.ScrollToStartOfLine()
+
+		case key.Matches(msg, m.KeyMap.LineEnd):
+			m.ScrollToEndOfLine()
+
+		case key.Matches(msg, m.KeyMap.ToggleFold):
+			if line, ok := m.currentFold(); ok {
+				m.ToggleFold(line)
+			}
+
+		case key.Matches(msg, m.KeyMap.ToggleAllFolds):
+			m.SetAllFolded(m.baseIndex == nil)
+
+		case key.Matches(msg, m.KeyMap.NextLink):
+			m.NextLink()
+
+		case key.Matches(msg, m.KeyMap.PrevLink):
+			m.PrevLink()
+
+		case key.Matches(msg, m.KeyMap.OpenLink):
+			cmd = m.openLink()
 		}
 
 	case tea.MouseMsg:
@@ -493,6 +673,9 @@ func (m Model) updateAsModel(msg tea.Msg) (Model, tea.Cmd) {
 		}
 	}
 
+	if wrapCmd != nil {
+		cmd = tea.Batch(cmd, wrapCmd)
+	}
 	return m, cmd
 }
 
@@ -520,12 +703,20 @@ func (m Model) View() string {
 		Height(contentHeight).    // pad to height.
 		MaxHeight(contentHeight). // truncate height if taller.
 		MaxWidth(contentWidth).   // truncate width if wider.
-		Render(strings.Join(m.visibleLines(), "\n"))
+		Render(strings.Join(m.overlayIndicator(m.visibleLines(), contentWidth, contentHeight), "\n"))
 	return m.Style.
 		UnsetWidth().UnsetHeight(). // Style size already applied in contents.
 		Render(contents)
 }
 
+// RenderString renders the viewport deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	m.Width = width
+	m.Height = height
+	return render.View(m.View, width, height)
+}
+
 func clamp(v, low, high int) int {
 	if high < low {
 		low, high = high, low
@@ -1,11 +1,17 @@
 package viewport
 
 import (
+	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
-)
 
-const defaultHorizontalStep = 6
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/golden"
+)
 
 func TestNew(t *testing.T) {
 	t.Parallel()
@@ -14,7 +20,6 @@ func TestNew(t *testing.T) {
 		t.Parallel()
 
 		m := New(10, 10)
-		m.horizontalStep = defaultHorizontalStep // remove on v2
 
 		if !m.initialized {
 			t.Errorf("on create by New, Model should be initialized")
@@ -41,7 +46,6 @@ func TestSetInitialValues(t *testing.T) {
 		t.Parallel()
 
 		m := Model{}
-		m.horizontalStep = defaultHorizontalStep // remove on v2
 		m.setInitialValues()
 
 		if m.horizontalStep != defaultHorizontalStep {
@@ -57,7 +61,6 @@ func TestSetHorizontalStep(t *testing.T) {
 		t.Parallel()
 
 		m := New(10, 10)
-		m.horizontalStep = defaultHorizontalStep // remove on v2
 
 		if m.horizontalStep != defaultHorizontalStep {
 			t.Errorf("default horizontalStep should be %d, got %d", defaultHorizontalStep, m.horizontalStep)
@@ -74,7 +77,6 @@ func TestSetHorizontalStep(t *testing.T) {
 		t.Parallel()
 
 		m := New(10, 10)
-		m.horizontalStep = defaultHorizontalStep // remove on v2
 
 		if m.horizontalStep != defaultHorizontalStep {
 			t.Errorf("default horizontalStep should be %d, got %d", defaultHorizontalStep, m.horizontalStep)
@@ -111,7 +113,6 @@ func TestScrollLeft(t *testing.T) {
 	t.Run("scroll", func(t *testing.T) {
 		t.Parallel()
 		m := New(10, 10)
-		m.horizontalStep = defaultHorizontalStep // remove on v2
 		m.longestLineWidth = 100
 		if m.xOffset != zeroPosition {
 			t.Errorf("default indent should be %d, got %d", zeroPosition, m.xOffset)
@@ -263,7 +264,6 @@ func TestVisibleLines(t *testing.T) {
 		numberOfLines := 10
 
 		m := New(10, numberOfLines)
-		m.horizontalStep = defaultHorizontalStep // remove on v2
 		m.SetContent(strings.Join(defaultList, "\n"))
 		m.SetYOffset(7)
 
@@ -392,3 +392,620 @@ func TestRightOverscroll(t *testing.T) {
 		}
 	})
 }
+
+func TestLineStyleFunc(t *testing.T) {
+	t.Parallel()
+
+	m := New(10, 2)
+	m.SetContent("+ added\n- removed\n  context")
+	m.SetYOffset(1)
+
+	var calls []string
+	m.LineStyleFunc = func(i int, line string) lipgloss.Style {
+		calls = append(calls, fmt.Sprintf("%d:%s", i, line))
+		return lipgloss.NewStyle()
+	}
+
+	list := m.visibleLines()
+	if want := []string{"1:- removed", "2:  context"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("expected LineStyleFunc to be called with %v, got %v", want, calls)
+	}
+	for i, l := range list {
+		if w := ansi.StringWidth(l); w != m.Width {
+			t.Errorf("line %d should be padded to %d, got %d", i, m.Width, w)
+		}
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	content := strings.Join([]string{"one", "two", "three", "four", "five", "six"}, "\n")
+
+	t.Run("top", func(t *testing.T) {
+		m := New(0, 0)
+		m.SetContent(content)
+		golden.RequireEqual(t, []byte(RenderString(m, 10, 3)))
+	})
+	t.Run("scrolled", func(t *testing.T) {
+		m := New(10, 3)
+		m.SetContent(content)
+		m.ScrollDown(2)
+		golden.RequireEqual(t, []byte(RenderString(m, 10, 3)))
+	})
+}
+
+func TestNewWithOptions(t *testing.T) {
+	m, err := NewWithOptions(
+		WithWidth(20),
+		WithHeight(2),
+		WithContent("one\ntwo\nthree"),
+		WithMouseWheelDelta(1),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Width != 20 || m.Height != 2 || m.TotalLineCount() != 3 || m.MouseWheelDelta != 1 {
+		t.Fatalf("options were not applied: %+v", m)
+	}
+
+	border := lipgloss.NewStyle().Border(lipgloss.NormalBorder()).Padding(1)
+	for name, opts := range map[string][]Option{
+		"negative size":        {WithWidth(-1)},
+		"negative wheel delta": {WithMouseWheelDelta(-3)},
+		"smaller than frame":   {WithStyle(border), WithWidth(20), WithHeight(3)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewWithOptions(opts...); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestRestorePosition(t *testing.T) {
+	var lines []string
+	for i := range 50 {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	content := strings.Join(lines, "\n")
+
+	m := New(10, 5)
+	m.SetContent(content)
+	m.SetYOffset(20)
+	token := m.PositionToken()
+
+	t.Run("unchanged content", func(t *testing.T) {
+		m := New(10, 5)
+		m.SetContent(content)
+		if err := m.RestorePosition(token); err != nil {
+			t.Fatal(err)
+		}
+		if m.YOffset != 20 {
+			t.Errorf("expected offset 20, got %d", m.YOffset)
+		}
+	})
+
+	t.Run("lines inserted above", func(t *testing.T) {
+		m := New(10, 5)
+		m.SetContent("new\nlines\nhere\n" + content)
+		if err := m.RestorePosition(token); err != nil {
+			t.Fatal(err)
+		}
+		if m.YOffset != 23 {
+			t.Errorf("expected offset 23, got %d", m.YOffset)
+		}
+	})
+
+	t.Run("anchor line removed", func(t *testing.T) {
+		m := New(10, 5)
+		m.SetContent(strings.Replace(content, "line 20\n", "", 1))
+		if err := m.RestorePosition(token); err != nil {
+			t.Fatal(err)
+		}
+		if m.YOffset != 20 {
+			t.Errorf("expected offset 20, got %d", m.YOffset)
+		}
+	})
+
+	t.Run("malformed token", func(t *testing.T) {
+		m := New(10, 5)
+		m.SetContent(content)
+		if err := m.RestorePosition("nope"); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestScrollToEndOfLine(t *testing.T) {
+	t.Parallel()
+
+	m := New(10, 2)
+	m.SetContent("short\na line which is much wider\nanother")
+
+	if left, right := m.Clipped(); left || !right {
+		t.Fatalf("expected content to be clipped on the right only, got %v %v", left, right)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	if m.xOffset != len("a line which is much wider")-10 {
+		t.Fatalf("expected the widest line to end at the right edge, got offset %d", m.xOffset)
+	}
+	if left, right := m.Clipped(); !left || right {
+		t.Fatalf("expected content to be clipped on the left only, got %v %v", left, right)
+	}
+
+	// The default key map scrolls horizontally.
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if m.xOffset != len("a line which is much wider")-10-defaultHorizontalStep {
+		t.Fatalf("expected left to scroll by the default step, got offset %d", m.xOffset)
+	}
+
+	m.ScrollToStartOfLine()
+	if m.xOffset != 0 {
+		t.Fatalf("expected to scroll back to the start, got offset %d", m.xOffset)
+	}
+}
+
+func TestScrollToEndOfLineInFrame(t *testing.T) {
+	t.Parallel()
+
+	m := New(12, 4)
+	m.Style = lipgloss.NewStyle().Border(lipgloss.NormalBorder())
+	m.SetContent("a line which is much wider")
+
+	m.ScrollToEndOfLine()
+	if want := len("a line which is much wider") - 10; m.xOffset != want {
+		t.Fatalf("expected offset %d inside the border, got %d", want, m.xOffset)
+	}
+	if left, right := m.Clipped(); !left || right {
+		t.Fatalf("expected content to be clipped on the left only, got %v %v", left, right)
+	}
+}
+
+func TestHorizontalKeysWithoutOverflow(t *testing.T) {
+	t.Parallel()
+
+	m := New(10, 2)
+	m.SetContent("short\nlines")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if m.xOffset != 0 {
+		t.Fatalf("expected no horizontal scrolling when nothing is clipped, got offset %d", m.xOffset)
+	}
+}
+
+func TestFolds(t *testing.T) {
+	var lines []string
+	for i := range 20 {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	m := New(20, 5)
+	m.SetContent(strings.Join(lines, "\n"))
+	m.SetFolds([]Fold{
+		{Start: 2, End: 9},
+		{Start: 4, End: 6},
+		{Start: 12, End: 18, Folded: true},
+	})
+
+	if got := m.TotalLineCount(); got != 14 {
+		t.Fatalf("expected the folded range to take one line, got %d lines", got)
+	}
+	if got := ansi.Strip(m.lines[12]); got != "▸ 7 lines folded" {
+		t.Fatalf("expected a summary line, got %q", got)
+	}
+	if m.ContentLine(13) != 19 {
+		t.Fatalf("expected lines after the fold to map to their content lines, got %d", m.ContentLine(13))
+	}
+
+	// Folding keeps the line at the top of the view in place.
+	m.SetYOffset(5)
+	m.SetFolded(5, true)
+	if got := ansi.Strip(m.visibleLines()[0]); got != "▸ 3 lines folded" || m.ContentLine(m.YOffset) != 4 {
+		t.Fatalf("expected the inner fold's summary at the top, got %q", got)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	if m.Folds()[1].Folded {
+		t.Fatal("expected z to unfold the fold at the top of the view")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Z")})
+	if got := m.TotalLineCount(); got != 20 {
+		t.Fatalf("expected Z to unfold everything, got %d lines", got)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Z")})
+	if got := m.TotalLineCount(); got != 7 {
+		t.Fatalf("expected Z to fold everything, got %d lines", got)
+	}
+}
+
+func TestHighlights(t *testing.T) {
+	upper := lipgloss.NewStyle().Transform(strings.ToUpper)
+	m := New(20, 5)
+	m.SetContent("hello world\nfoo bar\nbaz\n\x1b[31mred\x1b[0m text\nfolded")
+	m.SetHighlights([]Range{
+		{StartLine: 0, StartCol: 6, EndLine: 0, EndCol: 11, Style: upper},
+		{StartLine: 1, StartCol: 4, EndLine: 2, EndCol: 2, Style: upper},
+		{StartLine: 3, StartCol: 0, EndLine: 3, EndCol: 3, Style: upper},
+		// Overlaps the range before it, so only "te" is highlighted.
+		{StartLine: 3, StartCol: 2, EndLine: 3, EndCol: 6, Style: upper},
+		{StartLine: 4, StartCol: 0, EndLine: 4, EndCol: 99, Style: upper},
+	})
+
+	want := []string{"hello WORLD", "foo BAR", "BAz", "RED TExt", "FOLDED"}
+	for i, l := range m.visibleLines() {
+		if got := ansi.Strip(l); got != want[i] {
+			t.Errorf("line %d: expected %q, got %q", i, want[i], got)
+		}
+	}
+	if got := m.lines[3]; !strings.Contains(got, "\x1b[31m") {
+		t.Errorf("expected the content to be left as is, got %q", got)
+	}
+
+	// Fold summaries aren't highlighted.
+	m.SetFolds([]Fold{{Start: 4, End: 4, Folded: true}})
+	if got := ansi.Strip(m.visibleLines()[4]); got != "▸ 1 line folded" {
+		t.Errorf("expected the fold summary unhighlighted, got %q", got)
+	}
+
+	m.ClearHighlights()
+	if got := ansi.Strip(m.visibleLines()[0]); got != "hello world" {
+		t.Errorf("expected highlights to clear, got %q", got)
+	}
+}
+
+func TestSyncGroup(t *testing.T) {
+	t.Parallel()
+
+	lines := func(n int) string {
+		l := make([]string, n)
+		for i := range l {
+			l[i] = fmt.Sprintf("line %d of a long enough line", i)
+		}
+		return strings.Join(l, "\n")
+	}
+	left, right := New(10, 5), New(10, 5)
+	left.SetContent(lines(30))
+	right.SetContent(lines(40))
+
+	// The right pane has two lines for each of the left pane's first ten.
+	g := NewSyncGroup(LineMapper{}, LineMapper{
+		ToShared: func(o int) int {
+			if o < 20 {
+				return o / 2
+			}
+			return o - 10
+		},
+		FromShared: func(p int) int {
+			if p < 10 {
+				return p * 2
+			}
+			return p + 10
+		},
+	})
+
+	g.Update(0, tea.KeyMsg{Type: tea.KeyPgDown}, &left, &right)
+	if left.YOffset != 5 || right.YOffset != 10 {
+		t.Fatalf("expected offsets 5 and 10, got %d and %d", left.YOffset, right.YOffset)
+	}
+
+	g.Update(1, tea.KeyMsg{Type: tea.KeyPgDown}, &left, &right)
+	if right.YOffset != 15 || left.YOffset != 7 {
+		t.Fatalf("expected offsets 7 and 15, got %d and %d", left.YOffset, right.YOffset)
+	}
+
+	g.Update(0, tea.KeyMsg{Type: tea.KeyRight}, &left, &right)
+	if right.xOffset != left.xOffset || right.xOffset == 0 {
+		t.Fatalf("expected the horizontal offsets to match, got %d and %d", left.xOffset, right.xOffset)
+	}
+
+	left.GotoBottom()
+	g.Sync(0, &left, &right)
+	if right.YOffset != 35 {
+		t.Fatalf("expected the right pane at the bottom, got %d", right.YOffset)
+	}
+}
+
+func TestLargeContent(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+	for i := range 3000 {
+		fmt.Fprintf(&b, "line %d%s\r\n", i, strings.Repeat(".", i%50))
+	}
+	b.WriteString("last")
+	content := b.String()
+
+	small, large := New(20, 5), New(20, 5)
+	small.SetContent(content)
+	large.SetLargeContent(content)
+
+	if got, want := large.TotalLineCount(), small.TotalLineCount(); got != want {
+		t.Fatalf("expected %d lines, got %d", want, got)
+	}
+
+	check := func(what string) {
+		t.Helper()
+		if got, want := large.View(), small.View(); got != want {
+			t.Errorf("%s: expected view\n%s\ngot\n%s", what, want, got)
+		}
+	}
+	check("top")
+
+	small.GotoBottom()
+	large.GotoBottom()
+	if len(large.store.chunks) != 1 {
+		t.Errorf("expected the bottom to be shown without indexing, got %d chunks", len(large.store.chunks))
+	}
+	check("bottom")
+
+	for _, y := range []int{2500, 1024, 1023, 17} {
+		small.SetYOffset(y)
+		large.SetYOffset(y)
+		check(fmt.Sprintf("offset %d", y))
+	}
+
+	small.ScrollRight(10)
+	large.ScrollRight(10)
+	check("scrolled right")
+}
+
+func TestPositionIndicator(t *testing.T) {
+	t.Parallel()
+
+	lines := make([]string, 40)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	lines[12] = strings.Repeat("wide ", 10)
+	m := New(24, 5)
+	m.SetContent(strings.Join(lines, "\n"))
+	m.SetYOffset(10)
+	m.SetXOffset(2)
+
+	want := PositionInfo{TopLine: 11, BottomLine: 15, TotalLines: 40, Percent: 10.0 / 35, ClippedLeft: true, ClippedRight: true}
+	if got := m.PositionInfo(); got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+	if got := DefaultIndicator(m.PositionInfo()); got != "29% ┃ 15/40" {
+		t.Fatalf("expected the default indicator, got %q", got)
+	}
+
+	m.SetXOffset(0)
+	m.IndicatorCorner = BottomRight
+	golden.RequireEqual(t, []byte(RenderString(m, 24, 5)))
+
+	m.IndicatorCorner = TopLeft
+	m.IndicatorFunc = func(info PositionInfo) string {
+		return fmt.Sprintf("%d-%d", info.TopLine, info.BottomLine)
+	}
+	if got := strings.Split(ansi.Strip(m.View()), "\n")[0]; got != "11-1511                 " {
+		t.Fatalf("expected a custom indicator in the top left, got %q", got)
+	}
+}
+
+func TestLinks(t *testing.T) {
+	link := func(url, text string) string {
+		return ansi.SetHyperlink(url) + text + ansi.ResetHyperlink()
+	}
+	m := New(20, 2)
+	m.SetContent(strings.Join([]string{
+		"see " + link("https://a.example", "\x1b[1mdocs\x1b[0m") + " here",
+		"no links",
+		"日本 " + link("https://b.example", "b") + " \x1b]8;;https://c.example\x07c\x1b]8;;\x07",
+		"end",
+	}, "\n"))
+
+	want := []Link{
+		{Line: 0, StartCol: 4, EndCol: 8, URL: "https://a.example", Text: "docs"},
+		{Line: 2, StartCol: 5, EndCol: 6, URL: "https://b.example", Text: "b"},
+		{Line: 2, StartCol: 7, EndCol: 8, URL: "https://c.example", Text: "c"},
+	}
+	if got := m.Links(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected links %+v, got %+v", want, got)
+	}
+	if !m.KeyMap.NextLink.Enabled() {
+		t.Error("expected the link keys to be enabled")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if l, ok := m.FocusedLink(); !ok || l.URL != "https://a.example" {
+		t.Fatalf("expected the first link focused, got %+v", l)
+	}
+	if got := m.visibleLines()[0]; !strings.Contains(got, "\x1b]8;;https://a.example") {
+		t.Errorf("expected the link to be kept, got %q", got)
+	}
+
+	// Focusing a link below the view scrolls to it.
+	m.NextLink()
+	if l, _ := m.FocusedLink(); l.URL != "https://b.example" || m.YOffset != 1 {
+		t.Errorf("expected the second link focused in view, got %+v at offset %d", l, m.YOffset)
+	}
+
+	m.NextLink()
+	m.NextLink()
+	if l, _ := m.FocusedLink(); l.URL != "https://a.example" || m.YOffset != 0 {
+		t.Errorf("expected to wrap around to the first link, got %+v at offset %d", l, m.YOffset)
+	}
+	m.PrevLink()
+	if l, _ := m.FocusedLink(); l.URL != "https://c.example" {
+		t.Errorf("expected to wrap around to the last link, got %+v", l)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected a command opening the link")
+	}
+	if msg, ok := cmd().(OpenLinkMsg); !ok || msg.Link.URL != "https://c.example" {
+		t.Errorf("expected to open the focused link, got %#v", msg)
+	}
+
+	// Links in folded ranges are skipped.
+	m.ClearLinkFocus()
+	m.SetFolds([]Fold{{Start: 1, End: 2, Folded: true}})
+	m.GotoTop()
+	m.NextLink()
+	m.NextLink()
+	if l, _ := m.FocusedLink(); l.URL != "https://a.example" {
+		t.Errorf("expected folded links to be skipped, got %+v", l)
+	}
+
+	m.SetContent("plain")
+	if _, ok := m.FocusedLink(); ok || m.KeyMap.NextLink.Enabled() {
+		t.Error("expected new content without links to clear them")
+	}
+}
+
+func TestSoftWrap(t *testing.T) {
+	upper := lipgloss.NewStyle().Transform(strings.ToUpper)
+	m := New(10, 2)
+	m.SetSoftWrap(true)
+	m.SetContent("abcdefghijklmnop\nxyz")
+	m.SetHighlights([]Range{{StartLine: 0, StartCol: 8, EndLine: 0, EndCol: 12, Style: upper}})
+
+	want := []string{"abcdefghIJ", "KLmnop", "xyz"}
+	for i, l := range m.visibleLines() {
+		if got := ansi.Strip(l); got != want[i] {
+			t.Errorf("row %d: expected %q, got %q", i, want[i], got)
+		}
+	}
+	if m.TotalLineCount() != 3 || m.ContentLine(1) != 0 || m.ContentLine(2) != 1 {
+		t.Errorf("expected the rows to map to their content lines")
+	}
+
+	// The column at the top of the view stays in view when the width changes.
+	m.SetYOffset(1)
+	m.Width = 4
+	m, _ = m.Update(nil)
+	if got := ansi.Strip(m.visibleLines()[0]); m.YOffset != 2 || got != "IJKL" {
+		t.Errorf("expected the wrapped row with the anchor on top, got %q at offset %d", got, m.YOffset)
+	}
+
+	m.SetSoftWrap(false)
+	if m.TotalLineCount() != 2 || m.YOffset != 0 {
+		t.Errorf("expected the lines to unwrap, got %d lines at offset %d", m.TotalLineCount(), m.YOffset)
+	}
+}
+
+func TestRewrap(t *testing.T) {
+	lines := make([]string, 2*wrapChunkSize+1)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %05d", i)
+	}
+	m := New(20, 5)
+	m.SetSoftWrap(true)
+	m.SetContent(strings.Join(lines, "\n"))
+	m.SetYOffset(7000)
+
+	// Narrowing the view wraps the lines in the background.
+	m.Width = 5
+	m, cmd := m.Update(nil)
+	if cmd == nil {
+		t.Fatal("expected a command wrapping the lines")
+	}
+	if got := DefaultIndicator(m.PositionInfo()); got != "rewrapping… 0%" {
+		t.Errorf("expected the progress in the indicator, got %q", got)
+	}
+	if m.TotalLineCount() != len(lines) {
+		t.Errorf("expected the lines to stay as they were until they're wrapped")
+	}
+
+	var chunks int
+	for cmd != nil {
+		chunks++
+		m, cmd = m.Update(cmd())
+		if p, ok := m.Rewrapping(); ok && (p <= 0 || p >= 1) {
+			t.Errorf("expected partial progress, got %v", p)
+		}
+	}
+	if chunks != 3 {
+		t.Errorf("expected the lines to be wrapped in 3 chunks, got %d", chunks)
+	}
+	if _, ok := m.Rewrapping(); ok {
+		t.Error("expected wrapping to be done")
+	}
+	if m.TotalLineCount() != 2*len(lines) || m.YOffset != 14000 {
+		t.Errorf("expected the wrapped lines with line 7000 on top, got %d rows at offset %d", m.TotalLineCount(), m.YOffset)
+	}
+
+	// Messages from wrapping given up on are ignored.
+	m.Width = 10
+	m, stale := m.Update(nil)
+	m.SetContent("short")
+	if m, cmd = m.Update(stale()); cmd != nil || m.TotalLineCount() != 1 {
+		t.Errorf("expected the stale chunk to be ignored, got %d rows", m.TotalLineCount())
+	}
+}
+
+func TestCutLine(t *testing.T) {
+	lines := []string{
+		"plain ascii text",
+		"\x1b[31mred\x1b[0m and \x1b[1;32mbold green\x1b[0m",
+		"wide 日本語 text and emoji 👍🏽 here",
+		"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\ then\ttab",
+		"é combining \x1b[4munder日line\x1b[24m",
+		"",
+	}
+	for _, line := range lines {
+		c := indexLine(line)
+		width := ansi.StringWidth(line)
+		for left := 0; left <= width+1; left++ {
+			for right := left - 1; right <= width+2; right++ {
+				want := ansi.Cut(line, left, right)
+				if got := cutLine(line, c, left, right); got != want {
+					t.Fatalf("cutLine(%q, %d, %d) = %q, want %q", line, left, right, got, want)
+				}
+			}
+		}
+	}
+
+	// The view cuts lines the same way, with or without highlights.
+	m := New(10, 2)
+	m.SetContent(strings.Join(lines[1:3], "\n"))
+	m.SetXOffset(4)
+	for i, l := range m.visibleLines() {
+		if want := ansi.Cut(lines[i+1], 4, 14); l != want {
+			t.Fatalf("expected line %d to be cut to %q, got %q", i, want, l)
+		}
+	}
+	m.SetHighlights([]Range{{StartLine: 0, StartCol: 0, EndLine: 0, EndCol: 6, Style: lipgloss.NewStyle().Bold(true)}})
+	highlighted := m.highlightLines(0, m.lines)
+	if got, want := m.visibleLines()[0], ansi.Cut(highlighted[0], 4, 14); got != want {
+		t.Fatalf("expected the highlighted line to be cut to %q, got %q", want, got)
+	}
+}
+
+func TestAnchorBottom(t *testing.T) {
+	lines := func(from, to int) string {
+		var s []string
+		for i := from; i < to; i++ {
+			s = append(s, strconv.Itoa(i))
+		}
+		return strings.Join(s, "\n")
+	}
+	top := func(m Model) string {
+		return ansi.Strip(m.visibleLines()[0])
+	}
+
+	m := New(10, 3)
+	m.SetAnchorBottom(true)
+	m.SetContent(lines(0, 10))
+	if !m.AtBottom() || m.BottomOffset() != 0 {
+		t.Fatalf("expected the viewport to start at the bottom, got offset %d", m.YOffset)
+	}
+	m.SetContent(lines(0, 12))
+	if top(m) != "9" {
+		t.Fatalf("expected the viewport to follow appended lines, got %q on top", top(m))
+	}
+
+	m.SetBottomOffset(4)
+	if m.YOffset != 5 || m.ToBottomOffset(m.YOffset) != 4 || m.ToYOffset(4) != 5 {
+		t.Fatalf("expected offsets to convert, got %d from the top", m.YOffset)
+	}
+	m.SetContent(lines(0, 15))
+	if top(m) != "5" {
+		t.Fatalf("expected appended lines not to move the view while scrolled up, got %q on top", top(m))
+	}
+	m.SetContent(lines(-5, 15))
+	if top(m) != "5" || m.BottomOffset() != 7 {
+		t.Fatalf("expected lines inserted above to keep the view in place, got %q on top", top(m))
+	}
+}
@@ -0,0 +1,215 @@
+package viewport
+
+import (
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// wrapChunkSize is the number of lines wrapped by each command while
+// wrapping in the background, and the most lines wrapped right away.
+const wrapChunkSize = 5000
+
+// wrapJob is the wrapping of the lines to scroll through in the background,
+// started when the width changes. rows, rowIndex and rowStart hold the rows
+// wrapped so far, from the first done lines of base.
+type wrapJob struct {
+	width     int
+	base      []string
+	baseIndex []int
+
+	rows     []string
+	rowIndex []int
+	rowStart []int
+	cuts     []*lineCut
+	widest   int
+	done     int
+}
+
+// rewrapMsg carries the rows of a chunk of lines wrapped in the background,
+// up to line end of the job's base.
+type rewrapMsg struct {
+	job      *wrapJob
+	end      int
+	rows     []string
+	rowIndex []int
+	rowStart []int
+	cuts     []*lineCut
+	widest   int
+}
+
+// SetSoftWrap sets whether lines wider than the viewport wrap onto the rows
+// below it, rather than being cut off and scrolled horizontally. Lines are
+// wrapped at the last cell that fits, as less does, so that wrapped rows keep
+// the columns of highlights and links. Content set with SetLargeContent isn't
+// wrapped.
+//
+// Content of up to 5000 lines is wrapped right away. Longer content is
+// wrapped in the background by commands Update returns, a chunk of lines at
+// a time, so that resizing a large document doesn't stall the program. Until
+// the content is wrapped to the new width it's shown as before, and
+// PositionInfo reports the progress. After changing Width, pass a message to
+// Update, such as the tea.WindowSizeMsg itself, to wrap the content again.
+// The line at the top of the view stays there when it's wrapped.
+func (m *Model) SetSoftWrap(v bool) {
+	line, col := m.anchor()
+	m.softWrap = v
+	m.wrapJob = nil
+	m.layout()
+	m.SetYOffset(m.rowOf(line, col))
+}
+
+// SoftWrap returns whether lines wrap. See SetSoftWrap.
+func (m Model) SoftWrap() bool {
+	return m.softWrap
+}
+
+// Rewrapping returns how much of the content has been wrapped, from 0 to 1,
+// while it's being wrapped in the background. It returns false otherwise.
+func (m Model) Rewrapping() (float64, bool) {
+	if m.wrapJob == nil || len(m.wrapJob.base) == 0 {
+		return 0, false
+	}
+	return float64(m.wrapJob.done) / float64(len(m.wrapJob.base)), true
+}
+
+// setBase sets the lines to scroll through before wrapping, and the content
+// lines they show, stopping any wrapping in progress. It leaves the scroll
+// position alone.
+func (m *Model) setBase(lines []string, index []int) {
+	m.base, m.baseIndex = lines, index
+	m.wrapJob = nil
+	m.layout()
+}
+
+// layout lays out the rows to scroll through from the lines before wrapping,
+// wrapping them if they fit in a chunk, or leaving them to be wrapped in the
+// background otherwise.
+func (m *Model) layout() {
+	m.lines, m.lineIndex, m.rowStart, m.wrapWidth = m.base, m.baseIndex, nil, 0
+	if w := m.wrapTarget(); w > 0 && len(m.base) <= wrapChunkSize {
+		m.lines, m.lineIndex, m.rowStart = wrapLines(m.base, m.baseIndex, 0, len(m.base), w)
+		m.wrapWidth = w
+	}
+	m.cuts, m.longestLineWidth = indexLines(m.lines)
+}
+
+// wrapTarget returns the width to wrap lines to, or 0 if they don't wrap.
+func (m Model) wrapTarget() int {
+	if !m.softWrap || m.store != nil {
+		return 0
+	}
+	return max(0, m.Width-m.Style.GetHorizontalFrameSize())
+}
+
+// updateWrap wraps the lines again if the width has changed, starting to do
+// so in the background if there are too many to wrap right away, and carries
+// on with wrapping in the background.
+func (m *Model) updateWrap(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(rewrapMsg); ok {
+		return m.continueWrap(msg)
+	}
+
+	w := m.wrapTarget()
+	if w == 0 || w == m.wrapWidth || (m.wrapJob != nil && m.wrapJob.width == w) {
+		return nil
+	}
+	if len(m.base) <= wrapChunkSize {
+		line, col := m.anchor()
+		m.layout()
+		m.SetYOffset(m.rowOf(line, col))
+		return nil
+	}
+	m.wrapJob = &wrapJob{width: w, base: m.base, baseIndex: m.baseIndex}
+	return m.wrapJob.next()
+}
+
+// continueWrap adds the rows of a chunk wrapped in the background, returning
+// the command wrapping the next chunk, or once they're all wrapped, showing
+// them.
+func (m *Model) continueWrap(msg rewrapMsg) tea.Cmd {
+	job := m.wrapJob
+	if msg.job != job {
+		return nil
+	}
+	job.rows = append(job.rows, msg.rows...)
+	job.rowIndex = append(job.rowIndex, msg.rowIndex...)
+	job.rowStart = append(job.rowStart, msg.rowStart...)
+	job.cuts = append(job.cuts, msg.cuts...)
+	job.widest = max(job.widest, msg.widest)
+	job.done = msg.end
+	if job.done < len(job.base) {
+		return job.next()
+	}
+
+	line, col := m.anchor()
+	m.lines, m.lineIndex, m.rowStart, m.wrapWidth = job.rows, job.rowIndex, job.rowStart, job.width
+	m.cuts, m.longestLineWidth = job.cuts, job.widest
+	m.wrapJob = nil
+	m.SetYOffset(m.rowOf(line, col))
+	return nil
+}
+
+// next returns the command wrapping the next chunk of lines.
+func (j *wrapJob) next() tea.Cmd {
+	from := j.done
+	return func() tea.Msg {
+		end := min(from+wrapChunkSize, len(j.base))
+		rows, rowIndex, rowStart := wrapLines(j.base, j.baseIndex, from, end, j.width)
+		cuts, widest := indexLines(rows)
+		return rewrapMsg{
+			job: j, end: end,
+			rows: rows, rowIndex: rowIndex, rowStart: rowStart,
+			cuts: cuts, widest: widest,
+		}
+	}
+}
+
+// wrapLines wraps the lines from index from up to to at the given width,
+// returning the rows, the content line each shows and the column it starts
+// at. index maps the lines to content lines, or is nil if they're the same.
+func wrapLines(lines []string, index []int, from, to, width int) (rows []string, rowIndex, rowStart []int) {
+	for i := from; i < to; i++ {
+		line := i
+		if index != nil {
+			line = index[i]
+		}
+		col := 0
+		for _, row := range strings.Split(ansi.Hardwrap(lines[i], width, true), "\n") {
+			rows = append(rows, row)
+			rowIndex = append(rowIndex, line)
+			rowStart = append(rowStart, col)
+			col += ansi.StringWidth(row)
+		}
+	}
+	return rows, rowIndex, rowStart
+}
+
+// anchor returns the content line and column at the top of the view, which
+// stays there when the lines are laid out again.
+func (m Model) anchor() (line, col int) {
+	return m.ContentLine(m.YOffset), m.rowCol(m.YOffset)
+}
+
+// rowCol returns the column of its content line the row at the given index
+// starts at.
+func (m Model) rowCol(row int) int {
+	if row < 0 || row >= len(m.rowStart) {
+		return 0
+	}
+	return m.rowStart[row]
+}
+
+// rowOf returns the index of the row showing the given column of a content
+// line, or if it's hidden in a folded range, of the fold's summary.
+func (m Model) rowOf(line, col int) int {
+	if m.lineIndex == nil {
+		return line
+	}
+	after := sort.Search(len(m.lineIndex), func(r int) bool {
+		return m.lineIndex[r] > line || (m.lineIndex[r] == line && m.rowCol(r) > col)
+	})
+	return max(0, after-1)
+}
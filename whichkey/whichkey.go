@@ -0,0 +1,357 @@
+// Package whichkey provides a popup for Bubble Tea applications which, once a
+// prefix key such as "g" or "ctrl+x" is pressed, lists the keys that can
+// follow it along with what they do. The next keypress completes the
+// sequence, or the popup closes on its own after a timeout.
+package whichkey
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mikeflynn/bubbles/key"
+	"github.com/mikeflynn/bubbles/render"
+)
+
+// Internal ID management. Used to ensure that timeout messages are received
+// only by the popup that sent them.
+var lastID int64
+
+func nextID() int {
+	return int(atomic.AddInt64(&lastID, 1))
+}
+
+// Prefix is a key which starts a sequence, and the keys which can follow it.
+type Prefix struct {
+	// Name identifies the prefix in CompletedMsg and CanceledMsg.
+	Name string
+
+	// Binding is the prefix key. Its help key and description are used as
+	// the popup's title.
+	Binding key.Binding
+
+	// Continuations are the keys which complete the sequence. Their help
+	// keys and descriptions are listed in the popup, and disabled bindings
+	// are left out.
+	Continuations []key.NamedBinding
+}
+
+// CompletedMsg is sent when a continuation of a prefix is pressed.
+type CompletedMsg struct {
+	// Prefix is the name of the prefix.
+	Prefix string
+
+	// Name is the name of the continuation.
+	Name string
+}
+
+// CanceledMsg is sent when the popup closes without a continuation being
+// pressed.
+type CanceledMsg struct {
+	// Prefix is the name of the prefix.
+	Prefix string
+
+	// TimedOut is true if the popup closed because no key was pressed within
+	// the timeout, and false if it was canceled or another key was pressed.
+	TimedOut bool
+}
+
+type timeoutMsg struct {
+	id  int
+	tag int
+}
+
+// KeyMap is the key bindings of the open popup, besides the continuations of
+// the prefix. It satisfies the help.KeyMap interface.
+type KeyMap struct {
+	Cancel key.Binding
+}
+
+// ShortHelp implements the KeyMap interface.
+func (km KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{km.Cancel}
+}
+
+// FullHelp implements the KeyMap interface.
+func (km KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{km.Cancel}}
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Cancel: key.NewBinding(
+			key.WithKeys("esc", "ctrl+g"),
+			key.WithHelp("esc", "cancel"),
+		),
+	}
+}
+
+// Styles are the styles of the popup and the keys listed in it, as returned
+// by DefaultStyles by default.
+type Styles struct {
+	Popup       lipgloss.Style
+	Title       lipgloss.Style
+	Key         lipgloss.Style
+	Separator   lipgloss.Style
+	Description lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this
+// component.
+func DefaultStyles() Styles {
+	accent := lipgloss.AdaptiveColor{Light: "#AD58B4", Dark: "#F793FF"}
+	subdued := lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}
+
+	return Styles{
+		Popup:       lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(subdued).Padding(0, 1),
+		Title:       lipgloss.NewStyle().Bold(true).Foreground(accent),
+		Key:         lipgloss.NewStyle().Bold(true),
+		Separator:   lipgloss.NewStyle().Foreground(subdued),
+		Description: lipgloss.NewStyle().Foreground(subdued),
+	}
+}
+
+// Model is the state of a which-key popup.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+
+	// Timeout is how long the popup stays open without a key being pressed.
+	// If it's zero, the popup stays open until the next keypress.
+	Timeout time.Duration
+
+	// Separator is shown between each key and its description.
+	Separator string
+
+	// Width is the maximum width of the popup, including its frame. The
+	// continuations are laid out in as many columns as fit. If it's zero,
+	// they're listed in a single column.
+	Width int
+
+	// Translator, if set, localizes the help text of the prefixes and their
+	// continuations. Bindings with a translator of their own use that
+	// instead. See key.Binding.SetTranslator.
+	Translator key.Translator
+
+	prefixes []Prefix
+	active   int
+	id       int
+	tag      int
+}
+
+// New returns a model for the given prefixes.
+func New(prefixes ...Prefix) Model {
+	return Model{
+		KeyMap:    DefaultKeyMap(),
+		Styles:    DefaultStyles(),
+		Timeout:   3 * time.Second, //nolint:mnd
+		Separator: " → ",
+		prefixes:  prefixes,
+		active:    -1,
+		id:        nextID(),
+	}
+}
+
+// SetPrefixes replaces the prefixes, closing the popup.
+func (m *Model) SetPrefixes(prefixes ...Prefix) {
+	m.prefixes = prefixes
+	m.active = -1
+}
+
+// Prefixes returns the prefixes.
+func (m Model) Prefixes() []Prefix {
+	return m.prefixes
+}
+
+// Open opens the popup for the prefix with the given name, as if its key had
+// been pressed. It does nothing if there's no such prefix. Note that this
+// also returns a command.
+func (m *Model) Open(name string) tea.Cmd {
+	for i, p := range m.prefixes {
+		if p.Name == name {
+			return m.open(i)
+		}
+	}
+	return nil
+}
+
+func (m *Model) open(i int) tea.Cmd {
+	m.active = i
+	m.tag++
+	if m.Timeout <= 0 {
+		return nil
+	}
+	id, tag := m.id, m.tag
+	return tea.Tick(m.Timeout, func(time.Time) tea.Msg {
+		return timeoutMsg{id: id, tag: tag}
+	})
+}
+
+// Close closes the popup without sending a message.
+func (m *Model) Close() {
+	m.active = -1
+}
+
+// Active returns whether the popup is open.
+func (m Model) Active() bool {
+	return m.active >= 0
+}
+
+// Prefix returns the name of the prefix the popup is open for, or an empty
+// string if it's closed.
+func (m Model) Prefix() string {
+	if m.active < 0 {
+		return ""
+	}
+	return m.prefixes[m.active].Name
+}
+
+// Consumes reports whether Update would handle the given message itself,
+// which is the case for every keypress while the popup is open and for
+// prefix keys while it's closed. Applications should check this before
+// handling keys of their own:
+//
+//	if m.whichKey.Consumes(msg) {
+//	    m.whichKey, cmd = m.whichKey.Update(msg)
+//	    return m, cmd
+//	}
+func (m Model) Consumes(msg tea.Msg) bool {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return false
+	}
+	return m.Active() || m.prefixAt(keyMsg) >= 0
+}
+
+func (m Model) prefixAt(msg tea.KeyMsg) int {
+	for i, p := range m.prefixes {
+		if key.Matches(msg, p.Binding) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Init exists to satisfy the tea.Model interface.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case timeoutMsg:
+		if msg.id != m.id || msg.tag != m.tag || !m.Active() {
+			return m, nil
+		}
+		return m, m.close(CanceledMsg{Prefix: m.Prefix(), TimedOut: true})
+
+	case tea.KeyMsg:
+		if !m.Active() {
+			if i := m.prefixAt(msg); i >= 0 {
+				return m, m.open(i)
+			}
+			return m, nil
+		}
+
+		if key.Matches(msg, m.KeyMap.Cancel) {
+			return m, m.close(CanceledMsg{Prefix: m.Prefix()})
+		}
+		for _, c := range m.prefixes[m.active].Continuations {
+			if key.Matches(msg, c.Binding) {
+				return m, m.close(CompletedMsg{Prefix: m.Prefix(), Name: c.Name})
+			}
+		}
+		return m, m.close(CanceledMsg{Prefix: m.Prefix()})
+	}
+
+	return m, nil
+}
+
+// close closes the popup and returns a command sending msg.
+func (m *Model) close(msg tea.Msg) tea.Cmd {
+	m.active = -1
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// View renders the popup, or an empty string if it's closed.
+func (m Model) View() string {
+	if !m.Active() {
+		return ""
+	}
+	p := m.prefixes[m.active]
+
+	var keys, descs []string
+	var keyWidth int
+	for _, c := range p.Continuations {
+		if !c.Binding.Enabled() {
+			continue
+		}
+		h := c.Binding.TranslatedHelp(m.Translator)
+		keys = append(keys, h.Key)
+		descs = append(descs, h.Desc)
+		keyWidth = max(keyWidth, lipgloss.Width(h.Key))
+	}
+
+	entries := make([]string, len(keys))
+	var entryWidth int
+	for i := range keys {
+		entries[i] = m.Styles.Key.Width(keyWidth).Render(keys[i]) +
+			m.Styles.Separator.Render(m.Separator) +
+			m.Styles.Description.Render(descs[i])
+		entryWidth = max(entryWidth, lipgloss.Width(entries[i]))
+	}
+
+	h := p.Binding.TranslatedHelp(m.Translator)
+	title := m.Styles.Title.Render(h.Key)
+	if h.Desc != "" {
+		title += " " + m.Styles.Description.Render(h.Desc)
+	}
+	if len(entries) == 0 {
+		return m.Styles.Popup.Render(title)
+	}
+	return m.Styles.Popup.Render(title + "\n" + m.columns(entries, entryWidth))
+}
+
+// columnGap is the space between columns of continuations.
+const columnGap = 3
+
+// columns lays out the entries down as many columns as fit in Width.
+func (m Model) columns(entries []string, entryWidth int) string {
+	cols := 1
+	if m.Width > 0 {
+		inner := m.Width - m.Styles.Popup.GetHorizontalFrameSize()
+		cols = max(1, (inner+columnGap)/(entryWidth+columnGap))
+	}
+	rows := (len(entries) + cols - 1) / cols
+	cols = (len(entries) + rows - 1) / rows
+
+	lines := make([]string, rows)
+	for c := range cols {
+		for r := range rows {
+			i := c*rows + r
+			if i >= len(entries) {
+				break
+			}
+			if c > 0 {
+				lines[r] += strings.Repeat(" ", columnGap)
+			}
+			lines[r] += lipgloss.NewStyle().Width(entryWidth).Render(entries[i])
+		}
+	}
+	for r := range lines {
+		lines[r] = strings.TrimRight(lines[r], " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RenderString renders the popup deterministically at the given size, for
+// golden-file tests. See the render package for details.
+func RenderString(m Model, width, height int) string {
+	return render.View(m.View, width, height)
+}
@@ -0,0 +1,99 @@
+package whichkey
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/mikeflynn/bubbles/key"
+)
+
+func newWhichKey() Model {
+	binding := func(k, desc string) key.Binding {
+		return key.NewBinding(key.WithKeys(k), key.WithHelp(k, desc))
+	}
+	return New(Prefix{
+		Name:    "goto",
+		Binding: binding("g", "go to"),
+		Continuations: []key.NamedBinding{
+			{Name: "top", Binding: binding("g", "top")},
+			{Name: "bottom", Binding: binding("e", "bottom")},
+			{Name: "definition", Binding: binding("d", "definition")},
+			{Name: "references", Binding: binding("r", "references")},
+			{Name: "disabled", Binding: key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "hidden"), key.WithDisabled())},
+		},
+	})
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestSequence(t *testing.T) {
+	m := newWhichKey()
+	if m.Consumes(runes("e")) {
+		t.Fatal("expected a key other than a prefix not to be consumed while closed")
+	}
+	if !m.Consumes(runes("g")) {
+		t.Fatal("expected the prefix key to be consumed")
+	}
+
+	m, _ = m.Update(runes("g"))
+	if !m.Active() || m.Prefix() != "goto" {
+		t.Fatalf("expected the popup to open for goto, got %q", m.Prefix())
+	}
+	m, cmd := m.Update(runes("e"))
+	if m.Active() {
+		t.Fatal("expected the popup to close")
+	}
+	if msg := cmd(); msg != (CompletedMsg{Prefix: "goto", Name: "bottom"}) {
+		t.Fatalf("unexpected message %#v", msg)
+	}
+
+	m, _ = m.Update(runes("g"))
+	m, cmd = m.Update(runes("x"))
+	if msg := cmd(); m.Active() || msg != (CanceledMsg{Prefix: "goto"}) {
+		t.Fatalf("expected a disabled continuation to cancel, got %#v", msg)
+	}
+}
+
+func TestTranslator(t *testing.T) {
+	m := newWhichKey()
+	m.Translator = key.Translations{"go to": "gehe zu", "bottom": "ende"}
+	m, _ = m.Update(runes("g"))
+
+	v := ansi.Strip(m.View())
+	for _, want := range []string{"g gehe zu", "e → ende"} {
+		if !strings.Contains(v, want) {
+			t.Fatalf("expected the popup to contain %q, got %q", want, v)
+		}
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	m := newWhichKey()
+	m.Timeout = time.Millisecond
+
+	m, stale := m.Update(runes("g"))
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m, tick := m.Update(runes("g"))
+
+	// The timer from the first time the popup opened is ignored.
+	if m, cmd := m.Update(stale()); !m.Active() || cmd != nil {
+		t.Fatal("expected a stale timeout to be ignored")
+	}
+	m, cmd := m.Update(tick())
+	if msg := cmd(); m.Active() || msg != (CanceledMsg{Prefix: "goto", TimedOut: true}) {
+		t.Fatalf("expected the popup to time out, got %#v", msg)
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	m := newWhichKey()
+	m.Width = 40
+	m.Open("goto")
+	golden.RequireEqual(t, []byte(RenderString(m, 40, 5)))
+}